@@ -9,7 +9,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/handlers"
@@ -23,7 +22,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
 
-	_ "github.com/prefeitura-rio/app-rmi/docs"
+	"github.com/prefeitura-rio/app-rmi/docs"
 )
 
 // @title API RMI
@@ -72,6 +71,7 @@ func main() {
 	if err := config.LoadConfig(); err != nil {
 		logging.GetLogger().Fatal("failed to load config", zap.Error(err))
 	}
+	logging.SetPIIMasking(config.AppConfig.LogPIIMasking)
 
 	// Initialize observability
 	observability.InitTracer()
@@ -97,7 +97,9 @@ func main() {
 
 	// Initialize services
 	phoneMappingService := services.NewPhoneMappingService(observability.Logger())
-	configService := services.NewConfigService()
+	// Initialize config service (in-memory static lists refreshed in the background)
+	services.InitConfigService()
+	configService := services.ConfigServiceInstance
 	betaGroupService := services.NewBetaGroupService(observability.Logger())
 
 	// Initialize address service for maintenance request addresses
@@ -117,12 +119,32 @@ func main() {
 
 	services.InitCPFSecretariaService()
 
+	// Initialize audit history service for citizen history exports
+	services.InitAuditHistoryService()
+
 	// Initialize CF rate limiter for CF lookup requests
 	services.InitCFRateLimiter(config.AppConfig.CFLookupGlobalRateLimit, observability.Logger())
 
 	// Initialize CF lookup service for automatic Clínica da Família lookup
 	services.InitCFLookupService()
 
+	// Initialize feature flag service for per-cohort gating
+	services.InitFeatureFlagService(observability.Logger())
+
+	// Initialize scheduled per-collection data freshness reports
+	services.InitDataFreshnessService(observability.Logger())
+
+	// Initialize event bus for real-time citizen events
+	services.InitEventBus()
+
+	// Initialize email verification sender
+	services.InitEmailVerificationSender()
+
+	// Load the OpenAPI spec used to validate requests of the groups in OPENAPI_VALIDATION_GROUPS
+	if err := middleware.InitRequestValidator(docs.SwaggerInfo.ReadDoc()); err != nil {
+		logging.GetLogger().Fatal("failed to load OpenAPI spec for request validation", zap.Error(err))
+	}
+
 	// Initialize handlers
 	phoneHandlers := handlers.NewPhoneHandlers(observability.Logger(), phoneMappingService, configService)
 	betaGroupHandlers := handlers.NewBetaGroupHandlers(observability.Logger(), betaGroupService)
@@ -138,14 +160,20 @@ func main() {
 
 	// Create router with middleware
 	router := gin.New()
+	// Only trust forwarded client IPs set by our own proxies, so clients can't spoof them
+	if err := router.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		logging.GetLogger().Fatal("failed to set trusted proxies", zap.Error(err))
+	}
 	router.Use(
 		gin.Recovery(),
 		middleware.RequestID(),
+		middleware.CORS(), // Cross-origin policy from CORS_* settings
+		middleware.MaxBodySize(config.AppConfig.MaxRequestBodySize, bodySizeRouteLimits()), // 413 above MAX_REQUEST_BODY_SIZE (before anything reads the body)
 		middleware.RequestTiming(), // Add comprehensive timing middleware
 		middleware.RequestLogger(),
 		middleware.RequestTracker(),
 		middleware.AuditMiddleware(), // Automatic audit logging for all write operations
-		cors.Default(),
+		middleware.Compression(),     // gzip/Brotli responses above COMPRESSION_MIN_SIZE
 	)
 
 	// Metrics endpoint
@@ -156,13 +184,16 @@ func main() {
 	{
 		// Health check endpoint (no auth required)
 		v1.GET("/health", handlers.HealthCheck)
+		v1.GET("/health/live", handlers.LivenessCheck)   // Kubernetes liveness: the process is up
+		v1.GET("/health/ready", handlers.ReadinessCheck) // Kubernetes readiness: dependencies up and not draining
+		v1.GET("/health/deep", middleware.AuthMiddleware(), middleware.RequireAdmin(), handlers.DeepHealthCheck)
 
 		// Metrics endpoint (no auth required) - for Prometheus scraping
 		v1.GET("/metrics", handlers.MetricsHandler)
 
 		// Memory endpoints (require auth)
 		memory := v1.Group("/memory")
-		memory.Use(middleware.AuthMiddleware())
+		memory.Use(middleware.AuthMiddleware(), middleware.OpenAPIValidation("memory"))
 		{
 			memory.GET("/:phone_number", handlers.GetMemoryList)
 			memory.GET("/:phone_number/:memory_name", handlers.GetMemoryByName)
@@ -173,32 +204,46 @@ func main() {
 
 		// Citizen endpoints (require auth)
 		citizen := v1.Group("/citizen")
-		citizen.Use(middleware.AuthMiddleware())
+		citizen.Use(middleware.AuthMiddleware(), middleware.OpenAPIValidation("citizen"))
 		{
 			// Endpoints that require own CPF access
 			citizen.GET("/:cpf", middleware.RequireOwnCPF(), handlers.GetCitizenData)
 			citizen.GET("/:cpf/wallet", middleware.RequireOwnCPF(), handlers.GetCitizenWallet)
 			citizen.GET("/:cpf/maintenance-request", middleware.RequireOwnCPF(), handlers.GetMaintenanceRequests)
-			citizen.PUT("/:cpf/address", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredAddress)
-			citizen.PUT("/:cpf/phone", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredPhone)
-			citizen.PUT("/:cpf/email", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredEmail)
-			citizen.PUT("/:cpf/ethnicity", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredRaca)
-			citizen.PUT("/:cpf/exhibition-name", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredNomeExibicao)
-			citizen.PUT("/:cpf/gender", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredGenero)
-			citizen.PUT("/:cpf/family-income", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredRendaFamiliar)
-			citizen.PUT("/:cpf/education", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredEscolaridade)
-			citizen.PUT("/:cpf/disability", middleware.RequireOwnCPF(), handlers.UpdateSelfDeclaredDeficiencia)
+			citizen.GET("/:cpf/completeness", middleware.RequireOwnCPF(), handlers.GetCitizenCompleteness)
+			citizen.GET("/:cpf/profile", middleware.RequireOwnCPF(), handlers.GetCitizenProfile)
+			citizen.GET("/:cpf/notification-target", middleware.RequireOwnCPF(), handlers.GetNotificationTarget)
+			citizen.PUT("/:cpf/address", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredAddress)
+			citizen.POST("/:cpf/address/preview", middleware.RequireOwnCPF(), handlers.PreviewSelfDeclaredAddress)
+			citizen.PUT("/:cpf/phone", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredPhone)
+			citizen.PUT("/:cpf/email", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredEmail)
+			citizen.PUT("/:cpf/ethnicity", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredRaca)
+			citizen.PUT("/:cpf/exhibition-name", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredNomeExibicao)
+			citizen.PUT("/:cpf/gender", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredGenero)
+			citizen.PUT("/:cpf/family-income", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredRendaFamiliar)
+			citizen.PUT("/:cpf/education", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredEscolaridade)
+			citizen.PUT("/:cpf/disability", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.UpdateSelfDeclaredDeficiencia)
+			citizen.PATCH("/:cpf/self-declared", middleware.RequireOwnCPF(), middleware.Idempotency(), handlers.PatchSelfDeclared)
+			citizen.GET("/:cpf/events", middleware.RequireOwnCPF(), handlers.StreamCitizenEvents)
 			citizen.GET("/:cpf/firstlogin", middleware.RequireOwnCPF(), handlers.GetFirstLogin)
 			citizen.PUT("/:cpf/firstlogin", middleware.RequireOwnCPF(), handlers.UpdateFirstLogin)
 			citizen.GET("/:cpf/optin", middleware.RequireOwnCPF(), handlers.GetOptIn)
 			citizen.PUT("/:cpf/optin", middleware.RequireOwnCPF(), handlers.UpdateOptIn)
+			citizen.GET("/:cpf/preferred-channel", middleware.RequireOwnCPF(), handlers.GetPreferredChannel)
+			citizen.PUT("/:cpf/preferred-channel", middleware.RequireOwnCPF(), handlers.UpdatePreferredChannel)
 			citizen.POST("/:cpf/phone/validate", middleware.RequireOwnCPF(), handlers.ValidatePhoneVerification)
+			citizen.POST("/:cpf/phone/:phone_id/set-primary", middleware.RequireOwnCPF(), handlers.SetPrimaryPhone)
+			citizen.POST("/:cpf/email/validate", middleware.RequireOwnCPF(), handlers.ValidateEmailVerification)
 			citizen.GET("/:cpf/legal-entities", middleware.RequireOwnCPF(), handlers.GetLegalEntities)
+			citizen.GET("/:cpf/history/export", middleware.RequireOwnCPF(), handlers.ExportAuditHistory)
 			citizen.GET("/:cpf/pets", middleware.RequireOwnCPF(), handlers.GetPets)
 			citizen.POST("/:cpf/pets", middleware.RequireOwnCPF(), handlers.RegisterPet)
 			citizen.GET("/:cpf/pets/:pet_id", middleware.RequireOwnCPF(), handlers.GetPet)
 			citizen.GET("/:cpf/pets/stats", middleware.RequireOwnCPF(), handlers.GetPetStats)
 
+			// Admin lookup by the ID citizens have in another municipal system
+			citizen.GET("/by-external-id/:system/:id", middleware.RequireAdmin(), handlers.GetCitizenByExternalID)
+
 			// Avatar endpoints
 			citizen.GET("/:cpf/avatar", middleware.RequireOwnCPF(), handlers.GetUserAvatar)
 			citizen.PUT("/:cpf/avatar", middleware.RequireOwnCPF(), handlers.UpdateUserAvatar)
@@ -212,31 +257,40 @@ func main() {
 			public.GET("/family-income/options", handlers.GetFamilyIncomeOptions)
 			public.GET("/education/options", handlers.GetEducationOptions)
 			public.GET("/disability/options", handlers.GetDisabilityOptions)
+			public.GET("/merge-rules", handlers.GetMergeRules)
 		}
 
 		// Public avatar endpoints (no auth required)
 		avatars := v1.Group("/avatars")
 		{
-			avatars.GET("", handlers.ListAvatars) // Public avatar listing with pagination
+			avatars.GET("", handlers.ListAvatars)                // Public avatar listing with pagination
+			avatars.GET("/images/*key", handlers.GetAvatarImage) // Images kept in the avatar storage backend
 		}
 
 		// Admin-only avatar management endpoints
 		avatarAdmin := v1.Group("/avatars")
-		avatarAdmin.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		avatarAdmin.Use(middleware.AuthMiddleware(), middleware.RequireAdmin(), middleware.OpenAPIValidation("avatars"))
 		{
-			avatarAdmin.POST("", handlers.CreateAvatar)       // Create new avatar
-			avatarAdmin.DELETE("/:id", handlers.DeleteAvatar) // Delete avatar
+			avatarAdmin.POST("", handlers.CreateAvatar)                        // Create new avatar
+			avatarAdmin.DELETE("/:id", handlers.DeleteAvatar)                  // Delete avatar
+			avatarAdmin.POST("/migrate-storage", handlers.MigrateAvatarImages) // Copy externally hosted images into storage
 		}
 
 		// Public validation endpoints (no auth required)
 		validationGroup := v1.Group("/validate")
+		validationGroup.Use(middleware.OpenAPIValidation("validate"))
 		{
 			validationGroup.POST("/phone", handlers.ValidatePhoneNumber)
 			validationGroup.POST("/email", handlers.ValidateEmailAddress)
+			validationGroup.POST("/cpf", handlers.ValidateCPFNumber)
 		}
 
+		// Email verification magic link (public, opened from the citizen's mailbox)
+		v1.GET("/verify/email", handlers.VerifyEmailLink)
+
 		// Phone routes (public)
 		phoneGroup := v1.Group("/phone")
+		phoneGroup.Use(middleware.OpenAPIValidation("phone"))
 		{
 			phoneGroup.GET("/:phone_number/status", phoneHandlers.GetPhoneStatus)
 			phoneGroup.GET("/:phone_number/beta-status", betaGroupHandlers.GetBetaStatus)
@@ -244,7 +298,7 @@ func main() {
 
 		// Phone routes (protected)
 		protectedPhoneGroup := v1.Group("/phone")
-		protectedPhoneGroup.Use(middleware.AuthMiddleware())
+		protectedPhoneGroup.Use(middleware.AuthMiddleware(), middleware.OpenAPIValidation("phone"))
 		{
 			protectedPhoneGroup.GET("/:phone_number/citizen", phoneHandlers.GetCitizenByPhone)
 			protectedPhoneGroup.POST("/:phone_number/validate-registration", phoneHandlers.ValidateRegistration)
@@ -258,20 +312,27 @@ func main() {
 
 		// Admin routes
 		adminGroup := v1.Group("/admin")
-		adminGroup.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		adminGroup.Use(middleware.AuthMiddleware(), middleware.RequireAdmin(), middleware.OpenAPIValidation("admin"))
 		{
 			adminGroup.GET("/phone/quarantined", phoneHandlers.GetQuarantinedPhones)
 			adminGroup.GET("/phone/quarantine/stats", phoneHandlers.GetQuarantineStats)
+			adminGroup.POST("/phone/quarantine/bulk", phoneHandlers.BulkQuarantinePhones)
+			adminGroup.GET("/phone/:phone_number/reconcile", phoneHandlers.ReconcilePhoneMapping)
+			adminGroup.POST("/phone/:phone_number/reassign", phoneHandlers.ReassignPhone)
+			adminGroup.POST("/phone/:phone_number/delivery-receipt", phoneHandlers.RecordDeliveryReceipt)
+			adminGroup.GET("/optin/history", phoneHandlers.GetOptInHistory)
 
 			// Beta group management
 			adminGroup.GET("/beta/groups", betaGroupHandlers.ListGroups)
 			adminGroup.POST("/beta/groups", betaGroupHandlers.CreateGroup)
 			adminGroup.GET("/beta/groups/:group_id", betaGroupHandlers.GetGroup)
+			adminGroup.GET("/beta/groups/:group_id/stats", betaGroupHandlers.GetGroupStats)
 			adminGroup.PUT("/beta/groups/:group_id", betaGroupHandlers.UpdateGroup)
 			adminGroup.DELETE("/beta/groups/:group_id", betaGroupHandlers.DeleteGroup)
 
 			// Beta whitelist management
 			adminGroup.GET("/beta/whitelist", betaGroupHandlers.ListWhitelistedPhones)
+			adminGroup.GET("/beta/whitelist/export", betaGroupHandlers.ExportWhitelistedPhones)
 			adminGroup.POST("/beta/whitelist/:phone_number", betaGroupHandlers.AddToWhitelist)
 			adminGroup.DELETE("/beta/whitelist/:phone_number", betaGroupHandlers.RemoveFromWhitelist)
 			adminGroup.POST("/beta/whitelist/bulk-add", betaGroupHandlers.BulkAddToWhitelist)
@@ -281,13 +342,57 @@ func main() {
 			// Cache management
 			adminGroup.POST("/cache/read", handlers.ReadCacheKey)
 
+			// Sync queue management
+			adminGroup.POST("/sync/flush", handlers.FlushSyncQueues)
+
+			// Verification code incident response
+			adminGroup.POST("/verifications/expire", handlers.ExpireVerifications)
+
 			adminGroup.GET("/cpf-secretaria/:cpf", handlers.AdminListCPFSecretaria)
 			adminGroup.POST("/cpf-secretaria/:cpf", handlers.AdminAddCPFSecretaria)
 			adminGroup.DELETE("/cpf-secretaria/:cpf/:cd_ua", handlers.AdminRemoveCPFSecretaria)
+
+			// CF lookup bulk routes
+			adminGroup.POST("/cf/teams/batch", handlers.GetCFTeamsBatch)
+			adminGroup.POST("/cf-lookup/bulk", handlers.EnqueueCFLookupBulk)
+			adminGroup.GET("/cf-lookup/bulk/:batch_id", handlers.GetCFLookupBulkStatus)
+
+			// CF coverage stats routes
+			adminGroup.GET("/stats/cf-coverage", handlers.GetCFCoverageStats)
+
+			// Audit routes
+			adminGroup.GET("/audit/by-actor", handlers.GetAuditLogsByActor)
+
+			// Legal entity routes
+			adminGroup.GET("/legal-entities/:cnpj", handlers.AdminGetLegalEntity)
+
+			// Ethnicity options management
+			adminGroup.POST("/ethnicity/options", handlers.AdminAddEthnicityOption)
+			adminGroup.DELETE("/ethnicity/options/:value", handlers.AdminRemoveEthnicityOption)
+
+			// Feature flag management
+			adminGroup.GET("/feature-flags", handlers.AdminListFeatureFlags)
+			adminGroup.POST("/feature-flags", handlers.AdminCreateFeatureFlag)
+			adminGroup.GET("/feature-flags/:key", handlers.AdminGetFeatureFlag)
+			adminGroup.PUT("/feature-flags/:key", handlers.AdminUpdateFeatureFlag)
+			adminGroup.DELETE("/feature-flags/:key", handlers.AdminDeleteFeatureFlag)
+
+			// Data freshness
+			adminGroup.GET("/data-freshness", handlers.AdminGetDataFreshness)
+		}
+
+		// Service routes: machine-to-machine callers with service tokens, authorized by scope
+		serviceGroup := v1.Group("/service")
+		serviceGroup.Use(middleware.AuthMiddleware())
+		{
+			serviceGroup.POST("/cf/teams/batch", middleware.RequireScope(middleware.ScopeCitizenReadBatch), handlers.GetCFTeamsBatch)
+			serviceGroup.GET("/citizen/by-external-id/:system/:id", middleware.RequireScope(middleware.ScopeCitizenReadSearch), handlers.GetCitizenByExternalID)
+			serviceGroup.GET("/legal-entities/:cnpj", middleware.RequireScope(middleware.ScopeLegalEntityRead), handlers.AdminGetLegalEntity)
+			serviceGroup.GET("/phone/:phone_number/status", middleware.RequireScope(middleware.ScopePhoneStatusRead), phoneHandlers.GetPhoneStatusDetails)
 		}
 
 		cpfSecretariaGroup := v1.Group("/cpf-secretaria")
-		cpfSecretariaGroup.Use(middleware.AuthMiddleware())
+		cpfSecretariaGroup.Use(middleware.AuthMiddleware(), middleware.OpenAPIValidation("cpf-secretaria"))
 		{
 			cpfSecretariaGroup.GET("/:cpf", handlers.GetCPFSecretarias)
 		}
@@ -297,6 +402,7 @@ func main() {
 		{
 			configGroup.GET("/channels", phoneHandlers.GetAvailableChannels)
 			configGroup.GET("/opt-out-reasons", phoneHandlers.GetOptOutReasons)
+			configGroup.GET("/flags", handlers.GetFeatureFlags)
 		}
 
 		// Department routes (public)
@@ -314,7 +420,7 @@ func main() {
 
 		// Legal entity routes (protected)
 		legalEntity := v1.Group("/legal-entity")
-		legalEntity.Use(middleware.AuthMiddleware())
+		legalEntity.Use(middleware.AuthMiddleware(), middleware.OpenAPIValidation("legal-entity"))
 		{
 			legalEntity.GET("/:cnpj", handlers.GetLegalEntityByCNPJ)
 		}
@@ -327,7 +433,7 @@ func main() {
 
 		// Admin notification category routes (protected)
 		adminNotificationCategories := v1.Group("/admin/notification-categories")
-		adminNotificationCategories.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		adminNotificationCategories.Use(middleware.AuthMiddleware(), middleware.RequireAdmin(), middleware.OpenAPIValidation("admin"))
 		{
 			adminNotificationCategories.POST("", notificationCategoryHandlers.CreateCategory)
 			adminNotificationCategories.PUT("/:category_id", notificationCategoryHandlers.UpdateCategory)
@@ -336,7 +442,7 @@ func main() {
 
 		// Citizen notification preferences routes (protected)
 		citizenPreferences := v1.Group("/citizen/:cpf/notification-preferences")
-		citizenPreferences.Use(middleware.AuthMiddleware(), middleware.RequireOwnCPF())
+		citizenPreferences.Use(middleware.AuthMiddleware(), middleware.RequireOwnCPF(), middleware.OpenAPIValidation("notification-preferences"))
 		{
 			citizenPreferences.GET("", notificationPreferencesHandlers.GetCitizenPreferences)
 			citizenPreferences.PUT("", notificationPreferencesHandlers.UpdateCitizenPreferences)
@@ -345,7 +451,7 @@ func main() {
 
 		// Phone notification preferences routes (admin only)
 		phonePreferences := v1.Group("/phone/:phone_number/notification-preferences")
-		phonePreferences.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		phonePreferences.Use(middleware.AuthMiddleware(), middleware.RequireAdmin(), middleware.OpenAPIValidation("notification-preferences"))
 		{
 			phonePreferences.GET("", notificationPreferencesHandlers.GetPhonePreferences)
 			phonePreferences.PUT("", notificationPreferencesHandlers.UpdatePhonePreferences)
@@ -381,6 +487,12 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Fail readiness first and keep serving while load balancers notice, so traffic drains
+	// before the server stops accepting connections
+	handlers.SetShuttingDown()
+	logging.GetLogger().Info("draining before shutdown", zap.Duration("drain_delay", config.AppConfig.ShutdownDrainDelay))
+	time.Sleep(config.AppConfig.ShutdownDrainDelay)
+
 	// Create a deadline for server shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -400,5 +512,34 @@ func main() {
 		verificationQueue.Stop()
 	}
 
+	// Stop static list refreshes
+	services.ConfigServiceInstance.Stop()
+
+	// Stop scheduled data freshness reports
+	services.DataFreshnessServiceInstance.Stop()
+
 	logging.GetLogger().Info("server exiting")
 }
+
+// bodySizeRouteLimits lists the routes whose request body limit differs from MAX_REQUEST_BODY_SIZE
+func bodySizeRouteLimits() map[string]int64 {
+	limits := map[string]int64{
+		"/v1/avatars":             config.AppConfig.MaxAvatarBodySize,
+		"/v1/citizen/:cpf/avatar": config.AppConfig.MaxAvatarBodySize,
+	}
+	for _, path := range []string{
+		"/v1/citizen/:cpf/address",
+		"/v1/citizen/:cpf/phone",
+		"/v1/citizen/:cpf/email",
+		"/v1/citizen/:cpf/ethnicity",
+		"/v1/citizen/:cpf/exhibition-name",
+		"/v1/citizen/:cpf/gender",
+		"/v1/citizen/:cpf/family-income",
+		"/v1/citizen/:cpf/education",
+		"/v1/citizen/:cpf/disability",
+		"/v1/citizen/:cpf/self-declared",
+	} {
+		limits[path] = config.AppConfig.MaxSelfDeclaredBodySize
+	}
+	return limits
+}
@@ -21,6 +21,7 @@ func main() {
 	if err := logging.InitLogger(); err != nil {
 		log.Fatal("Failed to initialize logger:", err)
 	}
+	logging.SetPIIMasking(config.AppConfig.LogPIIMasking)
 
 	logging.GetLogger().Info("Starting RMI Sync Service")
 
@@ -42,6 +43,9 @@ func main() {
 	// Initialize CF lookup service for automatic Clínica da Família lookup
 	services.InitCFLookupService()
 
+	// Initialize event bus for real-time citizen events
+	services.InitEventBus()
+
 	// Create sync service
 	workerCount := config.AppConfig.DBWorkerCount
 	if workerCount == 0 {
@@ -65,7 +69,7 @@ func main() {
 	<-sigChan
 	logging.GetLogger().Info("Shutdown signal received")
 
-	// Stop sync service
+	// Stop sync service, draining pending sync queues for up to SYNC_FLUSH_TIMEOUT
 	syncService.Stop()
 
 	logging.GetLogger().Info("RMI Sync Service stopped")
@@ -24,6 +24,106 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/audit/by-actor": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as alterações (auditoria) feitas por um usuário ou operador, identificado pelo user_id do token, com paginação e filtros por recurso, ação e período. Valores sensíveis são ocultados (apenas administradores)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Listar auditoria por autor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário autor das alterações",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por recurso (ex: address, phone)",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ação (ex: UPDATE, DELETE)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do período, inclusivo (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do período, exclusivo (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 20, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registros de auditoria obtidos com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/services.AuditLogListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "user_id ausente ou período inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/beta/groups": {
             "get": {
                 "security": [
@@ -387,6 +487,76 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/beta/groups/{group_id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna as estatísticas de participação de um grupo beta (apenas administradores): total de telefones na whitelist, quantos estão vinculados a um CPF, quantos têm opt-in e a taxa de opt-in, além das inclusões e dos opt-ins/opt-outs registrados na janela BETA_GROUP_STATS_RECENT_WINDOW. As estatísticas ficam em cache por BETA_GROUP_STATS_CACHE_TTL e são invalidadas quando a whitelist do grupo é alterada.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beta Groups"
+                ],
+                "summary": "Obter estatísticas de grupo beta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do grupo beta",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estatísticas do grupo beta obtidas com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.BetaGroupStats"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do grupo é obrigatório ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo beta não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/beta/whitelist": {
             "get": {
                 "security": [
@@ -684,6 +854,80 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/beta/whitelist/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exporta todos os telefones na whitelist beta, opcionalmente de um único grupo, em CSV (whitelist_beta_AAAAMMDD.csv) ou JSON, sem paginação (apenas administradores). Cada item traz o telefone, o ID e o nome do grupo e a data de inclusão. O arquivo é transmitido à medida que os telefones são lidos. Os telefones são mascarados, exceto para tokens com um scope de BETA_WHITELIST_EXPORT_UNMASKED_SCOPES.",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "Beta Whitelist"
+                ],
+                "summary": "Exportar whitelist beta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ID do grupo",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Formato do arquivo (padrão: csv)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Arquivo com os telefones na whitelist",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do grupo ou formato inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Grupo não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/beta/whitelist/{phone_number}": {
             "post": {
                 "security": [
@@ -903,127 +1147,132 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/cpf-secretaria/{cpf}": {
-            "get": {
+        "/admin/cf-lookup/bulk": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todos os vínculos de secretaria associados a um CPF na base de apoio.",
+                "description": "Enfileira jobs cf_lookup para todos os cidadãos cujo endereço atual (autodeclarado, depois dados base) está no bairro e/ou município informado, ignorando maiúsculas e minúsculas. Usado quando uma nova Clínica da Família é inaugurada. Os jobs respeitam o rate limit global de CF (CF_LOOKUP_GLOBAL_RATE_LIMIT) e o progresso pode ser acompanhado pelo batch_id retornado. Com dry_run=true apenas conta os cidadãos (apenas administradores)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin",
-                    "cpf-secretaria"
+                    "admin"
                 ],
-                "summary": "Listar vínculos CPF-Secretaria",
+                "summary": "Reprocessar a consulta de Clínica da Família de um bairro ou município",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "CPF do usuário (11 dígitos)",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "description": "Filtro de região (bairro e/ou município)",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CFLookupBulkRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Jobs de CF enfileirados",
                         "schema": {
-                            "$ref": "#/definitions/models.CPFSecretariaListResponse"
+                            "$ref": "#/definitions/models.CFLookupBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Dados inválidos ou filtro de região ausente",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Consulta de CF desabilitada",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/cf-lookup/bulk/{batch_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Vincula um CPF a uma secretaria (cd_ua SICI) na base de apoio. Um CPF pode ter múltiplos vínculos.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna quantos jobs cf_lookup de um lote foram enfileirados, concluídos e falharam. O progresso fica disponível por 7 dias (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin",
-                    "cpf-secretaria"
+                    "admin"
                 ],
-                "summary": "Adicionar vínculo CPF-Secretaria",
+                "summary": "Progresso de um reprocessamento de Clínica da Família em lote",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "CPF do usuário (11 dígitos)",
-                        "name": "cpf",
+                        "description": "ID do lote retornado por POST /admin/cf-lookup/bulk",
+                        "name": "batch_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "cd_ua da secretaria",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AddCPFSecretariaRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.CPFSecretariaResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "Progresso do lote",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.CFLookupBatchStatus"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Lote não encontrado ou expirado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1031,68 +1280,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/cpf-secretaria/{cpf}/{cd_ua}": {
-            "delete": {
+        "/admin/cf/teams/batch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove o vínculo entre um CPF e uma secretaria específica.",
+                "description": "Retorna a Clínica da Família ativa e o resumo da equipe de saúde da família de uma lista de CPFs, a partir dos dados de CF já armazenados (não dispara novas consultas). CPFs sem dados de CF são retornados com has_cf_data=false (apenas administradores)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin",
-                    "cpf-secretaria"
+                    "admin"
                 ],
-                "summary": "Remover vínculo CPF-Secretaria",
+                "summary": "Consultar clínica da família e equipe de saúde em lote",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "CPF do usuário (11 dígitos)",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Código da UA (SICI)",
-                        "name": "cd_ua",
-                        "in": "path",
-                        "required": true
+                        "description": "Lista de CPFs (máximo 500)",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CFTeamsBatchRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Resumo de CF e equipe de saúde por CPF",
+                        "schema": {
+                            "$ref": "#/definitions/models.CFTeamsBatchResponse"
+                        }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Dados inválidos, lista vazia ou acima do limite",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1100,46 +1349,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/notification-categories": {
-            "post": {
+        "/admin/cpf-secretaria/{cpf}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new notification category (admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna todos os vínculos de secretaria associados a um CPF na base de apoio.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification-categories"
+                    "admin",
+                    "cpf-secretaria"
                 ],
-                "summary": "Create notification category",
+                "summary": "Listar vínculos CPF-Secretaria",
                 "parameters": [
                     {
-                        "description": "Category data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateNotificationCategoryRequest"
-                        }
+                        "type": "string",
+                        "description": "CPF do usuário (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Category created successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.NotificationCategory"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.CPFSecretariaListResponse"
                         }
                     },
                     "401": {
@@ -1148,29 +1387,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Category already exists",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/admin/notification-categories/{category_id}": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing notification category (admin only)",
+                "description": "Vincula um CPF a uma secretaria (cd_ua SICI) na base de apoio. Um CPF pode ter múltiplos vínculos.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1178,36 +1415,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-categories"
+                    "admin",
+                    "cpf-secretaria"
                 ],
-                "summary": "Update notification category",
+                "summary": "Adicionar vínculo CPF-Secretaria",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "CPF do usuário (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated category data",
+                        "description": "cd_ua da secretaria",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateNotificationCategoryRequest"
+                            "$ref": "#/definitions/models.AddCPFSecretariaRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Category updated successfully",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationCategory"
+                            "$ref": "#/definitions/models.CPFSecretariaResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1218,49 +1456,68 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Category not found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/cpf-secretaria/{cpf}/{cd_ua}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Soft-delete a notification category by setting active=false (admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove o vínculo entre um CPF e uma secretaria específica.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification-categories"
+                    "admin",
+                    "cpf-secretaria"
                 ],
-                "summary": "Delete notification category",
+                "summary": "Remover vínculo CPF-Secretaria",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "CPF do usuário (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Código da UA (SICI)",
+                        "name": "cd_ua",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Category deleted successfully"
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1268,14 +1525,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1283,26 +1546,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/phone/quarantine/stats": {
+        "/admin/data-freshness": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtém estatísticas sobre telefones em quarentena (apenas administradores)",
+                "description": "Retorna, por coleção, a distribuição da idade dos documentos (percentis 50, 90, 99 e máximo, em segundos) estimada a partir de uma amostra de DATA_FRESHNESS_SAMPLE_SIZE documentos, usando updated_at ou, na ausência dele, created_at. O relatório é recalculado a cada DATA_FRESHNESS_INTERVAL e também exportado na métrica collection_data_age_seconds. Use refresh=true para recalculá-lo na hora.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "admin"
+                ],
+                "summary": "Obter relatório de atualidade dos dados",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Recalcular o relatório em vez de usar o último gerado",
+                        "name": "refresh",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Obter estatísticas de quarentena",
                 "responses": {
                     "200": {
-                        "description": "Estatísticas de quarentena obtidas com sucesso",
+                        "description": "Relatório obtido com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.QuarantineStats"
+                            "$ref": "#/definitions/models.DataFreshnessReport"
                         }
                     },
                     "401": {
@@ -1312,13 +1583,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores podem obter estatísticas de quarentena",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1332,44 +1597,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/phone/quarantined": {
-            "get": {
+        "/admin/ethnicity/options": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lista todos os telefones em quarentena com paginação (apenas administradores)",
+                "description": "Adiciona uma opção de etnia ao final da lista retornada por GET /citizen/ethnicity/options e aceita nas atualizações de etnia. O valor é salvo sem espaços nas extremidades e em minúsculas. Todas as instâncias passam a usar a nova lista imediatamente.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "admin"
                 ],
-                "summary": "Listar telefones em quarentena",
+                "summary": "Adicionar opção de etnia",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10)",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Opção de etnia",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEthnicityOptionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Lista de telefones em quarentena obtida com sucesso",
+                    "201": {
+                        "description": "Opção de etnia adicionada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.QuarantinedListResponse"
+                            "$ref": "#/definitions/models.EthnicityOption"
                         }
                     },
                     "400": {
-                        "description": "Parâmetros de paginação inválidos",
+                        "description": "Corpo da requisição inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1381,13 +1646,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores podem listar telefones em quarentena",
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "409": {
+                        "description": "Opção de etnia já existe",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1401,48 +1666,97 @@ const docTemplate = `{
                 }
             }
         },
-        "/avatars": {
-            "get": {
-                "description": "Obtém lista paginada de avatares de foto de perfil disponíveis",
-                "consumes": [
-                    "application/json"
+        "/admin/ethnicity/options/{value}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Remove uma opção de etnia da lista retornada por GET /citizen/ethnicity/options; novas atualizações com esse valor passam a ser rejeitadas, mas cidadãos que já o declararam o mantêm. A última opção não pode ser removida. Todas as instâncias passam a usar a nova lista imediatamente.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "avatars"
+                    "admin"
                 ],
-                "summary": "Listar avatares disponíveis",
+                "summary": "Remover opção de etnia",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Valor da opção de etnia",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Opção de etnia removida com sucesso"
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - requer permissão de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Opção de etnia não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A última opção de etnia não pode ser removida",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/feature-flags": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 20, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Lista as feature flags cadastradas, ordenadas pela chave.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Listar feature flags",
                 "responses": {
                     "200": {
-                        "description": "Lista de avatares obtida com sucesso",
+                        "description": "Feature flags listadas com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.AvatarsListResponse"
+                            "$ref": "#/definitions/models.FeatureFlagListResponse"
                         }
                     },
-                    "400": {
-                        "description": "Parâmetros de paginação inválidos",
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "403": {
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1461,7 +1775,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cria um novo avatar de foto de perfil (somente administradores)",
+                "description": "Cadastra uma feature flag. A chave deve ter de 1 a 64 letras minúsculas, dígitos ou underscores. A flag fica ativa, quando habilitada, para os CPFs listados, para os cidadãos com telefone na whitelist de um dos grupos beta listados e para o percentual de rollout dos demais CPFs. A alteração vale imediatamente.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1469,29 +1783,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "avatars"
+                    "admin"
                 ],
-                "summary": "Criar novo avatar",
+                "summary": "Criar feature flag",
                 "parameters": [
                     {
-                        "description": "Dados do avatar",
+                        "description": "Definição da feature flag",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AvatarRequest"
+                            "$ref": "#/definitions/models.FeatureFlagRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Avatar criado com sucesso",
+                        "description": "Feature flag criada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.AvatarResponse"
+                            "$ref": "#/definitions/models.FeatureFlag"
                         }
                     },
                     "400": {
-                        "description": "Dados de avatar inválidos ou formato incorreto",
+                        "description": "Definição inválida",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1503,13 +1817,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores",
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "409": {
+                        "description": "Feature flag já existe",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1523,41 +1837,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/avatars/{id}": {
-            "delete": {
+        "/admin/feature-flags/{key}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Exclui um avatar de foto de perfil (exclusão suave, somente administradores)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Obtém a definição de uma feature flag cadastrada.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "avatars"
+                    "admin"
                 ],
-                "summary": "Excluir avatar",
+                "summary": "Obter feature flag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do avatar",
-                        "name": "id",
+                        "description": "Chave da feature flag",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Avatar excluído com sucesso"
-                    },
-                    "400": {
-                        "description": "ID do avatar é obrigatório ou inválido",
+                    "200": {
+                        "description": "Feature flag obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.FeatureFlag"
                         }
                     },
                     "401": {
@@ -1567,19 +1875,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores",
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Avatar não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Feature flag não encontrada",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1591,11 +1893,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/citizen/disability/options": {
-            "get": {
-                "description": "Retorna a lista de opções válidas de condições de deficiência para autodeclaração.",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Substitui a definição de uma feature flag (a chave do corpo é ignorada). Enviar enabled=false funciona como kill switch: a flag fica inativa para todos imediatamente, inclusive para avaliações já cacheadas.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1603,17 +1908,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
+                ],
+                "summary": "Atualizar feature flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave da feature flag",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Definição da feature flag",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureFlagRequest"
+                        }
+                    }
                 ],
-                "summary": "Listar opções de deficiência",
                 "responses": {
                     "200": {
-                        "description": "Lista de opções de deficiência válidas obtida com sucesso",
+                        "description": "Feature flag atualizada com sucesso",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.FeatureFlag"
+                        }
+                    },
+                    "400": {
+                        "description": "Definição inválida",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - requer permissão de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature flag não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1623,29 +1967,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/citizen/education/options": {
-            "get": {
-                "description": "Retorna a lista de opções válidas de níveis de escolaridade para autodeclaração.",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Remove uma feature flag. Flags conhecidas pelo serviço (como cf_integration) voltam ao valor padrão. A alteração vale imediatamente.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
+                ],
+                "summary": "Remover feature flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave da feature flag",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Listar opções de escolaridade",
                 "responses": {
-                    "200": {
-                        "description": "Lista de opções de escolaridade válidas obtida com sucesso",
+                    "204": {
+                        "description": "Feature flag removida com sucesso"
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - requer permissão de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature flag não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1657,9 +2022,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/ethnicity/options": {
+        "/admin/legal-entities/{cnpj}": {
             "get": {
-                "description": "Retorna a lista de opções válidas de etnia para autodeclaração. Esta lista é usada para validar as atualizações de etnia autodeclarada.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera o registro completo de uma entidade jurídica pelo CNPJ, incluindo todos os sócios, para atendimento. Os resultados são armazenados em cache (LEGAL_ENTITY_CACHE_TTL) e cada acesso é registrado na auditoria, já que o registro contém CPFs de outros cidadãos.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1667,81 +2037,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
+                ],
+                "summary": "Obter registro completo de uma entidade jurídica (admin)",
+                "parameters": [
+                    {
+                        "maxLength": 14,
+                        "minLength": 14,
+                        "type": "string",
+                        "description": "CNPJ da entidade (14 dígitos)",
+                        "name": "cnpj",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Listar opções de etnia",
                 "responses": {
                     "200": {
-                        "description": "Lista de opções de etnia válidas obtida com sucesso",
+                        "description": "Registro da entidade jurídica obtido com sucesso",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.LegalEntity"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "400": {
+                        "description": "Formato de CNPJ inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/citizen/family-income/options": {
-            "get": {
-                "description": "Retorna a lista de opções válidas de faixas de renda familiar mensal para autodeclaração.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "citizen"
-                ],
-                "summary": "Listar opções de renda familiar",
-                "responses": {
-                    "200": {
-                        "description": "Lista de opções de renda familiar válidas obtida com sucesso",
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "403": {
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/citizen/gender/options": {
-            "get": {
-                "description": "Retorna a lista de opções sugeridas de gênero para autodeclaração. Nota: Este campo aceita qualquer texto livre para a opção \"Outro\".",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "citizen"
-                ],
-                "summary": "Listar opções de gênero",
-                "responses": {
-                    "200": {
-                        "description": "Lista de opções de gênero sugeridas obtida com sucesso",
+                    },
+                    "404": {
+                        "description": "Entidade jurídica não encontrada",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -1753,14 +2091,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}": {
-            "get": {
+        "/admin/notification-categories": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera os dados do cidadão por CPF, incluindo informações básicas e dados autodeclarados.",
+                "description": "Create a new notification category (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1768,59 +2106,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "notification-categories"
                 ],
-                "summary": "Obter dados do cidadão",
+                "summary": "Create notification category",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
-                        "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "description": "Category data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateNotificationCategoryRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Dados do cidadão obtidos com sucesso",
+                    "201": {
+                        "description": "Category created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.Citizen"
+                            "$ref": "#/definitions/models.NotificationCategory"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Cidadão não encontrado",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "409": {
+                        "description": "Category already exists",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1828,14 +2154,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/address": {
+        "/admin/notification-categories/{category_id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o endereço autodeclarado de um cidadão por CPF. Apenas o campo de endereço é atualizado.",
+                "description": "Update an existing notification category (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1843,72 +2169,104 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "notification-categories"
                 ],
-                "summary": "Atualizar endereço autodeclarado",
+                "summary": "Update notification category",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
+                        "description": "Category ID",
+                        "name": "category_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Endereço autodeclarado",
+                        "description": "Updated category data",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredAddressInput"
+                            "$ref": "#/definitions/models.UpdateNotificationCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Endereço autodeclarado atualizado com sucesso",
+                        "description": "Category updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.NotificationCategory"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados de endereço incorretos",
+                        "description": "Invalid request body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflito - endereço não alterado (dados idênticos aos atuais)",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a notification category by setting active=false (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-categories"
+                ],
+                "summary": "Delete notification category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Category deleted successfully"
                     },
-                    "422": {
-                        "description": "Dados não processáveis - informações de endereço inválidas",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1916,43 +2274,79 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/avatar": {
+        "/admin/optin/history": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtém o avatar de foto de perfil atual de um usuário",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna o histórico cronológico de ações de opt-in (opt-in, opt-out, cadastro rejeitado, atualização de categoria e telefone rebaixado) de um CPF e/ou telefone, para auditorias de conformidade (apenas administradores). É obrigatório informar cpf ou phone. Com format=csv, exporta todas as ações filtradas em um arquivo CSV, ignorando a paginação. Cada consulta é registrada na auditoria.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "avatars",
-                    "citizen"
+                    "phone"
                 ],
-                "summary": "Obter avatar atual do usuário",
+                "summary": "Consultar histórico de opt-in",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "CPF do usuário",
+                        "description": "CPF do cidadão (obrigatório se phone não for informado)",
                         "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Número de telefone (obrigatório se cpf não for informado)",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ações a partir deste instante, inclusivo (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ações antes deste instante, exclusivo (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 20, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta (padrão: json)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Avatar do usuário obtido com sucesso",
+                        "description": "Histórico de opt-in obtido com sucesso (ou arquivo CSV com format=csv)",
                         "schema": {
-                            "$ref": "#/definitions/models.UserAvatarResponse"
+                            "$ref": "#/definitions/models.OptInHistoryListResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
+                        "description": "Parâmetros de filtro ou formato inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1964,13 +2358,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Usuário não encontrado",
+                        "description": "Acesso negado - somente administradores podem consultar o histórico de opt-in",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1988,14 +2376,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/phone/quarantine/bulk": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Define ou altera o avatar de foto de perfil de um usuário",
+                "description": "Coloca múltiplos números de telefone em quarentena com um motivo e uma duração compartilhados (apenas administradores). A duração padrão é PHONE_QUARANTINE_TTL. Retorna o resultado por número; a operação é registrada em um único evento de auditoria com a lista completa.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2003,37 +2393,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "avatars",
-                    "citizen"
+                    "phone"
                 ],
-                "summary": "Atualizar avatar do usuário",
+                "summary": "Colocar telefones em quarentena em lote",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "CPF do usuário",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Seleção de avatar",
+                        "description": "Telefones, motivo e duração da quarentena",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserAvatarRequest"
+                            "$ref": "#/definitions/models.BulkQuarantineRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Avatar do usuário atualizado com sucesso",
+                        "description": "Resultado da quarentena por telefone",
                         "schema": {
-                            "$ref": "#/definitions/models.UserAvatarResponse"
+                            "$ref": "#/definitions/models.BulkQuarantineResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou ID de avatar inválido",
+                        "description": "Dados inválidos, lista de telefones vazia ou motivo ausente",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2045,25 +2427,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Usuário ou avatar não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - avatar inexistente ou inativo",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2077,53 +2447,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/disability": {
-            "put": {
+        "/admin/phone/quarantine/stats": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria a condição de deficiência autodeclarada de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/disability/options.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Obtém estatísticas sobre telefones em quarentena, incluindo quarentenas ativas por motivo (active_by_reason) (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
-                ],
-                "summary": "Atualizar deficiência autodeclarada",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Deficiência autodeclarada",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredDeficienciaInput"
-                        }
-                    }
+                    "phone"
                 ],
+                "summary": "Obter estatísticas de quarentena",
                 "responses": {
                     "200": {
-                        "description": "Deficiência atualizada com sucesso",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Formato de CPF inválido ou valor de deficiência inválido",
+                        "description": "Estatísticas de quarentena obtidas com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.QuarantineStats"
                         }
                     },
                     "401": {
@@ -2133,13 +2476,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - somente administradores podem obter estatísticas de quarentena",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Dados não processáveis - valor de deficiência não é válido",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2153,51 +2496,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/education": {
-            "put": {
+        "/admin/phone/quarantined": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o nível de escolaridade autodeclarado de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/education/options.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lista os telefones em quarentena com paginação e filtros por motivo e período de início da quarentena (apenas administradores). Com format=csv, exporta todos os telefones filtrados em um arquivo CSV, ignorando a paginação; os CPFs são mascarados, exceto para tokens com um scope de QUARANTINE_EXPORT_UNMASKED_SCOPES.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "citizen"
+                    "phone"
                 ],
-                "summary": "Atualizar escolaridade autodeclarada",
+                "summary": "Listar telefones em quarentena",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "description": "Filtrar pelo motivo da quarentena",
+                        "name": "reason",
+                        "in": "query"
                     },
                     {
-                        "description": "Escolaridade autodeclarada",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredEscolaridadeInput"
-                        }
+                        "type": "string",
+                        "description": "Quarentenas iniciadas a partir deste instante, inclusivo (RFC3339)",
+                        "name": "quarantined_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quarentenas iniciadas antes deste instante, exclusivo (RFC3339)",
+                        "name": "quarantined_before",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta (padrão: json)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Escolaridade atualizada com sucesso",
+                        "description": "Lista de telefones em quarentena obtida com sucesso (ou arquivo CSV com format=csv)",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.QuarantinedListResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou valor de escolaridade inválido",
+                        "description": "Parâmetros de filtro ou formato inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2209,13 +2574,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - somente administradores podem listar telefones em quarentena",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Dados não processáveis - valor de escolaridade não é válido",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2229,14 +2594,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/email": {
-            "put": {
+        "/admin/phone/{phone_number}/delivery-receipt": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o email autodeclarado de um cidadão por CPF. Apenas o campo de email é atualizado.",
+                "description": "Registra o recibo de entrega de uma mensagem enviada ao telefone, informado pelo provedor. Uma entrega bem-sucedida zera o contador de falhas; após PHONE_DELIVERY_FAILURE_THRESHOLD falhas dentro de PHONE_DELIVERY_FAILURE_WINDOW, o telefone autodeclarado verificado do CPF vinculado volta a pendente de verificação e o cidadão precisa confirmá-lo novamente com um código. O rebaixamento é registrado na auditoria e no histórico de opt-in (apenas administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2244,36 +2609,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "phone"
                 ],
-                "summary": "Atualizar email autodeclarado",
+                "summary": "Registrar recibo de entrega de mensagem",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
+                        "description": "Número de telefone",
+                        "name": "phone_number",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Email autodeclarado",
+                        "description": "Recibo de entrega",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredEmailInput"
+                            "$ref": "#/definitions/models.PhoneDeliveryReceiptRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email autodeclarado atualizado com sucesso",
+                        "description": "Recibo registrado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.PhoneDeliveryReceiptResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados de email incorretos",
+                        "description": "Formato de número de telefone ou dados de entrada inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2285,25 +2650,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Cidadão não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflito - email não alterado (email corresponde aos dados atuais)",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - formato de email inválido",
+                        "description": "Acesso negado - somente administradores podem registrar recibos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2323,51 +2670,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/ethnicity": {
-            "put": {
+        "/admin/phone/{phone_number}/reconcile": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria a etnia autodeclarada de um cidadão por CPF. Apenas o campo de etnia é atualizado. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/ethnicity/options.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Compara o mapeamento do telefone no MongoDB com o estado em cache no Redis (cache de leitura e status beta) e retorna as divergências. Com repair=true, atualiza o cache de leitura a partir do MongoDB e remove o status beta divergente. O buffer de escrita é apenas informado (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "phone"
                 ],
-                "summary": "Atualizar etnia autodeclarada",
+                "summary": "Reconciliar cache do mapeamento de telefone",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
+                        "description": "Número de telefone",
+                        "name": "phone_number",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Etnia autodeclarada",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredRacaInput"
-                        }
+                        "type": "boolean",
+                        "description": "Corrigir as divergências encontradas (padrão: false)",
+                        "name": "repair",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Etnia atualizada com sucesso",
+                        "description": "Reconciliação executada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.PhoneReconcileResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou valor de etnia inválido",
+                        "description": "Formato de número de telefone inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2379,19 +2720,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Cidadão não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - valor de etnia não é válido",
+                        "description": "Acesso negado - somente administradores podem reconciliar mapeamentos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2411,51 +2740,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/exhibition-name": {
-            "put": {
+        "/admin/stats/cf-coverage": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o nome de exibição autodeclarado de um cidadão por CPF. Apenas o campo de nome de exibição é atualizado. O nome de exibição é o nome que aparece na interface do usuário, permitindo ao cidadão controlar como seu nome é exibido no aplicativo.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Agrega os lookups de CF armazenados pela região do endereço do cidadão (endereço autodeclarado, depois dados base), informando quantos cidadãos têm CF ativa e quantos não tiveram CF encontrada em cada região. O resultado fica em cache por CF_COVERAGE_STATS_CACHE_TTL (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
                 ],
-                "summary": "Atualizar nome de exibição autodeclarado",
+                "summary": "Estatísticas de cobertura de Clínica da Família por região",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Nome de exibição autodeclarado",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredNomeExibicaoInput"
-                        }
+                        "default": "bairro",
+                        "description": "Agrupamento por região (bairro ou municipio)",
+                        "name": "by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Nome de exibição atualizado com sucesso",
+                        "description": "Cobertura de CF por região",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.CFCoverageStats"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou valor de nome de exibição inválido",
+                        "description": "Agrupamento inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2467,19 +2784,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Cidadão não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - nome de exibição muito longo ou vazio",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2499,69 +2804,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/family-income": {
-            "put": {
+        "/admin/sync/flush": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria a renda familiar mensal autodeclarada de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/family-income/options.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Processa de forma síncrona todos os jobs pendentes nas filas sync:queue:* (buffers de escrita do Redis para o MongoDB) e retorna as contagens por fila. Com dry_run=true apenas informa os jobs pendentes, sem processá-los. O processamento é limitado por SYNC_FLUSH_TIMEOUT; complete=false indica que restaram jobs ou que houve falhas.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
                 ],
-                "summary": "Atualizar renda familiar autodeclarada",
+                "summary": "Esvaziar filas de sincronização",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Renda familiar autodeclarada",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredRendaFamiliarInput"
-                        }
+                        "type": "boolean",
+                        "description": "Apenas informar os jobs pendentes (padrão: false)",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Renda familiar atualizada com sucesso",
+                        "description": "Resultado do esvaziamento das filas",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/services.SyncFlushResult"
                         }
                     },
-                    "400": {
-                        "description": "Formato de CPF inválido ou valor de renda familiar inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - valor de renda familiar não é válido",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2575,14 +2855,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/firstlogin": {
-            "get": {
+        "/admin/verifications/expire": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Verifica se este é o primeiro login do usuário",
+                "description": "Invalida de uma vez os códigos de verificação de telefone e email ainda válidos, definindo expires_at para o momento atual (ex: suspeita de vazamento de códigos). cpf e phone_number filtram os códigos afetados; com phone_number, apenas verificações de telefone são expiradas. Envie {} para expirar todos os códigos. A ação é registrada na auditoria.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2590,27 +2870,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "admin"
                 ],
-                "summary": "Obter status do primeiro login",
+                "summary": "Expirar códigos de verificação pendentes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "description": "Filtros opcionais por CPF e telefone",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerificationExpireRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status do primeiro login obtido com sucesso",
+                        "description": "Quantidade de códigos expirados",
                         "schema": {
-                            "$ref": "#/definitions/models.UserConfigResponse"
+                            "$ref": "#/definitions/models.VerificationExpireResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
+                        "description": "Corpo inválido, CPF ou telefone inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2622,13 +2904,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Acesso negado - somente administradores",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2640,14 +2916,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Define o status do primeiro login como falso para um usuário",
+            }
+        },
+        "/avatars": {
+            "get": {
+                "description": "Obtém lista paginada de avatares de foto de perfil disponíveis, ordenada por ` + "`" + `sort` + "`" + ` e ` + "`" + `order` + "`" + ` com desempate estável pelo ID, para que as páginas sejam consistentes entre requisições",
                 "consumes": [
                     "application/json"
                 ],
@@ -2655,39 +2928,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "avatars"
                 ],
-                "summary": "Atualizar status do primeiro login",
+                "summary": "Listar avatares disponíveis",
                 "parameters": [
                     {
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 20, máximo: AVATAR_LIST_MAX_PER_PAGE, 100 por padrão)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "name"
+                        ],
                         "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
+                        "description": "Campo de ordenação: created_at (padrão) ou name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Direção da ordenação: asc ou desc (padrão: desc para created_at, asc para name)",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status do primeiro login atualizado com sucesso",
+                        "description": "Lista de avatares obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.UserConfigResponse"
+                            "$ref": "#/definitions/models.AvatarsListResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Parâmetros de paginação ou ordenação inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2705,16 +2991,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/citizen/{cpf}/gender": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o gênero autodeclarado de um cidadão por CPF. Aceita qualquer valor de texto livre, mas as opções sugeridas estão disponíveis no endpoint /citizen/gender/options.",
+                "description": "Cria um novo avatar de foto de perfil (somente administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2722,36 +3006,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "avatars"
                 ],
-                "summary": "Atualizar gênero autodeclarado",
+                "summary": "Criar novo avatar",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Número do CPF",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Gênero autodeclarado",
+                        "description": "Dados do avatar",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredGeneroInput"
+                            "$ref": "#/definitions/models.AvatarRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Gênero atualizado com sucesso",
+                    "201": {
+                        "description": "Avatar criado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.AvatarResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou valor de gênero vazio",
+                        "description": "Dados de avatar inválidos ou formato incorreto",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2763,7 +3040,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2777,14 +3060,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/legal-entities": {
-            "get": {
+        "/avatars/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera a lista paginada de entidades jurídicas (pessoas jurídicas) associadas ao CPF do cidadão. A busca é feita através do campo 'cpf_socio' no array 'socios' de cada entidade.",
+                "description": "Exclui um avatar de foto de perfil (exclusão suave, somente administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2792,50 +3075,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "citizen"
+                    "avatars"
                 ],
-                "summary": "Obter entidades jurídicas associadas ao CPF",
+                "summary": "Excluir avatar",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
-                        "name": "cpf",
+                        "description": "ID do avatar",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filtro opcional por ID da natureza jurídica",
-                        "name": "natureza_juridica_id",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Lista paginada de entidades jurídicas obtida com sucesso",
-                        "schema": {
-                            "$ref": "#/definitions/models.PaginatedLegalEntities"
-                        }
+                    "204": {
+                        "description": "Avatar excluído com sucesso"
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou parâmetros de paginação inválidos",
+                        "description": "ID do avatar é obrigatório ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2847,7 +3104,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Avatar não encontrado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2867,59 +3130,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/maintenance-request": {
+        "/citizen/by-external-id/{system}/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera os chamados do 1746 de um cidadão por CPF com paginação. Cada documento representa um chamado individual.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Recupera os dados do cidadão pelo ID que ele possui em outro sistema municipal (campo external_ids), para integrações que não usam o CPF. Apenas o sistema configurado em CITIZEN_EXTERNAL_ID_SYSTEM pode ser consultado. A resposta é a mesma de GET /citizen/{cpf}. O mapeamento ID externo → CPF é cacheado por EXTERNAL_ID_CACHE_TTL. Retorna 409 quando o ID pertence a mais de um cidadão.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Obter chamados do 1746 do cidadão",
+                "summary": "Obter dados do cidadão por ID externo",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
-                        "name": "cpf",
+                        "description": "Sistema de origem do ID (ex: id_municipal)",
+                        "name": "system",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID do cidadão no sistema de origem",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista paginada de chamados do 1746 obtida com sucesso",
+                        "description": "Dados do cidadão obtidos com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedMaintenanceRequests"
+                            "$ref": "#/definitions/models.CitizenResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou parâmetros de paginação inválidos",
+                        "description": "Sistema de ID externo não suportado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2931,13 +3181,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
+                        "description": "Acesso negado - requer permissão de administrador",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "404": {
+                        "description": "Nenhum cidadão com o ID externo",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ID externo pertence a mais de um cidadão",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2951,14 +3207,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/notification-preferences": {
+        "/citizen/disability/options": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get notification preferences for a citizen including global and category opt-ins",
+                "description": "Retorna a lista de opções válidas de condições de deficiência para autodeclaração.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2966,58 +3217,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
-                ],
-                "summary": "Get citizen notification preferences",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "CPF number",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    }
+                    "citizen"
                 ],
+                "summary": "Listar opções de deficiência",
                 "responses": {
                     "200": {
-                        "description": "Notification preferences",
+                        "description": "Lista de opções de deficiência válidas obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid CPF format",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "403": {
-                        "description": "Access denied",
+                    }
+                }
+            }
+        },
+        "/citizen/education/options": {
+            "get": {
+                "description": "Retorna a lista de opções válidas de níveis de escolaridade para autodeclaração.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Listar opções de escolaridade",
+                "responses": {
+                    "200": {
+                        "description": "Lista de opções de escolaridade válidas obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update notification preferences for a citizen (global and/or category opt-ins)",
+            }
+        },
+        "/citizen/ethnicity/options": {
+            "get": {
+                "description": "Retorna a lista de opções válidas de etnia para autodeclaração. Esta lista é usada para validar as atualizações de etnia autodeclarada. A lista é editável por administradores (POST e DELETE /admin/ethnicity/options) e mantida em memória; o header Last-Modified informa a última atualização.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3025,60 +3281,91 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
-                ],
-                "summary": "Update citizen notification preferences",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "CPF number",
-                        "name": "cpf",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated preferences",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateNotificationPreferencesRequest"
-                        }
-                    }
+                    "citizen"
                 ],
+                "summary": "Listar opções de etnia",
                 "responses": {
                     "200": {
-                        "description": "Preferences updated successfully",
+                        "description": "Lista de opções de etnia válidas obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Horário da última atualização da lista em memória"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid CPF format or request body",
+                    "500": {
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    }
+                }
+            }
+        },
+        "/citizen/family-income/options": {
+            "get": {
+                "description": "Retorna a lista de opções válidas de faixas de renda familiar mensal para autodeclaração.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Listar opções de renda familiar",
+                "responses": {
+                    "200": {
+                        "description": "Lista de opções de renda familiar válidas obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "500": {
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "422": {
-                        "description": "Invalid category ID",
+                    }
+                }
+            }
+        },
+        "/citizen/gender/options": {
+            "get": {
+                "description": "Retorna a lista de opções sugeridas de gênero para autodeclaração. Nota: Este campo aceita qualquer texto livre para a opção \"Outro\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Listar opções de gênero",
+                "responses": {
+                    "200": {
+                        "description": "Lista de opções de gênero sugeridas obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3086,14 +3373,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/notification-preferences/categories/{category_id}": {
-            "patch": {
+        "/citizen/merge-rules": {
+            "get": {
+                "description": "Retorna, por campo, as regras de precedência em vigor ao mesclar os dados autodeclarados sobre os dados base do cidadão: quando o valor autodeclarado substitui o valor base (condition), se o indicador autodeclarado precisa ser verdadeiro (requires_indicator, ex: telefone só após verificação) e como o indicador base é ajustado (indicator).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Listar regras de mesclagem dos dados autodeclarados",
+                "responses": {
+                    "200": {
+                        "description": "Regras de mesclagem obtidas com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeRulesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/citizen/{cpf}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update opt-in preference for a single notification category",
+                "description": "Recupera os dados do cidadão por CPF, incluindo informações básicas e dados autodeclarados. Com include_derived=true, inclui o objeto _derived com valores calculados pelo servidor (endereço formatado, idade e contatos mascarados). O objeto self_declared_status informa, para cada campo autodeclarado presente, a data da última atualização (last_updated) e se o dado está desatualizado (is_stale) segundo SELF_DECLARED_OUTDATED_THRESHOLD; campos sem data são considerados desatualizados. O header ETag contém a versão atual dos dados autodeclarados, a ser enviada no header If-Match das atualizações. Tokens com um scope listado em MASKED_RESPONSE_SCOPES recebem CPF, telefones e emails mascarados, exceto ao acessar o próprio CPF.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3101,67 +3411,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
+                    "citizen"
                 ],
-                "summary": "Update single category preference",
+                "summary": "Obter dados do cidadão",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF number",
+                        "description": "CPF do cidadão (11 dígitos)",
                         "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Incluir campos derivados calculados pelo servidor (padrão: false)",
+                        "name": "include_derived",
+                        "in": "query"
                     },
                     {
-                        "description": "Category preference",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateCategoryPreferenceRequest"
-                        }
+                        "type": "string",
+                        "description": "Campos de primeiro nível a retornar, separados por vírgula (ex: nome,telefone,endereco); padrão: todos",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Preference updated successfully",
+                        "description": "Dados do cidadão obtidos com sucesso (apenas os campos solicitados quando fields é informado)",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
+                            "$ref": "#/definitions/models.CitizenResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão atual dos dados autodeclarados (seguida de um hash dos campos quando fields é informado)"
+                            },
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED)"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid CPF format or request body",
+                        "description": "Formato de CPF inválido ou campo desconhecido em fields",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Invalid category ID",
+                    "404": {
+                        "description": "Cidadão não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3169,14 +3493,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/optin": {
-            "get": {
+        "/citizen/{cpf}/address": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Verifica se o usuário optou por receber notificações",
+                "description": "Atualiza ou cria o endereço autodeclarado de um cidadão por CPF. Apenas o campo de endereço é atualizado. O endereço é normalizado antes da comparação com os dados atuais: CEP com 8 dígitos sem pontuação, estado como UF maiúscula (aceita também o nome do estado) e bairro e logradouro com iniciais maiúsculas. CEP ou UF inválidos retornam 422 quando as regras address_cep e address_uf estão em modo enforce.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3186,7 +3510,7 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Obter status de opt-in",
+                "summary": "Atualizar endereço autodeclarado",
                 "parameters": [
                     {
                         "type": "string",
@@ -3194,19 +3518,46 @@ const docTemplate = `{
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Endereço autodeclarado",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredAddressInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status do opt-in obtido com sucesso",
+                        "description": "Endereço autodeclarado atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.UserConfigOptInResponse"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
+                        "description": "Formato de CPF inválido ou dados de endereço incorretos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -3221,27 +3572,47 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "409": {
+                        "description": "Conflito - endereço não alterado (dados idênticos aos atuais)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - informações de endereço inválidas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/citizen/{cpf}/address/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "description": "Atualiza o status de opt-in do usuário para notificações",
+                "description": "Consulta qual Clínica da Família (e equipe de saúde) atenderia o endereço informado, sem salvar o endereço nem o resultado, sem invalidar caches e sem registrar auditoria. Permite ao cidadão confirmar o endereço antes de enviá-lo em PUT /citizen/{cpf}/address. Quando nenhuma clínica atende o endereço, retorna found=false. A consulta conta no limite global de consultas de CF.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3251,7 +3622,7 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Atualizar status de opt-in",
+                "summary": "Pré-visualizar Clínica da Família de um endereço",
                 "parameters": [
                     {
                         "type": "string",
@@ -3261,26 +3632,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Status de opt-in",
+                        "description": "Endereço a consultar",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserConfigOptInResponse"
+                            "$ref": "#/definitions/models.SelfDeclaredAddressInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status do opt-in atualizado com sucesso",
+                        "description": "Clínica da Família correspondente ao endereço (ou found=false)",
                         "schema": {
-                            "$ref": "#/definitions/models.UserConfigOptInResponse"
+                            "$ref": "#/definitions/models.CFAddressPreview"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados de opt-in incorretos",
+                        "description": "Formato de CPF inválido ou dados de endereço incorretos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -3295,20 +3666,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Dados não processáveis - valor de opt-in inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Limite de consultas de CF excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "503": {
+                        "description": "Consulta de CF indisponível",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3316,14 +3681,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/pets": {
+        "/citizen/{cpf}/avatar": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera a lista paginada de pets associados ao CPF do cidadão.",
+                "description": "Obtém o avatar de foto de perfil atual de um usuário",
                 "consumes": [
                     "application/json"
                 ],
@@ -3331,44 +3696,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
+                    "avatars",
                     "citizen"
                 ],
-                "summary": "Obter pets associados ao CPF",
+                "summary": "Obter avatar atual do usuário",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
+                        "description": "CPF do usuário",
                         "name": "cpf",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista paginada de pets obtida com sucesso",
+                        "description": "Avatar do usuário obtido com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedPets"
+                            "$ref": "#/definitions/models.UserAvatarResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou parâmetros de paginação inválidos",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3385,6 +3734,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -3399,13 +3754,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Registra um novo pet para o CPF do cidadão (auto-declarado).",
+                "description": "Define ou altera o avatar de foto de perfil de um usuário",
                 "consumes": [
                     "application/json"
                 ],
@@ -3413,38 +3768,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
+                    "avatars",
                     "citizen"
                 ],
-                "summary": "Registrar novo pet",
+                "summary": "Atualizar avatar do usuário",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
+                        "description": "CPF do usuário",
                         "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados do pet para registro",
-                        "name": "pet",
+                        "description": "Seleção de avatar",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PetRegistrationRequest"
+                            "$ref": "#/definitions/models.UserAvatarRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Pet registrado com sucesso",
+                    "200": {
+                        "description": "Avatar do usuário atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.Pet"
+                            "$ref": "#/definitions/models.UserAvatarResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados do pet inválidos",
+                        "description": "Formato de CPF inválido ou ID de avatar inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3461,6 +3815,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Usuário ou avatar não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - avatar inexistente ou inativo",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -3476,24 +3842,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/pets/stats": {
+        "/citizen/{cpf}/completeness": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera as estatísticas de quantidade de pets por tipo associadas ao CPF do cidadão.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Calcula uma pontuação de 0 a 100 indicando o quanto o perfil do cidadão está completo, considerando telefone (e sua verificação), email, endereço, etnia, avatar e opt-in. Retorna, para cada campo, se ele está preenchido e o peso usado no cálculo. Os pesos são configurados em COMPLETENESS_WEIGHTS.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Obter estatísticas de pets do CPF",
+                "summary": "Obter completude do perfil do cidadão",
                 "parameters": [
                     {
                         "maxLength": 11,
@@ -3507,9 +3870,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Estatísticas obtidas com sucesso",
+                        "description": "Completude do perfil calculada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.PetStatsResponse"
+                            "$ref": "#/definitions/models.CitizenCompletenessResponse"
                         }
                     },
                     "400": {
@@ -3530,12 +3893,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Erro interno do servidor",
                         "schema": {
@@ -3545,14 +3902,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/pets/{pet_id}": {
-            "get": {
+        "/citizen/{cpf}/disability": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera um pet específico associado ao CPF do cidadão pelo ID do pet.",
+                "description": "Atualiza ou cria a condição de deficiência autodeclarada de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/disability/options.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3562,36 +3919,54 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Obter pet específico por ID",
+                "summary": "Atualizar deficiência autodeclarada",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
+                        "description": "Número do CPF",
                         "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID do pet",
-                        "name": "pet_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Deficiência autodeclarada",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredDeficienciaInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pet obtido com sucesso",
+                        "description": "Deficiência atualizada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.Pet"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou ID do pet inválido",
+                        "description": "Formato de CPF inválido ou valor de deficiência inválido",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -3606,14 +3981,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Pet não encontrado",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "422": {
+                        "description": "Dados não processáveis - valor de deficiência não é válido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3627,14 +4002,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/phone": {
+        "/citizen/{cpf}/education": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza ou cria o telefone autodeclarado de um cidadão por CPF. Apenas o campo de telefone é atualizado (armazenado como pendente até verificado).",
+                "description": "Atualiza ou cria o nível de escolaridade autodeclarado de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/education/options.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3644,7 +4019,7 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Atualizar telefone autodeclarado",
+                "summary": "Atualizar escolaridade autodeclarada",
                 "parameters": [
                     {
                         "type": "string",
@@ -3654,26 +4029,44 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Telefone autodeclarado",
+                        "description": "Escolaridade autodeclarada",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SelfDeclaredPhoneInput"
+                            "$ref": "#/definitions/models.SelfDeclaredEscolaridadeInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Telefone autodeclarado submetido para validação com sucesso",
+                        "description": "Escolaridade atualizada com sucesso",
                         "schema": {
                             "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados de telefone incorretos",
+                        "description": "Formato de CPF inválido ou valor de escolaridade inválido",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -3688,26 +4081,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Cidadão não encontrado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflito - telefone não alterado (telefone corresponde aos dados atuais verificados)",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "422": {
-                        "description": "Dados não processáveis - formato de telefone inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Dados não processáveis - valor de escolaridade não é válido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3721,14 +4102,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/phone/validate": {
-            "post": {
+        "/citizen/{cpf}/email": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Valida o código de verificação enviado para o número de telefone",
+                "description": "Atualiza ou cria o email autodeclarado de um cidadão por CPF. Apenas o campo de email é atualizado. Com EMAIL_VERIFICATION_ENABLED, o email fica pendente e um token de verificação é enviado; o email só passa a valer após validação em POST /citizen/{cpf}/email/validate.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3738,7 +4119,7 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Validar verificação de telefone",
+                "summary": "Atualizar email autodeclarado",
                 "parameters": [
                     {
                         "type": "string",
@@ -3748,26 +4129,44 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Código de verificação e telefone",
+                        "description": "Email autodeclarado",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PhoneVerificationValidateRequest"
+                            "$ref": "#/definitions/models.SelfDeclaredEmailInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Código de verificação validado com sucesso",
+                        "description": "Email autodeclarado atualizado com sucesso",
                         "schema": {
                             "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido ou dados de verificação incorretos",
+                        "description": "Formato de CPF inválido ou dados de email incorretos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -3783,13 +4182,25 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Código de verificação não encontrado ou expirado",
+                        "description": "Cidadão não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflito - email não alterado (email corresponde aos dados atuais)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "422": {
-                        "description": "Dados não processáveis - código inválido",
+                        "description": "Dados não processáveis - formato de email inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3809,14 +4220,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/citizen/{cpf}/wallet": {
-            "get": {
+        "/citizen/{cpf}/email/validate": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera os dados da carteira do cidadão por CPF, incluindo informações de saúde e outros dados da carteira.",
+                "description": "Valida o token de verificação enviado para o email autodeclarado. Após a validação, o email pendente passa a ser o email autodeclarado verificado do cidadão. Disponível apenas com EMAIL_VERIFICATION_ENABLED.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3826,27 +4237,34 @@ const docTemplate = `{
                 "tags": [
                     "citizen"
                 ],
-                "summary": "Obter dados da carteira do cidadão",
+                "summary": "Validar verificação de email",
                 "parameters": [
                     {
-                        "maxLength": 11,
-                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do cidadão (11 dígitos)",
+                        "description": "Número do CPF",
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Token de verificação e email",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailVerificationValidateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Dados da carteira do cidadão obtidos com sucesso",
+                        "description": "Email verificado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.CitizenWallet"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de CPF inválido",
+                        "description": "Formato de CPF inválido ou dados de verificação incorretos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3864,7 +4282,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Cidadão não encontrado",
+                        "description": "Token de verificação não encontrado ou expirado, ou verificação de email desabilitada",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3884,9 +4302,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/cnaes": {
-            "get": {
-                "description": "Recupera a lista paginada de CNAEs (Classificação Nacional de Atividades Econômicas) com filtros opcionais",
+        "/citizen/{cpf}/ethnicity": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza ou cria a etnia autodeclarada de um cidadão por CPF. Apenas o campo de etnia é atualizado. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/ethnicity/options.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3894,175 +4317,168 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cnaes"
+                    "citizen"
                 ],
-                "summary": "Listar CNAEs",
+                "summary": "Atualizar etnia autodeclarada",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Texto para busca na denominação",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filtro por seção (ex: A)",
-                        "name": "secao",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filtro por divisão (ex: 1)",
-                        "name": "divisao",
-                        "in": "query"
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Filtro por grupo (ex: 1.1)",
-                        "name": "grupo",
-                        "in": "query"
+                        "description": "Etnia autodeclarada",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredRacaInput"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Filtro por classe (ex: 01.12-1)",
-                        "name": "classe",
-                        "in": "query"
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "Filtro por subclasse",
-                        "name": "subclasse",
-                        "in": "query"
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista paginada de CNAEs obtida com sucesso",
+                        "description": "Etnia atualizada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.CNAEListResponse"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Parâmetros inválidos",
+                        "description": "Formato de CPF inválido ou valor de etnia inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/config/channels": {
-            "get": {
-                "description": "Obtém a lista de canais disponíveis para comunicação",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "config"
-                ],
-                "summary": "Obter canais disponíveis",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Cidadão não encontrado",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/config/opt-out-reasons": {
-            "get": {
-                "description": "Obtém a lista de motivos válidos para opt-out",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "config"
-                ],
-                "summary": "Obter motivos de opt-out",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - valor de etnia não é válido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cpf-secretaria/{cpf}": {
+        "/citizen/{cpf}/events": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna a lista de cd_uas vinculados ao CPF na base de apoio. Usado por outras APIs (app-go-api) via service account.",
+                "description": "Abre um stream Server-Sent Events com atualizações em tempo real do cidadão: telefone verificado (phone_verified), dados de CF disponíveis (cf_data_ready), dados autodeclarados sincronizados (self_declared_synced) e solicitações de manutenção alteradas (maintenance_changed). Envia um heartbeat a cada 15 segundos. Ao reconectar com o header Last-Event-ID, os eventos recentes perdidos (últimos 10 minutos) são reenviados. Streams sem eventos por EVENT_STREAM_IDLE_TIMEOUT são encerrados.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "cpf-secretaria"
+                    "citizen"
                 ],
-                "summary": "Consultar secretarias de um CPF",
+                "summary": "Stream de eventos do cidadão",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "CPF do usuário (11 dígitos)",
+                        "description": "CPF do cidadão (11 dígitos)",
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do último evento recebido, para retomar o stream",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Stream de eventos (cada evento SSE carrega um CitizenEvent em JSON)",
                         "schema": {
-                            "$ref": "#/definitions/models.CPFSecretariaQueryResponse"
+                            "$ref": "#/definitions/models.CitizenEvent"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Barramento de eventos indisponível ou limite de streams simultâneos (por instância ou por CPF) atingido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4070,9 +4486,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/departments": {
-            "get": {
-                "description": "Recupera a lista paginada de departamentos com filtros opcionais (hierarquia, nível, busca).",
+        "/citizen/{cpf}/exhibition-name": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza ou cria o nome de exibição autodeclarado de um cidadão por CPF. Apenas o campo de nome de exibição é atualizado. O nome de exibição é o nome que aparece na interface do usuário, permitindo ao cidadão controlar como seu nome é exibido no aplicativo. O nome deve ter entre EXHIBITION_NAME_MIN_LENGTH e 255 caracteres, conter apenas letras, espaços, apóstrofos, hífens e pontos, e não pode conter termos da lista EXHIBITION_NAME_BLOCKLIST.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4080,156 +4501,96 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "departments"
+                    "citizen"
                 ],
-                "summary": "Listar departamentos/unidades administrativas",
+                "summary": "Atualizar nome de exibição autodeclarado",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Código da UA pai para filtrar departamentos filhos",
-                        "name": "parent_id",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Nível mínimo do departamento",
-                        "name": "min_level",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Nível máximo do departamento",
-                        "name": "max_level",
-                        "in": "query"
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Nível exato do departamento (sobrescreve min/max)",
-                        "name": "exact_level",
-                        "in": "query"
+                        "description": "Nome de exibição autodeclarado",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredNomeExibicaoInput"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Sigla da UA para filtro exato",
-                        "name": "sigla_ua",
-                        "in": "query"
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "Texto para busca textual em nome_ua",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Número da página (padrão: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "Itens por página (padrão: 10, máximo: 100)",
-                        "name": "per_page",
-                        "in": "query"
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista paginada de departamentos obtida com sucesso",
+                        "description": "Nome de exibição atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.DepartmentListResponse"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Parâmetros inválidos",
+                        "description": "Formato de CPF inválido ou nome de exibição vazio",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/departments/{cd_ua}": {
-            "get": {
-                "description": "Recupera um departamento específico pelo código da UA (cd_ua).",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "departments"
-                ],
-                "summary": "Obter departamento/unidade administrativa por ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Código da Unidade Administrativa",
-                        "name": "cd_ua",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Departamento obtido com sucesso",
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
-                            "$ref": "#/definitions/models.DepartmentResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Código da UA inválido",
+                    "404": {
+                        "description": "Cidadão não encontrado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Departamento não encontrado",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "422": {
+                        "description": "Nome de exibição viola uma regra (code EXHIBITION_NAME_TOO_SHORT, EXHIBITION_NAME_TOO_LONG, EXHIBITION_NAME_INVALID_CHARACTERS ou EXHIBITION_NAME_BLOCKED; details.rule indica a regra)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Verifica a saúde da API e suas dependências (MongoDB e Redis). Retorna status detalhado para cada serviço.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Verificação de saúde",
-                "responses": {
-                    "200": {
-                        "description": "Todos os serviços estão saudáveis",
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
-                            "$ref": "#/definitions/handlers.HealthResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Um ou mais serviços não estão saudáveis",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4237,14 +4598,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/legal-entity/{cnpj}": {
-            "get": {
+        "/citizen/{cpf}/family-income": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera informações detalhadas de uma entidade jurídica (pessoa jurídica) pelo CNPJ. O acesso é permitido para administradores ou se o CPF do JWT estiver como responsável ou sócio da entidade.",
+                "description": "Atualiza ou cria a renda familiar mensal autodeclarada de um cidadão por CPF. O valor deve ser uma das opções válidas retornadas pelo endpoint /citizen/family-income/options.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4252,31 +4613,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "legal-entity"
+                    "citizen"
                 ],
-                "summary": "Obter informações de uma entidade jurídica por CNPJ",
+                "summary": "Atualizar renda familiar autodeclarada",
                 "parameters": [
                     {
-                        "maxLength": 14,
-                        "minLength": 14,
                         "type": "string",
-                        "description": "CNPJ da entidade (14 dígitos)",
-                        "name": "cnpj",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Renda familiar autodeclarada",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredRendaFamiliarInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Informações da entidade jurídica obtidas com sucesso",
+                        "description": "Renda familiar atualizada com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.LegalEntity"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Formato de CNPJ inválido",
+                        "description": "Formato de CPF inválido ou valor de renda familiar inválido",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -4286,19 +4672,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - sem permissão para visualizar esta entidade",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Entidade jurídica não encontrada",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "422": {
+                        "description": "Dados não processáveis - valor de renda familiar não é válido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4312,14 +4698,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/memory/{phone_number}": {
+        "/citizen/{cpf}/firstlogin": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera a lista de memórias associadas ao telefone do cidadão.",
+                "description": "Verifica se este é o primeiro login do usuário",
                 "consumes": [
                     "application/json"
                 ],
@@ -4327,30 +4713,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memory"
+                    "citizen"
                 ],
-                "summary": "Obter memórias associadas ao número de telefone.",
+                "summary": "Obter status do primeiro login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista de memórias obtida com sucesso",
+                        "description": "Status do primeiro login obtido com sucesso",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.MemoryModel"
-                            }
+                            "$ref": "#/definitions/models.UserConfigResponse"
                         }
                     },
                     "400": {
-                        "description": "Número de telefone inválido",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4387,7 +4770,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza uma única memória associada ao telefone do cidadão.",
+                "description": "Define o status do primeiro login como falso para um usuário. Com DEFAULT_AVATAR_MODE ativo, atribui um avatar padrão do conjunto de avatares ativos a usuários que ainda não escolheram um e o retorna em avatar",
                 "consumes": [
                     "application/json"
                 ],
@@ -4395,36 +4778,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memory"
+                    "citizen"
                 ],
-                "summary": "Atualiza memória associada ao número de telefone.",
+                "summary": "Atualizar status do primeiro login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Dados da memória a ser atualizada (memory_name identifica qual memória atualizar)",
-                        "name": "memory",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.MemoryModel"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memória atualizada com sucesso",
+                        "description": "Status do primeiro login atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/models.UserConfigResponse"
                         }
                     },
                     "400": {
-                        "description": "Número de telefone ou nome da memória inválidos",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4441,12 +4815,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Memória não encontrada",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -4460,14 +4828,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/citizen/{cpf}/gender": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cria uma única memória associada ao telefone do cidadão.",
+                "description": "Atualiza ou cria o gênero autodeclarado de um cidadão por CPF. Aceita qualquer valor de texto livre, mas as opções sugeridas estão disponíveis no endpoint /citizen/gender/options.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4475,38 +4845,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memory"
+                    "citizen"
                 ],
-                "summary": "Cria memória associada ao número de telefone.",
+                "summary": "Atualizar gênero autodeclarado",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados da memória a ser criada",
-                        "name": "memory",
+                        "description": "Gênero autodeclarado",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.MemoryModel"
+                            "$ref": "#/definitions/models.SelfDeclaredGeneroInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Memória criada com sucesso",
+                    "200": {
+                        "description": "Gênero atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MemoryModel"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Número de telefone ou dados da memória inválidos",
+                        "description": "Formato de CPF inválido ou valor de gênero vazio",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -4521,14 +4909,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Memória com esse nome já existe para este número de telefone",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4542,49 +4924,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/memory/{phone_number}/{memory_name}": {
+        "/citizen/{cpf}/history/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Recupera uma única memória associada ao telefone do cidadão.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Gera um arquivo para download com o histórico de alterações (auditoria) dos dados do cidadão, com rótulos em português e valores sensíveis ocultados. Atualmente apenas o formato CSV é suportado.",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "memory"
+                    "citizen"
                 ],
-                "summary": "Obter memória associada ao número de telefone pelo nome.",
+                "summary": "Exportar histórico de alterações do cidadão",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "enum": [
+                            "csv"
+                        ],
                         "type": "string",
-                        "description": "Nome da memória",
-                        "name": "memory_name",
-                        "in": "path",
-                        "required": true
+                        "description": "Formato do arquivo (padrão: csv)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Memória obtida com sucesso",
+                        "description": "Arquivo CSV com o histórico de alterações",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MemoryModel"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Número de telefone ou nome da memória inválidos",
+                        "description": "Formato de CPF inválido ou formato de exportação não suportado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4601,12 +4984,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Memória não encontrada",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -4620,14 +4997,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/citizen/{cpf}/legal-entities": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deleta uma única memória associada ao telefone do cidadão.",
+                "description": "Recupera a lista paginada de entidades jurídicas (pessoas jurídicas) associadas ao CPF do cidadão. A busca é feita através do campo 'cpf_socio' no array 'socios' de cada entidade. Os resultados são armazenados em cache por CPF e filtro (LEGAL_ENTITY_CACHE_TTL).",
                 "consumes": [
                     "application/json"
                 ],
@@ -4635,31 +5014,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "memory"
+                    "citizen"
                 ],
-                "summary": "Deleta memória associada ao número de telefone.",
+                "summary": "Obter entidades jurídicas associadas ao CPF",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Nome da memória a ser deletada",
-                        "name": "memory_name",
-                        "in": "path",
-                        "required": true
+                        "description": "Filtro opcional por ID da natureza jurídica",
+                        "name": "natureza_juridica_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Memória deletada com sucesso"
+                    "200": {
+                        "description": "Lista paginada de entidades jurídicas obtida com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedLegalEntities"
+                        }
                     },
                     "400": {
-                        "description": "Número de telefone ou nome da memória inválidos",
+                        "description": "Formato de CPF inválido ou parâmetros de paginação inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4676,12 +5074,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Memória não encontrada",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -4697,54 +5089,113 @@ const docTemplate = `{
                 }
             }
         },
-        "/metrics": {
+        "/citizen/{cpf}/maintenance-request": {
             "get": {
-                "description": "Expõe métricas Prometheus para monitoramento do sistema",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera os chamados do 1746 de um cidadão por CPF com paginação. Cada documento representa um chamado individual. Suporta paginação por página (page/per_page) ou por cursor (cursor/per_page); o modo cursor é o preferido para históricos longos e obrigatório além de MAINTENANCE_REQUEST_MAX_PAGE, quando configurado. Os filtros status, from, to e bairro são aplicados no MongoDB e combinados com qualquer modo de paginação; os totais da paginação consideram os filtros.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "metrics"
+                    "citizen"
+                ],
+                "summary": "Obter chamados do 1746 do cidadão",
+                "parameters": [
+                    {
+                        "maxLength": 11,
+                        "minLength": 11,
+                        "type": "string",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ativa o modo cursor: vazio para a primeira página ou o pagination.next_cursor da resposta anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status dos chamados, separados por vírgula (valor exato, ex: Aberto,Em andamento)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data de início mínima, inclusiva (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data de início máxima, inclusiva (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bairro contido no endereço do chamado (sem diferenciar maiúsculas)",
+                        "name": "bairro",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Métricas Prometheus",
                 "responses": {
                     "200": {
-                        "description": "Métricas Prometheus obtidas com sucesso",
+                        "description": "Lista paginada de chamados do 1746 obtida com sucesso",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.PaginatedMaintenanceRequests"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "400": {
+                        "description": "Formato de CPF inválido, parâmetros de paginação inválidos, cursor inválido, página além do limite ou filtros inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/notification-categories": {
-            "get": {
-                "description": "List all active notification categories",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "notification-categories"
-                ],
-                "summary": "List notification categories",
-                "responses": {
-                    "200": {
-                        "description": "List of active categories",
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationCategoriesResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4752,61 +5203,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/beta-status": {
+        "/citizen/{cpf}/notification-preferences": {
             "get": {
-                "description": "Verifica se um número de telefone está na whitelist beta (com cache)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get notification preferences for a citizen including global and category opt-ins",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Beta Whitelist"
+                    "notification-preferences"
                 ],
-                "summary": "Verificar status beta",
+                "summary": "Get citizen notification preferences",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número de telefone",
-                        "name": "phone_number",
+                        "description": "CPF number",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status beta verificado com sucesso",
+                        "description": "Notification preferences",
                         "schema": {
-                            "$ref": "#/definitions/models.BetaStatusResponse"
+                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
                         }
                     },
                     "400": {
-                        "description": "Número de telefone é obrigatório ou inválido",
+                        "description": "Invalid CPF format",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/phone/{phone_number}/bind": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Vincula um número de telefone a um CPF sem definir opt-in",
+                "description": "Update notification preferences for a citizen (global and/or category opt-ins)",
                 "consumes": [
                     "application/json"
                 ],
@@ -4814,72 +5277,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "notification-preferences"
                 ],
-                "summary": "Vincular telefone a CPF",
+                "summary": "Update citizen notification preferences",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF number",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados da vinculação",
+                        "description": "Updated preferences",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BindRequest"
+                            "$ref": "#/definitions/models.UpdateNotificationPreferencesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Telefone vinculado ao CPF com sucesso",
+                        "description": "Preferences updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.BindResponse"
+                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou dados de vinculação incorretos",
+                        "description": "Invalid CPF format or request body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflito - telefone já está vinculado a outro CPF",
+                        "description": "Access denied",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "422": {
-                        "description": "Dados não processáveis - CPF ou telefone inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Invalid category ID or too many category opt-ins",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4887,69 +5338,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/citizen": {
-            "get": {
+        "/citizen/{cpf}/notification-preferences/categories/{category_id}": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtém informações do cidadão associado a um número de telefone",
-                "produces": [
+                "description": "Update opt-in preference for a single notification category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "notification-preferences"
                 ],
-                "summary": "Obter cidadão por telefone",
+                "summary": "Update single category preference",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF number",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Category preference",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCategoryPreferenceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Informações do cidadão obtidas com sucesso",
+                        "description": "Preference updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.PhoneCitizenResponse"
+                            "$ref": "#/definitions/models.NotificationPreferencesResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido",
+                        "description": "Invalid CPF format or request body",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Token de autenticação não fornecido ou inválido",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - permissões insuficientes",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Nenhum cidadão encontrado para este telefone",
+                        "description": "Access denied",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "422": {
+                        "description": "Invalid category ID or too many category opt-ins",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4957,79 +5421,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/notification-preferences": {
+        "/citizen/{cpf}/notification-target": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get notification preferences for a phone number including global and category opt-ins (synced with CPF). Admin only.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Indica para qual telefone as notificações do cidadão devem ser enviadas. Os telefones verificados são considerados na ordem configurada em NOTIFICATION_PHONE_FALLBACK (por padrão, o telefone autodeclarado confirmado por código e, em seguida, o telefone governamental da base), ignorando telefones em quarentena ou com opt-out. Cidadãos com opt-out de notificações não têm telefone de destino. O canal preferido do cidadão (PUT /citizen/{cpf}/preferred-channel), quando escolhido, é retornado em preferred_channel.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
+                    "citizen"
                 ],
-                "summary": "Get phone notification preferences (admin only)",
+                "summary": "Obter telefone para notificações do cidadão",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Phone number",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification preferences",
+                        "description": "Telefone de destino resolvido (found=false com o motivo quando não há telefone)",
                         "schema": {
-                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                            "$ref": "#/definitions/models.NotificationTargetResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid phone format",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Phone not found",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/citizen/{cpf}/optin": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update notification preferences for a phone number (synced with CPF preferences). Admin only.",
+                "description": "Verifica se o usuário optou por receber notificações",
                 "consumes": [
                     "application/json"
                 ],
@@ -5037,81 +5496,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
+                    "citizen"
                 ],
-                "summary": "Update phone notification preferences (admin only)",
+                "summary": "Obter status de opt-in",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Phone number",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated preferences",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateNotificationPreferencesRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Preferences updated successfully",
+                        "description": "Status do opt-in obtido com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                            "$ref": "#/definitions/models.UserConfigOptInResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid phone format or request body",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Phone not found",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Invalid category ID",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/phone/{phone_number}/notification-preferences/categories/{category_id}": {
-            "patch": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update opt-in preference for a single notification category for a phone number. Admin only.",
+                "description": "Atualiza o status de opt-in do usuário para notificações. No opt-out, aceita um motivo (reason, código de /config/opt-out-reasons) e uma observação livre (note), que são salvos na configuração do usuário e no histórico de opt-in. O motivo é obrigatório quando OPT_OUT_REASON_REQUIRED está habilitado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5119,73 +5561,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification-preferences"
+                    "citizen"
                 ],
-                "summary": "Update single category preference for phone (admin only)",
+                "summary": "Atualizar status de opt-in",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Phone number",
-                        "name": "phone_number",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Category preference",
+                        "description": "Status de opt-in",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCategoryPreferenceRequest"
+                            "$ref": "#/definitions/models.UserConfigOptInResponse"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Preference updated successfully",
+                        "description": "Status do opt-in atualizado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                            "$ref": "#/definitions/models.UserConfigOptInResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid phone format or request body",
+                        "description": "Formato de CPF inválido, dados de opt-in incorretos ou motivo de opt-out ausente/inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token de autenticação não fornecido ou inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Phone not found",
+                    "422": {
+                        "description": "Dados não processáveis - valor de opt-in inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Invalid category ID",
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Erro interno do servidor",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5193,14 +5628,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/opt-in": {
-            "post": {
+        "/citizen/{cpf}/pets": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Realiza opt-in para receber notificações do chatbot",
+                "description": "Recupera a lista paginada de pets associados ao CPF do cidadão.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5208,36 +5643,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Realizar opt-in",
+                "summary": "Obter pets associados ao CPF",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados do opt-in",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.OptInRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Opt-in realizado com sucesso",
+                        "description": "Lista paginada de pets obtida com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.OptInResponse"
+                            "$ref": "#/definitions/models.PaginatedPets"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou dados de opt-in incorretos",
+                        "description": "Formato de CPF inválido ou parâmetros de paginação inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5254,18 +5697,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflito - telefone já possui opt-in ativo",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - telefone em quarentena ou bloqueado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -5279,16 +5710,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/phone/{phone_number}/opt-out": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Realiza opt-out para parar de receber notificações do chatbot",
+                "description": "Registra um novo pet para o CPF do cidadão (auto-declarado).",
                 "consumes": [
                     "application/json"
                 ],
@@ -5296,36 +5725,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Realizar opt-out",
+                "summary": "Registrar novo pet",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados do opt-out",
-                        "name": "data",
+                        "description": "Dados do pet para registro",
+                        "name": "pet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OptOutRequest"
+                            "$ref": "#/definitions/models.PetRegistrationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Opt-out realizado com sucesso",
+                    "201": {
+                        "description": "Pet registrado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.OptOutResponse"
+                            "$ref": "#/definitions/models.Pet"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou dados de opt-out incorretos",
+                        "description": "Formato de CPF inválido ou dados do pet inválidos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5342,18 +5773,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Telefone não encontrado ou sem opt-in ativo",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - opt-out já realizado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "429": {
                         "description": "Muitas requisições - limite de taxa excedido",
                         "schema": {
@@ -5369,14 +5788,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/quarantine": {
-            "post": {
+        "/citizen/{cpf}/pets/stats": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Coloca um número de telefone em quarentena (apenas administradores)",
+                "description": "Recupera as estatísticas de quantidade de pets por tipo associadas ao CPF do cidadão.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5384,27 +5803,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Colocar telefone em quarentena",
+                "summary": "Obter estatísticas de pets do CPF",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Telefone colocado em quarentena com sucesso",
+                        "description": "Estatísticas obtidas com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.QuarantineResponse"
+                            "$ref": "#/definitions/models.PetStatsResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou parâmetros incorretos",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5416,13 +5837,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores podem colocar telefones em quarentena",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - telefone já em quarentena ou inválido",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5440,39 +5855,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/citizen/{cpf}/pets/{pet_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Libera um número de telefone da quarentena (apenas administradores)",
+                "description": "Recupera um pet específico associado ao CPF do cidadão pelo ID do pet.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Liberar telefone da quarentena",
+                "summary": "Obter pet específico por ID",
                 "parameters": [
                     {
+                        "maxLength": 11,
+                        "minLength": 11,
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do pet",
+                        "name": "pet_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Telefone liberado da quarentena com sucesso",
+                        "description": "Pet obtido com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.QuarantineResponse"
+                            "$ref": "#/definitions/models.Pet"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido",
+                        "description": "Formato de CPF inválido ou ID do pet inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5484,19 +5913,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Acesso negado - somente administradores podem liberar telefones da quarentena",
+                        "description": "Acesso negado - permissões insuficientes",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Telefone não encontrado em quarentena",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "422": {
-                        "description": "Dados não processáveis - telefone não está em quarentena",
+                        "description": "Pet não encontrado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5516,14 +5939,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/reject-registration": {
-            "post": {
+        "/citizen/{cpf}/phone": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Rejeita um registro e bloqueia o mapeamento telefone-CPF",
+                "description": "Atualiza ou cria o telefone autodeclarado de um cidadão por CPF. Apenas o campo de telefone é atualizado (armazenado como pendente até verificado). O número é normalizado (DDD válido, celular com 9 dígitos iniciando em 9 ou fixo com 8 dígitos) antes de iniciar a verificação.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5531,38 +5954,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Rejeitar registro",
+                "summary": "Atualizar telefone autodeclarado",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados da rejeição",
+                        "description": "Telefone autodeclarado",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RejectRegistrationRequest"
+                            "$ref": "#/definitions/models.SelfDeclaredPhoneInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Registro rejeitado com sucesso",
+                        "description": "Telefone autodeclarado submetido para validação com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.RejectRegistrationResponse"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou dados de rejeição incorretos",
+                        "description": "Formato de CPF inválido ou dados de telefone incorretos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -5577,61 +6012,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Dados não processáveis - registro não pode ser rejeitado",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "404": {
+                        "description": "Cidadão não encontrado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "409": {
+                        "description": "Conflito - telefone não alterado (telefone corresponde aos dados atuais verificados)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/phone/{phone_number}/status": {
-            "get": {
-                "description": "Obtém o status de um número de telefone (quarentena, CPF vinculado, opt-in/opt-out, etc.)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "phone"
-                ],
-                "summary": "Obter status do telefone",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Status do telefone obtido com sucesso",
-                        "schema": {
-                            "$ref": "#/definitions/models.PhoneStatusResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Formato de telefone inválido",
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Telefone não encontrado no sistema",
+                    "422": {
+                        "description": "Dados não processáveis - número de telefone inválido (DDD, quantidade de dígitos ou prefixo)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5651,14 +6051,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/phone/{phone_number}/validate-registration": {
+        "/citizen/{cpf}/phone/validate": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Valida um registro de usuário contra dados base do governo",
+                "description": "Valida o código de verificação enviado para o número de telefone. Após PHONE_VERIFICATION_MAX_FAILURES tentativas com código inválido dentro de PHONE_VERIFICATION_LOCKOUT_WINDOW, novas tentativas do CPF são bloqueadas com 429 até o fim da janela (header Retry-After). O telefone validado passa a ser o principal e os telefones verificados antes são mantidos em telefone.verificados. Com PHONE_VERIFIED_WEBHOOK_ENABLED=true, uma validação bem-sucedida enfileira em segundo plano o webhook phone.verified (CPF, telefone normalizado e canal).",
                 "consumes": [
                     "application/json"
                 ],
@@ -5666,36 +6066,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "phone"
+                    "citizen"
                 ],
-                "summary": "Validar registro",
+                "summary": "Validar verificação de telefone",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Número do telefone",
-                        "name": "phone_number",
+                        "description": "Número do CPF",
+                        "name": "cpf",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados do registro",
+                        "description": "Código de verificação e telefone",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ValidateRegistrationRequest"
+                            "$ref": "#/definitions/models.PhoneVerificationValidateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Registro validado com sucesso",
+                        "description": "Código de verificação validado com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/models.ValidateRegistrationResponse"
+                            "$ref": "#/definitions/handlers.SuccessResponse"
                         }
                     },
                     "400": {
-                        "description": "Formato de telefone inválido ou dados de registro incorretos",
+                        "description": "Formato de CPF inválido ou dados de verificação incorretos",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5712,16 +6112,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Código de verificação não encontrado ou expirado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "422": {
-                        "description": "Dados não processáveis - informações não conferem com a base",
+                        "description": "Dados não processáveis - código ou número de telefone inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                        "description": "Muitas requisições - limite de taxa excedido ou CPF bloqueado após tentativas com código inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Segundos até o fim do bloqueio"
+                            }
                         }
                     },
                     "500": {
@@ -5733,45 +6145,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/validate/email": {
+        "/citizen/{cpf}/phone/{phone_id}/set-primary": {
             "post": {
-                "description": "Valida formato e estrutura de endereços de email, retornando informações detalhadas sobre o endereço quando válido.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Define qual dos telefones verificados do cidadão é o principal. phone_id é o telefone no formato de armazenamento (DDI+DDD+número, o id de telefone.verificados). O telefone principal é exposto em telefone.principal e os demais verificados em telefone.verificados. A troca é registrada na auditoria; definir o telefone que já é o principal não altera nada.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "validation"
+                    "citizen"
                 ],
-                "summary": "Valida endereço de email",
+                "summary": "Definir telefone principal",
                 "parameters": [
                     {
-                        "description": "Email a ser validado",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.EmailValidationRequest"
-                        }
+                        "type": "string",
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do telefone verificado (DDI+DDD+número)",
+                        "name": "phone_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Email validado com sucesso",
+                        "description": "Telefones autodeclarados com o novo principal",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EmailValidationResponse"
+                            "$ref": "#/definitions/models.Telefone"
                         }
                     },
                     "400": {
-                        "description": "Campo email é obrigatório ou formato inválido",
+                        "description": "Formato de CPF inválido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "Muitas requisições - limite de taxa excedido",
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Telefone não está entre os verificados do CPF",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5785,24 +6216,2557 @@ const docTemplate = `{
                 }
             }
         },
-        "/validate/phone": {
-            "post": {
-                "description": "Valida DDI, DDD e número para qualquer telefone internacional.",
-                "consumes": [
-                    "application/json"
+        "/citizen/{cpf}/preferred-channel": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retorna o canal pelo qual o cidadão prefere receber notificações (código de /config/channels), ou null quando não foi escolhido",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "validation"
+                    "citizen"
                 ],
-                "summary": "Valida número de telefone",
+                "summary": "Obter canal preferido de notificação",
                 "parameters": [
                     {
-                        "description": "Telefone a ser validado",
-                        "name": "data",
-                        "in": "body",
+                        "type": "string",
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Canal preferido obtido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PreferredChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CPF inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define o canal pelo qual o cidadão prefere receber notificações. O canal deve ser um dos canais disponíveis em /config/channels. O canal preferido é informado em GET /citizen/{cpf}/notification-target e nos webhooks de endereço, telefone verificado e verificação de e-mail (preferred_channel).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Atualizar canal preferido de notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Canal preferido",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PreferredChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Canal preferido atualizado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PreferredChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CPF inválido, corpo da requisição inválido ou canal indisponível",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/citizen/{cpf}/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna em uma única resposta os dados do cidadão (como em GET /citizen/{cpf}), a carteira (como em GET /citizen/{cpf}/wallet, com as mesmas regras de integração da clínica da família) e a completude do perfil (como em GET /citizen/{cpf}/completeness). Os dados do cidadão e os autodeclarados são lidos uma única vez e compartilhados entre as três seções.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Obter perfil consolidado do cidadão",
+                "parameters": [
+                    {
+                        "maxLength": 11,
+                        "minLength": 11,
+                        "type": "string",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seções da carteira a retornar, separadas por vírgula (documentos, saude, assistencia_social, educacao); padrão: todas. A consulta de clínica da família só é feita quando saude é solicitada",
+                        "name": "sections",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Perfil do cidadão obtido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.CitizenProfileResponse"
+                        },
+                        "headers": {
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED); a completude é omitida nesse caso"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CPF inválido ou seção desconhecida",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cidadão não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/citizen/{cpf}/self-declared": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza em uma única chamada endereço, email, telefone e/ou etnia autodeclarados. Apenas os campos presentes no corpo são atualizados; campos omitidos permanecem inalterados. Todos os campos informados são validados antes de qualquer alteração. A resposta indica, por campo, se foi atualizado (updated), se não houve alteração por ser idêntico aos dados atuais (unchanged), se iniciou a verificação de telefone ou de email (verification_pending) ou se falhou (error). Cada campo alterado gera seu próprio evento de auditoria.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Atualizar parcialmente dados autodeclarados",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do CPF",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos autodeclarados a atualizar",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredPatchInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado da atualização por campo",
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredPatchResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão dos dados autodeclarados"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CPF inválido, corpo vazio ou dados incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Dados autodeclarados alterados por outra requisição (versão desatualizada)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - número de telefone, CEP ou UF inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Nenhum campo pôde ser atualizado",
+                        "schema": {
+                            "$ref": "#/definitions/models.SelfDeclaredPatchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/citizen/{cpf}/wallet": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera os dados da carteira do cidadão por CPF, incluindo informações de saúde e outros dados da carteira.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "citizen"
+                ],
+                "summary": "Obter dados da carteira do cidadão",
+                "parameters": [
+                    {
+                        "maxLength": 11,
+                        "minLength": 11,
+                        "type": "string",
+                        "description": "CPF do cidadão (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Seções a retornar, separadas por vírgula (documentos, saude, assistencia_social, educacao); padrão: todas. A consulta de clínica da família só é feita quando saude é solicitada",
+                        "name": "sections",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de primeiro nível a retornar, separados por vírgula (ex: cpf,saude); aplicado após sections",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dados da carteira do cidadão obtidos com sucesso (apenas cpf e as seções solicitadas quando sections é informado)",
+                        "schema": {
+                            "$ref": "#/definitions/models.CitizenWallet"
+                        },
+                        "headers": {
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CPF inválido ou seção desconhecida",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Cidadão não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cnaes": {
+            "get": {
+                "description": "Recupera a lista paginada de CNAEs (Classificação Nacional de Atividades Econômicas) com filtros opcionais",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cnaes"
+                ],
+                "summary": "Listar CNAEs",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Texto para busca na denominação",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtro por seção (ex: A)",
+                        "name": "secao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtro por divisão (ex: 1)",
+                        "name": "divisao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtro por grupo (ex: 1.1)",
+                        "name": "grupo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtro por classe (ex: 01.12-1)",
+                        "name": "classe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtro por subclasse",
+                        "name": "subclasse",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista paginada de CNAEs obtida com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.CNAEListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/channels": {
+            "get": {
+                "description": "Obtém a lista de canais disponíveis para comunicação. A lista é mantida em memória; o header Last-Modified informa a última atualização.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Obter canais disponíveis",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Horário da última atualização da lista em memória"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/config/flags": {
+            "get": {
+                "description": "Avalia todas as feature flags para um CPF: cada flag está ativa quando habilitada e o CPF está na lista da flag, pertence (por um telefone na whitelist) a um dos grupos beta da flag ou cai no percentual de rollout. Sem CPF, apenas flags com rollout de 100% ficam ativas. Flags conhecidas ainda não cadastradas usam o valor padrão. As avaliações são cacheadas por FEATURE_FLAG_CACHE_TTL, mas qualquer alteração de flag (como desabilitá-la) vale imediatamente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Avaliar feature flags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CPF do cidadão",
+                        "name": "cpf",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flags avaliadas com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureFlagEvaluationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "CPF inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/opt-out-reasons": {
+            "get": {
+                "description": "Obtém a lista de motivos válidos para opt-out. A lista é mantida em memória; o header Last-Modified informa a última atualização.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Obter motivos de opt-out",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Horário da última atualização da lista em memória"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/cpf-secretaria/{cpf}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna a lista de cd_uas vinculados ao CPF na base de apoio. Usado por outras APIs (app-go-api) via service account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cpf-secretaria"
+                ],
+                "summary": "Consultar secretarias de um CPF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CPF do usuário (11 dígitos)",
+                        "name": "cpf",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CPFSecretariaQueryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments": {
+            "get": {
+                "description": "Recupera a lista paginada de departamentos com filtros opcionais (hierarquia, nível, busca).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "Listar departamentos/unidades administrativas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Código da UA pai para filtrar departamentos filhos",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Nível mínimo do departamento",
+                        "name": "min_level",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Nível máximo do departamento",
+                        "name": "max_level",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Nível exato do departamento (sobrescreve min/max)",
+                        "name": "exact_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sigla da UA para filtro exato",
+                        "name": "sigla_ua",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Texto para busca textual em nome_ua",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Número da página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 10, máximo: 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista paginada de departamentos obtida com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.DepartmentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments/{cd_ua}": {
+            "get": {
+                "description": "Recupera um departamento específico pelo código da UA (cd_ua).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "Obter departamento/unidade administrativa por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Código da Unidade Administrativa",
+                        "name": "cd_ua",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Departamento obtido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.DepartmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Código da UA inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Departamento não encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Verifica a saúde da API e suas dependências (MongoDB e Redis). Retorna status detalhado para cada serviço.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Verificação de saúde",
+                "responses": {
+                    "200": {
+                        "description": "Todos os serviços estão saudáveis",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Um ou mais serviços não estão saudáveis",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/deep": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifica a saúde da API e de todas as suas dependências internas: MongoDB, Redis, serviço de consulta de CF (ping no MCP), backlog das filas de sincronização e utilização do buffer do worker de auditoria. Cada verificação tem seu próprio timeout. Apenas administradores, pois expõe detalhes internos; use /health para load balancers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Verificação de saúde detalhada",
+                "responses": {
+                    "200": {
+                        "description": "Todos os componentes estão saudáveis",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Um ou mais componentes não estão saudáveis",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/legal-entity/{cnpj}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera informações detalhadas de uma entidade jurídica (pessoa jurídica) pelo CNPJ. O acesso é permitido para administradores ou se o CPF do JWT estiver como responsável ou sócio da entidade.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "legal-entity"
+                ],
+                "summary": "Obter informações de uma entidade jurídica por CNPJ",
+                "parameters": [
+                    {
+                        "maxLength": 14,
+                        "minLength": 14,
+                        "type": "string",
+                        "description": "CNPJ da entidade (14 dígitos)",
+                        "name": "cnpj",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Informações da entidade jurídica obtidas com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.LegalEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de CNPJ inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - sem permissão para visualizar esta entidade",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entidade jurídica não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/{phone_number}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera a lista de memórias associadas ao telefone do cidadão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memory"
+                ],
+                "summary": "Obter memórias associadas ao número de telefone.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista de memórias obtida com sucesso",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MemoryModel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Número de telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza uma única memória associada ao telefone do cidadão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memory"
+                ],
+                "summary": "Atualiza memória associada ao número de telefone.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da memória a ser atualizada (memory_name identifica qual memória atualizar)",
+                        "name": "memory",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MemoryModel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Memória atualizada com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Número de telefone ou nome da memória inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Memória não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria uma única memória associada ao telefone do cidadão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memory"
+                ],
+                "summary": "Cria memória associada ao número de telefone.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da memória a ser criada",
+                        "name": "memory",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MemoryModel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Memória criada com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MemoryModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Número de telefone ou dados da memória inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Memória com esse nome já existe para este número de telefone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/memory/{phone_number}/{memory_name}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recupera uma única memória associada ao telefone do cidadão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memory"
+                ],
+                "summary": "Obter memória associada ao número de telefone pelo nome.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome da memória",
+                        "name": "memory_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Memória obtida com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MemoryModel"
+                        }
+                    },
+                    "400": {
+                        "description": "Número de telefone ou nome da memória inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Memória não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deleta uma única memória associada ao telefone do cidadão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "memory"
+                ],
+                "summary": "Deleta memória associada ao número de telefone.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome da memória a ser deletada",
+                        "name": "memory_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Memória deletada com sucesso"
+                    },
+                    "400": {
+                        "description": "Número de telefone ou nome da memória inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Memória não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Expõe métricas Prometheus para monitoramento do sistema",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Métricas Prometheus",
+                "responses": {
+                    "200": {
+                        "description": "Métricas Prometheus obtidas com sucesso",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-categories": {
+            "get": {
+                "description": "List all active notification categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-categories"
+                ],
+                "summary": "List notification categories",
+                "responses": {
+                    "200": {
+                        "description": "List of active categories",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationCategoriesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/beta-status": {
+            "get": {
+                "description": "Verifica se um número de telefone está na whitelist beta (com cache)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beta Whitelist"
+                ],
+                "summary": "Verificar status beta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número de telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status beta verificado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.BetaStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Número de telefone é obrigatório ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/bind": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Vincula um número de telefone a um CPF sem definir opt-in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Vincular telefone a CPF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da vinculação",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BindRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Telefone vinculado ao CPF com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.BindResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou dados de vinculação incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflito - telefone já está vinculado a outro CPF",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - CPF ou telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/citizen": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtém informações do cidadão associado a um número de telefone",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Obter cidadão por telefone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Informações do cidadão obtidas com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneCitizenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Nenhum cidadão encontrado para este telefone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get notification preferences for a phone number including global and category opt-ins (synced with CPF). Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-preferences"
+                ],
+                "summary": "Get phone notification preferences (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Phone not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update notification preferences for a phone number (synced with CPF preferences). Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-preferences"
+                ],
+                "summary": "Update phone notification preferences (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated preferences",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Phone not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid category ID or too many category opt-ins",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/notification-preferences/categories/{category_id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update opt-in preference for a single notification category for a phone number. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-preferences"
+                ],
+                "summary": "Update single category preference for phone (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category preference",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCategoryPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preference updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneNotificationPreferencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Phone not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid category ID or too many category opt-ins",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/opt-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Realiza opt-in para receber notificações do chatbot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Realizar opt-in",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do opt-in",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OptInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Opt-in realizado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.OptInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou dados de opt-in incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflito - telefone já possui opt-in ativo",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - telefone em quarentena ou bloqueado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/opt-out": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Realiza opt-out para parar de receber notificações do chatbot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Realizar opt-out",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do opt-out",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OptOutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Opt-out realizado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.OptOutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou dados de opt-out incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Telefone não encontrado ou sem opt-in ativo",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - opt-out já realizado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/quarantine": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Coloca um número de telefone em quarentena (apenas administradores)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Colocar telefone em quarentena",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Telefone colocado em quarentena com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuarantineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou parâmetros incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores podem colocar telefones em quarentena",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - telefone já em quarentena ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Libera um número de telefone da quarentena (apenas administradores)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Liberar telefone da quarentena",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Telefone liberado da quarentena com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuarantineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - somente administradores podem liberar telefones da quarentena",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Telefone não encontrado em quarentena",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - telefone não está em quarentena",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/reject-registration": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rejeita um registro e bloqueia o mapeamento telefone-CPF",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Rejeitar registro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da rejeição",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RejectRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registro rejeitado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.RejectRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou dados de rejeição incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - registro não pode ser rejeitado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/status": {
+            "get": {
+                "description": "Obtém o status de um número de telefone (quarentena, CPF vinculado, opt-in/opt-out, etc.)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Obter status do telefone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status do telefone obtido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Telefone não encontrado no sistema",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/{phone_number}/validate-registration": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Valida um registro de usuário contra dados base do governo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Validar registro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados do registro",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registro validado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidateRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido ou dados de registro incorretos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado - permissões insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dados não processáveis - informações não conferem com a base",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/service/phone/{phone_number}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtém o status do telefone com motivo da quarentena, grupo beta, quantidade de CPFs vinculados e última ação de opt-in, para decisões de roteamento do chatbot. Requer token de serviço com o escopo phone:read:status. O resultado fica em cache por PHONE_STATUS_DETAILS_CACHE_TTL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "phone"
+                ],
+                "summary": "Obter status detalhado do telefone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Número do telefone",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status detalhado do telefone obtido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/models.PhoneStatusDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Formato de telefone inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token de autenticação não fornecido ou inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Token de serviço sem o escopo phone:read:status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/validate/cpf": {
+            "post": {
+                "description": "Valida o formato e os dígitos verificadores de um CPF, sem autenticação, para formulários validarem o CPF antes do login. Limitado a CPF_VALIDATION_RATE_LIMIT requisições por IP a cada CPF_VALIDATION_RATE_WINDOW; acima disso retorna 429 com o header Retry-After.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "validation"
+                ],
+                "summary": "Valida CPF",
+                "parameters": [
+                    {
+                        "description": "CPF a ser validado",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CPFValidationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CPF validado (valid=false com o motivo quando inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CPFValidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Campo cpf é obrigatório",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Segundos até o fim da janela"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/validate/email": {
+            "post": {
+                "description": "Valida formato e estrutura de endereços de email, retornando informações detalhadas sobre o endereço quando válido.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "validation"
+                ],
+                "summary": "Valida endereço de email",
+                "parameters": [
+                    {
+                        "description": "Email a ser validado",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EmailValidationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email validado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EmailValidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Campo email é obrigatório ou formato inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Muitas requisições - limite de taxa excedido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/validate/phone": {
+            "post": {
+                "description": "Valida DDI, DDD e número para qualquer telefone internacional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "validation"
+                ],
+                "summary": "Valida número de telefone",
+                "parameters": [
+                    {
+                        "description": "Telefone a ser validado",
+                        "name": "data",
+                        "in": "body",
                         "required": true,
                         "schema": {
                             "$ref": "#/definitions/handlers.PhoneValidationRequest"
@@ -5836,575 +8800,1042 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.CacheReadRequest": {
+        }
+    },
+    "definitions": {
+        "handlers.CPFValidationRequest": {
+            "description": "Estrutura de entrada contendo o CPF a ser validado.",
+            "type": "object",
+            "required": [
+                "cpf"
+            ],
+            "properties": {
+                "cpf": {
+                    "description": "CPF com ou sem pontuação.\nexample: \"123.456.789-09\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CPFValidationResponse": {
+            "description": "Resultado da validação, com o motivo quando o CPF é inválido.",
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Motivo da invalidez: invalid_format, repeated_digits ou invalid_check_digits.",
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Indica se o CPF é válido.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.CacheReadRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "citizen:12345678901"
+                }
+            }
+        },
+        "handlers.CacheReadResponse": {
+            "type": "object",
+            "properties": {
+                "exists": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                },
+                "value": {}
+            }
+        },
+        "handlers.EmailValidationRequest": {
+            "description": "Estrutura de entrada contendo o endereço de email a ser validado.",
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Endereço de email a ser validado.\nexample: \"usuario@exemplo.com\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.EmailValidationResponse": {
+            "description": "Resultado da validação, contendo informações sobre o endereço de email quando válido.",
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "description": "Domínio do email (após o @)",
+                    "type": "string"
+                },
+                "local_part": {
+                    "description": "Parte local do email (antes do @)",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Mensagem de retorno.",
+                    "type": "string"
+                },
+                "normalized": {
+                    "description": "Email normalizado (lowercase)",
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Indica se o email é válido.",
+                    "type": "boolean"
+                },
+                "validation_type": {
+                    "description": "Tipo de validação aplicada",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "CPF_INVALID"
+                },
+                "details": {},
+                "error": {
+                    "description": "Deprecated: use Message. Mirrors Message while clients migrate to code/message.",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "services": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.ServiceHealth"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MemoryModel": {
+            "type": "object",
+            "required": [
+                "description",
+                "memory_name",
+                "memory_type",
+                "relevance",
+                "value"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "memory_id": {
+                    "type": "string"
+                },
+                "memory_name": {
+                    "type": "string"
+                },
+                "memory_type": {
+                    "type": "string",
+                    "enum": [
+                        "base",
+                        "appended"
+                    ]
+                },
+                "relevance": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PhoneValidationRequest": {
+            "description": "Estrutura de entrada contendo o número de telefone a ser validado.",
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "description": "Número de telefone em formato internacional ou nacional.\nexample: \"+55 11 99988-7766\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PhoneValidationResponse": {
+            "description": "Resultado da validação, contendo a decomposição (DDI, DDD, número) quando válida.",
+            "type": "object",
+            "properties": {
+                "ddd": {
+                    "description": "DDD (código de área)",
+                    "type": "string"
+                },
+                "ddi": {
+                    "description": "DDI (código do país)",
+                    "type": "string"
+                },
+                "e164": {
+                    "description": "Representação E.164 do número",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Mensagem de retorno.",
+                    "type": "string"
+                },
+                "numero": {
+                    "description": "Número do assinante",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "Região ISO 3166-1 alpha-2",
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Indica se o número é válido.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ServiceHealth": {
             "type": "object",
-            "required": [
-                "key"
-            ],
             "properties": {
-                "key": {
-                    "type": "string",
-                    "example": "citizen:12345678901"
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.CacheReadResponse": {
+        "handlers.SuccessResponse": {
             "type": "object",
             "properties": {
-                "exists": {
-                    "type": "boolean"
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "VALIDATION_FAILED"
                 },
-                "key": {
+                "error": {
+                    "description": "Deprecated: use Message",
                     "type": "string"
                 },
-                "ttl_seconds": {
-                    "type": "integer"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.ValidationError"
+                    }
                 },
-                "value": {}
+                "message": {
+                    "type": "string"
+                }
             }
         },
-        "handlers.EmailValidationRequest": {
-            "description": "Estrutura de entrada contendo o endereço de email a ser validado.",
+        "models.Accountant": {
             "type": "object",
-            "required": [
-                "email"
-            ],
             "properties": {
-                "email": {
-                    "description": "Endereço de email a ser validado.\nexample: \"usuario@exemplo.com\"",
+                "pf": {
+                    "$ref": "#/definitions/models.AccountantIndividual"
+                },
+                "pj": {
+                    "$ref": "#/definitions/models.AccountantCorporate"
+                }
+            }
+        },
+        "models.AccountantCorporate": {
+            "type": "object",
+            "properties": {
+                "classificacao_crc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sequencial_crc": {
+                    "type": "string"
+                },
+                "tipo_crc": {
                     "type": "string"
                 }
             }
         },
-        "handlers.EmailValidationResponse": {
-            "description": "Resultado da validação, contendo informações sobre o endereço de email quando válido.",
+        "models.AccountantIndividual": {
             "type": "object",
             "properties": {
-                "domain": {
-                    "description": "Domínio do email (após o @)",
+                "classificacao_crc": {
                     "type": "string"
                 },
-                "local_part": {
-                    "description": "Parte local do email (antes do @)",
+                "id": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "Mensagem de retorno.",
+                "sequencial_crc": {
                     "type": "string"
                 },
-                "normalized": {
-                    "description": "Email normalizado (lowercase)",
+                "tipo_crc": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccreditedClinic": {
+            "type": "object",
+            "properties": {
+                "celular": {
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Indica se o email é válido.",
-                    "type": "boolean"
+                "email": {
+                    "type": "string"
                 },
-                "validation_type": {
-                    "description": "Tipo de validação aplicada",
+                "endereco": {
+                    "$ref": "#/definitions/models.ClinicAddress"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "telefone": {
                     "type": "string"
                 }
             }
         },
-        "handlers.ErrorResponse": {
+        "models.AddCPFSecretariaRequest": {
             "type": "object",
+            "required": [
+                "cd_ua"
+            ],
             "properties": {
-                "error": {
+                "cd_ua": {
                     "type": "string"
                 }
             }
         },
-        "handlers.HealthResponse": {
+        "models.Aluno": {
             "type": "object",
             "properties": {
-                "services": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/handlers.ServiceHealth"
-                    }
+                "conceito": {
+                    "type": "string"
                 },
-                "status": {
+                "frequencia": {
+                    "type": "number"
+                },
+                "indicador": {
                     "type": "boolean"
+                }
+            }
+        },
+        "models.AssistenciaSocial": {
+            "type": "object",
+            "properties": {
+                "cadunico": {
+                    "$ref": "#/definitions/models.CadUnico"
                 },
-                "timestamp": {
+                "cras": {
+                    "$ref": "#/definitions/models.CRAS"
+                }
+            }
+        },
+        "models.AvatarRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "image": {
                     "type": "string"
                 },
-                "version": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "handlers.MemoryModel": {
+        "models.AvatarResponse": {
             "type": "object",
-            "required": [
-                "description",
-                "memory_name",
-                "memory_type",
-                "relevance",
-                "value"
-            ],
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "memory_id": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string"
                 },
-                "memory_name": {
+                "url": {
                     "type": "string"
+                }
+            }
+        },
+        "models.AvatarsListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AvatarResponse"
+                    }
                 },
-                "memory_type": {
-                    "type": "string",
-                    "enum": [
-                        "base",
-                        "appended"
-                    ]
+                "page": {
+                    "type": "integer"
                 },
-                "relevance": {
-                    "type": "string",
-                    "enum": [
-                        "low",
-                        "medium",
-                        "high"
-                    ]
+                "per_page": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 },
-                "value": {
-                    "type": "string"
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.PhoneValidationRequest": {
-            "description": "Estrutura de entrada contendo o número de telefone a ser validado.",
+        "models.BetaGroupListResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BetaGroupResponse"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.PaginationInfo"
+                },
+                "total_groups": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BetaGroupRequest": {
             "type": "object",
             "required": [
-                "phone"
+                "name"
             ],
             "properties": {
-                "phone": {
-                    "description": "Número de telefone em formato internacional ou nacional.\nexample: \"+55 11 99988-7766\"",
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "handlers.PhoneValidationResponse": {
-            "description": "Resultado da validação, contendo a decomposição (DDI, DDD, número) quando válida.",
+        "models.BetaGroupResponse": {
             "type": "object",
             "properties": {
-                "ddd": {
-                    "description": "DDD (código de área)",
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "models.BetaGroupStats": {
+            "type": "object",
+            "properties": {
+                "bound_cpf_count": {
+                    "description": "Whitelisted phones bound to a CPF",
+                    "type": "integer"
                 },
-                "ddi": {
-                    "description": "DDI (código do país)",
+                "computed_at": {
                     "type": "string"
                 },
-                "e164": {
-                    "description": "Representação E.164 do número",
+                "group_id": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "Mensagem de retorno.",
+                "group_name": {
                     "type": "string"
                 },
-                "numero": {
-                    "description": "Número do assinante",
-                    "type": "string"
+                "opt_in_rate": {
+                    "description": "OptedInCount / WhitelistedCount, 0 for an empty group",
+                    "type": "number"
                 },
-                "region": {
-                    "description": "Região ISO 3166-1 alpha-2",
+                "opted_in_count": {
+                    "description": "Whitelisted phones currently opted in",
+                    "type": "integer"
+                },
+                "recent_additions": {
+                    "description": "Phones added (or last updated) within the recent window",
+                    "type": "integer"
+                },
+                "recent_opt_ins": {
+                    "description": "Opt-ins recorded for the group's phones within the recent window",
+                    "type": "integer"
+                },
+                "recent_opt_outs": {
+                    "description": "Opt-outs recorded for the group's phones within the recent window",
+                    "type": "integer"
+                },
+                "recent_since": {
+                    "description": "Start of the recent window",
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Indica se o número é válido.",
-                    "type": "boolean"
+                "whitelisted_count": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.ServiceHealth": {
+        "models.BetaStatusResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "beta_whitelisted": {
+                    "type": "boolean"
+                },
+                "group_id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "boolean"
+                "group_name": {
+                    "type": "string"
                 },
-                "timestamp": {
+                "phone_number": {
                     "type": "string"
                 }
             }
         },
-        "handlers.SuccessResponse": {
+        "models.BetaWhitelistBulkRemoveRequest": {
             "type": "object",
+            "required": [
+                "phone_numbers"
+            ],
             "properties": {
-                "message": {
-                    "type": "string"
+                "phone_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.Accountant": {
+        "models.BetaWhitelistBulkRequest": {
             "type": "object",
+            "required": [
+                "group_id",
+                "phone_numbers"
+            ],
             "properties": {
-                "pf": {
-                    "$ref": "#/definitions/models.AccountantIndividual"
+                "group_id": {
+                    "type": "string"
                 },
-                "pj": {
-                    "$ref": "#/definitions/models.AccountantCorporate"
+                "phone_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.AccountantCorporate": {
+        "models.BetaWhitelistListResponse": {
             "type": "object",
             "properties": {
-                "classificacao_crc": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "pagination": {
+                    "$ref": "#/definitions/models.PaginationInfo"
                 },
-                "sequencial_crc": {
-                    "type": "string"
+                "total_count": {
+                    "type": "integer"
                 },
-                "tipo_crc": {
-                    "type": "string"
+                "whitelisted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BetaWhitelistResponse"
+                    }
                 }
             }
         },
-        "models.AccountantIndividual": {
+        "models.BetaWhitelistMoveRequest": {
             "type": "object",
+            "required": [
+                "from_group_id",
+                "phone_numbers",
+                "to_group_id"
+            ],
             "properties": {
-                "classificacao_crc": {
+                "from_group_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "phone_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "sequencial_crc": {
+                "to_group_id": {
                     "type": "string"
-                },
-                "tipo_crc": {
+                }
+            }
+        },
+        "models.BetaWhitelistRequest": {
+            "type": "object",
+            "required": [
+                "group_id"
+            ],
+            "properties": {
+                "group_id": {
                     "type": "string"
                 }
             }
         },
-        "models.AccreditedClinic": {
+        "models.BetaWhitelistResponse": {
             "type": "object",
             "properties": {
-                "celular": {
+                "added_at": {
                     "type": "string"
                 },
-                "email": {
+                "group_id": {
                     "type": "string"
                 },
-                "endereco": {
-                    "$ref": "#/definitions/models.ClinicAddress"
-                },
-                "nome": {
+                "group_name": {
                     "type": "string"
                 },
-                "telefone": {
+                "phone_number": {
                     "type": "string"
                 }
             }
         },
-        "models.AddCPFSecretariaRequest": {
+        "models.BindRequest": {
             "type": "object",
             "required": [
-                "cd_ua"
+                "channel",
+                "cpf"
             ],
             "properties": {
-                "cd_ua": {
+                "channel": {
+                    "type": "string"
+                },
+                "cpf": {
                     "type": "string"
                 }
             }
         },
-        "models.Aluno": {
+        "models.BindResponse": {
             "type": "object",
             "properties": {
-                "conceito": {
+                "cpf": {
                     "type": "string"
                 },
-                "frequencia": {
-                    "type": "number"
+                "message": {
+                    "type": "string"
                 },
-                "indicador": {
+                "opt_in": {
                     "type": "boolean"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "models.AssistenciaSocial": {
+        "models.BulkQuarantineRequest": {
             "type": "object",
+            "required": [
+                "phone_numbers",
+                "reason"
+            ],
             "properties": {
-                "cadunico": {
-                    "$ref": "#/definitions/models.CadUnico"
+                "duration_days": {
+                    "description": "DurationDays defaults to PHONE_QUARANTINE_TTL when omitted",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 1
                 },
-                "cras": {
-                    "$ref": "#/definitions/models.CRAS"
+                "phone_numbers": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "models.AvatarRequest": {
+        "models.BulkQuarantineResponse": {
             "type": "object",
-            "required": [
-                "name",
-                "url"
-            ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "failed": {
+                    "type": "integer"
                 },
-                "url": {
+                "quarantine_until": {
+                    "type": "string"
+                },
+                "quarantined": {
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BulkQuarantineResult"
+                    }
                 }
             }
         },
-        "models.AvatarResponse": {
+        "models.BulkQuarantineResult": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "error": {
                     "type": "string"
                 },
-                "id": {
+                "phone_number": {
                     "type": "string"
                 },
-                "is_active": {
+                "success": {
                     "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
                 }
             }
         },
-        "models.AvatarsListResponse": {
+        "models.CFAddressPreview": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.AvatarResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer"
+                "address_hash": {
+                    "type": "string"
                 },
-                "per_page": {
-                    "type": "integer"
+                "cf_data": {
+                    "$ref": "#/definitions/models.CFInfo"
                 },
-                "total": {
-                    "type": "integer"
+                "equipe_saude_data": {
+                    "$ref": "#/definitions/models.EquipeSaudeInfo"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "found": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.BetaGroupListResponse": {
+        "models.CFContactInfo": {
             "type": "object",
             "properties": {
-                "groups": {
+                "email": {
+                    "type": "string"
+                },
+                "redes_social": {
+                    "$ref": "#/definitions/models.CFSocial"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "telefones": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.BetaGroupResponse"
+                        "type": "string"
                     }
-                },
-                "pagination": {
-                    "$ref": "#/definitions/models.PaginationInfo"
-                },
-                "total_groups": {
-                    "type": "integer"
                 }
             }
         },
-        "models.BetaGroupRequest": {
+        "models.CFCoverageRegion": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "coverage_rate": {
+                    "description": "with_cf / total, between 0 and 1",
+                    "type": "number"
+                },
+                "no_cf_found": {
+                    "type": "integer"
+                },
+                "region": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "with_cf": {
+                    "type": "integer"
                 }
             }
         },
-        "models.BetaGroupResponse": {
+        "models.CFCoverageStats": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "generated_at": {
                     "type": "string"
                 },
-                "id": {
+                "group_by": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CFCoverageRegion"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
+                "total_no_cf_found": {
+                    "type": "integer"
+                },
+                "total_with_cf": {
+                    "type": "integer"
                 }
             }
         },
-        "models.BetaStatusResponse": {
+        "models.CFHorario": {
             "type": "object",
             "properties": {
-                "beta_whitelisted": {
-                    "type": "boolean"
-                },
-                "group_id": {
+                "abre": {
                     "type": "string"
                 },
-                "group_name": {
+                "dia": {
                     "type": "string"
                 },
-                "phone_number": {
+                "fecha": {
                     "type": "string"
                 }
             }
         },
-        "models.BetaWhitelistBulkRemoveRequest": {
+        "models.CFInfo": {
             "type": "object",
-            "required": [
-                "phone_numbers"
-            ],
             "properties": {
-                "phone_numbers": {
+                "aberto_ao_publico": {
+                    "type": "boolean"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "bairro": {
+                    "type": "string"
+                },
+                "complemento": {
+                    "type": "string"
+                },
+                "contato": {
+                    "$ref": "#/definitions/models.CFContactInfo"
+                },
+                "horario_funcionamento": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.CFHorario"
                     }
+                },
+                "id_equipamento": {
+                    "type": "string"
+                },
+                "logradouro": {
+                    "type": "string"
+                },
+                "nome_oficial": {
+                    "type": "string"
+                },
+                "nome_popular": {
+                    "type": "string"
+                },
+                "numero": {
+                    "type": "string"
+                },
+                "regiao_administrativa": {
+                    "type": "string"
+                },
+                "regiao_planejamento": {
+                    "type": "string"
+                },
+                "subprefeitura": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.BetaWhitelistBulkRequest": {
+        "models.CFLookupBatchStatus": {
             "type": "object",
-            "required": [
-                "group_id",
-                "phone_numbers"
-            ],
             "properties": {
-                "group_id": {
+                "bairro": {
                     "type": "string"
                 },
-                "phone_numbers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "batch_id": {
+                    "type": "string"
+                },
+                "completed": {
+                    "description": "lookups finished successfully",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enqueued": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "lookup attempts that failed (failed jobs are retried)",
+                    "type": "integer"
+                },
+                "municipio": {
+                    "type": "string"
+                },
+                "pending": {
+                    "description": "enqueued jobs not completed yet",
+                    "type": "integer"
                 }
             }
         },
-        "models.BetaWhitelistListResponse": {
+        "models.CFLookupBulkRequest": {
             "type": "object",
             "properties": {
-                "pagination": {
-                    "$ref": "#/definitions/models.PaginationInfo"
+                "bairro": {
+                    "type": "string",
+                    "example": "Copacabana"
                 },
-                "total_count": {
-                    "type": "integer"
+                "dry_run": {
+                    "description": "only count the matching citizens",
+                    "type": "boolean"
                 },
-                "whitelisted": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BetaWhitelistResponse"
-                    }
+                "municipio": {
+                    "type": "string",
+                    "example": "Rio de Janeiro"
                 }
             }
         },
-        "models.BetaWhitelistMoveRequest": {
+        "models.CFLookupBulkResponse": {
             "type": "object",
-            "required": [
-                "from_group_id",
-                "phone_numbers",
-                "to_group_id"
-            ],
             "properties": {
-                "from_group_id": {
+                "bairro": {
                     "type": "string"
                 },
-                "phone_numbers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "batch_id": {
+                    "description": "empty on dry run",
+                    "type": "string"
                 },
-                "to_group_id": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "enqueued": {
+                    "description": "cf_lookup jobs queued",
+                    "type": "integer"
+                },
+                "matched": {
+                    "description": "citizens in the region with a usable address",
+                    "type": "integer"
+                },
+                "municipio": {
                     "type": "string"
+                },
+                "skipped_no_address": {
+                    "description": "citizens in the region without a street to look up",
+                    "type": "integer"
                 }
             }
         },
-        "models.BetaWhitelistRequest": {
+        "models.CFSocial": {
             "type": "object",
-            "required": [
-                "group_id"
-            ],
             "properties": {
-                "group_id": {
+                "facebook": {
+                    "type": "string"
+                },
+                "instagram": {
+                    "type": "string"
+                },
+                "twitter": {
                     "type": "string"
                 }
             }
         },
-        "models.BetaWhitelistResponse": {
+        "models.CFTeamsBatchItem": {
             "type": "object",
             "properties": {
-                "added_at": {
-                    "type": "string"
+                "clinica_familia": {
+                    "$ref": "#/definitions/models.ClinicaFamilia"
                 },
-                "group_id": {
+                "cpf": {
                     "type": "string"
                 },
-                "group_name": {
+                "equipe_saude_familia": {
+                    "$ref": "#/definitions/models.EquipeSaudeFamilia"
+                },
+                "error": {
                     "type": "string"
                 },
-                "phone_number": {
+                "has_cf_data": {
+                    "type": "boolean"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.BindRequest": {
+        "models.CFTeamsBatchRequest": {
             "type": "object",
             "required": [
-                "channel",
-                "cpf"
+                "cpfs"
             ],
             "properties": {
-                "channel": {
-                    "type": "string"
-                },
-                "cpf": {
-                    "type": "string"
+                "cpfs": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.BindResponse": {
+        "models.CFTeamsBatchResponse": {
             "type": "object",
             "properties": {
-                "cpf": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
+                "found_count": {
+                    "type": "integer"
                 },
-                "opt_in": {
-                    "type": "boolean"
+                "missing_count": {
+                    "type": "integer"
                 },
-                "phone_number": {
-                    "type": "string"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CFTeamsBatchItem"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "total_count": {
+                    "type": "integer"
                 }
             }
         },
@@ -6471,77 +9902,165 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
-                "cpf": {
-                    "type": "string"
+                "cpf": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CPFSecretariaResponse": {
+            "type": "object",
+            "properties": {
+                "cd_ua": {
+                    "type": "string"
+                },
+                "cpf": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CRAS": {
+            "type": "object",
+            "properties": {
+                "endereco": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CadUnico": {
+            "type": "object",
+            "properties": {
+                "data_cadastro": {
+                    "type": "string"
+                },
+                "data_limite_cadastro_atual": {
+                    "type": "string"
+                },
+                "data_ultima_atualizacao": {
+                    "type": "string"
+                },
+                "indicador": {
+                    "type": "boolean"
+                },
+                "status_cadastral": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CitizenCompletenessResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Whether each field is filled in (or verified)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "score": {
+                    "description": "0-100",
+                    "type": "integer"
+                },
+                "weights": {
+                    "description": "Weight of each field in the score",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "models.CPFSecretariaResponse": {
+        "models.CitizenDerivedFields": {
             "type": "object",
             "properties": {
-                "cd_ua": {
+                "email_mascarado": {
                     "type": "string"
                 },
-                "cpf": {
-                    "type": "string"
-                },
-                "created_at": {
+                "endereco_formatado": {
                     "type": "string"
                 },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "idade": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "telefone_mascarado": {
                     "type": "string"
                 }
             }
         },
-        "models.CRAS": {
+        "models.CitizenEvent": {
             "type": "object",
             "properties": {
-                "endereco": {
+                "cpf": {
                     "type": "string"
                 },
-                "nome": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
                     "type": "string"
                 },
-                "telefone": {
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.CadUnico": {
+        "models.CitizenProfileResponse": {
             "type": "object",
             "properties": {
-                "data_cadastro": {
-                    "type": "string"
-                },
-                "data_limite_cadastro_atual": {
-                    "type": "string"
-                },
-                "data_ultima_atualizacao": {
-                    "type": "string"
+                "citizen": {
+                    "$ref": "#/definitions/models.CitizenResponse"
                 },
-                "indicador": {
-                    "type": "boolean"
+                "completeness": {
+                    "description": "Completeness is omitted when the data is served stale, since it needs the user config",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CitizenCompletenessResponse"
+                        }
+                    ]
                 },
-                "status_cadastral": {
-                    "type": "string"
+                "wallet": {
+                    "description": "Wallet is a CitizenWallet, holding only cpf and the requested sections when sections is given"
                 }
             }
         },
-        "models.Citizen": {
+        "models.CitizenResponse": {
             "type": "object",
             "properties": {
+                "_derived": {
+                    "description": "Server-computed values, only present when requested with include_derived=true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CitizenDerivedFields"
+                        }
+                    ]
+                },
                 "_id": {
                     "type": "string"
                 },
-                "assistencia_social": {
-                    "$ref": "#/definitions/models.AssistenciaSocial"
-                },
                 "cpf": {
                     "type": "string"
                 },
@@ -6549,17 +10068,6 @@ const docTemplate = `{
                     "description": "Self-declared, not stored in base collection",
                     "type": "string"
                 },
-                "documentos": {
-                    "description": "Wallet and internal fields",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Documentos"
-                        }
-                    ]
-                },
-                "educacao": {
-                    "$ref": "#/definitions/models.Educacao"
-                },
                 "email": {
                     "$ref": "#/definitions/models.Email"
                 },
@@ -6570,6 +10078,13 @@ const docTemplate = `{
                     "description": "Self-declared, not stored in base collection",
                     "type": "string"
                 },
+                "external_ids": {
+                    "description": "IDs of the citizen in other municipal systems, keyed by system name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "genero": {
                     "description": "Self-declared, not stored in base collection",
                     "type": "string"
@@ -6603,8 +10118,12 @@ const docTemplate = `{
                     "description": "Self-declared, not stored in base collection",
                     "type": "string"
                 },
-                "saude": {
-                    "$ref": "#/definitions/models.Saude"
+                "self_declared_status": {
+                    "description": "Staleness of each self-declared field present, keyed by field name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.SelfDeclaredFieldStatus"
+                    }
                 },
                 "sexo": {
                     "type": "string"
@@ -6620,6 +10139,15 @@ const docTemplate = `{
                 "assistencia_social": {
                     "$ref": "#/definitions/models.AssistenciaSocial"
                 },
+                "cf_lookup_pending": {
+                    "description": "CFLookupPending is set when the CF lookup was queued to run in background, so the client can\nfetch the wallet again later to get saude.clinica_familia",
+                    "type": "boolean"
+                },
+                "clinica_familia_status": {
+                    "description": "ClinicaFamiliaStatus explains why saude.clinica_familia is (or isn't) filled in",
+                    "type": "string",
+                    "example": "no_equipment"
+                },
                 "cpf": {
                     "type": "string"
                 },
@@ -6631,6 +10159,13 @@ const docTemplate = `{
                 },
                 "saude": {
                     "$ref": "#/definitions/models.Saude"
+                },
+                "warnings": {
+                    "description": "Warnings lists the sections that failed to load and are returned as null",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WalletWarning"
+                    }
                 }
             }
         },
@@ -6679,11 +10214,42 @@ const docTemplate = `{
                 "nome": {
                     "type": "string"
                 },
+                "stale": {
+                    "description": "true when MCP data is past CF_LOOKUP_MAX_AGE and being refreshed",
+                    "type": "boolean"
+                },
                 "telefone": {
                     "type": "string"
                 }
             }
         },
+        "models.CollectionFreshness": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "description": "Age in seconds per quantile of the dated documents",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "collection": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Why the collection couldn't be sampled",
+                    "type": "string"
+                },
+                "sampled": {
+                    "description": "Documents sampled",
+                    "type": "integer"
+                },
+                "undated": {
+                    "description": "Sampled documents without updated_at or created_at",
+                    "type": "integer"
+                }
+            }
+        },
         "models.CompanySize": {
             "type": "object",
             "properties": {
@@ -6695,6 +10261,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CreateEthnicityOptionRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "quilombola"
+                }
+            }
+        },
         "models.CreateNotificationCategoryRequest": {
             "type": "object",
             "required": [
@@ -6723,6 +10302,23 @@ const docTemplate = `{
                 }
             }
         },
+        "models.DataFreshnessReport": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionFreshness"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "sample_size": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.DepartmentListResponse": {
             "type": "object",
             "properties": {
@@ -6848,6 +10444,21 @@ const docTemplate = `{
                 }
             }
         },
+        "models.EmailVerificationValidateRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "token"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Endereco": {
             "type": "object",
             "properties": {
@@ -6976,6 +10587,47 @@ const docTemplate = `{
                 }
             }
         },
+        "models.EquipeSaudeInfo": {
+            "type": "object",
+            "properties": {
+                "aberto_ao_publico": {
+                    "type": "boolean"
+                },
+                "ativo": {
+                    "type": "boolean"
+                },
+                "contato": {
+                    "$ref": "#/definitions/models.CFContactInfo"
+                },
+                "enfermeiros": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id_equipe": {
+                    "type": "string"
+                },
+                "medicos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "nome_oficial": {
+                    "type": "string"
+                },
+                "nome_popular": {
+                    "type": "string"
+                },
+                "regiao_planejamento": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Escola": {
             "type": "object",
             "properties": {
@@ -6988,14 +10640,126 @@ const docTemplate = `{
                 "horario_funcionamento": {
                     "type": "string"
                 },
-                "nome": {
-                    "type": "string"
+                "nome": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                },
+                "whatsapp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EthnicityOption": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "order": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeatureFlag": {
+            "type": "object",
+            "properties": {
+                "beta_group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cpfs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "rollout_percentage": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeatureFlagEvaluationResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "flags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                }
+            }
+        },
+        "models.FeatureFlagListResponse": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeatureFlag"
+                    }
+                }
+            }
+        },
+        "models.FeatureFlagRequest": {
+            "type": "object",
+            "properties": {
+                "beta_group_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cpfs": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Integração com Clínica da Família"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "telefone": {
-                    "type": "string"
+                "key": {
+                    "type": "string",
+                    "example": "cf_integration"
                 },
-                "whatsapp": {
-                    "type": "string"
+                "rollout_percentage": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 10
                 }
             }
         },
@@ -7258,6 +11022,40 @@ const docTemplate = `{
                 }
             }
         },
+        "models.MergeRule": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "description": "see MergeCondition* constants",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "indicator": {
+                    "description": "see MergeIndicator* constants",
+                    "type": "string"
+                },
+                "requires_indicator": {
+                    "description": "the self-declared indicador must be true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MergeRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MergeRule"
+                    }
+                }
+            }
+        },
         "models.Nascimento": {
             "type": "object",
             "properties": {
@@ -7341,6 +11139,54 @@ const docTemplate = `{
                 }
             }
         },
+        "models.NotificationTargetResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "found": {
+                    "type": "boolean"
+                },
+                "phone_number": {
+                    "description": "Storage format (DDI + DDD + number)",
+                    "type": "string"
+                },
+                "preferred_channel": {
+                    "description": "PreferredChannel is the channel the citizen prefers to be notified through, when chosen",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Why no phone was found",
+                    "type": "string"
+                },
+                "skipped": {
+                    "description": "Verified phones passed over, in fallback order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationTargetSkip"
+                    }
+                },
+                "source": {
+                    "description": "\"self_declared\" or \"base\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationTargetSkip": {
+            "type": "object",
+            "properties": {
+                "phone_number": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Obito": {
             "type": "object",
             "properties": {
@@ -7352,6 +11198,68 @@ const docTemplate = `{
                 }
             }
         },
+        "models.OptInHistory": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "opt_in, opt_out, rejected, category_update, phone_demoted",
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "cpf": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "free-text note left with an opt_out",
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "boolean"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "only for opt_out",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "global, category",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "validation_result": {
+                    "$ref": "#/definitions/models.ValidationResult"
+                }
+            }
+        },
+        "models.OptInHistoryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OptInHistory"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.PaginationInfo"
+                }
+            }
+        },
         "models.OptInRequest": {
             "type": "object",
             "required": [
@@ -7461,7 +11369,12 @@ const docTemplate = `{
                 "pagination": {
                     "type": "object",
                     "properties": {
+                        "next_cursor": {
+                            "description": "Cursor of the next page in cursor mode; empty on the last page",
+                            "type": "string"
+                        },
                         "page": {
+                            "description": "Not set in cursor mode",
                             "type": "integer"
                         },
                         "per_page": {
@@ -7723,22 +11636,189 @@ const docTemplate = `{
                 }
             }
         },
-        "models.PhoneNotificationPreferencesResponse": {
+        "models.PhoneDeliveryReceiptRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "error": {
+                    "description": "Provider error description, for failures",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "delivered",
+                        "failed"
+                    ]
+                }
+            }
+        },
+        "models.PhoneDeliveryReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "demoted": {
+                    "description": "Demoted is true when this receipt demoted the citizen's verified self-declared phone back\nto pending verification",
+                    "type": "boolean"
+                },
+                "failures": {
+                    "description": "Failures is the number of delivery failures counted in the current window",
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PhoneNotificationPreferencesResponse": {
+            "type": "object",
+            "properties": {
+                "category_opt_ins": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "opt_in": {
+                    "type": "boolean"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PhoneReconcileDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "cached_value": {},
+                "field": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is the Redis key holding the stale value",
+                    "type": "string"
+                },
+                "mongo_value": {}
+            }
+        },
+        "models.PhoneReconcileResponse": {
+            "type": "object",
+            "properties": {
+                "cached_keys": {
+                    "description": "CachedKeys lists the Redis keys currently holding state for the phone",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "consistent": {
+                    "type": "boolean"
+                },
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PhoneReconcileDiscrepancy"
+                    }
+                },
+                "found_in_mongo": {
+                    "type": "boolean"
+                },
+                "pending_write": {
+                    "description": "PendingWrite is true when the write buffer holds a change not yet synced to MongoDB;\nit is reported but never compared or repaired",
+                    "type": "boolean"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "repaired": {
+                    "type": "boolean"
+                },
+                "repaired_keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PhoneStatusDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "beta_group_id": {
+                    "type": "string"
+                },
+                "beta_group_name": {
+                    "type": "string"
+                },
+                "beta_whitelisted": {
+                    "type": "boolean"
+                },
+                "bound_cpf_count": {
+                    "description": "BoundCPFCount is the number of CPFs with an active mapping for the phone",
+                    "type": "integer"
+                },
+                "category_opt_ins": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "cpf": {
+                    "type": "string"
+                },
+                "found": {
+                    "type": "boolean"
+                },
+                "last_opt_in_action": {
+                    "$ref": "#/definitions/models.PhoneStatusOptInAction"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opt_in": {
+                    "type": "boolean"
+                },
+                "opted_out": {
+                    "type": "boolean"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "quarantine_reason": {
+                    "type": "string"
+                },
+                "quarantine_until": {
+                    "type": "string"
+                },
+                "quarantined": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.PhoneStatusOptInAction": {
             "type": "object",
             "properties": {
-                "category_opt_ins": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
-                },
-                "opt_in": {
-                    "type": "boolean"
+                "action": {
+                    "type": "string"
                 },
-                "phone_number": {
+                "channel": {
                     "type": "string"
                 },
-                "updated_at": {
+                "timestamp": {
                     "type": "string"
                 }
             }
@@ -7810,6 +11890,25 @@ const docTemplate = `{
                 }
             }
         },
+        "models.PreferredChannelRequest": {
+            "type": "object",
+            "required": [
+                "preferred_channel"
+            ],
+            "properties": {
+                "preferred_channel": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PreferredChannelResponse": {
+            "type": "object",
+            "properties": {
+                "preferred_channel": {
+                    "type": "string"
+                }
+            }
+        },
         "models.ProfissionalSaude": {
             "type": "object",
             "properties": {
@@ -7841,6 +11940,13 @@ const docTemplate = `{
         "models.QuarantineStats": {
             "type": "object",
             "properties": {
+                "active_by_reason": {
+                    "description": "ActiveByReason counts active quarantines per reason (QuarantineReasonUnspecified when none was given)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
                 "active_quarantines": {
                     "type": "integer"
                 },
@@ -7887,8 +11993,14 @@ const docTemplate = `{
                 "phone_number": {
                     "type": "string"
                 },
+                "quarantine_reason": {
+                    "type": "string"
+                },
                 "quarantine_until": {
                     "type": "string"
+                },
+                "quarantined_at": {
+                    "type": "string"
                 }
             }
         },
@@ -8012,6 +12124,9 @@ const docTemplate = `{
                 },
                 "tipo_logradouro": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8023,6 +12138,9 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8034,6 +12152,9 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8045,6 +12166,20 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SelfDeclaredFieldStatus": {
+            "type": "object",
+            "properties": {
+                "is_stale": {
+                    "type": "boolean"
+                },
+                "last_updated": {
+                    "type": "string"
                 }
             }
         },
@@ -8056,6 +12191,9 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8067,6 +12205,52 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SelfDeclaredPatchFieldResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SelfDeclaredPatchInput": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "$ref": "#/definitions/models.SelfDeclaredEmailInput"
+                },
+                "endereco": {
+                    "$ref": "#/definitions/models.SelfDeclaredAddressInput"
+                },
+                "raca": {
+                    "$ref": "#/definitions/models.SelfDeclaredRacaInput"
+                },
+                "telefone": {
+                    "$ref": "#/definitions/models.SelfDeclaredPhoneInput"
+                },
+                "version": {
+                    "description": "Version is the self-declared version the update is based on (alternative to If-Match)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SelfDeclaredPatchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.SelfDeclaredPatchFieldResult"
+                    }
                 }
             }
         },
@@ -8086,6 +12270,9 @@ const docTemplate = `{
                 },
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8097,6 +12284,9 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8108,6 +12298,9 @@ const docTemplate = `{
             "properties": {
                 "valor": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -8150,6 +12343,12 @@ const docTemplate = `{
                 },
                 "principal": {
                     "$ref": "#/definitions/models.TelefonePrincipal"
+                },
+                "verificados": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TelefoneVerificado"
+                    }
                 }
             }
         },
@@ -8196,6 +12395,29 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TelefoneVerificado": {
+            "type": "object",
+            "properties": {
+                "ddd": {
+                    "type": "string"
+                },
+                "ddi": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "boolean"
+                },
+                "valor": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateCategoryPreferenceRequest": {
             "type": "object",
             "required": [
@@ -8287,14 +12509,30 @@ const docTemplate = `{
                         "type": "boolean"
                     }
                 },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
                 "opt_in": {
                     "type": "boolean"
+                },
+                "reason": {
+                    "description": "Reason and Note are only accepted when opting out; Reason must be a code from /config/opt-out-reasons",
+                    "type": "string"
                 }
             }
         },
         "models.UserConfigResponse": {
             "type": "object",
             "properties": {
+                "avatar": {
+                    "description": "Avatar is the default avatar assigned when completing the first login, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AvatarResponse"
+                        }
+                    ]
+                },
                 "firstlogin": {
                     "type": "boolean"
                 }
@@ -8344,6 +12582,155 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "models.VerificationExpireRequest": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string",
+                    "example": "12345678901"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "5521987654321"
+                }
+            }
+        },
+        "models.VerificationExpireResponse": {
+            "type": "object",
+            "properties": {
+                "email_verifications_expired": {
+                    "type": "integer"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "phone_verifications_expired": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WalletWarning": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "section unavailable"
+                },
+                "section": {
+                    "type": "string",
+                    "example": "educacao"
+                }
+            }
+        },
+        "services.AuditLogListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.AuditLog"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.PaginationInfo"
+                }
+            }
+        },
+        "services.SyncFlushResult": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "flushed": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "pending": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total_failed": {
+                    "type": "integer"
+                },
+                "total_flushed": {
+                    "type": "integer"
+                },
+                "total_pending": {
+                    "type": "integer"
+                }
+            }
+        },
+        "utils.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "cpf": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "new_value": {},
+                "old_value": {},
+                "request_id": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.ValidationError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
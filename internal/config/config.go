@@ -2,27 +2,35 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // Config holds all configuration values
 type Config struct {
 	// Server configuration
-	Port        int    `json:"port"`
-	Environment string `json:"environment"`
+	Port               int           `json:"port"`
+	Environment        string        `json:"environment"`
+	ShutdownDrainDelay time.Duration `json:"shutdown_drain_delay"` // How long /health/ready answers 503 before the server stops accepting connections
 
 	// MongoDB configuration
 	MongoURI      string `json:"mongo_uri"`
 	MongoDatabase string `json:"mongo_database"`
 
+	MongoReadPreferences map[string]string `json:"mongo_read_preferences"` // Per-collection read preference overrides (collection -> mode)
+
 	// Redis configuration
-	RedisURI      string        `json:"redis_uri"`
-	RedisPassword string        `json:"redis_password"`
-	RedisDB       int           `json:"redis_db"`
-	RedisTTL      time.Duration `json:"redis_ttl"`
+	RedisURI      string         `json:"redis_uri"`
+	RedisPassword string         `json:"redis_password"`
+	RedisDB       int            `json:"redis_db"`
+	RedisTTL      time.Duration  `json:"redis_ttl"`
+	CacheTTL      CacheTTLConfig `json:"cache_ttl"` // Per-namespace overrides of RedisTTL
 
 	// Redis Cluster configuration (for production clustered setup)
 	RedisClusterEnabled  bool     `json:"redis_cluster_enabled"`
@@ -41,6 +49,7 @@ type Config struct {
 	CitizenCollection              string `json:"mongo_citizen_collection"`
 	SelfDeclaredCollection         string `json:"mongo_self_declared_collection"`
 	PhoneVerificationCollection    string `json:"mongo_phone_verification_collection"`
+	EmailVerificationCollection    string `json:"mongo_email_verification_collection"`
 	UserConfigCollection           string `json:"mongo_user_config_collection"`
 	MaintenanceRequestCollection   string `json:"mongo_maintenance_request_collection"`
 	PhoneMappingCollection         string `json:"mongo_phone_mapping_collection"`
@@ -50,6 +59,7 @@ type Config struct {
 	BairroCollection               string `json:"mongo_bairro_collection"`
 	LogradouroCollection           string `json:"mongo_logradouro_collection"`
 	AvatarsCollection              string `json:"mongo_avatars_collection"`
+	AvatarImagesCollection         string `json:"mongo_avatar_images_collection"`
 	LegalEntityCollection          string `json:"mongo_legal_entity_collection"`
 	PetCollection                  string `json:"mongo_pet_collection"`
 	PetsSelfRegisteredCollection   string `json:"mongo_pets_self_registered_collection"`
@@ -58,23 +68,111 @@ type Config struct {
 	NotificationCategoryCollection string `json:"mongo_notification_category_collection"`
 	CNAECollection                 string `json:"mongo_cnae_collection"`
 	CPFSecretariaCollection        string `json:"mongo_cpf_secretaria_collection"`
+	EthnicityOptionCollection      string `json:"mongo_ethnicity_option_collection"`
+	FeatureFlagCollection          string `json:"mongo_feature_flag_collection"`
 
 	// Phone verification configuration
-	PhoneVerificationTTL time.Duration `json:"phone_verification_ttl"`
-	PhoneQuarantineTTL   time.Duration `json:"phone_quarantine_ttl"` // 6 months
-	BetaStatusCacheTTL   time.Duration `json:"beta_status_cache_ttl"`
+	PhoneVerificationTTL              time.Duration `json:"phone_verification_ttl"`
+	PhoneVerificationCodeLength       int           `json:"phone_verification_code_length"`       // Characters in phone verification codes (4-12)
+	PhoneVerificationCodeAlphabet     string        `json:"phone_verification_code_alphabet"`     // "numeric" or "alphanumeric" phone verification codes
+	PhoneVerificationDuplicateRetries int           `json:"phone_verification_duplicate_retries"` // Retries after removing a stale record on duplicate key (0 disables)
+	PhoneVerificationMaxFailures      int           `json:"phone_verification_max_failures"`      // Failed code validations per CPF before it is locked out (0 disables)
+	PhoneVerificationLockoutWindow    time.Duration `json:"phone_verification_lockout_window"`    // Window in which failures are counted; also how long the lockout lasts
+	PhoneDeliveryFailureThreshold     int           `json:"phone_delivery_failure_threshold"`     // Failed delivery receipts before a verified self-declared phone is demoted (0 disables)
+	PhoneDeliveryFailureWindow        time.Duration `json:"phone_delivery_failure_window"`        // Window in which delivery failures are counted
+	PhoneQuarantineTTL                time.Duration `json:"phone_quarantine_ttl"`                 // 6 months
+	PhoneQuarantineSweepInterval      time.Duration `json:"phone_quarantine_sweep_interval"`      // Interval for releasing expired quarantines in the sync service (0 disables)
+	PhoneQuarantineSweepBatchSize     int           `json:"phone_quarantine_sweep_batch_size"`
+	PhoneStatusDetailsCacheTTL        time.Duration `json:"phone_status_details_cache_ttl"` // How long the detailed phone status served to service tokens is cached
+	BetaStatusCacheTTL                time.Duration `json:"beta_status_cache_ttl"`
+	BetaGroupStatsCacheTTL            time.Duration `json:"beta_group_stats_cache_ttl"`     // How long computed beta group statistics are cached
+	BetaGroupStatsRecentWindow        time.Duration `json:"beta_group_stats_recent_window"` // Window of the recent additions and opt-in activity in beta group statistics
+
+	// Public validation endpoints configuration
+	CPFValidationRateLimit  int           `json:"cpf_validation_rate_limit"`  // POST /validate/cpf requests per client IP within the window (0 disables)
+	CPFValidationRateWindow time.Duration `json:"cpf_validation_rate_window"` // Window in which CPF validation requests are counted
+
+	// Email verification configuration
+	EmailVerificationEnabled        bool          `json:"email_verification_enabled"` // Self-declared emails are only stored once verified
+	EmailVerificationTTL            time.Duration `json:"email_verification_ttl"`
+	EmailVerificationChannel        string        `json:"email_verification_channel"` // "webhook" or "log" (development only)
+	EmailVerificationWebhookURL     string        `json:"email_verification_webhook_url"`
+	EmailVerificationWebhookSecret  string        `json:"-"` // HMAC-SHA256 signing key
+	EmailVerificationWebhookTimeout time.Duration `json:"email_verification_webhook_timeout"`
+	EmailVerificationLinkURL        string        `json:"email_verification_link_url"`     // Public URL of GET /v1/verify/email; empty disables magic links
+	EmailVerificationLinkSecret     string        `json:"-"`                               // HMAC-SHA256 key signing magic link tokens
+	EmailVerificationRedirectURL    string        `json:"email_verification_redirect_url"` // App deep-link opened after a magic link is used
 
 	// Self-declared data configuration
 	SelfDeclaredOutdatedThreshold time.Duration `json:"self_declared_outdated_threshold"` // Time after which self-declared data is considered outdated (default: 180 days)
+	ExhibitionNameMinLength       int           `json:"exhibition_name_min_length"`       // Minimum exhibition name length, in characters
+	ExhibitionNameBlocklist       []string      `json:"-"`                                // Offensive terms rejected in exhibition names (whole words, case and accent insensitive)
 
 	// Address building configuration
 	AddressCacheTTL time.Duration `json:"address_cache_ttl"`
 
 	// Avatar configuration
-	AvatarCacheTTL time.Duration `json:"avatar_cache_ttl"`
+	AvatarCacheTTL       time.Duration `json:"avatar_cache_ttl"`
+	DefaultAvatarMode    string        `json:"default_avatar_mode"`      // Default avatar assigned on first login: "off", "random" or "deterministic" (by CPF)
+	AvatarListMaxPerPage int           `json:"avatar_list_max_per_page"` // Largest per_page accepted by the public avatar listing
+
+	AvatarStorageBackend    string `json:"avatar_storage_backend"` // Where uploaded avatar images are stored: "mongo" or "s3"
+	AvatarPublicBaseURL     string `json:"avatar_public_base_url"` // Base of avatar image URLs (API base for mongo, CDN for s3)
+	AvatarS3Endpoint        string `json:"avatar_s3_endpoint"`     // S3-compatible endpoint, e.g. https://storage.googleapis.com
+	AvatarS3Bucket          string `json:"avatar_s3_bucket"`       // Bucket holding the avatar images
+	AvatarS3Region          string `json:"avatar_s3_region"`       // Region used to sign requests
+	AvatarS3AccessKeyID     string `json:"-"`                      // HMAC access key
+	AvatarS3SecretAccessKey string `json:"-"`                      // HMAC secret
 
 	// Notification category configuration
 	NotificationCategoryCacheTTL time.Duration `json:"notification_category_cache_ttl"`
+	MaxCategoryOptIns            int           `json:"max_category_opt_ins"` // Upper bound on category opt-in keys per user/phone
+
+	// Ethnicity options configuration
+	EthnicityOptionsCacheTTL time.Duration `json:"ethnicity_options_cache_ttl"` // TTL of the Redis copy of the admin-editable ethnicity options
+
+	// Feature flag configuration
+	FeatureFlagCacheTTL time.Duration `json:"feature_flag_cache_ttl"` // TTL of cached flag definitions and per-CPF evaluations
+
+	// Legal entity configuration
+	LegalEntityCacheTTL time.Duration `json:"legal_entity_cache_ttl"`
+
+	// External ID configuration
+	CitizenExternalIDSystem string        `json:"citizen_external_id_system"` // System whose citizen external_ids entry is indexed and can be looked up ("" disables lookups)
+	ExternalIDCacheTTL      time.Duration `json:"external_id_cache_ttl"`      // TTL of the cached external ID to CPF mapping
+
+	// User config configuration
+	UserConfigWriteMode  string `json:"user_config_write_mode"`  // "field" (targeted $set per field) or "document" (whole document via write buffer)
+	OptOutReasonRequired bool   `json:"opt_out_reason_required"` // Reject opt-outs in the citizen config that don't carry a reason
+
+	// Validation rules configuration
+	ValidationRules map[string]string `json:"validation_rules"` // Mode ("off", "shadow" or "enforce") of each stricter validation rule
+
+	// Degraded reads configuration
+	MongoDegradedReadsEnabled bool `json:"mongo_degraded_reads_enabled"` // GET handlers serve the cached copy when MongoDB is unavailable
+
+	// Request validation configuration
+	OpenAPIValidationGroups []string `json:"openapi_validation_groups"` // Route groups whose requests are validated against the OpenAPI spec
+
+	// Idempotency configuration
+	IdempotencyKeyTTL time.Duration `json:"idempotency_key_ttl"` // How long the response of a request with an Idempotency-Key is replayed
+
+	// Event stream (SSE) configuration
+	EventStreamMaxConnections       int           `json:"event_stream_max_connections"`         // Max concurrent event streams per instance (0 = unlimited)
+	EventStreamMaxConnectionsPerCPF int           `json:"event_stream_max_connections_per_cpf"` // Max concurrent event streams per CPF (0 = unlimited)
+	EventStreamIdleTimeout          time.Duration `json:"event_stream_idle_timeout"`            // Streams without events for this long are closed (0 disables)
+
+	// Maintenance request pagination configuration
+	MaintenanceRequestMaxPage int `json:"maintenance_request_max_page"` // Deepest page served in page mode; deeper pages must use cursor mode (0 = unlimited)
+
+	// Profile completeness configuration
+	CompletenessWeights map[string]int `json:"completeness_weights"` // Weight of each field in the completeness score
+
+	// Notification target configuration
+	NotificationPhoneFallback []string `json:"notification_phone_fallback"` // Verified phone sources tried in order to pick the notification phone
+
+	// Self-declared write durability configuration
+	CriticalSelfDeclaredFields []string `json:"critical_self_declared_fields"` // Self-declared fields written synchronously to MongoDB with majority write concern
 
 	// MCP Server configuration
 	MCPServerURL            string        `json:"mcp_server_url"`
@@ -84,7 +182,28 @@ type Config struct {
 	CFLookupCacheTTL        time.Duration `json:"cf_lookup_cache_ttl"`
 	CFLookupRateLimit       time.Duration `json:"cf_lookup_rate_limit"`
 	CFLookupGlobalRateLimit int           `json:"cf_lookup_global_rate_limit"`
-	CFLookupSyncTimeout     time.Duration `json:"cf_lookup_sync_timeout"`
+	CFLookupSyncTimeout     time.Duration `json:"cf_lookup_sync_timeout"`     // Deadline of the synchronous lookup made by the wallet before falling back to a background job
+	CFLookupSyncEnabled     bool          `json:"cf_lookup_sync_enabled"`     // Whether the wallet looks CF data up synchronously (false always queues the background job)
+	CFLookupMaxAge          time.Duration `json:"cf_lookup_max_age"`          // Age after which cached CF data is served as stale and refreshed (0 disables)
+	CFLookupNoEquipmentTTL  time.Duration `json:"cf_lookup_no_equipment_ttl"` // How long a "no equipment found" result is remembered before the address is looked up again
+	CFCoverageStatsCacheTTL time.Duration `json:"cf_coverage_stats_cache_ttl"`
+	CFCircuitFailureRatio   float64       `json:"cf_circuit_failure_ratio"` // Ratio of failed synchronous MCP lookups in a window that opens the CF circuit breaker
+	CFCircuitMinRequests    int           `json:"cf_circuit_min_requests"`  // Synchronous MCP lookups needed in a window before the failure ratio is evaluated
+	CFCircuitOpenTimeout    time.Duration `json:"cf_circuit_open_timeout"`  // How long the CF circuit stays open before probing the MCP server again
+
+	// Address change webhook configuration
+	AddressWebhookEnabled    bool          `json:"address_webhook_enabled"`
+	AddressWebhookURL        string        `json:"address_webhook_url"`
+	AddressWebhookSecret     string        `json:"-"`                           // HMAC-SHA256 signing key
+	AddressWebhookTimeout    time.Duration `json:"address_webhook_timeout"`     // Per-delivery HTTP timeout
+	AddressWebhookMaxRetries int           `json:"address_webhook_max_retries"` // Delivery attempts before moving to the DLQ
+
+	// Phone verified webhook configuration
+	PhoneVerifiedWebhookEnabled    bool          `json:"phone_verified_webhook_enabled"`
+	PhoneVerifiedWebhookURL        string        `json:"phone_verified_webhook_url"`
+	PhoneVerifiedWebhookSecret     string        `json:"-"`                                  // HMAC-SHA256 signing key
+	PhoneVerifiedWebhookTimeout    time.Duration `json:"phone_verified_webhook_timeout"`     // Per-delivery HTTP timeout
+	PhoneVerifiedWebhookMaxRetries int           `json:"phone_verified_webhook_max_retries"` // Delivery attempts before moving to the DLQ
 
 	// WhatsApp configuration
 	WhatsAppEnabled      bool   `json:"whatsapp_enabled"`
@@ -99,33 +218,258 @@ type Config struct {
 	TracingEnabled  bool   `json:"tracing_enabled"`
 	TracingEndpoint string `json:"tracing_endpoint"`
 
+	// Logging configuration
+	LogPIIMasking bool `json:"log_pii_masking"` // Mask CPF and phone fields in logs (defaults to on outside development)
+
 	// Audit logging configuration
 	AuditLogsEnabled bool `json:"audit_logs_enabled"`
 
 	// Audit worker configuration
-	AuditWorkerCount int `json:"audit_worker_count"`
-	AuditBufferSize  int `json:"audit_buffer_size"`
+	AuditWorkerCount       int           `json:"audit_worker_count"`
+	AuditBufferSize        int           `json:"audit_buffer_size"`
+	AuditCoalescingEnabled bool          `json:"audit_coalescing_enabled"` // Batch audit logs into a single BulkWrite; when false each log is written on its own
+	AuditBatchSize         int           `json:"audit_batch_size"`         // Logs per worker that trigger a flush
+	AuditFlushInterval     time.Duration `json:"audit_flush_interval"`     // Maximum time a log waits in a partial batch
+	AuditOverflowPolicy    string        `json:"audit_overflow_policy"`    // What happens to audit logs when the buffer is full: "sync", "block", "drop" or "spill-to-redis"
 
 	// Verification queue configuration
 	VerificationWorkerCount int `json:"verification_worker_count"`
 	VerificationQueueSize   int `json:"verification_queue_size"`
 
 	// Database worker configuration
-	DBWorkerCount int `json:"db_worker_count"`
-	DBBatchSize   int `json:"db_batch_size"`
+	DBWorkerCount                  int            `json:"db_worker_count"`
+	DBBatchSize                    int            `json:"db_batch_size"`
+	SyncFlushTimeout               time.Duration  `json:"sync_flush_timeout"`                 // Max time spent draining sync queues on shutdown or admin flush
+	SyncWorkerPools                map[string]int `json:"sync_worker_pools"`                  // Dedicated workers per sync queue; the other queues share DB_WORKER_COUNT workers
+	WriteBufferReconcileInterval   time.Duration  `json:"write_buffer_reconcile_interval"`    // Interval for reconciling stale self-declared write buffers against MongoDB (0 disables)
+	WriteBufferReconcileStaleAfter time.Duration  `json:"write_buffer_reconcile_stale_after"` // Age after which a write buffer entry is expected to have reached MongoDB
+	WriteBufferReconcileBatchSize  int            `json:"write_buffer_reconcile_batch_size"`  // Max write buffer keys checked per data type in each run
 
 	// Authorization configuration
 	AdminGroup            string   `json:"admin_group"`
 	TrustedServiceClients []string `json:"trusted_service_clients"`
+	MaskedResponseScopes  []string `json:"masked_response_scopes"`
+	// Token scopes allowed to export quarantined phones with unmasked CPFs
+	QuarantineExportUnmaskedScopes []string `json:"quarantine_export_unmasked_scopes"`
+	// Token scopes allowed to export the beta whitelist with unmasked phone numbers
+	BetaWhitelistExportUnmaskedScopes []string `json:"beta_whitelist_export_unmasked_scopes"`
+
+	// Service token configuration (machine-to-machine callers authorized by scope)
+	ServiceTokenIssuer       string        `json:"service_token_issuer"`         // Issuer (iss) of service tokens; empty disables service tokens
+	ServiceTokenJWKSURL      string        `json:"service_token_jwks_url"`       // JWKS endpoint with the keys that sign service tokens
+	ServiceTokenAudience     string        `json:"service_token_audience"`       // Audience service tokens must carry (empty skips the check)
+	ServiceTokenJWKSCacheTTL time.Duration `json:"service_token_jwks_cache_ttl"` // How long fetched signing keys are reused before refetching
 
 	// Index maintenance configuration
 	IndexMaintenanceInterval time.Duration `json:"index_maintenance_interval"`
+
+	// Data freshness report configuration
+	DataFreshnessInterval    time.Duration `json:"data_freshness_interval"`    // Interval for recomputing the per-collection data age report (0 disables)
+	DataFreshnessCollections []string      `json:"data_freshness_collections"` // Collections whose updated_at/created_at ages are reported
+	DataFreshnessSampleSize  int           `json:"data_freshness_sample_size"` // Documents sampled per collection to estimate the age distribution
+
+	// Static lists (ethnicity options, channels, opt-out reasons) refresh interval (0 disables)
+	StaticListsRefreshInterval time.Duration `json:"static_lists_refresh_interval"`
+
+	// Request ID configuration
+	TrustInboundRequestID bool `json:"trust_inbound_request_id"`
+
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For / X-Real-IP headers are trusted to resolve the
+	// client IP (empty trusts none, so the client IP is the connection's remote address)
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// CORS configuration
+	CORSAllowedOrigins   []string      `json:"cors_allowed_origins"`   // Origins allowed to call the API from a browser (empty allows none, except in development)
+	CORSAllowedMethods   []string      `json:"cors_allowed_methods"`   // Methods allowed in cross-origin requests
+	CORSAllowedHeaders   []string      `json:"cors_allowed_headers"`   // Request headers allowed in cross-origin requests
+	CORSAllowCredentials bool          `json:"cors_allow_credentials"` // Whether cross-origin requests may carry credentials (cookies, Authorization)
+	CORSMaxAge           time.Duration `json:"cors_max_age"`           // How long browsers may cache a preflight response
+
+	// Response compression configuration
+	CompressionEnabled       bool     `json:"compression_enabled"`        // Whether responses are compressed when the client accepts it
+	CompressionMinSize       int      `json:"compression_min_size"`       // Smallest response body, in bytes, worth compressing
+	CompressionGzipLevel     int      `json:"compression_gzip_level"`     // gzip level (1 fastest - 9 smallest)
+	CompressionBrotliLevel   int      `json:"compression_brotli_level"`   // Brotli quality (0 fastest - 11 smallest)
+	CompressionExcludedPaths []string `json:"compression_excluded_paths"` // Paths never compressed (e.g. the metrics endpoints)
+
+	// Request body size limits, in bytes (0 disables the limit)
+	MaxRequestBodySize      int64 `json:"max_request_body_size"`       // Default limit applied to every route
+	MaxAvatarBodySize       int64 `json:"max_avatar_body_size"`        // Limit for avatar create/update routes
+	MaxSelfDeclaredBodySize int64 `json:"max_self_declared_body_size"` // Limit for self-declared update routes
+
+	// Outbound HTTP client configuration (MCP, webhooks, WhatsApp)
+	OutboundHTTPTimeout               time.Duration `json:"outbound_http_timeout"`                 // Overall timeout of an outbound request, including reading the body
+	OutboundHTTPDialTimeout           time.Duration `json:"outbound_http_dial_timeout"`            // Timeout for establishing a TCP connection
+	OutboundHTTPTLSHandshakeTimeout   time.Duration `json:"outbound_http_tls_handshake_timeout"`   // Timeout for the TLS handshake
+	OutboundHTTPResponseHeaderTimeout time.Duration `json:"outbound_http_response_header_timeout"` // Time to wait for the response headers after sending the request
+	OutboundHTTPIdleConnTimeout       time.Duration `json:"outbound_http_idle_conn_timeout"`       // How long an idle keep-alive connection stays in the pool
+	OutboundHTTPMaxIdleConns          int           `json:"outbound_http_max_idle_conns"`          // Idle connections kept across all hosts
+	OutboundHTTPMaxIdleConnsPerHost   int           `json:"outbound_http_max_idle_conns_per_host"` // Idle connections kept per host
+	OutboundHTTPMaxConnsPerHost       int           `json:"outbound_http_max_conns_per_host"`      // Connections per host, active or idle (0 for no limit)
 }
 
 var (
 	AppConfig *Config
 )
 
+// User config write modes
+const (
+	// UserConfigWriteModeField writes only the changed fields with targeted $set upserts, so concurrent
+	// updates to different fields (e.g. first login and opt-in during onboarding) both persist
+	UserConfigWriteModeField = "field"
+	// UserConfigWriteModeDocument writes the whole document through the Redis write buffer (last write wins)
+	UserConfigWriteModeDocument = "document"
+)
+
+// Phone verification code alphabets for PHONE_VERIFICATION_CODE_ALPHABET
+const (
+	// VerificationCodeAlphabetNumeric generates digit-only codes
+	VerificationCodeAlphabetNumeric = "numeric"
+	// VerificationCodeAlphabetAlphanumeric generates codes of digits and uppercase letters
+	VerificationCodeAlphabetAlphanumeric = "alphanumeric"
+)
+
+// Bounds of PHONE_VERIFICATION_CODE_LENGTH
+const (
+	MinVerificationCodeLength = 4
+	MaxVerificationCodeLength = 12
+)
+
+// MaxExhibitionNameLength is the maximum exhibition name length, in characters
+const MaxExhibitionNameLength = 255
+
+// Audit overflow policies for AUDIT_OVERFLOW_POLICY
+const (
+	// AuditOverflowPolicySync writes the log synchronously without waiting for buffer space (default)
+	AuditOverflowPolicySync = "sync"
+	// AuditOverflowPolicyBlock makes the request wait for buffer space (backpressure)
+	AuditOverflowPolicyBlock = "block"
+	// AuditOverflowPolicyDrop discards the log, counting it in audit_events_dropped_total
+	AuditOverflowPolicyDrop = "drop"
+	// AuditOverflowPolicySpillToRedis pushes the log to a Redis list drained back into the buffer later
+	AuditOverflowPolicySpillToRedis = "spill-to-redis"
+)
+
+// Default avatar modes for DEFAULT_AVATAR_MODE
+const (
+	// DefaultAvatarModeOff leaves new users without an avatar
+	DefaultAvatarModeOff = "off"
+	// DefaultAvatarModeRandom picks a random avatar from the active pool
+	DefaultAvatarModeRandom = "random"
+	// DefaultAvatarModeDeterministic picks the same active avatar for a CPF while the pool is unchanged
+	DefaultAvatarModeDeterministic = "deterministic"
+)
+
+// Avatar storage backends for AVATAR_STORAGE_BACKEND
+const (
+	// AvatarStorageMongo stores avatar images in MongoDB and serves them through the API
+	AvatarStorageMongo = "mongo"
+	// AvatarStorageS3 stores avatar images in an S3-compatible bucket (S3, GCS interoperability, MinIO)
+	AvatarStorageS3 = "s3"
+)
+
+// Profile completeness fields, used as keys of COMPLETENESS_WEIGHTS and of the completeness response
+const (
+	CompletenessFieldTelefone           = "telefone"
+	CompletenessFieldTelefoneVerificado = "telefone_verificado"
+	CompletenessFieldEmail              = "email"
+	CompletenessFieldEndereco           = "endereco"
+	CompletenessFieldRaca               = "raca"
+	CompletenessFieldAvatar             = "avatar"
+	CompletenessFieldOptIn              = "opt_in"
+)
+
+// CompletenessFields lists every field that can be weighted in the completeness score
+var CompletenessFields = []string{
+	CompletenessFieldTelefone,
+	CompletenessFieldTelefoneVerificado,
+	CompletenessFieldEmail,
+	CompletenessFieldEndereco,
+	CompletenessFieldRaca,
+	CompletenessFieldAvatar,
+	CompletenessFieldOptIn,
+}
+
+// SelfDeclaredFields lists the self-declared document fields accepted in CRITICAL_SELF_DECLARED_FIELDS
+var SelfDeclaredFields = []string{
+	"endereco",
+	"email",
+	"telefone",
+	"raca",
+	"nome_exibicao",
+	"genero",
+	"renda_familiar",
+	"escolaridade",
+	"deficiencia",
+}
+
+// Cache namespaces whose TTL can be set in CACHE_TTL
+// ("memory" covers both memory:* and memory_list:* keys)
+const (
+	CacheNamespaceCitizen             = "citizen"
+	CacheNamespaceMaintenanceRequests = "maintenance_requests"
+	CacheNamespaceMemory              = "memory"
+)
+
+// CacheNamespaces lists every namespace accepted in CACHE_TTL
+var CacheNamespaces = []string{
+	CacheNamespaceCitizen,
+	CacheNamespaceMaintenanceRequests,
+	CacheNamespaceMemory,
+}
+
+// CacheTTLConfig maps cache namespaces to the TTL of their entries
+type CacheTTLConfig map[string]time.Duration
+
+// TTL returns the TTL configured for namespace, or fallback when it has none
+func (c CacheTTLConfig) TTL(namespace string, fallback time.Duration) time.Duration {
+	if ttl, ok := c[namespace]; ok {
+		return ttl
+	}
+	return fallback
+}
+
+// Validation rule modes, used as values in VALIDATION_RULES
+const (
+	ValidationRuleModeOff     = "off"     // rule is not evaluated
+	ValidationRuleModeShadow  = "shadow"  // violations are logged and counted but the request is accepted
+	ValidationRuleModeEnforce = "enforce" // violations reject the request
+)
+
+// Validation rules whose mode can be set in VALIDATION_RULES
+const (
+	ValidationRuleAddressCEP  = "address_cep"  // self-declared address CEP must have 8 digits once punctuation is stripped
+	ValidationRuleAddressUF   = "address_uf"   // self-declared address estado must be a Brazilian UF
+	ValidationRuleEmailDomain = "email_domain" // email domain labels must be valid hostnames
+	ValidationRulePhoneDDD    = "phone_ddd"    // Brazilian phones must use a DDD assigned by Anatel
+)
+
+// defaultValidationRules holds the mode of each rule not listed in VALIDATION_RULES. New rules
+// start in shadow mode; phone_ddd was already enforced before rules became configurable.
+var defaultValidationRules = map[string]string{
+	ValidationRuleAddressCEP:  ValidationRuleModeShadow,
+	ValidationRuleAddressUF:   ValidationRuleModeShadow,
+	ValidationRuleEmailDomain: ValidationRuleModeShadow,
+	ValidationRulePhoneDDD:    ValidationRuleModeEnforce,
+}
+
+// Email verification channels
+const (
+	// EmailVerificationChannelWebhook posts the token to EMAIL_VERIFICATION_WEBHOOK_URL, which sends the email
+	EmailVerificationChannelWebhook = "webhook"
+	// EmailVerificationChannelLog only logs the token (development and tests)
+	EmailVerificationChannelLog = "log"
+)
+
+// Notification phone sources, used in NOTIFICATION_PHONE_FALLBACK
+const (
+	NotificationPhoneSourceSelfDeclared = "self_declared" // self-declared phone confirmed with a verification code
+	NotificationPhoneSourceBase         = "base"          // government base phone flagged as valid
+)
+
+// defaultCompletenessWeights is used when COMPLETENESS_WEIGHTS is not set (sums to 100)
+const defaultCompletenessWeights = "telefone=15,telefone_verificado=15,email=15,endereco=20,raca=10,avatar=10,opt_in=15"
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() error {
 	port, err := strconv.Atoi(getEnvOrDefault("PORT", "8080"))
@@ -143,6 +487,16 @@ func LoadConfig() error {
 		return fmt.Errorf("invalid REDIS_TTL: %w", err)
 	}
 
+	cacheTTL, err := parseCacheTTL(getEnvOrDefault("CACHE_TTL", ""))
+	if err != nil {
+		return fmt.Errorf("invalid CACHE_TTL: %w", err)
+	}
+
+	mongoReadPreferences, err := parseMongoReadPreferences(getEnvOrDefault("MONGODB_READ_PREFERENCES", ""))
+	if err != nil {
+		return fmt.Errorf("invalid MONGODB_READ_PREFERENCES: %w", err)
+	}
+
 	// Check if MONGODB_CITIZEN_COLLECTION is set
 	citizenCollection := os.Getenv("MONGODB_CITIZEN_COLLECTION")
 	if citizenCollection == "" {
@@ -198,37 +552,241 @@ func LoadConfig() error {
 	if err != nil {
 		return fmt.Errorf("invalid PHONE_VERIFICATION_TTL: %w", err)
 	}
+	if phoneVerificationTTL <= 0 {
+		return fmt.Errorf("invalid PHONE_VERIFICATION_TTL: must be positive")
+	}
+
+	phoneVerificationCodeLength := getEnvAsIntOrDefault("PHONE_VERIFICATION_CODE_LENGTH", 6)
+	if phoneVerificationCodeLength < MinVerificationCodeLength || phoneVerificationCodeLength > MaxVerificationCodeLength {
+		return fmt.Errorf("invalid PHONE_VERIFICATION_CODE_LENGTH: %d (must be between %d and %d)", phoneVerificationCodeLength, MinVerificationCodeLength, MaxVerificationCodeLength)
+	}
+
+	phoneVerificationCodeAlphabet := getEnvOrDefault("PHONE_VERIFICATION_CODE_ALPHABET", VerificationCodeAlphabetNumeric)
+	if phoneVerificationCodeAlphabet != VerificationCodeAlphabetNumeric && phoneVerificationCodeAlphabet != VerificationCodeAlphabetAlphanumeric {
+		return fmt.Errorf("invalid PHONE_VERIFICATION_CODE_ALPHABET: %q (must be %q or %q)", phoneVerificationCodeAlphabet, VerificationCodeAlphabetNumeric, VerificationCodeAlphabetAlphanumeric)
+	}
+
+	phoneVerificationLockoutWindow, err := time.ParseDuration(getEnvOrDefault("PHONE_VERIFICATION_LOCKOUT_WINDOW", "15m"))
+	if err != nil {
+		return fmt.Errorf("invalid PHONE_VERIFICATION_LOCKOUT_WINDOW: %w", err)
+	}
+	if phoneVerificationLockoutWindow <= 0 {
+		return fmt.Errorf("invalid PHONE_VERIFICATION_LOCKOUT_WINDOW: must be positive")
+	}
+
+	idempotencyKeyTTL, err := time.ParseDuration(getEnvOrDefault("IDEMPOTENCY_KEY_TTL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL: %w", err)
+	}
+	if idempotencyKeyTTL <= 0 {
+		return fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL: must be positive")
+	}
+
+	phoneDeliveryFailureWindow, err := time.ParseDuration(getEnvOrDefault("PHONE_DELIVERY_FAILURE_WINDOW", "168h"))
+	if err != nil {
+		return fmt.Errorf("invalid PHONE_DELIVERY_FAILURE_WINDOW: %w", err)
+	}
+	if phoneDeliveryFailureWindow <= 0 {
+		return fmt.Errorf("invalid PHONE_DELIVERY_FAILURE_WINDOW: must be positive")
+	}
 
 	phoneQuarantineTTL, err := time.ParseDuration(getEnvOrDefault("PHONE_QUARANTINE_TTL", "4320h")) // 6 months
 	if err != nil {
 		return fmt.Errorf("invalid PHONE_QUARANTINE_TTL: %w", err)
 	}
 
+	phoneQuarantineSweepInterval, err := time.ParseDuration(getEnvOrDefault("PHONE_QUARANTINE_SWEEP_INTERVAL", "5m"))
+	if err != nil {
+		return fmt.Errorf("invalid PHONE_QUARANTINE_SWEEP_INTERVAL: %w", err)
+	}
+
+	phoneStatusDetailsCacheTTL, err := time.ParseDuration(getEnvOrDefault("PHONE_STATUS_DETAILS_CACHE_TTL", "1m"))
+	if err != nil {
+		return fmt.Errorf("invalid PHONE_STATUS_DETAILS_CACHE_TTL: %w", err)
+	}
+	if phoneStatusDetailsCacheTTL <= 0 {
+		return fmt.Errorf("invalid PHONE_STATUS_DETAILS_CACHE_TTL: must be positive")
+	}
+
+	cpfValidationRateWindow, err := time.ParseDuration(getEnvOrDefault("CPF_VALIDATION_RATE_WINDOW", "1m"))
+	if err != nil {
+		return fmt.Errorf("invalid CPF_VALIDATION_RATE_WINDOW: %w", err)
+	}
+	if cpfValidationRateWindow <= 0 {
+		return fmt.Errorf("invalid CPF_VALIDATION_RATE_WINDOW: must be positive")
+	}
+
 	betaStatusCacheTTL, err := time.ParseDuration(getEnvOrDefault("BETA_STATUS_CACHE_TTL", "24h")) // 24 hours
 	if err != nil {
 		return fmt.Errorf("invalid BETA_STATUS_CACHE_TTL: %w", err)
 	}
 
+	betaGroupStatsCacheTTL, err := time.ParseDuration(getEnvOrDefault("BETA_GROUP_STATS_CACHE_TTL", "5m"))
+	if err != nil {
+		return fmt.Errorf("invalid BETA_GROUP_STATS_CACHE_TTL: %w", err)
+	}
+	if betaGroupStatsCacheTTL <= 0 {
+		return fmt.Errorf("invalid BETA_GROUP_STATS_CACHE_TTL: must be positive")
+	}
+
+	betaGroupStatsRecentWindow, err := time.ParseDuration(getEnvOrDefault("BETA_GROUP_STATS_RECENT_WINDOW", "168h")) // 7 days
+	if err != nil {
+		return fmt.Errorf("invalid BETA_GROUP_STATS_RECENT_WINDOW: %w", err)
+	}
+	if betaGroupStatsRecentWindow <= 0 {
+		return fmt.Errorf("invalid BETA_GROUP_STATS_RECENT_WINDOW: must be positive")
+	}
+
 	selfDeclaredOutdatedThreshold, err := time.ParseDuration(getEnvOrDefault("SELF_DECLARED_OUTDATED_THRESHOLD", "4320h")) // 180 days
 	if err != nil {
 		return fmt.Errorf("invalid SELF_DECLARED_OUTDATED_THRESHOLD: %w", err)
 	}
 
+	exhibitionNameMinLength := getEnvAsIntOrDefault("EXHIBITION_NAME_MIN_LENGTH", 2)
+	if exhibitionNameMinLength < 1 || exhibitionNameMinLength > MaxExhibitionNameLength {
+		return fmt.Errorf("invalid EXHIBITION_NAME_MIN_LENGTH: %d (must be between 1 and %d)", exhibitionNameMinLength, MaxExhibitionNameLength)
+	}
+
 	addressCacheTTL, err := time.ParseDuration(getEnvOrDefault("ADDRESS_CACHE_TTL", "6h")) // 6 hours
 	if err != nil {
 		return fmt.Errorf("invalid ADDRESS_CACHE_TTL: %w", err)
 	}
 
+	shutdownDrainDelay, err := time.ParseDuration(getEnvOrDefault("SHUTDOWN_DRAIN_DELAY", "5s"))
+	if err != nil {
+		return fmt.Errorf("invalid SHUTDOWN_DRAIN_DELAY: %w", err)
+	}
+	if shutdownDrainDelay < 0 {
+		return fmt.Errorf("invalid SHUTDOWN_DRAIN_DELAY: must not be negative")
+	}
+
 	avatarCacheTTL, err := time.ParseDuration(getEnvOrDefault("AVATAR_CACHE_TTL", "1h")) // 1 hour
 	if err != nil {
 		return fmt.Errorf("invalid AVATAR_CACHE_TTL: %w", err)
 	}
 
+	avatarListMaxPerPage, err := strconv.Atoi(getEnvOrDefault("AVATAR_LIST_MAX_PER_PAGE", "100"))
+	if err != nil {
+		return fmt.Errorf("invalid AVATAR_LIST_MAX_PER_PAGE: %w", err)
+	}
+	if avatarListMaxPerPage <= 0 {
+		return fmt.Errorf("invalid AVATAR_LIST_MAX_PER_PAGE: must be positive")
+	}
+
+	defaultAvatarMode := getEnvOrDefault("DEFAULT_AVATAR_MODE", DefaultAvatarModeOff)
+	switch defaultAvatarMode {
+	case DefaultAvatarModeOff, DefaultAvatarModeRandom, DefaultAvatarModeDeterministic:
+	default:
+		return fmt.Errorf("invalid DEFAULT_AVATAR_MODE: %q (must be %q, %q or %q)", defaultAvatarMode, DefaultAvatarModeOff, DefaultAvatarModeRandom, DefaultAvatarModeDeterministic)
+	}
+
+	avatarStorageBackend := getEnvOrDefault("AVATAR_STORAGE_BACKEND", AvatarStorageMongo)
+	avatarS3Endpoint := os.Getenv("AVATAR_S3_ENDPOINT")
+	avatarS3Bucket := os.Getenv("AVATAR_S3_BUCKET")
+	avatarS3AccessKeyID := os.Getenv("AVATAR_S3_ACCESS_KEY_ID")
+	avatarS3SecretAccessKey := os.Getenv("AVATAR_S3_SECRET_ACCESS_KEY")
+	switch avatarStorageBackend {
+	case AvatarStorageMongo:
+	case AvatarStorageS3:
+		if avatarS3Endpoint == "" {
+			return fmt.Errorf("AVATAR_S3_ENDPOINT is required when AVATAR_STORAGE_BACKEND is %q", AvatarStorageS3)
+		}
+		if avatarS3Bucket == "" {
+			return fmt.Errorf("AVATAR_S3_BUCKET is required when AVATAR_STORAGE_BACKEND is %q", AvatarStorageS3)
+		}
+		if avatarS3AccessKeyID == "" || avatarS3SecretAccessKey == "" {
+			return fmt.Errorf("AVATAR_S3_ACCESS_KEY_ID and AVATAR_S3_SECRET_ACCESS_KEY are required when AVATAR_STORAGE_BACKEND is %q", AvatarStorageS3)
+		}
+	default:
+		return fmt.Errorf("invalid AVATAR_STORAGE_BACKEND: %q (must be %q or %q)", avatarStorageBackend, AvatarStorageMongo, AvatarStorageS3)
+	}
+
 	notificationCategoryCacheTTL, err := time.ParseDuration(getEnvOrDefault("NOTIFICATION_CATEGORY_CACHE_TTL", "6h")) // 6 hours
 	if err != nil {
 		return fmt.Errorf("invalid NOTIFICATION_CATEGORY_CACHE_TTL: %w", err)
 	}
 
+	ethnicityOptionsCacheTTL, err := time.ParseDuration(getEnvOrDefault("ETHNICITY_OPTIONS_CACHE_TTL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid ETHNICITY_OPTIONS_CACHE_TTL: %w", err)
+	}
+	if ethnicityOptionsCacheTTL <= 0 {
+		return fmt.Errorf("invalid ETHNICITY_OPTIONS_CACHE_TTL: must be positive")
+	}
+
+	featureFlagCacheTTL, err := time.ParseDuration(getEnvOrDefault("FEATURE_FLAG_CACHE_TTL", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid FEATURE_FLAG_CACHE_TTL: %w", err)
+	}
+	if featureFlagCacheTTL <= 0 {
+		return fmt.Errorf("invalid FEATURE_FLAG_CACHE_TTL: must be positive")
+	}
+
+	legalEntityCacheTTL, err := time.ParseDuration(getEnvOrDefault("LEGAL_ENTITY_CACHE_TTL", "24h")) // 24 hours
+	if err != nil {
+		return fmt.Errorf("invalid LEGAL_ENTITY_CACHE_TTL: %w", err)
+	}
+
+	citizenExternalIDSystem := os.Getenv("CITIZEN_EXTERNAL_ID_SYSTEM")
+	if !isValidExternalIDSystem(citizenExternalIDSystem) {
+		return fmt.Errorf("invalid CITIZEN_EXTERNAL_ID_SYSTEM: %q (only letters, digits, '_' and '-' allowed)", citizenExternalIDSystem)
+	}
+
+	externalIDCacheTTL, err := time.ParseDuration(getEnvOrDefault("EXTERNAL_ID_CACHE_TTL", "24h"))
+	if err != nil {
+		return fmt.Errorf("invalid EXTERNAL_ID_CACHE_TTL: %w", err)
+	}
+	if externalIDCacheTTL <= 0 {
+		return fmt.Errorf("invalid EXTERNAL_ID_CACHE_TTL: must be positive")
+	}
+
+	userConfigWriteMode := getEnvOrDefault("USER_CONFIG_WRITE_MODE", UserConfigWriteModeField)
+	if userConfigWriteMode != UserConfigWriteModeField && userConfigWriteMode != UserConfigWriteModeDocument {
+		return fmt.Errorf("invalid USER_CONFIG_WRITE_MODE: %q (must be %q or %q)", userConfigWriteMode, UserConfigWriteModeField, UserConfigWriteModeDocument)
+	}
+
+	validationRules, err := parseValidationRules(getEnvOrDefault("VALIDATION_RULES", ""))
+	if err != nil {
+		return fmt.Errorf("invalid VALIDATION_RULES: %w", err)
+	}
+
+	mongoDegradedReadsEnabled := getEnvOrDefault("MONGODB_DEGRADED_READS_ENABLED", "false") == "true"
+
+	auditFlushInterval, err := time.ParseDuration(getEnvOrDefault("AUDIT_FLUSH_INTERVAL", "100ms"))
+	if err != nil {
+		return fmt.Errorf("invalid AUDIT_FLUSH_INTERVAL: %w", err)
+	}
+	if auditFlushInterval <= 0 {
+		return fmt.Errorf("invalid AUDIT_FLUSH_INTERVAL: must be positive")
+	}
+
+	auditOverflowPolicy := getEnvOrDefault("AUDIT_OVERFLOW_POLICY", AuditOverflowPolicySync)
+	switch auditOverflowPolicy {
+	case AuditOverflowPolicySync, AuditOverflowPolicyBlock, AuditOverflowPolicyDrop, AuditOverflowPolicySpillToRedis:
+	default:
+		return fmt.Errorf("invalid AUDIT_OVERFLOW_POLICY: %q (must be %q, %q, %q or %q)", auditOverflowPolicy, AuditOverflowPolicySync, AuditOverflowPolicyBlock, AuditOverflowPolicyDrop, AuditOverflowPolicySpillToRedis)
+	}
+
+	eventStreamIdleTimeout, err := time.ParseDuration(getEnvOrDefault("EVENT_STREAM_IDLE_TIMEOUT", "30m"))
+	if err != nil {
+		return fmt.Errorf("invalid EVENT_STREAM_IDLE_TIMEOUT: %w", err)
+	}
+
+	completenessWeights, err := parseCompletenessWeights(getEnvOrDefault("COMPLETENESS_WEIGHTS", defaultCompletenessWeights))
+	if err != nil {
+		return fmt.Errorf("invalid COMPLETENESS_WEIGHTS: %w", err)
+	}
+
+	notificationPhoneFallback, err := parseNotificationPhoneFallback(getEnvOrDefault("NOTIFICATION_PHONE_FALLBACK", NotificationPhoneSourceSelfDeclared+","+NotificationPhoneSourceBase))
+	if err != nil {
+		return fmt.Errorf("invalid NOTIFICATION_PHONE_FALLBACK: %w", err)
+	}
+
+	criticalSelfDeclaredFields, err := parseCriticalSelfDeclaredFields(getEnvOrDefault("CRITICAL_SELF_DECLARED_FIELDS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid CRITICAL_SELF_DECLARED_FIELDS: %w", err)
+	}
+
 	// CF Lookup configuration
 	cfLookupEnabled := getEnvOrDefault("CF_LOOKUP_ENABLED", "true") == "true"
 
@@ -264,6 +822,212 @@ func LoadConfig() error {
 	if err != nil {
 		return fmt.Errorf("invalid CF_LOOKUP_SYNC_TIMEOUT: %w", err)
 	}
+	if cfLookupSyncTimeout <= 0 {
+		return fmt.Errorf("invalid CF_LOOKUP_SYNC_TIMEOUT: must be positive")
+	}
+	cfLookupSyncEnabled := getEnvOrDefault("CF_LOOKUP_SYNC_ENABLED", "true") == "true"
+
+	trustedProxies, err := parseTrustedProxies(getEnvOrDefault("TRUSTED_PROXIES", ""))
+	if err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	corsAllowedOrigins, err := parseCORSAllowedOrigins(getEnvOrDefault("CORS_ALLOWED_ORIGINS", ""))
+	if err != nil {
+		return fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %w", err)
+	}
+	corsMaxAge, err := time.ParseDuration(getEnvOrDefault("CORS_MAX_AGE", "12h"))
+	if err != nil {
+		return fmt.Errorf("invalid CORS_MAX_AGE: %w", err)
+	}
+	if corsMaxAge < 0 {
+		return fmt.Errorf("invalid CORS_MAX_AGE: must not be negative")
+	}
+
+	serviceTokenIssuer := getEnvOrDefault("SERVICE_TOKEN_ISSUER", "")
+	serviceTokenJWKSURL := getEnvOrDefault("SERVICE_TOKEN_JWKS_URL", "")
+	if serviceTokenIssuer != "" && serviceTokenJWKSURL == "" {
+		return fmt.Errorf("invalid SERVICE_TOKEN_JWKS_URL: required when SERVICE_TOKEN_ISSUER is set")
+	}
+	serviceTokenJWKSCacheTTL, err := time.ParseDuration(getEnvOrDefault("SERVICE_TOKEN_JWKS_CACHE_TTL", "1h"))
+	if err != nil || serviceTokenJWKSCacheTTL <= 0 {
+		return fmt.Errorf("invalid SERVICE_TOKEN_JWKS_CACHE_TTL: must be a positive duration")
+	}
+
+	maxRequestBodySize, err := parseBodySizeEnv("MAX_REQUEST_BODY_SIZE", "1048576")
+	if err != nil {
+		return err
+	}
+	maxAvatarBodySize, err := parseBodySizeEnv("MAX_AVATAR_BODY_SIZE", "5242880")
+	if err != nil {
+		return err
+	}
+	maxSelfDeclaredBodySize, err := parseBodySizeEnv("MAX_SELF_DECLARED_BODY_SIZE", "16384")
+	if err != nil {
+		return err
+	}
+
+	compressionMinSize, err := strconv.Atoi(getEnvOrDefault("COMPRESSION_MIN_SIZE", "1024"))
+	if err != nil || compressionMinSize < 0 {
+		return fmt.Errorf("invalid COMPRESSION_MIN_SIZE: must be a non-negative number of bytes")
+	}
+	compressionGzipLevel, err := strconv.Atoi(getEnvOrDefault("COMPRESSION_GZIP_LEVEL", "5"))
+	if err != nil || compressionGzipLevel < 1 || compressionGzipLevel > 9 {
+		return fmt.Errorf("invalid COMPRESSION_GZIP_LEVEL: must be between 1 and 9")
+	}
+	compressionBrotliLevel, err := strconv.Atoi(getEnvOrDefault("COMPRESSION_BROTLI_LEVEL", "4"))
+	if err != nil || compressionBrotliLevel < 0 || compressionBrotliLevel > 11 {
+		return fmt.Errorf("invalid COMPRESSION_BROTLI_LEVEL: must be between 0 and 11")
+	}
+
+	var outboundHTTPTimeout, outboundHTTPDialTimeout, outboundHTTPTLSHandshakeTimeout, outboundHTTPResponseHeaderTimeout, outboundHTTPIdleConnTimeout time.Duration
+	for _, timeout := range []struct {
+		env          string
+		defaultValue string
+		target       *time.Duration
+	}{
+		{"OUTBOUND_HTTP_TIMEOUT", "30s", &outboundHTTPTimeout},
+		{"OUTBOUND_HTTP_DIAL_TIMEOUT", "5s", &outboundHTTPDialTimeout},
+		{"OUTBOUND_HTTP_TLS_HANDSHAKE_TIMEOUT", "10s", &outboundHTTPTLSHandshakeTimeout},
+		{"OUTBOUND_HTTP_RESPONSE_HEADER_TIMEOUT", "20s", &outboundHTTPResponseHeaderTimeout},
+		{"OUTBOUND_HTTP_IDLE_CONN_TIMEOUT", "90s", &outboundHTTPIdleConnTimeout},
+	} {
+		value, err := time.ParseDuration(getEnvOrDefault(timeout.env, timeout.defaultValue))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", timeout.env, err)
+		}
+		if value <= 0 {
+			return fmt.Errorf("invalid %s: must be positive", timeout.env)
+		}
+		*timeout.target = value
+	}
+	outboundHTTPMaxIdleConns, err := strconv.Atoi(getEnvOrDefault("OUTBOUND_HTTP_MAX_IDLE_CONNS", "100"))
+	if err != nil || outboundHTTPMaxIdleConns < 1 {
+		return fmt.Errorf("invalid OUTBOUND_HTTP_MAX_IDLE_CONNS: must be a positive number")
+	}
+	outboundHTTPMaxIdleConnsPerHost, err := strconv.Atoi(getEnvOrDefault("OUTBOUND_HTTP_MAX_IDLE_CONNS_PER_HOST", "20"))
+	if err != nil || outboundHTTPMaxIdleConnsPerHost < 1 {
+		return fmt.Errorf("invalid OUTBOUND_HTTP_MAX_IDLE_CONNS_PER_HOST: must be a positive number")
+	}
+	outboundHTTPMaxConnsPerHost, err := strconv.Atoi(getEnvOrDefault("OUTBOUND_HTTP_MAX_CONNS_PER_HOST", "100"))
+	if err != nil || outboundHTTPMaxConnsPerHost < 0 {
+		return fmt.Errorf("invalid OUTBOUND_HTTP_MAX_CONNS_PER_HOST: must be a non-negative number")
+	}
+
+	// Address change webhook configuration (URL and secret only required if enabled)
+	addressWebhookEnabled := getEnvOrDefault("ADDRESS_WEBHOOK_ENABLED", "false") == "true"
+	addressWebhookURL := os.Getenv("ADDRESS_WEBHOOK_URL")
+	addressWebhookSecret := os.Getenv("ADDRESS_WEBHOOK_SECRET")
+
+	if addressWebhookEnabled {
+		if addressWebhookURL == "" {
+			return fmt.Errorf("ADDRESS_WEBHOOK_URL is required when ADDRESS_WEBHOOK_ENABLED=true")
+		}
+		if addressWebhookSecret == "" {
+			return fmt.Errorf("ADDRESS_WEBHOOK_SECRET is required when ADDRESS_WEBHOOK_ENABLED=true")
+		}
+	}
+
+	addressWebhookTimeout, err := time.ParseDuration(getEnvOrDefault("ADDRESS_WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return fmt.Errorf("invalid ADDRESS_WEBHOOK_TIMEOUT: %w", err)
+	}
+
+	// Phone verified webhook configuration (URL and secret only required if enabled)
+	phoneVerifiedWebhookEnabled := getEnvOrDefault("PHONE_VERIFIED_WEBHOOK_ENABLED", "false") == "true"
+	phoneVerifiedWebhookURL := os.Getenv("PHONE_VERIFIED_WEBHOOK_URL")
+	phoneVerifiedWebhookSecret := os.Getenv("PHONE_VERIFIED_WEBHOOK_SECRET")
+
+	if phoneVerifiedWebhookEnabled {
+		if phoneVerifiedWebhookURL == "" {
+			return fmt.Errorf("PHONE_VERIFIED_WEBHOOK_URL is required when PHONE_VERIFIED_WEBHOOK_ENABLED=true")
+		}
+		if phoneVerifiedWebhookSecret == "" {
+			return fmt.Errorf("PHONE_VERIFIED_WEBHOOK_SECRET is required when PHONE_VERIFIED_WEBHOOK_ENABLED=true")
+		}
+	}
+
+	phoneVerifiedWebhookTimeout, err := time.ParseDuration(getEnvOrDefault("PHONE_VERIFIED_WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return fmt.Errorf("invalid PHONE_VERIFIED_WEBHOOK_TIMEOUT: %w", err)
+	}
+
+	// Email verification configuration (webhook URL and secret only required if enabled)
+	emailVerificationEnabled := getEnvOrDefault("EMAIL_VERIFICATION_ENABLED", "false") == "true"
+	emailVerificationTTL, err := time.ParseDuration(getEnvOrDefault("EMAIL_VERIFICATION_TTL", "24h"))
+	if err != nil {
+		return fmt.Errorf("invalid EMAIL_VERIFICATION_TTL: %w", err)
+	}
+
+	emailVerificationChannel := getEnvOrDefault("EMAIL_VERIFICATION_CHANNEL", EmailVerificationChannelWebhook)
+	if emailVerificationChannel != EmailVerificationChannelWebhook && emailVerificationChannel != EmailVerificationChannelLog {
+		return fmt.Errorf("invalid EMAIL_VERIFICATION_CHANNEL: %q (must be %q or %q)", emailVerificationChannel, EmailVerificationChannelWebhook, EmailVerificationChannelLog)
+	}
+
+	emailVerificationWebhookURL := os.Getenv("EMAIL_VERIFICATION_WEBHOOK_URL")
+	emailVerificationWebhookSecret := os.Getenv("EMAIL_VERIFICATION_WEBHOOK_SECRET")
+	if emailVerificationEnabled && emailVerificationChannel == EmailVerificationChannelWebhook {
+		if emailVerificationWebhookURL == "" {
+			return fmt.Errorf("EMAIL_VERIFICATION_WEBHOOK_URL is required when EMAIL_VERIFICATION_ENABLED=true and EMAIL_VERIFICATION_CHANNEL=webhook")
+		}
+		if emailVerificationWebhookSecret == "" {
+			return fmt.Errorf("EMAIL_VERIFICATION_WEBHOOK_SECRET is required when EMAIL_VERIFICATION_ENABLED=true and EMAIL_VERIFICATION_CHANNEL=webhook")
+		}
+	}
+
+	emailVerificationWebhookTimeout, err := time.ParseDuration(getEnvOrDefault("EMAIL_VERIFICATION_WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return fmt.Errorf("invalid EMAIL_VERIFICATION_WEBHOOK_TIMEOUT: %w", err)
+	}
+
+	// Magic links are optional; they need a signing secret and somewhere to send the citizen back
+	emailVerificationLinkURL := os.Getenv("EMAIL_VERIFICATION_LINK_URL")
+	emailVerificationLinkSecret := os.Getenv("EMAIL_VERIFICATION_LINK_SECRET")
+	emailVerificationRedirectURL := os.Getenv("EMAIL_VERIFICATION_REDIRECT_URL")
+	if emailVerificationLinkURL != "" {
+		if emailVerificationLinkSecret == "" {
+			return fmt.Errorf("EMAIL_VERIFICATION_LINK_SECRET is required when EMAIL_VERIFICATION_LINK_URL is set")
+		}
+		if emailVerificationRedirectURL == "" {
+			return fmt.Errorf("EMAIL_VERIFICATION_REDIRECT_URL is required when EMAIL_VERIFICATION_LINK_URL is set")
+		}
+	}
+
+	cfLookupMaxAge, err := time.ParseDuration(getEnvOrDefault("CF_LOOKUP_MAX_AGE", "720h")) // 30 days
+	if err != nil {
+		return fmt.Errorf("invalid CF_LOOKUP_MAX_AGE: %w", err)
+	}
+
+	cfLookupNoEquipmentTTL, err := time.ParseDuration(getEnvOrDefault("CF_LOOKUP_NO_EQUIPMENT_TTL", "24h"))
+	if err != nil {
+		return fmt.Errorf("invalid CF_LOOKUP_NO_EQUIPMENT_TTL: %w", err)
+	}
+
+	cfCoverageStatsCacheTTL, err := time.ParseDuration(getEnvOrDefault("CF_COVERAGE_STATS_CACHE_TTL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid CF_COVERAGE_STATS_CACHE_TTL: %w", err)
+	}
+
+	cfCircuitFailureRatio, err := strconv.ParseFloat(getEnvOrDefault("CF_CIRCUIT_FAILURE_RATIO", "0.5"), 64)
+	if err != nil {
+		return fmt.Errorf("invalid CF_CIRCUIT_FAILURE_RATIO: %w", err)
+	}
+	if cfCircuitFailureRatio <= 0 || cfCircuitFailureRatio > 1 {
+		return fmt.Errorf("invalid CF_CIRCUIT_FAILURE_RATIO: %v (must be greater than 0 and at most 1)", cfCircuitFailureRatio)
+	}
+
+	cfCircuitMinRequests := getEnvAsIntOrDefault("CF_CIRCUIT_MIN_REQUESTS", 10)
+	if cfCircuitMinRequests < 1 {
+		return fmt.Errorf("invalid CF_CIRCUIT_MIN_REQUESTS: %d (must be at least 1)", cfCircuitMinRequests)
+	}
+
+	cfCircuitOpenTimeout, err := time.ParseDuration(getEnvOrDefault("CF_CIRCUIT_OPEN_TIMEOUT", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid CF_CIRCUIT_OPEN_TIMEOUT: %w", err)
+	}
+	if cfCircuitOpenTimeout <= 0 {
+		return fmt.Errorf("invalid CF_CIRCUIT_OPEN_TIMEOUT: must be positive")
+	}
 
 	// WhatsApp configuration
 	whatsappEnabled := os.Getenv("WHATSAPP_ENABLED")
@@ -305,11 +1069,65 @@ func LoadConfig() error {
 		return fmt.Errorf("WHATSAPP_CAMPAIGN_NAME is required")
 	}
 
+	syncFlushTimeout, err := time.ParseDuration(getEnvOrDefault("SYNC_FLUSH_TIMEOUT", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid SYNC_FLUSH_TIMEOUT: %w", err)
+	}
+
+	syncWorkerPools, err := parseSyncWorkerPools(getEnvOrDefault("SYNC_WORKER_POOLS", "cf_lookup=2"))
+	if err != nil {
+		return fmt.Errorf("invalid SYNC_WORKER_POOLS: %w", err)
+	}
+
+	writeBufferReconcileInterval, err := time.ParseDuration(getEnvOrDefault("WRITE_BUFFER_RECONCILE_INTERVAL", "15m"))
+	if err != nil {
+		return fmt.Errorf("invalid WRITE_BUFFER_RECONCILE_INTERVAL: %w", err)
+	}
+	if writeBufferReconcileInterval < 0 {
+		return fmt.Errorf("invalid WRITE_BUFFER_RECONCILE_INTERVAL: must not be negative")
+	}
+	writeBufferReconcileStaleAfter, err := time.ParseDuration(getEnvOrDefault("WRITE_BUFFER_RECONCILE_STALE_AFTER", "30m"))
+	if err != nil {
+		return fmt.Errorf("invalid WRITE_BUFFER_RECONCILE_STALE_AFTER: %w", err)
+	}
+	if writeBufferReconcileStaleAfter <= 0 {
+		return fmt.Errorf("invalid WRITE_BUFFER_RECONCILE_STALE_AFTER: must be positive")
+	}
+
 	indexMaintenanceInterval, err := time.ParseDuration(getEnvOrDefault("INDEX_MAINTENANCE_INTERVAL", "1h"))
 	if err != nil {
 		return fmt.Errorf("invalid INDEX_MAINTENANCE_INTERVAL: %w", err)
 	}
 
+	dataFreshnessInterval, err := time.ParseDuration(getEnvOrDefault("DATA_FRESHNESS_INTERVAL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid DATA_FRESHNESS_INTERVAL: %w", err)
+	}
+	if dataFreshnessInterval < 0 {
+		return fmt.Errorf("invalid DATA_FRESHNESS_INTERVAL: must not be negative")
+	}
+	dataFreshnessSampleSize, err := strconv.Atoi(getEnvOrDefault("DATA_FRESHNESS_SAMPLE_SIZE", "10000"))
+	if err != nil || dataFreshnessSampleSize <= 0 {
+		return fmt.Errorf("invalid DATA_FRESHNESS_SAMPLE_SIZE: must be a positive number")
+	}
+	dataFreshnessCollections := parseCommaSeparatedList(getEnvOrDefault("DATA_FRESHNESS_COLLECTIONS", ""))
+	if len(dataFreshnessCollections) == 0 {
+		dataFreshnessCollections = []string{citizenCollection, maintenanceRequestCollection}
+	}
+
+	staticListsRefreshInterval, err := time.ParseDuration(getEnvOrDefault("STATIC_LISTS_REFRESH_INTERVAL", "5m"))
+	if err != nil {
+		return fmt.Errorf("invalid STATIC_LISTS_REFRESH_INTERVAL: %w", err)
+	}
+
+	// PII masking in logs is on by default everywhere but development
+	environment := getEnvOrDefault("ENVIRONMENT", "development")
+	logPIIMaskingDefault := "true"
+	if environment == "development" {
+		logPIIMaskingDefault = "false"
+	}
+	logPIIMasking := getEnvOrDefault("LOG_PII_MASKING", logPIIMaskingDefault) == "true"
+
 	// Redis Cluster configuration
 	redisClusterEnabled := getEnvOrDefault("REDIS_CLUSTER_ENABLED", "false") == "true"
 	var redisClusterAddrs []string
@@ -324,18 +1142,22 @@ func LoadConfig() error {
 
 	AppConfig = &Config{
 		// Server configuration
-		Port:        port,
-		Environment: getEnvOrDefault("ENVIRONMENT", "development"),
+		Port:               port,
+		Environment:        environment,
+		ShutdownDrainDelay: shutdownDrainDelay,
 
 		// MongoDB configuration
 		MongoURI:      getEnvOrDefault("MONGODB_URI", "mongodb://localhost:27017"),
 		MongoDatabase: getEnvOrDefault("MONGODB_DATABASE", "rmi"),
 
+		MongoReadPreferences: mongoReadPreferences,
+
 		// Redis configuration
 		RedisURI:      getEnvOrDefault("REDIS_URI", "localhost:6379"),
 		RedisPassword: getEnvOrDefault("REDIS_PASSWORD", ""),
 		RedisDB:       redisDB,
 		RedisTTL:      redisTTL,
+		CacheTTL:      cacheTTL,
 
 		// Redis Cluster configuration
 		RedisClusterEnabled:  redisClusterEnabled,
@@ -354,6 +1176,7 @@ func LoadConfig() error {
 		CitizenCollection:              citizenCollection,
 		SelfDeclaredCollection:         getEnvOrDefault("MONGODB_SELF_DECLARED_COLLECTION", "self_declared"),
 		PhoneVerificationCollection:    getEnvOrDefault("MONGODB_PHONE_VERIFICATION_COLLECTION", "phone_verifications"),
+		EmailVerificationCollection:    getEnvOrDefault("MONGODB_EMAIL_VERIFICATION_COLLECTION", "email_verifications"),
 		UserConfigCollection:           getEnvOrDefault("MONGODB_USER_CONFIG_COLLECTION", "user_config"),
 		MaintenanceRequestCollection:   maintenanceRequestCollection,
 		PhoneMappingCollection:         getEnvOrDefault("MONGODB_PHONE_MAPPING_COLLECTION", "phone_cpf_mappings"),
@@ -363,6 +1186,7 @@ func LoadConfig() error {
 		BairroCollection:               getEnvOrDefault("MONGODB_BAIRRO_COLLECTION", "bairro"),
 		LogradouroCollection:           getEnvOrDefault("MONGODB_LOGRADOURO_COLLECTION", "logradouro"),
 		AvatarsCollection:              getEnvOrDefault("MONGODB_AVATARS_COLLECTION", "avatars"),
+		AvatarImagesCollection:         getEnvOrDefault("MONGODB_AVATAR_IMAGES_COLLECTION", "avatar_images"),
 		LegalEntityCollection:          legalEntityCollection,
 		PetCollection:                  petCollection,
 		PetsSelfRegisteredCollection:   petsSelfRegisteredCollection,
@@ -371,21 +1195,109 @@ func LoadConfig() error {
 		NotificationCategoryCollection: notificationCategoryCollection,
 		CNAECollection:                 cnaeCollection,
 		CPFSecretariaCollection:        getEnvOrDefault("MONGODB_CPF_SECRETARIA_COLLECTION", "cpf_secretaria_mappings"),
+		EthnicityOptionCollection:      getEnvOrDefault("MONGODB_ETHNICITY_OPTION_COLLECTION", "ethnicity_options"),
+		FeatureFlagCollection:          getEnvOrDefault("MONGODB_FEATURE_FLAG_COLLECTION", "feature_flags"),
 
 		// Phone verification configuration
-		PhoneVerificationTTL:          phoneVerificationTTL,
-		PhoneQuarantineTTL:            phoneQuarantineTTL,
-		BetaStatusCacheTTL:            betaStatusCacheTTL,
-		SelfDeclaredOutdatedThreshold: selfDeclaredOutdatedThreshold,
+		PhoneVerificationTTL:              phoneVerificationTTL,
+		PhoneVerificationCodeLength:       phoneVerificationCodeLength,
+		PhoneVerificationCodeAlphabet:     phoneVerificationCodeAlphabet,
+		PhoneVerificationDuplicateRetries: getEnvAsIntOrDefault("PHONE_VERIFICATION_DUPLICATE_RETRIES", 1),
+		PhoneVerificationMaxFailures:      getEnvAsIntOrDefault("PHONE_VERIFICATION_MAX_FAILURES", 5),
+		PhoneVerificationLockoutWindow:    phoneVerificationLockoutWindow,
+		PhoneDeliveryFailureThreshold:     getEnvAsIntOrDefault("PHONE_DELIVERY_FAILURE_THRESHOLD", 3),
+		PhoneDeliveryFailureWindow:        phoneDeliveryFailureWindow,
+		PhoneQuarantineTTL:                phoneQuarantineTTL,
+		PhoneQuarantineSweepInterval:      phoneQuarantineSweepInterval,
+		PhoneQuarantineSweepBatchSize:     getEnvAsIntOrDefault("PHONE_QUARANTINE_SWEEP_BATCH_SIZE", 500),
+		PhoneStatusDetailsCacheTTL:        phoneStatusDetailsCacheTTL,
+		BetaStatusCacheTTL:                betaStatusCacheTTL,
+		BetaGroupStatsCacheTTL:            betaGroupStatsCacheTTL,
+		BetaGroupStatsRecentWindow:        betaGroupStatsRecentWindow,
+		SelfDeclaredOutdatedThreshold:     selfDeclaredOutdatedThreshold,
+		ExhibitionNameMinLength:           exhibitionNameMinLength,
+		ExhibitionNameBlocklist:           parseCommaSeparatedList(getEnvOrDefault("EXHIBITION_NAME_BLOCKLIST", "")),
+
+		// Public validation endpoints configuration
+		CPFValidationRateLimit:  getEnvAsIntOrDefault("CPF_VALIDATION_RATE_LIMIT", 30),
+		CPFValidationRateWindow: cpfValidationRateWindow,
+
+		// Email verification configuration
+		EmailVerificationEnabled:        emailVerificationEnabled,
+		EmailVerificationTTL:            emailVerificationTTL,
+		EmailVerificationChannel:        emailVerificationChannel,
+		EmailVerificationWebhookURL:     emailVerificationWebhookURL,
+		EmailVerificationWebhookSecret:  emailVerificationWebhookSecret,
+		EmailVerificationWebhookTimeout: emailVerificationWebhookTimeout,
+		EmailVerificationLinkURL:        emailVerificationLinkURL,
+		EmailVerificationLinkSecret:     emailVerificationLinkSecret,
+		EmailVerificationRedirectURL:    emailVerificationRedirectURL,
 
 		// Address building configuration
 		AddressCacheTTL: addressCacheTTL,
 
 		// Avatar configuration
-		AvatarCacheTTL: avatarCacheTTL,
+		AvatarCacheTTL:       avatarCacheTTL,
+		DefaultAvatarMode:    defaultAvatarMode,
+		AvatarListMaxPerPage: avatarListMaxPerPage,
+
+		AvatarStorageBackend:    avatarStorageBackend,
+		AvatarPublicBaseURL:     strings.TrimSuffix(os.Getenv("AVATAR_PUBLIC_BASE_URL"), "/"),
+		AvatarS3Endpoint:        strings.TrimSuffix(avatarS3Endpoint, "/"),
+		AvatarS3Bucket:          avatarS3Bucket,
+		AvatarS3Region:          getEnvOrDefault("AVATAR_S3_REGION", "us-east-1"),
+		AvatarS3AccessKeyID:     avatarS3AccessKeyID,
+		AvatarS3SecretAccessKey: avatarS3SecretAccessKey,
 
 		// Notification category configuration
 		NotificationCategoryCacheTTL: notificationCategoryCacheTTL,
+		MaxCategoryOptIns:            getEnvAsIntOrDefault("MAX_CATEGORY_OPT_INS", 50),
+
+		// Ethnicity options configuration
+		EthnicityOptionsCacheTTL: ethnicityOptionsCacheTTL,
+
+		// Feature flag configuration
+		FeatureFlagCacheTTL: featureFlagCacheTTL,
+
+		// Legal entity configuration
+		LegalEntityCacheTTL: legalEntityCacheTTL,
+
+		// External ID configuration
+		CitizenExternalIDSystem: citizenExternalIDSystem,
+		ExternalIDCacheTTL:      externalIDCacheTTL,
+
+		// User config configuration
+		UserConfigWriteMode:  userConfigWriteMode,
+		OptOutReasonRequired: getEnvOrDefault("OPT_OUT_REASON_REQUIRED", "false") == "true",
+
+		// Validation rules configuration
+		ValidationRules: validationRules,
+
+		// Degraded reads configuration
+		MongoDegradedReadsEnabled: mongoDegradedReadsEnabled,
+
+		// Request validation configuration
+		OpenAPIValidationGroups: parseCommaSeparatedList(getEnvOrDefault("OPENAPI_VALIDATION_GROUPS", "")),
+
+		// Idempotency configuration
+		IdempotencyKeyTTL: idempotencyKeyTTL,
+
+		// Event stream (SSE) configuration
+		EventStreamMaxConnections:       getEnvAsIntOrDefault("EVENT_STREAM_MAX_CONNECTIONS", 1000),
+		EventStreamMaxConnectionsPerCPF: getEnvAsIntOrDefault("EVENT_STREAM_MAX_CONNECTIONS_PER_CPF", 5),
+		EventStreamIdleTimeout:          eventStreamIdleTimeout,
+
+		// Maintenance request pagination configuration
+		MaintenanceRequestMaxPage: getEnvAsIntOrDefault("MAINTENANCE_REQUEST_MAX_PAGE", 0),
+
+		// Profile completeness configuration
+		CompletenessWeights: completenessWeights,
+
+		// Notification target configuration
+		NotificationPhoneFallback: notificationPhoneFallback,
+
+		// Self-declared write durability configuration
+		CriticalSelfDeclaredFields: criticalSelfDeclaredFields,
 
 		// MCP Server configuration
 		MCPServerURL:            mcpServerURL,
@@ -396,6 +1308,27 @@ func LoadConfig() error {
 		CFLookupRateLimit:       cfLookupRateLimit,
 		CFLookupGlobalRateLimit: cfLookupGlobalRateLimit,
 		CFLookupSyncTimeout:     cfLookupSyncTimeout,
+		CFLookupSyncEnabled:     cfLookupSyncEnabled,
+		CFLookupMaxAge:          cfLookupMaxAge,
+		CFLookupNoEquipmentTTL:  cfLookupNoEquipmentTTL,
+		CFCoverageStatsCacheTTL: cfCoverageStatsCacheTTL,
+		CFCircuitFailureRatio:   cfCircuitFailureRatio,
+		CFCircuitMinRequests:    cfCircuitMinRequests,
+		CFCircuitOpenTimeout:    cfCircuitOpenTimeout,
+
+		// Address change webhook configuration
+		AddressWebhookEnabled:    addressWebhookEnabled,
+		AddressWebhookURL:        addressWebhookURL,
+		AddressWebhookSecret:     addressWebhookSecret,
+		AddressWebhookTimeout:    addressWebhookTimeout,
+		AddressWebhookMaxRetries: getEnvAsIntOrDefault("ADDRESS_WEBHOOK_MAX_RETRIES", 5),
+
+		// Phone verified webhook configuration
+		PhoneVerifiedWebhookEnabled:    phoneVerifiedWebhookEnabled,
+		PhoneVerifiedWebhookURL:        phoneVerifiedWebhookURL,
+		PhoneVerifiedWebhookSecret:     phoneVerifiedWebhookSecret,
+		PhoneVerifiedWebhookTimeout:    phoneVerifiedWebhookTimeout,
+		PhoneVerifiedWebhookMaxRetries: getEnvAsIntOrDefault("PHONE_VERIFIED_WEBHOOK_MAX_RETRIES", 5),
 
 		// WhatsApp configuration
 		WhatsAppEnabled:      whatsappEnabledBool,
@@ -410,27 +1343,93 @@ func LoadConfig() error {
 		TracingEnabled:  getEnvOrDefault("TRACING_ENABLED", "false") == "true",
 		TracingEndpoint: getEnvOrDefault("TRACING_ENDPOINT", "localhost:4317"),
 
+		// Logging configuration
+		LogPIIMasking: logPIIMasking,
+
 		// Audit logging configuration
 		AuditLogsEnabled: getEnvOrDefault("AUDIT_LOGS_ENABLED", "true") == "true",
 
 		// Audit worker configuration
-		AuditWorkerCount: getEnvAsIntOrDefault("AUDIT_WORKER_COUNT", 20),
-		AuditBufferSize:  getEnvAsIntOrDefault("AUDIT_BUFFER_SIZE", 10000),
+		AuditWorkerCount:       getEnvAsIntOrDefault("AUDIT_WORKER_COUNT", 20),
+		AuditBufferSize:        getEnvAsIntOrDefault("AUDIT_BUFFER_SIZE", 10000),
+		AuditCoalescingEnabled: getEnvOrDefault("AUDIT_COALESCING_ENABLED", "true") == "true",
+		AuditBatchSize:         getEnvAsIntOrDefault("AUDIT_BATCH_SIZE", 100),
+		AuditFlushInterval:     auditFlushInterval,
+		AuditOverflowPolicy:    auditOverflowPolicy,
 
 		// Verification queue configuration
 		VerificationWorkerCount: getEnvAsIntOrDefault("VERIFICATION_WORKER_COUNT", 10),
 		VerificationQueueSize:   getEnvAsIntOrDefault("VERIFICATION_QUEUE_SIZE", 5000),
 
 		// Database worker configuration
-		DBWorkerCount: getEnvAsIntOrDefault("DB_WORKER_COUNT", 10),
-		DBBatchSize:   getEnvAsIntOrDefault("DB_BATCH_SIZE", 100),
+		DBWorkerCount:    getEnvAsIntOrDefault("DB_WORKER_COUNT", 10),
+		DBBatchSize:      getEnvAsIntOrDefault("DB_BATCH_SIZE", 100),
+		SyncFlushTimeout: syncFlushTimeout,
+		SyncWorkerPools:  syncWorkerPools,
+
+		// Write buffer reconciliation configuration
+		WriteBufferReconcileInterval:   writeBufferReconcileInterval,
+		WriteBufferReconcileStaleAfter: writeBufferReconcileStaleAfter,
+		WriteBufferReconcileBatchSize:  getEnvAsIntOrDefault("WRITE_BUFFER_RECONCILE_BATCH_SIZE", 500),
 
 		// Authorization configuration
-		AdminGroup:            getEnvOrDefault("ADMIN_GROUP", "heimdall-admin"),
-		TrustedServiceClients: parseCommaSeparatedList(getEnvOrDefault("TRUSTED_SERVICE_CLIENTS", "")),
+		AdminGroup:                        getEnvOrDefault("ADMIN_GROUP", "heimdall-admin"),
+		TrustedServiceClients:             parseCommaSeparatedList(getEnvOrDefault("TRUSTED_SERVICE_CLIENTS", "")),
+		MaskedResponseScopes:              parseCommaSeparatedList(getEnvOrDefault("MASKED_RESPONSE_SCOPES", "")),
+		QuarantineExportUnmaskedScopes:    parseCommaSeparatedList(getEnvOrDefault("QUARANTINE_EXPORT_UNMASKED_SCOPES", "")),
+		BetaWhitelistExportUnmaskedScopes: parseCommaSeparatedList(getEnvOrDefault("BETA_WHITELIST_EXPORT_UNMASKED_SCOPES", "")),
+
+		// Service token configuration
+		ServiceTokenIssuer:       serviceTokenIssuer,
+		ServiceTokenJWKSURL:      serviceTokenJWKSURL,
+		ServiceTokenAudience:     getEnvOrDefault("SERVICE_TOKEN_AUDIENCE", ""),
+		ServiceTokenJWKSCacheTTL: serviceTokenJWKSCacheTTL,
 
 		// Index maintenance configuration
 		IndexMaintenanceInterval: indexMaintenanceInterval,
+
+		// Data freshness report configuration
+		DataFreshnessInterval:    dataFreshnessInterval,
+		DataFreshnessCollections: dataFreshnessCollections,
+		DataFreshnessSampleSize:  dataFreshnessSampleSize,
+
+		// Static lists configuration
+		StaticListsRefreshInterval: staticListsRefreshInterval,
+
+		// Request ID configuration
+		TrustInboundRequestID: getEnvOrDefault("TRUST_INBOUND_REQUEST_ID", "true") == "true",
+
+		// Trusted proxies configuration
+		TrustedProxies: trustedProxies,
+
+		// CORS configuration
+		CORSAllowedOrigins:   corsAllowedOrigins,
+		CORSAllowedMethods:   parseCommaSeparatedList(getEnvOrDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")),
+		CORSAllowedHeaders:   parseCommaSeparatedList(getEnvOrDefault("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-Request-ID,Idempotency-Key,If-Match,If-None-Match")),
+		CORSAllowCredentials: getEnvOrDefault("CORS_ALLOW_CREDENTIALS", "false") == "true",
+		CORSMaxAge:           corsMaxAge,
+
+		// Response compression configuration
+		CompressionEnabled:       getEnvOrDefault("COMPRESSION_ENABLED", "true") == "true",
+		CompressionMinSize:       compressionMinSize,
+		CompressionGzipLevel:     compressionGzipLevel,
+		CompressionBrotliLevel:   compressionBrotliLevel,
+		CompressionExcludedPaths: parseCommaSeparatedList(getEnvOrDefault("COMPRESSION_EXCLUDED_PATHS", "/metrics,/v1/metrics")),
+
+		// Request body size limits
+		MaxRequestBodySize:      maxRequestBodySize,
+		MaxAvatarBodySize:       maxAvatarBodySize,
+		MaxSelfDeclaredBodySize: maxSelfDeclaredBodySize,
+
+		// Outbound HTTP client configuration
+		OutboundHTTPTimeout:               outboundHTTPTimeout,
+		OutboundHTTPDialTimeout:           outboundHTTPDialTimeout,
+		OutboundHTTPTLSHandshakeTimeout:   outboundHTTPTLSHandshakeTimeout,
+		OutboundHTTPResponseHeaderTimeout: outboundHTTPResponseHeaderTimeout,
+		OutboundHTTPIdleConnTimeout:       outboundHTTPIdleConnTimeout,
+		OutboundHTTPMaxIdleConns:          outboundHTTPMaxIdleConns,
+		OutboundHTTPMaxIdleConnsPerHost:   outboundHTTPMaxIdleConnsPerHost,
+		OutboundHTTPMaxConnsPerHost:       outboundHTTPMaxConnsPerHost,
 	}
 
 	return nil
@@ -464,6 +1463,223 @@ func getEnvAsDurationOrDefault(key string, defaultValue time.Duration) time.Dura
 	return defaultValue
 }
 
+// parseCompletenessWeights parses "field=weight" pairs separated by commas. Fields left out weigh
+// nothing; at least one weight must be positive.
+func parseCompletenessWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int, len(CompletenessFields))
+	total := 0
+	for _, entry := range parseCommaSeparatedList(value) {
+		field, rawWeight, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be in the form field=weight", entry)
+		}
+		field = strings.TrimSpace(field)
+		if !slices.Contains(CompletenessFields, field) {
+			return nil, fmt.Errorf("unknown field %q (must be one of %s)", field, strings.Join(CompletenessFields, ", "))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(rawWeight))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight of %q must be a non-negative integer", field)
+		}
+		weights[field] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one field must have a positive weight")
+	}
+	return weights, nil
+}
+
+// parseSyncWorkerPools parses the comma-separated queue=workers entries giving sync queues their
+// own worker pool. Queue names are checked by the sync service, which knows the queues.
+func parseSyncWorkerPools(value string) (map[string]int, error) {
+	pools := make(map[string]int)
+	for _, entry := range parseCommaSeparatedList(value) {
+		queue, rawWorkers, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be in the form queue=workers", entry)
+		}
+		queue = strings.TrimSpace(queue)
+		if _, ok := pools[queue]; ok {
+			return nil, fmt.Errorf("queue %q is listed more than once", queue)
+		}
+		workers, err := strconv.Atoi(strings.TrimSpace(rawWorkers))
+		if err != nil || workers <= 0 {
+			return nil, fmt.Errorf("workers of %q must be a positive integer", queue)
+		}
+		pools[queue] = workers
+	}
+	return pools, nil
+}
+
+// isValidExternalIDSystem reports whether system can be used as an external_ids key in MongoDB
+// field paths and index names
+func isValidExternalIDSystem(system string) bool {
+	for _, r := range system {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseValidationRules parses comma-separated rule=mode pairs (e.g. "address_uf=enforce").
+// Rules not listed keep their default mode.
+func parseValidationRules(value string) (map[string]string, error) {
+	rules := make(map[string]string, len(defaultValidationRules))
+	for rule, mode := range defaultValidationRules {
+		rules[rule] = mode
+	}
+	for _, entry := range parseCommaSeparatedList(value) {
+		rule, mode, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be in the form rule=mode", entry)
+		}
+		rule, mode = strings.TrimSpace(rule), strings.TrimSpace(mode)
+		if _, ok := defaultValidationRules[rule]; !ok {
+			return nil, fmt.Errorf("unknown rule %q", rule)
+		}
+		if mode != ValidationRuleModeOff && mode != ValidationRuleModeShadow && mode != ValidationRuleModeEnforce {
+			return nil, fmt.Errorf("mode of %q must be %q, %q or %q", rule, ValidationRuleModeOff, ValidationRuleModeShadow, ValidationRuleModeEnforce)
+		}
+		rules[rule] = mode
+	}
+	return rules, nil
+}
+
+// ValidationRuleMode returns the configured mode of rule, or its default when the
+// configuration has not been loaded
+func ValidationRuleMode(rule string) string {
+	if AppConfig != nil {
+		if mode, ok := AppConfig.ValidationRules[rule]; ok {
+			return mode
+		}
+	}
+	return defaultValidationRules[rule]
+}
+
+// parseCacheTTL parses comma-separated namespace=duration pairs (e.g. "citizen=30m,memory=2h").
+// An empty value means every namespace uses REDIS_TTL.
+func parseCacheTTL(value string) (CacheTTLConfig, error) {
+	ttls := make(CacheTTLConfig)
+	for _, entry := range parseCommaSeparatedList(value) {
+		namespace, rawTTL, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be in the form namespace=duration", entry)
+		}
+		namespace = strings.TrimSpace(namespace)
+		if !slices.Contains(CacheNamespaces, namespace) {
+			return nil, fmt.Errorf("unknown namespace %q (must be one of %s)", namespace, strings.Join(CacheNamespaces, ", "))
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(rawTTL))
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("TTL of %q must be a positive duration", namespace)
+		}
+		ttls[namespace] = ttl
+	}
+	return ttls, nil
+}
+
+// parseMongoReadPreferences parses comma-separated collection=mode pairs
+// (e.g. "citizens=primary,maintenance_requests=secondaryPreferred"). Modes are the MongoDB read
+// preference modes, case-insensitive; collections without an entry use the client default.
+func parseMongoReadPreferences(value string) (map[string]string, error) {
+	prefs := make(map[string]string)
+	for _, entry := range parseCommaSeparatedList(value) {
+		collection, rawMode, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("entry %q must be in the form collection=mode", entry)
+		}
+		collection = strings.TrimSpace(collection)
+		if collection == "" {
+			return nil, fmt.Errorf("entry %q has an empty collection", entry)
+		}
+		mode, err := readpref.ModeFromString(strings.TrimSpace(rawMode))
+		if err != nil {
+			return nil, fmt.Errorf("read preference of %q: %w", collection, err)
+		}
+		prefs[collection] = mode.String()
+	}
+	return prefs, nil
+}
+
+// CacheTTLFor returns the TTL for entries of the given cache namespace, defaulting to RedisTTL
+func CacheTTLFor(namespace string) time.Duration {
+	return AppConfig.CacheTTL.TTL(namespace, AppConfig.RedisTTL)
+}
+
+// parseNotificationPhoneFallback parses the comma-separated phone sources, in priority order.
+// Each source may appear once and at least one is required.
+func parseNotificationPhoneFallback(value string) ([]string, error) {
+	sources := parseCommaSeparatedList(value)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one phone source is required")
+	}
+	for i, source := range sources {
+		if source != NotificationPhoneSourceSelfDeclared && source != NotificationPhoneSourceBase {
+			return nil, fmt.Errorf("unknown phone source %q (must be %q or %q)", source, NotificationPhoneSourceSelfDeclared, NotificationPhoneSourceBase)
+		}
+		if slices.Contains(sources[:i], source) {
+			return nil, fmt.Errorf("phone source %q is listed more than once", source)
+		}
+	}
+	return sources, nil
+}
+
+// parseCriticalSelfDeclaredFields parses the comma-separated self-declared fields whose writes
+// need majority durability. An empty list keeps every field on the buffered write path.
+func parseCriticalSelfDeclaredFields(value string) ([]string, error) {
+	fields := parseCommaSeparatedList(value)
+	for _, field := range fields {
+		if !slices.Contains(SelfDeclaredFields, field) {
+			return nil, fmt.Errorf("unknown self-declared field %q", field)
+		}
+	}
+	return fields, nil
+}
+
+// parseBodySizeEnv parses a request body size limit in bytes from env key (0 disables the limit)
+func parseBodySizeEnv(key, defaultValue string) (int64, error) {
+	size, err := strconv.ParseInt(getEnvOrDefault(key, defaultValue), 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s: must be a non-negative number of bytes", key)
+	}
+	return size, nil
+}
+
+// parseTrustedProxies parses TRUSTED_PROXIES, a comma-separated list of IPs or CIDRs
+func parseTrustedProxies(value string) ([]string, error) {
+	proxies := parseCommaSeparatedList(value)
+	for _, proxy := range proxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return nil, fmt.Errorf("%q is not an IP or CIDR", proxy)
+		}
+	}
+	return proxies, nil
+}
+
+// parseCORSAllowedOrigins parses CORS_ALLOWED_ORIGINS, a comma-separated list of exact origins
+// (scheme://host[:port]). Wildcards are not accepted; permissive CORS is only available in
+// development, by leaving the list empty.
+func parseCORSAllowedOrigins(value string) ([]string, error) {
+	origins := parseCommaSeparatedList(value)
+	for _, origin := range origins {
+		if strings.Contains(origin, "*") {
+			return nil, fmt.Errorf("wildcard origin %q is not allowed", origin)
+		}
+		if !strings.HasPrefix(origin, "https://") && !strings.HasPrefix(origin, "http://") {
+			return nil, fmt.Errorf("origin %q must start with http:// or https://", origin)
+		}
+		if strings.HasSuffix(origin, "/") {
+			return nil, fmt.Errorf("origin %q must not end with a slash", origin)
+		}
+	}
+	return origins, nil
+}
+
 // parseCommaSeparatedList parses a comma-separated string into a slice of strings
 func parseCommaSeparatedList(value string) []string {
 	parts := strings.Split(value, ",")
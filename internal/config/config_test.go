@@ -1,7 +1,9 @@
 package config
 
 import (
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -708,6 +710,21 @@ func TestLoadConfig_InvalidPhoneQuarantineTTL(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_InvalidPhoneQuarantineSweepInterval(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("PHONE_QUARANTINE_SWEEP_INTERVAL", "invalid")
+	defer os.Unsetenv("PHONE_QUARANTINE_SWEEP_INTERVAL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid PHONE_QUARANTINE_SWEEP_INTERVAL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid PHONE_QUARANTINE_SWEEP_INTERVAL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid PHONE_QUARANTINE_SWEEP_INTERVAL'", err)
+	}
+}
+
 func TestLoadConfig_InvalidBetaStatusCacheTTL(t *testing.T) {
 	setupMinimalEnv(t)
 	os.Setenv("BETA_STATUS_CACHE_TTL", "invalid")
@@ -723,6 +740,48 @@ func TestLoadConfig_InvalidBetaStatusCacheTTL(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_BetaGroupStats(t *testing.T) {
+	setupMinimalEnv(t)
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.BetaGroupStatsCacheTTL != 5*time.Minute {
+		t.Errorf("BetaGroupStatsCacheTTL = %v, want 5m", AppConfig.BetaGroupStatsCacheTTL)
+	}
+	if AppConfig.BetaGroupStatsRecentWindow != 7*24*time.Hour {
+		t.Errorf("BetaGroupStatsRecentWindow = %v, want 168h", AppConfig.BetaGroupStatsRecentWindow)
+	}
+}
+
+func TestLoadConfig_InvalidBetaGroupStats(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"BETA_GROUP_STATS_CACHE_TTL", "invalid"},
+		{"BETA_GROUP_STATS_CACHE_TTL", "0s"},
+		{"BETA_GROUP_STATS_RECENT_WINDOW", "invalid"},
+		{"BETA_GROUP_STATS_RECENT_WINDOW", "-1h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			err := LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() should return error for %s=%s", tt.env, tt.value)
+			}
+			if !strings.Contains(err.Error(), "invalid "+tt.env) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.env)
+			}
+		})
+	}
+}
+
 func TestLoadConfig_InvalidSelfDeclaredOutdatedThreshold(t *testing.T) {
 	setupMinimalEnv(t)
 	os.Setenv("SELF_DECLARED_OUTDATED_THRESHOLD", "invalid")
@@ -783,6 +842,157 @@ func TestLoadConfig_InvalidNotificationCategoryCacheTTL(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_InvalidLegalEntityCacheTTL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("LEGAL_ENTITY_CACHE_TTL", "invalid")
+	defer os.Unsetenv("LEGAL_ENTITY_CACHE_TTL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid LEGAL_ENTITY_CACHE_TTL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid LEGAL_ENTITY_CACHE_TTL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid LEGAL_ENTITY_CACHE_TTL'", err)
+	}
+}
+
+func TestLoadConfig_InvalidUserConfigWriteMode(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("USER_CONFIG_WRITE_MODE", "invalid")
+	defer os.Unsetenv("USER_CONFIG_WRITE_MODE")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid USER_CONFIG_WRITE_MODE")
+	}
+
+	if !strings.Contains(err.Error(), "invalid USER_CONFIG_WRITE_MODE") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid USER_CONFIG_WRITE_MODE'", err)
+	}
+}
+
+func TestLoadConfig_UserConfigWriteModeDefault(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("USER_CONFIG_WRITE_MODE")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if AppConfig.UserConfigWriteMode != UserConfigWriteModeField {
+		t.Errorf("UserConfigWriteMode = %q, want %q", AppConfig.UserConfigWriteMode, UserConfigWriteModeField)
+	}
+}
+
+func TestLoadConfig_TrustInboundRequestID(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("TRUST_INBOUND_REQUEST_ID")
+	defer os.Unsetenv("TRUST_INBOUND_REQUEST_ID")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.TrustInboundRequestID {
+		t.Error("TrustInboundRequestID should default to true")
+	}
+
+	os.Setenv("TRUST_INBOUND_REQUEST_ID", "false")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.TrustInboundRequestID {
+		t.Error("TrustInboundRequestID = true, want false")
+	}
+}
+
+func TestLoadConfig_MongoDegradedReads(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("MONGODB_DEGRADED_READS_ENABLED")
+	defer os.Unsetenv("MONGODB_DEGRADED_READS_ENABLED")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.MongoDegradedReadsEnabled {
+		t.Error("MongoDegradedReadsEnabled should default to false")
+	}
+
+	os.Setenv("MONGODB_DEGRADED_READS_ENABLED", "true")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.MongoDegradedReadsEnabled {
+		t.Error("MongoDegradedReadsEnabled = false, want true")
+	}
+}
+
+func TestLoadConfig_CompletenessWeightsDefault(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("COMPLETENESS_WEIGHTS")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	total := 0
+	for _, field := range CompletenessFields {
+		weight, ok := AppConfig.CompletenessWeights[field]
+		if !ok {
+			t.Errorf("CompletenessWeights missing default weight for %q", field)
+		}
+		total += weight
+	}
+	if total != 100 {
+		t.Errorf("default CompletenessWeights sum = %d, want 100", total)
+	}
+}
+
+func TestLoadConfig_InvalidCompletenessWeights(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unknown field", "telefone=50,cnh=50"},
+		{"missing weight", "telefone"},
+		{"negative weight", "telefone=-10,email=20"},
+		{"non-numeric weight", "telefone=abc"},
+		{"all zero", "telefone=0,email=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv("COMPLETENESS_WEIGHTS", tt.value)
+			defer os.Unsetenv("COMPLETENESS_WEIGHTS")
+
+			err := LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() should return error for COMPLETENESS_WEIGHTS=%q", tt.value)
+			}
+			if !strings.Contains(err.Error(), "invalid COMPLETENESS_WEIGHTS") {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid COMPLETENESS_WEIGHTS'", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CustomCompletenessWeights(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("COMPLETENESS_WEIGHTS", " telefone = 60 , email=40")
+	defer os.Unsetenv("COMPLETENESS_WEIGHTS")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CompletenessWeights[CompletenessFieldTelefone] != 60 || AppConfig.CompletenessWeights[CompletenessFieldEmail] != 40 {
+		t.Errorf("CompletenessWeights = %v, want telefone=60 and email=40", AppConfig.CompletenessWeights)
+	}
+	if _, ok := AppConfig.CompletenessWeights[CompletenessFieldAvatar]; ok {
+		t.Error("fields left out of COMPLETENESS_WEIGHTS should not be weighted")
+	}
+}
+
 func TestLoadConfig_CFLookupEnabledWithoutMCPServer(t *testing.T) {
 	setupMinimalEnv(t)
 	os.Setenv("CF_LOOKUP_ENABLED", "true")
@@ -877,6 +1087,100 @@ func TestLoadConfig_InvalidCFLookupSyncTimeout(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_AddressWebhookEnabledWithoutURL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("ADDRESS_WEBHOOK_ENABLED", "true")
+	os.Unsetenv("ADDRESS_WEBHOOK_URL")
+	defer os.Unsetenv("ADDRESS_WEBHOOK_ENABLED")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error when ADDRESS_WEBHOOK_ENABLED=true but ADDRESS_WEBHOOK_URL is missing")
+	}
+
+	if !strings.Contains(err.Error(), "ADDRESS_WEBHOOK_URL is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'ADDRESS_WEBHOOK_URL is required'", err)
+	}
+}
+
+func TestLoadConfig_AddressWebhookEnabledWithoutSecret(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("ADDRESS_WEBHOOK_ENABLED", "true")
+	os.Setenv("ADDRESS_WEBHOOK_URL", "https://hooks.example.com/address")
+	os.Unsetenv("ADDRESS_WEBHOOK_SECRET")
+	defer os.Unsetenv("ADDRESS_WEBHOOK_ENABLED")
+	defer os.Unsetenv("ADDRESS_WEBHOOK_URL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error when ADDRESS_WEBHOOK_ENABLED=true but ADDRESS_WEBHOOK_SECRET is missing")
+	}
+
+	if !strings.Contains(err.Error(), "ADDRESS_WEBHOOK_SECRET is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'ADDRESS_WEBHOOK_SECRET is required'", err)
+	}
+}
+
+func TestLoadConfig_InvalidAddressWebhookTimeout(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("ADDRESS_WEBHOOK_TIMEOUT", "invalid")
+	defer os.Unsetenv("ADDRESS_WEBHOOK_TIMEOUT")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid ADDRESS_WEBHOOK_TIMEOUT")
+	}
+
+	if !strings.Contains(err.Error(), "invalid ADDRESS_WEBHOOK_TIMEOUT") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid ADDRESS_WEBHOOK_TIMEOUT'", err)
+	}
+}
+
+func TestLoadConfig_InvalidCFLookupMaxAge(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("CF_LOOKUP_MAX_AGE", "invalid")
+	defer os.Unsetenv("CF_LOOKUP_MAX_AGE")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid CF_LOOKUP_MAX_AGE")
+	}
+
+	if !strings.Contains(err.Error(), "invalid CF_LOOKUP_MAX_AGE") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid CF_LOOKUP_MAX_AGE'", err)
+	}
+}
+
+func TestLoadConfig_InvalidCFLookupNoEquipmentTTL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("CF_LOOKUP_NO_EQUIPMENT_TTL", "invalid")
+	defer os.Unsetenv("CF_LOOKUP_NO_EQUIPMENT_TTL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid CF_LOOKUP_NO_EQUIPMENT_TTL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid CF_LOOKUP_NO_EQUIPMENT_TTL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid CF_LOOKUP_NO_EQUIPMENT_TTL'", err)
+	}
+}
+
+func TestLoadConfig_InvalidCFCoverageStatsCacheTTL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("CF_COVERAGE_STATS_CACHE_TTL", "invalid")
+	defer os.Unsetenv("CF_COVERAGE_STATS_CACHE_TTL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid CF_COVERAGE_STATS_CACHE_TTL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid CF_COVERAGE_STATS_CACHE_TTL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid CF_COVERAGE_STATS_CACHE_TTL'", err)
+	}
+}
+
 func TestLoadConfig_InvalidWhatsAppEnabled(t *testing.T) {
 	setupMinimalEnv(t)
 	os.Setenv("WHATSAPP_ENABLED", "invalid")
@@ -991,6 +1295,36 @@ func TestLoadConfig_InvalidIndexMaintenanceInterval(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_InvalidStaticListsRefreshInterval(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("STATIC_LISTS_REFRESH_INTERVAL", "invalid")
+	defer os.Unsetenv("STATIC_LISTS_REFRESH_INTERVAL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid STATIC_LISTS_REFRESH_INTERVAL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid STATIC_LISTS_REFRESH_INTERVAL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid STATIC_LISTS_REFRESH_INTERVAL'", err)
+	}
+}
+
+func TestLoadConfig_InvalidSyncFlushTimeout(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("SYNC_FLUSH_TIMEOUT", "invalid")
+	defer os.Unsetenv("SYNC_FLUSH_TIMEOUT")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Error("LoadConfig() should return error for invalid SYNC_FLUSH_TIMEOUT")
+	}
+
+	if !strings.Contains(err.Error(), "invalid SYNC_FLUSH_TIMEOUT") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid SYNC_FLUSH_TIMEOUT'", err)
+	}
+}
+
 func TestLoadConfig_RedisClusterWithoutAddresses(t *testing.T) {
 	setupMinimalEnv(t)
 	os.Setenv("REDIS_CLUSTER_ENABLED", "true")
@@ -1176,3 +1510,1306 @@ func setupMinimalEnv(t *testing.T) {
 		os.Unsetenv("CF_LOOKUP_ENABLED")
 	})
 }
+
+func TestLoadConfig_NotificationPhoneFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string // empty leaves the variable unset
+		want  []string
+	}{
+		{"default", "", []string{NotificationPhoneSourceSelfDeclared, NotificationPhoneSourceBase}},
+		{"self-declared only", "self_declared", []string{NotificationPhoneSourceSelfDeclared}},
+		{"base first", " base , self_declared ", []string{NotificationPhoneSourceBase, NotificationPhoneSourceSelfDeclared}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Unsetenv("NOTIFICATION_PHONE_FALLBACK")
+			if tt.value != "" {
+				os.Setenv("NOTIFICATION_PHONE_FALLBACK", tt.value)
+				defer os.Unsetenv("NOTIFICATION_PHONE_FALLBACK")
+			}
+
+			if err := LoadConfig(); err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if !slices.Equal(AppConfig.NotificationPhoneFallback, tt.want) {
+				t.Errorf("NotificationPhoneFallback = %v, want %v", AppConfig.NotificationPhoneFallback, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidNotificationPhoneFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", " , "},
+		{"unknown source", "self_declared,whatsapp"},
+		{"duplicated source", "base,base"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv("NOTIFICATION_PHONE_FALLBACK", tt.value)
+			defer os.Unsetenv("NOTIFICATION_PHONE_FALLBACK")
+
+			err := LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() should return error for NOTIFICATION_PHONE_FALLBACK=%q", tt.value)
+			}
+			if !strings.Contains(err.Error(), "invalid NOTIFICATION_PHONE_FALLBACK") {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid NOTIFICATION_PHONE_FALLBACK'", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CriticalSelfDeclaredFields(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("CRITICAL_SELF_DECLARED_FIELDS")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(AppConfig.CriticalSelfDeclaredFields) != 0 {
+		t.Errorf("CriticalSelfDeclaredFields = %v, want none by default", AppConfig.CriticalSelfDeclaredFields)
+	}
+
+	os.Setenv("CRITICAL_SELF_DECLARED_FIELDS", " nome_exibicao , endereco ")
+	defer os.Unsetenv("CRITICAL_SELF_DECLARED_FIELDS")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if want := []string{"nome_exibicao", "endereco"}; !slices.Equal(AppConfig.CriticalSelfDeclaredFields, want) {
+		t.Errorf("CriticalSelfDeclaredFields = %v, want %v", AppConfig.CriticalSelfDeclaredFields, want)
+	}
+
+	os.Setenv("CRITICAL_SELF_DECLARED_FIELDS", "endereco,nome_civil")
+	err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "invalid CRITICAL_SELF_DECLARED_FIELDS") {
+		t.Errorf("LoadConfig() error = %v, want error for unknown field", err)
+	}
+}
+
+func TestLoadConfig_SyncWorkerPools(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("SYNC_WORKER_POOLS")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if want := map[string]int{"cf_lookup": 2}; !maps.Equal(AppConfig.SyncWorkerPools, want) {
+		t.Errorf("SyncWorkerPools = %v, want %v by default", AppConfig.SyncWorkerPools, want)
+	}
+
+	os.Setenv("SYNC_WORKER_POOLS", " cf_lookup = 4 , self_declared_address=1")
+	defer os.Unsetenv("SYNC_WORKER_POOLS")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if want := map[string]int{"cf_lookup": 4, "self_declared_address": 1}; !maps.Equal(AppConfig.SyncWorkerPools, want) {
+		t.Errorf("SyncWorkerPools = %v, want %v", AppConfig.SyncWorkerPools, want)
+	}
+
+	for _, value := range []string{"cf_lookup", "cf_lookup=0", "cf_lookup=two", "cf_lookup=1,cf_lookup=2"} {
+		os.Setenv("SYNC_WORKER_POOLS", value)
+		err := LoadConfig()
+		if err == nil || !strings.Contains(err.Error(), "invalid SYNC_WORKER_POOLS") {
+			t.Errorf("LoadConfig() with %q error = %v, want invalid SYNC_WORKER_POOLS", value, err)
+		}
+	}
+}
+
+func TestLoadConfig_EmailVerificationEnabledWithoutWebhookURL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("EMAIL_VERIFICATION_ENABLED", "true")
+	os.Unsetenv("EMAIL_VERIFICATION_CHANNEL")
+	os.Unsetenv("EMAIL_VERIFICATION_WEBHOOK_URL")
+	defer os.Unsetenv("EMAIL_VERIFICATION_ENABLED")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() should return error when EMAIL_VERIFICATION_ENABLED=true but EMAIL_VERIFICATION_WEBHOOK_URL is missing")
+	}
+
+	if !strings.Contains(err.Error(), "EMAIL_VERIFICATION_WEBHOOK_URL is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'EMAIL_VERIFICATION_WEBHOOK_URL is required'", err)
+	}
+}
+
+func TestLoadConfig_EmailVerificationEnabledWithLogChannel(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("EMAIL_VERIFICATION_ENABLED", "true")
+	os.Setenv("EMAIL_VERIFICATION_CHANNEL", "log")
+	os.Unsetenv("EMAIL_VERIFICATION_WEBHOOK_URL")
+	defer os.Unsetenv("EMAIL_VERIFICATION_ENABLED")
+	defer os.Unsetenv("EMAIL_VERIFICATION_CHANNEL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.EmailVerificationEnabled {
+		t.Error("EmailVerificationEnabled = false, want true")
+	}
+	if AppConfig.EmailVerificationChannel != EmailVerificationChannelLog {
+		t.Errorf("EmailVerificationChannel = %q, want %q", AppConfig.EmailVerificationChannel, EmailVerificationChannelLog)
+	}
+}
+
+func TestLoadConfig_EmailVerificationLinkRequiresSecretAndRedirect(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("EMAIL_VERIFICATION_LINK_URL", "https://api.example.com/v1/verify/email")
+	os.Unsetenv("EMAIL_VERIFICATION_LINK_SECRET")
+	os.Unsetenv("EMAIL_VERIFICATION_REDIRECT_URL")
+	defer os.Unsetenv("EMAIL_VERIFICATION_LINK_URL")
+
+	err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "EMAIL_VERIFICATION_LINK_SECRET is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'EMAIL_VERIFICATION_LINK_SECRET is required'", err)
+	}
+
+	os.Setenv("EMAIL_VERIFICATION_LINK_SECRET", "link-secret")
+	defer os.Unsetenv("EMAIL_VERIFICATION_LINK_SECRET")
+	err = LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "EMAIL_VERIFICATION_REDIRECT_URL is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'EMAIL_VERIFICATION_REDIRECT_URL is required'", err)
+	}
+
+	os.Setenv("EMAIL_VERIFICATION_REDIRECT_URL", "rioapp://email-verified")
+	defer os.Unsetenv("EMAIL_VERIFICATION_REDIRECT_URL")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.EmailVerificationRedirectURL != "rioapp://email-verified" {
+		t.Errorf("EmailVerificationRedirectURL = %q, want rioapp://email-verified", AppConfig.EmailVerificationRedirectURL)
+	}
+}
+
+func TestLoadConfig_InvalidEmailVerificationChannel(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("EMAIL_VERIFICATION_CHANNEL", "smtp")
+	defer os.Unsetenv("EMAIL_VERIFICATION_CHANNEL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() should return error for invalid EMAIL_VERIFICATION_CHANNEL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid EMAIL_VERIFICATION_CHANNEL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid EMAIL_VERIFICATION_CHANNEL'", err)
+	}
+}
+
+func TestLoadConfig_InvalidEmailVerificationTTL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("EMAIL_VERIFICATION_TTL", "invalid")
+	defer os.Unsetenv("EMAIL_VERIFICATION_TTL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() should return error for invalid EMAIL_VERIFICATION_TTL")
+	}
+
+	if !strings.Contains(err.Error(), "invalid EMAIL_VERIFICATION_TTL") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid EMAIL_VERIFICATION_TTL'", err)
+	}
+}
+
+func TestLoadConfig_CacheTTLPerNamespace(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("REDIS_TTL", "60m")
+	os.Setenv("CACHE_TTL", "citizen=30m, maintenance_requests=5m")
+	defer os.Unsetenv("REDIS_TTL")
+	defer os.Unsetenv("CACHE_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		namespace string
+		want      time.Duration
+	}{
+		{CacheNamespaceCitizen, 30 * time.Minute},
+		{CacheNamespaceMaintenanceRequests, 5 * time.Minute},
+		{CacheNamespaceMemory, 60 * time.Minute}, // not listed, falls back to REDIS_TTL
+	}
+	for _, tt := range tests {
+		if got := CacheTTLFor(tt.namespace); got != tt.want {
+			t.Errorf("CacheTTLFor(%q) = %v, want %v", tt.namespace, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_CacheTTLDefault(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("CACHE_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	for _, namespace := range CacheNamespaces {
+		if got := CacheTTLFor(namespace); got != AppConfig.RedisTTL {
+			t.Errorf("CacheTTLFor(%q) = %v, want RedisTTL %v", namespace, got, AppConfig.RedisTTL)
+		}
+	}
+}
+
+func TestLoadConfig_InvalidCacheTTL(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unknown namespace", "wallet=10m"},
+		{"missing duration", "citizen"},
+		{"invalid duration", "citizen=abc"},
+		{"zero duration", "citizen=0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv("CACHE_TTL", tt.value)
+			defer os.Unsetenv("CACHE_TTL")
+
+			err := LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() should return error for CACHE_TTL=%q", tt.value)
+			}
+			if !strings.Contains(err.Error(), "invalid CACHE_TTL") {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid CACHE_TTL'", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ValidationRulesDefault(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("VALIDATION_RULES")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	want := map[string]string{
+		ValidationRuleAddressCEP:  ValidationRuleModeShadow,
+		ValidationRuleAddressUF:   ValidationRuleModeShadow,
+		ValidationRuleEmailDomain: ValidationRuleModeShadow,
+		ValidationRulePhoneDDD:    ValidationRuleModeEnforce,
+	}
+	for rule, mode := range want {
+		if got := ValidationRuleMode(rule); got != mode {
+			t.Errorf("ValidationRuleMode(%q) = %q, want %q", rule, got, mode)
+		}
+	}
+}
+
+func TestLoadConfig_ValidationRulesOverride(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("VALIDATION_RULES", "address_uf=enforce, phone_ddd=shadow")
+	defer os.Unsetenv("VALIDATION_RULES")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if got := ValidationRuleMode(ValidationRuleAddressUF); got != ValidationRuleModeEnforce {
+		t.Errorf("ValidationRuleMode(address_uf) = %q, want %q", got, ValidationRuleModeEnforce)
+	}
+	if got := ValidationRuleMode(ValidationRulePhoneDDD); got != ValidationRuleModeShadow {
+		t.Errorf("ValidationRuleMode(phone_ddd) = %q, want %q", got, ValidationRuleModeShadow)
+	}
+	// Not listed, keeps its default
+	if got := ValidationRuleMode(ValidationRuleEmailDomain); got != ValidationRuleModeShadow {
+		t.Errorf("ValidationRuleMode(email_domain) = %q, want %q", got, ValidationRuleModeShadow)
+	}
+}
+
+func TestLoadConfig_InvalidValidationRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unknown rule", "cpf_digits=enforce"},
+		{"unknown mode", "address_uf=strict"},
+		{"missing mode", "address_uf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv("VALIDATION_RULES", tt.value)
+			defer os.Unsetenv("VALIDATION_RULES")
+
+			err := LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() should return error for VALIDATION_RULES=%q", tt.value)
+			}
+			if !strings.Contains(err.Error(), "invalid VALIDATION_RULES") {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid VALIDATION_RULES'", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EventStreamLimits(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"EVENT_STREAM_MAX_CONNECTIONS", "EVENT_STREAM_MAX_CONNECTIONS_PER_CPF", "EVENT_STREAM_IDLE_TIMEOUT"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.EventStreamMaxConnections != 1000 {
+		t.Errorf("EventStreamMaxConnections = %d, want 1000", AppConfig.EventStreamMaxConnections)
+	}
+	if AppConfig.EventStreamMaxConnectionsPerCPF != 5 {
+		t.Errorf("EventStreamMaxConnectionsPerCPF = %d, want 5", AppConfig.EventStreamMaxConnectionsPerCPF)
+	}
+	if AppConfig.EventStreamIdleTimeout != 30*time.Minute {
+		t.Errorf("EventStreamIdleTimeout = %v, want 30m", AppConfig.EventStreamIdleTimeout)
+	}
+
+	os.Setenv("EVENT_STREAM_IDLE_TIMEOUT", "not-a-duration")
+	if err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() should fail with an invalid EVENT_STREAM_IDLE_TIMEOUT")
+	}
+}
+
+func TestLoadConfig_AuditCoalescing(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"AUDIT_COALESCING_ENABLED", "AUDIT_BATCH_SIZE", "AUDIT_FLUSH_INTERVAL"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.AuditCoalescingEnabled || AppConfig.AuditBatchSize != 100 || AppConfig.AuditFlushInterval != 100*time.Millisecond {
+		t.Errorf("audit coalescing defaults = (%v, %d, %v), want (true, 100, 100ms)",
+			AppConfig.AuditCoalescingEnabled, AppConfig.AuditBatchSize, AppConfig.AuditFlushInterval)
+	}
+
+	for _, value := range []string{"soon", "0s"} {
+		os.Setenv("AUDIT_FLUSH_INTERVAL", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with AUDIT_FLUSH_INTERVAL=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_PhoneVerificationLockout(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"PHONE_VERIFICATION_MAX_FAILURES", "PHONE_VERIFICATION_LOCKOUT_WINDOW"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneVerificationMaxFailures != 5 || AppConfig.PhoneVerificationLockoutWindow != 15*time.Minute {
+		t.Errorf("phone verification lockout defaults = (%d, %v), want (5, 15m)",
+			AppConfig.PhoneVerificationMaxFailures, AppConfig.PhoneVerificationLockoutWindow)
+	}
+
+	os.Setenv("PHONE_VERIFICATION_MAX_FAILURES", "3")
+	os.Setenv("PHONE_VERIFICATION_LOCKOUT_WINDOW", "1h")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneVerificationMaxFailures != 3 || AppConfig.PhoneVerificationLockoutWindow != time.Hour {
+		t.Errorf("phone verification lockout = (%d, %v), want (3, 1h)",
+			AppConfig.PhoneVerificationMaxFailures, AppConfig.PhoneVerificationLockoutWindow)
+	}
+
+	for _, value := range []string{"soon", "0s"} {
+		os.Setenv("PHONE_VERIFICATION_LOCKOUT_WINDOW", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with PHONE_VERIFICATION_LOCKOUT_WINDOW=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_CPFValidationRateLimit(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"CPF_VALIDATION_RATE_LIMIT", "CPF_VALIDATION_RATE_WINDOW"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CPFValidationRateLimit != 30 || AppConfig.CPFValidationRateWindow != time.Minute {
+		t.Errorf("CPF validation rate limit defaults = (%d, %v), want (30, 1m)",
+			AppConfig.CPFValidationRateLimit, AppConfig.CPFValidationRateWindow)
+	}
+
+	os.Setenv("CPF_VALIDATION_RATE_LIMIT", "0")
+	os.Setenv("CPF_VALIDATION_RATE_WINDOW", "10m")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CPFValidationRateLimit != 0 || AppConfig.CPFValidationRateWindow != 10*time.Minute {
+		t.Errorf("CPF validation rate limit = (%d, %v), want (0, 10m)",
+			AppConfig.CPFValidationRateLimit, AppConfig.CPFValidationRateWindow)
+	}
+
+	for _, value := range []string{"soon", "0s"} {
+		os.Setenv("CPF_VALIDATION_RATE_WINDOW", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with CPF_VALIDATION_RATE_WINDOW=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_PhoneDeliveryFailures(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"PHONE_DELIVERY_FAILURE_THRESHOLD", "PHONE_DELIVERY_FAILURE_WINDOW"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneDeliveryFailureThreshold != 3 || AppConfig.PhoneDeliveryFailureWindow != 168*time.Hour {
+		t.Errorf("phone delivery failure defaults = (%d, %v), want (3, 168h)",
+			AppConfig.PhoneDeliveryFailureThreshold, AppConfig.PhoneDeliveryFailureWindow)
+	}
+
+	os.Setenv("PHONE_DELIVERY_FAILURE_THRESHOLD", "0")
+	os.Setenv("PHONE_DELIVERY_FAILURE_WINDOW", "24h")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneDeliveryFailureThreshold != 0 || AppConfig.PhoneDeliveryFailureWindow != 24*time.Hour {
+		t.Errorf("phone delivery failures = (%d, %v), want (0, 24h)",
+			AppConfig.PhoneDeliveryFailureThreshold, AppConfig.PhoneDeliveryFailureWindow)
+	}
+
+	for _, value := range []string{"weekly", "-1h"} {
+		os.Setenv("PHONE_DELIVERY_FAILURE_WINDOW", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with PHONE_DELIVERY_FAILURE_WINDOW=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_IdempotencyKeyTTL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("IDEMPOTENCY_KEY_TTL")
+	defer os.Unsetenv("IDEMPOTENCY_KEY_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.IdempotencyKeyTTL != time.Hour {
+		t.Errorf("IdempotencyKeyTTL default = %v, want 1h", AppConfig.IdempotencyKeyTTL)
+	}
+
+	os.Setenv("IDEMPOTENCY_KEY_TTL", "10m")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.IdempotencyKeyTTL != 10*time.Minute {
+		t.Errorf("IdempotencyKeyTTL = %v, want 10m", AppConfig.IdempotencyKeyTTL)
+	}
+
+	for _, value := range []string{"later", "0s"} {
+		os.Setenv("IDEMPOTENCY_KEY_TTL", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with IDEMPOTENCY_KEY_TTL=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_CFLookupSync(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"CF_LOOKUP_SYNC_TIMEOUT", "CF_LOOKUP_SYNC_ENABLED"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.CFLookupSyncEnabled || AppConfig.CFLookupSyncTimeout != 8*time.Second {
+		t.Errorf("CF sync lookup defaults = (%v, %v), want (true, 8s)", AppConfig.CFLookupSyncEnabled, AppConfig.CFLookupSyncTimeout)
+	}
+
+	os.Setenv("CF_LOOKUP_SYNC_ENABLED", "false")
+	os.Setenv("CF_LOOKUP_SYNC_TIMEOUT", "2s")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CFLookupSyncEnabled || AppConfig.CFLookupSyncTimeout != 2*time.Second {
+		t.Errorf("CF sync lookup = (%v, %v), want (false, 2s)", AppConfig.CFLookupSyncEnabled, AppConfig.CFLookupSyncTimeout)
+	}
+
+	os.Setenv("CF_LOOKUP_SYNC_TIMEOUT", "0s")
+	if err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() should fail with CF_LOOKUP_SYNC_TIMEOUT=0s")
+	}
+}
+
+func TestLoadConfig_CORS(t *testing.T) {
+	setupMinimalEnv(t)
+	keys := []string{"CORS_ALLOWED_ORIGINS", "CORS_ALLOWED_METHODS", "CORS_ALLOWED_HEADERS", "CORS_ALLOW_CREDENTIALS", "CORS_MAX_AGE"}
+	for _, key := range keys {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(AppConfig.CORSAllowedOrigins) != 0 || AppConfig.CORSAllowCredentials || AppConfig.CORSMaxAge != 12*time.Hour {
+		t.Errorf("CORS defaults = (%v, %v, %v), want no origins, no credentials and 12h",
+			AppConfig.CORSAllowedOrigins, AppConfig.CORSAllowCredentials, AppConfig.CORSMaxAge)
+	}
+	if !slices.Contains(AppConfig.CORSAllowedHeaders, "Authorization") || !slices.Contains(AppConfig.CORSAllowedMethods, "PATCH") {
+		t.Errorf("CORS default methods/headers = (%v, %v)", AppConfig.CORSAllowedMethods, AppConfig.CORSAllowedHeaders)
+	}
+
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://app.rio, http://localhost:3000")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !slices.Equal(AppConfig.CORSAllowedOrigins, []string{"https://app.rio", "http://localhost:3000"}) || !AppConfig.CORSAllowCredentials {
+		t.Errorf("CORS = (%v, %v)", AppConfig.CORSAllowedOrigins, AppConfig.CORSAllowCredentials)
+	}
+
+	for _, value := range []string{"*", "https://*.rio", "app.rio", "https://app.rio/"} {
+		os.Setenv("CORS_ALLOWED_ORIGINS", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with CORS_ALLOWED_ORIGINS=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_TrustedProxies(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("TRUSTED_PROXIES")
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(AppConfig.TrustedProxies) != 0 {
+		t.Errorf("TrustedProxies default = %v, want none", AppConfig.TrustedProxies)
+	}
+
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !slices.Equal(AppConfig.TrustedProxies, []string{"10.0.0.0/8", "192.168.1.10"}) {
+		t.Errorf("TrustedProxies = %v", AppConfig.TrustedProxies)
+	}
+
+	for _, value := range []string{"*", "10.0.0.0/33", "proxy.local"} {
+		os.Setenv("TRUSTED_PROXIES", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with TRUSTED_PROXIES=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_WriteBufferReconcile(t *testing.T) {
+	setupMinimalEnv(t)
+	keys := []string{"WRITE_BUFFER_RECONCILE_INTERVAL", "WRITE_BUFFER_RECONCILE_STALE_AFTER", "WRITE_BUFFER_RECONCILE_BATCH_SIZE"}
+	for _, key := range keys {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.WriteBufferReconcileInterval != 15*time.Minute || AppConfig.WriteBufferReconcileStaleAfter != 30*time.Minute ||
+		AppConfig.WriteBufferReconcileBatchSize != 500 {
+		t.Errorf("write buffer reconcile defaults = (%v, %v, %d), want (15m, 30m, 500)", AppConfig.WriteBufferReconcileInterval,
+			AppConfig.WriteBufferReconcileStaleAfter, AppConfig.WriteBufferReconcileBatchSize)
+	}
+
+	os.Setenv("WRITE_BUFFER_RECONCILE_INTERVAL", "0")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() should accept WRITE_BUFFER_RECONCILE_INTERVAL=0: %v", err)
+	}
+
+	for key, value := range map[string]string{
+		"WRITE_BUFFER_RECONCILE_INTERVAL":    "-1m",
+		"WRITE_BUFFER_RECONCILE_STALE_AFTER": "0s",
+	} {
+		os.Setenv(key, value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with %s=%q", key, value)
+		}
+		os.Unsetenv(key)
+	}
+}
+
+func TestLoadConfig_DefaultAvatarMode(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("DEFAULT_AVATAR_MODE")
+	defer os.Unsetenv("DEFAULT_AVATAR_MODE")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.DefaultAvatarMode != DefaultAvatarModeOff {
+		t.Errorf("DefaultAvatarMode = %q, want %q", AppConfig.DefaultAvatarMode, DefaultAvatarModeOff)
+	}
+
+	os.Setenv("DEFAULT_AVATAR_MODE", "deterministic")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.DefaultAvatarMode != DefaultAvatarModeDeterministic {
+		t.Errorf("DefaultAvatarMode = %q, want %q", AppConfig.DefaultAvatarMode, DefaultAvatarModeDeterministic)
+	}
+
+	os.Setenv("DEFAULT_AVATAR_MODE", "always")
+	err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "invalid DEFAULT_AVATAR_MODE") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid DEFAULT_AVATAR_MODE'", err)
+	}
+}
+
+func TestLoadConfig_AvatarListMaxPerPage(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("AVATAR_LIST_MAX_PER_PAGE")
+	defer os.Unsetenv("AVATAR_LIST_MAX_PER_PAGE")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AvatarListMaxPerPage != 100 {
+		t.Errorf("AvatarListMaxPerPage = %d, want 100", AppConfig.AvatarListMaxPerPage)
+	}
+
+	os.Setenv("AVATAR_LIST_MAX_PER_PAGE", "50")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AvatarListMaxPerPage != 50 {
+		t.Errorf("AvatarListMaxPerPage = %d, want 50", AppConfig.AvatarListMaxPerPage)
+	}
+
+	for _, value := range []string{"many", "0"} {
+		os.Setenv("AVATAR_LIST_MAX_PER_PAGE", value)
+		if err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() should fail with AVATAR_LIST_MAX_PER_PAGE=%q", value)
+		}
+	}
+}
+
+func TestLoadConfig_PhoneVerificationCode(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"PHONE_VERIFICATION_CODE_LENGTH", "PHONE_VERIFICATION_CODE_ALPHABET", "PHONE_VERIFICATION_TTL"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneVerificationCodeLength != 6 || AppConfig.PhoneVerificationCodeAlphabet != VerificationCodeAlphabetNumeric {
+		t.Errorf("phone verification code = %d %q, want 6 %q", AppConfig.PhoneVerificationCodeLength, AppConfig.PhoneVerificationCodeAlphabet, VerificationCodeAlphabetNumeric)
+	}
+
+	os.Setenv("PHONE_VERIFICATION_CODE_LENGTH", "8")
+	os.Setenv("PHONE_VERIFICATION_CODE_ALPHABET", "alphanumeric")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneVerificationCodeLength != 8 || AppConfig.PhoneVerificationCodeAlphabet != VerificationCodeAlphabetAlphanumeric {
+		t.Errorf("phone verification code = %d %q, want 8 %q", AppConfig.PhoneVerificationCodeLength, AppConfig.PhoneVerificationCodeAlphabet, VerificationCodeAlphabetAlphanumeric)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"PHONE_VERIFICATION_CODE_LENGTH", "3"},
+		{"PHONE_VERIFICATION_CODE_LENGTH", "13"},
+		{"PHONE_VERIFICATION_CODE_ALPHABET", "hex"},
+		{"PHONE_VERIFICATION_TTL", "0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			original := os.Getenv(tt.key)
+			os.Setenv(tt.key, tt.value)
+			defer os.Setenv(tt.key, original)
+
+			err := LoadConfig()
+			if err == nil || !strings.Contains(err.Error(), "invalid "+tt.key) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CFCircuit(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, key := range []string{"CF_CIRCUIT_FAILURE_RATIO", "CF_CIRCUIT_MIN_REQUESTS", "CF_CIRCUIT_OPEN_TIMEOUT"} {
+		os.Unsetenv(key)
+		defer os.Unsetenv(key)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CFCircuitFailureRatio != 0.5 || AppConfig.CFCircuitMinRequests != 10 || AppConfig.CFCircuitOpenTimeout != 30*time.Second {
+		t.Errorf("CF circuit defaults = %v %d %s, want 0.5 10 30s", AppConfig.CFCircuitFailureRatio, AppConfig.CFCircuitMinRequests, AppConfig.CFCircuitOpenTimeout)
+	}
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"CF_CIRCUIT_FAILURE_RATIO", "abc"},
+		{"CF_CIRCUIT_FAILURE_RATIO", "0"},
+		{"CF_CIRCUIT_FAILURE_RATIO", "1.5"},
+		{"CF_CIRCUIT_MIN_REQUESTS", "0"},
+		{"CF_CIRCUIT_OPEN_TIMEOUT", "invalid"},
+		{"CF_CIRCUIT_OPEN_TIMEOUT", "0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			os.Setenv(tt.key, tt.value)
+			defer os.Unsetenv(tt.key)
+
+			err := LoadConfig()
+			if err == nil || !strings.Contains(err.Error(), "invalid "+tt.key) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_AuditOverflowPolicy(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("AUDIT_OVERFLOW_POLICY")
+	defer os.Unsetenv("AUDIT_OVERFLOW_POLICY")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AuditOverflowPolicy != AuditOverflowPolicySync {
+		t.Errorf("AuditOverflowPolicy = %q, want %q", AppConfig.AuditOverflowPolicy, AuditOverflowPolicySync)
+	}
+
+	os.Setenv("AUDIT_OVERFLOW_POLICY", AuditOverflowPolicySpillToRedis)
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AuditOverflowPolicy != AuditOverflowPolicySpillToRedis {
+		t.Errorf("AuditOverflowPolicy = %q, want %q", AppConfig.AuditOverflowPolicy, AuditOverflowPolicySpillToRedis)
+	}
+
+	os.Setenv("AUDIT_OVERFLOW_POLICY", "discard")
+	if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid AUDIT_OVERFLOW_POLICY") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'invalid AUDIT_OVERFLOW_POLICY'", err)
+	}
+}
+
+func TestLoadConfig_ExhibitionName(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("EXHIBITION_NAME_MIN_LENGTH")
+	os.Setenv("EXHIBITION_NAME_BLOCKLIST", "palavrao, termo feio")
+	defer os.Unsetenv("EXHIBITION_NAME_MIN_LENGTH")
+	defer os.Unsetenv("EXHIBITION_NAME_BLOCKLIST")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.ExhibitionNameMinLength != 2 {
+		t.Errorf("ExhibitionNameMinLength = %d, want 2", AppConfig.ExhibitionNameMinLength)
+	}
+	if len(AppConfig.ExhibitionNameBlocklist) != 2 || AppConfig.ExhibitionNameBlocklist[1] != "termo feio" {
+		t.Errorf("ExhibitionNameBlocklist = %v, want [palavrao termo feio]", AppConfig.ExhibitionNameBlocklist)
+	}
+
+	for _, value := range []string{"0", "256"} {
+		os.Setenv("EXHIBITION_NAME_MIN_LENGTH", value)
+		if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid EXHIBITION_NAME_MIN_LENGTH") {
+			t.Errorf("LoadConfig() with EXHIBITION_NAME_MIN_LENGTH=%s error = %v, want error containing 'invalid EXHIBITION_NAME_MIN_LENGTH'", value, err)
+		}
+	}
+}
+
+func TestLoadConfig_EthnicityOptions(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("ETHNICITY_OPTIONS_CACHE_TTL")
+	os.Unsetenv("MONGODB_ETHNICITY_OPTION_COLLECTION")
+	defer os.Unsetenv("ETHNICITY_OPTIONS_CACHE_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.EthnicityOptionsCacheTTL != time.Hour {
+		t.Errorf("EthnicityOptionsCacheTTL = %v, want 1h", AppConfig.EthnicityOptionsCacheTTL)
+	}
+	if AppConfig.EthnicityOptionCollection != "ethnicity_options" {
+		t.Errorf("EthnicityOptionCollection = %q, want ethnicity_options", AppConfig.EthnicityOptionCollection)
+	}
+
+	for _, value := range []string{"0s", "soon"} {
+		os.Setenv("ETHNICITY_OPTIONS_CACHE_TTL", value)
+		if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid ETHNICITY_OPTIONS_CACHE_TTL") {
+			t.Errorf("LoadConfig() with ETHNICITY_OPTIONS_CACHE_TTL=%s error = %v, want error containing 'invalid ETHNICITY_OPTIONS_CACHE_TTL'", value, err)
+		}
+	}
+}
+
+func TestLoadConfig_PhoneVerifiedWebhookEnabledWithoutURL(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("PHONE_VERIFIED_WEBHOOK_ENABLED", "true")
+	os.Unsetenv("PHONE_VERIFIED_WEBHOOK_URL")
+	defer os.Unsetenv("PHONE_VERIFIED_WEBHOOK_ENABLED")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() should return error when PHONE_VERIFIED_WEBHOOK_ENABLED=true but PHONE_VERIFIED_WEBHOOK_URL is missing")
+	}
+
+	if !strings.Contains(err.Error(), "PHONE_VERIFIED_WEBHOOK_URL is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'PHONE_VERIFIED_WEBHOOK_URL is required'", err)
+	}
+}
+
+func TestLoadConfig_PhoneVerifiedWebhookEnabledWithoutSecret(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("PHONE_VERIFIED_WEBHOOK_ENABLED", "true")
+	os.Setenv("PHONE_VERIFIED_WEBHOOK_URL", "https://hooks.example.com/phone")
+	os.Unsetenv("PHONE_VERIFIED_WEBHOOK_SECRET")
+	defer os.Unsetenv("PHONE_VERIFIED_WEBHOOK_ENABLED")
+	defer os.Unsetenv("PHONE_VERIFIED_WEBHOOK_URL")
+
+	err := LoadConfig()
+	if err == nil {
+		t.Fatal("LoadConfig() should return error when PHONE_VERIFIED_WEBHOOK_ENABLED=true but PHONE_VERIFIED_WEBHOOK_SECRET is missing")
+	}
+
+	if !strings.Contains(err.Error(), "PHONE_VERIFIED_WEBHOOK_SECRET is required") {
+		t.Errorf("LoadConfig() error = %v, want error containing 'PHONE_VERIFIED_WEBHOOK_SECRET is required'", err)
+	}
+}
+
+func TestLoadConfig_PhoneVerifiedWebhookDefaults(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("PHONE_VERIFIED_WEBHOOK_ENABLED")
+	os.Unsetenv("PHONE_VERIFIED_WEBHOOK_TIMEOUT")
+	os.Unsetenv("PHONE_VERIFIED_WEBHOOK_MAX_RETRIES")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.PhoneVerifiedWebhookEnabled {
+		t.Error("PhoneVerifiedWebhookEnabled = true, want false")
+	}
+	if AppConfig.PhoneVerifiedWebhookTimeout != 10*time.Second {
+		t.Errorf("PhoneVerifiedWebhookTimeout = %v, want 10s", AppConfig.PhoneVerifiedWebhookTimeout)
+	}
+	if AppConfig.PhoneVerifiedWebhookMaxRetries != 5 {
+		t.Errorf("PhoneVerifiedWebhookMaxRetries = %d, want 5", AppConfig.PhoneVerifiedWebhookMaxRetries)
+	}
+}
+
+func TestLoadConfig_CitizenExternalID(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("CITIZEN_EXTERNAL_ID_SYSTEM")
+	os.Unsetenv("EXTERNAL_ID_CACHE_TTL")
+	defer os.Unsetenv("CITIZEN_EXTERNAL_ID_SYSTEM")
+	defer os.Unsetenv("EXTERNAL_ID_CACHE_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CitizenExternalIDSystem != "" {
+		t.Errorf("CitizenExternalIDSystem = %q, want empty", AppConfig.CitizenExternalIDSystem)
+	}
+	if AppConfig.ExternalIDCacheTTL != 24*time.Hour {
+		t.Errorf("ExternalIDCacheTTL = %v, want 24h", AppConfig.ExternalIDCacheTTL)
+	}
+
+	os.Setenv("CITIZEN_EXTERNAL_ID_SYSTEM", "id_municipal")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.CitizenExternalIDSystem != "id_municipal" {
+		t.Errorf("CitizenExternalIDSystem = %q, want id_municipal", AppConfig.CitizenExternalIDSystem)
+	}
+
+	for _, system := range []string{"id.municipal", "$id", "id municipal"} {
+		os.Setenv("CITIZEN_EXTERNAL_ID_SYSTEM", system)
+		if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid CITIZEN_EXTERNAL_ID_SYSTEM") {
+			t.Errorf("LoadConfig() with CITIZEN_EXTERNAL_ID_SYSTEM=%q error = %v, want error containing 'invalid CITIZEN_EXTERNAL_ID_SYSTEM'", system, err)
+		}
+	}
+	os.Unsetenv("CITIZEN_EXTERNAL_ID_SYSTEM")
+
+	os.Setenv("EXTERNAL_ID_CACHE_TTL", "0s")
+	if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid EXTERNAL_ID_CACHE_TTL") {
+		t.Errorf("LoadConfig() with EXTERNAL_ID_CACHE_TTL=0s error = %v, want error containing 'invalid EXTERNAL_ID_CACHE_TTL'", err)
+	}
+}
+
+func TestLoadConfig_MongoReadPreferences(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Setenv("MONGODB_READ_PREFERENCES", "self_declared=PRIMARY, maintenance_requests=secondaryPreferred")
+	defer os.Unsetenv("MONGODB_READ_PREFERENCES")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	want := map[string]string{
+		"self_declared":        "primary",
+		"maintenance_requests": "secondaryPreferred",
+	}
+	if len(AppConfig.MongoReadPreferences) != len(want) {
+		t.Fatalf("MongoReadPreferences = %v, want %v", AppConfig.MongoReadPreferences, want)
+	}
+	for collection, mode := range want {
+		if got := AppConfig.MongoReadPreferences[collection]; got != mode {
+			t.Errorf("MongoReadPreferences[%q] = %q, want %q", collection, got, mode)
+		}
+	}
+}
+
+func TestLoadConfig_InvalidMongoReadPreferences(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"missing mode", "citizens"},
+		{"unknown mode", "citizens=fastest"},
+		{"empty collection", "=primary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv("MONGODB_READ_PREFERENCES", tt.value)
+			defer os.Unsetenv("MONGODB_READ_PREFERENCES")
+
+			if err := LoadConfig(); err == nil {
+				t.Errorf("LoadConfig() expected error for MONGODB_READ_PREFERENCES=%q", tt.value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_FeatureFlags(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("FEATURE_FLAG_CACHE_TTL")
+	os.Unsetenv("MONGODB_FEATURE_FLAG_COLLECTION")
+	defer os.Unsetenv("FEATURE_FLAG_CACHE_TTL")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.FeatureFlagCacheTTL != 30*time.Second {
+		t.Errorf("FeatureFlagCacheTTL = %v, want 30s", AppConfig.FeatureFlagCacheTTL)
+	}
+	if AppConfig.FeatureFlagCollection != "feature_flags" {
+		t.Errorf("FeatureFlagCollection = %q, want feature_flags", AppConfig.FeatureFlagCollection)
+	}
+
+	for _, value := range []string{"0s", "-1s", "soon"} {
+		os.Setenv("FEATURE_FLAG_CACHE_TTL", value)
+		if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid FEATURE_FLAG_CACHE_TTL") {
+			t.Errorf("LoadConfig() with FEATURE_FLAG_CACHE_TTL=%s error = %v, want error containing 'invalid FEATURE_FLAG_CACHE_TTL'", value, err)
+		}
+	}
+}
+
+func TestLoadConfig_CompressionDefaults(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, name := range []string{"COMPRESSION_ENABLED", "COMPRESSION_MIN_SIZE", "COMPRESSION_GZIP_LEVEL", "COMPRESSION_BROTLI_LEVEL", "COMPRESSION_EXCLUDED_PATHS"} {
+		os.Unsetenv(name)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !AppConfig.CompressionEnabled {
+		t.Error("CompressionEnabled = false, want true")
+	}
+	if AppConfig.CompressionMinSize != 1024 || AppConfig.CompressionGzipLevel != 5 || AppConfig.CompressionBrotliLevel != 4 {
+		t.Errorf("compression settings = %d/%d/%d, want 1024/5/4",
+			AppConfig.CompressionMinSize, AppConfig.CompressionGzipLevel, AppConfig.CompressionBrotliLevel)
+	}
+	if len(AppConfig.CompressionExcludedPaths) != 2 || AppConfig.CompressionExcludedPaths[0] != "/metrics" {
+		t.Errorf("CompressionExcludedPaths = %v, want [/metrics /v1/metrics]", AppConfig.CompressionExcludedPaths)
+	}
+}
+
+func TestLoadConfig_InvalidCompression(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"COMPRESSION_MIN_SIZE", "-1"},
+		{"COMPRESSION_MIN_SIZE", "1kb"},
+		{"COMPRESSION_GZIP_LEVEL", "0"},
+		{"COMPRESSION_GZIP_LEVEL", "10"},
+		{"COMPRESSION_BROTLI_LEVEL", "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid "+tt.env) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_BodySizeLimits(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, name := range []string{"MAX_REQUEST_BODY_SIZE", "MAX_AVATAR_BODY_SIZE", "MAX_SELF_DECLARED_BODY_SIZE"} {
+		os.Unsetenv(name)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.MaxRequestBodySize != 1<<20 || AppConfig.MaxAvatarBodySize != 5<<20 || AppConfig.MaxSelfDeclaredBodySize != 16<<10 {
+		t.Errorf("body size limits = %d/%d/%d, want %d/%d/%d",
+			AppConfig.MaxRequestBodySize, AppConfig.MaxAvatarBodySize, AppConfig.MaxSelfDeclaredBodySize, 1<<20, 5<<20, 16<<10)
+	}
+
+	for _, tt := range []struct {
+		env   string
+		value string
+	}{
+		{"MAX_REQUEST_BODY_SIZE", "-1"},
+		{"MAX_AVATAR_BODY_SIZE", "5mb"},
+		{"MAX_SELF_DECLARED_BODY_SIZE", "abc"},
+	} {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid "+tt.env) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DataFreshness(t *testing.T) {
+	setupMinimalEnv(t)
+	for _, name := range []string{"DATA_FRESHNESS_INTERVAL", "DATA_FRESHNESS_COLLECTIONS", "DATA_FRESHNESS_SAMPLE_SIZE"} {
+		os.Unsetenv(name)
+	}
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.DataFreshnessInterval != time.Hour || AppConfig.DataFreshnessSampleSize != 10000 {
+		t.Errorf("DataFreshnessInterval/SampleSize = %v/%d, want 1h/10000",
+			AppConfig.DataFreshnessInterval, AppConfig.DataFreshnessSampleSize)
+	}
+	// Defaults to the citizen and maintenance request collections
+	if got := AppConfig.DataFreshnessCollections; len(got) != 2 || got[0] != "citizens" || got[1] != "maintenance_requests" {
+		t.Errorf("DataFreshnessCollections = %v, want [citizens maintenance_requests]", got)
+	}
+
+	os.Setenv("DATA_FRESHNESS_COLLECTIONS", "pets, legal_entities")
+	defer os.Unsetenv("DATA_FRESHNESS_COLLECTIONS")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := AppConfig.DataFreshnessCollections; len(got) != 2 || got[0] != "pets" || got[1] != "legal_entities" {
+		t.Errorf("DataFreshnessCollections = %v, want [pets legal_entities]", got)
+	}
+}
+
+func TestLoadConfig_InvalidDataFreshness(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"DATA_FRESHNESS_INTERVAL", "-1h"},
+		{"DATA_FRESHNESS_INTERVAL", "hourly"},
+		{"DATA_FRESHNESS_SAMPLE_SIZE", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid "+tt.env) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_OutboundHTTP(t *testing.T) {
+	setupMinimalEnv(t)
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.OutboundHTTPTimeout != 30*time.Second || AppConfig.OutboundHTTPDialTimeout != 5*time.Second {
+		t.Errorf("outbound timeouts = %v/%v, want 30s/5s", AppConfig.OutboundHTTPTimeout, AppConfig.OutboundHTTPDialTimeout)
+	}
+	if AppConfig.OutboundHTTPMaxIdleConns != 100 || AppConfig.OutboundHTTPMaxIdleConnsPerHost != 20 || AppConfig.OutboundHTTPMaxConnsPerHost != 100 {
+		t.Errorf("outbound pool limits = %d/%d/%d, want 100/20/100", AppConfig.OutboundHTTPMaxIdleConns,
+			AppConfig.OutboundHTTPMaxIdleConnsPerHost, AppConfig.OutboundHTTPMaxConnsPerHost)
+	}
+
+	os.Setenv("OUTBOUND_HTTP_RESPONSE_HEADER_TIMEOUT", "5s")
+	os.Setenv("OUTBOUND_HTTP_MAX_CONNS_PER_HOST", "0")
+	defer os.Unsetenv("OUTBOUND_HTTP_RESPONSE_HEADER_TIMEOUT")
+	defer os.Unsetenv("OUTBOUND_HTTP_MAX_CONNS_PER_HOST")
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.OutboundHTTPResponseHeaderTimeout != 5*time.Second {
+		t.Errorf("OutboundHTTPResponseHeaderTimeout = %v, want 5s", AppConfig.OutboundHTTPResponseHeaderTimeout)
+	}
+	if AppConfig.OutboundHTTPMaxConnsPerHost != 0 {
+		t.Errorf("OutboundHTTPMaxConnsPerHost = %d, want 0 (no limit)", AppConfig.OutboundHTTPMaxConnsPerHost)
+	}
+}
+
+func TestLoadConfig_InvalidOutboundHTTP(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"OUTBOUND_HTTP_TIMEOUT", "0s"},
+		{"OUTBOUND_HTTP_DIAL_TIMEOUT", "fast"},
+		{"OUTBOUND_HTTP_IDLE_CONN_TIMEOUT", "-1s"},
+		{"OUTBOUND_HTTP_MAX_IDLE_CONNS", "0"},
+		{"OUTBOUND_HTTP_MAX_IDLE_CONNS_PER_HOST", "many"},
+		{"OUTBOUND_HTTP_MAX_CONNS_PER_HOST", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			setupMinimalEnv(t)
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid "+tt.env) {
+				t.Errorf("LoadConfig() error = %v, want error containing 'invalid %s'", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_LogPIIMaskingDefaultsByEnvironment(t *testing.T) {
+	setupMinimalEnv(t)
+	os.Unsetenv("LOG_PII_MASKING")
+	defer os.Unsetenv("ENVIRONMENT")
+
+	tests := []struct {
+		environment string
+		override    string
+		want        bool
+	}{
+		{environment: "development", want: false},
+		{environment: "production", want: true},
+		{environment: "staging", want: true},
+		{environment: "development", override: "true", want: true},
+		{environment: "production", override: "false", want: false},
+	}
+
+	for _, tt := range tests {
+		os.Setenv("ENVIRONMENT", tt.environment)
+		if tt.override != "" {
+			os.Setenv("LOG_PII_MASKING", tt.override)
+		} else {
+			os.Unsetenv("LOG_PII_MASKING")
+		}
+
+		if err := LoadConfig(); err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if AppConfig.LogPIIMasking != tt.want {
+			t.Errorf("LogPIIMasking (ENVIRONMENT=%s, LOG_PII_MASKING=%q) = %v, want %v", tt.environment, tt.override, AppConfig.LogPIIMasking, tt.want)
+		}
+	}
+	os.Unsetenv("LOG_PII_MASKING")
+}
+
+func TestLoadConfig_AvatarStorageBackend(t *testing.T) {
+	setupMinimalEnv(t)
+	defer func() {
+		for _, key := range []string{"AVATAR_STORAGE_BACKEND", "AVATAR_S3_ENDPOINT", "AVATAR_S3_BUCKET", "AVATAR_S3_ACCESS_KEY_ID", "AVATAR_S3_SECRET_ACCESS_KEY"} {
+			os.Unsetenv(key)
+		}
+	}()
+
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AvatarStorageBackend != AvatarStorageMongo {
+		t.Errorf("AvatarStorageBackend = %q, want %q", AppConfig.AvatarStorageBackend, AvatarStorageMongo)
+	}
+
+	os.Setenv("AVATAR_STORAGE_BACKEND", "ftp")
+	if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid AVATAR_STORAGE_BACKEND") {
+		t.Errorf("LoadConfig() error = %v, want invalid AVATAR_STORAGE_BACKEND", err)
+	}
+
+	os.Setenv("AVATAR_STORAGE_BACKEND", AvatarStorageS3)
+	os.Setenv("AVATAR_S3_ENDPOINT", "https://storage.googleapis.com/")
+	os.Setenv("AVATAR_S3_BUCKET", "avatars")
+	if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "AVATAR_S3_ACCESS_KEY_ID and AVATAR_S3_SECRET_ACCESS_KEY are required") {
+		t.Errorf("LoadConfig() error = %v, want missing credentials error", err)
+	}
+
+	os.Setenv("AVATAR_S3_ACCESS_KEY_ID", "key")
+	os.Setenv("AVATAR_S3_SECRET_ACCESS_KEY", "secret")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.AvatarS3Endpoint != "https://storage.googleapis.com" {
+		t.Errorf("AvatarS3Endpoint = %q, want trailing slash trimmed", AppConfig.AvatarS3Endpoint)
+	}
+}
+
+func TestLoadConfig_ShutdownDrainDelay(t *testing.T) {
+	setupMinimalEnv(t)
+	defer os.Unsetenv("SHUTDOWN_DRAIN_DELAY")
+
+	os.Unsetenv("SHUTDOWN_DRAIN_DELAY")
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if AppConfig.ShutdownDrainDelay != 5*time.Second {
+		t.Errorf("ShutdownDrainDelay = %v, want 5s", AppConfig.ShutdownDrainDelay)
+	}
+
+	os.Setenv("SHUTDOWN_DRAIN_DELAY", "-1s")
+	if err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid SHUTDOWN_DRAIN_DELAY") {
+		t.Errorf("LoadConfig() error = %v, want invalid SHUTDOWN_DRAIN_DELAY", err)
+	}
+}
@@ -36,6 +36,12 @@ var (
 	// that occur between InitRedis and the background goroutines started by
 	// InitMongoDB (startIndexMaintenance, monitorRedisConnectionPool).
 	redisMu sync.RWMutex
+
+	// MongoPoolStatsReporter and RedisPoolStatsReporter receive the connection pool stats
+	// collected by the monitor goroutines. They are wired by the observability package
+	// (which already imports config) to export the stats as Prometheus gauges.
+	MongoPoolStatsReporter func(sessionsInProgress int)
+	RedisPoolStatsReporter func(redisType string, stats *redis.PoolStats)
 )
 
 // getRedis returns the Redis client in a race-safe manner.
@@ -395,6 +401,11 @@ func ensureIndexes() error {
 		return err
 	}
 
+	// Ensure citizen external ID index (only if a system is configured)
+	if err := ensureCitizenExternalIDIndex(ctx, logger); err != nil {
+		return err
+	}
+
 	// Ensure maintenance request collection index
 	if err := ensureMaintenanceRequestIndex(ctx, logger); err != nil {
 		return err
@@ -410,6 +421,11 @@ func ensureIndexes() error {
 		return err
 	}
 
+	// Ensure email_verifications collection index
+	if err := ensureEmailVerificationIndex(ctx, logger); err != nil {
+		return err
+	}
+
 	// Ensure user_config collection index
 	if err := ensureUserConfigIndex(ctx, logger); err != nil {
 		return err
@@ -575,11 +591,19 @@ func ensureCitizenIndex(ctx context.Context, logger *zap.Logger) error {
 	return nil
 }
 
-// ensureMaintenanceRequestIndex creates the index on cpf for maintenance request collection
-func ensureMaintenanceRequestIndex(ctx context.Context, logger *zap.Logger) error {
-	collection := MongoDB.Collection(AppConfig.MaintenanceRequestCollection)
+// ensureCitizenExternalIDIndex creates the index backing lookups by the configured external ID
+// system. It is not unique: the IDs come from other systems, so collisions are reported by the
+// lookup instead of rejecting citizen writes.
+func ensureCitizenExternalIDIndex(ctx context.Context, logger *zap.Logger) error {
+	if AppConfig.CitizenExternalIDSystem == "" {
+		return nil
+	}
 
-	// Check if index already exists
+	collection := MongoDB.Collection(AppConfig.CitizenCollection)
+	field := "external_ids." + AppConfig.CitizenExternalIDSystem
+	indexName := field + "_1"
+
+	// Check if index already exists or is being built
 	cursor, err := collection.Indexes().List(ctx)
 	if err != nil {
 		logger.Error("failed to list indexes", zap.Error(err))
@@ -593,42 +617,140 @@ func ensureMaintenanceRequestIndex(ctx context.Context, logger *zap.Logger) erro
 		if err := cursor.Decode(&index); err != nil {
 			continue
 		}
-		if name, ok := index["name"].(string); ok && name == "cpf_1" {
+		if name, ok := index["name"].(string); ok && name == indexName {
 			indexExists = true
 			break
 		}
 	}
 
 	if indexExists {
-		logger.Debug("maintenance request collection index already exists",
-			zap.String("collection", AppConfig.MaintenanceRequestCollection))
+		logger.Debug("citizen external ID index already exists",
+			zap.String("collection", AppConfig.CitizenCollection),
+			zap.String("index", indexName))
+		return nil
+	}
+
+	if isIndexBuildInProgress(ctx, AppConfig.CitizenCollection, indexName, logger) {
+		logger.Info("citizen external ID index build already in progress, skipping",
+			zap.String("collection", AppConfig.CitizenCollection))
 		return nil
 	}
 
-	// Create non-unique index on cpf
+	// Sparse, since most citizens have no ID in the system
 	indexModel := mongo.IndexModel{
-		Keys: bson.D{{Key: "cpf", Value: 1}},
+		Keys: bson.D{{Key: field, Value: 1}},
 		Options: options.Index().
-			SetName("cpf_1"),
+			SetName(indexName).
+			SetSparse(true),
 	}
 
 	_, err = collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		if isIndexBuildAlreadyInProgressError(err) {
+			logger.Info("citizen external ID index build already in progress (detected via error)",
+				zap.String("collection", AppConfig.CitizenCollection))
+			return nil
+		}
+		logger.Error("failed to create citizen external ID index",
+			zap.String("collection", AppConfig.CitizenCollection),
+			zap.Error(err))
+		return err
+	}
+
+	logger.Info("created citizen external ID index",
+		zap.String("collection", AppConfig.CitizenCollection),
+		zap.String("index", indexName))
+	return nil
+}
+
+// ensureMaintenanceRequestIndex creates the indexes for maintenance request collection
+func ensureMaintenanceRequestIndex(ctx context.Context, logger *zap.Logger) error {
+	collection := MongoDB.Collection(AppConfig.MaintenanceRequestCollection)
+
+	// Check if indexes already exist
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		logger.Error("failed to list indexes", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	existingIndexes := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var index bson.M
+		if err := cursor.Decode(&index); err != nil {
+			continue
+		}
+		if name, ok := index["name"].(string); ok {
+			existingIndexes[name] = true
+		}
+	}
+
+	// Define required indexes for maintenance request collection
+	requiredIndexes := []mongo.IndexModel{
+		// Index 1: CPF lookup (count and page mode)
+		{
+			Keys:    bson.D{{Key: "cpf", Value: 1}},
+			Options: options.Index().SetName("cpf_1"),
+		},
+		// Index 2: Cursor pagination, newest first with _id as tie-breaker
+		{
+			Keys: bson.D{
+				{Key: "cpf", Value: 1},
+				{Key: "data_inicio", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("cpf_1_data_inicio_-1__id_-1"),
+		},
+		// Index 3: Status filter, then date range and sort
+		{
+			Keys: bson.D{
+				{Key: "cpf", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "data_inicio", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("cpf_1_status_1_data_inicio_-1__id_-1"),
+		},
+	}
+
+	// Create missing indexes
+	indexesToCreate := []mongo.IndexModel{}
+	requiredNames := []string{
+		"cpf_1",
+		"cpf_1_data_inicio_-1__id_-1",
+		"cpf_1_status_1_data_inicio_-1__id_-1",
+	}
+
+	for i, indexModel := range requiredIndexes {
+		if !existingIndexes[requiredNames[i]] {
+			indexesToCreate = append(indexesToCreate, indexModel)
+		}
+	}
+
+	if len(indexesToCreate) == 0 {
+		logger.Debug("maintenance request collection indexes already exist",
+			zap.String("collection", AppConfig.MaintenanceRequestCollection))
+		return nil
+	}
+
+	_, err = collection.Indexes().CreateMany(ctx, indexesToCreate)
 	if err != nil {
 		// Check if it's a duplicate key error (another instance created it)
 		if mongo.IsDuplicateKeyError(err) {
-			logger.Info("maintenance request index already exists (created by another instance)",
+			logger.Info("maintenance request indexes already exist (created by another instance)",
 				zap.String("collection", AppConfig.MaintenanceRequestCollection))
 			return nil
 		}
-		logger.Error("failed to create maintenance request index",
+		logger.Error("failed to create maintenance request indexes",
 			zap.String("collection", AppConfig.MaintenanceRequestCollection),
 			zap.Error(err))
 		return err
 	}
 
-	logger.Info("created maintenance request collection index",
+	logger.Info("created maintenance request collection indexes",
 		zap.String("collection", AppConfig.MaintenanceRequestCollection),
-		zap.String("index", "cpf_1"))
+		zap.Int("created_count", len(indexesToCreate)))
 	return nil
 }
 
@@ -792,6 +914,91 @@ func ensurePhoneVerificationIndex(ctx context.Context, logger *zap.Logger) error
 	return nil
 }
 
+// ensureEmailVerificationIndex creates the required indexes for email_verifications collection
+func ensureEmailVerificationIndex(ctx context.Context, logger *zap.Logger) error {
+	collection := MongoDB.Collection(AppConfig.EmailVerificationCollection)
+
+	// Check if indexes already exist
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		logger.Error("failed to list indexes", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	existingIndexes := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var index bson.M
+		if err := cursor.Decode(&index); err != nil {
+			continue
+		}
+		if name, ok := index["name"].(string); ok {
+			existingIndexes[name] = true
+		}
+	}
+
+	// Create indexes that don't exist
+	indexesToCreate := []mongo.IndexModel{}
+
+	// 1. Unique compound index on cpf and email
+	if !existingIndexes["cpf_1_email_1"] {
+		indexesToCreate = append(indexesToCreate, mongo.IndexModel{
+			Keys: bson.D{{Key: "cpf", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName("cpf_1_email_1").
+				SetUnique(true),
+		})
+	}
+
+	// 2. TTL index on expires_at for automatic cleanup
+	if !existingIndexes["expires_at_1"] {
+		indexesToCreate = append(indexesToCreate, mongo.IndexModel{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().
+				SetName("expires_at_1").
+				SetExpireAfterSeconds(0),
+		})
+	}
+
+	// 3. Sparse index on link_token_hash for magic link lookups
+	if !existingIndexes["link_token_hash_1"] {
+		indexesToCreate = append(indexesToCreate, mongo.IndexModel{
+			Keys: bson.D{{Key: "link_token_hash", Value: 1}},
+			Options: options.Index().
+				SetName("link_token_hash_1").
+				SetSparse(true),
+		})
+	}
+
+	// Create all missing indexes
+	for _, indexModel := range indexesToCreate {
+		_, err = collection.Indexes().CreateOne(ctx, indexModel)
+		if err != nil {
+			// Check if it's a duplicate key error (another instance created it)
+			if mongo.IsDuplicateKeyError(err) {
+				logger.Info("email_verifications index already exists (created by another instance)",
+					zap.String("collection", AppConfig.EmailVerificationCollection))
+				continue
+			}
+			logger.Error("failed to create email_verifications index",
+				zap.String("collection", AppConfig.EmailVerificationCollection),
+				zap.Error(err))
+			return err
+		}
+	}
+
+	if len(indexesToCreate) > 0 {
+		logger.Info("created email_verifications collection indexes",
+			zap.String("collection", AppConfig.EmailVerificationCollection),
+			zap.Int("count", len(indexesToCreate)))
+	} else {
+		logger.Debug("email_verifications collection indexes already exist",
+			zap.String("collection", AppConfig.EmailVerificationCollection))
+	}
+
+	return nil
+}
+
 // ensureUserConfigIndex creates the unique index on cpf for user_config collection
 func ensureUserConfigIndex(ctx context.Context, logger *zap.Logger) error {
 	collection := MongoDB.Collection(AppConfig.UserConfigCollection)
@@ -885,6 +1092,15 @@ func ensureAuditLogsIndex(ctx context.Context, logger *zap.Logger) error {
 		})
 	}
 
+	// 2. Compound index on user_id and timestamp for actor lookups (GET /admin/audit/by-actor)
+	if !existingIndexes["user_id_1_timestamp_-1"] {
+		indexesToCreate = append(indexesToCreate, mongo.IndexModel{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().
+				SetName("user_id_1_timestamp_-1"),
+		})
+	}
+
 	// Note: Removed timestamp_1 and action_1_resource_1 indexes for better write performance
 	// These indexes are rarely used for queries and slow down write operations
 
@@ -1636,6 +1852,9 @@ func monitorConnectionPool() {
 	for range ticker.C {
 		// Get connection pool stats
 		stats := MongoDB.Client().NumberSessionsInProgress()
+		if MongoPoolStatsReporter != nil {
+			MongoPoolStatsReporter(stats)
+		}
 
 		// Log connection pool status
 		logging.GetLogger().Info("MongoDB connection pool status",
@@ -1701,6 +1920,10 @@ func monitorRedisConnectionPool() {
 			redisAddr = fmt.Sprintf("%v", AppConfig.RedisClusterAddrs)
 		}
 
+		if RedisPoolStatsReporter != nil {
+			RedisPoolStatsReporter(redisType, poolStats)
+		}
+
 		// Calculate usage percentages
 		totalUsagePercent := float64(poolStats.TotalConns) / float64(AppConfig.RedisPoolSize) * 100
 
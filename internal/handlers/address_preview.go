@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// PreviewSelfDeclaredAddress godoc
+// @Summary Pré-visualizar Clínica da Família de um endereço
+// @Description Consulta qual Clínica da Família (e equipe de saúde) atenderia o endereço informado, sem salvar o endereço nem o resultado, sem invalidar caches e sem registrar auditoria. Permite ao cidadão confirmar o endereço antes de enviá-lo em PUT /citizen/{cpf}/address. Quando nenhuma clínica atende o endereço, retorna found=false. A consulta conta no limite global de consultas de CF.
+// @Tags citizen
+// @Accept json
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Param data body models.SelfDeclaredAddressInput true "Endereço a consultar"
+// @Security BearerAuth
+// @Success 200 {object} models.CFAddressPreview "Clínica da Família correspondente ao endereço (ou found=false)"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou dados de endereço incorretos"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 429 {object} ErrorResponse "Limite de consultas de CF excedido"
+// @Failure 503 {object} ErrorResponse "Consulta de CF indisponível"
+// @Router /citizen/{cpf}/address/preview [post]
+func PreviewSelfDeclaredAddress(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "PreviewSelfDeclaredAddress")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "preview_self_declared_address"),
+		attribute.String("service", "citizen"),
+	)
+
+	if !utils.ValidateCPF(cpf) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+
+	var input models.SelfDeclaredAddressInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
+	if err := utils.CheckValidationRule(config.ValidationRuleAddressUF, utils.AddressUFViolation(input.Estado)); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeValidationRule, Message: err.Error()})
+		return
+	}
+
+	if services.CFLookupServiceInstance == nil || !services.CFIntegrationEnabled(ctx, cpf) {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Code: ErrCodeCFLookupUnavailable, Message: "CF lookup is not available"})
+		return
+	}
+
+	preview, err := services.CFLookupServiceInstance.PreviewCFForAddress(ctx, cpf, selfDeclaredAddressString(input))
+	if errors.Is(err, services.ErrCFRateLimited) {
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Code: ErrCodeCFLookupRateLimited, Message: "Too many CF lookups, try again later"})
+		return
+	}
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		logger.Warn("CF preview lookup failed", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Code: ErrCodeCFLookupUnavailable, Message: "CF lookup failed, try again later"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+
+	logger.Debug("PreviewSelfDeclaredAddress completed",
+		zap.Bool("found", preview.Found),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
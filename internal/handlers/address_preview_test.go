@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewSelfDeclaredAddress(t *testing.T) {
+	original := services.CFLookupServiceInstance
+	services.CFLookupServiceInstance = nil
+	defer func() { services.CFLookupServiceInstance = original }()
+
+	r := gin.New()
+	r.POST("/v1/citizen/:cpf/address/preview", PreviewSelfDeclaredAddress)
+
+	validBody := `{"logradouro":"Rua Afonso Cavalcanti","numero":"455","bairro":"Cidade Nova","municipio":"Rio de Janeiro","estado":"RJ","cep":"20211110"}`
+	tests := []struct {
+		name     string
+		cpf      string
+		body     string
+		wantCode int
+	}{
+		{"invalid CPF", "12345678900", validBody, http.StatusBadRequest},
+		{"missing fields", "11144477735", `{"logradouro":"Rua Afonso Cavalcanti"}`, http.StatusBadRequest},
+		{"CF lookup disabled", "11144477735", validBody, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/v1/citizen/"+tt.cpf+"/address/preview", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
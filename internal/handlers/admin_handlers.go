@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/middleware"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -131,3 +136,176 @@ func ReadCacheKey(c *gin.Context) {
 		zap.Duration("total_duration", totalDuration),
 		zap.String("status", "success"))
 }
+
+// FlushSyncQueues godoc
+// @Summary Esvaziar filas de sincronização
+// @Description Processa de forma síncrona todos os jobs pendentes nas filas sync:queue:* (buffers de escrita do Redis para o MongoDB) e retorna as contagens por fila. Com dry_run=true apenas informa os jobs pendentes, sem processá-los. O processamento é limitado por SYNC_FLUSH_TIMEOUT; complete=false indica que restaram jobs ou que houve falhas.
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Apenas informar os jobs pendentes (padrão: false)"
+// @Security BearerAuth
+// @Success 200 {object} services.SyncFlushResult "Resultado do esvaziamento das filas"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/sync/flush [post]
+func FlushSyncQueues(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "FlushSyncQueues")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	dryRun := c.Query("dry_run") == "true"
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "flush_sync_queues"),
+		attribute.String("service", "admin"),
+		attribute.Bool("dry_run", dryRun),
+	)
+
+	logger.Info("FlushSyncQueues called", zap.Bool("dry_run", dryRun))
+
+	// Drain the queues with tracing
+	flushCtx := ctx
+	if timeout := config.AppConfig.SyncFlushTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	flushCtx, flushSpan := utils.TraceBusinessLogic(flushCtx, "flush_sync_queues")
+	result, err := services.FlushSyncQueues(flushCtx, services.SyncFlushTriggerAdmin, dryRun)
+	if err != nil {
+		utils.RecordErrorInSpan(flushSpan, err, map[string]interface{}{
+			"dry_run": dryRun,
+		})
+		flushSpan.End()
+		logger.Error("failed to flush sync queues", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to flush sync queues: " + err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(flushSpan, "sync.total_pending", result.TotalPending)
+	utils.AddSpanAttribute(flushSpan, "sync.total_flushed", result.TotalFlushed)
+	utils.AddSpanAttribute(flushSpan, "sync.complete", result.Complete)
+	flushSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(flushCtx, "success")
+	c.JSON(http.StatusOK, result)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Info("FlushSyncQueues completed",
+		zap.Bool("dry_run", dryRun),
+		zap.Int64("total_pending", result.TotalPending),
+		zap.Int("total_flushed", result.TotalFlushed),
+		zap.Int("total_failed", result.TotalFailed),
+		zap.Bool("complete", result.Complete),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// ExpireVerifications godoc
+// @Summary Expirar códigos de verificação pendentes
+// @Description Invalida de uma vez os códigos de verificação de telefone e email ainda válidos, definindo expires_at para o momento atual (ex: suspeita de vazamento de códigos). cpf e phone_number filtram os códigos afetados; com phone_number, apenas verificações de telefone são expiradas. Envie {} para expirar todos os códigos. A ação é registrada na auditoria.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body models.VerificationExpireRequest true "Filtros opcionais por CPF e telefone"
+// @Security BearerAuth
+// @Success 200 {object} models.VerificationExpireResponse "Quantidade de códigos expirados"
+// @Failure 400 {object} ErrorResponse "Corpo inválido, CPF ou telefone inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/verifications/expire [post]
+func ExpireVerifications(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ExpireVerifications")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "expire_verifications"),
+		attribute.String("service", "admin"),
+	)
+
+	logger.Debug("ExpireVerifications called")
+
+	// Parse input with tracing
+	ctx, parseSpan := utils.TraceInputParsing(ctx, "verification_expire_request")
+	var request models.VerificationExpireRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RecordErrorInSpan(parseSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "VerificationExpireRequest",
+		})
+		parseSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+	request.CPF = strings.TrimSpace(request.CPF)
+	request.PhoneNumber = strings.TrimSpace(request.PhoneNumber)
+	if request.CPF != "" && !utils.ValidateCPF(request.CPF) {
+		parseSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF"})
+		return
+	}
+	utils.AddSpanAttribute(parseSpan, "filter.cpf", request.CPF != "")
+	utils.AddSpanAttribute(parseSpan, "filter.phone_number", request.PhoneNumber != "")
+	parseSpan.End()
+
+	// Expire the codes with tracing
+	ctx, expireSpan := utils.TraceBusinessLogic(ctx, "expire_verifications")
+	result, err := services.ExpireVerifications(ctx, request.CPF, request.PhoneNumber)
+	if err != nil {
+		utils.RecordErrorInSpan(expireSpan, err, nil)
+		expireSpan.End()
+		if isPhoneParsingError(err) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid phone number format"})
+			return
+		}
+		logger.Error("failed to expire verifications", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to expire verifications: " + err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(expireSpan, "verification.phone_expired", result.PhoneVerificationsExpired)
+	utils.AddSpanAttribute(expireSpan, "verification.email_expired", result.EmailVerificationsExpired)
+	expireSpan.End()
+
+	// Log audit event with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "phone_verification")
+	adminCPF, _ := middleware.ExtractCPFFromToken(c)
+	auditCtx := utils.AuditContext{
+		UserID:    adminCPF,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogVerificationExpiry(ctx, auditCtx, request.CPF, request.PhoneNumber,
+		result.PhoneVerificationsExpired, result.EmailVerificationsExpired); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "update",
+			"audit.resource": "phone_verification",
+		})
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, result)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Info("ExpireVerifications completed",
+		zap.Int64("phone_verifications_expired", result.PhoneVerificationsExpired),
+		zap.Int64("email_verifications_expired", result.EmailVerificationsExpired),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ExportAuditHistory godoc
+// @Summary Exportar histórico de alterações do cidadão
+// @Description Gera um arquivo para download com o histórico de alterações (auditoria) dos dados do cidadão, com rótulos em português e valores sensíveis ocultados. Atualmente apenas o formato CSV é suportado.
+// @Tags citizen
+// @Produce text/csv
+// @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Param format query string false "Formato do arquivo (padrão: csv)" Enums(csv)
+// @Security BearerAuth
+// @Success 200 {file} file "Arquivo CSV com o histórico de alterações"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou formato de exportação não suportado"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/history/export [get]
+func ExportAuditHistory(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ExportAuditHistory")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("format", format),
+		attribute.String("operation", "export_audit_history"),
+		attribute.String("service", "audit_history"),
+	)
+
+	logger.Debug("ExportAuditHistory called", zap.String("cpf", cpf), zap.String("format", format))
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Validate export format with tracing
+	ctx, formatSpan := utils.TraceInputValidation(ctx, "export_format", "format")
+	if format != "csv" {
+		utils.RecordErrorInSpan(formatSpan, fmt.Errorf("unsupported export format"), map[string]interface{}{
+			"format": format,
+		})
+		formatSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported export format. Supported formats: csv"})
+		return
+	}
+	formatSpan.End()
+
+	// Check if audit history service is available
+	if services.AuditHistoryServiceInstance == nil {
+		logger.Error("audit history service not initialized")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Audit history service unavailable"})
+		return
+	}
+
+	// Query audit history with tracing
+	ctx, querySpan := utils.TraceDatabaseFind(ctx, "audit_logs", "cpf")
+	entries, err := services.AuditHistoryServiceInstance.GetHistoryByCPF(ctx, cpf, services.MaxAuditHistoryExportEntries)
+	if err != nil {
+		utils.RecordErrorInSpan(querySpan, err, map[string]interface{}{
+			"operation": "get_history_by_cpf",
+			"cpf":       cpf,
+		})
+		querySpan.End()
+		logger.Error("failed to get audit history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve audit history"})
+		return
+	}
+	utils.AddSpanAttribute(querySpan, "entries_found", len(entries))
+	querySpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+
+	// Stream the CSV download with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	filename := fmt.Sprintf("historico_%s_%s.csv", cpf, time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	if err := services.WriteAuditHistoryCSV(c.Writer, entries); err != nil {
+		utils.RecordErrorInSpan(responseSpan, err, map[string]interface{}{
+			"format": format,
+		})
+		responseSpan.End()
+		logger.Error("failed to write audit history export", zap.Error(err))
+		return
+	}
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("ExportAuditHistory completed",
+		zap.String("cpf", cpf),
+		zap.String("format", format),
+		zap.Int("entries_exported", len(entries)),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// GetAuditLogsByActor godoc
+// @Summary Listar auditoria por autor
+// @Description Lista as alterações (auditoria) feitas por um usuário ou operador, identificado pelo user_id do token, com paginação e filtros por recurso, ação e período. Valores sensíveis são ocultados (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Param user_id query string true "ID do usuário autor das alterações"
+// @Param resource query string false "Filtrar por recurso (ex: address, phone)"
+// @Param action query string false "Filtrar por ação (ex: UPDATE, DELETE)"
+// @Param from query string false "Início do período, inclusivo (RFC3339)"
+// @Param to query string false "Fim do período, exclusivo (RFC3339)"
+// @Param page query int false "Página (padrão: 1)"
+// @Param per_page query int false "Itens por página (padrão: 20, máximo: 100)"
+// @Security BearerAuth
+// @Success 200 {object} services.AuditLogListResponse "Registros de auditoria obtidos com sucesso"
+// @Failure 400 {object} ErrorResponse "user_id ausente ou período inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/audit/by-actor [get]
+func GetAuditLogsByActor(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetAuditLogsByActor")
+	defer span.End()
+
+	userID := c.Query("user_id")
+	logger := observability.Logger().With(zap.String("user_id", userID))
+
+	// Add actor to span attributes
+	span.SetAttributes(
+		attribute.String("user_id", userID),
+		attribute.String("operation", "get_audit_logs_by_actor"),
+		attribute.String("service", "audit_history"),
+	)
+
+	logger.Debug("GetAuditLogsByActor called")
+
+	// Parse filters with tracing
+	ctx, filterSpan := utils.TraceInputParsing(ctx, "audit_actor_filter")
+	filter, err := parseAuditActorFilter(c)
+	if err != nil {
+		utils.RecordErrorInSpan(filterSpan, err, map[string]interface{}{
+			"user_id": userID,
+		})
+		filterSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	utils.AddSpanAttribute(filterSpan, "resource", filter.Resource)
+	utils.AddSpanAttribute(filterSpan, "action", filter.Action)
+	utils.AddSpanAttribute(filterSpan, "page", page)
+	utils.AddSpanAttribute(filterSpan, "per_page", perPage)
+	filterSpan.End()
+
+	// Check if audit history service is available
+	if services.AuditHistoryServiceInstance == nil {
+		logger.Error("audit history service not initialized")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Audit history service unavailable"})
+		return
+	}
+
+	// Query audit logs with tracing
+	ctx, querySpan := utils.TraceDatabaseFind(ctx, "audit_logs", "user_id")
+	response, err := services.AuditHistoryServiceInstance.GetLogsByActor(ctx, filter, page, perPage)
+	if err != nil {
+		utils.RecordErrorInSpan(querySpan, err, map[string]interface{}{
+			"operation": "get_logs_by_actor",
+			"user_id":   userID,
+		})
+		querySpan.End()
+		logger.Error("failed to get audit logs by actor", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve audit logs"})
+		return
+	}
+	utils.AddSpanAttribute(querySpan, "entries_found", len(response.Data))
+	utils.AddSpanAttribute(querySpan, "total", response.Pagination.Total)
+	querySpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetAuditLogsByActor completed",
+		zap.Int("entries", len(response.Data)),
+		zap.Int("total", response.Pagination.Total),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// parseAuditActorFilter reads the actor, resource, action and period of an audit lookup
+func parseAuditActorFilter(c *gin.Context) (services.AuditActorFilter, error) {
+	filter := services.AuditActorFilter{
+		UserID:   strings.TrimSpace(c.Query("user_id")),
+		Resource: c.Query("resource"),
+		Action:   strings.ToUpper(c.Query("action")),
+	}
+	if filter.UserID == "" {
+		return filter, fmt.Errorf("user_id is required")
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: must be an RFC3339 timestamp", bound.name)
+		}
+		*bound.target = &parsed
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
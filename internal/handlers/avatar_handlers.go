@@ -1,10 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,6 +16,7 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/services"
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
 )
 
 // AvatarHandlers contains all avatar-related HTTP handlers
@@ -33,14 +35,16 @@ func NewAvatarHandlers(logger *logging.SafeLogger, cacheService *services.CacheS
 
 // ListAvatars godoc
 // @Summary Listar avatares disponíveis
-// @Description Obtém lista paginada de avatares de foto de perfil disponíveis
+// @Description Obtém lista paginada de avatares de foto de perfil disponíveis, ordenada por `sort` e `order` com desempate estável pelo ID, para que as páginas sejam consistentes entre requisições
 // @Tags avatars
 // @Accept json
 // @Produce json
 // @Param page query int false "Número da página (padrão: 1)"
-// @Param per_page query int false "Itens por página (padrão: 20, máximo: 100)"
+// @Param per_page query int false "Itens por página (padrão: 20, máximo: AVATAR_LIST_MAX_PER_PAGE, 100 por padrão)"
+// @Param sort query string false "Campo de ordenação: created_at (padrão) ou name" Enums(created_at, name)
+// @Param order query string false "Direção da ordenação: asc ou desc (padrão: desc para created_at, asc para name)" Enums(asc, desc)
 // @Success 200 {object} models.AvatarsListResponse "Lista de avatares obtida com sucesso"
-// @Failure 400 {object} ErrorResponse "Parâmetros de paginação inválidos"
+// @Failure 400 {object} ErrorResponse "Parâmetros de paginação ou ordenação inválidos"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /avatars [get]
@@ -50,22 +54,14 @@ func (h *AvatarHandlers) ListAvatars(c *gin.Context) {
 	_, span := utils.TraceBusinessLogic(ctx, "list_avatars")
 	defer span.End()
 
-	// Parse pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-
-	// Validate pagination
-	if page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Page must be greater than 0"})
-		return
-	}
-	if perPage < 1 || perPage > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Per page must be between 1 and 100"})
+	// Parse pagination and sort parameters
+	query, ok := parseAvatarListQuery(c)
+	if !ok {
 		return
 	}
 
 	// Get avatars from service
-	response, err := services.AvatarServiceInstance.ListAvatars(ctx, page, perPage)
+	response, err := services.AvatarServiceInstance.ListAvatars(ctx, query.page, query.perPage, query.sort, query.order)
 	if err != nil {
 		h.logger.Error("failed to list avatars", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve avatars"})
@@ -78,9 +74,41 @@ func (h *AvatarHandlers) ListAvatars(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// avatarListQuery holds the pagination and sort parameters of the avatar listing
+type avatarListQuery struct {
+	page, perPage int
+	sort, order   string
+}
+
+// parseAvatarListQuery reads and validates the avatar listing parameters, answering 400 when
+// one is invalid
+func parseAvatarListQuery(c *gin.Context) (avatarListQuery, bool) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	query := avatarListQuery{page: page, perPage: perPage, sort: c.Query("sort"), order: c.Query("order")}
+
+	if query.page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Page must be greater than 0"})
+		return query, false
+	}
+	if maxPerPage := services.MaxAvatarListPerPage(); query.perPage < 1 || query.perPage > maxPerPage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Per page must be between 1 and %d", maxPerPage)})
+		return query, false
+	}
+	if query.sort != "" && query.sort != models.AvatarSortCreatedAt && query.sort != models.AvatarSortName {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sort must be created_at or name"})
+		return query, false
+	}
+	if query.order != "" && query.order != models.AvatarOrderAsc && query.order != models.AvatarOrderDesc {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order must be asc or desc"})
+		return query, false
+	}
+	return query, true
+}
+
 // CreateAvatar godoc
 // @Summary Criar novo avatar
-// @Description Cria um novo avatar de foto de perfil (somente administradores)
+// @Description Cria um novo avatar de foto de perfil (somente administradores). Informe `url` (imagem hospedada externamente) ou `image` (PNG, JPEG, WebP ou GIF em base64, guardada no armazenamento de avatares).
 // @Tags avatars
 // @Accept json
 // @Produce json
@@ -112,14 +140,18 @@ func (h *AvatarHandlers) CreateAvatar(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar name must be between 1 and 100 characters"})
 		return
 	}
-	if request.URL == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar URL is required"})
+	if (request.URL == "") == (request.Image == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either an avatar URL or an image is required"})
 		return
 	}
 
 	// Create avatar
 	avatar, err := services.AvatarServiceInstance.CreateAvatar(ctx, &request)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAvatarImage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("failed to create avatar", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create avatar"})
 		return
@@ -339,13 +371,11 @@ func (h *AvatarHandlers) UpdateUserAvatar(c *gin.Context) {
 			CPF:        cpf,
 			FirstLogin: true,
 			OptIn:      true,
-			UpdatedAt:  time.Now(),
 		}
 	}
 
 	// Update avatar
 	userConfig.AvatarID = request.AvatarID
-	userConfig.UpdatedAt = time.Now()
 
 	// Save via cache service
 	err = h.cacheService.UpdateUserConfig(ctx, cpf, &userConfig)
@@ -405,19 +435,12 @@ func ListAvatars(c *gin.Context) {
 	_, span := utils.TraceBusinessLogic(ctx, "list_avatars_legacy")
 	defer span.End()
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-
-	if page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Page must be greater than 0"})
-		return
-	}
-	if perPage < 1 || perPage > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Per page must be between 1 and 100"})
+	query, ok := parseAvatarListQuery(c)
+	if !ok {
 		return
 	}
 
-	response, err := services.AvatarServiceInstance.ListAvatars(ctx, page, perPage)
+	response, err := services.AvatarServiceInstance.ListAvatars(ctx, query.page, query.perPage, query.sort, query.order)
 	if err != nil {
 		observability.Logger().Error("failed to list avatars", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve avatars"})
@@ -443,13 +466,17 @@ func CreateAvatar(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar name must be between 1 and 100 characters"})
 		return
 	}
-	if request.URL == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar URL is required"})
+	if (request.URL == "") == (request.Image == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either an avatar URL or an image is required"})
 		return
 	}
 
 	avatar, err := services.AvatarServiceInstance.CreateAvatar(ctx, &request)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAvatarImage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		observability.Logger().Error("failed to create avatar", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create avatar"})
 		return
@@ -595,12 +622,10 @@ func UpdateUserAvatar(c *gin.Context) {
 			CPF:        cpf,
 			FirstLogin: true,
 			OptIn:      true,
-			UpdatedAt:  time.Now(),
 		}
 	}
 
 	userConfig.AvatarID = request.AvatarID
-	userConfig.UpdatedAt = time.Now()
 
 	cacheService := services.NewCacheService()
 	err = cacheService.UpdateUserConfig(ctx, cpf, &userConfig)
@@ -643,3 +668,66 @@ func UpdateUserAvatar(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// GetAvatarImage godoc
+// @Summary Obter imagem de avatar
+// @Description Retorna a imagem de um avatar guardada no armazenamento de avatares
+// @Tags avatars
+// @Produce image/png,image/jpeg,image/webp,image/gif
+// @Param key path string true "Chave da imagem (ex: avatars/{id}.png)"
+// @Success 200 {file} binary "Imagem do avatar"
+// @Failure 404 {object} ErrorResponse "Imagem não encontrada"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /avatars/images/{key} [get]
+func GetAvatarImage(c *gin.Context) {
+	ctx := c.Request.Context()
+	_, span := utils.TraceBusinessLogic(ctx, "get_avatar_image")
+	defer span.End()
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if key == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar image not found"})
+		return
+	}
+
+	data, contentType, err := services.AvatarServiceInstance.GetAvatarImage(ctx, key)
+	if err != nil {
+		if errors.Is(err, services.ErrAvatarImageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Avatar image not found"})
+			return
+		}
+		observability.Logger().Error("failed to get avatar image", zap.Error(err), zap.String("key", key))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve avatar image"})
+		return
+	}
+
+	// Keys embed the avatar ID and images are never rewritten, so they can be cached for long
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// MigrateAvatarImages godoc
+// @Summary Migrar imagens de avatares para o armazenamento
+// @Description Copia para o armazenamento de avatares as imagens dos avatares que ainda apontam para uma URL externa (somente administradores). Avatares com falha permanecem inalterados e são listados na resposta.
+// @Tags avatars
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AvatarMigrationResponse "Resultado da migração"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /avatars/migrate-storage [post]
+func MigrateAvatarImages(c *gin.Context) {
+	ctx := c.Request.Context()
+	_, span := utils.TraceBusinessLogic(ctx, "migrate_avatar_images")
+	defer span.End()
+
+	response, err := services.AvatarServiceInstance.MigrateAvatarImages(ctx, httpclient.PublicClient(httpclient.ClientAvatarMigration))
+	if err != nil {
+		observability.Logger().Error("failed to migrate avatar images", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to migrate avatar images"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
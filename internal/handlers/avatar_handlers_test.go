@@ -170,6 +170,8 @@ func TestListAvatars_InvalidPagination(t *testing.T) {
 		{"per_page zero", "1", "0"},
 		{"per_page too large", "1", "101"},
 		{"negative page", "-1", "20"},
+		{"unknown sort", "1", "20&sort=url"},
+		{"unknown order", "1", "20&order=up"},
 	}
 
 	for _, tt := range tests {
@@ -228,6 +230,8 @@ func TestCreateAvatar_InvalidRequest(t *testing.T) {
 	}{
 		{"empty name", map[string]interface{}{"name": "", "url": "https://example.com/avatar.png"}},
 		{"missing URL", map[string]interface{}{"name": "Avatar"}},
+		{"URL and image", map[string]interface{}{"name": "Avatar", "url": "https://example.com/avatar.png", "image": "iVBORw0KGgo="}},
+		{"invalid image", map[string]interface{}{"name": "Avatar", "image": "bm90IGFuIGltYWdl"}},
 		{"name too long", map[string]interface{}{"name": string(make([]byte, 101)), "url": "https://example.com/avatar.png"}},
 	}
 
@@ -919,3 +923,88 @@ func TestLegacyHandlers_UpdateUserAvatar(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code, "Legacy UpdateUserAvatar() status code mismatch")
 }
+
+func TestUpdateFirstLogin_AssignsDefaultAvatar(t *testing.T) {
+	_, router, cleanup := setupAvatarHandlersTest(t)
+	defer cleanup()
+
+	original := config.AppConfig.DefaultAvatarMode
+	config.AppConfig.DefaultAvatarMode = config.DefaultAvatarModeDeterministic
+	defer func() { config.AppConfig.DefaultAvatarMode = original }()
+	router.PUT("/citizen/:cpf/firstlogin", UpdateFirstLogin)
+
+	ctx := context.Background()
+	avatarID := primitive.NewObjectID()
+	_, err := config.MongoDB.Collection(config.AppConfig.AvatarsCollection).InsertOne(ctx, bson.M{
+		"_id":        avatarID,
+		"name":       "Default Avatar",
+		"url":        "https://example.com/default.png",
+		"is_active":  true,
+		"created_at": time.Now(),
+	})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("PUT", "/citizen/03561350712/firstlogin", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response models.UserConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.FirstLogin)
+	require.NotNil(t, response.Avatar, "UpdateFirstLogin() should return the default avatar")
+	assert.Equal(t, avatarID.Hex(), response.Avatar.ID)
+
+	var userConfig models.UserConfig
+	err = config.MongoDB.Collection(config.AppConfig.UserConfigCollection).FindOne(ctx, bson.M{"cpf": "03561350712"}).Decode(&userConfig)
+	require.NoError(t, err)
+	require.NotNil(t, userConfig.AvatarID)
+	assert.Equal(t, avatarID.Hex(), *userConfig.AvatarID)
+}
+
+func TestUpdateFirstLogin_KeepsChosenAvatar(t *testing.T) {
+	_, router, cleanup := setupAvatarHandlersTest(t)
+	defer cleanup()
+
+	original := config.AppConfig.DefaultAvatarMode
+	config.AppConfig.DefaultAvatarMode = config.DefaultAvatarModeRandom
+	defer func() { config.AppConfig.DefaultAvatarMode = original }()
+	router.PUT("/citizen/:cpf/firstlogin", UpdateFirstLogin)
+
+	ctx := context.Background()
+	_, err := config.MongoDB.Collection(config.AppConfig.AvatarsCollection).InsertOne(ctx, bson.M{
+		"name":       "Default Avatar",
+		"url":        "https://example.com/default.png",
+		"is_active":  true,
+		"created_at": time.Now(),
+	})
+	require.NoError(t, err)
+
+	// The user picked an avatar during onboarding
+	chosen := primitive.NewObjectID().Hex()
+	_, err = config.MongoDB.Collection(config.AppConfig.UserConfigCollection).InsertOne(ctx, models.UserConfig{
+		CPF:        "03561350712",
+		FirstLogin: true,
+		AvatarID:   &chosen,
+		Timestamps: models.Timestamps{UpdatedAt: time.Now()},
+	})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("PUT", "/citizen/03561350712/firstlogin", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response models.UserConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Avatar)
+
+	var userConfig models.UserConfig
+	err = config.MongoDB.Collection(config.AppConfig.UserConfigCollection).FindOne(ctx, bson.M{"cpf": "03561350712"}).Decode(&userConfig)
+	require.NoError(t, err)
+	require.NotNil(t, userConfig.AvatarID)
+	assert.Equal(t, chosen, *userConfig.AvatarID)
+	assert.False(t, userConfig.FirstLogin)
+}
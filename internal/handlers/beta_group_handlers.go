@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/middleware"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
@@ -223,6 +225,89 @@ func (h *BetaGroupHandlers) GetGroup(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// GetGroupStats godoc
+// @Summary Obter estatísticas de grupo beta
+// @Description Retorna as estatísticas de participação de um grupo beta (apenas administradores): total de telefones na whitelist, quantos estão vinculados a um CPF, quantos têm opt-in e a taxa de opt-in, além das inclusões e dos opt-ins/opt-outs registrados na janela BETA_GROUP_STATS_RECENT_WINDOW. As estatísticas ficam em cache por BETA_GROUP_STATS_CACHE_TTL e são invalidadas quando a whitelist do grupo é alterada.
+// @Tags Beta Groups
+// @Produce json
+// @Param group_id path string true "ID do grupo beta"
+// @Security BearerAuth
+// @Success 200 {object} models.BetaGroupStats "Estatísticas do grupo beta obtidas com sucesso"
+// @Failure 400 {object} ErrorResponse "ID do grupo é obrigatório ou inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 404 {object} ErrorResponse "Grupo beta não encontrado"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/beta/groups/{group_id}/stats [get]
+func (h *BetaGroupHandlers) GetGroupStats(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetBetaGroupStats")
+	defer span.End()
+
+	groupID := c.Param("group_id")
+
+	span.SetAttributes(
+		attribute.String("group_id", groupID),
+		attribute.String("operation", "get_beta_group_stats"),
+		attribute.String("service", "beta_group"),
+	)
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Code: ErrCodeAdminRequired, Message: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	if groupID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeBetaGroupIDInvalid, Message: "ID do grupo é obrigatório"})
+		return
+	}
+
+	// Get stats with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "beta_group_service", "get_group_stats")
+	stats, err := h.betaGroupService.GetGroupStats(ctx, groupID)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "beta_group_service",
+			"service.operation": "get_group_stats",
+		})
+		serviceSpan.End()
+
+		switch err {
+		case models.ErrInvalidGroupID:
+			c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeBetaGroupIDInvalid, Message: err.Error()})
+		case models.ErrGroupNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeBetaGroupNotFound, Message: err.Error()})
+		default:
+			h.logger.Error("failed to get beta group stats", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Erro interno do servidor"})
+		}
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.whitelisted_count", stats.WhitelistedCount)
+	serviceSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, stats)
+	responseSpan.End()
+
+	h.logger.Debug("GetBetaGroupStats completed",
+		zap.String("group_id", groupID),
+		zap.Int64("whitelisted_count", stats.WhitelistedCount),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
 // ListGroups godoc
 // @Summary Listar grupos beta
 // @Description Lista todos os grupos beta com paginação (apenas administradores)
@@ -898,6 +983,108 @@ func (h *BetaGroupHandlers) ListWhitelistedPhones(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// ExportWhitelistedPhones godoc
+// @Summary Exportar whitelist beta
+// @Description Exporta todos os telefones na whitelist beta, opcionalmente de um único grupo, em CSV (whitelist_beta_AAAAMMDD.csv) ou JSON, sem paginação (apenas administradores). Cada item traz o telefone, o ID e o nome do grupo e a data de inclusão. O arquivo é transmitido à medida que os telefones são lidos. Os telefones são mascarados, exceto para tokens com um scope de BETA_WHITELIST_EXPORT_UNMASKED_SCOPES.
+// @Tags Beta Whitelist
+// @Produce text/csv
+// @Produce json
+// @Param group_id query string false "Filtrar por ID do grupo"
+// @Param format query string false "Formato do arquivo (padrão: csv)" Enums(csv, json)
+// @Security BearerAuth
+// @Success 200 {file} file "Arquivo com os telefones na whitelist"
+// @Failure 400 {object} ErrorResponse "ID do grupo ou formato inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 404 {object} ErrorResponse "Grupo não encontrado"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/beta/whitelist/export [get]
+func (h *BetaGroupHandlers) ExportWhitelistedPhones(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ExportWhitelistedPhones")
+	defer span.End()
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Code: ErrCodeAdminRequired, Message: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	groupID := c.Query("group_id")
+	format := strings.ToLower(c.DefaultQuery("format", services.WhitelistExportFormatCSV))
+
+	span.SetAttributes(
+		attribute.String("operation", "export_whitelisted_phones"),
+		attribute.String("service", "beta_group"),
+		attribute.String("format", format),
+	)
+
+	if format != services.WhitelistExportFormatCSV && format != services.WhitelistExportFormatJSON {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeFormatInvalid, Message: "Formato inválido. Formatos suportados: csv, json"})
+		return
+	}
+
+	// A group filter must name an existing group, so a typo isn't exported as an empty file
+	if groupID != "" {
+		if _, err := h.betaGroupService.GetGroup(ctx, groupID); err != nil {
+			switch err {
+			case models.ErrInvalidGroupID:
+				c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeBetaGroupIDInvalid, Message: err.Error()})
+			case models.ErrGroupNotFound:
+				c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeBetaGroupNotFound, Message: err.Error()})
+			default:
+				h.logger.Error("failed to get beta group for export", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Erro interno do servidor"})
+			}
+			return
+		}
+	}
+
+	maskPhone := !middleware.HasAnyScope(c, config.AppConfig.BetaWhitelistExportUnmaskedScopes)
+
+	// Stream the download with tracing
+	ctx, exportSpan := utils.TraceExternalService(ctx, "beta_group_service", "export_whitelisted_phones")
+	utils.AddSpanAttribute(exportSpan, "mask_phone", maskPhone)
+	contentType := "text/csv; charset=utf-8"
+	if format == services.WhitelistExportFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("whitelist_beta_%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	exported, err := h.betaGroupService.ExportWhitelistedPhones(ctx, c.Writer, groupID, format, maskPhone)
+	if err != nil {
+		// The status line is already sent, so the download ends truncated
+		utils.RecordErrorInSpan(exportSpan, err, map[string]interface{}{
+			"service.name":      "beta_group_service",
+			"service.operation": "export_whitelisted_phones",
+			"exported":          exported,
+		})
+		exportSpan.End()
+		h.logger.Error("failed to export whitelisted phones", zap.Error(err), zap.Int("exported", exported))
+		return
+	}
+	utils.AddSpanAttribute(exportSpan, "exported", exported)
+	exportSpan.End()
+
+	h.logger.Debug("ExportWhitelistedPhones completed",
+		zap.String("group_id_filter", groupID),
+		zap.String("format", format),
+		zap.Bool("mask_phone", maskPhone),
+		zap.Int("exported", exported),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
 // BulkAddToWhitelist godoc
 // @Summary Adicionar múltiplos telefones à whitelist
 // @Description Adiciona múltiplos números de telefone a um grupo beta (apenas administradores)
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -47,6 +48,7 @@ func setupBetaGroupHandlersTest(t *testing.T) (*BetaGroupHandlers, *gin.Engine,
 	router.Use(adminMiddleware)
 	router.POST("/admin/beta/groups", handlers.CreateGroup)
 	router.GET("/admin/beta/groups/:group_id", handlers.GetGroup)
+	router.GET("/admin/beta/groups/:group_id/stats", handlers.GetGroupStats)
 	router.GET("/admin/beta/groups", handlers.ListGroups)
 	router.PUT("/admin/beta/groups/:group_id", handlers.UpdateGroup)
 	router.DELETE("/admin/beta/groups/:group_id", handlers.DeleteGroup)
@@ -54,6 +56,7 @@ func setupBetaGroupHandlersTest(t *testing.T) (*BetaGroupHandlers, *gin.Engine,
 	router.POST("/admin/beta/groups/:group_id/whitelist", handlers.AddToWhitelist)
 	router.DELETE("/admin/beta/groups/:group_id/whitelist", handlers.RemoveFromWhitelist)
 	router.GET("/admin/beta/groups/:group_id/whitelist", handlers.ListWhitelistedPhones)
+	router.GET("/admin/beta/whitelist/export", handlers.ExportWhitelistedPhones)
 
 	return handlers, router, func() {
 		_ = database.Drop(ctx)
@@ -299,6 +302,77 @@ func TestDeleteGroup_Success(t *testing.T) {
 // Helper function
 //
 //nolint:unused // Keeping for potential future use
+func TestGetGroupStats_NotFound(t *testing.T) {
+	_, router, cleanup := setupBetaGroupHandlersTest(t)
+	defer cleanup()
+
+	req, _ := http.NewRequest("GET", "/admin/beta/groups/507f1f77bcf86cd799439011/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetGroupStats() not found status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetGroupStats_InvalidID(t *testing.T) {
+	_, router, cleanup := setupBetaGroupHandlersTest(t)
+	defer cleanup()
+
+	req, _ := http.NewRequest("GET", "/admin/beta/groups/not-an-id/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GetGroupStats() invalid ID status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportWhitelistedPhones_InvalidFormat(t *testing.T) {
+	_, router, cleanup := setupBetaGroupHandlersTest(t)
+	defer cleanup()
+
+	req, _ := http.NewRequest("GET", "/admin/beta/whitelist/export?format=xlsx", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("ExportWhitelistedPhones() invalid format status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportWhitelistedPhones_GroupNotFound(t *testing.T) {
+	_, router, cleanup := setupBetaGroupHandlersTest(t)
+	defer cleanup()
+
+	req, _ := http.NewRequest("GET", "/admin/beta/whitelist/export?group_id=507f1f77bcf86cd799439011", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("ExportWhitelistedPhones() unknown group status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestExportWhitelistedPhones_CSV(t *testing.T) {
+	_, router, cleanup := setupBetaGroupHandlersTest(t)
+	defer cleanup()
+
+	req, _ := http.NewRequest("GET", "/admin/beta/whitelist/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ExportWhitelistedPhones() status = %v, want %v (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("ExportWhitelistedPhones() Content-Type = %q, want text/csv", ct)
+	}
+	if !strings.HasPrefix(w.Body.String(), "Telefone,ID do grupo,Grupo,Adicionado em") {
+		t.Errorf("ExportWhitelistedPhones() body = %q, want CSV header", w.Body.String())
+	}
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetCFTeamsBatch godoc
+// @Summary Consultar clínica da família e equipe de saúde em lote
+// @Description Retorna a Clínica da Família ativa e o resumo da equipe de saúde da família de uma lista de CPFs, a partir dos dados de CF já armazenados (não dispara novas consultas). CPFs sem dados de CF são retornados com has_cf_data=false (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body models.CFTeamsBatchRequest true "Lista de CPFs (máximo 500)"
+// @Security BearerAuth
+// @Success 200 {object} models.CFTeamsBatchResponse "Resumo de CF e equipe de saúde por CPF"
+// @Failure 400 {object} ErrorResponse "Dados inválidos, lista vazia ou acima do limite"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/cf/teams/batch [post]
+func GetCFTeamsBatch(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCFTeamsBatch")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "get_cf_teams_batch"),
+		attribute.String("service", "cf_lookup"),
+	)
+
+	logger.Debug("GetCFTeamsBatch called")
+
+	// Parse input with tracing
+	ctx, parseSpan := utils.TraceInputParsing(ctx, "cf_teams_batch_request")
+	var request models.CFTeamsBatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RecordErrorInSpan(parseSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "CFTeamsBatchRequest",
+		})
+		parseSpan.End()
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
+	utils.AddSpanAttribute(parseSpan, "input.cpf_count", len(request.CPFs))
+	parseSpan.End()
+
+	// Validate batch size with tracing
+	ctx, sizeSpan := utils.TraceInputValidation(ctx, "batch_size", "cpfs")
+	if len(request.CPFs) > services.MaxCFTeamsBatchSize {
+		utils.RecordErrorInSpan(sizeSpan, fmt.Errorf("batch size exceeds limit"), map[string]interface{}{
+			"cpf_count": len(request.CPFs),
+			"max_size":  services.MaxCFTeamsBatchSize,
+		})
+		sizeSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Too many CPFs in batch (max %d)", services.MaxCFTeamsBatchSize)})
+		return
+	}
+	sizeSpan.End()
+
+	// Check if CF lookup service is available
+	if services.CFLookupServiceInstance == nil {
+		logger.Error("CF lookup service not initialized")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "CF lookup service unavailable"})
+		return
+	}
+
+	// Resolve stored CF data with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "cf_lookup_service", "get_cf_teams_batch")
+	response := services.CFLookupServiceInstance.GetCFTeamsBatch(ctx, request.CPFs)
+	utils.AddSpanAttribute(serviceSpan, "response.found_count", response.FoundCount)
+	utils.AddSpanAttribute(serviceSpan, "response.missing_count", response.MissingCount)
+	serviceSpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetCFTeamsBatch completed",
+		zap.Int("cpf_count", response.TotalCount),
+		zap.Int("found_count", response.FoundCount),
+		zap.Int("missing_count", response.MissingCount),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// GetCFCoverageStats godoc
+// @Summary Estatísticas de cobertura de Clínica da Família por região
+// @Description Agrega os lookups de CF armazenados pela região do endereço do cidadão (endereço autodeclarado, depois dados base), informando quantos cidadãos têm CF ativa e quantos não tiveram CF encontrada em cada região. O resultado fica em cache por CF_COVERAGE_STATS_CACHE_TTL (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Param by query string false "Agrupamento por região (bairro ou municipio)" default(bairro)
+// @Security BearerAuth
+// @Success 200 {object} models.CFCoverageStats "Cobertura de CF por região"
+// @Failure 400 {object} ErrorResponse "Agrupamento inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/stats/cf-coverage [get]
+func GetCFCoverageStats(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCFCoverageStats")
+	defer span.End()
+
+	by := c.DefaultQuery("by", models.CFCoverageByBairro)
+	logger := observability.Logger().With(zap.String("group_by", by))
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "get_cf_coverage_stats"),
+		attribute.String("service", "cf_lookup"),
+		attribute.String("group_by", by),
+	)
+
+	logger.Debug("GetCFCoverageStats called")
+
+	// Validate grouping with tracing
+	ctx, groupSpan := utils.TraceInputValidation(ctx, "group_by", "by")
+	if !services.IsValidCFCoverageGroupBy(by) {
+		utils.RecordErrorInSpan(groupSpan, fmt.Errorf("invalid group by"), map[string]interface{}{
+			"group_by": by,
+		})
+		groupSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid 'by' parameter: must be %s or %s", models.CFCoverageByBairro, models.CFCoverageByMunicipio)})
+		return
+	}
+	groupSpan.End()
+
+	// Check if CF lookup service is available
+	if services.CFLookupServiceInstance == nil {
+		logger.Error("CF lookup service not initialized")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "CF lookup service unavailable"})
+		return
+	}
+
+	// Aggregate coverage with tracing
+	ctx, serviceSpan := utils.TraceBusinessLogic(ctx, "get_cf_coverage_stats")
+	stats, err := services.CFLookupServiceInstance.GetCoverageStats(ctx, by)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"operation": "GetCoverageStats",
+		})
+		serviceSpan.End()
+		logger.Error("failed to get CF coverage stats", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.region_count", len(stats.Regions))
+	serviceSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, stats)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetCFCoverageStats completed",
+		zap.Int("region_count", len(stats.Regions)),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// EnqueueCFLookupBulk godoc
+// @Summary Reprocessar a consulta de Clínica da Família de um bairro ou município
+// @Description Enfileira jobs cf_lookup para todos os cidadãos cujo endereço atual (autodeclarado, depois dados base) está no bairro e/ou município informado, ignorando maiúsculas e minúsculas. Usado quando uma nova Clínica da Família é inaugurada. Os jobs respeitam o rate limit global de CF (CF_LOOKUP_GLOBAL_RATE_LIMIT) e o progresso pode ser acompanhado pelo batch_id retornado. Com dry_run=true apenas conta os cidadãos (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body models.CFLookupBulkRequest true "Filtro de região (bairro e/ou município)"
+// @Security BearerAuth
+// @Success 200 {object} models.CFLookupBulkResponse "Jobs de CF enfileirados"
+// @Failure 400 {object} ErrorResponse "Dados inválidos ou filtro de região ausente"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} ErrorResponse "Consulta de CF desabilitada"
+// @Router /admin/cf-lookup/bulk [post]
+func EnqueueCFLookupBulk(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "EnqueueCFLookupBulk")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "enqueue_cf_lookup_bulk"),
+		attribute.String("service", "cf_lookup"),
+	)
+
+	logger.Debug("EnqueueCFLookupBulk called")
+
+	// Parse input with tracing
+	ctx, parseSpan := utils.TraceInputParsing(ctx, "cf_lookup_bulk_request")
+	var request models.CFLookupBulkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RecordErrorInSpan(parseSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "CFLookupBulkRequest",
+		})
+		parseSpan.End()
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
+	request.Bairro = strings.TrimSpace(request.Bairro)
+	request.Municipio = strings.TrimSpace(request.Municipio)
+	if request.Bairro == "" && request.Municipio == "" {
+		utils.RecordErrorInSpan(parseSpan, fmt.Errorf("missing region filter"), nil)
+		parseSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "bairro or municipio is required"})
+		return
+	}
+	utils.AddSpanAttribute(parseSpan, "input.bairro", request.Bairro)
+	utils.AddSpanAttribute(parseSpan, "input.municipio", request.Municipio)
+	utils.AddSpanAttribute(parseSpan, "input.dry_run", request.DryRun)
+	parseSpan.End()
+
+	// Check if CF lookup service is available
+	if services.CFLookupServiceInstance == nil {
+		logger.Warn("CF lookup service disabled - bulk CF lookup rejected")
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "CF lookup service unavailable"})
+		return
+	}
+
+	// Stream matching citizens and enqueue their jobs with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "cf_lookup_service", "enqueue_cf_lookups_by_region")
+	response, err := services.CFLookupServiceInstance.EnqueueCFLookupsByRegion(ctx, request)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"operation": "EnqueueCFLookupsByRegion",
+		})
+		serviceSpan.End()
+		logger.Error("failed to enqueue bulk CF lookups",
+			zap.Error(err),
+			zap.String("bairro", request.Bairro),
+			zap.String("municipio", request.Municipio))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.batch_id", response.BatchID)
+	utils.AddSpanAttribute(serviceSpan, "response.enqueued", response.Enqueued)
+	serviceSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Info("EnqueueCFLookupBulk completed",
+		zap.String("batch_id", response.BatchID),
+		zap.Int("matched", response.Matched),
+		zap.Int("enqueued", response.Enqueued),
+		zap.Bool("dry_run", response.DryRun),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// GetCFLookupBulkStatus godoc
+// @Summary Progresso de um reprocessamento de Clínica da Família em lote
+// @Description Retorna quantos jobs cf_lookup de um lote foram enfileirados, concluídos e falharam. O progresso fica disponível por 7 dias (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Param batch_id path string true "ID do lote retornado por POST /admin/cf-lookup/bulk"
+// @Security BearerAuth
+// @Success 200 {object} models.CFLookupBatchStatus "Progresso do lote"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 404 {object} ErrorResponse "Lote não encontrado ou expirado"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/cf-lookup/bulk/{batch_id} [get]
+func GetCFLookupBulkStatus(c *gin.Context) {
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCFLookupBulkStatus")
+	defer span.End()
+
+	batchID := c.Param("batch_id")
+	logger := observability.Logger().With(zap.String("batch_id", batchID))
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "get_cf_lookup_bulk_status"),
+		attribute.String("service", "cf_lookup"),
+		attribute.String("batch_id", batchID),
+	)
+
+	status, err := services.GetCFLookupBatchStatus(ctx, batchID)
+	if errors.Is(err, services.ErrCFLookupBatchNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "CF lookup batch not found"})
+		return
+	}
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{
+			"operation": "GetCFLookupBatchStatus",
+		})
+		logger.Error("failed to get CF lookup batch status", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+	logger.Debug("GetCFLookupBulkStatus completed",
+		zap.Int("enqueued", status.Enqueued),
+		zap.Int("completed", status.Completed),
+		zap.String("status", "success"))
+}
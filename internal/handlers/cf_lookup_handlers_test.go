@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCFCoverageStatsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/stats/cf-coverage", GetCFCoverageStats)
+	return router
+}
+
+func TestGetCFCoverageStats_InvalidGroupBy(t *testing.T) {
+	router := setupCFCoverageStatsRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/stats/cf-coverage?by=estado", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid 'by' parameter")
+}
+
+func TestGetCFCoverageStats_ServiceUnavailable(t *testing.T) {
+	original := services.CFLookupServiceInstance
+	services.CFLookupServiceInstance = nil
+	defer func() { services.CFLookupServiceInstance = original }()
+
+	router := setupCFCoverageStatsRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/stats/cf-coverage", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "CF lookup service unavailable")
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/middleware"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/services"
@@ -31,16 +34,18 @@ var Version = "dev"
 func queueCFLookupJob(ctx context.Context, cpf, address string) {
 	logger := observability.Logger().With(zap.String("cpf", cpf))
 
-	// Create CF lookup job
+	// Create CF lookup job, carrying the trace context so the worker continues this trace
+	jobData := map[string]interface{}{
+		"cpf":     cpf,
+		"address": address,
+	}
+	observability.InjectTraceContextIntoJob(ctx, jobData)
 	job := services.SyncJob{
 		ID:         fmt.Sprintf("cf_lookup_%s_%d", cpf, time.Now().UnixNano()),
 		Type:       "cf_lookup",
 		Key:        cpf,
 		Collection: "cf_lookup",
-		Data: map[string]interface{}{
-			"cpf":     cpf,
-			"address": address,
-		},
+		Data:       jobData,
 		Timestamp:  time.Now(),
 		RetryCount: 0,
 		MaxRetries: 3,
@@ -63,16 +68,42 @@ func queueCFLookupJob(ctx context.Context, cpf, address string) {
 	logger.Debug("CF lookup job queued successfully", zap.String("job_id", job.ID))
 }
 
+// queueAddressChangeWebhook notifies downstream systems of an address change (only if enabled).
+// Failures are logged and never fail the update.
+func queueAddressChangeWebhook(ctx context.Context, cpf string, oldAddress, newAddress *models.Endereco) {
+	if !config.AppConfig.AddressWebhookEnabled {
+		return
+	}
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	oldAddressHash := ""
+	if oldAddress != nil && oldAddress.Principal != nil {
+		oldAddressHash = services.HashAddress(buildAddressString(oldAddress.Principal))
+	}
+	newAddressHash := services.HashAddress(buildAddressString(newAddress.Principal))
+
+	if err := services.QueueAddressChangeWebhook(ctx, cpf, oldAddressHash, newAddressHash); err != nil {
+		logger.Error("failed to queue address change webhook", zap.Error(err))
+		return
+	}
+
+	logger.Debug("address change webhook queued")
+}
+
 // GetCitizenData godoc
 // @Summary Obter dados do cidadão
-// @Description Recupera os dados do cidadão por CPF, incluindo informações básicas e dados autodeclarados.
+// @Description Recupera os dados do cidadão por CPF, incluindo informações básicas e dados autodeclarados. Com include_derived=true, inclui o objeto _derived com valores calculados pelo servidor (endereço formatado, idade e contatos mascarados). O objeto self_declared_status informa, para cada campo autodeclarado presente, a data da última atualização (last_updated) e se o dado está desatualizado (is_stale) segundo SELF_DECLARED_OUTDATED_THRESHOLD; campos sem data são considerados desatualizados. O header ETag contém a versão atual dos dados autodeclarados, a ser enviada no header If-Match das atualizações. Tokens com um scope listado em MASKED_RESPONSE_SCOPES recebem CPF, telefones e emails mascarados, exceto ao acessar o próprio CPF.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Param include_derived query bool false "Incluir campos derivados calculados pelo servidor (padrão: false)"
+// @Param fields query string false "Campos de primeiro nível a retornar, separados por vírgula (ex: nome,telefone,endereco); padrão: todos"
 // @Security BearerAuth
-// @Success 200 {object} models.Citizen "Dados do cidadão obtidos com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Success 200 {object} models.CitizenResponse "Dados do cidadão obtidos com sucesso (apenas os campos solicitados quando fields é informado)"
+// @Header 200 {string} ETag "Versão atual dos dados autodeclarados (seguida de um hash dos campos quando fields é informado)"
+// @Header 200 {string} X-Data-Stale "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED)"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou campo desconhecido em fields"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
@@ -103,14 +134,29 @@ func GetCitizenData(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
 
+	// Parse requested fields with tracing
+	ctx, fieldsSpan := utils.TraceInputParsing(ctx, "citizen_fields")
+	fields, err := parseFieldsParam(c.Query("fields"), responseFieldNames(models.CitizenResponse{}))
+	if err != nil {
+		utils.RecordErrorInSpan(fieldsSpan, err, map[string]interface{}{
+			"fields": c.Query("fields"),
+		})
+		fieldsSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeFieldsInvalid, Message: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(fieldsSpan, "citizen.fields", strings.Join(fields, ","))
+	fieldsSpan.End()
+
 	// Use getMergedCitizenData which implements cache-aware reading
 	ctx, getDataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
 	citizen, err := getMergedCitizenData(ctx, cpf)
+	servedStale := false
 	if err != nil {
 		utils.RecordErrorInSpan(getDataSpan, err, map[string]interface{}{
 			"operation": "getMergedCitizenData",
@@ -118,32 +164,33 @@ func GetCitizenData(c *gin.Context) {
 		})
 		getDataSpan.End()
 		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Citizen not found"})
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeCitizenNotFound, Message: "Citizen not found"})
 			return
 		}
-		logger.Error("failed to get citizen data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
-		return
+		staleCitizen, ok := readStaleCitizen(ctx, c, logger, cpf, "citizen", err)
+		if !ok {
+			logger.Error("failed to get citizen data", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+			return
+		}
+		citizen, servedStale = staleCitizen, true
+	} else {
+		getDataSpan.End()
 	}
-	getDataSpan.End()
+	utils.AddSpanAttribute(span, "citizen.served_stale", servedStale)
 
-	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+	if !servedStale {
+		observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
 
-	// Cache the merged result with tracing
-	ctx, cacheSetSpan := utils.TraceCacheSet(ctx, fmt.Sprintf("citizen:%s", cpf), config.AppConfig.RedisTTL)
-	if jsonData, err := json.Marshal(citizen); err == nil {
-		config.Redis.Set(ctx, fmt.Sprintf("citizen:%s", cpf), jsonData, config.AppConfig.RedisTTL)
-		utils.AddSpanAttribute(cacheSetSpan, "cache.set_success", true)
-	} else {
-		utils.RecordErrorInSpan(cacheSetSpan, err, map[string]interface{}{
-			"cache.operation": "set",
-		})
+		// Cache the merged result with tracing
+		cacheMergedCitizen(ctx, cpf, citizen)
 	}
-	cacheSetSpan.End()
 
 	// Check for CF lookup and queue background job if needed (only if enabled)
 	ctx, cfSpan := utils.TraceBusinessLogic(ctx, "cf_lookup_check")
-	if services.CFLookupServiceInstance != nil {
+	if servedStale {
+		logger.Debug("serving stale citizen data - skipping CF lookup check", zap.String("cpf", cpf))
+	} else if services.CFIntegrationEnabled(ctx, cpf) {
 		shouldLookup, address, err := services.CFLookupServiceInstance.ShouldLookupCF(ctx, cpf, citizen)
 		if err != nil {
 			logger.Warn("failed to check CF lookup status", zap.Error(err))
@@ -153,18 +200,48 @@ func GetCitizenData(c *gin.Context) {
 			queueCFLookupJob(ctx, cpf, address)
 		}
 	} else {
-		logger.Debug("CF lookup service disabled - skipping CF lookup check", zap.String("cpf", cpf))
+		logger.Debug("CF integration disabled - skipping CF lookup check", zap.String("cpf", cpf))
 	}
 	cfSpan.End()
 
 	// Convert to response model (excluding wallet fields) with tracing
 	ctx, convertSpan := utils.TraceBusinessLogic(ctx, "convert_to_citizen_response")
-	citizenResponse := citizen.ToCitizenResponse()
+	var citizenResponse *models.CitizenResponse
+	if middleware.ShouldMaskResponse(c) {
+		citizenResponse = citizen.ToCitizenResponseMasked(utils.PIIMasker{})
+		utils.AddSpanAttribute(convertSpan, "masked", true)
+	} else {
+		citizenResponse = citizen.ToCitizenResponse()
+	}
+	if c.Query("include_derived") == "true" {
+		citizenResponse.Derived = buildDerivedFields(citizen, time.Now())
+		utils.AddSpanAttribute(convertSpan, "include_derived", true)
+	}
 	convertSpan.End()
 
+	// Expose the self-declared version for conditional updates (If-Match); the version can't
+	// be read while MongoDB is unavailable
+	if !servedStale {
+		if version, err := services.GetSelfDeclaredVersion(ctx, cpf); err != nil {
+			logger.Warn("failed to get self-declared version", zap.Error(err))
+		} else if fields != nil {
+			setProjectedSelfDeclaredVersionHeader(c, version, fields)
+		} else {
+			setSelfDeclaredVersionHeader(c, version)
+		}
+	}
+
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
-	c.JSON(http.StatusOK, citizenResponse)
+	if fields == nil {
+		c.JSON(http.StatusOK, citizenResponse)
+	} else if projected, err := projectFields(citizenResponse, fields); err != nil {
+		utils.RecordErrorInSpan(responseSpan, err, nil)
+		logger.Error("failed to project citizen response", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+	} else {
+		c.JSON(http.StatusOK, projected)
+	}
 	responseSpan.End()
 
 	// Log total operation time
@@ -177,6 +254,44 @@ func GetCitizenData(c *gin.Context) {
 
 // Helper: Get merged citizen data (as delivered by /citizen/{cpf})
 func getMergedCitizenData(ctx context.Context, cpf string) (*models.Citizen, error) {
+	citizen, _, err := getMergedCitizenDataWithSelfDeclared(ctx, cpf)
+	return citizen, err
+}
+
+// cacheMergedCitizen caches the merged citizen data, the copy served stale when MongoDB is
+// unavailable (see readStaleCitizen)
+func cacheMergedCitizen(ctx context.Context, cpf string, citizen *models.Citizen) {
+	ctx, cacheSetSpan := utils.TraceCacheSet(ctx, fmt.Sprintf("citizen:%s", cpf), config.CacheTTLFor(config.CacheNamespaceCitizen))
+	defer cacheSetSpan.End()
+
+	jsonData, err := json.Marshal(citizen)
+	if err != nil {
+		utils.RecordErrorInSpan(cacheSetSpan, err, map[string]interface{}{
+			"cache.operation": "set",
+		})
+		return
+	}
+	config.Redis.Set(ctx, fmt.Sprintf("citizen:%s", cpf), jsonData, config.CacheTTLFor(config.CacheNamespaceCitizen))
+	utils.AddSpanAttribute(cacheSetSpan, "cache.set_success", true)
+}
+
+// getMergedCitizenDataWithSelfDeclared is getMergedCitizenData also returning the self-declared
+// data it merged, for callers that need more than the merged view (e.g. phone verification)
+func getMergedCitizenDataWithSelfDeclared(ctx context.Context, cpf string) (*models.Citizen, models.SelfDeclaredData, error) {
+	citizen, err := getBaseCitizenData(ctx, cpf)
+	if err != nil {
+		return nil, models.SelfDeclaredData{}, err
+	}
+
+	// Use batched Redis operations for self-declared data with MongoDB fallback
+	selfDeclared, updatedAt := getBatchedSelfDeclaredData(ctx, cpf)
+	mergeSelfDeclaredData(citizen, selfDeclared, updatedAt)
+
+	return citizen, selfDeclared, nil
+}
+
+// getBaseCitizenData reads the citizen data without the self-declared fields
+func getBaseCitizenData(ctx context.Context, cpf string) (*models.Citizen, error) {
 	// Create data manager for cache-aware reads
 	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
 
@@ -190,51 +305,71 @@ func getMergedCitizenData(ctx context.Context, cpf string) (*models.Citizen, err
 		}
 	}
 
-	// Use batched Redis operations for self-declared data with MongoDB fallback
-	selfDeclared := getBatchedSelfDeclaredData(ctx, cpf)
-	// Merge logic (same as in GetCitizenData)
+	return &citizen, nil
+}
+
+// mergeSelfDeclaredData overlays the self-declared data on the citizen data following
+// models.SelfDeclaredMergeRules, the same rules GET /citizen/merge-rules exposes
+func mergeSelfDeclaredData(citizen *models.Citizen, selfDeclared models.SelfDeclaredData, updatedAt map[string]*time.Time) {
+	applyMergeRules(citizen, selfDeclared, models.SelfDeclaredMergeRules())
+	citizen.SelfDeclaredStatus = buildSelfDeclaredStatus(selfDeclared, updatedAt, time.Now())
+}
+
+// buildSelfDeclaredStatus reports the staleness of every self-declared field merged into the
+// citizen. Address, email and phone use the timestamp of their principal value; the other
+// fields use the time they were last written.
+func buildSelfDeclaredStatus(selfDeclared models.SelfDeclaredData, updatedAt map[string]*time.Time, now time.Time) map[string]models.SelfDeclaredFieldStatus {
+	threshold := config.AppConfig.SelfDeclaredOutdatedThreshold
+	status := make(map[string]models.SelfDeclaredFieldStatus)
+
 	if selfDeclared.Endereco != nil && selfDeclared.Endereco.Principal != nil {
-		if citizen.Endereco == nil {
-			citizen.Endereco = &models.Endereco{}
-		}
-		citizen.Endereco.Principal = selfDeclared.Endereco.Principal
-		if citizen.Endereco.Indicador == nil {
-			citizen.Endereco.Indicador = utils.BoolPtr(true)
-		}
+		status["endereco"] = models.NewSelfDeclaredFieldStatus(selfDeclared.Endereco.Principal.UpdatedAt, threshold, now)
 	}
 	if selfDeclared.Email != nil && selfDeclared.Email.Principal != nil {
-		if citizen.Email == nil {
-			citizen.Email = &models.Email{}
-		}
-		citizen.Email.Principal = selfDeclared.Email.Principal
-		if citizen.Email.Indicador == nil {
-			citizen.Email.Indicador = utils.BoolPtr(true)
-		}
+		status["email"] = models.NewSelfDeclaredFieldStatus(selfDeclared.Email.Principal.UpdatedAt, threshold, now)
 	}
+	// Only a verified phone is merged into the citizen
 	if selfDeclared.Telefone != nil && selfDeclared.Telefone.Principal != nil && selfDeclared.Telefone.Indicador != nil && *selfDeclared.Telefone.Indicador {
-		if citizen.Telefone == nil {
-			citizen.Telefone = &models.Telefone{}
+		status["telefone"] = models.NewSelfDeclaredFieldStatus(selfDeclared.Telefone.Principal.UpdatedAt, threshold, now)
+	}
+
+	scalarFields := map[string]*string{
+		"raca":           selfDeclared.Raca,
+		"nome_exibicao":  selfDeclared.NomeExibicao,
+		"genero":         selfDeclared.Genero,
+		"renda_familiar": selfDeclared.RendaFamiliar,
+		"escolaridade":   selfDeclared.Escolaridade,
+		"deficiencia":    selfDeclared.Deficiencia,
+	}
+	for field, value := range scalarFields {
+		if value != nil {
+			status[field] = models.NewSelfDeclaredFieldStatus(updatedAt[field], threshold, now)
 		}
-		citizen.Telefone.Principal = selfDeclared.Telefone.Principal
-		citizen.Telefone.Indicador = utils.BoolPtr(true)
 	}
-	if selfDeclared.Raca != nil {
-		citizen.Raca = selfDeclared.Raca
+
+	if len(status) == 0 {
+		return nil
 	}
-	// Always set exhibition name field (even if nil) to ensure it appears in JSON response
-	citizen.NomeExibicao = selfDeclared.NomeExibicao
-	// Set new demographic fields
-	citizen.Genero = selfDeclared.Genero
-	citizen.RendaFamiliar = selfDeclared.RendaFamiliar
-	citizen.Escolaridade = selfDeclared.Escolaridade
-	citizen.Deficiencia = selfDeclared.Deficiencia
+	return status
+}
 
-	return &citizen, nil
+// parseSelfDeclaredTimestamp parses the updated_at of a buffered self-declared entry
+func parseSelfDeclaredTimestamp(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || parsed.IsZero() {
+		return nil
+	}
+	return &parsed
 }
 
-// getBatchedSelfDeclaredData efficiently retrieves self-declared data using Redis batch operations
-func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDeclaredData {
+// getBatchedSelfDeclaredData efficiently retrieves self-declared data using Redis batch operations.
+// It also returns when each scalar field (raca, genero, ...) was last written, keyed by field name.
+func getBatchedSelfDeclaredData(ctx context.Context, cpf string) (models.SelfDeclaredData, map[string]*time.Time) {
 	var selfDeclared models.SelfDeclaredData
+	updatedAt := make(map[string]*time.Time)
 
 	// Use batched Redis operations to fetch all self-declared data
 	keys := []string{
@@ -300,6 +435,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[3], "raca", &racaData) && racaData.Raca != nil {
 		selfDeclared.Raca = racaData.Raca
+		updatedAt["raca"] = parseSelfDeclaredTimestamp(racaData.UpdatedAt)
 	}
 
 	var nomeExibicaoData struct {
@@ -309,6 +445,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[4], "nome_exibicao", &nomeExibicaoData) && nomeExibicaoData.NomeExibicao != nil {
 		selfDeclared.NomeExibicao = nomeExibicaoData.NomeExibicao
+		updatedAt["nome_exibicao"] = parseSelfDeclaredTimestamp(nomeExibicaoData.UpdatedAt)
 	}
 
 	var generoData struct {
@@ -318,6 +455,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[5], "genero", &generoData) && generoData.Genero != nil {
 		selfDeclared.Genero = generoData.Genero
+		updatedAt["genero"] = parseSelfDeclaredTimestamp(generoData.UpdatedAt)
 	}
 
 	var rendaFamiliarData struct {
@@ -327,6 +465,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[6], "renda_familiar", &rendaFamiliarData) && rendaFamiliarData.RendaFamiliar != nil {
 		selfDeclared.RendaFamiliar = rendaFamiliarData.RendaFamiliar
+		updatedAt["renda_familiar"] = parseSelfDeclaredTimestamp(rendaFamiliarData.UpdatedAt)
 	}
 
 	var escolaridadeData struct {
@@ -336,6 +475,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[7], "escolaridade", &escolaridadeData) && escolaridadeData.Escolaridade != nil {
 		selfDeclared.Escolaridade = escolaridadeData.Escolaridade
+		updatedAt["escolaridade"] = parseSelfDeclaredTimestamp(escolaridadeData.UpdatedAt)
 	}
 
 	var deficienciaData struct {
@@ -345,6 +485,7 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 	}
 	if parseResult(keys[8], "deficiencia", &deficienciaData) && deficienciaData.Deficiencia != nil {
 		selfDeclared.Deficiencia = deficienciaData.Deficiencia
+		updatedAt["deficiencia"] = parseSelfDeclaredTimestamp(deficienciaData.UpdatedAt)
 	}
 
 	// If write buffer didn't have everything, try read cache in batch
@@ -392,21 +533,27 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 		}
 		if selfDeclared.Raca == nil && parseCacheResult(cacheKeys[3], "raca", &racaData) && racaData.Raca != nil {
 			selfDeclared.Raca = racaData.Raca
+			updatedAt["raca"] = parseSelfDeclaredTimestamp(racaData.UpdatedAt)
 		}
 		if selfDeclared.NomeExibicao == nil && parseCacheResult(cacheKeys[4], "nome_exibicao", &nomeExibicaoData) && nomeExibicaoData.NomeExibicao != nil {
 			selfDeclared.NomeExibicao = nomeExibicaoData.NomeExibicao
+			updatedAt["nome_exibicao"] = parseSelfDeclaredTimestamp(nomeExibicaoData.UpdatedAt)
 		}
 		if selfDeclared.Genero == nil && parseCacheResult(cacheKeys[5], "genero", &generoData) && generoData.Genero != nil {
 			selfDeclared.Genero = generoData.Genero
+			updatedAt["genero"] = parseSelfDeclaredTimestamp(generoData.UpdatedAt)
 		}
 		if selfDeclared.RendaFamiliar == nil && parseCacheResult(cacheKeys[6], "renda_familiar", &rendaFamiliarData) && rendaFamiliarData.RendaFamiliar != nil {
 			selfDeclared.RendaFamiliar = rendaFamiliarData.RendaFamiliar
+			updatedAt["renda_familiar"] = parseSelfDeclaredTimestamp(rendaFamiliarData.UpdatedAt)
 		}
 		if selfDeclared.Escolaridade == nil && parseCacheResult(cacheKeys[7], "escolaridade", &escolaridadeData) && escolaridadeData.Escolaridade != nil {
 			selfDeclared.Escolaridade = escolaridadeData.Escolaridade
+			updatedAt["escolaridade"] = parseSelfDeclaredTimestamp(escolaridadeData.UpdatedAt)
 		}
 		if selfDeclared.Deficiencia == nil && parseCacheResult(cacheKeys[8], "deficiencia", &deficienciaData) && deficienciaData.Deficiencia != nil {
 			selfDeclared.Deficiencia = deficienciaData.Deficiencia
+			updatedAt["deficiencia"] = parseSelfDeclaredTimestamp(deficienciaData.UpdatedAt)
 		}
 	}
 
@@ -434,6 +581,12 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 			observability.Logger().Warn("failed to fetch self-declared data from MongoDB",
 				zap.String("cpf", cpf), zap.Error(err))
 		} else if err == nil {
+			// Scalar fields have no timestamp of their own in MongoDB, so the document's is used
+			var documentUpdatedAt *time.Time
+			if !mongoSelfDeclared.UpdatedAt.IsZero() {
+				documentUpdatedAt = &mongoSelfDeclared.UpdatedAt
+			}
+
 			// Fill in missing fields from MongoDB
 			if selfDeclared.Endereco == nil && mongoSelfDeclared.Endereco != nil {
 				selfDeclared.Endereco = mongoSelfDeclared.Endereco
@@ -446,21 +599,27 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 			}
 			if selfDeclared.Raca == nil && mongoSelfDeclared.Raca != nil {
 				selfDeclared.Raca = mongoSelfDeclared.Raca
+				updatedAt["raca"] = documentUpdatedAt
 			}
 			if selfDeclared.NomeExibicao == nil && mongoSelfDeclared.NomeExibicao != nil {
 				selfDeclared.NomeExibicao = mongoSelfDeclared.NomeExibicao
+				updatedAt["nome_exibicao"] = documentUpdatedAt
 			}
 			if selfDeclared.Genero == nil && mongoSelfDeclared.Genero != nil {
 				selfDeclared.Genero = mongoSelfDeclared.Genero
+				updatedAt["genero"] = documentUpdatedAt
 			}
 			if selfDeclared.RendaFamiliar == nil && mongoSelfDeclared.RendaFamiliar != nil {
 				selfDeclared.RendaFamiliar = mongoSelfDeclared.RendaFamiliar
+				updatedAt["renda_familiar"] = documentUpdatedAt
 			}
 			if selfDeclared.Escolaridade == nil && mongoSelfDeclared.Escolaridade != nil {
 				selfDeclared.Escolaridade = mongoSelfDeclared.Escolaridade
+				updatedAt["escolaridade"] = documentUpdatedAt
 			}
 			if selfDeclared.Deficiencia == nil && mongoSelfDeclared.Deficiencia != nil {
 				selfDeclared.Deficiencia = mongoSelfDeclared.Deficiencia
+				updatedAt["deficiencia"] = documentUpdatedAt
 			}
 
 			observability.Logger().Debug("filled missing self-declared fields from MongoDB",
@@ -468,23 +627,27 @@ func getBatchedSelfDeclaredData(ctx context.Context, cpf string) models.SelfDecl
 		}
 	}
 
-	return selfDeclared
+	return selfDeclared, updatedAt
 }
 
 // UpdateSelfDeclaredAddress godoc
 // @Summary Atualizar endereço autodeclarado
-// @Description Atualiza ou cria o endereço autodeclarado de um cidadão por CPF. Apenas o campo de endereço é atualizado.
+// @Description Atualiza ou cria o endereço autodeclarado de um cidadão por CPF. Apenas o campo de endereço é atualizado. O endereço é normalizado antes da comparação com os dados atuais: CEP com 8 dígitos sem pontuação, estado como UF maiúscula (aceita também o nome do estado) e bairro e logradouro com iniciais maiúsculas. CEP ou UF inválidos retornam 422 quando as regras address_cep e address_uf estão em modo enforce.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredAddressInput true "Endereço autodeclarado"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Endereço autodeclarado atualizado com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou dados de endereço incorretos"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou dados de endereço incorretos"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 409 {object} ErrorResponse "Conflito - endereço não alterado (dados idênticos aos atuais)"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - informações de endereço inválidas"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
@@ -513,7 +676,7 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -526,15 +689,31 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 			"input": input,
 		})
 		parseSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid input: " + err.Error()})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	parseSpan.End()
 
-	// Validate input with tracing
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
+	// Normalize and validate input with tracing
 	ctx, validateSpan := utils.TraceInputValidation(ctx, "address_validation", "address")
 	// Note: SelfDeclaredAddressInput doesn't have a Validate method
-	// We'll rely on the binding validation and business logic validation
+	// We'll rely on the binding validation, the configurable rules and business logic validation.
+	// Normalizing before the comparison keeps equivalent formats from counting as a change.
+	if err := utils.NormalizeSelfDeclaredAddress(&input); err != nil {
+		utils.RecordErrorInSpan(validateSpan, err, map[string]interface{}{
+			"input.cep":    input.CEP,
+			"input.estado": input.Estado,
+		})
+		validateSpan.End()
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Code: ErrCodeValidationRule, Message: err.Error()})
+		return
+	}
 	validateSpan.End()
 
 	// Get current address data for comparison with tracing (optimized to fetch only address field)
@@ -547,52 +726,28 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 		})
 		findSpan.End()
 		logger.Error("failed to fetch current address data for comparison", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check current data: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
 		return
 	}
 	findSpan.End()
 
 	// Compare data with tracing
 	ctx, compareSpan := utils.TraceDataComparison(ctx, "address_comparison")
-	if current != nil && current.Principal != nil &&
-		*current.Principal.Bairro == input.Bairro &&
-		*current.Principal.CEP == input.CEP &&
-		(current.Principal.Complemento == nil && input.Complemento == nil ||
-			(current.Principal.Complemento != nil && input.Complemento != nil && *current.Principal.Complemento == *input.Complemento)) &&
-		*current.Principal.Estado == input.Estado &&
-		*current.Principal.Logradouro == input.Logradouro &&
-		*current.Principal.Municipio == input.Municipio &&
-		*current.Principal.Numero == input.Numero &&
-		(current.Principal.TipoLogradouro == nil && input.TipoLogradouro == nil ||
-			(current.Principal.TipoLogradouro != nil && input.TipoLogradouro != nil && *current.Principal.TipoLogradouro == *input.TipoLogradouro)) {
+	if selfDeclaredAddressMatches(current, input) {
 		compareSpan.End()
-		c.JSON(http.StatusConflict, ErrorResponse{Error: "No change: address matches current data"})
+		c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodeAddressUnchanged, Message: "No change: address matches current data"})
 		return
 	}
 	compareSpan.End()
 
 	// Build address object with tracing
 	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_address_object")
-	origem := "self-declared"
-	sistema := "rmi"
-	now := time.Now()
-	endereco := models.Endereco{
-		Indicador: utils.BoolPtr(true),
-		Principal: &models.EnderecoPrincipal{
-			Bairro:         &input.Bairro,
-			CEP:            &input.CEP,
-			Complemento:    input.Complemento,
-			Estado:         &input.Estado,
-			Logradouro:     &input.Logradouro,
-			Municipio:      &input.Municipio,
-			Numero:         &input.Numero,
-			TipoLogradouro: input.TipoLogradouro,
-			Origem:         &origem,
-			Sistema:        &sistema,
-			UpdatedAt:      &now,
-		},
-	}
+	endereco := buildSelfDeclaredAddress(input, time.Now())
 	buildSpan.End()
+	if err := endereco.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
 
 	// Use cache service for update with tracing
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_citizen_via_cache")
@@ -601,7 +756,12 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 	cacheService := services.NewCacheService()
 
 	// Update via cache service (this will queue for MongoDB sync)
-	err = cacheService.UpdateSelfDeclaredAddress(ctx, cpf, &endereco)
+	version, err := cacheService.UpdateSelfDeclaredAddress(ctx, cpf, &endereco, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_address",
@@ -609,7 +769,7 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 		})
 		updateSpan.End()
 		logger.Error("failed to update self-declared address via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update address: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to update address: " + err.Error()})
 		return
 	}
 	updateSpan.End()
@@ -619,9 +779,8 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 
 	// Invalidate old cache with tracing
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -651,36 +810,17 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 	}
 	auditSpan.End()
 
+	// Notify downstream systems of the address change (only if enabled)
+	queueAddressChangeWebhook(ctx, cpf, current, &endereco)
+
 	// Handle CF data invalidation when address changes (only if enabled)
 	ctx, cfInvalidateSpan := utils.TraceBusinessLogic(ctx, "cf_invalidate_on_address_change")
-	if services.CFLookupServiceInstance != nil {
-		newAddress := fmt.Sprintf("%s, %s, %s, %s, %s, %s",
-			input.Logradouro, input.Numero,
-			func() string {
-				if input.Complemento != nil {
-					return *input.Complemento
-				} else {
-					return ""
-				}
-			}(),
-			input.Bairro, input.Municipio, input.Estado)
-		newAddressHash := services.CFLookupServiceInstance.GenerateAddressHash(newAddress)
-
-		err = services.CFLookupServiceInstance.InvalidateCFDataForAddress(ctx, cpf, newAddressHash)
-		if err != nil {
-			logger.Warn("failed to invalidate CF data for address change", zap.Error(err))
-		} else {
-			// Queue new CF lookup for the updated address
-			logger.Debug("queuing CF lookup for updated address", zap.String("cpf", cpf))
-			queueCFLookupJob(ctx, cpf, newAddress)
-		}
-	} else {
-		logger.Debug("CF lookup service disabled - skipping CF data invalidation", zap.String("cpf", cpf))
-	}
+	refreshCFDataForAddress(ctx, cpf, input)
 	cfInvalidateSpan.End()
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared address updated successfully"})
 	responseSpan.End()
 
@@ -695,20 +835,23 @@ func UpdateSelfDeclaredAddress(c *gin.Context) {
 
 // UpdateSelfDeclaredPhone godoc
 // @Summary Atualizar telefone autodeclarado
-// @Description Atualiza ou cria o telefone autodeclarado de um cidadão por CPF. Apenas o campo de telefone é atualizado (armazenado como pendente até verificado).
+// @Description Atualiza ou cria o telefone autodeclarado de um cidadão por CPF. Apenas o campo de telefone é atualizado (armazenado como pendente até verificado). O número é normalizado (DDD válido, celular com 9 dígitos iniciando em 9 ou fixo com 8 dígitos) antes de iniciar a verificação.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredPhoneInput true "Telefone autodeclarado"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Telefone autodeclarado submetido para validação com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou dados de telefone incorretos"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou dados de telefone incorretos"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
 // @Failure 409 {object} ErrorResponse "Conflito - telefone não alterado (telefone corresponde aos dados atuais verificados)"
-// @Failure 422 {object} ErrorResponse "Dados não processáveis - formato de telefone inválido"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
+// @Failure 422 {object} ErrorResponse "Dados não processáveis - número de telefone inválido (DDD, quantidade de dígitos ou prefixo)"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/phone [put]
@@ -739,7 +882,7 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.ddi", input.DDI)
@@ -747,6 +890,12 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	// Validate CPF with tracing
 	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
 	if !utils.ValidateCPF(cpf) {
@@ -754,11 +903,30 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
 
+	// Normalize and validate phone number (DDD list, mobile/landline rules) with tracing
+	ctx, validationSpan := utils.TraceInputValidation(ctx, "phone_format", "phone")
+	normalized, err := utils.NormalizePhone(input.DDI, input.DDD, input.Valor)
+	if err != nil {
+		utils.RecordErrorInSpan(validationSpan, err, map[string]interface{}{
+			"input.ddi":   input.DDI,
+			"input.ddd":   input.DDD,
+			"input.valor": input.Valor,
+		})
+		validationSpan.End()
+		logger.Warn("invalid phone number", zap.Error(err))
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Code: ErrCodePhoneInvalid, Message: err.Error()})
+		return
+	}
+	input.DDI, input.DDD, input.Valor = normalized.DDI, normalized.DDD, normalized.Valor
+	utils.AddSpanAttribute(validationSpan, "validation.valid", true)
+	utils.AddSpanAttribute(validationSpan, "phone", normalized.Full)
+	validationSpan.End()
+
 	// Get current phone data for comparison with tracing (optimized to fetch only phone field)
 	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.SelfDeclaredCollection, "cpf")
 	current, err := getCurrentPhoneData(ctx, cpf)
@@ -769,7 +937,7 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 		})
 		findSpan.End()
 		logger.Error("failed to fetch current phone data for comparison", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check current data: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
 		return
 	}
 	findSpan.End()
@@ -782,19 +950,14 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 	// 1. They never verified it (Indicador != true)
 	// 2. The data is outdated (updated_at > threshold ago)
 	// 3. No updated_at timestamp exists (legacy data)
-	if current != nil && current.Telefone != nil && current.Telefone.Principal != nil &&
-		current.Telefone.Indicador != nil && *current.Telefone.Indicador &&
-		current.Telefone.Principal.DDI != nil && *current.Telefone.Principal.DDI == input.DDI &&
-		current.Telefone.Principal.DDD != nil && *current.Telefone.Principal.DDD == input.DDD &&
-		current.Telefone.Principal.Valor != nil && *current.Telefone.Principal.Valor == input.Valor {
-
+	if selfDeclaredPhoneMatchesVerified(current, normalized) {
 		// Check if data is outdated (allow re-declaration if outdated or no timestamp)
-		isOutdated := current.UpdatedAt == nil || time.Since(*current.UpdatedAt) > config.AppConfig.SelfDeclaredOutdatedThreshold
+		isOutdated := isSelfDeclaredOutdated(current.UpdatedAt)
 
 		if !isOutdated {
 			// Data is recent and matches - return conflict
 			compareSpan.End()
-			c.JSON(http.StatusConflict, ErrorResponse{Error: "No change: phone matches current data"})
+			c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodePhoneUnchanged, Message: "No change: phone matches current data"})
 			return
 		}
 
@@ -806,41 +969,29 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 	}
 	compareSpan.End()
 
-	// Build full phone number with tracing
-	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_phone_number")
-	fullPhone := input.DDI + input.DDD + input.Valor
-	buildSpan.End()
-
-	// Validate basic phone number format (length and digits only)
-	ctx, validationSpan := utils.TraceInputValidation(ctx, "phone_format", "phone")
-	if len(fullPhone) < 10 || len(fullPhone) > 15 {
-		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid phone number length"), map[string]interface{}{
-			"phone":  fullPhone,
-			"length": len(fullPhone),
-		})
-		validationSpan.End()
-		logger.Warn("invalid phone number length", zap.String("phone", fullPhone), zap.Int("length", len(fullPhone)))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid phone number format"})
+	// The phone is only written once verified, so the version precondition is checked now
+	if _, err := services.CheckSelfDeclaredVersion(ctx, cpf, expectedVersion); err != nil {
+		if isSelfDeclaredVersionConflict(err) {
+			respondSelfDeclaredVersionConflict(c, logger, err)
+			return
+		}
+		logger.Error("failed to check self-declared version", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
 		return
 	}
-	utils.AddSpanAttribute(validationSpan, "validation.valid", true)
-	utils.AddSpanAttribute(validationSpan, "phone", fullPhone)
-	validationSpan.End()
 
 	// DON'T update self-declared phone data yet - only store verification data
 	// This preserves any existing verified phone until the new one is verified
 	ctx, skipUpdateSpan := utils.TraceBusinessLogic(ctx, "skip_phone_update_until_verified")
 	logger.Debug("skipping phone update until verification - preserving existing verified phone",
 		zap.String("cpf", cpf),
-		zap.String("new_phone", fullPhone))
+		zap.String("new_phone", normalized.Full))
 	skipUpdateSpan.End()
 
-	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
-
 	// Invalidate cache with tracing
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
 	cacheKey := fmt.Sprintf("citizen:%s", cpf)
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := services.NewCacheService().InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
 			"cache.key": cacheKey,
 		})
@@ -848,52 +999,13 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 	}
 	cacheSpan.End()
 
-	// Generate verification code for phone verification process with tracing
-	ctx, codeSpan := utils.TraceBusinessLogic(ctx, "generate_verification_code")
-	code := utils.GenerateVerificationCode()
-	now := time.Now()
-	expiresAt := now.Add(config.AppConfig.PhoneVerificationTTL)
-	codeSpan.End()
-
-	// Prepare verification data with tracing
-	ctx, dataSpan := utils.TraceBusinessLogic(ctx, "prepare_verification_data")
-	verificationData := utils.PhoneVerificationData{
-		CPF:         cpf,
-		DDI:         input.DDI,
-		DDD:         input.DDD,
-		Valor:       input.Valor,
-		PhoneNumber: fullPhone,
-		Code:        code,
-		ExpiresAt:   expiresAt,
-	}
-	dataSpan.End()
-
-	// Delete previous verifications with tracing
-	ctx, deleteSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.PhoneVerificationCollection, "cpf", false)
-	verColl := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
-	_, err = verColl.DeleteMany(ctx, bson.M{"cpf": cpf})
+	// Replace previous verifications with a new one
+	fullPhone, err := startSelfDeclaredPhoneVerification(ctx, cpf, normalized)
 	if err != nil {
-		utils.RecordErrorInSpan(deleteSpan, err, map[string]interface{}{
-			"db.collection": config.AppConfig.PhoneVerificationCollection,
-			"db.operation":  "delete_many",
-		})
-		logger.Warn("failed to delete previous verifications", zap.Error(err))
-	}
-	deleteSpan.End()
-
-	// Create verification record with tracing
-	ctx, createSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.PhoneVerificationCollection, "cpf", false)
-	if err := utils.CreatePhoneVerification(ctx, verificationData); err != nil {
-		utils.RecordErrorInSpan(createSpan, err, map[string]interface{}{
-			"db.collection": config.AppConfig.PhoneVerificationCollection,
-			"db.operation":  "create",
-		})
-		createSpan.End()
 		logger.Error("failed to create phone verification", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start phone verification: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to start phone verification: " + err.Error()})
 		return
 	}
-	createSpan.End()
 
 	// Log audit event with tracing
 	ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "phone")
@@ -905,17 +1017,7 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 		RequestID: c.GetString("RequestID"),
 	}
 
-	oldValue := "none"
-	if current != nil && current.Telefone != nil && current.Telefone.Principal != nil {
-		oldValue = fmt.Sprintf("%s%s%s",
-			*current.Telefone.Principal.DDI,
-			*current.Telefone.Principal.DDD,
-			*current.Telefone.Principal.Valor)
-	}
-
-	newValue := fullPhone
-
-	err = utils.LogPhoneUpdate(ctx, auditCtx, oldValue, newValue)
+	err = utils.LogPhoneUpdate(ctx, auditCtx, currentPhoneAuditValue(current), fullPhone)
 	if err != nil {
 		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
 			"audit.action":   "update",
@@ -940,19 +1042,23 @@ func UpdateSelfDeclaredPhone(c *gin.Context) {
 
 // UpdateSelfDeclaredEmail godoc
 // @Summary Atualizar email autodeclarado
-// @Description Atualiza ou cria o email autodeclarado de um cidadão por CPF. Apenas o campo de email é atualizado.
+// @Description Atualiza ou cria o email autodeclarado de um cidadão por CPF. Apenas o campo de email é atualizado. Com EMAIL_VERIFICATION_ENABLED, o email fica pendente e um token de verificação é enviado; o email só passa a valer após validação em POST /citizen/{cpf}/email/validate.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredEmailInput true "Email autodeclarado"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Email autodeclarado atualizado com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou dados de email incorretos"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou dados de email incorretos"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
 // @Failure 409 {object} ErrorResponse "Conflito - email não alterado (email corresponde aos dados atuais)"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - formato de email inválido"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
@@ -984,12 +1090,18 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	// Validate CPF with tracing
 	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
 	if !utils.ValidateCPF(cpf) {
@@ -997,7 +1109,7 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -1011,7 +1123,7 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 			"errors": validationResult.Errors,
 		})
 		emailSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid email format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeEmailInvalid, Message: "Invalid email format"})
 		return
 	}
 	emailSpan.End()
@@ -1026,7 +1138,7 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 		})
 		findSpan.End()
 		logger.Error("failed to fetch current email data for comparison", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check current data: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
 		return
 	}
 	findSpan.End()
@@ -1037,16 +1149,19 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 	// This allows users to re-enter the same email if:
 	// 1. The data is outdated (updated_at > threshold ago)
 	// 2. No updated_at timestamp exists (legacy data)
-	if current != nil && current.Email != nil && current.Email.Principal != nil &&
-		current.Email.Principal.Valor != nil && *current.Email.Principal.Valor == input.Valor {
-
+	// With email verification enabled, an unverified email can also be re-entered to resend the token
+	emailMatches := selfDeclaredEmailMatches(current, input.Valor)
+	if config.AppConfig.EmailVerificationEnabled {
+		emailMatches = selfDeclaredEmailMatchesVerified(current, input.Valor)
+	}
+	if emailMatches {
 		// Check if data is outdated (allow re-declaration if outdated or no timestamp)
-		isOutdated := current.UpdatedAt == nil || time.Since(*current.UpdatedAt) > config.AppConfig.SelfDeclaredOutdatedThreshold
+		isOutdated := isSelfDeclaredOutdated(current.UpdatedAt)
 
 		if !isOutdated {
 			// Data is recent and matches - return conflict
 			compareSpan.End()
-			c.JSON(http.StatusConflict, ErrorResponse{Error: "No change: email matches current data"})
+			c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodeEmailUnchanged, Message: "No change: email matches current data"})
 			return
 		}
 
@@ -1058,44 +1173,63 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 	}
 	compareSpan.End()
 
-	// Build email object with tracing
-	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_email_object")
-	origem := "self-declared"
-	sistema := "rmi"
-	now := time.Now()
-	email := models.Email{
-		Indicador: utils.BoolPtr(true),
-		Principal: &models.EmailPrincipal{
-			Valor:     &input.Valor,
-			Origem:    &origem,
-			Sistema:   &sistema,
-			UpdatedAt: &now,
-		},
-	}
-	buildSpan.End()
+	var version int32
+	if config.AppConfig.EmailVerificationEnabled {
+		// The email is only written once verified, so the version precondition is checked now
+		if _, err := services.CheckSelfDeclaredVersion(ctx, cpf, expectedVersion); err != nil {
+			if isSelfDeclaredVersionConflict(err) {
+				respondSelfDeclaredVersionConflict(c, logger, err)
+				return
+			}
+			logger.Error("failed to check self-declared version", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
+			return
+		}
 
-	// Use cache service for update with tracing
-	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_email_via_cache")
-	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredEmail(ctx, cpf, &email)
-	if err != nil {
-		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
-			"cache.operation": "update_self_declared_email",
-			"cache.service":   "unified_cache_service",
-		})
+		// Keep the email pending (and any existing verified email untouched) until verified
+		if err := startSelfDeclaredEmailVerification(ctx, cpf, input.Valor); err != nil {
+			logger.Error("failed to create email verification", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to start email verification: " + err.Error()})
+			return
+		}
+	} else {
+		// Build email object with tracing
+		ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_email_object")
+		email := buildSelfDeclaredEmail(input.Valor, time.Now())
+		buildSpan.End()
+		if err := email.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+			return
+		}
+
+		// Use cache service for update with tracing
+		ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_email_via_cache")
+		cacheService := services.NewCacheService()
+		version, err = cacheService.UpdateSelfDeclaredEmail(ctx, cpf, &email, expectedVersion)
+		if isSelfDeclaredVersionConflict(err) {
+			updateSpan.End()
+			respondSelfDeclaredVersionConflict(c, logger, err)
+			return
+		}
+		if err != nil {
+			utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
+				"cache.operation": "update_self_declared_email",
+				"cache.service":   "unified_cache_service",
+			})
+			updateSpan.End()
+			logger.Error("failed to update self-declared email via cache service", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to update email: " + err.Error()})
+			return
+		}
 		updateSpan.End()
-		logger.Error("failed to update self-declared email via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update email: " + err.Error()})
-		return
-	}
-	updateSpan.End()
 
-	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+		observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+	}
 
 	// Invalidate cache with tracing
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
 	cacheKey := fmt.Sprintf("citizen:%s", cpf)
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := services.NewCacheService().InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
 			"cache.key": cacheKey,
 		})
@@ -1113,14 +1247,7 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 		RequestID: c.GetString("RequestID"),
 	}
 
-	oldValue := "none"
-	if current != nil && current.Email != nil && current.Email.Principal != nil && current.Email.Principal.Valor != nil {
-		oldValue = *current.Email.Principal.Valor
-	}
-
-	newValue := input.Valor
-
-	err = utils.LogEmailUpdate(ctx, auditCtx, oldValue, newValue)
+	err = utils.LogEmailUpdate(ctx, auditCtx, currentEmailAuditValue(current), input.Valor)
 	if err != nil {
 		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
 			"audit.action":   "update",
@@ -1132,7 +1259,12 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
-	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared email updated successfully"})
+	if config.AppConfig.EmailVerificationEnabled {
+		c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared email submitted for validation. Verification email sent."})
+	} else {
+		setSelfDeclaredVersionHeader(c, version)
+		c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared email updated successfully"})
+	}
 	responseSpan.End()
 
 	// Log total operation time
@@ -1151,12 +1283,16 @@ func UpdateSelfDeclaredEmail(c *gin.Context) {
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredRacaInput true "Etnia autodeclarada"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Etnia atualizada com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de etnia inválido"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou valor de etnia inválido"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - valor de etnia não é válido"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
@@ -1188,21 +1324,27 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	// Validate ethnicity with tracing
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "ethnicity_value", "ethnicity")
-	if !models.IsValidEthnicity(input.Valor) {
+	if !services.IsValidEthnicity(input.Valor) {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid ethnicity value: %s", input.Valor), map[string]interface{}{
 			"invalid_value": input.Valor,
 		})
 		validationSpan.End()
 		logger.Error("invalid ethnicity value", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ethnicity value"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeEthnicityInvalid, Message: "invalid ethnicity value"})
 		return
 	}
 	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
@@ -1220,7 +1362,7 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get self-declared data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 
@@ -1236,7 +1378,12 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 	// Use cache service for update with tracing
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_ethnicity_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredRaca(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredRaca(ctx, cpf, input.Valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_raca",
@@ -1245,7 +1392,7 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared ethnicity via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(updateSpan, "new_ethnicity", input.Valor)
@@ -1256,9 +1403,8 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 
 	// Invalidate cache with tracing
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -1290,6 +1436,7 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared ethnicity updated successfully"})
 	responseSpan.End()
 
@@ -1304,19 +1451,23 @@ func UpdateSelfDeclaredRaca(c *gin.Context) {
 
 // UpdateSelfDeclaredNomeExibicao godoc
 // @Summary Atualizar nome de exibição autodeclarado
-// @Description Atualiza ou cria o nome de exibição autodeclarado de um cidadão por CPF. Apenas o campo de nome de exibição é atualizado. O nome de exibição é o nome que aparece na interface do usuário, permitindo ao cidadão controlar como seu nome é exibido no aplicativo.
+// @Description Atualiza ou cria o nome de exibição autodeclarado de um cidadão por CPF. Apenas o campo de nome de exibição é atualizado. O nome de exibição é o nome que aparece na interface do usuário, permitindo ao cidadão controlar como seu nome é exibido no aplicativo. O nome deve ter entre EXHIBITION_NAME_MIN_LENGTH e 255 caracteres, conter apenas letras, espaços, apóstrofos, hífens e pontos, e não pode conter termos da lista EXHIBITION_NAME_BLOCKLIST.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredNomeExibicaoInput true "Nome de exibição autodeclarado"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Nome de exibição atualizado com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de nome de exibição inválido"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou nome de exibição vazio"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
-// @Failure 422 {object} ErrorResponse "Dados não processáveis - nome de exibição muito longo ou vazio"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
+// @Failure 422 {object} ErrorResponse "Nome de exibição viola uma regra (code EXHIBITION_NAME_TOO_SHORT, EXHIBITION_NAME_TOO_LONG, EXHIBITION_NAME_INVALID_CHARACTERS ou EXHIBITION_NAME_BLOCKED; details.rule indica a regra)"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/exhibition-name [put]
@@ -1344,7 +1495,7 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -1359,40 +1510,68 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
-	// Basic validation - check if name is not empty and not too long
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
+	// Validate the name against the length, character and blocklist rules
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "exhibition_name_value", "exhibition_name")
-	if len(strings.TrimSpace(input.Valor)) == 0 {
+	nomeExibicao := strings.TrimSpace(input.Valor)
+	if nomeExibicao == "" {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("exhibition name cannot be empty"), map[string]interface{}{
 			"invalid_value": input.Valor,
 		})
 		validationSpan.End()
 		logger.Error("exhibition name cannot be empty", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "exhibition name cannot be empty"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeExhibitionNameInvalid, Message: "exhibition name cannot be empty"})
 		return
 	}
-	if len(input.Valor) > 255 {
-		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("exhibition name too long: %d characters", len(input.Valor)), map[string]interface{}{
-			"invalid_value": input.Valor,
-			"length":        len(input.Valor),
+	if violation := utils.ValidateExhibitionName(nomeExibicao); violation != nil {
+		utils.RecordErrorInSpan(validationSpan, violation, map[string]interface{}{
+			"invalid_value":   nomeExibicao,
+			"validation.rule": violation.Rule,
 		})
 		validationSpan.End()
-		logger.Error("exhibition name too long", zap.String("value", input.Valor), zap.Int("length", len(input.Valor)))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "exhibition name too long (maximum 255 characters)"})
+		logger.Info("exhibition name rejected", zap.String("rule", violation.Rule), zap.Error(violation))
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    exhibitionNameErrorCodes[violation.Rule],
+			Message: violation.Message,
+			Details: map[string]string{"rule": violation.Rule},
+		})
 		return
 	}
-	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
+	utils.AddSpanAttribute(validationSpan, "validated_value", nomeExibicao)
 	validationSpan.End()
 
+	// Get the current exhibition name so the audit log records the value being replaced
+	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.SelfDeclaredCollection, "cpf")
+	previousNomeExibicao, err := getCurrentNomeExibicao(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(findSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.SelfDeclaredCollection,
+			"db.filter":     "cpf",
+		})
+		logger.Warn("failed to fetch current exhibition name for audit", zap.Error(err))
+	}
+	findSpan.End()
+
 	// Use cache service for update with tracing
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_exhibition_name_via_cache")
 	cacheService := services.NewCacheService()
-	err := cacheService.UpdateSelfDeclaredNomeExibicao(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredNomeExibicao(ctx, cpf, nomeExibicao, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_nome_exibicao",
@@ -1401,10 +1580,10 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared exhibition name via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
-	utils.AddSpanAttribute(updateSpan, "new_exhibition_name", input.Valor)
+	utils.AddSpanAttribute(updateSpan, "new_exhibition_name", nomeExibicao)
 	updateSpan.End()
 
 	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
@@ -1412,9 +1591,8 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 
 	// Invalidate cache with tracing
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -1434,8 +1612,7 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 		RequestID: c.GetString("RequestID"),
 	}
 
-	// For audit, we log the new value as both old and new for now since we don't have historical data
-	err = utils.LogExhibitionNameUpdate(ctx, auditCtx, "", input.Valor)
+	err = utils.LogExhibitionNameUpdate(ctx, auditCtx, previousNomeExibicao, nomeExibicao)
 	if err != nil {
 		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
 			"audit.action":   "update",
@@ -1447,6 +1624,7 @@ func UpdateSelfDeclaredNomeExibicao(c *gin.Context) {
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared exhibition name updated successfully"})
 	responseSpan.End()
 
@@ -1612,7 +1790,7 @@ func GetFirstLogin(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -1662,7 +1840,7 @@ func GetFirstLogin(c *gin.Context) {
 		logger.Error("failed to get user config via DataManager",
 			zap.String("cpf", cpf),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get user config"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
 		return
 	}
 	utils.AddSpanAttribute(dbSpan, "user_config.found", true)
@@ -1693,7 +1871,7 @@ func GetFirstLogin(c *gin.Context) {
 
 // UpdateFirstLogin godoc
 // @Summary Atualizar status do primeiro login
-// @Description Define o status do primeiro login como falso para um usuário
+// @Description Define o status do primeiro login como falso para um usuário. Com DEFAULT_AVATAR_MODE ativo, atribui um avatar padrão do conjunto de avatares ativos a usuários que ainda não escolheram um e o retorna em avatar
 // @Tags citizen
 // @Accept json
 // @Produce json
@@ -1730,25 +1908,26 @@ func UpdateFirstLogin(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
 
-	// Build user config object with tracing
-	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_user_config_object")
-	userConfig := &models.UserConfig{
-		CPF:        cpf,
-		FirstLogin: false,
-		OptIn:      true, // Default to opted in
-		UpdatedAt:  time.Now(),
+	// Pick a default avatar for users completing the first login without one
+	ctx, avatarSpan := utils.TraceBusinessLogic(ctx, "default_avatar_assignment")
+	defaultAvatar := pickFirstLoginAvatar(ctx, cpf)
+	fields := bson.M{"first_login": false}
+	if defaultAvatar != nil {
+		fields["avatar_id"] = defaultAvatar.ID.Hex()
+		utils.AddSpanAttribute(avatarSpan, "avatar_id", defaultAvatar.ID.Hex())
 	}
-	buildSpan.End()
+	avatarSpan.End()
 
-	// Update via cache service with tracing
+	// Update only the first login (and default avatar) fields via cache service with tracing, so a
+	// concurrent opt-in update during onboarding isn't overwritten
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_user_config_via_cache")
 	cacheService := services.NewCacheService()
-	err := cacheService.UpdateUserConfig(ctx, cpf, userConfig)
+	err := cacheService.PatchUserConfig(ctx, cpf, fields)
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_user_config",
@@ -1756,7 +1935,7 @@ func UpdateFirstLogin(c *gin.Context) {
 		})
 		updateSpan.End()
 		logger.Error("failed to update first login status via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update first login status"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to update first login status"})
 		return
 	}
 	updateSpan.End()
@@ -1792,21 +1971,64 @@ func UpdateFirstLogin(c *gin.Context) {
 		})
 		logger.Warn("failed to log audit event", zap.Error(err))
 	}
+	if defaultAvatar != nil {
+		if err := utils.LogUserConfigUpdate(ctx, auditCtx, "avatar_id", nil, defaultAvatar.ID.Hex()); err != nil {
+			logger.Warn("failed to log default avatar audit event", zap.Error(err))
+		}
+	}
 	auditSpan.End()
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
-	c.JSON(http.StatusOK, models.UserConfigResponse{FirstLogin: false})
+	response := models.UserConfigResponse{FirstLogin: false}
+	if defaultAvatar != nil {
+		avatarResponse := defaultAvatar.ToResponse()
+		response.Avatar = &avatarResponse
+	}
+	c.JSON(http.StatusOK, response)
 	responseSpan.End()
 
 	// Log total operation time
 	totalDuration := time.Since(startTime)
 	logger.Debug("UpdateFirstLogin completed",
 		zap.String("cpf", cpf),
+		zap.Bool("default_avatar_assigned", defaultAvatar != nil),
 		zap.Duration("total_duration", totalDuration),
 		zap.String("status", "success"))
 }
 
+// pickFirstLoginAvatar returns the default avatar to assign when the CPF completes its first
+// login, or nil when DEFAULT_AVATAR_MODE is off, the first login was already completed or the
+// user already picked an avatar during onboarding. Failures are logged and skip the assignment,
+// so they never block the first login update.
+func pickFirstLoginAvatar(ctx context.Context, cpf string) *models.Avatar {
+	if config.AppConfig.DefaultAvatarMode == config.DefaultAvatarModeOff || services.AvatarServiceInstance == nil {
+		return nil
+	}
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	var userConfig models.UserConfig
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+	err := dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &userConfig)
+	if err == services.ErrDocumentNotFound {
+		// No config yet: the user is on their first login and has no avatar
+		userConfig = models.UserConfig{FirstLogin: true}
+	} else if err != nil {
+		logger.Warn("failed to read user config for default avatar", zap.Error(err))
+		return nil
+	}
+	if !userConfig.FirstLogin || userConfig.AvatarID != nil {
+		return nil
+	}
+
+	avatar, err := services.AvatarServiceInstance.PickDefaultAvatar(ctx, cpf)
+	if err != nil {
+		logger.Warn("failed to pick default avatar", zap.Error(err))
+		return nil
+	}
+	return avatar
+}
+
 // GetOptIn godoc
 // @Summary Obter status de opt-in
 // @Description Verifica se o usuário optou por receber notificações
@@ -1846,7 +2068,7 @@ func GetOptIn(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -1896,7 +2118,7 @@ func GetOptIn(c *gin.Context) {
 		})
 		dbSpan.End()
 		logger.Error("failed to get user config via DataManager", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get user config"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
 		return
 	}
 	utils.AddSpanAttribute(dbSpan, "user_config.found", true)
@@ -1906,10 +2128,17 @@ func GetOptIn(c *gin.Context) {
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
 	optInValue := userConfig.OptIn
-	c.JSON(http.StatusOK, models.UserConfigOptInResponse{
+	response := models.UserConfigOptInResponse{
 		OptIn:          &optInValue,
 		CategoryOptIns: userConfig.CategoryOptIns,
-	})
+	}
+	if userConfig.OptOutReason != nil {
+		response.Reason = *userConfig.OptOutReason
+	}
+	if userConfig.OptOutNote != nil {
+		response.Note = *userConfig.OptOutNote
+	}
+	c.JSON(http.StatusOK, response)
 	responseSpan.End()
 
 	// Log total operation time
@@ -1923,7 +2152,7 @@ func GetOptIn(c *gin.Context) {
 
 // UpdateOptIn godoc
 // @Summary Atualizar status de opt-in
-// @Description Atualiza o status de opt-in do usuário para notificações
+// @Description Atualiza o status de opt-in do usuário para notificações. No opt-out, aceita um motivo (reason, código de /config/opt-out-reasons) e uma observação livre (note), que são salvos na configuração do usuário e no histórico de opt-in. O motivo é obrigatório quando OPT_OUT_REASON_REQUIRED está habilitado.
 // @Tags citizen
 // @Accept json
 // @Produce json
@@ -1931,7 +2160,7 @@ func GetOptIn(c *gin.Context) {
 // @Param data body models.UserConfigOptInResponse true "Status de opt-in"
 // @Security BearerAuth
 // @Success 200 {object} models.UserConfigOptInResponse "Status do opt-in atualizado com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou dados de opt-in incorretos"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido, dados de opt-in incorretos ou motivo de opt-out ausente/inválido"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - valor de opt-in inválido"
@@ -1962,7 +2191,7 @@ func UpdateOptIn(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
@@ -1976,25 +2205,43 @@ func UpdateOptIn(c *gin.Context) {
 			"input.type": "UserConfigOptInResponse",
 		})
 		inputSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeInvalidRequestBody, Message: "Invalid request body: " + err.Error()})
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.opt_in", *input.OptIn)
+	utils.AddSpanAttribute(inputSpan, "input.reason", input.Reason)
 	inputSpan.End()
 
-	// Build user config object with tracing
-	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_user_config_object")
-	userConfig := &models.UserConfig{
-		CPF:       cpf,
-		OptIn:     *input.OptIn,
-		UpdatedAt: time.Now(),
+	// Validate opt-out reason with tracing
+	ctx, reasonSpan := utils.TraceInputValidation(ctx, "opt_out_reason", "reason")
+	if err := validateOptOutReason(&input); err != nil {
+		utils.RecordErrorInSpan(reasonSpan, err, map[string]interface{}{
+			"reason": input.Reason,
+		})
+		reasonSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeOptOutReasonInvalid, Message: err.Error()})
+		return
 	}
-	buildSpan.End()
+	reasonSpan.End()
 
-	// Update via cache service with tracing
+	var reason, note *string
+	if input.Reason != "" {
+		reason = &input.Reason
+	}
+	if input.Note != "" {
+		note = &input.Note
+	}
+
+	// Update only the opt-in fields via cache service with tracing, so a concurrent
+	// first login update during onboarding isn't overwritten. Opting back in clears
+	// the stored opt-out reason.
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_user_config_via_cache")
 	cacheService := services.NewCacheService()
-	err := cacheService.UpdateUserConfig(ctx, cpf, userConfig)
+	err := cacheService.PatchUserConfig(ctx, cpf, bson.M{
+		"opt_in":         *input.OptIn,
+		"opt_out_reason": reason,
+		"opt_out_note":   note,
+	})
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_user_config",
@@ -2002,7 +2249,7 @@ func UpdateOptIn(c *gin.Context) {
 		})
 		updateSpan.End()
 		logger.Error("failed to update opt-in status via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update opt-in status"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to update opt-in status"})
 		return
 	}
 	updateSpan.End()
@@ -2020,6 +2267,16 @@ func UpdateOptIn(c *gin.Context) {
 	}
 	cacheSpan.End()
 
+	// Record opt-in history with tracing, so churn reasons can be analyzed alongside the phone flows
+	ctx, historySpan := utils.TraceBusinessLogic(ctx, "record_opt_in_history")
+	if err := recordUserConfigOptInHistory(ctx, cpf, *input.OptIn, reason, note); err != nil {
+		utils.RecordErrorInSpan(historySpan, err, map[string]interface{}{
+			"collection": config.AppConfig.OptInHistoryCollection,
+		})
+		logger.Warn("failed to record opt-in history", zap.Error(err))
+	}
+	historySpan.End()
+
 	// Log audit event with tracing
 	ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "opt_in")
 	auditCtx := utils.AuditContext{
@@ -2054,13 +2311,61 @@ func UpdateOptIn(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// validateOptOutReason checks the reason and note sent with an opt-in update: they're only accepted
+// when opting out, the reason must be one of the configured opt-out reasons and it may be required
+// by OPT_OUT_REASON_REQUIRED
+func validateOptOutReason(input *models.UserConfigOptInResponse) error {
+	if *input.OptIn {
+		if input.Reason != "" || input.Note != "" {
+			return fmt.Errorf("reason and note are only accepted when opting out")
+		}
+		return nil
+	}
+	if input.Reason == "" {
+		if config.AppConfig.OptOutReasonRequired {
+			return fmt.Errorf("reason is required when opting out")
+		}
+		return nil
+	}
+	if !services.IsValidOptOutReason(input.Reason) {
+		return fmt.Errorf("invalid opt-out reason: %s", input.Reason)
+	}
+	return nil
+}
+
+// recordUserConfigOptInHistory records a global opt-in change made through the citizen config
+// in the opt-in history collection
+func recordUserConfigOptInHistory(ctx context.Context, cpf string, optIn bool, reason, note *string) error {
+	oldValue := !optIn
+	action := models.OptInActionOptOut
+	if optIn {
+		action = models.OptInActionOptIn
+	}
+
+	history := models.OptInHistory{
+		CPF:       cpf,
+		Action:    action,
+		Scope:     models.OptInScopeGlobal,
+		Channel:   models.ChannelWeb,
+		Reason:    reason,
+		Note:      note,
+		OldValue:  &oldValue,
+		NewValue:  &optIn,
+		Timestamp: time.Now(),
+	}
+
+	_, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).InsertOne(ctx, history)
+	return err
+}
+
 // GetEthnicityOptions godoc
 // @Summary Listar opções de etnia
-// @Description Retorna a lista de opções válidas de etnia para autodeclaração. Esta lista é usada para validar as atualizações de etnia autodeclarada.
+// @Description Retorna a lista de opções válidas de etnia para autodeclaração. Esta lista é usada para validar as atualizações de etnia autodeclarada. A lista é editável por administradores (POST e DELETE /admin/ethnicity/options) e mantida em memória; o header Last-Modified informa a última atualização.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Success 200 {array} string "Lista de opções de etnia válidas obtida com sucesso"
+// @Header 200 {string} Last-Modified "Horário da última atualização da lista em memória"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/ethnicity/options [get]
 func GetEthnicityOptions(c *gin.Context) {
@@ -2081,11 +2386,17 @@ func GetEthnicityOptions(c *gin.Context) {
 	// Get ethnicity options with tracing
 	ctx, optionsSpan := utils.TraceBusinessLogic(ctx, "get_valid_ethnicity_options")
 	options := models.ValidEthnicityOptions()
+	var refreshedAt time.Time
+	if services.ConfigServiceInstance != nil {
+		options = services.ConfigServiceInstance.GetEthnicityOptions()
+		refreshedAt = services.ConfigServiceInstance.LastRefresh(services.StaticListEthnicityOptions)
+	}
 	utils.AddSpanAttribute(optionsSpan, "options.count", len(options))
 	optionsSpan.End()
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setStaticListLastModified(c, refreshedAt)
 	c.JSON(http.StatusOK, options)
 	responseSpan.End()
 
@@ -2097,6 +2408,14 @@ func GetEthnicityOptions(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// setStaticListLastModified exposes when a cached static list was last refreshed
+func setStaticListLastModified(c *gin.Context, refreshedAt time.Time) {
+	if refreshedAt.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", refreshedAt.UTC().Format(http.TimeFormat))
+}
+
 // UpdateSelfDeclaredGenero godoc
 // @Summary Atualizar gênero autodeclarado
 // @Description Atualiza ou cria o gênero autodeclarado de um cidadão por CPF. Aceita qualquer valor de texto livre, mas as opções sugeridas estão disponíveis no endpoint /citizen/gender/options.
@@ -2105,11 +2424,15 @@ func GetEthnicityOptions(c *gin.Context) {
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredGeneroInput true "Gênero autodeclarado"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Gênero atualizado com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de gênero vazio"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou valor de gênero vazio"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/gender [put]
 func UpdateSelfDeclaredGenero(c *gin.Context) {
@@ -2131,7 +2454,7 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 	// Validate CPF format
 	if !utils.ValidateCPF(cpf) {
 		logger.Error("invalid CPF format")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "invalid CPF format"})
 		return
 	}
 
@@ -2144,12 +2467,18 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "gender_value", "gender")
 	if !models.IsValidGender(input.Valor) {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid gender value: %s", input.Valor), map[string]interface{}{
@@ -2157,7 +2486,7 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 		})
 		validationSpan.End()
 		logger.Error("invalid gender value", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "gender cannot be empty"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeGenderInvalid, Message: "gender cannot be empty"})
 		return
 	}
 	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
@@ -2174,7 +2503,7 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get self-declared data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 
@@ -2188,7 +2517,12 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_gender_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredGenero(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredGenero(ctx, cpf, input.Valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_genero",
@@ -2197,7 +2531,7 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared gender via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(updateSpan, "new_gender", input.Valor)
@@ -2207,9 +2541,8 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
 
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -2220,6 +2553,7 @@ func UpdateSelfDeclaredGenero(c *gin.Context) {
 	cacheSpan.End()
 
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared gender updated successfully"})
 	responseSpan.End()
 
@@ -2278,11 +2612,15 @@ func GetGenderOptions(c *gin.Context) {
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredRendaFamiliarInput true "Renda familiar autodeclarada"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Renda familiar atualizada com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de renda familiar inválido"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou valor de renda familiar inválido"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - valor de renda familiar não é válido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/family-income [put]
@@ -2305,7 +2643,7 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 	// Validate CPF format
 	if !utils.ValidateCPF(cpf) {
 		logger.Error("invalid CPF format")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "invalid CPF format"})
 		return
 	}
 
@@ -2318,12 +2656,18 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "family_income_value", "family_income")
 	if !models.IsValidFamilyIncome(input.Valor) {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid family income value: %s", input.Valor), map[string]interface{}{
@@ -2331,7 +2675,7 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 		})
 		validationSpan.End()
 		logger.Error("invalid family income value", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid family income value"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeFamilyIncomeInvalid, Message: "invalid family income value"})
 		return
 	}
 	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
@@ -2348,7 +2692,7 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get self-declared data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 
@@ -2362,7 +2706,12 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_family_income_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredRendaFamiliar(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredRendaFamiliar(ctx, cpf, input.Valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_renda_familiar",
@@ -2371,7 +2720,7 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared family income via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(updateSpan, "new_family_income", input.Valor)
@@ -2381,9 +2730,8 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
 
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -2394,6 +2742,7 @@ func UpdateSelfDeclaredRendaFamiliar(c *gin.Context) {
 	cacheSpan.End()
 
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared family income updated successfully"})
 	responseSpan.End()
 
@@ -2452,11 +2801,15 @@ func GetFamilyIncomeOptions(c *gin.Context) {
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredEscolaridadeInput true "Escolaridade autodeclarada"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Escolaridade atualizada com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de escolaridade inválido"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou valor de escolaridade inválido"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - valor de escolaridade não é válido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/education [put]
@@ -2479,7 +2832,7 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 	// Validate CPF format
 	if !utils.ValidateCPF(cpf) {
 		logger.Error("invalid CPF format")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "invalid CPF format"})
 		return
 	}
 
@@ -2492,12 +2845,18 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "education_value", "education")
 	if !models.IsValidEducation(input.Valor) {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid education value: %s", input.Valor), map[string]interface{}{
@@ -2505,7 +2864,7 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 		})
 		validationSpan.End()
 		logger.Error("invalid education value", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid education value"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeEducationInvalid, Message: "invalid education value"})
 		return
 	}
 	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
@@ -2522,7 +2881,7 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get self-declared data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 
@@ -2536,7 +2895,12 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_education_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredEscolaridade(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredEscolaridade(ctx, cpf, input.Valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_escolaridade",
@@ -2545,7 +2909,7 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared education via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(updateSpan, "new_education", input.Valor)
@@ -2555,9 +2919,8 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
 
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -2568,6 +2931,7 @@ func UpdateSelfDeclaredEscolaridade(c *gin.Context) {
 	cacheSpan.End()
 
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared education updated successfully"})
 	responseSpan.End()
 
@@ -2626,11 +2990,15 @@ func GetEducationOptions(c *gin.Context) {
 // @Produce json
 // @Param cpf path string true "Número do CPF"
 // @Param data body models.SelfDeclaredDeficienciaInput true "Deficiência autodeclarada"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
 // @Security BearerAuth
 // @Success 200 {object} SuccessResponse "Deficiência atualizada com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou valor de deficiência inválido"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido ou valor de deficiência inválido"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
 // @Failure 422 {object} ErrorResponse "Dados não processáveis - valor de deficiência não é válido"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/disability [put]
@@ -2653,7 +3021,7 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 	// Validate CPF format
 	if !utils.ValidateCPF(cpf) {
 		logger.Error("invalid CPF format")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "invalid CPF format"})
 		return
 	}
 
@@ -2666,12 +3034,18 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 		})
 		inputSpan.End()
 		logger.Error("failed to parse input", zap.Error(err))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid input format"})
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
 		return
 	}
 	utils.AddSpanAttribute(inputSpan, "input.valor", input.Valor)
 	inputSpan.End()
 
+	expectedVersion, versionErr := expectedSelfDeclaredVersion(c, input.Version)
+	if versionErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: versionErr.Error()})
+		return
+	}
+
 	ctx, validationSpan := utils.TraceInputValidation(ctx, "disability_value", "disability")
 	if !models.IsValidDisability(input.Valor) {
 		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid disability value: %s", input.Valor), map[string]interface{}{
@@ -2679,7 +3053,7 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 		})
 		validationSpan.End()
 		logger.Error("invalid disability value", zap.String("value", input.Valor))
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid disability value"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeDisabilityInvalid, Message: "invalid disability value"})
 		return
 	}
 	utils.AddSpanAttribute(validationSpan, "validated_value", input.Valor)
@@ -2696,7 +3070,7 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get self-declared data", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 
@@ -2710,7 +3084,12 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_disability_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredDeficiencia(ctx, cpf, input.Valor)
+	version, err := cacheService.UpdateSelfDeclaredDeficiencia(ctx, cpf, input.Valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		updateSpan.End()
+		respondSelfDeclaredVersionConflict(c, logger, err)
+		return
+	}
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_self_declared_deficiencia",
@@ -2719,7 +3098,7 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 		updateSpan.End()
 		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
 		logger.Error("failed to update self-declared disability via cache service", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(updateSpan, "new_disability", input.Valor)
@@ -2729,9 +3108,8 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
 
 	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
-	cacheKey := fmt.Sprintf("citizen:%s", cpf)
 	cacheStart := time.Now()
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.invalidation_error", err.Error())
 		logger.Warn("failed to invalidate old cache", zap.Error(err))
 	} else {
@@ -2742,6 +3120,7 @@ func UpdateSelfDeclaredDeficiencia(c *gin.Context) {
 	cacheSpan.End()
 
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setSelfDeclaredVersionHeader(c, version)
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Self-declared disability updated successfully"})
 	responseSpan.End()
 
@@ -2799,9 +3178,12 @@ func GetDisabilityOptions(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Param sections query string false "Seções a retornar, separadas por vírgula (documentos, saude, assistencia_social, educacao); padrão: todas. A consulta de clínica da família só é feita quando saude é solicitada"
+// @Param fields query string false "Campos de primeiro nível a retornar, separados por vírgula (ex: cpf,saude); aplicado após sections"
 // @Security BearerAuth
-// @Success 200 {object} models.CitizenWallet "Dados da carteira do cidadão obtidos com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Success 200 {object} models.CitizenWallet "Dados da carteira do cidadão obtidos com sucesso (apenas cpf e as seções solicitadas quando sections é informado)"
+// @Header 200 {string} X-Data-Stale "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED)"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou seção desconhecida"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
@@ -2832,34 +3214,64 @@ func GetCitizenWallet(c *gin.Context) {
 			"cpf": cpf,
 		})
 		cpfSpan.End()
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
 		return
 	}
 	cpfSpan.End()
 
+	// Parse requested sections with tracing
+	ctx, sectionsSpan := utils.TraceInputParsing(ctx, "wallet_sections")
+	sections, err := parseWalletSections(c.Query("sections"))
+	if err != nil {
+		utils.RecordErrorInSpan(sectionsSpan, err, map[string]interface{}{
+			"sections": c.Query("sections"),
+		})
+		sectionsSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeWalletSectionInvalid, Message: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(sectionsSpan, "wallet.sections", c.Query("sections"))
+	fields, err := parseFieldsParam(c.Query("fields"), responseFieldNames(models.CitizenWallet{}))
+	if err != nil {
+		utils.RecordErrorInSpan(sectionsSpan, err, map[string]interface{}{
+			"fields": c.Query("fields"),
+		})
+		sectionsSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeFieldsInvalid, Message: err.Error()})
+		return
+	}
+	sectionsSpan.End()
+
 	// Use DataManager for cache-aware reading with tracing
 	ctx, dataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
 	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
 
 	var citizen models.Citizen
-	err := dataManager.Read(ctx, cpf, config.AppConfig.CitizenCollection, "citizen", &citizen)
+	err = dataManager.Read(ctx, cpf, config.AppConfig.CitizenCollection, "citizen", &citizen)
+	servedStale := false
 	if err != nil {
 		utils.RecordErrorInSpan(dataSpan, err, map[string]interface{}{
 			"operation": "dataManager.Read",
 			"cpf":       cpf,
 			"type":      "citizen",
 		})
-		dataSpan.End()
 		if err == services.ErrDocumentNotFound {
+			dataSpan.End()
 			logger.Debug("citizen wallet not found",
 				zap.String("cpf", cpf),
 				zap.String("collection", config.AppConfig.CitizenCollection))
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "citizen not found"})
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeCitizenNotFound, Message: "citizen not found"})
 			return
 		}
-		logger.Error("failed to get citizen data via DataManager", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
-		return
+		staleCitizen, ok := readStaleCitizen(ctx, c, logger, cpf, "wallet", err)
+		if !ok {
+			dataSpan.End()
+			logger.Error("failed to get citizen data via DataManager", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
+			return
+		}
+		citizen, servedStale = *staleCitizen, true
+		utils.AddSpanAttribute(dataSpan, "citizen.served_stale", true)
 	}
 	utils.AddSpanAttribute(dataSpan, "citizen.found", true)
 	utils.AddSpanAttribute(dataSpan, "citizen.has_documentos", citizen.Documentos != nil)
@@ -2868,24 +3280,143 @@ func GetCitizenWallet(c *gin.Context) {
 	utils.AddSpanAttribute(dataSpan, "citizen.has_educacao", citizen.Educacao != nil)
 	dataSpan.End()
 
-	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+	if !servedStale {
+		observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+	}
 
 	// Create wallet response with tracing
 	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_citizen_wallet")
-	wallet := models.CitizenWallet{
-		CPF:               cpf,
-		Documentos:        citizen.Documentos,
-		Saude:             citizen.Saude,
-		AssistenciaSocial: citizen.AssistenciaSocial,
-		Educacao:          citizen.Educacao,
+	wallet := buildCitizenWallet(ctx, logger, cpf, &citizen, sections, servedStale)
+	buildSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	var response interface{} = wallet
+	if sections != nil {
+		response = filterWalletSections(&wallet, sections)
+	}
+	if fields != nil {
+		projected, err := projectFields(response, fields)
+		if err != nil {
+			utils.RecordErrorInSpan(responseSpan, err, nil)
+			responseSpan.End()
+			logger.Error("failed to project wallet response", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+			return
+		}
+		response = projected
+	}
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetCitizenWallet completed",
+		zap.String("cpf", cpf),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// buildCitizenWallet builds the requested sections of a citizen's wallet (sections nil means the
+// whole wallet), integrating the CF data into saude.clinica_familia. Each section is built on its
+// own: one that fails is returned as null with a warning instead of failing the whole wallet.
+func buildCitizenWallet(ctx context.Context, logger *logging.SafeLogger, cpf string, citizen *models.Citizen, sections map[string]bool, servedStale bool) models.CitizenWallet {
+	wallet := models.CitizenWallet{CPF: cpf}
+	requested := func(section string) bool { return sections == nil || sections[section] }
+
+	if requested(models.WalletSectionDocumentos) {
+		populateWalletSection(logger, &wallet, models.WalletSectionDocumentos, func() (err error) {
+			wallet.Documentos, err = checkedWalletSection(citizen.Documentos)
+			return err
+		})
+	}
+	if requested(models.WalletSectionSaude) {
+		populateWalletSection(logger, &wallet, models.WalletSectionSaude, func() (err error) {
+			if wallet.Saude, err = checkedWalletSection(citizen.Saude); err != nil {
+				return err
+			}
+			integrateWalletCFData(ctx, logger, cpf, citizen, &wallet, sections, servedStale)
+			// Check again, now with the CF data
+			wallet.Saude, err = checkedWalletSection(wallet.Saude)
+			return err
+		})
+	}
+	if requested(models.WalletSectionAssistenciaSocial) {
+		populateWalletSection(logger, &wallet, models.WalletSectionAssistenciaSocial, func() (err error) {
+			wallet.AssistenciaSocial, err = checkedWalletSection(citizen.AssistenciaSocial)
+			return err
+		})
 	}
+	if requested(models.WalletSectionEducacao) {
+		populateWalletSection(logger, &wallet, models.WalletSectionEducacao, func() (err error) {
+			wallet.Educacao, err = checkedWalletSection(citizen.Educacao)
+			return err
+		})
+	}
+
+	return wallet
+}
+
+// populateWalletSection runs populate, which fills in one section of the wallet. An error or
+// panic is logged and counted, and the section is cleared and reported in the wallet warnings.
+func populateWalletSection(logger *logging.SafeLogger, wallet *models.CitizenWallet, section string, populate func() error) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return populate()
+	}()
+	if err == nil {
+		return
+	}
+
+	logger.Error("failed to build wallet section - returning it as null",
+		zap.String("section", section),
+		zap.Error(err))
+	observability.WalletSectionFailuresTotal.WithLabelValues(section).Inc()
+
+	switch section {
+	case models.WalletSectionDocumentos:
+		wallet.Documentos = nil
+	case models.WalletSectionSaude:
+		wallet.Saude = nil
+		wallet.ClinicaFamiliaStatus = ""
+		wallet.CFLookupPending = false
+	case models.WalletSectionAssistenciaSocial:
+		wallet.AssistenciaSocial = nil
+	case models.WalletSectionEducacao:
+		wallet.Educacao = nil
+	}
+	wallet.Warnings = append(wallet.Warnings, models.WalletWarning{Section: section, Message: "section unavailable"})
+}
+
+// checkedWalletSection returns the section if it can be serialized, so malformed data (e.g. NaN
+// values) fails its own section instead of the whole response
+func checkedWalletSection[T any](section *T) (*T, error) {
+	if section == nil {
+		return nil, nil
+	}
+	if _, err := json.Marshal(section); err != nil {
+		return nil, fmt.Errorf("malformed section data: %w", err)
+	}
+	return section, nil
+}
 
-	// Check if we need to populate CF data in saude.clinica_familia
+// integrateWalletCFData integrates the CF data into the wallet's saude.clinica_familia when saude
+// is requested. The CF lookup is skipped when the citizen was served stale, since it depends on
+// MongoDB.
+func integrateWalletCFData(ctx context.Context, logger *logging.SafeLogger, cpf string, citizen *models.Citizen, wallet *models.CitizenWallet, sections map[string]bool, servedStale bool) {
+	// Check if we need to populate CF data in saude.clinica_familia; the CF lookup is skipped
+	// entirely when saude wasn't requested
 	ctx, cfDataSpan := utils.TraceBusinessLogic(ctx, "cf_data_integration_wallet")
 	needsCFData := false
-	if wallet.Saude == nil || wallet.Saude.ClinicaFamilia == nil ||
-		wallet.Saude.ClinicaFamilia.Indicador == nil || !*wallet.Saude.ClinicaFamilia.Indicador {
-		needsCFData = true
+	if sections == nil || sections[models.WalletSectionSaude] {
+		if wallet.Saude == nil || wallet.Saude.ClinicaFamilia == nil ||
+			wallet.Saude.ClinicaFamilia.Indicador == nil || !*wallet.Saude.ClinicaFamilia.Indicador {
+			needsCFData = true
+		}
 	}
 
 	logger.Info("WALLET CF CHECK", zap.Bool("needs_cf_data", needsCFData))
@@ -2908,12 +3439,17 @@ func GetCitizenWallet(c *gin.Context) {
 		zap.Bool("indicador_is_false", indicadorIsFalse),
 		zap.String("operation", "cf_integration_debug"))
 
-	if needsCFData {
+	if needsCFData && servedStale {
+		// CF lookups depend on MongoDB, which is unavailable when serving stale data
+		logger.Debug("serving stale wallet data - skipping CF data integration", zap.String("cpf", cpf))
+		wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusUnavailable
+	} else if needsCFData {
 		logger.Debug("attempting to get CF data for citizen", zap.String("cpf", cpf))
 
-		// Check if CF lookup service is available
-		if services.CFLookupServiceInstance == nil {
-			logger.Info("CF lookup service disabled - skipping CF data integration", zap.String("cpf", cpf))
+		// Check if CF integration is enabled for this citizen (service available and flag on)
+		if !services.CFIntegrationEnabled(ctx, cpf) {
+			logger.Info("CF integration disabled - skipping CF data integration", zap.String("cpf", cpf))
+			wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusUnavailable
 		} else {
 			// First try to get existing cached CF data
 			logger.Info("CHECKING FOR CACHED CF DATA", zap.String("cpf", cpf))
@@ -2926,20 +3462,51 @@ func GetCitizenWallet(c *gin.Context) {
 				address := getSelfDeclaredAddressForCFLookup(ctx, cpf)
 				if address == "" {
 					// Fallback to extraction from citizen data
-					address = services.CFLookupServiceInstance.ExtractAddress(&citizen)
+					address = services.CFLookupServiceInstance.ExtractAddress(citizen)
 				}
 				logger.Info("EXTRACTED ADDRESS FOR CF LOOKUP", zap.String("address", address))
 
-				if address != "" {
+				if address != "" && services.CFLookupServiceInstance.IsNoEquipmentCached(ctx, cpf, address) {
+					// A recent lookup of the same address found no equipment - no CF data until it expires
+					logger.Info("NO EQUIPMENT FOUND RECENTLY - SKIPPING CF LOOKUP", zap.String("cpf", cpf))
+					observability.RMICFLookupNoEquipmentTotal.WithLabelValues("cached").Inc()
+					wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusNoEquipment
+				} else if address != "" && !config.AppConfig.CFLookupSyncEnabled {
+					logger.Info("SYNCHRONOUS CF LOOKUP DISABLED - QUEUEING BACKGROUND LOOKUP", zap.String("cpf", cpf))
+					queueCFLookupJob(ctx, cpf, address)
+					wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusPending
+					wallet.CFLookupPending = true
+				} else if address != "" {
 					logger.Info("CALLING TrySynchronousCFLookup", zap.String("cpf", cpf), zap.String("address", address))
-					cfData, err = services.CFLookupServiceInstance.TrySynchronousCFLookup(ctx, cpf, address)
-					if err != nil {
-						logger.Info("SYNCHRONOUS CF LOOKUP FAILED", zap.Error(err), zap.String("cpf", cpf))
-					} else {
+					// Bound the whole lookup (cache, database and MCP) so a slow dependency doesn't hold
+					// the wallet response; on failure the service queues the lookup in background
+					lookupCtx, cancel := context.WithTimeout(ctx, config.AppConfig.CFLookupSyncTimeout)
+					cfData, err = services.CFLookupServiceInstance.TrySynchronousCFLookup(lookupCtx, cpf, address)
+					cancel()
+					switch {
+					case errors.Is(err, services.ErrNoEquipmentFound):
+						logger.Info("SYNCHRONOUS CF LOOKUP FOUND NO EQUIPMENT", zap.String("cpf", cpf))
+						wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusNoEquipment
+					case services.IsCFCircuitOpen(err):
+						logger.Debug("MCP CIRCUIT OPEN - QUEUED BACKGROUND CF LOOKUP", zap.String("cpf", cpf))
+						wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusPending
+						wallet.CFLookupPending = true
+					case err != nil:
+						if errors.Is(err, context.DeadlineExceeded) {
+							observability.RMICFSyncLookupTimeoutsTotal.Inc()
+							logger.Info("SYNCHRONOUS CF LOOKUP TIMED OUT", zap.Duration("timeout", config.AppConfig.CFLookupSyncTimeout), zap.String("cpf", cpf))
+						} else {
+							logger.Info("SYNCHRONOUS CF LOOKUP FAILED", zap.Error(err), zap.String("cpf", cpf))
+						}
+						wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusPending
+						wallet.CFLookupPending = true
+					default:
 						logger.Info("SYNCHRONOUS CF LOOKUP RESULT", zap.Bool("cf_data_found", cfData != nil))
+						wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusNotFound
 					}
 				} else {
 					logger.Info("NO ADDRESS EXTRACTED - SKIPPING CF LOOKUP", zap.String("cpf", cpf))
+					wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusNoAddress
 				}
 			} else {
 				logger.Info("FOUND CACHED CF DATA", zap.String("cpf", cpf), zap.Bool("is_active", cfData.IsActive))
@@ -2960,6 +3527,7 @@ func GetCitizenWallet(c *gin.Context) {
 
 				// Replace/populate clinica_familia with CF data
 				wallet.Saude.ClinicaFamilia = cfData.ToClinicaFamilia()
+				wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusFound
 
 				// Replace/populate equipe_saude_familia with Family Health Team data if available
 				if cfData.EquipeSaudeData != nil {
@@ -2983,35 +3551,85 @@ func GetCitizenWallet(c *gin.Context) {
 		// Mark existing CF data as coming from bigquery
 		fonte := "bigquery"
 		wallet.Saude.ClinicaFamilia.Fonte = &fonte
+		wallet.ClinicaFamiliaStatus = models.ClinicaFamiliaStatusFound
 	}
 	cfDataSpan.End()
-	buildSpan.End()
+}
 
-	// Serialize response with tracing
-	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
-	c.JSON(http.StatusOK, wallet)
-	responseSpan.End()
+// parseWalletSections parses the comma-separated sections query parameter. It returns nil when no
+// section was given, meaning the whole wallet.
+func parseWalletSections(raw string) (map[string]bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
 
-	// Log total operation time
-	totalDuration := time.Since(startTime)
-	logger.Debug("GetCitizenWallet completed",
-		zap.String("cpf", cpf),
-		zap.Duration("total_duration", totalDuration),
-		zap.String("status", "success"))
+	valid := make(map[string]bool, len(models.WalletSections))
+	for _, section := range models.WalletSections {
+		valid[section] = true
+	}
+
+	sections := make(map[string]bool)
+	for _, section := range strings.Split(raw, ",") {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if section == "" {
+			continue
+		}
+		if !valid[section] {
+			return nil, fmt.Errorf("invalid wallet section %q: must be one of %s", section, strings.Join(models.WalletSections, ", "))
+		}
+		sections[section] = true
+	}
+	if len(sections) == 0 {
+		return nil, nil
+	}
+	return sections, nil
+}
+
+// filterWalletSections keeps only the requested sections of the wallet (and the CF status and
+// pending hint along with saude), plus the warnings of sections that failed to load
+func filterWalletSections(wallet *models.CitizenWallet, sections map[string]bool) map[string]interface{} {
+	response := map[string]interface{}{"cpf": wallet.CPF}
+	if sections[models.WalletSectionDocumentos] {
+		response[models.WalletSectionDocumentos] = wallet.Documentos
+	}
+	if sections[models.WalletSectionSaude] {
+		response[models.WalletSectionSaude] = wallet.Saude
+		if wallet.ClinicaFamiliaStatus != "" {
+			response["clinica_familia_status"] = wallet.ClinicaFamiliaStatus
+		}
+		if wallet.CFLookupPending {
+			response["cf_lookup_pending"] = true
+		}
+	}
+	if sections[models.WalletSectionAssistenciaSocial] {
+		response[models.WalletSectionAssistenciaSocial] = wallet.AssistenciaSocial
+	}
+	if sections[models.WalletSectionEducacao] {
+		response[models.WalletSectionEducacao] = wallet.Educacao
+	}
+	if len(wallet.Warnings) > 0 {
+		response["warnings"] = wallet.Warnings
+	}
+	return response
 }
 
 // GetMaintenanceRequests godoc
 // @Summary Obter chamados do 1746 do cidadão
-// @Description Recupera os chamados do 1746 de um cidadão por CPF com paginação. Cada documento representa um chamado individual.
+// @Description Recupera os chamados do 1746 de um cidadão por CPF com paginação. Cada documento representa um chamado individual. Suporta paginação por página (page/per_page) ou por cursor (cursor/per_page); o modo cursor é o preferido para históricos longos e obrigatório além de MAINTENANCE_REQUEST_MAX_PAGE, quando configurado. Os filtros status, from, to e bairro são aplicados no MongoDB e combinados com qualquer modo de paginação; os totais da paginação consideram os filtros.
 // @Tags citizen
 // @Accept json
 // @Produce json
 // @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
 // @Param page query int false "Número da página (padrão: 1)" minimum(1)
 // @Param per_page query int false "Itens por página (padrão: 10, máximo: 100)" minimum(1) maximum(100)
+// @Param cursor query string false "Ativa o modo cursor: vazio para a primeira página ou o pagination.next_cursor da resposta anterior"
+// @Param status query string false "Status dos chamados, separados por vírgula (valor exato, ex: Aberto,Em andamento)"
+// @Param from query string false "Data de início mínima, inclusiva (YYYY-MM-DD)"
+// @Param to query string false "Data de início máxima, inclusiva (YYYY-MM-DD)"
+// @Param bairro query string false "Bairro contido no endereço do chamado (sem diferenciar maiúsculas)"
 // @Security BearerAuth
 // @Success 200 {object} models.PaginatedMaintenanceRequests "Lista paginada de chamados do 1746 obtida com sucesso"
-// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou parâmetros de paginação inválidos"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido, parâmetros de paginação inválidos, cursor inválido, página além do limite ou filtros inválidos"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
@@ -3045,7 +3663,7 @@ func GetMaintenanceRequests(c *gin.Context) {
 				"page_str": pageStr,
 			})
 			paginationSpan.End()
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid page parameter"})
+			c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePaginationInvalid, Message: "invalid page parameter"})
 			return
 		}
 	}
@@ -3059,21 +3677,74 @@ func GetMaintenanceRequests(c *gin.Context) {
 				"per_page_str": perPageStr,
 			})
 			paginationSpan.End()
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid per_page parameter (must be between 1 and 100)"})
+			c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePaginationInvalid, Message: "invalid per_page parameter (must be between 1 and 100)"})
 			return
 		}
 	}
 
+	// Cursor mode is selected by the cursor parameter, sent empty for the first page
+	cursorParam, cursorMode := c.GetQuery("cursor")
+	var after *maintenanceRequestCursor
+	if cursorMode {
+		if c.Query("page") != "" {
+			utils.RecordErrorInSpan(paginationSpan, fmt.Errorf("page and cursor parameters are mutually exclusive"), nil)
+			paginationSpan.End()
+			c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePaginationInvalid, Message: "page and cursor parameters are mutually exclusive"})
+			return
+		}
+		if cursorParam != "" {
+			decoded, err := decodeMaintenanceRequestCursor(cursorParam)
+			if err != nil {
+				utils.RecordErrorInSpan(paginationSpan, err, map[string]interface{}{
+					"cursor": cursorParam,
+				})
+				paginationSpan.End()
+				c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePaginationInvalid, Message: "invalid cursor parameter"})
+				return
+			}
+			after = decoded
+		}
+	} else if maxPage := config.AppConfig.MaintenanceRequestMaxPage; maxPage > 0 && page > maxPage {
+		// Deep skips degrade MongoDB; past the configured depth only cursor mode is served
+		utils.RecordErrorInSpan(paginationSpan, fmt.Errorf("page exceeds maximum page depth"), map[string]interface{}{
+			"page":     page,
+			"max_page": maxPage,
+		})
+		paginationSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePaginationInvalid, Message: fmt.Sprintf("page exceeds the maximum page depth (%d), use cursor pagination", maxPage)})
+		return
+	}
+
 	// Calculate skip value
-	skip := (page - 1) * perPage
+	skip := 0
+	if !cursorMode {
+		skip = (page - 1) * perPage
+	}
+	utils.AddSpanAttribute(paginationSpan, "cursor_mode", cursorMode)
 	utils.AddSpanAttribute(paginationSpan, "page", page)
 	utils.AddSpanAttribute(paginationSpan, "per_page", perPage)
 	utils.AddSpanAttribute(paginationSpan, "skip", skip)
 	paginationSpan.End()
 
-	// Try to get from cache first (include pagination in cache key) with tracing
-	ctx, cacheSpan := utils.TraceCacheGet(ctx, fmt.Sprintf("maintenance_requests:%s:page_%d_per_%d", cpf, page, perPage))
+	// Parse filters with tracing
+	ctx, filtersSpan := utils.TraceInputParsing(ctx, "maintenance_request_filters")
+	filters, err := parseMaintenanceRequestFilters(c)
+	if err != nil {
+		utils.RecordErrorInSpan(filtersSpan, err, nil)
+		filtersSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeFilterInvalid, Message: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(filtersSpan, "filtered", !filters.empty())
+	filtersSpan.End()
+
+	// Try to get from cache first (include pagination and filters in cache key) with tracing
 	cacheKey := fmt.Sprintf("maintenance_requests:%s:page_%d_per_%d", cpf, page, perPage)
+	if cursorMode {
+		cacheKey = fmt.Sprintf("maintenance_requests:%s:cursor_%s_per_%d", cpf, cursorParam, perPage)
+	}
+	cacheKey += filters.cacheKeySuffix()
+	ctx, cacheSpan := utils.TraceCacheGet(ctx, cacheKey)
 	cachedData, err := config.Redis.Get(ctx, cacheKey).Result()
 	if err == nil {
 		utils.AddSpanAttribute(cacheSpan, "cache.hit", true)
@@ -3106,7 +3777,7 @@ func GetMaintenanceRequests(c *gin.Context) {
 
 	// Get total count with tracing
 	ctx, countSpan := utils.TraceDatabaseCount(ctx, config.AppConfig.MaintenanceRequestCollection, "cpf")
-	total, err := config.MongoDB.Collection(config.AppConfig.MaintenanceRequestCollection).CountDocuments(ctx, bson.M{"cpf": cpf})
+	total, err := config.MongoDB.Collection(config.AppConfig.MaintenanceRequestCollection).CountDocuments(ctx, filters.apply(maintenanceRequestFilter(cpf, nil)))
 	if err != nil {
 		utils.RecordErrorInSpan(countSpan, err, map[string]interface{}{
 			"db.collection": config.AppConfig.MaintenanceRequestCollection,
@@ -3115,7 +3786,7 @@ func GetMaintenanceRequests(c *gin.Context) {
 		countSpan.End()
 		observability.DatabaseOperations.WithLabelValues("count", "error").Inc()
 		logger.Error("failed to count maintenance requests", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	utils.AddSpanAttribute(countSpan, "total_count", total)
@@ -3124,11 +3795,15 @@ func GetMaintenanceRequests(c *gin.Context) {
 	// Get maintenance request documents with pagination with tracing
 	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.MaintenanceRequestCollection, "cpf_paginated")
 	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(perPage)).
-		SetSort(bson.D{{Key: "data_inicio", Value: -1}}) // Sort by data_inicio descending (newest first)
+		SetSort(bson.D{{Key: "data_inicio", Value: -1}, {Key: "_id", Value: -1}}) // Newest first, _id breaks ties so pages are stable
+	if cursorMode {
+		// Fetch one extra document to know whether there is a next page
+		opts.SetLimit(int64(perPage + 1))
+	} else {
+		opts.SetSkip(int64(skip)).SetLimit(int64(perPage))
+	}
 
-	cursor, err := config.MongoDB.Collection(config.AppConfig.MaintenanceRequestCollection).Find(ctx, bson.M{"cpf": cpf}, opts)
+	cursor, err := config.MongoDB.Collection(config.AppConfig.MaintenanceRequestCollection).Find(ctx, filters.apply(maintenanceRequestFilter(cpf, after)), opts)
 	if err != nil {
 		utils.RecordErrorInSpan(findSpan, err, map[string]interface{}{
 			"db.collection": config.AppConfig.MaintenanceRequestCollection,
@@ -3139,7 +3814,7 @@ func GetMaintenanceRequests(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to get maintenance requests", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
 	defer cursor.Close(ctx)
@@ -3152,9 +3827,14 @@ func GetMaintenanceRequests(c *gin.Context) {
 		findSpan.End()
 		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
 		logger.Error("failed to decode maintenance request documents", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "internal server error"})
 		return
 	}
+	var nextCursor string
+	if cursorMode && len(docs) > perPage {
+		docs = docs[:perPage]
+		nextCursor = encodeMaintenanceRequestCursor(docs[len(docs)-1])
+	}
 	utils.AddSpanAttribute(findSpan, "documents_found", len(docs))
 	utils.AddSpanAttribute(findSpan, "skip", skip)
 	utils.AddSpanAttribute(findSpan, "limit", perPage)
@@ -3186,16 +3866,19 @@ func GetMaintenanceRequests(c *gin.Context) {
 	response := models.PaginatedMaintenanceRequests{
 		Data: requests,
 	}
-	response.Pagination.Page = page
+	if !cursorMode {
+		response.Pagination.Page = page
+	}
 	response.Pagination.PerPage = perPage
 	response.Pagination.Total = int(total)
 	response.Pagination.TotalPages = totalPages
+	response.Pagination.NextCursor = nextCursor
 	buildSpan.End()
 
 	// Cache the result with tracing
-	_, cacheSetSpan := utils.TraceCacheSet(ctx, cacheKey, config.AppConfig.RedisTTL)
+	_, cacheSetSpan := utils.TraceCacheSet(ctx, cacheKey, config.CacheTTLFor(config.CacheNamespaceMaintenanceRequests))
 	if jsonData, err := json.Marshal(response); err == nil {
-		config.Redis.Set(ctx, cacheKey, jsonData, config.AppConfig.RedisTTL)
+		config.Redis.Set(ctx, cacheKey, jsonData, config.CacheTTLFor(config.CacheNamespaceMaintenanceRequests))
 		utils.AddSpanAttribute(cacheSetSpan, "cache.set_success", true)
 	} else {
 		utils.RecordErrorInSpan(cacheSetSpan, err, map[string]interface{}{
@@ -3241,10 +3924,34 @@ func GetMaintenanceRequests(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/phone/validate [post]
 
+// ErrorResponse is the error body returned by the API. Code is one of the ErrCode* constants
+// and Message is human-readable; Details optionally carries structured context.
 type ErrorResponse struct {
+	Code    string      `json:"code,omitempty" example:"CPF_INVALID"`
+	Message string      `json:"message,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+	// Deprecated: use Message. Mirrors Message while clients migrate to code/message.
 	Error string `json:"error"`
 }
 
+// ValidationErrorResponse is returned when a request body fails binding or validation
+type ValidationErrorResponse struct {
+	Code    string                  `json:"code" example:"VALIDATION_FAILED"`
+	Message string                  `json:"message"`
+	Error   string                  `json:"error"` // Deprecated: use Message
+	Errors  []utils.ValidationError `json:"errors"`
+}
+
+// NewValidationErrorResponse builds a structured response from a binding/validation error
+func NewValidationErrorResponse(err error) ValidationErrorResponse {
+	return ValidationErrorResponse{
+		Code:    ErrCodeValidationFailed,
+		Message: "Invalid request body",
+		Error:   "Invalid request body",
+		Errors:  utils.ParseValidationError(err),
+	}
+}
+
 type ServiceHealth struct {
 	Status    bool      `json:"status"`
 	Message   string    `json:"message"`
@@ -3302,6 +4009,53 @@ func getCurrentAddressData(ctx context.Context, cpf string) (*models.Endereco, e
 	return selfDeclared.Endereco, nil
 }
 
+// exhibitionNameErrorCodes maps the exhibition name rules to the error code returned for them
+var exhibitionNameErrorCodes = map[string]string{
+	utils.ExhibitionNameRuleTooShort:          ErrCodeExhibitionNameShort,
+	utils.ExhibitionNameRuleTooLong:           ErrCodeExhibitionNameLong,
+	utils.ExhibitionNameRuleInvalidCharacters: ErrCodeExhibitionNameChars,
+	utils.ExhibitionNameRuleBlockedTerm:       ErrCodeExhibitionNameBlocked,
+}
+
+// getCurrentNomeExibicao gets only the exhibition name from self_declared data, empty when unset
+func getCurrentNomeExibicao(ctx context.Context, cpf string) (string, error) {
+	// Try to get from cache first using DataManager
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+
+	var nomeExibicaoData struct {
+		CPF          string `json:"cpf"`
+		NomeExibicao string `json:"nome_exibicao"`
+		UpdatedAt    string `json:"updated_at"`
+	}
+
+	err := dataManager.Read(ctx, cpf, config.AppConfig.SelfDeclaredCollection, "self_declared_nome_exibicao", &nomeExibicaoData)
+	if err == nil && nomeExibicaoData.NomeExibicao != "" {
+		return nomeExibicaoData.NomeExibicao, nil
+	}
+
+	// Fallback to MongoDB with field projection (only get nome_exibicao field)
+	var selfDeclared struct {
+		NomeExibicao *string `bson:"nome_exibicao"`
+	}
+	err = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).FindOne(
+		ctx,
+		bson.M{"cpf": cpf},
+		options.FindOne().SetProjection(bson.M{"nome_exibicao": 1}),
+	).Decode(&selfDeclared)
+
+	if err == mongo.ErrNoDocuments {
+		return "", nil // No data found, this is okay
+	}
+	if err != nil {
+		return "", err
+	}
+	if selfDeclared.NomeExibicao == nil {
+		return "", nil
+	}
+
+	return *selfDeclared.NomeExibicao, nil
+}
+
 // PhoneDataWithTimestamp holds phone data with its update timestamp
 type PhoneDataWithTimestamp struct {
 	Telefone  *models.Telefone
@@ -3479,3 +4233,52 @@ func buildAddressString(endereco *models.EnderecoPrincipal) string {
 
 	return strings.Join(parts, ", ")
 }
+
+// buildDerivedFields computes server-side derived values (formatted address, age, masked contacts)
+func buildDerivedFields(citizen *models.Citizen, now time.Time) *models.CitizenDerivedFields {
+	derived := &models.CitizenDerivedFields{}
+
+	if citizen.Endereco != nil && citizen.Endereco.Principal != nil {
+		if address := buildAddressString(citizen.Endereco.Principal); address != "" {
+			derived.EnderecoFormatado = &address
+		}
+	}
+
+	if citizen.Nascimento != nil && citizen.Nascimento.Data != nil {
+		if age := calculateAge(*citizen.Nascimento.Data, now); age >= 0 {
+			derived.Idade = &age
+		}
+	}
+
+	if citizen.Email != nil && citizen.Email.Principal != nil && citizen.Email.Principal.Valor != nil && *citizen.Email.Principal.Valor != "" {
+		masked := utils.MaskEmail(*citizen.Email.Principal.Valor)
+		derived.EmailMascarado = &masked
+	}
+
+	if citizen.Telefone != nil && citizen.Telefone.Principal != nil && citizen.Telefone.Principal.Valor != nil && *citizen.Telefone.Principal.Valor != "" {
+		phone := *citizen.Telefone.Principal.Valor
+		if citizen.Telefone.Principal.DDD != nil {
+			phone = *citizen.Telefone.Principal.DDD + phone
+		}
+		if citizen.Telefone.Principal.DDI != nil {
+			phone = *citizen.Telefone.Principal.DDI + phone
+		}
+		masked := utils.MaskPhone(phone)
+		derived.TelefoneMascarado = &masked
+	}
+
+	return derived
+}
+
+// calculateAge returns the age in full years at the given time, or -1 for future birthdates
+func calculateAge(birthDate, now time.Time) int {
+	if birthDate.After(now) {
+		return -1
+	}
+
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetCitizenCompleteness godoc
+// @Summary Obter completude do perfil do cidadão
+// @Description Calcula uma pontuação de 0 a 100 indicando o quanto o perfil do cidadão está completo, considerando telefone (e sua verificação), email, endereço, etnia, avatar e opt-in. Retorna, para cada campo, se ele está preenchido e o peso usado no cálculo. Os pesos são configurados em COMPLETENESS_WEIGHTS.
+// @Tags citizen
+// @Produce json
+// @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Security BearerAuth
+// @Success 200 {object} models.CitizenCompletenessResponse "Completude do perfil calculada com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/completeness [get]
+func GetCitizenCompleteness(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCitizenCompleteness")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "get_citizen_completeness"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("GetCitizenCompleteness called")
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Merged citizen data, keeping the self-declared data to check phone verification
+	ctx, getDataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
+	citizen, selfDeclared, err := getMergedCitizenDataWithSelfDeclared(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(getDataSpan, err, map[string]interface{}{
+			"operation": "getMergedCitizenDataWithSelfDeclared",
+			"cpf":       cpf,
+		})
+		getDataSpan.End()
+		logger.Error("failed to get citizen data", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+		return
+	}
+	getDataSpan.End()
+
+	// Avatar and opt-in live in the user config
+	ctx, userConfigSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.UserConfigCollection, "cpf")
+	userConfig, err := getOptionalUserConfig(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(userConfigSpan, err, map[string]interface{}{
+			"operation": "dataManager.Read",
+			"cpf":       cpf,
+			"type":      "user_config",
+		})
+		userConfigSpan.End()
+		logger.Error("failed to get user config via DataManager", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
+		return
+	}
+	utils.AddSpanAttribute(userConfigSpan, "user_config.found", userConfig != nil)
+	userConfigSpan.End()
+
+	// Compute the score with tracing
+	ctx, scoreSpan := utils.TraceBusinessLogic(ctx, "compute_completeness_score")
+	response := buildCompletenessResponse(cpf, citizen, selfDeclared, userConfig)
+	utils.AddSpanAttribute(scoreSpan, "completeness.score", response.Score)
+	scoreSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetCitizenCompleteness completed",
+		zap.Int("score", response.Score),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// getOptionalUserConfig reads the user config of a citizen, returning nil when they have none yet
+func getOptionalUserConfig(ctx context.Context, cpf string) (*models.UserConfig, error) {
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+	var userConfig models.UserConfig
+	err := dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &userConfig)
+	if err == services.ErrDocumentNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userConfig, nil
+}
+
+// buildCompletenessResponse scores the completeness of the profile. citizen must already have the
+// self-declared data merged; userConfig is nil when the citizen has no user config yet.
+func buildCompletenessResponse(cpf string, citizen *models.Citizen, selfDeclared models.SelfDeclaredData, userConfig *models.UserConfig) models.CitizenCompletenessResponse {
+	fields := buildCompletenessFields(citizen, selfDeclared, userConfig)
+	return models.CitizenCompletenessResponse{
+		CPF:     cpf,
+		Score:   models.CompletenessScore(fields, config.AppConfig.CompletenessWeights),
+		Fields:  fields,
+		Weights: config.AppConfig.CompletenessWeights,
+	}
+}
+
+// buildCompletenessFields reports which profile fields are filled in. citizen must already have
+// the self-declared data merged; userConfig is nil when the citizen has no user config yet.
+func buildCompletenessFields(citizen *models.Citizen, selfDeclared models.SelfDeclaredData, userConfig *models.UserConfig) map[string]bool {
+	fields := make(map[string]bool, len(config.CompletenessFields))
+
+	fields[config.CompletenessFieldTelefone] = citizen.Telefone != nil && citizen.Telefone.Principal != nil &&
+		hasText(citizen.Telefone.Principal.Valor)
+	// Only a phone confirmed with a verification code counts as verified
+	fields[config.CompletenessFieldTelefoneVerificado] = selfDeclared.Telefone != nil && selfDeclared.Telefone.Principal != nil &&
+		selfDeclared.Telefone.Indicador != nil && *selfDeclared.Telefone.Indicador
+	fields[config.CompletenessFieldEmail] = citizen.Email != nil && citizen.Email.Principal != nil &&
+		hasText(citizen.Email.Principal.Valor)
+	fields[config.CompletenessFieldEndereco] = citizen.Endereco != nil && citizen.Endereco.Principal != nil
+	fields[config.CompletenessFieldRaca] = hasText(citizen.Raca)
+
+	if userConfig != nil {
+		fields[config.CompletenessFieldAvatar] = hasText(userConfig.AvatarID)
+		fields[config.CompletenessFieldOptIn] = userConfig.OptIn
+	} else {
+		// Citizens without a user config are opted in by default (see GetOptIn)
+		fields[config.CompletenessFieldAvatar] = false
+		fields[config.CompletenessFieldOptIn] = true
+	}
+
+	return fields
+}
+
+// hasText reports whether value is set and not blank
+func hasText(value *string) bool {
+	return value != nil && strings.TrimSpace(*value) != ""
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCompletenessFields_EmptyProfile(t *testing.T) {
+	fields := buildCompletenessFields(&models.Citizen{}, models.SelfDeclaredData{}, nil)
+
+	assert.Len(t, fields, len(config.CompletenessFields))
+	assert.False(t, fields[config.CompletenessFieldTelefone])
+	assert.False(t, fields[config.CompletenessFieldTelefoneVerificado])
+	assert.False(t, fields[config.CompletenessFieldEmail])
+	assert.False(t, fields[config.CompletenessFieldEndereco])
+	assert.False(t, fields[config.CompletenessFieldRaca])
+	assert.False(t, fields[config.CompletenessFieldAvatar])
+	// No user config means opted in by default
+	assert.True(t, fields[config.CompletenessFieldOptIn])
+}
+
+func TestBuildCompletenessFields_CompleteProfile(t *testing.T) {
+	phone := &models.Telefone{
+		Indicador: utils.BoolPtr(true),
+		Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("987654321")},
+	}
+	citizen := &models.Citizen{
+		Telefone: phone,
+		Email:    &models.Email{Principal: &models.EmailPrincipal{Valor: strPtr("cidadao@example.com")}},
+		Endereco: &models.Endereco{Principal: &models.EnderecoPrincipal{Logradouro: strPtr("Rua A")}},
+		Raca:     strPtr("parda"),
+	}
+	userConfig := &models.UserConfig{OptIn: true, AvatarID: strPtr("avatar-1")}
+
+	fields := buildCompletenessFields(citizen, models.SelfDeclaredData{Telefone: phone}, userConfig)
+
+	for _, field := range config.CompletenessFields {
+		assert.True(t, fields[field], "field %s should be complete", field)
+	}
+}
+
+func TestBuildCompletenessFields_UnverifiedPhoneAndBlankValues(t *testing.T) {
+	citizen := &models.Citizen{
+		Telefone: &models.Telefone{Principal: &models.TelefonePrincipal{Valor: strPtr("987654321")}},
+		Email:    &models.Email{Principal: &models.EmailPrincipal{Valor: strPtr("  ")}},
+		Raca:     strPtr(""),
+	}
+	selfDeclared := models.SelfDeclaredData{
+		Telefone: &models.Telefone{
+			Indicador: utils.BoolPtr(false),
+			Principal: &models.TelefonePrincipal{Valor: strPtr("912345678")},
+		},
+	}
+	userConfig := &models.UserConfig{OptIn: false, AvatarID: strPtr("")}
+
+	fields := buildCompletenessFields(citizen, selfDeclared, userConfig)
+
+	assert.True(t, fields[config.CompletenessFieldTelefone])
+	assert.False(t, fields[config.CompletenessFieldTelefoneVerificado])
+	assert.False(t, fields[config.CompletenessFieldEmail])
+	assert.False(t, fields[config.CompletenessFieldRaca])
+	assert.False(t, fields[config.CompletenessFieldAvatar])
+	assert.False(t, fields[config.CompletenessFieldOptIn])
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	// citizenEventsHeartbeatInterval keeps proxies from closing idle event streams
+	citizenEventsHeartbeatInterval = 15 * time.Second
+	// citizenEventsRetryMillis is the reconnection delay suggested to EventSource clients
+	citizenEventsRetryMillis = 3000
+)
+
+// Limits reported when an event stream connection is rejected
+const (
+	eventStreamLimitInstance = "instance"
+	eventStreamLimitCPF      = "cpf"
+)
+
+// eventStreamLimiter caps the concurrent event streams of the instance and of each CPF
+type eventStreamLimiter struct {
+	mu     sync.Mutex
+	total  int
+	perCPF map[string]int
+}
+
+func newEventStreamLimiter() *eventStreamLimiter {
+	return &eventStreamLimiter{perCPF: make(map[string]int)}
+}
+
+// acquire reserves a stream for cpf. When a cap (0 = unlimited) is reached it returns false and
+// the exceeded limit.
+func (l *eventStreamLimiter) acquire(cpf string, maxTotal, maxPerCPF int) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if maxTotal > 0 && l.total >= maxTotal {
+		return eventStreamLimitInstance, false
+	}
+	if maxPerCPF > 0 && l.perCPF[cpf] >= maxPerCPF {
+		return eventStreamLimitCPF, false
+	}
+	l.total++
+	l.perCPF[cpf]++
+	return "", true
+}
+
+// release frees a stream reserved by acquire
+func (l *eventStreamLimiter) release(cpf string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+	if l.perCPF[cpf]--; l.perCPF[cpf] <= 0 {
+		delete(l.perCPF, cpf)
+	}
+}
+
+// citizenEventStreams tracks the event streams open on this instance
+var citizenEventStreams = newEventStreamLimiter()
+
+// StreamCitizenEvents godoc
+// @Summary Stream de eventos do cidadão
+// @Description Abre um stream Server-Sent Events com atualizações em tempo real do cidadão: telefone verificado (phone_verified), dados de CF disponíveis (cf_data_ready), dados autodeclarados sincronizados (self_declared_synced) e solicitações de manutenção alteradas (maintenance_changed). Envia um heartbeat a cada 15 segundos. Ao reconectar com o header Last-Event-ID, os eventos recentes perdidos (últimos 10 minutos) são reenviados. Streams sem eventos por EVENT_STREAM_IDLE_TIMEOUT são encerrados.
+// @Tags citizen
+// @Produce text/event-stream
+// @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Param Last-Event-ID header string false "ID do último evento recebido, para retomar o stream"
+// @Security BearerAuth
+// @Success 200 {object} models.CitizenEvent "Stream de eventos (cada evento SSE carrega um CitizenEvent em JSON)"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Failure 503 {object} ErrorResponse "Barramento de eventos indisponível ou limite de streams simultâneos (por instância ou por CPF) atingido"
+// @Router /citizen/{cpf}/events [get]
+func StreamCitizenEvents(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "StreamCitizenEvents")
+	defer span.End()
+
+	logger := observability.Logger()
+	cpf := c.Param("cpf")
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "stream_citizen_events"),
+		attribute.String("service", "citizen"),
+	)
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	if services.EventBusInstance == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Event stream not available"})
+		return
+	}
+
+	// Reserve a stream slot; released when the client disconnects or the stream times out
+	if limit, ok := citizenEventStreams.acquire(cpf, config.AppConfig.EventStreamMaxConnections, config.AppConfig.EventStreamMaxConnectionsPerCPF); !ok {
+		observability.RMIEventStreamsRejectedTotal.WithLabelValues(limit).Inc()
+		utils.AddSpanAttribute(span, "events.rejected_limit", limit)
+		logger.Warn("citizen event stream rejected by concurrency cap", zap.String("cpf", cpf), zap.String("limit", limit))
+		c.Header("Retry-After", strconv.Itoa(citizenEventsRetryMillis/1000))
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Too many event streams, try again later"})
+		return
+	}
+	observability.RMIEventStreamsActive.Inc()
+	defer func() {
+		citizenEventStreams.release(cpf)
+		observability.RMIEventStreamsActive.Dec()
+	}()
+
+	// Subscribe before replaying history so nothing published in between is lost
+	subscription, err := services.EventBusInstance.Subscribe(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{
+			"operation": "subscribe_citizen_events",
+		})
+		logger.Error("failed to subscribe to citizen events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to open event stream"})
+		return
+	}
+	defer subscription.Close()
+
+	// Long-lived response: lift the server write timeout for this connection
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warn("failed to clear write deadline for event stream", zap.Error(err))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", citizenEventsRetryMillis)
+
+	var lastSentID int64
+	send := func(event *models.CitizenEvent) bool {
+		id, idErr := strconv.ParseInt(event.ID, 10, 64)
+		if idErr == nil && id <= lastSentID {
+			return true // already delivered through the replay
+		}
+		if err := writeSSEEvent(c.Writer, event); err != nil {
+			return false
+		}
+		if idErr == nil {
+			lastSentID = id
+		}
+		return true
+	}
+
+	// Replay events missed while the client was reconnecting
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		events, err := services.EventBusInstance.EventsSince(ctx, cpf, lastEventID)
+		if err != nil {
+			logger.Warn("failed to replay citizen events", zap.String("last_event_id", lastEventID), zap.Error(err))
+		}
+		for i := range events {
+			if !send(&events[i]) {
+				return
+			}
+		}
+		utils.AddSpanAttribute(span, "events.replayed", len(events))
+	}
+	c.Writer.Flush()
+
+	logger.Debug("citizen event stream opened", zap.String("cpf", cpf))
+
+	heartbeat := time.NewTicker(citizenEventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Close streams that go idle; heartbeats don't count as activity
+	var idle <-chan time.Time
+	idleTimeout := config.AppConfig.EventStreamIdleTimeout
+	var idleTimer *time.Timer
+	if idleTimeout > 0 {
+		idleTimer = time.NewTimer(idleTimeout)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
+
+	events := subscription.Events()
+	eventsSent := 0
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug("citizen event stream closed",
+				zap.String("cpf", cpf),
+				zap.Int("events_sent", eventsSent),
+				zap.Duration("total_duration", time.Since(startTime)))
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !send(&event) {
+				return
+			}
+			c.Writer.Flush()
+			eventsSent++
+			if idleTimer != nil {
+				idleTimer.Reset(idleTimeout)
+			}
+		case <-idle:
+			logger.Debug("citizen event stream closed after idle timeout",
+				zap.String("cpf", cpf),
+				zap.Int("events_sent", eventsSent),
+				zap.Duration("total_duration", time.Since(startTime)))
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSEEvent writes an event in Server-Sent Events format
+func writeSSEEvent(w io.Writer, event *models.CitizenEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
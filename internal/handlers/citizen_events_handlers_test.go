@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSSEEvent(t *testing.T) {
+	event := &models.CitizenEvent{
+		ID:        "1700000000000000000",
+		Type:      models.CitizenEventPhoneVerified,
+		Data:      map[string]interface{}{"phone_number": "5521987654321"},
+		Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeSSEEvent(&buf, event))
+
+	output := buf.String()
+	assert.True(t, strings.HasSuffix(output, "\n\n"), "event must be terminated by a blank line")
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "id: 1700000000000000000", lines[0])
+	assert.Equal(t, "event: phone_verified", lines[1])
+	require.True(t, strings.HasPrefix(lines[2], "data: "))
+
+	var decoded models.CitizenEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, event.Type, decoded.Type)
+	assert.Equal(t, "5521987654321", decoded.Data["phone_number"])
+}
+
+func TestEventStreamLimiter(t *testing.T) {
+	limiter := newEventStreamLimiter()
+
+	// Per-CPF cap
+	_, ok := limiter.acquire("11111111111", 3, 2)
+	require.True(t, ok)
+	_, ok = limiter.acquire("11111111111", 3, 2)
+	require.True(t, ok)
+	limit, ok := limiter.acquire("11111111111", 3, 2)
+	assert.False(t, ok)
+	assert.Equal(t, eventStreamLimitCPF, limit)
+
+	// Instance cap
+	_, ok = limiter.acquire("22222222222", 3, 2)
+	require.True(t, ok)
+	limit, ok = limiter.acquire("33333333333", 3, 2)
+	assert.False(t, ok)
+	assert.Equal(t, eventStreamLimitInstance, limit)
+
+	// Releasing frees the slot
+	limiter.release("11111111111")
+	_, ok = limiter.acquire("33333333333", 3, 2)
+	assert.True(t, ok)
+
+	limiter.release("22222222222")
+	assert.NotContains(t, limiter.perCPF, "22222222222")
+
+	// Zero disables the caps
+	_, ok = limiter.acquire("11111111111", 0, 0)
+	assert.True(t, ok)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/middleware"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetCitizenByExternalID godoc
+// @Summary Obter dados do cidadão por ID externo
+// @Description Recupera os dados do cidadão pelo ID que ele possui em outro sistema municipal (campo external_ids), para integrações que não usam o CPF. Apenas o sistema configurado em CITIZEN_EXTERNAL_ID_SYSTEM pode ser consultado. A resposta é a mesma de GET /citizen/{cpf}. O mapeamento ID externo → CPF é cacheado por EXTERNAL_ID_CACHE_TTL. Retorna 409 quando o ID pertence a mais de um cidadão.
+// @Tags citizen
+// @Produce json
+// @Param system path string true "Sistema de origem do ID (ex: id_municipal)"
+// @Param id path string true "ID do cidadão no sistema de origem"
+// @Security BearerAuth
+// @Success 200 {object} models.CitizenResponse "Dados do cidadão obtidos com sucesso"
+// @Failure 400 {object} ErrorResponse "Sistema de ID externo não suportado"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 404 {object} ErrorResponse "Nenhum cidadão com o ID externo"
+// @Failure 409 {object} ErrorResponse "ID externo pertence a mais de um cidadão"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/by-external-id/{system}/{id} [get]
+func GetCitizenByExternalID(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCitizenByExternalID")
+	defer span.End()
+
+	system := c.Param("system")
+	externalID := strings.TrimSpace(c.Param("id"))
+	logger := observability.Logger().With(zap.String("external_id_system", system))
+
+	span.SetAttributes(
+		attribute.String("external_id.system", system),
+		attribute.String("operation", "get_citizen_by_external_id"),
+		attribute.String("service", "citizen"),
+	)
+
+	service := services.NewCitizenExternalIDService(logger)
+
+	// Resolve and load the citizen; a cached mapping the citizen no longer matches is dropped
+	// and resolved again from the database
+	var citizen *models.Citizen
+	for attempt := 1; ; attempt++ {
+		ctx, resolveSpan := utils.TraceBusinessLogic(ctx, "resolve_external_id")
+		cpf, err := service.ResolveCPF(ctx, system, externalID)
+		if err != nil {
+			utils.RecordErrorInSpan(resolveSpan, err, map[string]interface{}{
+				"external_id.system": system,
+			})
+			resolveSpan.End()
+			switch {
+			case errors.Is(err, services.ErrExternalIDSystemUnsupported):
+				c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeExternalIDSystemUnsupported, Message: err.Error()})
+			case errors.Is(err, services.ErrExternalIDNotFound):
+				c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeCitizenNotFound, Message: "Citizen not found"})
+			case errors.Is(err, services.ErrExternalIDAmbiguous):
+				c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodeExternalIDAmbiguous, Message: err.Error()})
+			default:
+				logger.Error("failed to resolve external ID", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+			}
+			return
+		}
+		resolveSpan.End()
+
+		ctx, getDataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
+		merged, err := getMergedCitizenData(ctx, cpf)
+		if err != nil && err != mongo.ErrNoDocuments {
+			utils.RecordErrorInSpan(getDataSpan, err, map[string]interface{}{
+				"operation": "getMergedCitizenData",
+			})
+			getDataSpan.End()
+			logger.Error("failed to get citizen data", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+			return
+		}
+		getDataSpan.End()
+
+		if err == nil && merged.ExternalIDs[system] == externalID {
+			citizen = merged
+			cacheMergedCitizen(ctx, cpf, citizen)
+			break
+		}
+
+		service.Invalidate(ctx, system, externalID)
+		if attempt == 2 {
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeCitizenNotFound, Message: "Citizen not found"})
+			return
+		}
+		logger.Debug("cached external ID mapping outdated, resolving again")
+	}
+
+	// Convert to response model (excluding wallet fields) with tracing
+	_, convertSpan := utils.TraceBusinessLogic(ctx, "convert_to_citizen_response")
+	var citizenResponse *models.CitizenResponse
+	if middleware.ShouldMaskResponse(c) {
+		citizenResponse = citizen.ToCitizenResponseMasked(utils.PIIMasker{})
+	} else {
+		citizenResponse = citizen.ToCitizenResponse()
+	}
+	convertSpan.End()
+
+	c.JSON(http.StatusOK, citizenResponse)
+
+	logger.Debug("GetCitizenByExternalID completed",
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
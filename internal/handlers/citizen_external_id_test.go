@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetCitizenByExternalID_UnsupportedSystem(t *testing.T) {
+	original := config.AppConfig.CitizenExternalIDSystem
+	config.AppConfig.CitizenExternalIDSystem = "id_municipal"
+	defer func() { config.AppConfig.CitizenExternalIDSystem = original }()
+
+	r := gin.New()
+	r.GET("/v1/citizen/by-external-id/:system/:id", GetCitizenByExternalID)
+
+	req, _ := http.NewRequest("GET", "/v1/citizen/by-external-id/id_saude/123", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeExternalIDSystemUnsupported)
+}
+
+func TestGetCitizenByExternalID(t *testing.T) {
+	if config.MongoDB == nil || config.Redis == nil {
+		t.Skip("Skipping external ID lookup tests: MongoDB or Redis not initialized")
+	}
+
+	original := config.AppConfig.CitizenExternalIDSystem
+	config.AppConfig.CitizenExternalIDSystem = "id_municipal"
+	defer func() { config.AppConfig.CitizenExternalIDSystem = original }()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.CitizenCollection)
+	cpfs := []string{"90000000001", "90000000002", "90000000003"}
+	cleanup := func() {
+		collection.DeleteMany(ctx, bson.M{"cpf": bson.M{"$in": cpfs}})
+		for _, cpf := range cpfs {
+			_ = utils.InvalidateCitizenCache(ctx, cpf)
+		}
+		config.Redis.Del(ctx, "external_id:id_municipal:M-1", "external_id:id_municipal:M-2")
+	}
+	cleanup()
+	defer cleanup()
+
+	_, err := collection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": cpfs[0], "cpf": cpfs[0], "nome": "Maria", "external_ids": bson.M{"id_municipal": "M-1"}},
+		bson.M{"_id": cpfs[1], "cpf": cpfs[1], "nome": "Joana", "external_ids": bson.M{"id_municipal": "M-2"}},
+		bson.M{"_id": cpfs[2], "cpf": cpfs[2], "nome": "Ana", "external_ids": bson.M{"id_municipal": "M-2"}},
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.GET("/v1/citizen/by-external-id/:system/:id", GetCitizenByExternalID)
+	get := func(id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/v1/citizen/by-external-id/id_municipal/"+id, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Unique IDs resolve to the citizen and the mapping is cached
+	w := get("M-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.CitizenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, cpfs[0], response.CPF)
+	assert.Equal(t, "M-1", response.ExternalIDs["id_municipal"])
+	cached, err := config.Redis.Get(ctx, "external_id:id_municipal:M-1").Result()
+	require.NoError(t, err)
+	assert.Equal(t, cpfs[0], cached)
+
+	// IDs shared by more than one citizen are a conflict
+	w = get("M-2")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeExternalIDAmbiguous)
+
+	// Unknown IDs are not found
+	w = get("M-3")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// A cached mapping the citizen no longer matches is resolved again
+	config.Redis.Set(ctx, "external_id:id_municipal:M-1", cpfs[1], config.AppConfig.ExternalIDCacheTTL)
+	w = get("M-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, cpfs[0], response.CPF)
+}
@@ -136,6 +136,98 @@ func strPtr(s string) *string {
 	return &s
 }
 
+// TestBuildDerivedFields tests the derived fields added with include_derived=true
+func TestBuildDerivedFields(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	birth := time.Date(1990, 6, 16, 0, 0, 0, 0, time.UTC)
+
+	citizen := &models.Citizen{
+		Nascimento: &models.Nascimento{Data: &birth},
+		Endereco: &models.Endereco{Principal: &models.EnderecoPrincipal{
+			Logradouro: strPtr("Rua A"),
+			Numero:     strPtr("10"),
+		}},
+		Email: &models.Email{Principal: &models.EmailPrincipal{Valor: strPtr("joao@example.com")}},
+		Telefone: &models.Telefone{Principal: &models.TelefonePrincipal{
+			DDI:   strPtr("55"),
+			DDD:   strPtr("21"),
+			Valor: strPtr("987654321"),
+		}},
+	}
+
+	derived := buildDerivedFields(citizen, now)
+	require.NotNil(t, derived)
+	require.NotNil(t, derived.EnderecoFormatado)
+	assert.Equal(t, "Rua A, 10, Rio de Janeiro, RJ", *derived.EnderecoFormatado)
+	require.NotNil(t, derived.Idade)
+	assert.Equal(t, 33, *derived.Idade, "birthday not reached yet this year")
+	require.NotNil(t, derived.EmailMascarado)
+	assert.Equal(t, "j***@example.com", *derived.EmailMascarado)
+	require.NotNil(t, derived.TelefoneMascarado)
+	assert.Equal(t, "*********4321", *derived.TelefoneMascarado)
+
+	empty := buildDerivedFields(&models.Citizen{}, now)
+	assert.Nil(t, empty.EnderecoFormatado)
+	assert.Nil(t, empty.Idade)
+	assert.Nil(t, empty.EmailMascarado)
+	assert.Nil(t, empty.TelefoneMascarado)
+}
+
+// TestCalculateAge tests the calculateAge helper function
+func TestCalculateAge(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 34, calculateAge(time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), now), "birthday today")
+	assert.Equal(t, 33, calculateAge(time.Date(1990, 12, 1, 0, 0, 0, 0, time.UTC), now), "birthday later this year")
+	assert.Equal(t, 0, calculateAge(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), now), "born this year")
+	assert.Equal(t, -1, calculateAge(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), now), "future birthdate")
+}
+
+// TestBuildSelfDeclaredStatus tests the per-field staleness reported with the citizen
+func TestBuildSelfDeclaredStatus(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-365 * 24 * time.Hour)
+	config.AppConfig.SelfDeclaredOutdatedThreshold = 180 * 24 * time.Hour
+
+	selfDeclared := models.SelfDeclaredData{
+		Endereco: &models.Endereco{Principal: &models.EnderecoPrincipal{Logradouro: strPtr("Rua A"), UpdatedAt: &recent}},
+		Email:    &models.Email{Principal: &models.EmailPrincipal{Valor: strPtr("joao@example.com")}},
+		Telefone: &models.Telefone{Indicador: boolPtr(false), Principal: &models.TelefonePrincipal{Valor: strPtr("987654321"), UpdatedAt: &recent}},
+		Raca:     strPtr("parda"),
+		Genero:   strPtr("feminino"),
+	}
+	updatedAt := map[string]*time.Time{"raca": &old}
+
+	status := buildSelfDeclaredStatus(selfDeclared, updatedAt, now)
+
+	require.Contains(t, status, "endereco")
+	assert.False(t, status["endereco"].IsStale)
+	assert.Equal(t, &recent, status["endereco"].LastUpdated)
+	require.Contains(t, status, "email")
+	assert.True(t, status["email"].IsStale, "legacy email without timestamp is stale")
+	assert.Nil(t, status["email"].LastUpdated)
+	assert.NotContains(t, status, "telefone", "unverified phone is not merged")
+	require.Contains(t, status, "raca")
+	assert.True(t, status["raca"].IsStale)
+	require.Contains(t, status, "genero")
+	assert.True(t, status["genero"].IsStale, "field without timestamp is stale")
+	assert.NotContains(t, status, "escolaridade")
+
+	assert.Nil(t, buildSelfDeclaredStatus(models.SelfDeclaredData{}, nil, now))
+}
+
+// TestParseSelfDeclaredTimestamp tests parsing the updated_at of buffered self-declared data
+func TestParseSelfDeclaredTimestamp(t *testing.T) {
+	parsed := parseSelfDeclaredTimestamp("2024-06-15T12:00:00.123456789-03:00")
+	require.NotNil(t, parsed)
+	assert.Equal(t, 2024, parsed.Year())
+
+	assert.Nil(t, parseSelfDeclaredTimestamp(""))
+	assert.Nil(t, parseSelfDeclaredTimestamp("not a date"))
+	assert.Nil(t, parseSelfDeclaredTimestamp("0001-01-01T00:00:00Z"))
+}
+
 // TestQueueCFLookupJob tests the queueCFLookupJob function
 func TestQueueCFLookupJob(t *testing.T) {
 	ctx := context.Background()
@@ -197,3 +289,55 @@ func TestQueueCFLookupJob(t *testing.T) {
 		assert.Equal(t, int64(3), length, "Queue should have 3 jobs")
 	})
 }
+
+// TestParseWalletSections tests the wallet sections query parsing
+func TestParseWalletSections(t *testing.T) {
+	sections, err := parseWalletSections("")
+	assert.NoError(t, err)
+	assert.Nil(t, sections, "no sections means the whole wallet")
+
+	sections, err = parseWalletSections(" , ")
+	assert.NoError(t, err)
+	assert.Nil(t, sections)
+
+	sections, err = parseWalletSections("saude, EDUCACAO")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{models.WalletSectionSaude: true, models.WalletSectionEducacao: true}, sections)
+
+	_, err = parseWalletSections("saude,vacinas")
+	assert.Error(t, err)
+}
+
+// TestFilterWalletSections tests that only requested wallet sections are returned
+func TestFilterWalletSections(t *testing.T) {
+	wallet := &models.CitizenWallet{
+		CPF:                  "12345678901",
+		Documentos:           &models.Documentos{},
+		Saude:                &models.Saude{},
+		Educacao:             &models.Educacao{},
+		ClinicaFamiliaStatus: models.ClinicaFamiliaStatusFound,
+	}
+
+	response := filterWalletSections(wallet, map[string]bool{models.WalletSectionEducacao: true})
+	assert.Equal(t, map[string]interface{}{"cpf": "12345678901", "educacao": wallet.Educacao}, response)
+
+	response = filterWalletSections(wallet, map[string]bool{models.WalletSectionSaude: true, models.WalletSectionAssistenciaSocial: true})
+	assert.Equal(t, wallet.Saude, response["saude"])
+	assert.Equal(t, models.ClinicaFamiliaStatusFound, response["clinica_familia_status"])
+	assert.Contains(t, response, "assistencia_social", "requested sections are present even when empty")
+	assert.NotContains(t, response, "documentos")
+}
+
+func TestFilterWalletSections_CFLookupPending(t *testing.T) {
+	wallet := &models.CitizenWallet{
+		CPF:                  "12345678901",
+		ClinicaFamiliaStatus: models.ClinicaFamiliaStatusPending,
+		CFLookupPending:      true,
+	}
+
+	response := filterWalletSections(wallet, map[string]bool{models.WalletSectionSaude: true})
+	assert.Equal(t, true, response["cf_lookup_pending"])
+
+	response = filterWalletSections(wallet, map[string]bool{models.WalletSectionDocumentos: true})
+	assert.NotContains(t, response, "cf_lookup_pending", "the hint goes along with saude")
+}
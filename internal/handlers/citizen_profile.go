@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/middleware"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetCitizenProfile godoc
+// @Summary Obter perfil consolidado do cidadão
+// @Description Retorna em uma única resposta os dados do cidadão (como em GET /citizen/{cpf}), a carteira (como em GET /citizen/{cpf}/wallet, com as mesmas regras de integração da clínica da família) e a completude do perfil (como em GET /citizen/{cpf}/completeness). Os dados do cidadão e os autodeclarados são lidos uma única vez e compartilhados entre as três seções.
+// @Tags citizen
+// @Produce json
+// @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Param sections query string false "Seções da carteira a retornar, separadas por vírgula (documentos, saude, assistencia_social, educacao); padrão: todas. A consulta de clínica da família só é feita quando saude é solicitada"
+// @Security BearerAuth
+// @Success 200 {object} models.CitizenProfileResponse "Perfil do cidadão obtido com sucesso"
+// @Header 200 {string} X-Data-Stale "Presente (true) quando o MongoDB está indisponível e os dados vêm da última cópia em cache (MONGODB_DEGRADED_READS_ENABLED); a completude é omitida nesse caso"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou seção desconhecida"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 404 {object} ErrorResponse "Cidadão não encontrado"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/profile [get]
+func GetCitizenProfile(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetCitizenProfile")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "get_citizen_profile"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("GetCitizenProfile called")
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Parse requested wallet sections with tracing
+	ctx, sectionsSpan := utils.TraceInputParsing(ctx, "wallet_sections")
+	sections, err := parseWalletSections(c.Query("sections"))
+	if err != nil {
+		utils.RecordErrorInSpan(sectionsSpan, err, map[string]interface{}{
+			"sections": c.Query("sections"),
+		})
+		sectionsSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeWalletSectionInvalid, Message: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(sectionsSpan, "wallet.sections", c.Query("sections"))
+	sectionsSpan.End()
+
+	// Read the citizen and self-declared data once for all sections
+	ctx, getDataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
+	citizen, selfDeclared, err := getMergedCitizenDataWithSelfDeclared(ctx, cpf)
+	servedStale := false
+	if err != nil {
+		utils.RecordErrorInSpan(getDataSpan, err, map[string]interface{}{
+			"operation": "getMergedCitizenDataWithSelfDeclared",
+			"cpf":       cpf,
+		})
+		getDataSpan.End()
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeCitizenNotFound, Message: "Citizen not found"})
+			return
+		}
+		staleCitizen, ok := readStaleCitizen(ctx, c, logger, cpf, "profile", err)
+		if !ok {
+			logger.Error("failed to get citizen data", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Internal server error"})
+			return
+		}
+		citizen, servedStale = staleCitizen, true
+	} else {
+		getDataSpan.End()
+	}
+	utils.AddSpanAttribute(span, "citizen.served_stale", servedStale)
+
+	if !servedStale {
+		observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
+		cacheMergedCitizen(ctx, cpf, citizen)
+	}
+
+	// Convert to response model before the wallet build fills in the CF data
+	ctx, convertSpan := utils.TraceBusinessLogic(ctx, "convert_to_citizen_response")
+	response := models.CitizenProfileResponse{}
+	if middleware.ShouldMaskResponse(c) {
+		response.Citizen = citizen.ToCitizenResponseMasked(utils.PIIMasker{})
+		utils.AddSpanAttribute(convertSpan, "masked", true)
+	} else {
+		response.Citizen = citizen.ToCitizenResponse()
+	}
+	convertSpan.End()
+
+	// Build the wallet with the same CF integration as GET /citizen/{cpf}/wallet
+	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_citizen_wallet")
+	wallet := buildCitizenWallet(ctx, logger, cpf, citizen, sections, servedStale)
+	if sections != nil {
+		response.Wallet = filterWalletSections(&wallet, sections)
+	} else {
+		response.Wallet = wallet
+	}
+	buildSpan.End()
+
+	// Score the completeness; the user config can't be read while MongoDB is unavailable
+	if !servedStale {
+		ctx, userConfigSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.UserConfigCollection, "cpf")
+		userConfig, err := getOptionalUserConfig(ctx, cpf)
+		if err != nil {
+			utils.RecordErrorInSpan(userConfigSpan, err, map[string]interface{}{
+				"operation": "dataManager.Read",
+				"cpf":       cpf,
+				"type":      "user_config",
+			})
+			userConfigSpan.End()
+			logger.Error("failed to get user config via DataManager", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
+			return
+		}
+		userConfigSpan.End()
+
+		completeness := buildCompletenessResponse(cpf, citizen, selfDeclared, userConfig)
+		response.Completeness = &completeness
+		utils.AddSpanAttribute(span, "completeness.score", completeness.Score)
+	}
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetCitizenProfile completed",
+		zap.Bool("served_stale", servedStale),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
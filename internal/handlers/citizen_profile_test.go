@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCitizenProfile_InvalidInput(t *testing.T) {
+	r := gin.New()
+	r.GET("/v1/citizen/:cpf/profile", GetCitizenProfile)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode string
+	}{
+		{"invalid CPF", "/v1/citizen/invalid/profile", ErrCodeCPFInvalid},
+		{"unknown wallet section", "/v1/citizen/" + cpfTest + "/profile?sections=saude,veiculos", ErrCodeWalletSectionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedCode)
+		})
+	}
+}
+
+func TestBuildCitizenWallet_CFIntegration(t *testing.T) {
+	cfService := services.CFLookupServiceInstance
+	services.CFLookupServiceInstance = nil
+	defer func() { services.CFLookupServiceInstance = cfService }()
+
+	ctx := context.Background()
+	logger := logging.GetLogger()
+
+	// Base CF data is kept and tagged with its source
+	citizen := &models.Citizen{
+		Saude: &models.Saude{ClinicaFamilia: &models.ClinicaFamilia{Indicador: utils.BoolPtr(true), Nome: strPtr("CF Teste")}},
+	}
+	wallet := buildCitizenWallet(ctx, logger, cpfTest, citizen, nil, false)
+	assert.Equal(t, cpfTest, wallet.CPF)
+	assert.Equal(t, models.ClinicaFamiliaStatusFound, wallet.ClinicaFamiliaStatus)
+	assert.Equal(t, "bigquery", *wallet.Saude.ClinicaFamilia.Fonte)
+
+	// Without base CF data and with the CF lookup disabled, the status explains why
+	wallet = buildCitizenWallet(ctx, logger, cpfTest, &models.Citizen{}, nil, false)
+	assert.Equal(t, models.ClinicaFamiliaStatusUnavailable, wallet.ClinicaFamiliaStatus)
+
+	// Stale data skips the lookup
+	wallet = buildCitizenWallet(ctx, logger, cpfTest, &models.Citizen{}, nil, true)
+	assert.Equal(t, models.ClinicaFamiliaStatusUnavailable, wallet.ClinicaFamiliaStatus)
+
+	// No CF check at all when saude isn't requested
+	wallet = buildCitizenWallet(ctx, logger, cpfTest, &models.Citizen{}, map[string]bool{models.WalletSectionDocumentos: true}, false)
+	assert.Empty(t, wallet.ClinicaFamiliaStatus)
+}
+
+func TestBuildCitizenWallet_BrokenSection(t *testing.T) {
+	cfService := services.CFLookupServiceInstance
+	services.CFLookupServiceInstance = nil
+	defer func() { services.CFLookupServiceInstance = cfService }()
+
+	// NaN can't be serialized, so the educacao section fails on its own
+	frequencia := math.NaN()
+	citizen := &models.Citizen{
+		Saude:    &models.Saude{ClinicaFamilia: &models.ClinicaFamilia{Indicador: utils.BoolPtr(true), Nome: strPtr("CF Teste")}},
+		Educacao: &models.Educacao{Aluno: &models.Aluno{Frequencia: &frequencia}},
+	}
+
+	wallet := buildCitizenWallet(context.Background(), logging.GetLogger(), cpfTest, citizen, nil, false)
+	assert.Nil(t, wallet.Educacao)
+	assert.NotNil(t, wallet.Saude, "health data is still returned")
+	assert.Equal(t, []models.WalletWarning{{Section: models.WalletSectionEducacao, Message: "section unavailable"}}, wallet.Warnings)
+	_, err := json.Marshal(wallet)
+	assert.NoError(t, err)
+}
+
+func TestPopulateWalletSection_RecoversPanic(t *testing.T) {
+	wallet := models.CitizenWallet{Saude: &models.Saude{}, ClinicaFamiliaStatus: models.ClinicaFamiliaStatusFound}
+
+	populateWalletSection(logging.GetLogger(), &wallet, models.WalletSectionSaude, func() error {
+		var cfData *models.CFLookup
+		_ = cfData.IsActive // nil dereference
+		return nil
+	})
+
+	assert.Nil(t, wallet.Saude)
+	assert.Empty(t, wallet.ClinicaFamiliaStatus)
+	assert.Len(t, wallet.Warnings, 1)
+	assert.Equal(t, models.WalletSectionSaude, wallet.Warnings[0].Section)
+}
+
+func TestBuildCompletenessResponse(t *testing.T) {
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	weights := config.AppConfig.CompletenessWeights
+	config.AppConfig.CompletenessWeights = map[string]int{config.CompletenessFieldRaca: 40, config.CompletenessFieldOptIn: 60}
+	defer func() { config.AppConfig.CompletenessWeights = weights }()
+
+	response := buildCompletenessResponse(cpfTest, &models.Citizen{Raca: strPtr("parda")}, models.SelfDeclaredData{}, &models.UserConfig{OptIn: false})
+
+	assert.Equal(t, cpfTest, response.CPF)
+	assert.Equal(t, 40, response.Score)
+	assert.True(t, response.Fields[config.CompletenessFieldRaca])
+	assert.False(t, response.Fields[config.CompletenessFieldOptIn])
+	assert.Equal(t, config.AppConfig.CompletenessWeights, response.Weights)
+}
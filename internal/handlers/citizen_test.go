@@ -6,9 +6,13 @@ import (
 	"flag"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -390,6 +394,33 @@ func TestUpdateOptIn_MissingOptInField(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected 400 for missing OptIn field")
 }
 
+func TestValidateOptOutReason(t *testing.T) {
+	optIn, optOut := true, false
+	defer func(required bool) { config.AppConfig.OptOutReasonRequired = required }(config.AppConfig.OptOutReasonRequired)
+
+	config.AppConfig.OptOutReasonRequired = false
+	assert.NoError(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optOut}))
+	assert.NoError(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optOut, Reason: models.OptOutReasonTooManyMessages, Note: "muitas mensagens"}))
+	assert.Error(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optOut, Reason: "unknown"}))
+	assert.Error(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optIn, Reason: models.OptOutReasonTooManyMessages}))
+
+	config.AppConfig.OptOutReasonRequired = true
+	assert.Error(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optOut}))
+	assert.NoError(t, validateOptOutReason(&models.UserConfigOptInResponse{OptIn: &optIn}))
+}
+
+func TestUpdateOptIn_InvalidOptOutReason(t *testing.T) {
+	r := setupRouter()
+
+	body := map[string]interface{}{"opt_in": false, "reason": "not_a_reason"}
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("PUT", "/v1/citizen/"+cpfTest+"/optin", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected 400 for unknown opt-out reason")
+}
+
 // Test helper functions and options functions
 
 func TestGetEthnicityOptions(t *testing.T) {
@@ -712,6 +743,22 @@ func TestUpdateSelfDeclaredNomeExibicao(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "display name too short",
+			cpf:  cpfTest,
+			body: map[string]interface{}{
+				"Valor": " J ",
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "display name with disallowed characters",
+			cpf:  cpfTest,
+			body: map[string]interface{}{
+				"Valor": "João <script>",
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tt := range tests {
@@ -725,3 +772,47 @@ func TestUpdateSelfDeclaredNomeExibicao(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSelfDeclaredNomeExibicao_RuleViolations(t *testing.T) {
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	blocklist := config.AppConfig.ExhibitionNameBlocklist
+	config.AppConfig.ExhibitionNameBlocklist = []string{"palavrao", "termo feio"}
+	defer func() { config.AppConfig.ExhibitionNameBlocklist = blocklist }()
+
+	r := gin.New()
+	r.PUT("/v1/citizen/:cpf/exhibition-name", UpdateSelfDeclaredNomeExibicao)
+
+	tests := []struct {
+		name         string
+		valor        string
+		expectedCode string
+		expectedRule string
+	}{
+		{"too short", "J", ErrCodeExhibitionNameShort, utils.ExhibitionNameRuleTooShort},
+		{"too long", strings.Repeat("á", 256), ErrCodeExhibitionNameLong, utils.ExhibitionNameRuleTooLong},
+		{"digits", "Maria 2", ErrCodeExhibitionNameChars, utils.ExhibitionNameRuleInvalidCharacters},
+		{"blocked term", "Maria Palavrão", ErrCodeExhibitionNameBlocked, utils.ExhibitionNameRuleBlockedTerm},
+		{"blocked phrase", "Termo-Feio da Silva", ErrCodeExhibitionNameBlocked, utils.ExhibitionNameRuleBlockedTerm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(map[string]interface{}{"valor": tt.valor})
+			req, _ := http.NewRequest("PUT", "/v1/citizen/"+cpfTest+"/exhibition-name", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			var response struct {
+				Code    string            `json:"code"`
+				Details map[string]string `json:"details"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.Equal(t, tt.expectedRule, response.Details["rule"])
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+)
+
+// Motivos de um CPF inválido em CPFValidationResponse
+const (
+	CPFInvalidReasonFormat         = "invalid_format"       // não tem 11 dígitos (pontos, hífen e espaços são ignorados)
+	CPFInvalidReasonRepeatedDigits = "repeated_digits"      // todos os dígitos iguais, como 111.111.111-11
+	CPFInvalidReasonCheckDigits    = "invalid_check_digits" // dígitos verificadores não conferem
+)
+
+// cpfSeparators são ignorados ao validar um CPF formatado como "123.456.789-09"
+var cpfSeparators = strings.NewReplacer(".", "", "-", "", " ", "")
+
+// CPFValidationRequest representa a requisição para validação de CPF
+// swagger:model
+// @description Estrutura de entrada contendo o CPF a ser validado.
+type CPFValidationRequest struct {
+	// CPF com ou sem pontuação.
+	// example: "123.456.789-09"
+	CPF string `json:"cpf" binding:"required"`
+}
+
+// CPFValidationResponse representa a resposta da validação de CPF
+// swagger:model
+// @description Resultado da validação, com o motivo quando o CPF é inválido.
+type CPFValidationResponse struct {
+	// Indica se o CPF é válido.
+	Valid bool `json:"valid"`
+	// Motivo da invalidez: invalid_format, repeated_digits ou invalid_check_digits.
+	Reason string `json:"reason,omitempty"`
+}
+
+// cpfInvalidReason returns why a CPF is invalid, or an empty string when it is valid
+func cpfInvalidReason(cpf string) string {
+	digits := cpfSeparators.Replace(strings.TrimSpace(cpf))
+	if len(digits) != 11 || strings.ContainsFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) {
+		return CPFInvalidReasonFormat
+	}
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return CPFInvalidReasonRepeatedDigits
+	}
+	if !utils.ValidateCPF(digits) {
+		return CPFInvalidReasonCheckDigits
+	}
+	return ""
+}
+
+// ValidateCPFNumber godoc
+// @Summary Valida CPF
+// @Description Valida o formato e os dígitos verificadores de um CPF, sem autenticação, para formulários validarem o CPF antes do login. Limitado a CPF_VALIDATION_RATE_LIMIT requisições por IP a cada CPF_VALIDATION_RATE_WINDOW; acima disso retorna 429 com o header Retry-After.
+// @Tags validation
+// @Accept json
+// @Produce json
+// @Param data body CPFValidationRequest true "CPF a ser validado"
+// @Success 200 {object} CPFValidationResponse "CPF validado (valid=false com o motivo quando inválido)"
+// @Failure 400 {object} ErrorResponse "Campo cpf é obrigatório"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Header 429 {string} Retry-After "Segundos até o fim da janela"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /validate/cpf [post]
+func ValidateCPFNumber(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ValidateCPFNumber")
+	defer span.End()
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "validate_cpf"),
+		attribute.String("service", "cpf_validation"),
+	)
+
+	logger := logging.GetLogger().With(zap.String("client_ip", c.ClientIP()))
+
+	// Rate limit per client IP with tracing, so the endpoint can't be used to enumerate CPFs
+	ctx, rateSpan := utils.TraceBusinessLogic(ctx, "check_cpf_validation_rate_limit")
+	retryAfter, err := services.CPFValidationRetryAfter(ctx, c.ClientIP())
+	if err != nil {
+		// Fail open: a Redis outage shouldn't break the forms
+		utils.RecordErrorInSpan(rateSpan, err, map[string]interface{}{
+			"rate_limit.operation": "count",
+		})
+		logger.Warn("failed to check CPF validation rate limit", zap.Error(err))
+	}
+	utils.AddSpanAttribute(rateSpan, "rate_limit.limited", retryAfter > 0)
+	rateSpan.End()
+	if retryAfter > 0 {
+		logger.Debug("CPF validation rate limited", zap.Duration("retry_after", retryAfter))
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Code: ErrCodeRateLimited, Message: "Too many requests, try again later"})
+		return
+	}
+
+	var req CPFValidationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "CPFValidationRequest",
+		})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeInvalidRequestBody, Message: "campo cpf é obrigatório"})
+		return
+	}
+
+	// Validate format and check digits with tracing
+	ctx, validationSpan := utils.TraceBusinessLogic(ctx, "validate_cpf")
+	reason := cpfInvalidReason(req.CPF)
+	utils.AddSpanAttribute(validationSpan, "validation.valid", reason == "")
+	utils.AddSpanAttribute(validationSpan, "validation.reason", reason)
+	validationSpan.End()
+
+	// Serialize response with tracing
+	_, serializeSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, CPFValidationResponse{Valid: reason == "", Reason: reason})
+	serializeSpan.End()
+
+	// Log total operation time (without the CPF itself)
+	logger.Debug("ValidateCPFNumber completed",
+		zap.Bool("valid", reason == ""),
+		zap.String("reason", reason),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPFInvalidReason(t *testing.T) {
+	tests := []struct {
+		cpf  string
+		want string
+	}{
+		{cpf: "03561350712", want: ""},
+		{cpf: "035.613.507-12", want: ""},
+		{cpf: " 035 613 507 12 ", want: ""},
+		{cpf: "0356135071", want: CPFInvalidReasonFormat},
+		{cpf: "035613507123", want: CPFInvalidReasonFormat},
+		{cpf: "0356135071a", want: CPFInvalidReasonFormat},
+		{cpf: "111.111.111-11", want: CPFInvalidReasonRepeatedDigits},
+		{cpf: "03561350713", want: CPFInvalidReasonCheckDigits},
+	}
+	for _, tt := range tests {
+		if got := cpfInvalidReason(tt.cpf); got != tt.want {
+			t.Errorf("cpfInvalidReason(%q) = %q, want %q", tt.cpf, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCPFNumber(t *testing.T) {
+	defer func(limit int) { config.AppConfig.CPFValidationRateLimit = limit }(config.AppConfig.CPFValidationRateLimit)
+	config.AppConfig.CPFValidationRateLimit = 0
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/validate/cpf", ValidateCPFNumber)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/validate/cpf", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"cpf": "035.613.507-12"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response CPFValidationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CPFValidationResponse{Valid: true}, response)
+
+	w = post(`{"cpf": "03561350713"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response = CPFValidationResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CPFValidationResponse{Valid: false, Reason: CPFInvalidReasonCheckDigits}, response)
+
+	w = post(`{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestValidateCPFNumber_RateLimited(t *testing.T) {
+	if config.Redis == nil {
+		t.Skip("Skipping CPF validation rate limit test: Redis not available")
+	}
+	defer func(limit int) { config.AppConfig.CPFValidationRateLimit = limit }(config.AppConfig.CPFValidationRateLimit)
+	config.AppConfig.CPFValidationRateLimit = 1
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/validate/cpf", ValidateCPFNumber)
+
+	ip := "198.51.100.7"
+	defer config.Redis.Del(context.Background(), "cpf_validation_rate:"+ip)
+	config.Redis.Del(context.Background(), "cpf_validation_rate:"+ip)
+
+	codes := make([]int, 0, 2)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("POST", "/validate/cpf", bytes.NewBufferString(`{"cpf": "03561350712"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		if w.Code == http.StatusTooManyRequests {
+			assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+}
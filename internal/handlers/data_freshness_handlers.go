@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// AdminGetDataFreshness godoc
+// @Summary Obter relatório de atualidade dos dados
+// @Description Retorna, por coleção, a distribuição da idade dos documentos (percentis 50, 90, 99 e máximo, em segundos) estimada a partir de uma amostra de DATA_FRESHNESS_SAMPLE_SIZE documentos, usando updated_at ou, na ausência dele, created_at. O relatório é recalculado a cada DATA_FRESHNESS_INTERVAL e também exportado na métrica collection_data_age_seconds. Use refresh=true para recalculá-lo na hora.
+// @Tags admin
+// @Produce json
+// @Param refresh query bool false "Recalcular o relatório em vez de usar o último gerado"
+// @Security BearerAuth
+// @Success 200 {object} models.DataFreshnessReport "Relatório obtido com sucesso"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/data-freshness [get]
+func AdminGetDataFreshness(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminGetDataFreshness")
+	defer span.End()
+
+	logger := observability.Logger()
+	refresh := c.Query("refresh") == "true"
+
+	span.SetAttributes(
+		attribute.String("operation", "get_data_freshness"),
+		attribute.String("service", "admin"),
+		attribute.Bool("refresh", refresh),
+	)
+
+	service := services.DataFreshnessServiceInstance
+	if service == nil {
+		service = services.NewDataFreshnessService(logger)
+	}
+
+	getReport := service.GetReport
+	if refresh {
+		getReport = service.Generate
+	}
+	report, err := getReport(ctx)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get data freshness report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+
+	logger.Debug("AdminGetDataFreshness completed",
+		zap.Int("collections_count", len(report.Collections)),
+		zap.Bool("refresh", refresh),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ValidateEmailVerification godoc
+// @Summary Validar verificação de email
+// @Description Valida o token de verificação enviado para o email autodeclarado. Após a validação, o email pendente passa a ser o email autodeclarado verificado do cidadão. Disponível apenas com EMAIL_VERIFICATION_ENABLED.
+// @Tags citizen
+// @Accept json
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Param data body models.EmailVerificationValidateRequest true "Token de verificação e email"
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse "Email verificado com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido ou dados de verificação incorretos"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 404 {object} ErrorResponse "Token de verificação não encontrado ou expirado, ou verificação de email desabilitada"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/email/validate [post]
+func ValidateEmailVerification(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ValidateEmailVerification")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "validate_email_verification"),
+		attribute.String("service", "email_verification"),
+	)
+
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+	logger.Debug("ValidateEmailVerification called", zap.String("cpf", cpf))
+
+	if !config.AppConfig.EmailVerificationEnabled {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    ErrCodeEmailVerificationOff,
+			Message: "Email verification is not enabled",
+		})
+		return
+	}
+
+	// Validate CPF format
+	if !utils.ValidateCPF(cpf) {
+		logger.Warn("invalid CPF format", zap.String("cpf", cpf))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    ErrCodeCPFInvalid,
+			Message: "Invalid CPF format",
+		})
+		return
+	}
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "email_verification_validate_request")
+	var req models.EmailVerificationValidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "EmailVerificationValidateRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    ErrCodeInvalidRequestBody,
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	req.Token = strings.TrimSpace(req.Token)
+	utils.AddSpanAttribute(inputSpan, "input.email", req.Email)
+	inputSpan.End()
+
+	// Find verification request with tracing
+	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.EmailVerificationCollection, "verification_lookup")
+	verification, err := services.FindEmailVerification(ctx, cpf, req.Email, req.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailVerificationNotFound) {
+			utils.AddSpanAttribute(findSpan, "verification.found", false)
+			utils.AddSpanAttribute(findSpan, "verification.reason", "invalid_or_expired_token")
+			findSpan.End()
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:    ErrCodeEmailVerificationExpired,
+				Message: "Invalid or expired verification token",
+			})
+			return
+		}
+		utils.RecordErrorInSpan(findSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.EmailVerificationCollection,
+			"db.operation":  "find_one",
+		})
+		findSpan.End()
+		logger.Error("failed to find email verification request", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Failed to validate verification token",
+		})
+		return
+	}
+	utils.AddSpanAttribute(findSpan, "verification.found", true)
+	utils.AddSpanAttribute(findSpan, "verification.expires_at", verification.ExpiresAt.String())
+	findSpan.End()
+
+	if err := promoteVerifiedEmail(ctx, c, logger, cpf, verification.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Failed to update email data",
+		})
+		return
+	}
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Email verified successfully",
+	})
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("ValidateEmailVerification completed",
+		zap.String("cpf", cpf),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// Magic link outcomes, sent to the app as the status query parameter of the redirect
+const (
+	emailVerificationLinkVerified = "verified"
+	emailVerificationLinkInvalid  = "invalid"
+	emailVerificationLinkError    = "error"
+)
+
+// VerifyEmailLink godoc
+// @Summary Verificar email por link
+// @Description Valida o link de verificação enviado para o email autodeclarado. O link só pode ser usado uma vez; após a validação, o email pendente passa a ser o email autodeclarado verificado do cidadão. Redireciona para EMAIL_VERIFICATION_REDIRECT_URL com o parâmetro status (verified, invalid ou error). Disponível apenas com EMAIL_VERIFICATION_ENABLED e EMAIL_VERIFICATION_LINK_URL.
+// @Tags citizen
+// @Produce json
+// @Param token query string true "Token do link de verificação"
+// @Success 302 "Redirecionamento para o aplicativo"
+// @Failure 404 {object} ErrorResponse "Verificação de email por link desabilitada"
+// @Router /verify/email [get]
+func VerifyEmailLink(c *gin.Context) {
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "VerifyEmailLink")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("operation", "verify_email_link"),
+		attribute.String("service", "email_verification"),
+	)
+
+	logger := observability.Logger()
+
+	if !config.AppConfig.EmailVerificationEnabled || config.AppConfig.EmailVerificationLinkURL == "" {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    ErrCodeEmailVerificationOff,
+			Message: "Email verification links are not enabled",
+		})
+		return
+	}
+
+	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.EmailVerificationCollection, "verification_link_lookup")
+	verification, err := services.ConsumeEmailVerificationLink(ctx, c.Query("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrEmailVerificationNotFound) {
+			utils.AddSpanAttribute(findSpan, "verification.found", false)
+			findSpan.End()
+			redirectEmailVerificationLink(c, emailVerificationLinkInvalid)
+			return
+		}
+		utils.RecordErrorInSpan(findSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.EmailVerificationCollection,
+			"db.operation":  "find_one_and_delete",
+		})
+		findSpan.End()
+		logger.Error("failed to consume email verification link", zap.Error(err))
+		redirectEmailVerificationLink(c, emailVerificationLinkError)
+		return
+	}
+	utils.AddSpanAttribute(findSpan, "verification.found", true)
+	findSpan.End()
+
+	logger = logger.With(zap.String("cpf", verification.CPF))
+	if err := promoteVerifiedEmail(ctx, c, logger, verification.CPF, verification.Email); err != nil {
+		redirectEmailVerificationLink(c, emailVerificationLinkError)
+		return
+	}
+
+	redirectEmailVerificationLink(c, emailVerificationLinkVerified)
+}
+
+// redirectEmailVerificationLink sends the citizen back to the app deep-link with the outcome
+func redirectEmailVerificationLink(c *gin.Context, status string) {
+	target, err := url.Parse(config.AppConfig.EmailVerificationRedirectURL)
+	if err != nil {
+		observability.Logger().Error("invalid email verification redirect URL", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Invalid email verification redirect URL",
+		})
+		return
+	}
+	query := target.Query()
+	query.Set("status", status)
+	target.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+// promoteVerifiedEmail stores a verified email as the citizen's self-declared email, then clears
+// the verification data, invalidates the citizen caches and logs the audit event. Only a failure
+// to store the email is returned; the other steps are best effort.
+func promoteVerifiedEmail(ctx context.Context, c *gin.Context, logger *logging.SafeLogger, cpf, verifiedEmail string) error {
+	// Use cache service for verified email update with tracing
+	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_verified_email_via_cache")
+	email := buildSelfDeclaredEmail(verifiedEmail, time.Now())
+	cacheService := services.NewCacheService()
+	_, err := cacheService.UpdateSelfDeclaredEmail(ctx, cpf, &email, nil)
+	if err != nil {
+		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
+			"cache.operation": "update_verified_email",
+			"cache.service":   "unified_cache_service",
+		})
+		updateSpan.End()
+		logger.Error("failed to update verified email via cache service", zap.Error(err))
+		return err
+	}
+	updateSpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+
+	// Clean up verification data and the pending email
+	ctx, cleanupSpan, cleanupCleanup := utils.TraceDatabaseOperation(ctx, "cleanup_email_verification", "delete", "cpf")
+	defer cleanupCleanup()
+	if err := services.CompleteEmailVerification(ctx, cpf, verifiedEmail); err != nil {
+		utils.RecordErrorInSpan(cleanupSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.EmailVerificationCollection,
+			"db.operation":  "delete",
+		})
+		logger.Warn("failed to cleanup email verification data", zap.Error(err))
+		// Don't fail the entire operation for cleanup failure
+	}
+	cleanupSpan.End()
+
+	// Invalidate all related caches with tracing
+	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
+	if err := utils.InvalidateCitizenCache(ctx, cpf); err != nil {
+		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
+			"cache.key": fmt.Sprintf("citizen:%s", cpf),
+		})
+		logger.Warn("failed to invalidate cache", zap.Error(err))
+	}
+	cacheSpan.End()
+
+	// Log audit event with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "email_verification_success", "email_verification")
+	auditCtx := utils.GetAuditContextFromGin(c, cpf)
+	if err := utils.LogEmailVerificationSuccess(ctx, auditCtx, verifiedEmail); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "email_verification_success",
+			"audit.resource": "email_verification",
+		})
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+)
+
+func setupVerifyEmailLinkTest(t *testing.T) *gin.Engine {
+	_ = logging.InitLogger()
+	gin.SetMode(gin.TestMode)
+
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	previous := *config.AppConfig
+	config.AppConfig.EmailVerificationEnabled = true
+	config.AppConfig.EmailVerificationLinkURL = "https://api.example.com/v1/verify/email"
+	config.AppConfig.EmailVerificationLinkSecret = "link-secret"
+	config.AppConfig.EmailVerificationRedirectURL = "rioapp://email-verified?source=email"
+	t.Cleanup(func() { *config.AppConfig = previous })
+
+	router := gin.New()
+	router.GET("/verify/email", VerifyEmailLink)
+	return router
+}
+
+func TestVerifyEmailLink_Disabled(t *testing.T) {
+	router := setupVerifyEmailLinkTest(t)
+	config.AppConfig.EmailVerificationLinkURL = ""
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/verify/email?token=abc.def", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestVerifyEmailLink_InvalidSignatureRedirects(t *testing.T) {
+	router := setupVerifyEmailLinkTest(t)
+
+	// Tokens with a bad signature are rejected before any lookup
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/verify/email?token=abc.forged", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("status = %d, want 302", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "rioapp://email-verified?source=email&status=invalid" {
+		t.Errorf("Location = %q, want the redirect URL with status=invalid", location)
+	}
+}
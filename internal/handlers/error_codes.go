@@ -0,0 +1,97 @@
+package handlers
+
+import "encoding/json"
+
+// Machine-readable error codes returned in ErrorResponse.Code. Clients branch on and localize
+// them, so existing values must never change; add a new code instead.
+const (
+	// Request shape
+	ErrCodeInvalidRequestBody = "INVALID_REQUEST_BODY"
+	ErrCodeValidationFailed   = "VALIDATION_FAILED"
+	ErrCodeValidationRule     = "VALIDATION_RULE_VIOLATED"
+	ErrCodeNoFieldsProvided   = "NO_FIELDS_PROVIDED"
+	ErrCodePaginationInvalid  = "PAGINATION_INVALID"
+	ErrCodeFilterInvalid      = "FILTER_INVALID"
+	ErrCodeFormatInvalid      = "FORMAT_INVALID"
+
+	// Authorization
+	ErrCodeAdminRequired = "ADMIN_REQUIRED"
+
+	// Citizen
+	ErrCodeCPFInvalid      = "CPF_INVALID"
+	ErrCodeCitizenNotFound = "CITIZEN_NOT_FOUND"
+
+	// External IDs
+	ErrCodeExternalIDSystemUnsupported = "EXTERNAL_ID_SYSTEM_UNSUPPORTED"
+	ErrCodeExternalIDAmbiguous         = "EXTERNAL_ID_AMBIGUOUS"
+
+	// Wallet
+	ErrCodeWalletSectionInvalid = "WALLET_SECTION_INVALID"
+
+	// Self-declared data
+	ErrCodeVersionInvalid        = "VERSION_INVALID"
+	ErrCodeVersionConflict       = "VERSION_CONFLICT"
+	ErrCodeAddressUnchanged      = "ADDRESS_UNCHANGED"
+	ErrCodePhoneInvalid          = "PHONE_INVALID"
+	ErrCodePhoneUnchanged        = "PHONE_UNCHANGED"
+	ErrCodeEmailInvalid          = "EMAIL_INVALID"
+	ErrCodeEmailUnchanged        = "EMAIL_UNCHANGED"
+	ErrCodeEthnicityInvalid      = "ETHNICITY_INVALID"
+	ErrCodeExhibitionNameInvalid = "EXHIBITION_NAME_INVALID"
+	ErrCodeExhibitionNameShort   = "EXHIBITION_NAME_TOO_SHORT"
+	ErrCodeExhibitionNameLong    = "EXHIBITION_NAME_TOO_LONG"
+	ErrCodeExhibitionNameChars   = "EXHIBITION_NAME_INVALID_CHARACTERS"
+	ErrCodeExhibitionNameBlocked = "EXHIBITION_NAME_BLOCKED"
+	ErrCodeGenderInvalid         = "GENDER_INVALID"
+	ErrCodeFamilyIncomeInvalid   = "FAMILY_INCOME_INVALID"
+	ErrCodeEducationInvalid      = "EDUCATION_INVALID"
+	ErrCodeDisabilityInvalid     = "DISABILITY_INVALID"
+
+	// Ethnicity options
+	ErrCodeEthnicityOptionExists   = "ETHNICITY_OPTION_EXISTS"
+	ErrCodeEthnicityOptionNotFound = "ETHNICITY_OPTION_NOT_FOUND"
+	ErrCodeEthnicityOptionLast     = "ETHNICITY_OPTION_LAST"
+
+	// Beta groups
+	ErrCodeBetaGroupIDInvalid = "BETA_GROUP_ID_INVALID"
+	ErrCodeBetaGroupNotFound  = "BETA_GROUP_NOT_FOUND"
+
+	// User config
+	ErrCodeOptOutReasonInvalid     = "OPT_OUT_REASON_INVALID"
+	ErrCodePreferredChannelInvalid = "PREFERRED_CHANNEL_INVALID"
+
+	// Verification
+	ErrCodePhoneVerificationExpired = "PHONE_VERIFICATION_EXPIRED"
+	ErrCodePhoneVerificationLocked  = "PHONE_VERIFICATION_LOCKED"
+	ErrCodeVerifiedPhoneNotFound    = "VERIFIED_PHONE_NOT_FOUND"
+	ErrCodeEmailVerificationExpired = "EMAIL_VERIFICATION_EXPIRED"
+	ErrCodeEmailVerificationOff     = "EMAIL_VERIFICATION_DISABLED"
+
+	// CF lookup
+	ErrCodeCFLookupRateLimited = "CF_LOOKUP_RATE_LIMITED"
+	ErrCodeCFLookupUnavailable = "CF_LOOKUP_UNAVAILABLE"
+
+	// Response projection
+	ErrCodeFieldsInvalid = "FIELDS_INVALID"
+
+	// Rate limiting
+	ErrCodeRateLimited = "RATE_LIMITED"
+
+	// Server side
+	ErrCodeInternal = "INTERNAL_ERROR"
+)
+
+// MarshalJSON keeps the legacy error field and the new message field in sync, so handlers that
+// still only set Error also expose message, and migrated handlers still expose error to clients
+// that haven't moved to code/message yet.
+func (e ErrorResponse) MarshalJSON() ([]byte, error) {
+	type errorResponse ErrorResponse
+	out := errorResponse(e)
+	if out.Message == "" {
+		out.Message = out.Error
+	}
+	if out.Error == "" {
+		out.Error = out.Message
+	}
+	return json.Marshal(out)
+}
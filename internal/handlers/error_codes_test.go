@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponse_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response ErrorResponse
+		want     map[string]interface{}
+	}{
+		{
+			name:     "coded response keeps legacy error field",
+			response: ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"},
+			want:     map[string]interface{}{"code": ErrCodeCPFInvalid, "message": "Invalid CPF format", "error": "Invalid CPF format"},
+		},
+		{
+			name:     "legacy response gains message field",
+			response: ErrorResponse{Error: "Erro interno do servidor"},
+			want:     map[string]interface{}{"message": "Erro interno do servidor", "error": "Erro interno do servidor"},
+		},
+		{
+			name:     "details are included",
+			response: ErrorResponse{Code: ErrCodeValidationRule, Message: "invalid UF", Details: map[string]string{"rule": "address_uf"}},
+			want: map[string]interface{}{
+				"code": ErrCodeValidationRule, "message": "invalid UF", "error": "invalid UF",
+				"details": map[string]interface{}{"rule": "address_uf"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.response)
+			require.NoError(t, err)
+
+			var got map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
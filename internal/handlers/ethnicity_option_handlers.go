@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// AdminAddEthnicityOption godoc
+// @Summary Adicionar opção de etnia
+// @Description Adiciona uma opção de etnia ao final da lista retornada por GET /citizen/ethnicity/options e aceita nas atualizações de etnia. O valor é salvo sem espaços nas extremidades e em minúsculas. Todas as instâncias passam a usar a nova lista imediatamente.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body models.CreateEthnicityOptionRequest true "Opção de etnia"
+// @Security BearerAuth
+// @Success 201 {object} models.EthnicityOption "Opção de etnia adicionada com sucesso"
+// @Failure 400 {object} ErrorResponse "Corpo da requisição inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 409 {object} ErrorResponse "Opção de etnia já existe"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/ethnicity/options [post]
+func AdminAddEthnicityOption(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminAddEthnicityOption")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	span.SetAttributes(
+		attribute.String("operation", "add_ethnicity_option"),
+		attribute.String("service", "admin"),
+	)
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "create_ethnicity_option_request")
+	var req models.CreateEthnicityOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "CreateEthnicityOptionRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeInvalidRequestBody, Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	value := services.NormalizeEthnicityOption(req.Value)
+	if value == "" {
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeValidationFailed, Message: "value is required"})
+		return
+	}
+	utils.AddSpanAttribute(inputSpan, "input.value", value)
+	inputSpan.End()
+
+	// Add option with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "ethnicity_option_service", "add")
+	option, err := services.NewEthnicityOptionService(logger).Add(ctx, value)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "ethnicity_option_service",
+			"service.operation": "add",
+		})
+		serviceSpan.End()
+		if errors.Is(err, services.ErrEthnicityOptionExists) {
+			c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodeEthnicityOptionExists, Message: err.Error()})
+			return
+		}
+		logger.Error("failed to add ethnicity option", zap.Error(err), zap.String("value", value))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to add ethnicity option"})
+		return
+	}
+	serviceSpan.End()
+
+	refreshEthnicityOptions(c, logger)
+
+	c.JSON(http.StatusCreated, option)
+
+	logger.Debug("AdminAddEthnicityOption completed",
+		zap.String("value", value),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminRemoveEthnicityOption godoc
+// @Summary Remover opção de etnia
+// @Description Remove uma opção de etnia da lista retornada por GET /citizen/ethnicity/options; novas atualizações com esse valor passam a ser rejeitadas, mas cidadãos que já o declararam o mantêm. A última opção não pode ser removida. Todas as instâncias passam a usar a nova lista imediatamente.
+// @Tags admin
+// @Produce json
+// @Param value path string true "Valor da opção de etnia"
+// @Security BearerAuth
+// @Success 204 "Opção de etnia removida com sucesso"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 404 {object} ErrorResponse "Opção de etnia não encontrada"
+// @Failure 409 {object} ErrorResponse "A última opção de etnia não pode ser removida"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/ethnicity/options/{value} [delete]
+func AdminRemoveEthnicityOption(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminRemoveEthnicityOption")
+	defer span.End()
+
+	logger := observability.Logger()
+	value := services.NormalizeEthnicityOption(c.Param("value"))
+
+	span.SetAttributes(
+		attribute.String("operation", "remove_ethnicity_option"),
+		attribute.String("service", "admin"),
+		attribute.String("ethnicity_option", value),
+	)
+
+	// Remove option with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "ethnicity_option_service", "remove")
+	err := services.NewEthnicityOptionService(logger).Remove(ctx, value)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "ethnicity_option_service",
+			"service.operation": "remove",
+		})
+		serviceSpan.End()
+		switch {
+		case errors.Is(err, services.ErrEthnicityOptionNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Code: ErrCodeEthnicityOptionNotFound, Message: err.Error()})
+		case errors.Is(err, services.ErrLastEthnicityOption):
+			c.JSON(http.StatusConflict, ErrorResponse{Code: ErrCodeEthnicityOptionLast, Message: err.Error()})
+		default:
+			logger.Error("failed to remove ethnicity option", zap.Error(err), zap.String("value", value))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to remove ethnicity option"})
+		}
+		return
+	}
+	serviceSpan.End()
+
+	refreshEthnicityOptions(c, logger)
+
+	c.Status(http.StatusNoContent)
+
+	logger.Debug("AdminRemoveEthnicityOption completed",
+		zap.String("value", value),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// refreshEthnicityOptions reloads this instance's ethnicity options right away, so the change is
+// visible as soon as the admin request returns; other instances refresh on the change announcement
+func refreshEthnicityOptions(c *gin.Context, logger *logging.SafeLogger) {
+	if services.ConfigServiceInstance == nil {
+		return
+	}
+	if err := services.ConfigServiceInstance.RefreshEthnicityOptions(c.Request.Context()); err != nil {
+		logger.Warn("failed to refresh ethnicity options", zap.Error(err))
+	}
+}
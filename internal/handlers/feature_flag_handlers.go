@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetFeatureFlags godoc
+// @Summary Avaliar feature flags
+// @Description Avalia todas as feature flags para um CPF: cada flag está ativa quando habilitada e o CPF está na lista da flag, pertence (por um telefone na whitelist) a um dos grupos beta da flag ou cai no percentual de rollout. Sem CPF, apenas flags com rollout de 100% ficam ativas. Flags conhecidas ainda não cadastradas usam o valor padrão. As avaliações são cacheadas por FEATURE_FLAG_CACHE_TTL, mas qualquer alteração de flag (como desabilitá-la) vale imediatamente.
+// @Tags config
+// @Produce json
+// @Param cpf query string false "CPF do cidadão"
+// @Success 200 {object} models.FeatureFlagEvaluationResponse "Flags avaliadas com sucesso"
+// @Failure 400 {object} ErrorResponse "CPF inválido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /config/flags [get]
+func GetFeatureFlags(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetFeatureFlags")
+	defer span.End()
+
+	logger := observability.Logger()
+	cpf := strings.TrimSpace(c.Query("cpf"))
+
+	span.SetAttributes(
+		attribute.String("operation", "get_feature_flags"),
+		attribute.String("service", "config"),
+	)
+
+	if cpf != "" && !utils.ValidateCPF(cpf) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		return
+	}
+
+	// Evaluate flags with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "feature_flag_service", "evaluate_all")
+	flags, err := services.NewFeatureFlagService(logger).EvaluateAll(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "feature_flag_service",
+			"service.operation": "evaluate_all",
+		})
+		serviceSpan.End()
+		logger.Error("failed to evaluate feature flags", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to evaluate feature flags"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.flags_count", len(flags))
+	serviceSpan.End()
+
+	c.JSON(http.StatusOK, models.FeatureFlagEvaluationResponse{CPF: cpf, Flags: flags})
+
+	logger.Debug("GetFeatureFlags completed",
+		zap.Int("flags_count", len(flags)),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminListFeatureFlags godoc
+// @Summary Listar feature flags
+// @Description Lista as feature flags cadastradas, ordenadas pela chave.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.FeatureFlagListResponse "Feature flags listadas com sucesso"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/feature-flags [get]
+func AdminListFeatureFlags(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminListFeatureFlags")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	span.SetAttributes(
+		attribute.String("operation", "list_feature_flags"),
+		attribute.String("service", "admin"),
+	)
+
+	flags, err := services.NewFeatureFlagService(logger).List(ctx)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list feature flags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.FeatureFlagListResponse{Flags: flags})
+
+	logger.Debug("AdminListFeatureFlags completed",
+		zap.Int("flags_count", len(flags)),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminGetFeatureFlag godoc
+// @Summary Obter feature flag
+// @Description Obtém a definição de uma feature flag cadastrada.
+// @Tags admin
+// @Produce json
+// @Param key path string true "Chave da feature flag"
+// @Security BearerAuth
+// @Success 200 {object} models.FeatureFlag "Feature flag obtida com sucesso"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 404 {object} ErrorResponse "Feature flag não encontrada"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/feature-flags/{key} [get]
+func AdminGetFeatureFlag(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminGetFeatureFlag")
+	defer span.End()
+
+	logger := observability.Logger()
+	key := c.Param("key")
+
+	span.SetAttributes(
+		attribute.String("operation", "get_feature_flag"),
+		attribute.String("service", "admin"),
+		attribute.String("feature_flag", key),
+	)
+
+	flag, err := services.NewFeatureFlagService(logger).Get(ctx, key)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		if errors.Is(err, services.ErrFeatureFlagNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get feature flag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+
+	logger.Debug("AdminGetFeatureFlag completed",
+		zap.String("flag", key),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminCreateFeatureFlag godoc
+// @Summary Criar feature flag
+// @Description Cadastra uma feature flag. A chave deve ter de 1 a 64 letras minúsculas, dígitos ou underscores. A flag fica ativa, quando habilitada, para os CPFs listados, para os cidadãos com telefone na whitelist de um dos grupos beta listados e para o percentual de rollout dos demais CPFs. A alteração vale imediatamente.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body models.FeatureFlagRequest true "Definição da feature flag"
+// @Security BearerAuth
+// @Success 201 {object} models.FeatureFlag "Feature flag criada com sucesso"
+// @Failure 400 {object} ErrorResponse "Definição inválida"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 409 {object} ErrorResponse "Feature flag já existe"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/feature-flags [post]
+func AdminCreateFeatureFlag(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminCreateFeatureFlag")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	span.SetAttributes(
+		attribute.String("operation", "create_feature_flag"),
+		attribute.String("service", "admin"),
+	)
+
+	var req models.FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	flag, err := services.NewFeatureFlagService(logger).Create(ctx, req)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		switch {
+		case errors.Is(err, services.ErrInvalidFeatureFlag):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrFeatureFlagExists):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create feature flag"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, flag)
+
+	logger.Debug("AdminCreateFeatureFlag completed",
+		zap.String("flag", flag.Key),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminUpdateFeatureFlag godoc
+// @Summary Atualizar feature flag
+// @Description Substitui a definição de uma feature flag (a chave do corpo é ignorada). Enviar enabled=false funciona como kill switch: a flag fica inativa para todos imediatamente, inclusive para avaliações já cacheadas.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Chave da feature flag"
+// @Param data body models.FeatureFlagRequest true "Definição da feature flag"
+// @Security BearerAuth
+// @Success 200 {object} models.FeatureFlag "Feature flag atualizada com sucesso"
+// @Failure 400 {object} ErrorResponse "Definição inválida"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 404 {object} ErrorResponse "Feature flag não encontrada"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/feature-flags/{key} [put]
+func AdminUpdateFeatureFlag(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminUpdateFeatureFlag")
+	defer span.End()
+
+	logger := observability.Logger()
+	key := c.Param("key")
+
+	span.SetAttributes(
+		attribute.String("operation", "update_feature_flag"),
+		attribute.String("service", "admin"),
+		attribute.String("feature_flag", key),
+	)
+
+	var req models.FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	flag, err := services.NewFeatureFlagService(logger).Update(ctx, key, req)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		switch {
+		case errors.Is(err, services.ErrInvalidFeatureFlag):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrFeatureFlagNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update feature flag"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+
+	logger.Debug("AdminUpdateFeatureFlag completed",
+		zap.String("flag", key),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// AdminDeleteFeatureFlag godoc
+// @Summary Remover feature flag
+// @Description Remove uma feature flag. Flags conhecidas pelo serviço (como cf_integration) voltam ao valor padrão. A alteração vale imediatamente.
+// @Tags admin
+// @Produce json
+// @Param key path string true "Chave da feature flag"
+// @Security BearerAuth
+// @Success 204 "Feature flag removida com sucesso"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - requer permissão de administrador"
+// @Failure 404 {object} ErrorResponse "Feature flag não encontrada"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/feature-flags/{key} [delete]
+func AdminDeleteFeatureFlag(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminDeleteFeatureFlag")
+	defer span.End()
+
+	logger := observability.Logger()
+	key := c.Param("key")
+
+	span.SetAttributes(
+		attribute.String("operation", "delete_feature_flag"),
+		attribute.String("service", "admin"),
+		attribute.String("feature_flag", key),
+	)
+
+	if err := services.NewFeatureFlagService(logger).Delete(ctx, key); err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		if errors.Is(err, services.ErrFeatureFlagNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete feature flag"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+
+	logger.Debug("AdminDeleteFeatureFlag completed",
+		zap.String("flag", key),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFeatureFlags_InvalidCPF(t *testing.T) {
+	r := gin.New()
+	r.GET("/v1/config/flags", GetFeatureFlags)
+
+	req, _ := http.NewRequest("GET", "/v1/config/flags?cpf=12345678900", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminCreateFeatureFlag_InvalidBody(t *testing.T) {
+	r := gin.New()
+	r.POST("/v1/admin/feature-flags", AdminCreateFeatureFlag)
+
+	for _, body := range []string{`{`, `{"key":"f","rollout_percentage":150}`} {
+		req, _ := http.NewRequest("POST", "/v1/admin/feature-flags", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "body %s", body)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// responseFieldNames returns the top-level JSON field names of a response struct, which are the
+// names accepted by the fields query parameter
+func responseFieldNames(response interface{}) []string {
+	t := reflect.TypeOf(response)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseFieldsParam parses the comma-separated fields query parameter against the allowed field
+// names. It returns the requested fields sorted and without duplicates, or nil when no field was
+// given, meaning the whole response.
+func parseFieldsParam(raw string, allowed []string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("invalid field %q: must be one of %s", field, strings.Join(allowed, ", "))
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+// projectFields keeps only the given top-level fields of a JSON-serializable response. Requested
+// fields missing from the response (omitted when empty) are returned as null.
+func projectFields(response interface{}, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			projected[field] = value
+		} else {
+			projected[field] = json.RawMessage("null")
+		}
+	}
+	return projected, nil
+}
+
+// projectionETagSuffix identifies a field projection in the ETag, so projected responses don't
+// share the ETag of the full representation (fields must be sorted, as from parseFieldsParam)
+func projectionETagSuffix(fields []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, ",")))
+	return hex.EncodeToString(sum[:4])
+}
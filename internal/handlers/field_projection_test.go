@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResponseFieldNames tests that the allowed fields are the JSON names of the response
+func TestResponseFieldNames(t *testing.T) {
+	names := responseFieldNames(&models.CitizenResponse{})
+	assert.Contains(t, names, "nome")
+	assert.Contains(t, names, "telefone")
+	assert.Contains(t, names, "_derived")
+	assert.NotContains(t, names, "Nome")
+
+	assert.Contains(t, responseFieldNames(models.CitizenWallet{}), "cf_lookup_pending")
+}
+
+// TestParseFieldsParam tests parsing of the fields query parameter
+func TestParseFieldsParam(t *testing.T) {
+	allowed := responseFieldNames(models.CitizenResponse{})
+
+	fields, err := parseFieldsParam("", allowed)
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = parseFieldsParam(" telefone, nome,,telefone ", allowed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nome", "telefone"}, fields, "fields are sorted and deduplicated")
+
+	_, err = parseFieldsParam("nome,senha", allowed)
+	assert.Error(t, err)
+}
+
+// TestProjectFields tests that only the requested top-level fields are kept
+func TestProjectFields(t *testing.T) {
+	nome := "Maria"
+	response := &models.CitizenResponse{CPF: "12345678901", Nome: &nome}
+
+	projected, err := projectFields(response, []string{"cpf", "nome", "self_declared_status"})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(projected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cpf":"12345678901","nome":"Maria","self_declared_status":null}`, string(data))
+}
+
+// TestProjectionETagSuffix tests that each projection gets its own ETag suffix
+func TestProjectionETagSuffix(t *testing.T) {
+	assert.Equal(t, projectionETagSuffix([]string{"nome", "telefone"}), projectionETagSuffix([]string{"nome", "telefone"}))
+	assert.NotEqual(t, projectionETagSuffix([]string{"nome"}), projectionETagSuffix([]string{"nome", "telefone"}))
+	assert.Len(t, projectionETagSuffix([]string{"nome"}), 8)
+}
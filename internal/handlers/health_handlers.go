@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	// deepHealthCheckTimeout bounds each deep health sub-check so one slow dependency doesn't stall the response
+	deepHealthCheckTimeout = 3 * time.Second
+	// syncQueueBacklogThreshold is the total sync backlog above which the sync queue is reported unhealthy
+	syncQueueBacklogThreshold = 10000
+	// auditBufferUtilizationThreshold is the audit buffer usage (percent) above which the audit worker is reported unhealthy
+	auditBufferUtilizationThreshold = 90.0
+)
+
+// deepHealthCheck is a single named component probe of the deep health check
+type deepHealthCheck struct {
+	name  string
+	check func(ctx context.Context) ServiceHealth
+}
+
+// shuttingDown is set once the server starts draining, so readiness fails before connections stop
+var shuttingDown atomic.Bool
+
+// SetShuttingDown marks the server as draining: /health/ready answers 503 from then on, while
+// /health/live keeps answering 200
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// ProbeResponse is the body of the liveness probe
+type ProbeResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
+}
+
+// LivenessCheck godoc
+// @Summary Liveness probe
+// @Description Indica apenas que o processo está no ar, sem verificar dependências. Use como liveness probe do Kubernetes; falhas de MongoDB ou Redis não devem reiniciar o pod.
+// @Tags health
+// @Produce json
+// @Success 200 {object} ProbeResponse "Processo no ar"
+// @Router /health/live [get]
+func LivenessCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, ProbeResponse{Status: "alive", Timestamp: time.Now(), Version: Version})
+}
+
+// ReadinessCheck godoc
+// @Summary Readiness probe
+// @Description Verifica se a API pode receber tráfego: MongoDB e Redis acessíveis e servidor fora do encerramento. Durante o encerramento gracioso responde 503 por SHUTDOWN_DRAIN_DELAY antes de o servidor parar de aceitar conexões, para que o tráfego seja drenado. Use como readiness probe do Kubernetes.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponse "Pronta para receber tráfego"
+// @Failure 503 {object} HealthResponse "Dependência indisponível ou servidor em encerramento"
+// @Router /health/ready [get]
+func ReadinessCheck(c *gin.Context) {
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ReadinessCheck")
+	defer span.End()
+
+	if shuttingDown.Load() {
+		span.SetAttributes(attribute.Bool("health.shutting_down", true))
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{
+			Status:    false,
+			Timestamp: time.Now(),
+			Version:   Version,
+			Services: map[string]ServiceHealth{
+				"server": {Status: false, Message: "Shutting down", Timestamp: time.Now()},
+			},
+		})
+		return
+	}
+
+	checks := []deepHealthCheck{
+		{name: "mongodb", check: checkMongoDBHealth},
+		{name: "redis", check: checkRedisHealth},
+	}
+	results := runHealthChecks(ctx, checks)
+
+	ready := true
+	servicesHealth := make(map[string]ServiceHealth, len(checks))
+	for i, hc := range checks {
+		servicesHealth[hc.name] = results[i]
+		span.SetAttributes(attribute.Bool("health."+hc.name, results[i].Status))
+		if !results[i].Status {
+			ready = false
+		}
+	}
+	span.SetAttributes(attribute.Bool("health.overall", ready))
+
+	statusCode := http.StatusOK
+	if !ready {
+		statusCode = http.StatusServiceUnavailable
+		observability.Logger().Warn("readiness check failed",
+			zap.Bool("mongodb_healthy", servicesHealth["mongodb"].Status),
+			zap.Bool("redis_healthy", servicesHealth["redis"].Status))
+	}
+
+	c.JSON(statusCode, HealthResponse{
+		Status:    ready,
+		Timestamp: time.Now(),
+		Version:   Version,
+		Services:  servicesHealth,
+	})
+}
+
+// DeepHealthCheck godoc
+// @Summary Verificação de saúde detalhada
+// @Description Verifica a saúde da API e de todas as suas dependências internas: MongoDB, Redis, serviço de consulta de CF (ping no MCP), backlog das filas de sincronização e utilização do buffer do worker de auditoria. Cada verificação tem seu próprio timeout. Apenas administradores, pois expõe detalhes internos; use /health para load balancers.
+// @Tags health
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} HealthResponse "Todos os componentes estão saudáveis"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 500 {object} HealthResponse "Um ou mais componentes não estão saudáveis"
+// @Router /health/deep [get]
+func DeepHealthCheck(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "DeepHealthCheck")
+	defer span.End()
+
+	logger := observability.Logger()
+	logger.Debug("DeepHealthCheck called")
+
+	checks := []deepHealthCheck{
+		{name: "mongodb", check: checkMongoDBHealth},
+		{name: "redis", check: checkRedisHealth},
+		{name: "cf_lookup", check: checkCFLookupHealth},
+		{name: "sync_queue", check: checkSyncQueueHealth},
+		{name: "audit_worker", check: checkAuditWorkerHealth},
+	}
+
+	results := runHealthChecks(ctx, checks)
+
+	overallHealthy := true
+	servicesHealth := make(map[string]ServiceHealth, len(checks))
+	for i, hc := range checks {
+		servicesHealth[hc.name] = results[i]
+		span.SetAttributes(attribute.Bool("health."+hc.name, results[i].Status))
+		if !results[i].Status {
+			overallHealthy = false
+			logger.Warn("deep health check component unhealthy",
+				zap.String("component", hc.name),
+				zap.String("message", results[i].Message))
+		}
+	}
+	span.SetAttributes(attribute.Bool("health.overall", overallHealthy))
+
+	statusCode := http.StatusOK
+	if !overallHealthy {
+		statusCode = http.StatusInternalServerError
+	}
+
+	c.JSON(statusCode, HealthResponse{
+		Status:    overallHealthy,
+		Timestamp: time.Now(),
+		Version:   Version,
+		Services:  servicesHealth,
+	})
+
+	logger.Debug("DeepHealthCheck completed",
+		zap.Bool("healthy", overallHealthy),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// runHealthChecks runs every check concurrently, each with its own timeout, returning the results in order
+func runHealthChecks(ctx context.Context, checks []deepHealthCheck) []ServiceHealth {
+	results := make([]ServiceHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, hc := range checks {
+		wg.Add(1)
+		go func(i int, hc deepHealthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, deepHealthCheckTimeout)
+			defer cancel()
+			results[i] = runDeepHealthCheck(checkCtx, hc.check)
+		}(i, hc)
+	}
+	wg.Wait()
+	return results
+}
+
+// runDeepHealthCheck runs a sub-check and reports a timeout if it doesn't finish before the context deadline
+func runDeepHealthCheck(ctx context.Context, check func(ctx context.Context) ServiceHealth) ServiceHealth {
+	resultChan := make(chan ServiceHealth, 1)
+	go func() {
+		resultChan <- check(ctx)
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result
+	case <-ctx.Done():
+		return ServiceHealth{Status: false, Message: "Check timed out", Timestamp: time.Now()}
+	}
+}
+
+// checkMongoDBHealth pings MongoDB
+func checkMongoDBHealth(ctx context.Context) ServiceHealth {
+	if config.MongoDB == nil {
+		return ServiceHealth{Status: false, Message: "Not initialized", Timestamp: time.Now()}
+	}
+	if err := config.MongoDB.Client().Ping(ctx, nil); err != nil {
+		return ServiceHealth{Status: false, Message: "Connection failed", Timestamp: time.Now()}
+	}
+	return ServiceHealth{Status: true, Message: "Connected", Timestamp: time.Now()}
+}
+
+// checkRedisHealth pings Redis
+func checkRedisHealth(ctx context.Context) ServiceHealth {
+	if config.Redis == nil {
+		return ServiceHealth{Status: false, Message: "Not initialized", Timestamp: time.Now()}
+	}
+	if _, err := config.Redis.Ping(ctx).Result(); err != nil {
+		return ServiceHealth{Status: false, Message: "Connection failed", Timestamp: time.Now()}
+	}
+	return ServiceHealth{Status: true, Message: "Connected", Timestamp: time.Now()}
+}
+
+// checkCFLookupHealth pings the MCP server backing CF lookups (healthy when CF lookup is disabled)
+func checkCFLookupHealth(ctx context.Context) ServiceHealth {
+	if config.AppConfig != nil && !config.AppConfig.CFLookupEnabled {
+		return ServiceHealth{Status: true, Message: "Disabled", Timestamp: time.Now()}
+	}
+	if services.CFLookupServiceInstance == nil {
+		return ServiceHealth{Status: false, Message: "Not initialized", Timestamp: time.Now()}
+	}
+	if err := services.CFLookupServiceInstance.Ping(ctx); err != nil {
+		return ServiceHealth{Status: false, Message: "MCP server unreachable", Timestamp: time.Now()}
+	}
+	return ServiceHealth{Status: true, Message: "MCP server reachable", Timestamp: time.Now()}
+}
+
+// checkSyncQueueHealth reports the total sync queue backlog
+func checkSyncQueueHealth(ctx context.Context) ServiceHealth {
+	if config.Redis == nil {
+		return ServiceHealth{Status: false, Message: "Redis not initialized", Timestamp: time.Now()}
+	}
+	backlog, err := services.GetSyncQueueBacklog(ctx)
+	if err != nil {
+		return ServiceHealth{Status: false, Message: "Failed to read queue backlog", Timestamp: time.Now()}
+	}
+	return syncQueueHealth(backlog)
+}
+
+// syncQueueHealth evaluates the sync queue backlog against syncQueueBacklogThreshold
+func syncQueueHealth(backlog map[string]int64) ServiceHealth {
+	var total int64
+	var deepestQueue string
+	var deepestDepth int64
+	for queue, depth := range backlog {
+		total += depth
+		if depth > deepestDepth {
+			deepestQueue, deepestDepth = queue, depth
+		}
+	}
+
+	message := fmt.Sprintf("Backlog: %d jobs", total)
+	if deepestDepth > 0 {
+		message += fmt.Sprintf(" (largest: %s=%d)", deepestQueue, deepestDepth)
+	}
+
+	return ServiceHealth{
+		Status:    total <= syncQueueBacklogThreshold,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
+
+// checkAuditWorkerHealth reports the audit worker buffer utilization
+func checkAuditWorkerHealth(_ context.Context) ServiceHealth {
+	return auditWorkerHealth(utils.GetAuditWorker().GetAuditWorkerStats())
+}
+
+// auditWorkerHealth evaluates audit worker stats against auditBufferUtilizationThreshold
+func auditWorkerHealth(stats map[string]interface{}) ServiceHealth {
+	if stats["status"] != "running" {
+		return ServiceHealth{Status: false, Message: "Not initialized", Timestamp: time.Now()}
+	}
+
+	capacity, _ := stats["buffer_capacity"].(int)
+	usage, _ := stats["buffer_usage"].(int)
+
+	var utilization float64
+	if capacity > 0 {
+		utilization = float64(usage) / float64(capacity) * 100
+	}
+
+	return ServiceHealth{
+		Status:    utilization <= auditBufferUtilizationThreshold,
+		Message:   fmt.Sprintf("Buffer utilization: %.1f%% (%d/%d)", utilization, usage, capacity),
+		Timestamp: time.Now(),
+	}
+}
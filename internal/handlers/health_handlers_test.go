@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDeepHealthCheck_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	result := runDeepHealthCheck(ctx, func(ctx context.Context) ServiceHealth {
+		time.Sleep(200 * time.Millisecond)
+		return ServiceHealth{Status: true}
+	})
+
+	assert.False(t, result.Status)
+	assert.Equal(t, "Check timed out", result.Message)
+}
+
+func TestRunDeepHealthCheck_Completes(t *testing.T) {
+	result := runDeepHealthCheck(context.Background(), func(ctx context.Context) ServiceHealth {
+		return ServiceHealth{Status: true, Message: "Connected"}
+	})
+
+	assert.True(t, result.Status)
+	assert.Equal(t, "Connected", result.Message)
+}
+
+func TestSyncQueueHealth(t *testing.T) {
+	healthy := syncQueueHealth(map[string]int64{"citizen": 3, "cf_lookup": 7})
+	assert.True(t, healthy.Status)
+	assert.Equal(t, "Backlog: 10 jobs (largest: cf_lookup=7)", healthy.Message)
+
+	empty := syncQueueHealth(map[string]int64{"citizen": 0})
+	assert.True(t, empty.Status)
+	assert.Equal(t, "Backlog: 0 jobs", empty.Message)
+
+	unhealthy := syncQueueHealth(map[string]int64{"citizen": syncQueueBacklogThreshold + 1})
+	assert.False(t, unhealthy.Status)
+}
+
+func TestAuditWorkerHealth(t *testing.T) {
+	notInitialized := auditWorkerHealth(map[string]interface{}{"status": "not_initialized"})
+	assert.False(t, notInitialized.Status)
+
+	healthy := auditWorkerHealth(map[string]interface{}{
+		"status":          "running",
+		"buffer_capacity": 100,
+		"buffer_usage":    25,
+	})
+	assert.True(t, healthy.Status)
+	assert.Equal(t, "Buffer utilization: 25.0% (25/100)", healthy.Message)
+
+	saturated := auditWorkerHealth(map[string]interface{}{
+		"status":          "running",
+		"buffer_capacity": 100,
+		"buffer_usage":    95,
+	})
+	assert.False(t, saturated.Status)
+}
+
+func TestLivenessCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer shuttingDown.Store(false)
+	router := gin.New()
+	router.GET("/health/live", LivenessCheck)
+
+	// Liveness doesn't depend on dependencies nor on draining
+	for _, draining := range []bool{false, true} {
+		shuttingDown.Store(draining)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response ProbeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "alive", response.Status)
+	}
+}
+
+func TestReadinessCheck_ShuttingDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer shuttingDown.Store(false)
+	router := gin.New()
+	router.GET("/health/ready", ReadinessCheck)
+
+	SetShuttingDown()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Status)
+	assert.Equal(t, "Shutting down", response.Services["server"].Message)
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	results := runHealthChecks(context.Background(), []deepHealthCheck{
+		{name: "up", check: func(ctx context.Context) ServiceHealth { return ServiceHealth{Status: true} }},
+		{name: "down", check: func(ctx context.Context) ServiceHealth { return ServiceHealth{Status: false} }},
+	})
+
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Status)
+	assert.False(t, results[1].Status)
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/middleware"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/services"
@@ -18,7 +20,7 @@ import (
 
 // GetLegalEntities godoc
 // @Summary Obter entidades jurídicas associadas ao CPF
-// @Description Recupera a lista paginada de entidades jurídicas (pessoas jurídicas) associadas ao CPF do cidadão. A busca é feita através do campo 'cpf_socio' no array 'socios' de cada entidade.
+// @Description Recupera a lista paginada de entidades jurídicas (pessoas jurídicas) associadas ao CPF do cidadão. A busca é feita através do campo 'cpf_socio' no array 'socios' de cada entidade. Os resultados são armazenados em cache por CPF e filtro (LEGAL_ENTITY_CACHE_TTL).
 // @Tags citizen
 // @Accept json
 // @Produce json
@@ -194,7 +196,7 @@ func GetLegalEntityByCNPJ(c *gin.Context) {
 		})
 		querySpan.End()
 
-		if err.Error() == "legal entity not found" {
+		if errors.Is(err, services.ErrLegalEntityNotFound) {
 			logger.Warn("legal entity not found", zap.String("cnpj", cnpj))
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Legal entity not found"})
 			return
@@ -334,3 +336,101 @@ func GetLegalEntityByCNPJ(c *gin.Context) {
 		zap.String("cpf", authenticatedCPF))
 	c.JSON(http.StatusForbidden, ErrorResponse{Error: "Access denied - you are not authorized to view this legal entity"})
 }
+
+// AdminGetLegalEntity godoc
+// @Summary Obter registro completo de uma entidade jurídica (admin)
+// @Description Recupera o registro completo de uma entidade jurídica pelo CNPJ, incluindo todos os sócios, para atendimento. Os resultados são armazenados em cache (LEGAL_ENTITY_CACHE_TTL) e cada acesso é registrado na auditoria, já que o registro contém CPFs de outros cidadãos.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param cnpj path string true "CNPJ da entidade (14 dígitos)" minLength(14) maxLength(14)
+// @Security BearerAuth
+// @Success 200 {object} models.LegalEntity "Registro da entidade jurídica obtido com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de CNPJ inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 404 {object} ErrorResponse "Entidade jurídica não encontrada"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/legal-entities/{cnpj} [get]
+func AdminGetLegalEntity(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "AdminGetLegalEntity")
+	defer span.End()
+
+	cnpj := c.Param("cnpj")
+	logger := observability.Logger().With(zap.String("cnpj", cnpj))
+
+	// Add CNPJ to span attributes
+	span.SetAttributes(
+		attribute.String("cnpj", cnpj),
+		attribute.String("operation", "admin_get_legal_entity"),
+		attribute.String("service", "legal_entity"),
+	)
+
+	logger.Debug("AdminGetLegalEntity called", zap.String("cnpj", cnpj))
+
+	// Validate CNPJ with tracing; only the 14 digits are accepted, as stored
+	ctx, cnpjSpan := utils.TraceInputValidation(ctx, "cnpj_format", "cnpj")
+	if len(cnpj) != 14 || !utils.ValidateCNPJ(cnpj) {
+		utils.RecordErrorInSpan(cnpjSpan, fmt.Errorf("invalid CNPJ format"), map[string]interface{}{
+			"cnpj": cnpj,
+		})
+		cnpjSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CNPJ format"})
+		return
+	}
+	cnpjSpan.End()
+
+	// Check if legal entity service is available
+	if services.LegalEntityServiceInstance == nil {
+		logger.Error("legal entity service not initialized")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Legal entity service unavailable"})
+		return
+	}
+
+	// Query legal entity with tracing
+	ctx, querySpan := utils.TraceDatabaseFind(ctx, "legal_entities", "cnpj")
+	entity, err := services.LegalEntityServiceInstance.GetLegalEntityByCNPJ(ctx, cnpj)
+	if err != nil {
+		utils.RecordErrorInSpan(querySpan, err, map[string]interface{}{
+			"operation": "admin_get_legal_entity",
+			"cnpj":      cnpj,
+		})
+		querySpan.End()
+
+		if errors.Is(err, services.ErrLegalEntityNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Legal entity not found"})
+			return
+		}
+
+		logger.Error("failed to get legal entity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve legal entity"})
+		return
+	}
+	querySpan.End()
+
+	// Audit the access, since the record exposes the partners' CPFs
+	adminCPF, _ := middleware.ExtractCPFFromToken(c)
+	auditCtx := utils.AuditContext{
+		UserID:    adminCPF,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogLegalEntityAccess(ctx, auditCtx, cnpj, len(entity.Partners)); err != nil {
+		logger.Warn("failed to log legal entity access audit event", zap.Error(err))
+	}
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, entity)
+	responseSpan.End()
+
+	// Log total operation time
+	logger.Debug("AdminGetLegalEntity completed",
+		zap.String("cnpj", cnpj),
+		zap.String("admin_cpf", adminCPF),
+		zap.Int("partners", len(entity.Partners)),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
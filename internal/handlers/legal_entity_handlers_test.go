@@ -29,6 +29,7 @@ func setupLegalEntityHandlersTest(t *testing.T) (*gin.Engine, func()) {
 
 	ctx := context.Background()
 	database := config.MongoDB
+	clearLegalEntityCache(ctx)
 
 	// Initialize global legal entity service instance
 	services.LegalEntityServiceInstance = services.NewLegalEntityService(database, logging.GetLogger())
@@ -39,10 +40,21 @@ func setupLegalEntityHandlersTest(t *testing.T) (*gin.Engine, func()) {
 
 	return router, func() {
 		_ = database.Drop(ctx)
+		clearLegalEntityCache(ctx)
 		services.LegalEntityServiceInstance = nil
 	}
 }
 
+// clearLegalEntityCache removes cached lookups, since tests reuse CNPJs and CPFs with different data
+func clearLegalEntityCache(ctx context.Context) {
+	if config.Redis == nil {
+		return
+	}
+	if keys, err := config.Redis.Keys(ctx, "legal_entities:*").Result(); err == nil && len(keys) > 0 {
+		config.Redis.Del(ctx, keys...)
+	}
+}
+
 // Helper function to create admin middleware
 func adminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -79,8 +91,6 @@ func serviceAccountMiddleware(clientID string) gin.HandlerFunc {
 }
 
 // Helper function to create test legal entity
-//
-//nolint:unused // Keeping for potential future use
 func createTestLegalEntity(cnpj, companyName, responsibleCPF string, partners []string) bson.M {
 	entity := bson.M{
 		"cnpj":            cnpj,
@@ -1016,3 +1026,62 @@ func generateCNPJ(seed int) string {
 	// Note: These are not validated CNPJs, just for test data
 	return fmt.Sprintf("123456780001%02d", seed%100)
 }
+
+func TestAdminGetLegalEntity_Success(t *testing.T) {
+	_, cleanup := setupLegalEntityHandlersTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.LegalEntityCollection)
+	_, err := collection.InsertOne(ctx, createTestLegalEntity("11222333000181", "Test Company Support", "99999999999",
+		[]string{"03561350712", "11144477735"}))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(adminMiddleware())
+	router.GET("/admin/legal-entities/:cnpj", AdminGetLegalEntity)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "/admin/legal-entities/11222333000181", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.LegalEntity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Test Company Support", response.CompanyName)
+		assert.Len(t, response.Partners, 2, "all partners are returned")
+
+		// The second request is served from cache
+		_, err := collection.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err)
+	}
+}
+
+func TestAdminGetLegalEntity_NotFound(t *testing.T) {
+	_, cleanup := setupLegalEntityHandlersTest(t)
+	defer cleanup()
+
+	router := gin.New()
+	router.Use(adminMiddleware())
+	router.GET("/admin/legal-entities/:cnpj", AdminGetLegalEntity)
+
+	req, _ := http.NewRequest("GET", "/admin/legal-entities/11222333000181", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminGetLegalEntity_InvalidCNPJ(t *testing.T) {
+	router := gin.New()
+	router.GET("/admin/legal-entities/:cnpj", AdminGetLegalEntity)
+
+	for _, cnpj := range []string{"12345", "11222333000182", "11111111111111", "11.222.333000181"} {
+		req, _ := http.NewRequest("GET", "/admin/legal-entities/"+cnpj, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "CNPJ %q", cnpj)
+	}
+}
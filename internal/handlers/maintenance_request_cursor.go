@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maintenanceRequestCursor is the position after which the next page of maintenance requests starts.
+// Requests are sorted by data_inicio and _id, both descending.
+type maintenanceRequestCursor struct {
+	DataInicio string `json:"d"`
+	ID         string `json:"i"`
+}
+
+// encodeMaintenanceRequestCursor returns the opaque cursor pointing after doc
+func encodeMaintenanceRequestCursor(doc models.MaintenanceRequestDocument) string {
+	data, _ := json.Marshal(maintenanceRequestCursor{DataInicio: doc.DataInicio, ID: doc.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeMaintenanceRequestCursor parses a cursor returned by encodeMaintenanceRequestCursor
+func decodeMaintenanceRequestCursor(value string) (*maintenanceRequestCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+	var cursor maintenanceRequestCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor payload: %w", err)
+	}
+	if cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor payload: missing id")
+	}
+	return &cursor, nil
+}
+
+// maintenanceRequestFilter returns the filter for the CPF's maintenance requests after cursor,
+// served by the cpf_1_data_inicio_-1__id_-1 index
+func maintenanceRequestFilter(cpf string, cursor *maintenanceRequestCursor) bson.M {
+	if cursor == nil {
+		return bson.M{"cpf": cpf}
+	}
+	return bson.M{
+		"cpf": cpf,
+		"$or": bson.A{
+			bson.M{"data_inicio": bson.M{"$lt": cursor.DataInicio}},
+			bson.M{"data_inicio": cursor.DataInicio, "_id": bson.M{"$lt": cursor.ID}},
+		},
+	}
+}
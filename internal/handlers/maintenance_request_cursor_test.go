@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMaintenanceRequestCursor_RoundTrip(t *testing.T) {
+	doc := models.MaintenanceRequestDocument{ID: "abc123", DataInicio: "2024-01-15T10:30:00"}
+
+	cursor, err := decodeMaintenanceRequestCursor(encodeMaintenanceRequestCursor(doc))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", cursor.ID)
+	assert.Equal(t, "2024-01-15T10:30:00", cursor.DataInicio)
+}
+
+func TestDecodeMaintenanceRequestCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"not base64!", "bm90IGpzb24", "e30"} { // "not json", "{}"
+		_, err := decodeMaintenanceRequestCursor(value)
+		assert.Error(t, err, "cursor %q", value)
+	}
+}
+
+func TestMaintenanceRequestFilter(t *testing.T) {
+	assert.Equal(t, bson.M{"cpf": "12345678901"}, maintenanceRequestFilter("12345678901", nil))
+
+	filter := maintenanceRequestFilter("12345678901", &maintenanceRequestCursor{DataInicio: "2024-01-15", ID: "abc"})
+	assert.Equal(t, "12345678901", filter["cpf"])
+	assert.Equal(t, bson.A{
+		bson.M{"data_inicio": bson.M{"$lt": "2024-01-15"}},
+		bson.M{"data_inicio": "2024-01-15", "_id": bson.M{"$lt": "abc"}},
+	}, filter["$or"])
+}
+
+func TestParseMaintenanceRequestFilters(t *testing.T) {
+	parse := func(query string) (maintenanceRequestFilters, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		return parseMaintenanceRequestFilters(c)
+	}
+
+	filters, err := parse("")
+	require.NoError(t, err)
+	assert.True(t, filters.empty())
+	assert.Equal(t, "", filters.cacheKeySuffix())
+	assert.Equal(t, bson.M{"cpf": "12345678901"}, filters.apply(maintenanceRequestFilter("12345678901", nil)))
+
+	filters, err = parse("status=Fechado,%20Aberto&from=2024-01-01&to=2024-01-31&bairro=Barra%20da%20Tijuca")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Aberto", "Fechado"}, filters.Statuses)
+	filter := filters.apply(maintenanceRequestFilter("12345678901", nil))
+	assert.Equal(t, bson.M{"$in": []string{"Aberto", "Fechado"}}, filter["status"])
+	assert.Equal(t, bson.M{"$gte": "2024-01-01", "$lt": "2024-02-01"}, filter["data_inicio"])
+	assert.Equal(t, primitive.Regex{Pattern: "Barra da Tijuca", Options: "i"}, filter["endereco"])
+
+	for _, query := range []string{"from=01/01/2024", "to=2024-13-01", "from=2024-02-01&to=2024-01-01"} {
+		_, err := parse(query)
+		assert.Error(t, err, "query %q", query)
+	}
+}
+
+func TestMaintenanceRequestFilters_CacheKeySuffix(t *testing.T) {
+	a := maintenanceRequestFilters{Statuses: []string{"Aberto"}, From: "2024-01-01"}
+	b := maintenanceRequestFilters{Statuses: []string{"Aberto"}, From: "2024-01-02"}
+
+	assert.NotEmpty(t, a.cacheKeySuffix())
+	assert.Equal(t, a.cacheKeySuffix(), a.cacheKeySuffix())
+	assert.NotEqual(t, a.cacheKeySuffix(), b.cacheKeySuffix())
+	assert.Equal(t,
+		maintenanceRequestFilters{Bairro: "Centro"}.cacheKeySuffix(),
+		maintenanceRequestFilters{Bairro: "centro"}.cacheKeySuffix())
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maintenanceRequestDateLayout is the format of the from/to maintenance request filters
+const maintenanceRequestDateLayout = "2006-01-02"
+
+// maintenanceRequestFilters narrows a citizen's maintenance requests. Zero values don't filter.
+type maintenanceRequestFilters struct {
+	Statuses []string // Exact status values, any of which matches
+	From     string   // First day (inclusive) of data_inicio, as YYYY-MM-DD
+	To       string   // Last day (inclusive) of data_inicio, as YYYY-MM-DD
+	Bairro   string   // Neighborhood contained in the pre-built address, case-insensitive
+}
+
+// parseMaintenanceRequestFilters reads the status, from, to and bairro query parameters
+func parseMaintenanceRequestFilters(c *gin.Context) (maintenanceRequestFilters, error) {
+	var filters maintenanceRequestFilters
+
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filters.Statuses = append(filters.Statuses, status)
+		}
+	}
+	sort.Strings(filters.Statuses)
+
+	var from, to time.Time
+	var err error
+	if filters.From = strings.TrimSpace(c.Query("from")); filters.From != "" {
+		if from, err = time.Parse(maintenanceRequestDateLayout, filters.From); err != nil {
+			return filters, fmt.Errorf("invalid from parameter (expected YYYY-MM-DD)")
+		}
+	}
+	if filters.To = strings.TrimSpace(c.Query("to")); filters.To != "" {
+		if to, err = time.Parse(maintenanceRequestDateLayout, filters.To); err != nil {
+			return filters, fmt.Errorf("invalid to parameter (expected YYYY-MM-DD)")
+		}
+	}
+	if filters.From != "" && filters.To != "" && to.Before(from) {
+		return filters, fmt.Errorf("from must not be after to")
+	}
+
+	filters.Bairro = strings.TrimSpace(c.Query("bairro"))
+	return filters, nil
+}
+
+// empty reports whether no filter was given
+func (f maintenanceRequestFilters) empty() bool {
+	return len(f.Statuses) == 0 && f.From == "" && f.To == "" && f.Bairro == ""
+}
+
+// cacheKeySuffix identifies the filter set in cache keys; unfiltered requests keep their
+// original keys
+func (f maintenanceRequestFilters) cacheKeySuffix() string {
+	if f.empty() {
+		return ""
+	}
+	canonical := fmt.Sprintf("status=%s|from=%s|to=%s|bairro=%s",
+		strings.Join(f.Statuses, ","), f.From, f.To, strings.ToLower(f.Bairro))
+	sum := sha256.Sum256([]byte(canonical))
+	return ":filter_" + hex.EncodeToString(sum[:8])
+}
+
+// apply adds the filters to a maintenance request query. data_inicio is stored as an ISO 8601
+// string, so the date range is a string range: from its first day up to the day after to.
+func (f maintenanceRequestFilters) apply(filter bson.M) bson.M {
+	if len(f.Statuses) == 1 {
+		filter["status"] = f.Statuses[0]
+	} else if len(f.Statuses) > 1 {
+		filter["status"] = bson.M{"$in": f.Statuses}
+	}
+
+	dateRange := bson.M{}
+	if f.From != "" {
+		dateRange["$gte"] = f.From
+	}
+	if f.To != "" {
+		to, _ := time.Parse(maintenanceRequestDateLayout, f.To)
+		dateRange["$lt"] = to.AddDate(0, 0, 1).Format(maintenanceRequestDateLayout)
+	}
+	if len(dateRange) > 0 {
+		filter["data_inicio"] = dateRange
+	}
+
+	if f.Bairro != "" {
+		filter["endereco"] = primitive.Regex{Pattern: regexp.QuoteMeta(f.Bairro), Options: "i"}
+	}
+	return filter
+}
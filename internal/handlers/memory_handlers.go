@@ -60,7 +60,7 @@ func getMemoryListData(ctx context.Context, phoneNumber string) ([]MemoryModel,
 
 	// Cache the result
 	if jsonData, err := json.Marshal(memories); err == nil {
-		config.Redis.Set(ctx, cacheKey, jsonData, config.AppConfig.RedisTTL)
+		config.Redis.Set(ctx, cacheKey, jsonData, config.CacheTTLFor(config.CacheNamespaceMemory))
 	}
 
 	return memories, nil
@@ -277,9 +277,9 @@ func GetMemoryByName(c *gin.Context) {
 	observability.DatabaseOperations.WithLabelValues("find", "success").Inc()
 
 	// Cache the result with tracing
-	ctx, cacheSetSpan := utils.TraceCacheSet(ctx, cacheKey, config.AppConfig.RedisTTL)
+	ctx, cacheSetSpan := utils.TraceCacheSet(ctx, cacheKey, config.CacheTTLFor(config.CacheNamespaceMemory))
 	if jsonData, err := json.Marshal(memory); err == nil {
-		config.Redis.Set(ctx, cacheKey, jsonData, config.AppConfig.RedisTTL)
+		config.Redis.Set(ctx, cacheKey, jsonData, config.CacheTTLFor(config.CacheNamespaceMemory))
 		utils.AddSpanAttribute(cacheSetSpan, "cache.set_success", true)
 	} else {
 		utils.RecordErrorInSpan(cacheSetSpan, err, map[string]interface{}{
@@ -669,11 +669,11 @@ func UpdateMemory(c *gin.Context) {
 
 	// Batch set operations
 	if len(keysToSet) > 0 {
-		if err := pipeline.BatchSet(keysToSet, config.AppConfig.RedisTTL); err != nil {
+		if err := pipeline.BatchSet(keysToSet, config.CacheTTLFor(config.CacheNamespaceMemory)); err != nil {
 			logger.Warn("failed to batch set cache keys", zap.Error(err))
 			// Fall back to individual sets
 			for key, value := range keysToSet {
-				config.Redis.Set(ctx, key, value, config.AppConfig.RedisTTL)
+				config.Redis.Set(ctx, key, value, config.CacheTTLFor(config.CacheNamespaceMemory))
 			}
 		}
 	}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetMergeRules godoc
+// @Summary Listar regras de mesclagem dos dados autodeclarados
+// @Description Retorna, por campo, as regras de precedência em vigor ao mesclar os dados autodeclarados sobre os dados base do cidadão: quando o valor autodeclarado substitui o valor base (condition), se o indicador autodeclarado precisa ser verdadeiro (requires_indicator, ex: telefone só após verificação) e como o indicador base é ajustado (indicator).
+// @Tags citizen
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.MergeRulesResponse "Regras de mesclagem obtidas com sucesso"
+// @Router /citizen/merge-rules [get]
+func GetMergeRules(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetMergeRules")
+	defer span.End()
+
+	logger := observability.Logger()
+
+	span.SetAttributes(
+		attribute.String("operation", "get_merge_rules"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("GetMergeRules called")
+
+	ctx, rulesSpan := utils.TraceBusinessLogic(ctx, "get_self_declared_merge_rules")
+	rules := models.SelfDeclaredMergeRules()
+	utils.AddSpanAttribute(rulesSpan, "rules.count", len(rules))
+	rulesSpan.End()
+
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, models.MergeRulesResponse{Rules: rules})
+	responseSpan.End()
+
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetMergeRules completed",
+		zap.Int("rules_count", len(rules)),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// mergeField binds a merge rule to the self-declared and citizen data of its field
+type mergeField struct {
+	// present reports whether the self-declared field is set (its principal, for address,
+	// email and phone)
+	present func(selfDeclared models.SelfDeclaredData) bool
+	// indicator returns the self-declared indicador, checked by rules that require it
+	indicator func(selfDeclared models.SelfDeclaredData) *bool
+	// apply copies the self-declared value into the citizen and returns the citizen's
+	// indicator, or nil for fields without one
+	apply func(citizen *models.Citizen, selfDeclared models.SelfDeclaredData) **bool
+}
+
+// selfDeclaredMergeFields maps each merge rule field to its data. A new self-declared field
+// needs an entry here and a rule in models.SelfDeclaredMergeRules.
+var selfDeclaredMergeFields = map[string]mergeField{
+	"endereco": {
+		present: func(sd models.SelfDeclaredData) bool { return sd.Endereco != nil && sd.Endereco.Principal != nil },
+		indicator: func(sd models.SelfDeclaredData) *bool {
+			if sd.Endereco == nil {
+				return nil
+			}
+			return sd.Endereco.Indicador
+		},
+		apply: func(citizen *models.Citizen, sd models.SelfDeclaredData) **bool {
+			if citizen.Endereco == nil {
+				citizen.Endereco = &models.Endereco{}
+			}
+			citizen.Endereco.Principal = sd.Endereco.Principal
+			return &citizen.Endereco.Indicador
+		},
+	},
+	"email": {
+		present: func(sd models.SelfDeclaredData) bool { return sd.Email != nil && sd.Email.Principal != nil },
+		indicator: func(sd models.SelfDeclaredData) *bool {
+			if sd.Email == nil {
+				return nil
+			}
+			return sd.Email.Indicador
+		},
+		apply: func(citizen *models.Citizen, sd models.SelfDeclaredData) **bool {
+			if citizen.Email == nil {
+				citizen.Email = &models.Email{}
+			}
+			citizen.Email.Principal = sd.Email.Principal
+			return &citizen.Email.Indicador
+		},
+	},
+	"telefone": {
+		present: func(sd models.SelfDeclaredData) bool { return sd.Telefone != nil && sd.Telefone.Principal != nil },
+		indicator: func(sd models.SelfDeclaredData) *bool {
+			if sd.Telefone == nil {
+				return nil
+			}
+			return sd.Telefone.Indicador
+		},
+		apply: func(citizen *models.Citizen, sd models.SelfDeclaredData) **bool {
+			if citizen.Telefone == nil {
+				citizen.Telefone = &models.Telefone{}
+			}
+			citizen.Telefone.Principal = sd.Telefone.Principal
+			citizen.Telefone.Verificados = sd.Telefone.SecondaryVerified()
+			return &citizen.Telefone.Indicador
+		},
+	},
+	"raca": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.Raca },
+		func(sd models.SelfDeclaredData) *string { return sd.Raca }),
+	"nome_exibicao": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.NomeExibicao },
+		func(sd models.SelfDeclaredData) *string { return sd.NomeExibicao }),
+	"genero": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.Genero },
+		func(sd models.SelfDeclaredData) *string { return sd.Genero }),
+	"renda_familiar": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.RendaFamiliar },
+		func(sd models.SelfDeclaredData) *string { return sd.RendaFamiliar }),
+	"escolaridade": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.Escolaridade },
+		func(sd models.SelfDeclaredData) *string { return sd.Escolaridade }),
+	"deficiencia": stringMergeField(
+		func(citizen *models.Citizen) **string { return &citizen.Deficiencia },
+		func(sd models.SelfDeclaredData) *string { return sd.Deficiencia }),
+}
+
+// stringMergeField builds the mergeField of a plain string field without indicator
+func stringMergeField(target func(citizen *models.Citizen) **string, value func(sd models.SelfDeclaredData) *string) mergeField {
+	return mergeField{
+		present: func(sd models.SelfDeclaredData) bool { return value(sd) != nil },
+		apply: func(citizen *models.Citizen, sd models.SelfDeclaredData) **bool {
+			*target(citizen) = value(sd)
+			return nil
+		},
+	}
+}
+
+// applyMergeRules overlays the self-declared fields whose rule applies, in rule order
+func applyMergeRules(citizen *models.Citizen, selfDeclared models.SelfDeclaredData, rules []models.MergeRule) {
+	for _, rule := range rules {
+		field, ok := selfDeclaredMergeFields[rule.Field]
+		if !ok || !mergeRuleApplies(rule, field, selfDeclared) {
+			continue
+		}
+
+		indicator := field.apply(citizen, selfDeclared)
+		if indicator == nil {
+			continue
+		}
+		switch rule.Indicator {
+		case models.MergeIndicatorSetIfMissing:
+			if *indicator == nil {
+				*indicator = utils.BoolPtr(true)
+			}
+		case models.MergeIndicatorSetTrue:
+			*indicator = utils.BoolPtr(true)
+		}
+	}
+}
+
+// mergeRuleApplies reports whether the self-declared value of the field overrides the base value
+func mergeRuleApplies(rule models.MergeRule, field mergeField, selfDeclared models.SelfDeclaredData) bool {
+	if rule.RequiresIndicator {
+		if field.indicator == nil {
+			return false
+		}
+		if verified := field.indicator(selfDeclared); verified == nil || !*verified {
+			return false
+		}
+	}
+
+	switch rule.Condition {
+	case models.MergeConditionAlways:
+		return true
+	case models.MergeConditionPrincipalPresent, models.MergeConditionValuePresent:
+		return field.present(selfDeclared)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfDeclaredMergeFields_CoverRules(t *testing.T) {
+	for _, rule := range models.SelfDeclaredMergeRules() {
+		_, ok := selfDeclaredMergeFields[rule.Field]
+		assert.True(t, ok, "merge rule %q has no merge field", rule.Field)
+	}
+}
+
+func TestApplyMergeRules_Address(t *testing.T) {
+	base := &models.EnderecoPrincipal{Logradouro: strPtr("Rua Base")}
+	selfDeclared := &models.EnderecoPrincipal{Logradouro: strPtr("Rua Autodeclarada")}
+
+	// Overrides the base principal and sets a missing indicator
+	citizen := &models.Citizen{Endereco: &models.Endereco{Principal: base}}
+	applyMergeRules(citizen, models.SelfDeclaredData{Endereco: &models.Endereco{Principal: selfDeclared}}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, selfDeclared, citizen.Endereco.Principal)
+	require.NotNil(t, citizen.Endereco.Indicador)
+	assert.True(t, *citizen.Endereco.Indicador)
+
+	// Keeps an existing base indicator
+	citizen = &models.Citizen{Endereco: &models.Endereco{Indicador: utils.BoolPtr(false), Principal: base}}
+	applyMergeRules(citizen, models.SelfDeclaredData{Endereco: &models.Endereco{Principal: selfDeclared}}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, selfDeclared, citizen.Endereco.Principal)
+	assert.False(t, *citizen.Endereco.Indicador)
+
+	// Without a self-declared principal the base address is kept
+	citizen = &models.Citizen{Endereco: &models.Endereco{Principal: base}}
+	applyMergeRules(citizen, models.SelfDeclaredData{Endereco: &models.Endereco{}}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, base, citizen.Endereco.Principal)
+}
+
+func TestApplyMergeRules_Email(t *testing.T) {
+	selfDeclared := &models.EmailPrincipal{Valor: strPtr("maria@example.com")}
+
+	citizen := &models.Citizen{}
+	applyMergeRules(citizen, models.SelfDeclaredData{Email: &models.Email{Principal: selfDeclared}}, models.SelfDeclaredMergeRules())
+	require.NotNil(t, citizen.Email)
+	assert.Equal(t, selfDeclared, citizen.Email.Principal)
+	assert.True(t, *citizen.Email.Indicador)
+
+	citizen = &models.Citizen{}
+	applyMergeRules(citizen, models.SelfDeclaredData{}, models.SelfDeclaredMergeRules())
+	assert.Nil(t, citizen.Email)
+}
+
+func TestApplyMergeRules_Phone(t *testing.T) {
+	base := &models.TelefonePrincipal{Valor: strPtr("911111111")}
+	selfDeclared := &models.TelefonePrincipal{Valor: strPtr("922222222")}
+
+	// Unverified self-declared phones never override the base phone
+	for _, indicador := range []*bool{nil, utils.BoolPtr(false)} {
+		citizen := &models.Citizen{Telefone: &models.Telefone{Indicador: utils.BoolPtr(false), Principal: base}}
+		applyMergeRules(citizen, models.SelfDeclaredData{Telefone: &models.Telefone{Indicador: indicador, Principal: selfDeclared}}, models.SelfDeclaredMergeRules())
+		assert.Equal(t, base, citizen.Telefone.Principal)
+		assert.False(t, *citizen.Telefone.Indicador)
+	}
+
+	// Verified self-declared phones override it and always set the indicator
+	citizen := &models.Citizen{Telefone: &models.Telefone{Indicador: utils.BoolPtr(false), Principal: base}}
+	applyMergeRules(citizen, models.SelfDeclaredData{Telefone: &models.Telefone{Indicador: utils.BoolPtr(true), Principal: selfDeclared}}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, selfDeclared, citizen.Telefone.Principal)
+	assert.True(t, *citizen.Telefone.Indicador)
+
+	// Only the verified phones other than the primary one are listed
+	secondary := models.TelefoneVerificado{ID: "5521911111111", Valor: strPtr("911111111")}
+	citizen = &models.Citizen{}
+	applyMergeRules(citizen, models.SelfDeclaredData{Telefone: &models.Telefone{
+		Indicador: utils.BoolPtr(true),
+		Principal: selfDeclared,
+		Verificados: []models.TelefoneVerificado{
+			{ID: "5521922222222", Valor: strPtr("922222222"), Principal: true},
+			secondary,
+		},
+	}}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, selfDeclared, citizen.Telefone.Principal)
+	assert.Equal(t, []models.TelefoneVerificado{secondary}, citizen.Telefone.Verificados)
+}
+
+func TestApplyMergeRules_Raca(t *testing.T) {
+	citizen := &models.Citizen{Raca: strPtr("branca")}
+	applyMergeRules(citizen, models.SelfDeclaredData{}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, "branca", *citizen.Raca)
+
+	applyMergeRules(citizen, models.SelfDeclaredData{Raca: strPtr("parda")}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, "parda", *citizen.Raca)
+}
+
+func TestApplyMergeRules_SelfDeclaredOnlyFields(t *testing.T) {
+	citizen := &models.Citizen{
+		NomeExibicao:  strPtr("stale"),
+		Genero:        strPtr("stale"),
+		RendaFamiliar: strPtr("stale"),
+		Escolaridade:  strPtr("stale"),
+		Deficiencia:   strPtr("stale"),
+	}
+
+	// Always taken from the self-declared data, even when unset
+	applyMergeRules(citizen, models.SelfDeclaredData{NomeExibicao: strPtr("Maria")}, models.SelfDeclaredMergeRules())
+	assert.Equal(t, "Maria", *citizen.NomeExibicao)
+	assert.Nil(t, citizen.Genero)
+	assert.Nil(t, citizen.RendaFamiliar)
+	assert.Nil(t, citizen.Escolaridade)
+	assert.Nil(t, citizen.Deficiencia)
+}
+
+func TestApplyMergeRules_RulesAreAdjustable(t *testing.T) {
+	// Requiring the indicator on the address makes unverified addresses keep the base value
+	rules := []models.MergeRule{{
+		Field:             "endereco",
+		Condition:         models.MergeConditionPrincipalPresent,
+		RequiresIndicator: true,
+		Indicator:         models.MergeIndicatorSetTrue,
+	}}
+	base := &models.EnderecoPrincipal{Logradouro: strPtr("Rua Base")}
+	selfDeclared := &models.EnderecoPrincipal{Logradouro: strPtr("Rua Autodeclarada")}
+
+	citizen := &models.Citizen{Endereco: &models.Endereco{Principal: base}}
+	applyMergeRules(citizen, models.SelfDeclaredData{Endereco: &models.Endereco{Principal: selfDeclared}}, rules)
+	assert.Equal(t, base, citizen.Endereco.Principal)
+
+	applyMergeRules(citizen, models.SelfDeclaredData{Endereco: &models.Endereco{Indicador: utils.BoolPtr(true), Principal: selfDeclared}}, rules)
+	assert.Equal(t, selfDeclared, citizen.Endereco.Principal)
+	assert.True(t, *citizen.Endereco.Indicador)
+}
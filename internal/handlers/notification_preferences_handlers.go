@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -143,7 +144,6 @@ func (h *NotificationPreferencesHandlers) GetCitizenPreferences(c *gin.Context)
 			return
 		}
 		userConfig.CategoryOptIns = categoryOptIns
-		userConfig.UpdatedAt = time.Now()
 
 		// Persist the initialization to avoid re-initialization on every read
 		cacheService := services.NewCacheService()
@@ -190,7 +190,7 @@ func (h *NotificationPreferencesHandlers) GetCitizenPreferences(c *gin.Context)
 // @Failure 400 {object} ErrorResponse "Invalid CPF format or request body"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Access denied"
-// @Failure 422 {object} ErrorResponse "Invalid category ID"
+// @Failure 422 {object} ErrorResponse "Invalid category ID or too many category opt-ins"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /citizen/{cpf}/notification-preferences [put]
 func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Context) {
@@ -242,19 +242,18 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Contex
 	}
 	inputSpan.End()
 
-	// Validate category IDs exist and are active
+	// Validate category IDs exist, are active and stay within the configured limit
 	if len(input.CategoryOptIns) > 0 {
 		ctx, validateSpan := utils.TraceBusinessLogic(ctx, "validate_categories")
-		for categoryID := range input.CategoryOptIns {
-			if err := h.categoryService.ValidateCategoryExists(ctx, categoryID); err != nil {
-				utils.RecordErrorInSpan(validateSpan, err, map[string]interface{}{
-					"category_id": categoryID,
-				})
-				validateSpan.End()
-				logger.Warn("invalid category ID", zap.String("category_id", categoryID), zap.Error(err))
-				c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: fmt.Sprintf("Invalid category: %s", categoryID)})
-				return
-			}
+		if err := h.categoryService.ValidateCategoryOptIns(ctx, input.CategoryOptIns); err != nil {
+			utils.RecordErrorInSpan(validateSpan, err, map[string]interface{}{
+				"category_count": len(input.CategoryOptIns),
+			})
+			validateSpan.End()
+			logger.Warn("invalid category opt-ins", zap.Int("category_count", len(input.CategoryOptIns)), zap.Error(err))
+			status, response := categoryOptInsErrorResponse(err)
+			c.JSON(status, response)
+			return
 		}
 		validateSpan.End()
 	}
@@ -294,7 +293,6 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Contex
 			OptIn:          true,
 			CategoryOptIns: defaultCategoryOptIns,
 			FirstLogin:     false,
-			UpdatedAt:      time.Now(),
 		}
 		initSpan.End()
 	}
@@ -315,6 +313,12 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Contex
 
 	// Update category opt-ins if provided
 	if len(input.CategoryOptIns) > 0 {
+		if err := services.CheckCategoryOptInsLimit(userConfig.CategoryOptIns, input.CategoryOptIns); err != nil {
+			logger.Warn("category opt-ins limit exceeded", zap.Error(err))
+			status, response := categoryOptInsErrorResponse(err)
+			c.JSON(status, response)
+			return
+		}
 		if userConfig.CategoryOptIns == nil {
 			userConfig.CategoryOptIns = make(map[string]bool)
 		}
@@ -323,8 +327,6 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Contex
 		}
 	}
 
-	userConfig.UpdatedAt = time.Now()
-
 	// Update via cache service with tracing
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_user_config_via_cache")
 	cacheService := services.NewCacheService()
@@ -399,7 +401,7 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenPreferences(c *gin.Contex
 // @Failure 400 {object} ErrorResponse "Invalid CPF format or request body"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Access denied"
-// @Failure 422 {object} ErrorResponse "Invalid category ID"
+// @Failure 422 {object} ErrorResponse "Invalid category ID or too many category opt-ins"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /citizen/{cpf}/notification-preferences/categories/{category_id} [patch]
 func (h *NotificationPreferencesHandlers) UpdateCitizenCategoryPreference(c *gin.Context) {
@@ -467,17 +469,22 @@ func (h *NotificationPreferencesHandlers) UpdateCitizenCategoryPreference(c *gin
 			OptIn:          true,
 			CategoryOptIns: defaultCategoryOptIns,
 			FirstLogin:     false,
-			UpdatedAt:      time.Now(),
 		}
 	}
 
+	if err := services.CheckCategoryOptInsLimit(userConfig.CategoryOptIns, fullUpdate.CategoryOptIns); err != nil {
+		logger.Warn("category opt-ins limit exceeded", zap.Error(err))
+		status, response := categoryOptInsErrorResponse(err)
+		c.JSON(status, response)
+		return
+	}
+
 	if userConfig.CategoryOptIns == nil {
 		userConfig.CategoryOptIns = make(map[string]bool)
 	}
 
 	oldValue := userConfig.CategoryOptIns[categoryID]
 	userConfig.CategoryOptIns[categoryID] = input.OptIn
-	userConfig.UpdatedAt = time.Now()
 
 	cacheService := services.NewCacheService()
 	err = cacheService.UpdateUserConfig(ctx, cpf, &userConfig)
@@ -633,7 +640,7 @@ func (h *NotificationPreferencesHandlers) GetPhonePreferences(c *gin.Context) {
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Admin access required"
 // @Failure 404 {object} ErrorResponse "Phone not found"
-// @Failure 422 {object} ErrorResponse "Invalid category ID"
+// @Failure 422 {object} ErrorResponse "Invalid category ID or too many category opt-ins"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /phone/{phone_number}/notification-preferences [put]
 func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context) {
@@ -673,14 +680,13 @@ func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context)
 		return
 	}
 
-	// Validate category IDs exist and are active
+	// Validate category IDs exist, are active and stay within the configured limit
 	if len(input.CategoryOptIns) > 0 {
-		for categoryID := range input.CategoryOptIns {
-			if err := h.categoryService.ValidateCategoryExists(ctx, categoryID); err != nil {
-				logger.Warn("invalid category ID", zap.String("category_id", categoryID), zap.Error(err))
-				c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: fmt.Sprintf("Invalid category: %s", categoryID)})
-				return
-			}
+		if err := h.categoryService.ValidateCategoryOptIns(ctx, input.CategoryOptIns); err != nil {
+			logger.Warn("invalid category opt-ins", zap.Int("category_count", len(input.CategoryOptIns)), zap.Error(err))
+			status, response := categoryOptInsErrorResponse(err)
+			c.JSON(status, response)
+			return
 		}
 	}
 
@@ -715,6 +721,12 @@ func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context)
 
 	// Update category opt-ins if provided
 	if len(input.CategoryOptIns) > 0 {
+		if err := services.CheckCategoryOptInsLimit(mapping.CategoryOptIns, input.CategoryOptIns); err != nil {
+			logger.Warn("category opt-ins limit exceeded", zap.Error(err))
+			status, response := categoryOptInsErrorResponse(err)
+			c.JSON(status, response)
+			return
+		}
 		if mapping.CategoryOptIns == nil {
 			mapping.CategoryOptIns = make(map[string]bool)
 		}
@@ -757,7 +769,6 @@ func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context)
 					OptIn:          mapping.OptIn,
 					CategoryOptIns: defaultCategoryOptIns,
 					FirstLogin:     false,
-					UpdatedAt:      now,
 				}
 			}
 		} else {
@@ -773,7 +784,6 @@ func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context)
 					userConfig.CategoryOptIns[categoryID] = opted
 				}
 			}
-			userConfig.UpdatedAt = now
 		}
 
 		// Update CPF config
@@ -837,7 +847,7 @@ func (h *NotificationPreferencesHandlers) UpdatePhonePreferences(c *gin.Context)
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Admin access required"
 // @Failure 404 {object} ErrorResponse "Phone not found"
-// @Failure 422 {object} ErrorResponse "Invalid category ID"
+// @Failure 422 {object} ErrorResponse "Invalid category ID or too many category opt-ins"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /phone/{phone_number}/notification-preferences/categories/{category_id} [patch]
 func (h *NotificationPreferencesHandlers) UpdatePhoneCategoryPreference(c *gin.Context) {
@@ -892,6 +902,13 @@ func (h *NotificationPreferencesHandlers) UpdatePhoneCategoryPreference(c *gin.C
 		return
 	}
 
+	if err := services.CheckCategoryOptInsLimit(mapping.CategoryOptIns, fullUpdate.CategoryOptIns); err != nil {
+		logger.Warn("category opt-ins limit exceeded", zap.Error(err))
+		status, response := categoryOptInsErrorResponse(err)
+		c.JSON(status, response)
+		return
+	}
+
 	if mapping.CategoryOptIns == nil {
 		mapping.CategoryOptIns = make(map[string]bool)
 	}
@@ -933,7 +950,6 @@ func (h *NotificationPreferencesHandlers) UpdatePhoneCategoryPreference(c *gin.C
 					OptIn:          mapping.OptIn,
 					CategoryOptIns: defaultCategoryOptIns,
 					FirstLogin:     false,
-					UpdatedAt:      now,
 				}
 			}
 		}
@@ -943,7 +959,6 @@ func (h *NotificationPreferencesHandlers) UpdatePhoneCategoryPreference(c *gin.C
 			userConfig.CategoryOptIns = make(map[string]bool)
 		}
 		userConfig.CategoryOptIns[categoryID] = input.OptIn
-		userConfig.UpdatedAt = now
 
 		// Persist the sync
 		cacheService := services.NewCacheService()
@@ -1024,3 +1039,16 @@ func (h *NotificationPreferencesHandlers) recordOptInHistoryWithPhone(ctx contex
 		// Don't fail the main operation for this error
 	}
 }
+
+// categoryOptInsErrorResponse maps category opt-in validation errors to an HTTP status and body
+func categoryOptInsErrorResponse(err error) (int, ErrorResponse) {
+	var invalidCategory *services.InvalidCategoryError
+	switch {
+	case errors.Is(err, services.ErrTooManyCategoryOptIns):
+		return http.StatusUnprocessableEntity, ErrorResponse{Error: fmt.Sprintf("Too many category opt-ins: maximum is %d", config.AppConfig.MaxCategoryOptIns)}
+	case errors.As(err, &invalidCategory):
+		return http.StatusUnprocessableEntity, ErrorResponse{Error: fmt.Sprintf("Invalid category: %s", invalidCategory.CategoryID)}
+	default:
+		return http.StatusInternalServerError, ErrorResponse{Error: "Failed to validate categories"}
+	}
+}
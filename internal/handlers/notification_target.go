@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetNotificationTarget godoc
+// @Summary Obter telefone para notificações do cidadão
+// @Description Indica para qual telefone as notificações do cidadão devem ser enviadas. Os telefones verificados são considerados na ordem configurada em NOTIFICATION_PHONE_FALLBACK (por padrão, o telefone autodeclarado confirmado por código e, em seguida, o telefone governamental da base), ignorando telefones em quarentena ou com opt-out. Cidadãos com opt-out de notificações não têm telefone de destino. O canal preferido do cidadão (PUT /citizen/{cpf}/preferred-channel), quando escolhido, é retornado em preferred_channel.
+// @Tags citizen
+// @Produce json
+// @Param cpf path string true "CPF do cidadão (11 dígitos)" minLength(11) maxLength(11)
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationTargetResponse "Telefone de destino resolvido (found=false com o motivo quando não há telefone)"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/notification-target [get]
+func GetNotificationTarget(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetNotificationTarget")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "get_notification_target"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("GetNotificationTarget called")
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Base and self-declared phones are kept apart, since each is a separate fallback source
+	ctx, getDataSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.CitizenCollection, "cpf")
+	citizen, err := getBaseCitizenData(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(getDataSpan, err, map[string]interface{}{
+			"operation": "getBaseCitizenData",
+			"cpf":       cpf,
+		})
+		getDataSpan.End()
+		logger.Error("failed to get citizen data", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+		return
+	}
+	selfDeclared, _ := getBatchedSelfDeclaredData(ctx, cpf)
+	getDataSpan.End()
+
+	// Opt-in lives in the user config
+	ctx, userConfigSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.UserConfigCollection, "cpf")
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+	var userConfig models.UserConfig
+	err = dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &userConfig)
+	if err != nil && err != services.ErrDocumentNotFound {
+		utils.RecordErrorInSpan(userConfigSpan, err, map[string]interface{}{
+			"operation": "dataManager.Read",
+			"cpf":       cpf,
+			"type":      "user_config",
+		})
+		userConfigSpan.End()
+		logger.Error("failed to get user config via DataManager", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get user config"})
+		return
+	}
+	// Citizens without a user config are opted in by default (see GetOptIn)
+	optIn := err != nil || userConfig.OptIn
+	utils.AddSpanAttribute(userConfigSpan, "user_config.opt_in", optIn)
+	userConfigSpan.End()
+
+	// Resolve the target phone with tracing
+	ctx, resolveSpan := utils.TraceBusinessLogic(ctx, "resolve_notification_target")
+	phoneMappingService := services.NewPhoneMappingService(observability.Logger())
+	response, err := phoneMappingService.ResolveNotificationTarget(ctx, cpf, selfDeclared.Telefone, citizen.Telefone, optIn)
+	if err != nil {
+		utils.RecordErrorInSpan(resolveSpan, err, map[string]interface{}{
+			"operation": "ResolveNotificationTarget",
+			"cpf":       cpf,
+		})
+		resolveSpan.End()
+		logger.Error("failed to resolve notification target", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+		return
+	}
+	if userConfig.PreferredChannel != nil && services.IsAvailableChannel(*userConfig.PreferredChannel) {
+		response.PreferredChannel = *userConfig.PreferredChannel
+	}
+	utils.AddSpanAttribute(resolveSpan, "notification_target.found", response.Found)
+	utils.AddSpanAttribute(resolveSpan, "notification_target.source", response.Source)
+	resolveSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("GetNotificationTarget completed",
+		zap.Bool("found", response.Found),
+		zap.String("source", response.Source),
+		zap.String("reason", response.Reason),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/middleware"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
@@ -137,6 +140,59 @@ func (h *PhoneHandlers) GetPhoneStatus(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// GetPhoneStatusDetails godoc
+// @Summary Obter status detalhado do telefone
+// @Description Obtém o status do telefone com motivo da quarentena, grupo beta, quantidade de CPFs vinculados e última ação de opt-in, para decisões de roteamento do chatbot. Requer token de serviço com o escopo phone:read:status. O resultado fica em cache por PHONE_STATUS_DETAILS_CACHE_TTL.
+// @Tags phone
+// @Produce json
+// @Param phone_number path string true "Número do telefone"
+// @Security BearerAuth
+// @Success 200 {object} models.PhoneStatusDetailsResponse "Status detalhado do telefone obtido com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de telefone inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Token de serviço sem o escopo phone:read:status"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /service/phone/{phone_number}/status [get]
+func (h *PhoneHandlers) GetPhoneStatusDetails(c *gin.Context) {
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetPhoneStatusDetails")
+	defer span.End()
+
+	phoneNumber := c.Param("phone_number")
+	span.SetAttributes(
+		attribute.String("phone_number", phoneNumber),
+		attribute.String("operation", "get_phone_status_details"),
+		attribute.String("service", "phone"),
+	)
+
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "get_phone_status_details")
+	response, err := h.phoneMappingService.GetPhoneStatusDetails(ctx, phoneNumber)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "get_phone_status_details",
+		})
+		serviceSpan.End()
+
+		if isPhoneParsingError(err) {
+			h.logger.Warn("invalid phone number format", zap.Error(err), zap.String("phone_number", phoneNumber))
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Formato de número de telefone inválido"})
+			return
+		}
+
+		h.logger.Error("failed to get phone status details", zap.Error(err), zap.String("phone_number", phoneNumber))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.found", response.Found)
+	utils.AddSpanAttribute(serviceSpan, "response.quarantined", response.Quarantined)
+	utils.AddSpanAttribute(serviceSpan, "response.bound_cpf_count", response.BoundCPFCount)
+	serviceSpan.End()
+
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+}
+
 // GetCitizenByPhone godoc
 // @Summary Obter cidadão por telefone
 // @Description Obtém informações do cidadão associado a um número de telefone
@@ -853,6 +909,126 @@ func (h *PhoneHandlers) QuarantinePhone(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// BulkQuarantinePhones godoc
+// @Summary Colocar telefones em quarentena em lote
+// @Description Coloca múltiplos números de telefone em quarentena com um motivo e uma duração compartilhados (apenas administradores). A duração padrão é PHONE_QUARANTINE_TTL. Retorna o resultado por número; a operação é registrada em um único evento de auditoria com a lista completa.
+// @Tags phone
+// @Accept json
+// @Produce json
+// @Param data body models.BulkQuarantineRequest true "Telefones, motivo e duração da quarentena"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkQuarantineResponse "Resultado da quarentena por telefone"
+// @Failure 400 {object} ErrorResponse "Dados inválidos, lista de telefones vazia ou motivo ausente"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/phone/quarantine/bulk [post]
+func (h *PhoneHandlers) BulkQuarantinePhones(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "BulkQuarantinePhones")
+	defer span.End()
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "bulk_quarantine_phones"),
+		attribute.String("service", "phone"),
+	)
+
+	h.logger.Debug("BulkQuarantinePhones called")
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "bulk_quarantine_request")
+	var req models.BulkQuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "BulkQuarantineRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Dados inválidos: " + err.Error()})
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Motivo da quarentena é obrigatório"})
+		return
+	}
+	duration := config.AppConfig.PhoneQuarantineTTL
+	if req.DurationDays > 0 {
+		duration = time.Duration(req.DurationDays) * 24 * time.Hour
+	}
+	utils.AddSpanAttribute(inputSpan, "input.phone_count", len(req.PhoneNumbers))
+	utils.AddSpanAttribute(inputSpan, "input.reason", req.Reason)
+	utils.AddSpanAttribute(inputSpan, "input.duration", duration.String())
+	inputSpan.End()
+
+	// Bulk quarantine with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "bulk_quarantine_phones")
+	response, err := h.phoneMappingService.BulkQuarantinePhones(ctx, req.PhoneNumbers, req.Reason, duration)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "bulk_quarantine_phones",
+		})
+		serviceSpan.End()
+		h.logger.Error("failed to bulk quarantine phones", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.quarantined", response.Quarantined)
+	utils.AddSpanAttribute(serviceSpan, "response.failed", response.Failed)
+	serviceSpan.End()
+
+	// Log audit event once for the whole list with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "create", "phone_quarantine")
+	adminCPF, _ := middleware.ExtractCPFFromToken(c)
+	auditCtx := utils.AuditContext{
+		UserID:    adminCPF,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogPhoneBulkQuarantine(ctx, auditCtx, req.PhoneNumbers, req.Reason, response.QuarantineUntil); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "create",
+			"audit.resource": "phone_quarantine",
+		})
+		h.logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("BulkQuarantinePhones completed",
+		zap.Int("phone_count", len(req.PhoneNumbers)),
+		zap.Int("quarantined", response.Quarantined),
+		zap.Int("failed", response.Failed),
+		zap.String("reason", req.Reason),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
 // ReleaseQuarantine godoc
 // @Summary Liberar telefone da quarentena
 // @Description Libera um número de telefone da quarentena (apenas administradores)
@@ -945,14 +1121,19 @@ func (h *PhoneHandlers) ReleaseQuarantine(c *gin.Context) {
 
 // GetQuarantinedPhones godoc
 // @Summary Listar telefones em quarentena
-// @Description Lista todos os telefones em quarentena com paginação (apenas administradores)
+// @Description Lista os telefones em quarentena com paginação e filtros por motivo e período de início da quarentena (apenas administradores). Com format=csv, exporta todos os telefones filtrados em um arquivo CSV, ignorando a paginação; os CPFs são mascarados, exceto para tokens com um scope de QUARANTINE_EXPORT_UNMASKED_SCOPES.
 // @Tags phone
 // @Produce json
+// @Produce text/csv
 // @Param page query int false "Página (padrão: 1)"
 // @Param per_page query int false "Itens por página (padrão: 10)"
+// @Param reason query string false "Filtrar pelo motivo da quarentena"
+// @Param quarantined_after query string false "Quarentenas iniciadas a partir deste instante, inclusivo (RFC3339)"
+// @Param quarantined_before query string false "Quarentenas iniciadas antes deste instante, exclusivo (RFC3339)"
+// @Param format query string false "Formato da resposta (padrão: json)" Enums(json, csv)
 // @Security BearerAuth
-// @Success 200 {object} models.QuarantinedListResponse "Lista de telefones em quarentena obtida com sucesso"
-// @Failure 400 {object} ErrorResponse "Parâmetros de paginação inválidos"
+// @Success 200 {object} models.QuarantinedListResponse "Lista de telefones em quarentena obtida com sucesso (ou arquivo CSV com format=csv)"
+// @Failure 400 {object} ErrorResponse "Parâmetros de filtro ou formato inválidos"
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores podem listar telefones em quarentena"
 // @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
@@ -963,13 +1144,16 @@ func (h *PhoneHandlers) GetQuarantinedPhones(c *gin.Context) {
 	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetQuarantinedPhones")
 	defer span.End()
 
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+
 	// Add operation to span attributes
 	span.SetAttributes(
 		attribute.String("operation", "get_quarantined_phones"),
 		attribute.String("service", "phone"),
+		attribute.String("format", format),
 	)
 
-	h.logger.Debug("GetQuarantinedPhones called")
+	h.logger.Debug("GetQuarantinedPhones called", zap.String("format", format))
 
 	// Check admin access with tracing
 	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
@@ -985,6 +1169,31 @@ func (h *PhoneHandlers) GetQuarantinedPhones(c *gin.Context) {
 	}
 	adminSpan.End()
 
+	// Parse filters with tracing
+	ctx, filterSpan := utils.TraceInputParsing(ctx, "quarantined_phones_filter")
+	if format != "json" && format != "csv" {
+		utils.RecordErrorInSpan(filterSpan, fmt.Errorf("unsupported format"), map[string]interface{}{
+			"format": format,
+		})
+		filterSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported format. Supported formats: json, csv"})
+		return
+	}
+	filter, err := parseQuarantinedPhonesFilter(c)
+	if err != nil {
+		utils.RecordErrorInSpan(filterSpan, err, nil)
+		filterSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(filterSpan, "reason", filter.Reason)
+	filterSpan.End()
+
+	if format == "csv" {
+		h.exportQuarantinedPhones(ctx, c, filter, startTime)
+		return
+	}
+
 	// Parse pagination parameters with tracing
 	ctx, paginationSpan := utils.TraceInputParsing(ctx, "pagination_parameters")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -1002,7 +1211,7 @@ func (h *PhoneHandlers) GetQuarantinedPhones(c *gin.Context) {
 
 	// Get quarantined phones with tracing
 	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "get_quarantined_phones")
-	response, err := h.phoneMappingService.GetQuarantinedPhones(ctx, page, perPage, false) // false = not expired
+	response, err := h.phoneMappingService.GetQuarantinedPhones(ctx, filter, page, perPage, false) // false = not expired
 	if err != nil {
 		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
 			"service.name":      "phone_mapping_service",
@@ -1032,9 +1241,73 @@ func (h *PhoneHandlers) GetQuarantinedPhones(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// exportQuarantinedPhones streams the quarantined phones matching the filter as a CSV download.
+// CPFs stay masked unless the token carries one of QUARANTINE_EXPORT_UNMASKED_SCOPES.
+func (h *PhoneHandlers) exportQuarantinedPhones(ctx context.Context, c *gin.Context, filter services.QuarantinedPhonesFilter, startTime time.Time) {
+	maskCPF := !middleware.HasAnyScope(c, config.AppConfig.QuarantineExportUnmaskedScopes)
+
+	// Stream the CSV download with tracing
+	ctx, exportSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "export_quarantined_phones")
+	utils.AddSpanAttribute(exportSpan, "mask_cpf", maskCPF)
+	filename := fmt.Sprintf("telefones_quarentena_%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	exported, err := h.phoneMappingService.ExportQuarantinedPhonesCSV(ctx, c.Writer, filter, false, maskCPF) // false = not expired
+	if err != nil {
+		// The status line is already sent, so the download ends truncated
+		utils.RecordErrorInSpan(exportSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "export_quarantined_phones",
+			"exported":          exported,
+		})
+		exportSpan.End()
+		h.logger.Error("failed to export quarantined phones", zap.Error(err), zap.Int("exported", exported))
+		return
+	}
+	utils.AddSpanAttribute(exportSpan, "exported", exported)
+	exportSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("GetQuarantinedPhones completed",
+		zap.String("format", "csv"),
+		zap.Bool("mask_cpf", maskCPF),
+		zap.Int("exported", exported),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// parseQuarantinedPhonesFilter reads the reason and quarantine period filters of the listing
+func parseQuarantinedPhonesFilter(c *gin.Context) (services.QuarantinedPhonesFilter, error) {
+	filter := services.QuarantinedPhonesFilter{
+		Reason: strings.TrimSpace(c.Query("reason")),
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"quarantined_after", &filter.QuarantinedAfter}, {"quarantined_before", &filter.QuarantinedBefore}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: must be an RFC3339 timestamp", bound.name)
+		}
+		*bound.target = &parsed
+	}
+
+	if filter.QuarantinedAfter != nil && filter.QuarantinedBefore != nil && !filter.QuarantinedAfter.Before(*filter.QuarantinedBefore) {
+		return filter, fmt.Errorf("quarantined_after must be before quarantined_before")
+	}
+	return filter, nil
+}
+
 // GetQuarantineStats godoc
 // @Summary Obter estatísticas de quarentena
-// @Description Obtém estatísticas sobre telefones em quarentena (apenas administradores)
+// @Description Obtém estatísticas sobre telefones em quarentena, incluindo quarentenas ativas por motivo (active_by_reason) (apenas administradores)
 // @Tags phone
 // @Produce json
 // @Security BearerAuth
@@ -1104,12 +1377,580 @@ func (h *PhoneHandlers) GetQuarantineStats(c *gin.Context) {
 		zap.String("status", "success"))
 }
 
+// ReconcilePhoneMapping godoc
+// @Summary Reconciliar cache do mapeamento de telefone
+// @Description Compara o mapeamento do telefone no MongoDB com o estado em cache no Redis (cache de leitura e status beta) e retorna as divergências. Com repair=true, atualiza o cache de leitura a partir do MongoDB e remove o status beta divergente. O buffer de escrita é apenas informado (apenas administradores)
+// @Tags phone
+// @Produce json
+// @Param phone_number path string true "Número de telefone"
+// @Param repair query bool false "Corrigir as divergências encontradas (padrão: false)"
+// @Security BearerAuth
+// @Success 200 {object} models.PhoneReconcileResponse "Reconciliação executada com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de número de telefone inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores podem reconciliar mapeamentos"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/phone/{phone_number}/reconcile [get]
+func (h *PhoneHandlers) ReconcilePhoneMapping(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ReconcilePhoneMapping")
+	defer span.End()
+
+	phoneNumber := c.Param("phone_number")
+	repair := c.Query("repair") == "true"
+
+	// Add phone number to span attributes
+	span.SetAttributes(
+		attribute.String("phone_number", phoneNumber),
+		attribute.Bool("repair", repair),
+		attribute.String("operation", "reconcile_phone_mapping"),
+		attribute.String("service", "phone"),
+	)
+
+	h.logger.Debug("ReconcilePhoneMapping called", zap.String("phone_number", phoneNumber), zap.Bool("repair", repair))
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	// Reconcile phone mapping with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "reconcile_phone_mapping")
+	response, err := h.phoneMappingService.ReconcilePhoneMapping(ctx, phoneNumber, repair)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "reconcile_phone_mapping",
+		})
+		serviceSpan.End()
+
+		if isPhoneParsingError(err) {
+			h.logger.Warn("invalid phone number format", zap.Error(err), zap.String("phone_number", phoneNumber))
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Formato de número de telefone inválido"})
+			return
+		}
+
+		h.logger.Error("failed to reconcile phone mapping", zap.Error(err), zap.String("phone_number", phoneNumber))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.consistent", response.Consistent)
+	utils.AddSpanAttribute(serviceSpan, "response.discrepancies", len(response.Discrepancies))
+	utils.AddSpanAttribute(serviceSpan, "response.repaired", response.Repaired)
+	serviceSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("ReconcilePhoneMapping completed",
+		zap.String("phone_number", phoneNumber),
+		zap.Bool("consistent", response.Consistent),
+		zap.Int("discrepancies", len(response.Discrepancies)),
+		zap.Bool("repaired", response.Repaired),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// ReassignPhone godoc
+// @Summary Reatribuir telefone a outro CPF
+// @Description Transfere um número de telefone para outro CPF, por exemplo quando o número foi reciclado pela operadora. Os mapeamentos ativos de outros CPFs são desvinculados e perdem o opt-in, o novo CPF recebe um mapeamento ativo sem opt-in e o histórico de opt-in e a auditoria registram a mudança para todos os CPFs envolvidos (apenas administradores)
+// @Tags phone
+// @Accept json
+// @Produce json
+// @Param phone_number path string true "Número de telefone"
+// @Param data body models.PhoneReassignRequest true "Novo CPF e motivo da reatribuição"
+// @Security BearerAuth
+// @Success 200 {object} models.PhoneReassignResponse "Telefone reatribuído com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de número de telefone ou CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores podem reatribuir telefones"
+// @Failure 409 {object} ErrorResponse "Telefone já atribuído a este CPF"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/phone/{phone_number}/reassign [post]
+func (h *PhoneHandlers) ReassignPhone(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "ReassignPhone")
+	defer span.End()
+
+	phoneNumber := c.Param("phone_number")
+
+	// Add phone number to span attributes
+	span.SetAttributes(
+		attribute.String("phone_number", phoneNumber),
+		attribute.String("operation", "reassign_phone"),
+		attribute.String("service", "phone"),
+	)
+
+	h.logger.Debug("ReassignPhone called", zap.String("phone_number", phoneNumber))
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "phone_reassign_request")
+	var req models.PhoneReassignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "PhoneReassignRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Dados inválidos: " + err.Error()})
+		return
+	}
+	req.CPF = strings.TrimSpace(req.CPF)
+	req.Reason = strings.TrimSpace(req.Reason)
+	if !utils.ValidateCPF(req.CPF) {
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "CPF inválido"})
+		return
+	}
+	utils.AddSpanAttribute(inputSpan, "input.reason", req.Reason)
+	inputSpan.End()
+
+	// Reassign phone with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "reassign_phone")
+	reassignment, err := h.phoneMappingService.ReassignPhone(ctx, phoneNumber, req.CPF, req.Reason)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "reassign_phone",
+		})
+		serviceSpan.End()
+
+		if isPhoneParsingError(err) {
+			h.logger.Warn("invalid phone number format", zap.Error(err), zap.String("phone_number", phoneNumber))
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Formato de número de telefone inválido"})
+			return
+		}
+		if errors.Is(err, services.ErrPhoneAlreadyAssigned) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Telefone já atribuído a este CPF"})
+			return
+		}
+
+		h.logger.Error("failed to reassign phone", zap.Error(err), zap.String("phone_number", phoneNumber))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	previousCPFs := reassignment.PreviousCPFs()
+	utils.AddSpanAttribute(serviceSpan, "response.previous_cpfs", len(previousCPFs))
+	serviceSpan.End()
+
+	// Log audit events for every CPF involved with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "phone_mapping")
+	adminCPF, _ := middleware.ExtractCPFFromToken(c)
+	auditCtx := utils.AuditContext{
+		UserID:    adminCPF,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogPhoneReassignment(ctx, auditCtx, reassignment.PhoneNumber, previousCPFs, req.CPF, req.Reason); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "update",
+			"audit.resource": "phone_mapping",
+		})
+		h.logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, models.PhoneReassignResponse{
+		Status:       "reassigned",
+		PhoneNumber:  phoneNumber,
+		CPF:          req.CPF,
+		PreviousCPFs: previousCPFs,
+		Message:      "Phone number reassigned to CPF without opt-in",
+	})
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("ReassignPhone completed",
+		zap.String("phone_number", phoneNumber),
+		zap.Int("previous_cpfs", len(previousCPFs)),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// RecordDeliveryReceipt godoc
+// @Summary Registrar recibo de entrega de mensagem
+// @Description Registra o recibo de entrega de uma mensagem enviada ao telefone, informado pelo provedor. Uma entrega bem-sucedida zera o contador de falhas; após PHONE_DELIVERY_FAILURE_THRESHOLD falhas dentro de PHONE_DELIVERY_FAILURE_WINDOW, o telefone autodeclarado verificado do CPF vinculado volta a pendente de verificação e o cidadão precisa confirmá-lo novamente com um código. O rebaixamento é registrado na auditoria e no histórico de opt-in (apenas administradores)
+// @Tags phone
+// @Accept json
+// @Produce json
+// @Param phone_number path string true "Número de telefone"
+// @Param data body models.PhoneDeliveryReceiptRequest true "Recibo de entrega"
+// @Security BearerAuth
+// @Success 200 {object} models.PhoneDeliveryReceiptResponse "Recibo registrado com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de número de telefone ou dados de entrada inválidos"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores podem registrar recibos"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/phone/{phone_number}/delivery-receipt [post]
+func (h *PhoneHandlers) RecordDeliveryReceipt(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "RecordDeliveryReceipt")
+	defer span.End()
+
+	phoneNumber := c.Param("phone_number")
+
+	// Add phone number to span attributes
+	span.SetAttributes(
+		attribute.String("phone_number", phoneNumber),
+		attribute.String("operation", "record_delivery_receipt"),
+		attribute.String("service", "phone"),
+	)
+
+	h.logger.Debug("RecordDeliveryReceipt called", zap.String("phone_number", phoneNumber))
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "delivery_receipt_request")
+	var req models.PhoneDeliveryReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "PhoneDeliveryReceiptRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Dados inválidos: " + err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(inputSpan, "input.status", req.Status)
+	utils.AddSpanAttribute(inputSpan, "input.error", req.Error)
+	inputSpan.End()
+
+	// Record delivery receipt with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "record_delivery_receipt")
+	response, err := h.phoneMappingService.RecordDeliveryReceipt(ctx, phoneNumber, req.Status)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "record_delivery_receipt",
+		})
+		serviceSpan.End()
+
+		if isPhoneParsingError(err) {
+			h.logger.Warn("invalid phone number format", zap.Error(err), zap.String("phone_number", phoneNumber))
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Formato de número de telefone inválido"})
+			return
+		}
+
+		h.logger.Error("failed to record delivery receipt", zap.Error(err), zap.String("phone_number", phoneNumber))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.failures", response.Failures)
+	utils.AddSpanAttribute(serviceSpan, "response.demoted", response.Demoted)
+	serviceSpan.End()
+
+	// Log audit event for demotions with tracing
+	if response.Demoted {
+		ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "phone_verification")
+		adminCPF, _ := middleware.ExtractCPFFromToken(c)
+		auditCtx := utils.AuditContext{
+			CPF:       response.CPF,
+			UserID:    adminCPF,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: c.GetString("RequestID"),
+		}
+		if err := utils.LogPhoneDemotion(ctx, auditCtx, response.PhoneNumber, response.Failures); err != nil {
+			utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+				"audit.action":   "update",
+				"audit.resource": "phone_verification",
+			})
+			h.logger.Warn("failed to log audit event", zap.Error(err))
+		}
+		auditSpan.End()
+	}
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("RecordDeliveryReceipt completed",
+		zap.String("phone_number", phoneNumber),
+		zap.String("delivery_status", req.Status),
+		zap.String("delivery_error", req.Error),
+		zap.Int("failures", response.Failures),
+		zap.Bool("demoted", response.Demoted),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// GetOptInHistory godoc
+// @Summary Consultar histórico de opt-in
+// @Description Retorna o histórico cronológico de ações de opt-in (opt-in, opt-out, cadastro rejeitado, atualização de categoria e telefone rebaixado) de um CPF e/ou telefone, para auditorias de conformidade (apenas administradores). É obrigatório informar cpf ou phone. Com format=csv, exporta todas as ações filtradas em um arquivo CSV, ignorando a paginação. Cada consulta é registrada na auditoria.
+// @Tags phone
+// @Produce json
+// @Produce text/csv
+// @Param cpf query string false "CPF do cidadão (obrigatório se phone não for informado)"
+// @Param phone query string false "Número de telefone (obrigatório se cpf não for informado)"
+// @Param from query string false "Ações a partir deste instante, inclusivo (RFC3339)"
+// @Param to query string false "Ações antes deste instante, exclusivo (RFC3339)"
+// @Param page query int false "Página (padrão: 1)"
+// @Param per_page query int false "Itens por página (padrão: 20, máximo: 100)"
+// @Param format query string false "Formato da resposta (padrão: json)" Enums(json, csv)
+// @Security BearerAuth
+// @Success 200 {object} models.OptInHistoryListResponse "Histórico de opt-in obtido com sucesso (ou arquivo CSV com format=csv)"
+// @Failure 400 {object} ErrorResponse "Parâmetros de filtro ou formato inválidos"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - somente administradores podem consultar o histórico de opt-in"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /admin/optin/history [get]
+func (h *PhoneHandlers) GetOptInHistory(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetOptInHistory")
+	defer span.End()
+
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+
+	// Add operation to span attributes
+	span.SetAttributes(
+		attribute.String("operation", "get_opt_in_history"),
+		attribute.String("service", "phone"),
+		attribute.String("format", format),
+	)
+
+	h.logger.Debug("GetOptInHistory called", zap.String("format", format))
+
+	// Check admin access with tracing
+	ctx, adminSpan := utils.TraceBusinessLogic(ctx, "admin_access_check")
+	isAdmin, err := middleware.IsAdmin(c)
+	if err != nil || !isAdmin {
+		utils.RecordErrorInSpan(adminSpan, fmt.Errorf("access denied"), map[string]interface{}{
+			"is_admin": isAdmin,
+			"error":    err,
+		})
+		adminSpan.End()
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Acesso negado - apenas administradores"})
+		return
+	}
+	adminSpan.End()
+
+	// Parse filters with tracing
+	ctx, filterSpan := utils.TraceInputParsing(ctx, "opt_in_history_filter")
+	if format != "json" && format != "csv" {
+		utils.RecordErrorInSpan(filterSpan, fmt.Errorf("unsupported format"), map[string]interface{}{
+			"format": format,
+		})
+		filterSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported format. Supported formats: json, csv"})
+		return
+	}
+	filter, err := parseOptInHistoryFilter(c)
+	if err != nil {
+		utils.RecordErrorInSpan(filterSpan, err, nil)
+		filterSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(filterSpan, "has_cpf", filter.CPF != "")
+	utils.AddSpanAttribute(filterSpan, "has_phone", filter.PhoneNumber != "")
+	filterSpan.End()
+
+	// Audit the query before running it, so failed or truncated exports are recorded too
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "read", "opt_in_history")
+	adminCPF, _ := middleware.ExtractCPFFromToken(c)
+	auditCtx := utils.AuditContext{
+		CPF:       filter.CPF,
+		UserID:    adminCPF,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogOptInHistoryAccess(ctx, auditCtx, filter.CPF, filter.PhoneNumber, format); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "read",
+			"audit.resource": "opt_in_history",
+		})
+		h.logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	if format == "csv" {
+		h.exportOptInHistory(ctx, c, filter, startTime)
+		return
+	}
+
+	// Parse pagination parameters with tracing
+	ctx, paginationSpan := utils.TraceInputParsing(ctx, "pagination_parameters")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	utils.AddSpanAttribute(paginationSpan, "page", page)
+	utils.AddSpanAttribute(paginationSpan, "per_page", perPage)
+	paginationSpan.End()
+
+	// Get opt-in history with tracing
+	ctx, serviceSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "get_opt_in_history")
+	response, err := h.phoneMappingService.GetOptInHistory(ctx, filter, page, perPage)
+	if err != nil {
+		utils.RecordErrorInSpan(serviceSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "get_opt_in_history",
+		})
+		serviceSpan.End()
+		h.logger.Error("failed to get opt-in history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erro interno do servidor"})
+		return
+	}
+	utils.AddSpanAttribute(serviceSpan, "response.total_count", response.Pagination.Total)
+	utils.AddSpanAttribute(serviceSpan, "response.entries_count", len(response.Data))
+	serviceSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("GetOptInHistory completed",
+		zap.Int("page", page),
+		zap.Int("per_page", perPage),
+		zap.Int("total_count", response.Pagination.Total),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// exportOptInHistory streams the opt-in history matching the filter as a CSV download
+func (h *PhoneHandlers) exportOptInHistory(ctx context.Context, c *gin.Context, filter services.OptInHistoryFilter, startTime time.Time) {
+	// Stream the CSV download with tracing
+	ctx, exportSpan := utils.TraceExternalService(ctx, "phone_mapping_service", "export_opt_in_history")
+	filename := fmt.Sprintf("historico_optin_%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	exported, err := h.phoneMappingService.ExportOptInHistoryCSV(ctx, c.Writer, filter)
+	if err != nil {
+		// The status line is already sent, so the download ends truncated
+		utils.RecordErrorInSpan(exportSpan, err, map[string]interface{}{
+			"service.name":      "phone_mapping_service",
+			"service.operation": "export_opt_in_history",
+			"exported":          exported,
+		})
+		exportSpan.End()
+		h.logger.Error("failed to export opt-in history", zap.Error(err), zap.Int("exported", exported))
+		return
+	}
+	utils.AddSpanAttribute(exportSpan, "exported", exported)
+	exportSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	h.logger.Debug("GetOptInHistory completed",
+		zap.String("format", "csv"),
+		zap.Int("exported", exported),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// parseOptInHistoryFilter reads the CPF, phone and period filters of the opt-in history query.
+// At least one of cpf and phone is required, so the query never scans every citizen's history.
+func parseOptInHistoryFilter(c *gin.Context) (services.OptInHistoryFilter, error) {
+	var filter services.OptInHistoryFilter
+
+	if cpf := strings.TrimSpace(c.Query("cpf")); cpf != "" {
+		if !utils.ValidateCPF(cpf) {
+			return filter, fmt.Errorf("invalid cpf")
+		}
+		filter.CPF = cpf
+	}
+
+	if phone := strings.TrimSpace(c.Query("phone")); phone != "" {
+		components, err := utils.ParsePhoneNumber(phone)
+		if err != nil {
+			return filter, fmt.Errorf("invalid phone: %w", err)
+		}
+		filter.PhoneNumber = utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+	}
+
+	if filter.CPF == "" && filter.PhoneNumber == "" {
+		return filter, fmt.Errorf("cpf or phone is required")
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: must be an RFC3339 timestamp", bound.name)
+		}
+		*bound.target = &parsed
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
+
 // GetAvailableChannels godoc
 // @Summary Obter canais disponíveis
-// @Description Obtém a lista de canais disponíveis para comunicação
+// @Description Obtém a lista de canais disponíveis para comunicação. A lista é mantida em memória; o header Last-Modified informa a última atualização.
 // @Tags config
 // @Produce json
 // @Success 200 {array} string
+// @Header 200 {string} Last-Modified "Horário da última atualização da lista em memória"
 // @Router /config/channels [get]
 func (h *PhoneHandlers) GetAvailableChannels(c *gin.Context) {
 	startTime := time.Now()
@@ -1132,6 +1973,7 @@ func (h *PhoneHandlers) GetAvailableChannels(c *gin.Context) {
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setStaticListLastModified(c, h.configService.LastRefresh(services.StaticListChannels))
 	c.JSON(http.StatusOK, channels)
 	responseSpan.End()
 
@@ -1145,10 +1987,11 @@ func (h *PhoneHandlers) GetAvailableChannels(c *gin.Context) {
 
 // GetOptOutReasons godoc
 // @Summary Obter motivos de opt-out
-// @Description Obtém a lista de motivos válidos para opt-out
+// @Description Obtém a lista de motivos válidos para opt-out. A lista é mantida em memória; o header Last-Modified informa a última atualização.
 // @Tags config
 // @Produce json
 // @Success 200 {array} string
+// @Header 200 {string} Last-Modified "Horário da última atualização da lista em memória"
 // @Router /config/opt-out-reasons [get]
 func (h *PhoneHandlers) GetOptOutReasons(c *gin.Context) {
 	startTime := time.Now()
@@ -1171,6 +2014,7 @@ func (h *PhoneHandlers) GetOptOutReasons(c *gin.Context) {
 
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	setStaticListLastModified(c, h.configService.LastRefresh(services.StaticListOptOutReasons))
 	c.JSON(http.StatusOK, reasons)
 	responseSpan.End()
 
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,6 +83,9 @@ func setupPhoneHandlersTest(t *testing.T) (*PhoneHandlers, *gin.Engine, func())
 		admin.DELETE("/phone/:phone_number/quarantine", handlers.ReleaseQuarantine)
 		admin.GET("/admin/phone/quarantined", handlers.GetQuarantinedPhones)
 		admin.GET("/admin/phone/quarantine/stats", handlers.GetQuarantineStats)
+		admin.POST("/admin/phone/quarantine/bulk", handlers.BulkQuarantinePhones)
+		admin.POST("/admin/phone/:phone_number/delivery-receipt", handlers.RecordDeliveryReceipt)
+		admin.GET("/admin/optin/history", handlers.GetOptInHistory)
 	}
 
 	// Config routes
@@ -531,6 +535,82 @@ func TestQuarantinePhone_EmptyPhoneNumber(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// TestBulkQuarantinePhones_Success tests quarantining several phones at once (admin)
+func TestBulkQuarantinePhones_Success(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	body, _ := json.Marshal(models.BulkQuarantineRequest{
+		PhoneNumbers: []string{"+5521999887766", "+5521988776655", "invalid"},
+		Reason:       "spam",
+		DurationDays: 30,
+	})
+	req, _ := http.NewRequest("POST", "/admin/phone/quarantine/bulk", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.BulkQuarantineResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.Quarantined)
+	assert.Equal(t, 1, response.Failed)
+	assert.Equal(t, "spam", response.Reason)
+	assert.Len(t, response.Results, 3)
+	assert.False(t, response.Results[2].Success)
+	assert.NotEmpty(t, response.Results[2].Error)
+}
+
+// TestBulkQuarantinePhones_MissingReason tests bulk quarantine without a reason
+func TestBulkQuarantinePhones_MissingReason(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	body := []byte(`{"phone_numbers": ["+5521999887766"], "reason": "  "}`)
+	req, _ := http.NewRequest("POST", "/admin/phone/quarantine/bulk", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestRecordDeliveryReceipt_InvalidStatus tests a delivery receipt with an unknown status
+func TestRecordDeliveryReceipt_InvalidStatus(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	body := []byte(`{"status": "bounced"}`)
+	req, _ := http.NewRequest("POST", "/admin/phone/+5521999887766/delivery-receipt", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestRecordDeliveryReceipt_Delivered tests a successful delivery receipt for an unbound phone
+func TestRecordDeliveryReceipt_Delivered(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	body := []byte(`{"status": "delivered"}`)
+	req, _ := http.NewRequest("POST", "/admin/phone/+5521999887766/delivery-receipt", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.PhoneDeliveryReceiptResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "5521999887766", response.PhoneNumber)
+	assert.False(t, response.Demoted)
+}
+
 // TestReleaseQuarantine_Success tests successful quarantine release (admin)
 func TestReleaseQuarantine_Success(t *testing.T) {
 	_, router, cleanup := setupPhoneHandlersTest(t)
@@ -657,6 +737,245 @@ func TestGetQuarantinedPhones_Pagination(t *testing.T) {
 	assert.Equal(t, 5, response.Pagination.PerPage)
 }
 
+// insertQuarantinedPhonesForFilters inserts quarantined phones with different reasons and start dates
+func insertQuarantinedPhonesForFilters(t *testing.T) {
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+	quarantineUntil := time.Now().Add(24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-30 * 24 * time.Hour)
+
+	phones := []interface{}{
+		models.PhoneCPFMapping{
+			PhoneNumber:       utils.FormatPhoneForStorage("55", "21", "999887766"),
+			CPF:               "03561350712",
+			Status:            models.MappingStatusQuarantined,
+			QuarantineUntil:   &quarantineUntil,
+			QuarantineReason:  "fraud",
+			QuarantineHistory: []models.QuarantineEvent{{QuarantinedAt: recent, QuarantineUntil: quarantineUntil, Reason: "fraud"}},
+		},
+		models.PhoneCPFMapping{
+			PhoneNumber:       utils.FormatPhoneForStorage("55", "21", "988776655"),
+			Status:            models.MappingStatusQuarantined,
+			QuarantineUntil:   &quarantineUntil,
+			QuarantineReason:  "spam",
+			QuarantineHistory: []models.QuarantineEvent{{QuarantinedAt: old, QuarantineUntil: quarantineUntil, Reason: "spam"}},
+		},
+	}
+
+	_, err := collection.InsertMany(ctx, phones)
+	assert.NoError(t, err)
+}
+
+// TestGetQuarantinedPhones_Filters tests filtering quarantined phones by reason and period
+func TestGetQuarantinedPhones_Filters(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	insertQuarantinedPhonesForFilters(t)
+
+	after := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name   string
+		query  string
+		reason string
+	}{
+		{name: "By reason", query: "reason=spam", reason: "spam"},
+		{name: "Quarantined after", query: "quarantined_after=" + after, reason: "fraud"},
+		{name: "Quarantined before", query: "quarantined_before=" + after, reason: "spam"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/admin/phone/quarantined?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.QuarantinedListResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, response.Pagination.Total)
+			if assert.Len(t, response.Data, 1) {
+				assert.Equal(t, tt.reason, response.Data[0].QuarantineReason)
+				assert.NotNil(t, response.Data[0].QuarantinedAt)
+			}
+		})
+	}
+}
+
+// TestGetQuarantinedPhones_InvalidParams tests rejecting invalid filters and formats
+func TestGetQuarantinedPhones_InvalidParams(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	for _, query := range []string{
+		"format=xml",
+		"quarantined_after=yesterday",
+		"quarantined_after=2024-02-01T00:00:00Z&quarantined_before=2024-01-01T00:00:00Z",
+	} {
+		req, _ := http.NewRequest("GET", "/admin/phone/quarantined?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+// TestGetQuarantinedPhones_CSVExport tests the CSV export masks CPFs by default
+func TestGetQuarantinedPhones_CSVExport(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	insertQuarantinedPhonesForFilters(t)
+
+	req, _ := http.NewRequest("GET", "/admin/phone/quarantined?format=csv&reason=fraud", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.True(t, strings.HasPrefix(lines[0], "Telefone,CPF,Motivo"))
+		assert.Contains(t, lines[1], utils.MaskCPF("03561350712"))
+		assert.NotContains(t, lines[1], "03561350712")
+		assert.Contains(t, lines[1], "fraud")
+	}
+}
+
+// TestGetQuarantinedPhones_CSVExportUnmasked tests the CSV export keeps CPFs for elevated scopes
+func TestGetQuarantinedPhones_CSVExportUnmasked(t *testing.T) {
+	handlers, _, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	original := config.AppConfig.QuarantineExportUnmaskedScopes
+	config.AppConfig.QuarantineExportUnmaskedScopes = []string{"phone:export"}
+	defer func() { config.AppConfig.QuarantineExportUnmaskedScopes = original }()
+
+	insertQuarantinedPhonesForFilters(t)
+
+	router := gin.New()
+	router.GET("/admin/phone/quarantined", func(c *gin.Context) {
+		claims := &models.JWTClaims{PreferredUsername: "03561350712", Scope: "openid phone:export"}
+		claims.RealmAccess.Roles = []string{"go:admin"}
+		c.Set("claims", claims)
+		c.Next()
+	}, handlers.GetQuarantinedPhones)
+
+	req, _ := http.NewRequest("GET", "/admin/phone/quarantined?format=csv&reason=fraud", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "03561350712")
+}
+
+// insertOptInHistoryForQuery inserts opt-in history entries of two citizens, out of chronological order
+func insertOptInHistoryForQuery(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	reason := "too_many_messages"
+
+	entries := []interface{}{
+		models.OptInHistory{PhoneNumber: "5521999887766", CPF: "03561350712", Action: models.OptInActionOptOut, Channel: models.ChannelWhatsApp, Reason: &reason, Timestamp: base.Add(48 * time.Hour)},
+		models.OptInHistory{PhoneNumber: "5521999887766", CPF: "03561350712", Action: models.OptInActionOptIn, Channel: models.ChannelWhatsApp, Timestamp: base},
+		models.OptInHistory{PhoneNumber: "5521999887766", CPF: "03561350712", Action: models.OptInActionRejected, Channel: models.ChannelWhatsApp, Timestamp: base.Add(24 * time.Hour)},
+		models.OptInHistory{PhoneNumber: "5521988776655", CPF: "11144477735", Action: models.OptInActionOptIn, Channel: models.ChannelWhatsApp, Timestamp: base},
+	}
+
+	_, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).InsertMany(ctx, entries)
+	assert.NoError(t, err)
+}
+
+// TestGetOptInHistory tests the opt-in history is returned chronologically and filtered
+func TestGetOptInHistory(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	insertOptInHistoryForQuery(t)
+
+	tests := []struct {
+		name    string
+		query   string
+		actions []string
+	}{
+		{"by cpf", "cpf=03561350712", []string{models.OptInActionOptIn, models.OptInActionRejected, models.OptInActionOptOut}},
+		{"by phone", "phone=%2B5521999887766", []string{models.OptInActionOptIn, models.OptInActionRejected, models.OptInActionOptOut}},
+		{"by cpf and phone", "cpf=11144477735&phone=5521999887766", nil},
+		{"by period", "cpf=03561350712&from=2025-03-11T00:00:00Z&to=2025-03-12T12:00:00Z", []string{models.OptInActionRejected}},
+		{"paginated", "cpf=03561350712&page=2&per_page=2", []string{models.OptInActionOptOut}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/admin/optin/history?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.OptInHistoryListResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			var actions []string
+			for _, entry := range response.Data {
+				actions = append(actions, entry.Action)
+			}
+			assert.Equal(t, tt.actions, actions)
+		})
+	}
+}
+
+// TestGetOptInHistory_InvalidFilters tests invalid filters are rejected
+func TestGetOptInHistory_InvalidFilters(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	for _, query := range []string{
+		"",
+		"cpf=12345678900",
+		"phone=abc",
+		"cpf=03561350712&from=yesterday",
+		"cpf=03561350712&from=2025-03-12T00:00:00Z&to=2025-03-11T00:00:00Z",
+		"cpf=03561350712&format=xml",
+	} {
+		req, _ := http.NewRequest("GET", "/admin/optin/history?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+// TestGetOptInHistory_CSVExport tests the CSV export lists every action with localized labels
+func TestGetOptInHistory_CSVExport(t *testing.T) {
+	_, router, cleanup := setupPhoneHandlersTest(t)
+	defer cleanup()
+
+	insertOptInHistoryForQuery(t)
+
+	req, _ := http.NewRequest("GET", "/admin/optin/history?format=csv&cpf=03561350712&per_page=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.True(t, strings.HasPrefix(lines[0], "Data/Hora,Ação,Telefone,CPF"))
+		assert.Contains(t, lines[1], "Opt-in")
+		assert.Contains(t, lines[2], "Cadastro rejeitado")
+		assert.Contains(t, lines[3], "too_many_messages")
+	}
+}
+
 // TestGetQuarantineStats_Empty tests getting stats when no quarantines exist
 func TestGetQuarantineStats_Empty(t *testing.T) {
 	_, router, cleanup := setupPhoneHandlersTest(t)
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,9 +22,37 @@ import (
 	"go.uber.org/zap"
 )
 
+// phoneVerifiedWebhookQueueTimeout bounds queueing a phone verified webhook, which runs after the
+// verification response is sent
+const phoneVerifiedWebhookQueueTimeout = 5 * time.Second
+
+// queuePhoneVerifiedWebhook notifies downstream systems that a phone was verified and bound to a
+// CPF (only if enabled). The job is queued in the background, so neither its latency nor its
+// failures affect the verification response.
+func queuePhoneVerifiedWebhook(ctx context.Context, cpf, phone string) {
+	if !config.AppConfig.PhoneVerifiedWebhookEnabled {
+		return
+	}
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Verification codes are delivered through WhatsApp
+	channel := models.ChannelWhatsApp
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, phoneVerifiedWebhookQueueTimeout)
+		defer cancel()
+
+		if err := services.QueuePhoneVerifiedWebhook(ctx, cpf, phone, channel); err != nil {
+			logger.Error("failed to queue phone verified webhook", zap.Error(err))
+			return
+		}
+		logger.Debug("phone verified webhook queued")
+	}()
+}
+
 // ValidatePhoneVerification godoc
 // @Summary Validar verificação de telefone
-// @Description Valida o código de verificação enviado para o número de telefone
+// @Description Valida o código de verificação enviado para o número de telefone. Após PHONE_VERIFICATION_MAX_FAILURES tentativas com código inválido dentro de PHONE_VERIFICATION_LOCKOUT_WINDOW, novas tentativas do CPF são bloqueadas com 429 até o fim da janela (header Retry-After). O telefone validado passa a ser o principal e os telefones verificados antes são mantidos em telefone.verificados. Com PHONE_VERIFIED_WEBHOOK_ENABLED=true, uma validação bem-sucedida enfileira em segundo plano o webhook phone.verified (CPF, telefone normalizado e canal).
 // @Tags citizen
 // @Accept json
 // @Produce json
@@ -33,8 +64,9 @@ import (
 // @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
 // @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
 // @Failure 404 {object} ErrorResponse "Código de verificação não encontrado ou expirado"
-// @Failure 422 {object} ErrorResponse "Dados não processáveis - código inválido"
-// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 422 {object} ErrorResponse "Dados não processáveis - código ou número de telefone inválido"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido ou CPF bloqueado após tentativas com código inválido"
+// @Header 429 {string} Retry-After "Segundos até o fim do bloqueio"
 // @Failure 500 {object} ErrorResponse "Erro interno do servidor"
 // @Router /citizen/{cpf}/phone/validate [post]
 func ValidatePhoneVerification(c *gin.Context) {
@@ -58,7 +90,8 @@ func ValidatePhoneVerification(c *gin.Context) {
 	if !utils.ValidateCPF(cpf) {
 		logger.Warn("invalid CPF format", zap.String("cpf", cpf))
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid CPF format",
+			Code:    ErrCodeCPFInvalid,
+			Message: "Invalid CPF format",
 		})
 		return
 	}
@@ -73,26 +106,68 @@ func ValidatePhoneVerification(c *gin.Context) {
 		})
 		inputSpan.End()
 		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body: " + err.Error(),
+			Code:    ErrCodeInvalidRequestBody,
+			Message: "Invalid request body: " + err.Error(),
 		})
 		return
 	}
+	// Compare in the stored format, whatever length and alphabet the code was generated with
+	req.Code = utils.NormalizeVerificationCode(req.Code)
 	utils.AddSpanAttribute(inputSpan, "input.ddi", req.DDI)
 	utils.AddSpanAttribute(inputSpan, "input.ddd", req.DDD)
 	utils.AddSpanAttribute(inputSpan, "input.valor", req.Valor)
 	utils.AddSpanAttribute(inputSpan, "input.code", req.Code)
 	inputSpan.End()
 
-	// Build full phone number for lookup with tracing
+	// Reject CPFs locked out after too many failed attempts with tracing
+	ctx, lockoutSpan := utils.TraceBusinessLogic(ctx, "check_verification_lockout")
+	lockedFor, err := services.PhoneVerificationLockedFor(ctx, cpf)
+	if err != nil {
+		// Fail open: a Redis outage shouldn't block legitimate verifications
+		utils.RecordErrorInSpan(lockoutSpan, err, map[string]interface{}{
+			"lockout.operation": "check",
+		})
+		logger.Warn("failed to check phone verification lockout", zap.Error(err))
+	}
+	utils.AddSpanAttribute(lockoutSpan, "lockout.locked", lockedFor > 0)
+	lockoutSpan.End()
+	if lockedFor > 0 {
+		observability.PhoneVerificationFailedTotal.WithLabelValues("locked_out").Inc()
+		logger.Warn("phone verification locked out", zap.Duration("retry_after", lockedFor))
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedFor.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Code:    ErrCodePhoneVerificationLocked,
+			Message: "Too many failed verification attempts, try again later",
+		})
+		return
+	}
+
+	// Normalize phone so the lookup matches the stored verification record with tracing
 	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_full_phone_number")
-	fullPhone := req.DDI + req.DDD + req.Valor
+	normalized, err := utils.NormalizePhone(req.DDI, req.DDD, req.Valor)
+	if err != nil {
+		utils.RecordErrorInSpan(buildSpan, err, map[string]interface{}{
+			"input.ddi":   req.DDI,
+			"input.ddd":   req.DDD,
+			"input.valor": req.Valor,
+		})
+		buildSpan.End()
+		observability.PhoneVerificationFailedTotal.WithLabelValues("invalid_phone").Inc()
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    ErrCodePhoneInvalid,
+			Message: err.Error(),
+		})
+		return
+	}
+	req.DDI, req.DDD, req.Valor = normalized.DDI, normalized.DDD, normalized.Valor
+	fullPhone := utils.FormatPhoneForStorage(normalized.DDI, normalized.DDD, normalized.Valor)
 	utils.AddSpanAttribute(buildSpan, "full_phone_number", fullPhone)
 	buildSpan.End()
 
 	// Find verification request with tracing
 	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.PhoneVerificationCollection, "verification_lookup")
 	var verification models.PhoneVerification
-	err := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection).FindOne(
+	err = config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection).FindOne(
 		ctx,
 		bson.M{
 			"cpf":          cpf,
@@ -107,8 +182,15 @@ func ValidatePhoneVerification(c *gin.Context) {
 			utils.AddSpanAttribute(findSpan, "verification.found", false)
 			utils.AddSpanAttribute(findSpan, "verification.reason", "invalid_or_expired_code")
 			findSpan.End()
+			observability.PhoneVerificationFailedTotal.WithLabelValues("invalid_or_expired_code").Inc()
+			if locked, err := services.RecordPhoneVerificationFailure(ctx, cpf); err != nil {
+				logger.Warn("failed to record phone verification failure", zap.Error(err))
+			} else if locked {
+				logger.Warn("phone verification locked out after too many failed attempts")
+			}
 			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "Invalid or expired verification code",
+				Code:    ErrCodePhoneVerificationExpired,
+				Message: "Invalid or expired verification code",
 			})
 			return
 		}
@@ -119,7 +201,8 @@ func ValidatePhoneVerification(c *gin.Context) {
 		findSpan.End()
 		observability.Logger().Error("failed to find verification request", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to validate verification code",
+			Code:    ErrCodeInternal,
+			Message: "Failed to validate verification code",
 		})
 		return
 	}
@@ -129,30 +212,54 @@ func ValidatePhoneVerification(c *gin.Context) {
 	utils.AddSpanAttribute(findSpan, "verification.expires_at", verification.ExpiresAt.String())
 	findSpan.End()
 
+	if err := services.ResetPhoneVerificationFailures(ctx, cpf); err != nil {
+		logger.Warn("failed to reset phone verification failures", zap.Error(err))
+	}
+
 	// Prepare verified phone data with tracing
 	ctx, prepareSpan := utils.TraceBusinessLogic(ctx, "prepare_verified_phone_data")
-	origem := "self-declared"
-	sistema := "rmi"
+	current, err := getCurrentPhoneData(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(prepareSpan, err, map[string]interface{}{
+			"operation": "get_current_phone_data",
+		})
+		prepareSpan.End()
+		observability.Logger().Error("failed to get current phone data", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Failed to update phone data",
+		})
+		return
+	}
 	now := time.Now()
 
-	// Build verified phone object
-	telefone := models.Telefone{
-		Indicador: utils.BoolPtr(true), // Set to true since it's now verified
-		Principal: &models.TelefonePrincipal{
-			DDI:       &req.DDI,
-			DDD:       &req.DDD,
-			Valor:     &req.Valor,
-			Origem:    &origem,
-			Sistema:   &sistema,
-			UpdatedAt: &now,
-		},
+	// The verified phone becomes the primary one, keeping the phones verified before
+	var telefone models.Telefone
+	previousPrimary := ""
+	if current != nil {
+		telefone.Verificados = utils.VerifiedPhones(current.Telefone)
+		if primary := telefone.PrimaryVerified(); primary != nil {
+			previousPrimary = primary.ID
+		}
 	}
+	telefone.AddVerified(models.TelefoneVerificado{
+		ID:         fullPhone,
+		DDI:        &req.DDI,
+		DDD:        &req.DDD,
+		Valor:      &req.Valor,
+		VerifiedAt: &now,
+	}, now)
+	utils.AddSpanAttribute(prepareSpan, "phone.verified_count", len(telefone.Verificados))
 	prepareSpan.End()
+	if err := telefone.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
 
 	// Use cache service for verified phone update with tracing
 	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_verified_phone_via_cache")
 	cacheService := services.NewCacheService()
-	err = cacheService.UpdateSelfDeclaredPhone(ctx, cpf, &telefone)
+	_, err = cacheService.UpdateSelfDeclaredPhone(ctx, cpf, &telefone, nil)
 	if err != nil {
 		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
 			"cache.operation": "update_verified_phone",
@@ -161,7 +268,8 @@ func ValidatePhoneVerification(c *gin.Context) {
 		updateSpan.End()
 		observability.Logger().Error("failed to update verified phone via cache service", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to update phone data",
+			Code:    ErrCodeInternal,
+			Message: "Failed to update phone data",
 		})
 		return
 	}
@@ -172,7 +280,7 @@ func ValidatePhoneVerification(c *gin.Context) {
 	// Invalidate cache with tracing
 	ctx, cacheInvalidateSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
 	cacheKey := fmt.Sprintf("citizen:%s", cpf)
-	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
 		utils.RecordErrorInSpan(cacheInvalidateSpan, err, map[string]interface{}{
 			"cache.key": cacheKey,
 		})
@@ -218,8 +326,17 @@ func ValidatePhoneVerification(c *gin.Context) {
 		})
 		observability.Logger().Warn("failed to log audit event", zap.Error(err))
 	}
+	if previousPrimary != "" && previousPrimary != fullPhone {
+		logPhonePrimaryChange(ctx, auditCtx, previousPrimary, fullPhone)
+	}
 	auditSpan.End()
 
+	// Notify connected clients (best-effort)
+	services.EventBusInstance.PublishPhoneVerified(ctx, cpf, verification.PhoneNumber)
+
+	// Notify downstream systems (only if enabled, never delays the response)
+	queuePhoneVerifiedWebhook(ctx, cpf, verification.PhoneNumber)
+
 	// Serialize response with tracing
 	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
 	c.JSON(http.StatusOK, SuccessResponse{
@@ -236,3 +353,140 @@ func ValidatePhoneVerification(c *gin.Context) {
 		zap.Duration("total_duration", totalDuration),
 		zap.String("status", "success"))
 }
+
+// logPhonePrimaryChange audits the primary phone of the CPF changing from previous to primary
+// (phone IDs). Failures are only logged.
+func logPhonePrimaryChange(ctx context.Context, auditCtx utils.AuditContext, previous, primary string) {
+	if previous == "" {
+		previous = "none"
+	}
+	if err := utils.LogPhonePrimaryChange(ctx, auditCtx, previous, primary); err != nil {
+		observability.Logger().Warn("failed to log phone primary change", zap.Error(err))
+	}
+}
+
+// SetPrimaryPhone godoc
+// @Summary Definir telefone principal
+// @Description Define qual dos telefones verificados do cidadão é o principal. phone_id é o telefone no formato de armazenamento (DDI+DDD+número, o id de telefone.verificados). O telefone principal é exposto em telefone.principal e os demais verificados em telefone.verificados. A troca é registrada na auditoria; definir o telefone que já é o principal não altera nada.
+// @Tags citizen
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Param phone_id path string true "ID do telefone verificado (DDI+DDD+número)"
+// @Security BearerAuth
+// @Success 200 {object} models.Telefone "Telefones autodeclarados com o novo principal"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 404 {object} ErrorResponse "Telefone não está entre os verificados do CPF"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/phone/{phone_id}/set-primary [post]
+func SetPrimaryPhone(c *gin.Context) {
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "SetPrimaryPhone")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	phoneID := c.Param("phone_id")
+
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("phone_id", phoneID),
+		attribute.String("operation", "set_primary_phone"),
+		attribute.String("service", "phone_verification"),
+	)
+
+	logger := observability.Logger().With(zap.String("cpf", cpf), zap.String("phone_id", phoneID))
+
+	// Validate CPF format
+	if !utils.ValidateCPF(cpf) {
+		logger.Warn("invalid CPF format", zap.String("cpf", cpf))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    ErrCodeCPFInvalid,
+			Message: "Invalid CPF format",
+		})
+		return
+	}
+
+	// Get the current self-declared phone with tracing
+	ctx, findSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.SelfDeclaredCollection, "cpf")
+	current, err := getCurrentPhoneData(ctx, cpf)
+	if err != nil {
+		utils.RecordErrorInSpan(findSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.SelfDeclaredCollection,
+			"db.operation":  "find_one",
+		})
+		findSpan.End()
+		logger.Error("failed to get current phone data", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Failed to get phone data",
+		})
+		return
+	}
+	findSpan.End()
+
+	var telefone models.Telefone
+	if current != nil && current.Telefone != nil {
+		telefone = *current.Telefone
+		telefone.Verificados = utils.VerifiedPhones(current.Telefone)
+	}
+
+	previousPrimary := ""
+	if primary := telefone.PrimaryVerified(); primary != nil {
+		previousPrimary = primary.ID
+	}
+	if telefone.FindVerified(phoneID) == nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    ErrCodeVerifiedPhoneNotFound,
+			Message: "Phone is not a verified phone of this CPF",
+		})
+		return
+	}
+	if previousPrimary == phoneID {
+		c.JSON(http.StatusOK, telefone)
+		return
+	}
+	telefone.SetPrimary(phoneID, time.Now())
+
+	// Use cache service for the phone update with tracing
+	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_primary_phone_via_cache")
+	cacheService := services.NewCacheService()
+	if _, err := cacheService.UpdateSelfDeclaredPhone(ctx, cpf, &telefone, nil); err != nil {
+		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
+			"cache.operation": "update_primary_phone",
+			"cache.service":   "unified_cache_service",
+		})
+		updateSpan.End()
+		logger.Error("failed to update primary phone via cache service", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    ErrCodeInternal,
+			Message: "Failed to update phone data",
+		})
+		return
+	}
+	updateSpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+
+	// Invalidate all related caches with tracing
+	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
+	if err := cacheService.InvalidateSelfDeclared(ctx, cpf); err != nil {
+		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
+			"cache.key": fmt.Sprintf("citizen:%s", cpf),
+		})
+		logger.Warn("failed to invalidate cache", zap.Error(err))
+	}
+	if err := utils.InvalidateCitizenCache(ctx, cpf); err != nil {
+		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
+			"cache.key": fmt.Sprintf("citizen:%s", cpf),
+		})
+		logger.Warn("failed to invalidate cache", zap.Error(err))
+	}
+	cacheSpan.End()
+
+	// Log audit event with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "phone_primary_change", "phone")
+	logPhonePrimaryChange(ctx, utils.GetAuditContextFromGin(c, cpf), previousPrimary, phoneID)
+	auditSpan.End()
+
+	c.JSON(http.StatusOK, telefone)
+}
@@ -15,6 +15,7 @@ func setupPhoneRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	r.POST("/v1/citizen/:cpf/phone/validate", ValidatePhoneVerification)
+	r.POST("/v1/citizen/:cpf/phone/:phone_id/set-primary", SetPrimaryPhone)
 	return r
 }
 
@@ -114,3 +115,44 @@ func TestValidatePhoneVerification_ResponseFormat(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err, "Response should be valid JSON")
 }
+
+func TestValidatePhoneVerification_InvalidPhoneNumber(t *testing.T) {
+	r := setupPhoneRouter()
+	cpf := "03561350712"
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown DDD", `{"code":"123456","ddi":"55","ddd":"20","valor":"999999999"}`},
+		{"mobile without leading 9", `{"code":"123456","ddi":"55","ddd":"21","valor":"899999999"}`},
+		{"too few digits", `{"code":"123456","ddi":"55","ddd":"21","valor":"9999"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/v1/citizen/"+cpf+"/phone/validate", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		})
+	}
+}
+
+func TestSetPrimaryPhone_InvalidCPF(t *testing.T) {
+	r := setupPhoneRouter()
+
+	for _, cpf := range []string{"123", "abcdefghijk", "12345678900"} {
+		t.Run(cpf, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/v1/citizen/"+cpf+"/phone/5521987654321/set-primary", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, ErrCodeCPFInvalid, response["code"])
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// GetPreferredChannel godoc
+// @Summary Obter canal preferido de notificação
+// @Description Retorna o canal pelo qual o cidadão prefere receber notificações (código de /config/channels), ou null quando não foi escolhido
+// @Tags citizen
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Security BearerAuth
+// @Success 200 {object} models.PreferredChannelResponse "Canal preferido obtido com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/preferred-channel [get]
+func GetPreferredChannel(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "GetPreferredChannel")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "get_preferred_channel"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("GetPreferredChannel called")
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Use DataManager for cache-aware reading with tracing
+	ctx, dbSpan := utils.TraceDatabaseFind(ctx, config.AppConfig.UserConfigCollection, "cpf")
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+
+	var userConfig models.UserConfig
+	err := dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &userConfig)
+	if err != nil && err != services.ErrDocumentNotFound {
+		utils.RecordErrorInSpan(dbSpan, err, map[string]interface{}{
+			"operation": "dataManager.Read",
+			"cpf":       cpf,
+			"type":      "user_config",
+		})
+		dbSpan.End()
+		logger.Error("failed to get user config via DataManager", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
+		return
+	}
+	utils.AddSpanAttribute(dbSpan, "user_config.found", err == nil)
+	dbSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, models.PreferredChannelResponse{PreferredChannel: userConfig.PreferredChannel})
+	responseSpan.End()
+
+	logger.Debug("GetPreferredChannel completed",
+		zap.Bool("preferred_channel_set", userConfig.PreferredChannel != nil),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
+
+// UpdatePreferredChannel godoc
+// @Summary Atualizar canal preferido de notificação
+// @Description Define o canal pelo qual o cidadão prefere receber notificações. O canal deve ser um dos canais disponíveis em /config/channels. O canal preferido é informado em GET /citizen/{cpf}/notification-target e nos webhooks de endereço, telefone verificado e verificação de e-mail (preferred_channel).
+// @Tags citizen
+// @Accept json
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Param data body models.PreferredChannelRequest true "Canal preferido"
+// @Security BearerAuth
+// @Success 200 {object} models.PreferredChannelResponse "Canal preferido atualizado com sucesso"
+// @Failure 400 {object} ErrorResponse "Formato de CPF inválido, corpo da requisição inválido ou canal indisponível"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} ErrorResponse "Erro interno do servidor"
+// @Router /citizen/{cpf}/preferred-channel [put]
+func UpdatePreferredChannel(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "UpdatePreferredChannel")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "update_preferred_channel"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("UpdatePreferredChannel called")
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "preferred_channel")
+	var input models.PreferredChannelRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "PreferredChannelRequest",
+		})
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeInvalidRequestBody, Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	utils.AddSpanAttribute(inputSpan, "input.preferred_channel", input.PreferredChannel)
+	inputSpan.End()
+
+	// Validate the channel against the available channels with tracing
+	ctx, channelSpan := utils.TraceInputValidation(ctx, "preferred_channel", "preferred_channel")
+	if !services.IsAvailableChannel(input.PreferredChannel) {
+		utils.RecordErrorInSpan(channelSpan, fmt.Errorf("unavailable channel"), map[string]interface{}{
+			"preferred_channel": input.PreferredChannel,
+		})
+		channelSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodePreferredChannelInvalid, Message: fmt.Sprintf("invalid preferred channel: %s", input.PreferredChannel)})
+		return
+	}
+	channelSpan.End()
+
+	// Read the current value for the audit trail
+	dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+	var current models.UserConfig
+	if err := dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &current); err != nil && err != services.ErrDocumentNotFound {
+		logger.Error("failed to get user config via DataManager", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to get user config"})
+		return
+	}
+
+	// Update only the preferred channel via cache service with tracing, so concurrent updates of
+	// other user config fields aren't overwritten
+	ctx, updateSpan := utils.TraceBusinessLogic(ctx, "update_user_config_via_cache")
+	cacheService := services.NewCacheService()
+	if err := cacheService.PatchUserConfig(ctx, cpf, bson.M{"preferred_channel": input.PreferredChannel}); err != nil {
+		utils.RecordErrorInSpan(updateSpan, err, map[string]interface{}{
+			"cache.operation": "update_user_config",
+			"cache.service":   "unified_cache_service",
+		})
+		updateSpan.End()
+		logger.Error("failed to update preferred channel via cache service", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to update preferred channel"})
+		return
+	}
+	updateSpan.End()
+
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+
+	// Invalidate cache with tracing
+	cacheKey := fmt.Sprintf("user_config:%s", cpf)
+	ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, cacheKey)
+	if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+		utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
+			"cache.key": cacheKey,
+		})
+		logger.Warn("failed to invalidate cache", zap.Error(err))
+	}
+	cacheSpan.End()
+
+	// Log audit event with tracing
+	ctx, auditSpan := utils.TraceAuditLogging(ctx, "update", "preferred_channel")
+	auditCtx := utils.AuditContext{
+		CPF:       cpf,
+		UserID:    c.GetString("user_id"),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+	if err := utils.LogUserConfigUpdate(ctx, auditCtx, "preferred_channel", current.PreferredChannel, input.PreferredChannel); err != nil {
+		utils.RecordErrorInSpan(auditSpan, err, map[string]interface{}{
+			"audit.action":   "update",
+			"audit.resource": "preferred_channel",
+		})
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+	auditSpan.End()
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(http.StatusOK, models.PreferredChannelResponse{PreferredChannel: &input.PreferredChannel})
+	responseSpan.End()
+
+	logger.Debug("UpdatePreferredChannel completed",
+		zap.String("preferred_channel", input.PreferredChannel),
+		zap.Duration("total_duration", time.Since(startTime)),
+		zap.String("status", "success"))
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatePreferredChannel_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/v1/citizen/:cpf/preferred-channel", UpdatePreferredChannel)
+
+	tests := []struct {
+		name     string
+		cpf      string
+		body     string
+		wantCode string
+	}{
+		{name: "invalid CPF", cpf: "123", body: `{"preferred_channel": "whatsapp"}`, wantCode: ErrCodeCPFInvalid},
+		{name: "missing channel", cpf: cpfTest, body: `{}`, wantCode: ErrCodeInvalidRequestBody},
+		{name: "unavailable channel", cpf: cpfTest, body: `{"preferred_channel": "sms"}`, wantCode: ErrCodePreferredChannelInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/v1/citizen/"+tt.cpf+"/preferred-channel", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,630 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Field names used as keys in the PATCH /citizen/{cpf}/self-declared results
+const (
+	selfDeclaredPatchFieldAddress   = "endereco"
+	selfDeclaredPatchFieldEmail     = "email"
+	selfDeclaredPatchFieldPhone     = "telefone"
+	selfDeclaredPatchFieldEthnicity = "raca"
+)
+
+// PatchSelfDeclared godoc
+// @Summary Atualizar parcialmente dados autodeclarados
+// @Description Atualiza em uma única chamada endereço, email, telefone e/ou etnia autodeclarados. Apenas os campos presentes no corpo são atualizados; campos omitidos permanecem inalterados. Todos os campos informados são validados antes de qualquer alteração. A resposta indica, por campo, se foi atualizado (updated), se não houve alteração por ser idêntico aos dados atuais (unchanged), se iniciou a verificação de telefone ou de email (verification_pending) ou se falhou (error). Cada campo alterado gera seu próprio evento de auditoria.
+// @Tags citizen
+// @Accept json
+// @Produce json
+// @Param cpf path string true "Número do CPF"
+// @Param data body models.SelfDeclaredPatchInput true "Campos autodeclarados a atualizar"
+// @Param If-Match header string false "Versão dos dados autodeclarados em que a atualização se baseia (ETag de GET /citizen/{cpf}); também aceita no campo version do corpo"
+// @Param Idempotency-Key header string false "Chave de idempotência (máx. 255 caracteres): repetições com a mesma chave recebem a resposta original sem reexecutar a atualização"
+// @Security BearerAuth
+// @Success 200 {object} models.SelfDeclaredPatchResponse "Resultado da atualização por campo"
+// @Header 200 {string} ETag "Nova versão dos dados autodeclarados"
+// @Failure 400 {object} ValidationErrorResponse "Formato de CPF inválido, corpo vazio ou dados incorretos"
+// @Failure 401 {object} ErrorResponse "Token de autenticação não fornecido ou inválido"
+// @Failure 403 {object} ErrorResponse "Acesso negado - permissões insuficientes"
+// @Failure 412 {object} ErrorResponse "Dados autodeclarados alterados por outra requisição (versão desatualizada)"
+// @Failure 422 {object} ErrorResponse "Dados não processáveis - número de telefone, CEP ou UF inválidos"
+// @Failure 429 {object} ErrorResponse "Muitas requisições - limite de taxa excedido"
+// @Failure 500 {object} models.SelfDeclaredPatchResponse "Nenhum campo pôde ser atualizado"
+// @Router /citizen/{cpf}/self-declared [patch]
+func PatchSelfDeclared(c *gin.Context) {
+	startTime := time.Now()
+	ctx, span := otel.Tracer("").Start(c.Request.Context(), "PatchSelfDeclared")
+	defer span.End()
+
+	cpf := c.Param("cpf")
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Add CPF to span attributes
+	span.SetAttributes(
+		attribute.String("cpf", cpf),
+		attribute.String("operation", "patch_self_declared"),
+		attribute.String("service", "citizen"),
+	)
+
+	logger.Debug("PatchSelfDeclared called", zap.String("cpf", cpf))
+
+	// Validate CPF with tracing
+	ctx, cpfSpan := utils.TraceInputValidation(ctx, "cpf_format", "cpf")
+	if !utils.ValidateCPF(cpf) {
+		utils.RecordErrorInSpan(cpfSpan, fmt.Errorf("invalid CPF format"), map[string]interface{}{
+			"cpf": cpf,
+		})
+		cpfSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeCPFInvalid, Message: "Invalid CPF format"})
+		return
+	}
+	cpfSpan.End()
+
+	// Parse input with tracing
+	ctx, inputSpan := utils.TraceInputParsing(ctx, "self_declared_patch")
+	var input models.SelfDeclaredPatchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.RecordErrorInSpan(inputSpan, err, map[string]interface{}{
+			"error.type": "input_parsing",
+			"input.type": "SelfDeclaredPatchInput",
+		})
+		inputSpan.End()
+		logger.Error("failed to parse input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(err))
+		return
+	}
+	if input.IsEmpty() {
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeNoFieldsProvided, Message: "At least one field must be provided"})
+		return
+	}
+	expectedVersion, err := expectedSelfDeclaredVersion(c, input.Version)
+	if err != nil {
+		inputSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeVersionInvalid, Message: err.Error()})
+		return
+	}
+	inputSpan.End()
+
+	// Validate every present field before changing anything
+	ctx, validationSpan := utils.TraceInputValidation(ctx, "self_declared_patch", "self_declared")
+	var normalizedPhone *utils.PhoneComponents
+	if input.Telefone != nil {
+		normalized, err := utils.NormalizePhone(input.Telefone.DDI, input.Telefone.DDD, input.Telefone.Valor)
+		if err != nil {
+			utils.RecordErrorInSpan(validationSpan, err, map[string]interface{}{
+				"field": selfDeclaredPatchFieldPhone,
+			})
+			validationSpan.End()
+			logger.Warn("invalid phone number", zap.Error(err))
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Code: ErrCodePhoneInvalid, Message: err.Error()})
+			return
+		}
+		normalizedPhone = normalized
+	}
+	if input.Endereco != nil {
+		if err := utils.NormalizeSelfDeclaredAddress(input.Endereco); err != nil {
+			utils.RecordErrorInSpan(validationSpan, err, map[string]interface{}{
+				"field": selfDeclaredPatchFieldAddress,
+			})
+			validationSpan.End()
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Code: ErrCodeValidationRule, Message: err.Error()})
+			return
+		}
+	}
+	if input.Email != nil && !utils.ValidateEmail(*input.Email).IsValid {
+		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid email format"), map[string]interface{}{
+			"field": selfDeclaredPatchFieldEmail,
+		})
+		validationSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeEmailInvalid, Message: "Invalid email format"})
+		return
+	}
+	if input.Raca != nil && !services.IsValidEthnicity(input.Raca.Valor) {
+		utils.RecordErrorInSpan(validationSpan, fmt.Errorf("invalid ethnicity value: %s", input.Raca.Valor), map[string]interface{}{
+			"field": selfDeclaredPatchFieldEthnicity,
+		})
+		validationSpan.End()
+		c.JSON(http.StatusBadRequest, ErrorResponse{Code: ErrCodeEthnicityInvalid, Message: "invalid ethnicity value"})
+		return
+	}
+	validationSpan.End()
+
+	// Reject the whole update up front when it is based on an outdated version. Each field
+	// write then expects the version left by the previous one.
+	if _, err := services.CheckSelfDeclaredVersion(ctx, cpf, expectedVersion); err != nil {
+		if isSelfDeclaredVersionConflict(err) {
+			respondSelfDeclaredVersionConflict(c, logger, err)
+			return
+		}
+		logger.Error("failed to check self-declared version", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: ErrCodeInternal, Message: "Failed to check current data: " + err.Error()})
+		return
+	}
+
+	auditCtx := utils.AuditContext{
+		CPF:       cpf,
+		UserID:    c.GetString("user_id"),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetString("RequestID"),
+	}
+
+	response := models.SelfDeclaredPatchResponse{Results: make(map[string]models.SelfDeclaredPatchFieldResult)}
+	if input.Endereco != nil {
+		response.Results[selfDeclaredPatchFieldAddress] = patchSelfDeclaredAddress(ctx, cpf, *input.Endereco, expectedVersion, auditCtx)
+	}
+	if input.Email != nil {
+		response.Results[selfDeclaredPatchFieldEmail] = patchSelfDeclaredEmail(ctx, cpf, input.Email.Valor, expectedVersion, auditCtx)
+	}
+	if input.Raca != nil {
+		response.Results[selfDeclaredPatchFieldEthnicity] = patchSelfDeclaredRaca(ctx, cpf, input.Raca.Valor, expectedVersion, auditCtx)
+	}
+	if normalizedPhone != nil {
+		response.Results[selfDeclaredPatchFieldPhone] = patchSelfDeclaredPhone(ctx, cpf, normalizedPhone, auditCtx)
+	}
+
+	changed, failed := 0, 0
+	for field, result := range response.Results {
+		utils.AddSpanAttribute(span, "result."+field, result.Status)
+		switch result.Status {
+		case models.SelfDeclaredPatchUpdated, models.SelfDeclaredPatchVerificationPending:
+			changed++
+		case models.SelfDeclaredPatchError:
+			failed++
+		}
+	}
+
+	// Invalidate the merged citizen cache once for all changed fields
+	if changed > 0 {
+		// The version read below must observe these writes, not a lagging secondary
+		ctx = utils.WithReadAfterWrite(ctx)
+
+		ctx, cacheSpan := utils.TraceCacheInvalidation(ctx, fmt.Sprintf("citizen:%s", cpf))
+		cacheKey := fmt.Sprintf("citizen:%s", cpf)
+		if err := services.NewCacheService().InvalidateSelfDeclared(ctx, cpf); err != nil {
+			utils.RecordErrorInSpan(cacheSpan, err, map[string]interface{}{
+				"cache.key": cacheKey,
+			})
+			logger.Warn("failed to invalidate cache", zap.Error(err))
+		}
+		cacheSpan.End()
+	}
+
+	if version, err := services.GetSelfDeclaredVersion(ctx, cpf); err == nil {
+		setSelfDeclaredVersionHeader(c, version)
+	}
+
+	status := http.StatusOK
+	if failed == len(response.Results) {
+		status = http.StatusInternalServerError
+	}
+
+	// Serialize response with tracing
+	_, responseSpan := utils.TraceResponseSerialization(ctx, "success")
+	c.JSON(status, response)
+	responseSpan.End()
+
+	// Log total operation time
+	totalDuration := time.Since(startTime)
+	logger.Debug("PatchSelfDeclared completed",
+		zap.String("cpf", cpf),
+		zap.Int("changed", changed),
+		zap.Int("failed", failed),
+		zap.Duration("total_duration", totalDuration),
+		zap.String("status", "success"))
+}
+
+// patchSelfDeclaredAddress applies the address part of a partial self-declared update
+func patchSelfDeclaredAddress(ctx context.Context, cpf string, input models.SelfDeclaredAddressInput, expectedVersion *int32, auditCtx utils.AuditContext) models.SelfDeclaredPatchFieldResult {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	current, err := getCurrentAddressData(ctx, cpf)
+	if err != nil {
+		logger.Error("failed to fetch current address data for comparison", zap.Error(err))
+		return patchFieldError("Failed to check current data: " + err.Error())
+	}
+	if selfDeclaredAddressMatches(current, input) {
+		return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUnchanged}
+	}
+
+	endereco := buildSelfDeclaredAddress(input, time.Now())
+	if err := endereco.Validate(); err != nil {
+		return patchFieldError(err.Error())
+	}
+	version, err := services.NewCacheService().UpdateSelfDeclaredAddress(ctx, cpf, &endereco, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		return patchFieldVersionConflict(logger, err)
+	}
+	if err != nil {
+		logger.Error("failed to update self-declared address via cache service", zap.Error(err))
+		return patchFieldError("Failed to update address: " + err.Error())
+	}
+	advanceSelfDeclaredVersion(expectedVersion, version)
+	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
+
+	if err := utils.LogAddressUpdate(ctx, auditCtx, current, &endereco); err != nil {
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+
+	queueAddressChangeWebhook(ctx, cpf, current, &endereco)
+	refreshCFDataForAddress(ctx, cpf, input)
+	return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUpdated}
+}
+
+// patchSelfDeclaredEmail applies the email part of a partial self-declared update
+func patchSelfDeclaredEmail(ctx context.Context, cpf, valor string, expectedVersion *int32, auditCtx utils.AuditContext) models.SelfDeclaredPatchFieldResult {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	current, err := getCurrentEmailData(ctx, cpf)
+	if err != nil {
+		logger.Error("failed to fetch current email data for comparison", zap.Error(err))
+		return patchFieldError("Failed to check current data: " + err.Error())
+	}
+	if config.AppConfig.EmailVerificationEnabled {
+		if selfDeclaredEmailMatchesVerified(current, valor) && !isSelfDeclaredOutdated(current.UpdatedAt) {
+			return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUnchanged}
+		}
+
+		if err := startSelfDeclaredEmailVerification(ctx, cpf, valor); err != nil {
+			logger.Error("failed to create email verification", zap.Error(err))
+			return patchFieldError("Failed to start email verification: " + err.Error())
+		}
+
+		if err := utils.LogEmailUpdate(ctx, auditCtx, currentEmailAuditValue(current), valor); err != nil {
+			logger.Warn("failed to log audit event", zap.Error(err))
+		}
+
+		return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchVerificationPending}
+	}
+
+	if selfDeclaredEmailMatches(current, valor) && !isSelfDeclaredOutdated(current.UpdatedAt) {
+		return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUnchanged}
+	}
+
+	email := buildSelfDeclaredEmail(valor, time.Now())
+	if err := email.Validate(); err != nil {
+		return patchFieldError(err.Error())
+	}
+	version, err := services.NewCacheService().UpdateSelfDeclaredEmail(ctx, cpf, &email, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		return patchFieldVersionConflict(logger, err)
+	}
+	if err != nil {
+		logger.Error("failed to update self-declared email via cache service", zap.Error(err))
+		return patchFieldError("Failed to update email: " + err.Error())
+	}
+	advanceSelfDeclaredVersion(expectedVersion, version)
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+
+	if err := utils.LogEmailUpdate(ctx, auditCtx, currentEmailAuditValue(current), valor); err != nil {
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+
+	return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUpdated}
+}
+
+// patchSelfDeclaredRaca applies the ethnicity part of a partial self-declared update
+func patchSelfDeclaredRaca(ctx context.Context, cpf, valor string, expectedVersion *int32, auditCtx utils.AuditContext) models.SelfDeclaredPatchFieldResult {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	var selfDeclared models.SelfDeclaredData
+	err := config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).FindOne(ctx, bson.M{"cpf": cpf}).Decode(&selfDeclared)
+	if err != nil && err != mongo.ErrNoDocuments {
+		observability.DatabaseOperations.WithLabelValues("find", "error").Inc()
+		logger.Error("failed to get self-declared data", zap.Error(err))
+		return patchFieldError("internal server error")
+	}
+
+	oldEthnicity := ""
+	if selfDeclared.Raca != nil {
+		oldEthnicity = *selfDeclared.Raca
+	}
+	if oldEthnicity == valor {
+		return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUnchanged}
+	}
+
+	version, err := services.NewCacheService().UpdateSelfDeclaredRaca(ctx, cpf, valor, expectedVersion)
+	if isSelfDeclaredVersionConflict(err) {
+		return patchFieldVersionConflict(logger, err)
+	}
+	if err != nil {
+		observability.DatabaseOperations.WithLabelValues("update", "error").Inc()
+		logger.Error("failed to update self-declared ethnicity via cache service", zap.Error(err))
+		return patchFieldError("internal server error")
+	}
+	advanceSelfDeclaredVersion(expectedVersion, version)
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+	observability.SelfDeclaredUpdates.WithLabelValues("success").Inc()
+
+	if err := utils.LogEthnicityUpdate(ctx, auditCtx, oldEthnicity, valor); err != nil {
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+
+	return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUpdated}
+}
+
+// patchSelfDeclaredPhone starts the verification flow for the phone part of a partial
+// self-declared update. As with PUT /citizen/{cpf}/phone, the phone itself is only
+// stored once the code is validated.
+func patchSelfDeclaredPhone(ctx context.Context, cpf string, phone *utils.PhoneComponents, auditCtx utils.AuditContext) models.SelfDeclaredPatchFieldResult {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	current, err := getCurrentPhoneData(ctx, cpf)
+	if err != nil {
+		logger.Error("failed to fetch current phone data for comparison", zap.Error(err))
+		return patchFieldError("Failed to check current data: " + err.Error())
+	}
+	if selfDeclaredPhoneMatchesVerified(current, phone) && !isSelfDeclaredOutdated(current.UpdatedAt) {
+		return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchUnchanged}
+	}
+
+	fullPhone, err := startSelfDeclaredPhoneVerification(ctx, cpf, phone)
+	if err != nil {
+		logger.Error("failed to create phone verification", zap.Error(err))
+		return patchFieldError("Failed to start phone verification: " + err.Error())
+	}
+
+	if err := utils.LogPhoneUpdate(ctx, auditCtx, currentPhoneAuditValue(current), fullPhone); err != nil {
+		logger.Warn("failed to log audit event", zap.Error(err))
+	}
+
+	return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchVerificationPending}
+}
+
+func patchFieldError(message string) models.SelfDeclaredPatchFieldResult {
+	return models.SelfDeclaredPatchFieldResult{Status: models.SelfDeclaredPatchError, Error: message}
+}
+
+// patchFieldVersionConflict reports a field rejected because another request changed the
+// self-declared data while the partial update was being applied
+func patchFieldVersionConflict(logger *logging.SafeLogger, err error) models.SelfDeclaredPatchFieldResult {
+	logger.Info("self-declared field update rejected by version precondition", zap.Error(err))
+	return patchFieldError("Precondition failed: self-declared data was modified by another request")
+}
+
+// advanceSelfDeclaredVersion makes the next field write of a conditional partial update
+// expect the version produced by the previous one
+func advanceSelfDeclaredVersion(expectedVersion *int32, version int32) {
+	if expectedVersion != nil {
+		*expectedVersion = version
+	}
+}
+
+// isSelfDeclaredOutdated reports whether self-declared data is old enough (or has no
+// timestamp) that re-declaring the same value is accepted
+func isSelfDeclaredOutdated(updatedAt *time.Time) bool {
+	return updatedAt == nil || time.Since(*updatedAt) > config.AppConfig.SelfDeclaredOutdatedThreshold
+}
+
+// selfDeclaredAddressMatches reports whether the (normalized) input is identical to the current
+// address once normalized the same way. Fields missing from partially-populated legacy records
+// count as different.
+func selfDeclaredAddressMatches(current *models.Endereco, input models.SelfDeclaredAddressInput) bool {
+	if current == nil || current.Principal == nil {
+		return false
+	}
+	principal := utils.NormalizeStoredAddress(current.Principal)
+	return equalStringPtr(principal.Bairro, &input.Bairro) &&
+		equalStringPtr(principal.CEP, &input.CEP) &&
+		equalStringPtr(principal.Complemento, input.Complemento) &&
+		equalStringPtr(principal.Estado, &input.Estado) &&
+		equalStringPtr(principal.Logradouro, &input.Logradouro) &&
+		equalStringPtr(principal.Municipio, &input.Municipio) &&
+		equalStringPtr(principal.Numero, &input.Numero) &&
+		equalStringPtr(principal.TipoLogradouro, input.TipoLogradouro)
+}
+
+// equalStringPtr reports whether a and b are both nil or both point to equal strings
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// buildSelfDeclaredAddress builds the self-declared address stored for the input
+func buildSelfDeclaredAddress(input models.SelfDeclaredAddressInput, now time.Time) models.Endereco {
+	origem := "self-declared"
+	sistema := "rmi"
+	return models.Endereco{
+		Indicador: utils.BoolPtr(true),
+		Principal: &models.EnderecoPrincipal{
+			Bairro:         &input.Bairro,
+			CEP:            &input.CEP,
+			Complemento:    input.Complemento,
+			Estado:         &input.Estado,
+			Logradouro:     &input.Logradouro,
+			Municipio:      &input.Municipio,
+			Numero:         &input.Numero,
+			TipoLogradouro: input.TipoLogradouro,
+			Origem:         &origem,
+			Sistema:        &sistema,
+			UpdatedAt:      &now,
+		},
+	}
+}
+
+// selfDeclaredAddressString formats a self-declared address the way it is hashed and sent to
+// the CF lookup
+func selfDeclaredAddressString(input models.SelfDeclaredAddressInput) string {
+	complemento := ""
+	if input.Complemento != nil {
+		complemento = *input.Complemento
+	}
+	return fmt.Sprintf("%s, %s, %s, %s, %s, %s",
+		input.Logradouro, input.Numero, complemento,
+		input.Bairro, input.Municipio, input.Estado)
+}
+
+// refreshCFDataForAddress invalidates CF data cached for the previous address and queues
+// a new CF lookup for the updated one (only if the CF lookup service is enabled)
+func refreshCFDataForAddress(ctx context.Context, cpf string, input models.SelfDeclaredAddressInput) {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	if services.CFLookupServiceInstance == nil {
+		logger.Debug("CF lookup service disabled - skipping CF data invalidation", zap.String("cpf", cpf))
+		return
+	}
+
+	newAddress := selfDeclaredAddressString(input)
+	newAddressHash := services.CFLookupServiceInstance.GenerateAddressHash(newAddress)
+
+	if err := services.CFLookupServiceInstance.InvalidateCFDataForAddress(ctx, cpf, newAddressHash); err != nil {
+		logger.Warn("failed to invalidate CF data for address change", zap.Error(err))
+		return
+	}
+
+	// Queue new CF lookup for the updated address
+	logger.Debug("queuing CF lookup for updated address", zap.String("cpf", cpf))
+	queueCFLookupJob(ctx, cpf, newAddress)
+}
+
+// selfDeclaredEmailMatches reports whether valor equals the current email
+func selfDeclaredEmailMatches(current *EmailDataWithTimestamp, valor string) bool {
+	return current != nil && current.Email != nil && current.Email.Principal != nil &&
+		current.Email.Principal.Valor != nil && *current.Email.Principal.Valor == valor
+}
+
+// selfDeclaredEmailMatchesVerified reports whether valor equals the current email and that
+// email has been verified
+func selfDeclaredEmailMatchesVerified(current *EmailDataWithTimestamp, valor string) bool {
+	return selfDeclaredEmailMatches(current, valor) &&
+		current.Email.Indicador != nil && *current.Email.Indicador
+}
+
+// currentEmailAuditValue formats the current email for audit logs
+func currentEmailAuditValue(current *EmailDataWithTimestamp) string {
+	if current == nil || current.Email == nil || current.Email.Principal == nil || current.Email.Principal.Valor == nil {
+		return "none"
+	}
+	return *current.Email.Principal.Valor
+}
+
+// buildSelfDeclaredEmail builds the self-declared email stored for valor
+func buildSelfDeclaredEmail(valor string, now time.Time) models.Email {
+	origem := "self-declared"
+	sistema := "rmi"
+	return models.Email{
+		Indicador: utils.BoolPtr(true),
+		Principal: &models.EmailPrincipal{
+			Valor:     &valor,
+			Origem:    &origem,
+			Sistema:   &sistema,
+			UpdatedAt: &now,
+		},
+	}
+}
+
+// selfDeclaredPhoneMatchesVerified reports whether the phone equals the current phone
+// and that phone is verified (Indicador == true)
+func selfDeclaredPhoneMatchesVerified(current *PhoneDataWithTimestamp, phone *utils.PhoneComponents) bool {
+	return current != nil && current.Telefone != nil && current.Telefone.Principal != nil &&
+		current.Telefone.Indicador != nil && *current.Telefone.Indicador &&
+		current.Telefone.Principal.DDI != nil && *current.Telefone.Principal.DDI == phone.DDI &&
+		current.Telefone.Principal.DDD != nil && *current.Telefone.Principal.DDD == phone.DDD &&
+		current.Telefone.Principal.Valor != nil && *current.Telefone.Principal.Valor == phone.Valor
+}
+
+// currentPhoneAuditValue formats the current phone for audit logs
+func currentPhoneAuditValue(current *PhoneDataWithTimestamp) string {
+	if current == nil || current.Telefone == nil || current.Telefone.Principal == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s%s%s",
+		*current.Telefone.Principal.DDI,
+		*current.Telefone.Principal.DDD,
+		*current.Telefone.Principal.Valor)
+}
+
+// startSelfDeclaredPhoneVerification replaces any pending verification for the CPF with a
+// new one for phone and returns the phone in storage format
+func startSelfDeclaredPhoneVerification(ctx context.Context, cpf string, phone *utils.PhoneComponents) (string, error) {
+	logger := observability.Logger().With(zap.String("cpf", cpf))
+
+	// Build full phone number in storage format (E.164 without "+", as used by phone mappings) with tracing
+	ctx, buildSpan := utils.TraceBusinessLogic(ctx, "build_phone_number")
+	fullPhone := utils.FormatPhoneForStorage(phone.DDI, phone.DDD, phone.Valor)
+	buildSpan.End()
+
+	// Generate verification code for phone verification process with tracing
+	ctx, codeSpan := utils.TraceBusinessLogic(ctx, "generate_verification_code")
+	code := utils.GenerateVerificationCode()
+	expiresAt := time.Now().Add(config.AppConfig.PhoneVerificationTTL)
+	codeSpan.End()
+
+	verificationData := utils.PhoneVerificationData{
+		CPF:         cpf,
+		DDI:         phone.DDI,
+		DDD:         phone.DDD,
+		Valor:       phone.Valor,
+		PhoneNumber: fullPhone,
+		Code:        code,
+		ExpiresAt:   expiresAt,
+	}
+
+	// Delete previous verifications with tracing
+	ctx, deleteSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.PhoneVerificationCollection, "cpf", false)
+	verColl := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
+	if _, err := verColl.DeleteMany(ctx, bson.M{"cpf": cpf}); err != nil {
+		utils.RecordErrorInSpan(deleteSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.PhoneVerificationCollection,
+			"db.operation":  "delete_many",
+		})
+		logger.Warn("failed to delete previous verifications", zap.Error(err))
+	}
+	deleteSpan.End()
+
+	// Create verification record with tracing
+	ctx, createSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.PhoneVerificationCollection, "cpf", false)
+	defer createSpan.End()
+	if err := utils.CreatePhoneVerification(ctx, verificationData); err != nil {
+		utils.RecordErrorInSpan(createSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.PhoneVerificationCollection,
+			"db.operation":  "create",
+		})
+		return "", err
+	}
+
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+	observability.PhoneVerificationRequestedTotal.Inc()
+	return fullPhone, nil
+}
+
+// startSelfDeclaredEmailVerification replaces any pending verification for the CPF with a new
+// one for valor. The email is kept as pending (not verified) until the token is validated.
+func startSelfDeclaredEmailVerification(ctx context.Context, cpf, valor string) error {
+	ctx, createSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.EmailVerificationCollection, "cpf", false)
+	defer createSpan.End()
+
+	pending := buildSelfDeclaredEmail(valor, time.Now())
+	pending.Indicador = utils.BoolPtr(false)
+	if err := services.StartEmailVerification(ctx, cpf, &pending); err != nil {
+		utils.RecordErrorInSpan(createSpan, err, map[string]interface{}{
+			"db.collection": config.AppConfig.EmailVerificationCollection,
+			"db.operation":  "create",
+		})
+		return err
+	}
+
+	observability.DatabaseOperations.WithLabelValues("update", "success").Inc()
+	return nil
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSelfDeclaredAddressMatches tests the address comparison used by PUT and PATCH
+func TestSelfDeclaredAddressMatches(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{
+		Bairro:     "Centro",
+		CEP:        "20000000",
+		Estado:     "RJ",
+		Logradouro: "Rua das Flores",
+		Municipio:  "Rio de Janeiro",
+		Numero:     "123",
+	}
+	current := buildSelfDeclaredAddress(input, time.Now())
+
+	assert.False(t, selfDeclaredAddressMatches(nil, input))
+	assert.True(t, selfDeclaredAddressMatches(&current, input))
+
+	changed := input
+	changed.Numero = "124"
+	assert.False(t, selfDeclaredAddressMatches(&current, changed))
+
+	withComplement := input
+	withComplement.Complemento = strPtr("Apto 401")
+	assert.False(t, selfDeclaredAddressMatches(&current, withComplement))
+}
+
+// TestSelfDeclaredAddressMatches_StoredBeforeNormalization tests that an address stored in
+// another format matches its normalized input
+func TestSelfDeclaredAddressMatches_StoredBeforeNormalization(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{
+		Bairro:     "Centro",
+		CEP:        "20000000",
+		Estado:     "RJ",
+		Logradouro: "Rua das Flores",
+		Municipio:  "Rio de Janeiro",
+		Numero:     "123",
+	}
+	stored := input
+	stored.Bairro = "CENTRO"
+	stored.CEP = "20000-000"
+	stored.Estado = "rj"
+	stored.Logradouro = "rua  das flores"
+	current := buildSelfDeclaredAddress(stored, time.Now())
+
+	assert.True(t, selfDeclaredAddressMatches(&current, input))
+	assert.Equal(t, "20000-000", *current.Principal.CEP, "comparison must not modify the stored address")
+}
+
+// TestSelfDeclaredAddressMatches_PartialLegacyRecord tests that records with missing fields
+// from older imports compare as different instead of panicking
+func TestSelfDeclaredAddressMatches_PartialLegacyRecord(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{
+		Bairro:     "Centro",
+		CEP:        "20000000",
+		Estado:     "RJ",
+		Logradouro: "Rua das Flores",
+		Municipio:  "Rio de Janeiro",
+		Numero:     "123",
+	}
+
+	assert.False(t, selfDeclaredAddressMatches(&models.Endereco{}, input))
+	assert.False(t, selfDeclaredAddressMatches(&models.Endereco{Principal: &models.EnderecoPrincipal{}}, input))
+
+	missingBairro := buildSelfDeclaredAddress(input, time.Now())
+	missingBairro.Principal.Bairro = nil
+	assert.NotPanics(t, func() {
+		assert.False(t, selfDeclaredAddressMatches(&missingBairro, input))
+	})
+
+	onlyCEP := &models.Endereco{Principal: &models.EnderecoPrincipal{CEP: strPtr("20000000")}}
+	assert.NotPanics(t, func() {
+		assert.False(t, selfDeclaredAddressMatches(onlyCEP, input))
+	})
+}
+
+// TestEqualStringPtr tests nil-safe string pointer comparison
+func TestEqualStringPtr(t *testing.T) {
+	assert.True(t, equalStringPtr(nil, nil))
+	assert.True(t, equalStringPtr(strPtr("a"), strPtr("a")))
+	assert.False(t, equalStringPtr(strPtr("a"), strPtr("b")))
+	assert.False(t, equalStringPtr(nil, strPtr("a")))
+	assert.False(t, equalStringPtr(strPtr("a"), nil))
+}
+
+// TestSelfDeclaredPhoneMatchesVerified tests that only a verified identical phone matches
+func TestSelfDeclaredPhoneMatchesVerified(t *testing.T) {
+	phone := &utils.PhoneComponents{DDI: "55", DDD: "21", Valor: "987654321"}
+	current := &PhoneDataWithTimestamp{
+		Telefone: &models.Telefone{
+			Indicador: utils.BoolPtr(true),
+			Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("987654321")},
+		},
+	}
+
+	assert.False(t, selfDeclaredPhoneMatchesVerified(nil, phone))
+	assert.True(t, selfDeclaredPhoneMatchesVerified(current, phone))
+	assert.False(t, selfDeclaredPhoneMatchesVerified(current, &utils.PhoneComponents{DDI: "55", DDD: "21", Valor: "912345678"}))
+
+	current.Telefone.Indicador = utils.BoolPtr(false)
+	assert.False(t, selfDeclaredPhoneMatchesVerified(current, phone))
+}
+
+// TestSelfDeclaredEmailMatchesVerified tests that only a verified identical email matches
+func TestSelfDeclaredEmailMatchesVerified(t *testing.T) {
+	current := &EmailDataWithTimestamp{Email: &models.Email{}}
+	*current.Email = buildSelfDeclaredEmail("maria@example.com", time.Now())
+
+	assert.False(t, selfDeclaredEmailMatchesVerified(nil, "maria@example.com"))
+	assert.True(t, selfDeclaredEmailMatchesVerified(current, "maria@example.com"))
+	assert.False(t, selfDeclaredEmailMatchesVerified(current, "joao@example.com"))
+
+	current.Email.Indicador = utils.BoolPtr(false)
+	assert.False(t, selfDeclaredEmailMatchesVerified(current, "maria@example.com"))
+	assert.True(t, selfDeclaredEmailMatches(current, "maria@example.com"))
+}
+
+// TestIsSelfDeclaredOutdated tests the re-declaration threshold check
+func TestIsSelfDeclaredOutdated(t *testing.T) {
+	recent := time.Now()
+	old := time.Now().Add(-365 * 24 * time.Hour)
+
+	assert.True(t, isSelfDeclaredOutdated(nil))
+	assert.False(t, isSelfDeclaredOutdated(&recent))
+	assert.True(t, isSelfDeclaredOutdated(&old))
+}
+
+// TestAdvanceSelfDeclaredVersion tests the version chaining between PATCH field writes
+func TestAdvanceSelfDeclaredVersion(t *testing.T) {
+	expected := int32(2)
+	advanceSelfDeclaredVersion(&expected, 3)
+	assert.Equal(t, int32(3), expected)
+
+	// Unconditional updates stay unconditional
+	advanceSelfDeclaredVersion(nil, 3)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.uber.org/zap"
+)
+
+// expectedSelfDeclaredVersion returns the self-declared version a conditional update is based
+// on, taken from the If-Match header (e.g. `"3"`, `3` or the `"3-<fields>"` ETag of a projected
+// response) or, when absent, the body's version field. Nil (no precondition, or If-Match: *)
+// means an unconditional update.
+func expectedSelfDeclaredVersion(c *gin.Context, bodyVersion *int32) (*int32, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return bodyVersion, nil
+	}
+	if header == "*" {
+		return nil, nil
+	}
+
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	value, _, _ = strings.Cut(value, "-")
+	version, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("invalid If-Match header: expected a self-declared version")
+	}
+	expected := int32(version)
+	return &expected, nil
+}
+
+// isSelfDeclaredVersionConflict reports whether an update was rejected because the
+// self-declared data changed since the version the client sent
+func isSelfDeclaredVersionConflict(err error) bool {
+	var lockErr utils.OptimisticLockError
+	return errors.As(err, &lockErr)
+}
+
+// respondSelfDeclaredVersionConflict answers a stale conditional update with 412
+func respondSelfDeclaredVersionConflict(c *gin.Context, logger *logging.SafeLogger, err error) {
+	logger.Info("self-declared update rejected by version precondition", zap.Error(err))
+	c.JSON(http.StatusPreconditionFailed, ErrorResponse{Code: ErrCodeVersionConflict, Message: "Precondition failed: self-declared data was modified by another request"})
+}
+
+// setSelfDeclaredVersionHeader exposes the current self-declared version as the ETag, to be
+// sent back in If-Match on the next update
+func setSelfDeclaredVersionHeader(c *gin.Context, version int32) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(int64(version), 10)))
+}
+
+// setProjectedSelfDeclaredVersionHeader is setSelfDeclaredVersionHeader for a response projected
+// with the fields query parameter: the version is followed by a hash of the fields, so each
+// projection has its own ETag, and it is still accepted in If-Match
+func setProjectedSelfDeclaredVersionHeader(c *gin.Context, version int32, fields []string) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(int64(version), 10)+"-"+projectionETagSuffix(fields)))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedSelfDeclaredVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bodyVersion := int32(5)
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		bodyVersion *int32
+		expected    *int32
+		wantErr     bool
+	}{
+		{name: "no precondition", expected: nil},
+		{name: "body version", bodyVersion: &bodyVersion, expected: &bodyVersion},
+		{name: "quoted header", ifMatch: `"3"`, expected: int32Ptr(3)},
+		{name: "unquoted header", ifMatch: "3", expected: int32Ptr(3)},
+		{name: "weak header", ifMatch: `W/"3"`, expected: int32Ptr(3)},
+		{name: "projected response header", ifMatch: `"3-1a2b3c4d"`, expected: int32Ptr(3)},
+		{name: "header wins over body", ifMatch: `"7"`, bodyVersion: &bodyVersion, expected: int32Ptr(7)},
+		{name: "wildcard is unconditional", ifMatch: "*", bodyVersion: &bodyVersion, expected: nil},
+		{name: "invalid header", ifMatch: `"abc"`, wantErr: true},
+		{name: "negative header", ifMatch: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			got, err := expectedSelfDeclaredVersion(c, tt.bodyVersion)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestIsSelfDeclaredVersionConflict(t *testing.T) {
+	conflict := utils.OptimisticLockError{Resource: "self_declared", Message: "expected version 1, but current version is 2"}
+
+	assert.True(t, isSelfDeclaredVersionConflict(conflict))
+	assert.True(t, isSelfDeclaredVersionConflict(fmt.Errorf("update failed: %w", conflict)))
+	assert.False(t, isSelfDeclaredVersionConflict(fmt.Errorf("redis unavailable")))
+	assert.False(t, isSelfDeclaredVersionConflict(nil))
+}
+
+func TestSetSelfDeclaredVersionHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	setSelfDeclaredVersionHeader(c, 4)
+
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/services"
+	"go.uber.org/zap"
+)
+
+// staleDataHeader marks GET responses served from cached data while MongoDB is unavailable
+const staleDataHeader = "X-Data-Stale"
+
+// readStaleCitizen serves the cached citizen when degraded reads are enabled and readErr means
+// MongoDB is unavailable. The merged copy cached by GetCitizenData is tried first, as the
+// DataManager cache layers were already missed by the read that reached MongoDB. It marks the
+// response as stale and returns false when there is nothing cached to fall back to.
+func readStaleCitizen(ctx context.Context, c *gin.Context, logger *logging.SafeLogger, cpf, resource string, readErr error) (*models.Citizen, bool) {
+	if !config.AppConfig.MongoDegradedReadsEnabled || !services.IsMongoUnavailable(readErr) {
+		return nil, false
+	}
+
+	var citizen models.Citizen
+	found := false
+	if data, err := config.Redis.Get(ctx, fmt.Sprintf("citizen:%s", cpf)).Result(); err == nil {
+		found = json.Unmarshal([]byte(data), &citizen) == nil
+	}
+	if !found {
+		dataManager := services.NewDataManager(config.Redis, config.MongoDB, observability.Logger())
+		if err := dataManager.ReadCacheOnly(ctx, cpf, "citizen", &citizen); err != nil {
+			logger.Warn("MongoDB unavailable and no cached citizen to serve",
+				zap.String("resource", resource),
+				zap.Error(readErr))
+			return nil, false
+		}
+	}
+
+	c.Header(staleDataHeader, "true")
+	observability.RMIServedStaleTotal.WithLabelValues(resource).Inc()
+	logger.Warn("MongoDB unavailable, serving stale cached data",
+		zap.String("resource", resource),
+		zap.Error(readErr))
+	return &citizen, true
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestGetCitizenWallet_ServesMergedCopyWhenMongoUnavailable(t *testing.T) {
+	if config.Redis == nil || config.MongoDB == nil {
+		t.Skip("Skipping stale data test: Redis or MongoDB not available")
+	}
+	ctx := context.Background()
+	cpf := "52998224725"
+
+	previousDegraded := config.AppConfig.MongoDegradedReadsEnabled
+	config.AppConfig.MongoDegradedReadsEnabled = true
+	defer func() { config.AppConfig.MongoDegradedReadsEnabled = previousDegraded }()
+
+	// A disconnected client fails every operation the way an unavailable MongoDB does
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(os.Getenv("MONGODB_URI")))
+	require.NoError(t, err)
+	require.NoError(t, client.Disconnect(ctx))
+	previousMongo := config.MongoDB
+	config.MongoDB = client.Database(previousMongo.Name())
+	defer func() { config.MongoDB = previousMongo }()
+
+	// Only the merged copy cached by GetCitizenData is available
+	keys := []string{"citizen:" + cpf, "citizen:write:" + cpf, "citizen:cache:" + cpf}
+	require.NoError(t, config.Redis.Del(ctx, keys...).Err())
+	defer config.Redis.Del(ctx, keys...)
+	merged, err := json.Marshal(models.Citizen{CPF: cpf, Documentos: &models.Documentos{CNS: []string{"123456789012345"}}})
+	require.NoError(t, err)
+	require.NoError(t, config.Redis.Set(ctx, "citizen:"+cpf, string(merged), 0).Err())
+
+	r := setupRouter()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/citizen/"+cpf+"/wallet?sections=documentos", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(staleDataHeader))
+	var wallet models.CitizenWallet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &wallet))
+	require.NotNil(t, wallet.Documentos)
+	assert.Equal(t, []string{"123456789012345"}, wallet.Documentos.CNS)
+}
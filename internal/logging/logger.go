@@ -11,6 +11,7 @@ import (
 // SafeLogger wraps a zap.Logger and always provides a valid logger
 // If the underlying logger is nil, it uses a no-op logger
 // All logging methods are safe to call even if not initialized
+// CPF and phone fields are masked when SetPIIMasking is enabled
 
 type SafeLogger struct {
 	logger *zap.Logger
@@ -67,42 +68,42 @@ func InitLogger() error {
 // Info logs an info message
 func (l *SafeLogger) Info(msg string, fields ...zap.Field) {
 	if l != nil && l.logger != nil {
-		l.logger.Info(msg, fields...)
+		l.logger.Info(msg, maskPIIFields(fields)...)
 	}
 }
 
 // Warn logs a warning message
 func (l *SafeLogger) Warn(msg string, fields ...zap.Field) {
 	if l != nil && l.logger != nil {
-		l.logger.Warn(msg, fields...)
+		l.logger.Warn(msg, maskPIIFields(fields)...)
 	}
 }
 
 // Debug logs a debug message
 func (l *SafeLogger) Debug(msg string, fields ...zap.Field) {
 	if l != nil && l.logger != nil {
-		l.logger.Debug(msg, fields...)
+		l.logger.Debug(msg, maskPIIFields(fields)...)
 	}
 }
 
 // Error logs an error message
 func (l *SafeLogger) Error(msg string, fields ...zap.Field) {
 	if l != nil && l.logger != nil {
-		l.logger.Error(msg, fields...)
+		l.logger.Error(msg, maskPIIFields(fields)...)
 	}
 }
 
 // Fatal logs a fatal message and exits
 func (l *SafeLogger) Fatal(msg string, fields ...zap.Field) {
 	if l != nil && l.logger != nil {
-		l.logger.Fatal(msg, fields...)
+		l.logger.Fatal(msg, maskPIIFields(fields)...)
 	}
 }
 
 // With returns a new SafeLogger with additional fields
 func (l *SafeLogger) With(fields ...zap.Field) *SafeLogger {
 	if l != nil && l.logger != nil {
-		return &SafeLogger{logger: l.logger.With(fields...)}
+		return &SafeLogger{logger: l.logger.With(maskPIIFields(fields)...)}
 	}
 	return l
 }
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// piiMasking controls whether SafeLogger masks PII fields, set from config.AppConfig.LogPIIMasking
+var piiMasking atomic.Bool
+
+// piiFieldMaskers maps the field keys used for CPFs and phone numbers to their masking function
+var piiFieldMaskers = map[string]func(string) string{
+	"cpf":          MaskedCPF,
+	"phone":        MaskedPhone,
+	"phone_number": MaskedPhone,
+	"new_phone":    MaskedPhone,
+	"path":         MaskedURLPart,
+	"query":        MaskedURLPart,
+}
+
+// urlPIIPattern matches the CPFs (plain or formatted) and phone numbers embedded in request paths
+// and query strings, e.g. /v1/citizen/45049725810/wallet
+var urlPIIPattern = regexp.MustCompile(`\d{3}\.\d{3}\.\d{3}-\d{2}|\d{8,}`)
+
+// SetPIIMasking enables or disables masking of CPF and phone fields in all SafeLoggers
+func SetPIIMasking(enabled bool) {
+	piiMasking.Store(enabled)
+}
+
+// PIIMaskingEnabled reports whether CPF and phone fields are masked
+func PIIMaskingEnabled() bool {
+	return piiMasking.Load()
+}
+
+// MaskedCPF masks a CPF for logging, keeping the first 3 and the check digits (e.g., "45049725810" -> "450******10")
+func MaskedCPF(cpf string) string {
+	digits := onlyDigits(cpf)
+	if len(digits) != 11 {
+		return "***********"
+	}
+	return digits[:3] + "******" + digits[9:]
+}
+
+// MaskedPhone masks all but the last 4 digits of a phone number (e.g., "5521987654321" -> "*********4321")
+func MaskedPhone(phone string) string {
+	digits := onlyDigits(phone)
+	if len(digits) <= 4 {
+		return strings.Repeat("*", len(digits))
+	}
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+// MaskedURLPart masks the CPFs and phone numbers in a request path or query string, keeping the
+// rest so the route stays recognizable (e.g., "/v1/citizen/45049725810/wallet" -> "/v1/citizen/***********/wallet")
+func MaskedURLPart(value string) string {
+	return urlPIIPattern.ReplaceAllStringFunc(value, func(match string) string {
+		return strings.Repeat("*", len(match))
+	})
+}
+
+// maskPIIFields returns fields with CPF and phone values masked when masking is enabled.
+// The slice is only copied when a field actually needs masking.
+func maskPIIFields(fields []zap.Field) []zap.Field {
+	if !piiMasking.Load() {
+		return fields
+	}
+
+	var masked []zap.Field
+	for i, field := range fields {
+		mask, ok := piiFieldMaskers[field.Key]
+		if !ok || field.Type != zapcore.StringType {
+			continue
+		}
+		if masked == nil {
+			masked = make([]zap.Field, len(fields))
+			copy(masked, fields)
+		}
+		masked[i] = zap.String(field.Key, mask(field.String))
+	}
+	if masked == nil {
+		return fields
+	}
+	return masked
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMaskedCPF(t *testing.T) {
+	assert.Equal(t, "450******10", MaskedCPF("45049725810"))
+	assert.Equal(t, "450******10", MaskedCPF("450.497.258-10"))
+	assert.Equal(t, "***********", MaskedCPF("123"))
+}
+
+func TestMaskedPhone(t *testing.T) {
+	assert.Equal(t, "*********4321", MaskedPhone("5521987654321"))
+	assert.Equal(t, "*********4321", MaskedPhone("+55 (21) 98765-4321"))
+	assert.Equal(t, "***", MaskedPhone("123"))
+}
+
+func TestMaskedURLPart(t *testing.T) {
+	assert.Equal(t, "/v1/citizen/***********/wallet", MaskedURLPart("/v1/citizen/45049725810/wallet"))
+	assert.Equal(t, "/v1/citizen/**************", MaskedURLPart("/v1/citizen/450.497.258-10"))
+	assert.Equal(t, "/v1/phone/*************/status", MaskedURLPart("/v1/phone/5521987654321/status"))
+	assert.Equal(t, "page=2&per_page=50&cpf=***********", MaskedURLPart("page=2&per_page=50&cpf=45049725810"))
+	assert.Equal(t, "/v1/health", MaskedURLPart("/v1/health"))
+}
+
+func TestSafeLogger_PIIMasking(t *testing.T) {
+	defer SetPIIMasking(false)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := &SafeLogger{logger: zap.New(core)}
+
+	SetPIIMasking(false)
+	logger.Debug("unmasked", zap.String("cpf", "45049725810"))
+	assert.Equal(t, "45049725810", logs.TakeAll()[0].ContextMap()["cpf"])
+
+	SetPIIMasking(true)
+	logger.With(zap.String("cpf", "45049725810")).Info("masked",
+		zap.String("phone_number", "5521987654321"),
+		zap.String("path", "/v1/citizen/45049725810/wallet"),
+		zap.String("status", "active"),
+		zap.Int("cpf_count", 1))
+
+	fields := logs.TakeAll()[0].ContextMap()
+	assert.Equal(t, "450******10", fields["cpf"])
+	assert.Equal(t, "*********4321", fields["phone_number"])
+	assert.Equal(t, "/v1/citizen/***********/wallet", fields["path"])
+	assert.Equal(t, "active", fields["status"])
+	assert.Equal(t, int64(1), fields["cpf_count"])
+}
+
+func TestMaskPIIFields_DoesNotModifyInput(t *testing.T) {
+	defer SetPIIMasking(false)
+	SetPIIMasking(true)
+
+	fields := []zap.Field{zap.String("cpf", "45049725810")}
+	masked := maskPIIFields(fields)
+
+	assert.Equal(t, "450******10", masked[0].String)
+	assert.Equal(t, "45049725810", fields[0].String)
+}
@@ -11,6 +11,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// nonAuditedRouteSuffixes are routes served with a write method that don't change any data,
+// such as previews, so they aren't recorded as changes
+var nonAuditedRouteSuffixes = []string{"/address/preview"}
+
+// logAuditEvent records an audit event; replaced in tests
+var logAuditEvent = utils.LogAuditEvent
+
 // AuditMiddleware logs all PUT/POST/DELETE requests automatically
 // This ensures comprehensive audit trail for all write operations
 func AuditMiddleware() gin.HandlerFunc {
@@ -29,6 +36,12 @@ func AuditMiddleware() gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		for _, suffix := range nonAuditedRouteSuffixes {
+			if strings.HasSuffix(c.FullPath(), suffix) {
+				c.Next()
+				return
+			}
+		}
 
 		// Extract CPF from various sources
 		cpf := extractCPFFromRequest(c)
@@ -85,7 +98,7 @@ func AuditMiddleware() gin.HandlerFunc {
 			metadata["response_status"] = string(rune(status))
 
 			// Log audit event asynchronously
-			if err := utils.LogAuditEvent(c.Request.Context(), auditCtx, action, resource, resourceID, nil, nil, metadata); err != nil {
+			if err := logAuditEvent(c.Request.Context(), auditCtx, action, resource, resourceID, nil, nil, metadata); err != nil {
 				observability.Logger().Warn("failed to log audit event",
 					zap.Error(err),
 					zap.String("endpoint", path),
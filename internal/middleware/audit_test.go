@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
 )
 
 func TestAuditMiddleware_SkipsGETRequests(t *testing.T) {
@@ -65,6 +67,46 @@ func TestAuditMiddleware_SkipsMetrics(t *testing.T) {
 	}
 }
 
+// recordAuditEvents replaces the audit logger for the test and returns the recorded endpoints
+func recordAuditEvents(t *testing.T) *[]string {
+	t.Helper()
+	var endpoints []string
+	previous := logAuditEvent
+	logAuditEvent = func(_ context.Context, _ utils.AuditContext, _, _, _ string, _, _ interface{}, metadata map[string]string) error {
+		endpoints = append(endpoints, metadata["endpoint"])
+		return nil
+	}
+	t.Cleanup(func() { logAuditEvent = previous })
+	return &endpoints
+}
+
+func TestAuditMiddleware_SkipsAddressPreview(t *testing.T) {
+	endpoints := recordAuditEvents(t)
+	router := gin.New()
+	router.Use(AuditMiddleware())
+	router.POST("/v1/citizen/:cpf/address/preview", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"valid": true})
+	})
+	router.PUT("/v1/citizen/:cpf/address", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/v1/citizen/12345678901/address/preview", bytes.NewBufferString(`{"cep":"20000000"}`)),
+		httptest.NewRequest("PUT", "/v1/citizen/12345678901/address", bytes.NewBufferString(`{"cep":"20000000"}`)),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s status = %v, want %v", req.Method, req.URL.Path, w.Code, http.StatusOK)
+		}
+	}
+
+	if len(*endpoints) != 1 || (*endpoints)[0] != "/v1/citizen/12345678901/address" {
+		t.Errorf("audited endpoints = %v, want only the address update", *endpoints)
+	}
+}
+
 func TestAuditMiddleware_POSTRequest(t *testing.T) {
 	router := gin.New()
 	router.Use(AuditMiddleware())
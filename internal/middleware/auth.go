@@ -14,7 +14,9 @@ import (
 	"go.uber.org/zap"
 )
 
-// AuthMiddleware extracts and validates JWT claims from the request
+// AuthMiddleware extracts and validates JWT claims from the request. Tokens from
+// SERVICE_TOKEN_ISSUER are service tokens: their signature is verified here and they are only
+// accepted by routes guarded with RequireScope.
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
@@ -49,6 +51,16 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		observability.Logger().Debug("successfully extracted claims from JWT token", zap.String("user_sub", claims.SUB))
 
+		if isServiceToken(claims) {
+			if err := verifyServiceToken(c.Request.Context(), token, claims); err != nil {
+				observability.Logger().Warn("rejected service token", zap.Error(err), zap.String("azp", claims.AZP))
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+			c.Set(serviceTokenKey, true)
+		}
+
 		// Store claims in context for later use
 		c.Set("claims", claims)
 		c.Next()
@@ -132,6 +144,13 @@ func RequireAdmin() gin.HandlerFunc {
 			return
 		}
 
+		// Service tokens only reach routes guarded by RequireScope
+		if IsServiceToken(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Service tokens are not allowed on this route"})
+			c.Abort()
+			return
+		}
+
 		// Check if user has admin role in RealmAccess or ResourceAccess.Superapp
 		isAdmin := false
 		for _, role := range jwtClaims.RealmAccess.Roles {
@@ -176,6 +195,13 @@ func RequireOwnCPF() gin.HandlerFunc {
 			return
 		}
 
+		// Service tokens only reach routes guarded by RequireScope
+		if IsServiceToken(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Service tokens are not allowed on this route"})
+			c.Abort()
+			return
+		}
+
 		// Get the CPF from the URL
 		requestedCPF := c.Param("cpf")
 		userCPF := jwtClaims.PreferredUsername
@@ -245,5 +271,52 @@ func IsAdmin(c *gin.Context) (bool, error) {
 	return false, nil
 }
 
+// ShouldMaskResponse checks if the caller's token carries a scope configured for masked
+// citizen responses. Users accessing their own CPF always receive unmasked data.
+func ShouldMaskResponse(c *gin.Context) bool {
+	if len(config.AppConfig.MaskedResponseScopes) == 0 {
+		return false
+	}
+
+	claims, exists := c.Get("claims")
+	if !exists {
+		return false
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		return false
+	}
+
+	if cpf := c.Param("cpf"); cpf != "" && cpf == jwtClaims.PreferredUsername {
+		return false
+	}
+
+	return HasAnyScope(c, config.AppConfig.MaskedResponseScopes)
+}
+
+// HasAnyScope checks if the caller's token carries at least one of the given scopes
+func HasAnyScope(c *gin.Context, scopes []string) bool {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return false
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		return false
+	}
+
+	for _, scope := range strings.Fields(jwtClaims.Scope) {
+		for _, wanted := range scopes {
+			if scope == wanted {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // ErrAccessDenied is returned when access is denied
 var ErrAccessDenied = fmt.Errorf("access denied")
@@ -19,7 +19,8 @@ func init() {
 
 	if config.AppConfig == nil {
 		config.AppConfig = &config.Config{
-			AdminGroup: "go:admin",
+			AdminGroup:            "go:admin",
+			TrustInboundRequestID: true,
 		}
 	}
 }
@@ -337,6 +338,54 @@ func TestIsAdmin_NoClaims(t *testing.T) {
 	}
 }
 
+func TestShouldMaskResponse(t *testing.T) {
+	original := config.AppConfig.MaskedResponseScopes
+	config.AppConfig.MaskedResponseScopes = []string{"citizen:masked"}
+	defer func() { config.AppConfig.MaskedResponseScopes = original }()
+
+	tests := []struct {
+		name   string
+		scope  string
+		user   string
+		cpf    string
+		claims bool
+		want   bool
+	}{
+		{name: "Masked scope accessing other CPF", scope: "openid citizen:masked", user: "service-account", cpf: "12345678901", claims: true, want: true},
+		{name: "Masked scope accessing own CPF", scope: "openid citizen:masked", user: "12345678901", cpf: "12345678901", claims: true, want: false},
+		{name: "No masked scope", scope: "openid profile", user: "service-account", cpf: "12345678901", claims: true, want: false},
+		{name: "Scope prefix does not match", scope: "citizen:masked-extra", user: "service-account", cpf: "12345678901", claims: true, want: false},
+		{name: "No claims", cpf: "12345678901", claims: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Params = gin.Params{{Key: "cpf", Value: tt.cpf}}
+			if tt.claims {
+				c.Set("claims", &models.JWTClaims{PreferredUsername: tt.user, Scope: tt.scope})
+			}
+
+			if got := ShouldMaskResponse(c); got != tt.want {
+				t.Errorf("ShouldMaskResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldMaskResponse_NoScopesConfigured(t *testing.T) {
+	original := config.AppConfig.MaskedResponseScopes
+	config.AppConfig.MaskedResponseScopes = nil
+	defer func() { config.AppConfig.MaskedResponseScopes = original }()
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set("claims", &models.JWTClaims{PreferredUsername: "service-account", Scope: "citizen:masked"})
+
+	if ShouldMaskResponse(c) {
+		t.Error("ShouldMaskResponse() = true, want false when no masked scopes are configured")
+	}
+}
+
 func TestMin(t *testing.T) {
 	tests := []struct {
 		name string
@@ -358,3 +407,31 @@ func TestMin(t *testing.T) {
 		})
 	}
 }
+
+func TestHasAnyScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  string
+		scopes []string
+		claims bool
+		want   bool
+	}{
+		{name: "Matching scope", scope: "openid phone:export", scopes: []string{"phone:export"}, claims: true, want: true},
+		{name: "No matching scope", scope: "openid profile", scopes: []string{"phone:export"}, claims: true, want: false},
+		{name: "No scopes wanted", scope: "openid phone:export", claims: true, want: false},
+		{name: "No claims", scopes: []string{"phone:export"}, claims: false, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.claims {
+				c.Set("claims", &models.JWTClaims{PreferredUsername: "service-account", Scope: tt.scope})
+			}
+
+			if got := HasAnyScope(c, tt.scopes); got != tt.want {
+				t.Errorf("HasAnyScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
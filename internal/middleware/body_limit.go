@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrCodeRequestBodyTooLarge is returned when a request body exceeds the route's size limit
+const ErrCodeRequestBodyTooLarge = "REQUEST_BODY_TOO_LARGE"
+
+// ErrorResponse is the error body returned by middleware, with the same shape as the handlers'
+// ErrorResponse (which middleware can't import): a machine-readable code and a message, mirrored
+// in the deprecated error field for clients that haven't moved to code/message yet.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// newErrorResponse builds an ErrorResponse with the legacy error field in sync with message
+func newErrorResponse(code, message string) ErrorResponse {
+	return ErrorResponse{Code: code, Message: message, Error: message}
+}
+
+// MaxBodySize limits request bodies to limit bytes. routeLimits overrides the limit for the
+// routes it lists, keyed by gin route path (e.g. /v1/citizen/:cpf/avatar); a limit of 0 disables
+// it. Requests whose Content-Length exceeds the limit are rejected with 413; bodies without a
+// declared length (chunked) are read up front through http.MaxBytesReader, so going past the limit
+// is a 413 too rather than a read error surfacing as a 400 in the handler.
+// Must be registered before any middleware that reads the body.
+func MaxBodySize(limit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routeLimit := limit
+		if override, ok := routeLimits[c.FullPath()]; ok {
+			routeLimit = override
+		}
+		if routeLimit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > routeLimit {
+			abortBodyTooLarge(c, routeLimit)
+			return
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)
+		if c.Request.ContentLength < 0 {
+			data, err := io.ReadAll(body)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortBodyTooLarge(c, routeLimit)
+				return
+			}
+			if err != nil {
+				// The client went away or sent a malformed chunked body
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(data))
+			c.Next()
+			return
+		}
+
+		c.Request.Body = body
+		c.Next()
+	}
+}
+
+// abortBodyTooLarge rejects the request with 413 and the route's limit
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, newErrorResponse(
+		ErrCodeRequestBodyTooLarge,
+		fmt.Sprintf("Request body must have at most %d bytes", limit),
+	))
+}
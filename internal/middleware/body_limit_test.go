@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupBodyLimitRouter(limit int64, routeLimits map[string]int64) *gin.Engine {
+	router := gin.New()
+	router.Use(MaxBodySize(limit, routeLimits))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"size": len(body)})
+	}
+	router.PUT("/citizen/:cpf/address", echo)
+	router.PUT("/citizen/:cpf/avatar", echo)
+	return router
+}
+
+func sendBody(router *gin.Engine, path string, size int, chunked bool) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(strings.Repeat("a", size)))
+	if chunked {
+		req.ContentLength = -1
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMaxBodySize_WithinLimit(t *testing.T) {
+	router := setupBodyLimitRouter(100, nil)
+
+	w := sendBody(router, "/citizen/12345678901/address", 100, false)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestMaxBodySize_ContentLengthExceeded(t *testing.T) {
+	router := setupBodyLimitRouter(100, nil)
+
+	w := sendBody(router, "/citizen/12345678901/address", 101, false)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", w.Code)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["code"] != ErrCodeRequestBodyTooLarge || body["message"] == "" || body["error"] != body["message"] {
+		t.Errorf("response = %v, want code %s with message and error", body, ErrCodeRequestBodyTooLarge)
+	}
+}
+
+func TestMaxBodySize_UndeclaredLengthExceeded(t *testing.T) {
+	router := setupBodyLimitRouter(100, nil)
+
+	w := sendBody(router, "/citizen/12345678901/address", 101, true)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413 for a chunked body past the limit", w.Code)
+	}
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Code != ErrCodeRequestBodyTooLarge || body.Message == "" {
+		t.Errorf("response = %+v, want code %s with a message", body, ErrCodeRequestBodyTooLarge)
+	}
+}
+
+func TestMaxBodySize_UndeclaredLengthWithinLimit(t *testing.T) {
+	router := setupBodyLimitRouter(100, nil)
+
+	w := sendBody(router, "/citizen/12345678901/address", 100, true)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"size":100`) {
+		t.Errorf("response = %d %s, want 200 with the whole chunked body passed on", w.Code, w.Body.String())
+	}
+}
+
+func TestMaxBodySize_RouteOverride(t *testing.T) {
+	router := setupBodyLimitRouter(100, map[string]int64{
+		"/citizen/:cpf/avatar":  1000,
+		"/citizen/:cpf/address": 10,
+	})
+
+	if w := sendBody(router, "/citizen/12345678901/avatar", 500, false); w.Code != http.StatusOK {
+		t.Errorf("avatar status = %d, want 200 under the raised limit", w.Code)
+	}
+	if w := sendBody(router, "/citizen/12345678901/address", 50, false); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("address status = %d, want 413 under the lowered limit", w.Code)
+	}
+}
+
+func TestMaxBodySize_Disabled(t *testing.T) {
+	router := setupBodyLimitRouter(0, nil)
+
+	if w := sendBody(router, "/citizen/12345678901/address", 10000, false); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with the limit disabled", w.Code)
+	}
+}
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+)
+
+// Supported response encodings
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressor is the part of gzip.Writer and brotli.Writer the middleware uses
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// Compression compresses response bodies with Brotli or gzip, as negotiated with the client's
+// Accept-Encoding header. Bodies are buffered until they reach COMPRESSION_MIN_SIZE, so small
+// responses go out unchanged. Only text-like content types are compressed; streams
+// (text/event-stream) and paths in COMPRESSION_EXCLUDED_PATHS never are. ETags are computed
+// by handlers from the uncompressed data, so conditional requests behave the same either way.
+func Compression() gin.HandlerFunc {
+	cfg := config.AppConfig
+	if !cfg.CompressionEnabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	pools := map[string]*sync.Pool{
+		encodingBrotli: {New: func() interface{} {
+			return brotli.NewWriterLevel(io.Discard, cfg.CompressionBrotliLevel)
+		}},
+		encodingGzip: {New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, cfg.CompressionGzipLevel)
+			return w
+		}},
+	}
+
+	return func(c *gin.Context) {
+		if slices.Contains(cfg.CompressionExcludedPaths, c.Request.URL.Path) || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		// The response may differ by Accept-Encoding, so caches must key on it
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &compressionWriter{
+			ResponseWriter: original,
+			encoding:       encoding,
+			pool:           pools[encoding],
+			minSize:        cfg.CompressionMinSize,
+		}
+		c.Writer = writer
+		defer func() { c.Writer = original }()
+
+		c.Next()
+		writer.finish()
+	}
+}
+
+// negotiateEncoding picks the response encoding from an Accept-Encoding header: the supported
+// encoding with the highest quality value, preferring Brotli on ties. Returns "" when the
+// client accepts neither.
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQuality := "", 0.0
+	for _, candidate := range []string{encodingBrotli, encodingGzip} {
+		quality := encodingQuality(acceptEncoding, candidate)
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best
+}
+
+// encodingQuality returns the quality value the header gives an encoding, directly or through
+// the "*" wildcard; 0 means not acceptable
+func encodingQuality(acceptEncoding, encoding string) float64 {
+	wildcard, found := 0.0, false
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if name == encoding {
+			return quality
+		}
+		wildcard, found = quality, true
+	}
+	if found {
+		return wildcard
+	}
+	return 0
+}
+
+// isCompressibleContentType reports whether a response content type benefits from compression
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/event-stream":
+		// Streams are flushed event by event; compressing them would delay delivery
+		return false
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/xml",
+		mediaType == "application/javascript",
+		mediaType == "application/x-yaml":
+		return true
+	}
+	return false
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	n, err := w.Writer.Write(data)
+	w.n += n
+	return n, err
+}
+
+// compressionWriter buffers the start of the body until it knows whether the response is worth
+// compressing, then either streams it through a compressor or writes it unchanged
+type compressionWriter struct {
+	gin.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	buf        bytes.Buffer
+	decided    bool
+	compressor compressor
+	wire       *countingWriter
+	original   int
+	elapsed    time.Duration
+}
+
+func (w *compressionWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf.Write(data)
+		if w.buf.Len() < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	return w.write(data)
+}
+
+func (w *compressionWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written also counts the buffered body, which will be written when the handler returns
+func (w *compressionWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what was written so far; a body flushed before reaching the minimum size is sent
+// uncompressed
+func (w *compressionWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer, so http.ResponseController reaches the connection, e.g. to
+// lift the write deadline of event streams
+func (w *compressionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide chooses whether to compress, based on the buffered body, and writes the buffer out
+func (w *compressionWriter) decide() error {
+	w.decided = true
+	if w.buf.Len() > 0 && w.buf.Len() >= w.minSize && w.compressible() {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		w.wire = &countingWriter{Writer: w.ResponseWriter}
+		w.compressor = w.pool.Get().(compressor)
+		w.compressor.Reset(w.wire)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// compressible reports whether the response status, headers and content type allow compression
+func (w *compressionWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return isCompressibleContentType(header.Get("Content-Type"))
+}
+
+func (w *compressionWriter) write(data []byte) (int, error) {
+	if w.compressor == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	start := time.Now()
+	n, err := w.compressor.Write(data)
+	w.elapsed += time.Since(start)
+	w.original += n
+	return n, err
+}
+
+// finish writes out a body still below the minimum size and completes the compressed stream
+func (w *compressionWriter) finish() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.compressor == nil {
+		return
+	}
+
+	start := time.Now()
+	_ = w.compressor.Close()
+	w.elapsed += time.Since(start)
+	w.compressor.Reset(io.Discard)
+	w.pool.Put(w.compressor)
+	w.compressor = nil
+
+	observability.HTTPResponseCompressionBytes.WithLabelValues(w.encoding, "uncompressed").Add(float64(w.original))
+	observability.HTTPResponseCompressionBytes.WithLabelValues(w.encoding, "compressed").Add(float64(w.wire.n))
+	observability.HTTPResponseCompressionDuration.WithLabelValues(w.encoding).Observe(w.elapsed.Seconds())
+}
@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+// compressionTestPayload is a wallet-like JSON body well above the minimum size
+func compressionTestPayload() gin.H {
+	items := make([]gin.H, 50)
+	for i := range items {
+		items[i] = gin.H{"id": i, "tipo": "reparo de iluminação", "status": "Aberto", "endereco": "Rua Afonso Cavalcanti, 455 - Cidade Nova"}
+	}
+	return gin.H{"data": items}
+}
+
+func setupCompressionRouter(t *testing.T, cfg *config.Config) *gin.Engine {
+	original := config.AppConfig
+	config.AppConfig = cfg
+	t.Cleanup(func() { config.AppConfig = original })
+
+	router := gin.New()
+	router.Use(Compression())
+	router.GET("/large", func(c *gin.Context) {
+		c.Header("ETag", `"7"`)
+		c.JSON(http.StatusOK, compressionTestPayload())
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/not-modified", func(c *gin.Context) {
+		c.Header("ETag", `"7"`)
+		c.Status(http.StatusNotModified)
+	})
+	router.GET("/metrics", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("metric 1\n", 500))
+	})
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, strings.Repeat("data: {}\n\n", 500))
+		c.Writer.Flush()
+	})
+	return router
+}
+
+func compressionTestConfig() *config.Config {
+	return &config.Config{
+		CompressionEnabled:       true,
+		CompressionMinSize:       1024,
+		CompressionGzipLevel:     5,
+		CompressionBrotliLevel:   4,
+		CompressionExcludedPaths: []string{"/metrics"},
+	}
+}
+
+func sendCompressionRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decompress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	default:
+		return body
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompressing %s body: %v", encoding, err)
+	}
+	return data
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0, gzip;q=0.1", "gzip"},
+		{"*", "br"},
+		{"*;q=0.5, br;q=0", "gzip"},
+		{"GZIP", "gzip"},
+		{"gzip;q=0", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompression_LargeResponses(t *testing.T) {
+	router := setupCompressionRouter(t, compressionTestConfig())
+	want, _ := json.Marshal(compressionTestPayload())
+
+	for _, encoding := range []string{"gzip", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			w := sendCompressionRequest(router, "/large", encoding)
+
+			if got := w.Header().Get("Content-Encoding"); got != encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, encoding)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			// The ETag identifies the uncompressed representation and is kept as is
+			if got := w.Header().Get("ETag"); got != `"7"` {
+				t.Errorf("ETag = %q, want \"7\"", got)
+			}
+			if w.Body.Len() >= len(want) {
+				t.Errorf("compressed body has %d bytes, uncompressed %d", w.Body.Len(), len(want))
+			}
+			if got := decompress(t, encoding, w.Body.Bytes()); !bytes.Equal(got, want) {
+				t.Errorf("decompressed body differs from the handler's")
+			}
+		})
+	}
+}
+
+func TestCompression_Skipped(t *testing.T) {
+	router := setupCompressionRouter(t, compressionTestConfig())
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+	}{
+		{"client without Accept-Encoding", "/large", ""},
+		{"body below minimum size", "/small", "gzip, br"},
+		{"not modified", "/not-modified", "gzip, br"},
+		{"excluded path", "/metrics", "gzip, br"},
+		{"event stream", "/events", "gzip, br"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendCompressionRequest(router, tt.path, tt.acceptEncoding)
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+		})
+	}
+
+	w := sendCompressionRequest(router, "/small", "gzip")
+	if w.Body.String() != `{"ok":true}` {
+		t.Errorf("small body = %q, want it unchanged", w.Body.String())
+	}
+}
+
+func TestCompression_Disabled(t *testing.T) {
+	cfg := compressionTestConfig()
+	cfg.CompressionEnabled = false
+	router := setupCompressionRouter(t, cfg)
+
+	w := sendCompressionRequest(router, "/large", "gzip, br")
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none when disabled", got)
+	}
+}
+
+// BenchmarkCompression measures the CPU cost of each encoding and level against the bytes it
+// saves on a wallet-like payload; compare ns/op with the reported compression ratio
+func BenchmarkCompression(b *testing.B) {
+	body, _ := json.Marshal(compressionTestPayload())
+
+	cases := []struct {
+		name string
+		new  func(io.Writer) io.WriteCloser
+	}{
+		{"gzip-1", func(w io.Writer) io.WriteCloser { gz, _ := gzip.NewWriterLevel(w, 1); return gz }},
+		{"gzip-5", func(w io.Writer) io.WriteCloser { gz, _ := gzip.NewWriterLevel(w, 5); return gz }},
+		{"gzip-9", func(w io.Writer) io.WriteCloser { gz, _ := gzip.NewWriterLevel(w, 9); return gz }},
+		{"br-1", func(w io.Writer) io.WriteCloser { return brotli.NewWriterLevel(w, 1) }},
+		{"br-4", func(w io.Writer) io.WriteCloser { return brotli.NewWriterLevel(w, 4) }},
+		{"br-11", func(w io.Writer) io.WriteCloser { return brotli.NewWriterLevel(w, 11) }},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			var out bytes.Buffer
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				out.Reset()
+				w := tc.new(&out)
+				w.Write(body)
+				w.Close()
+			}
+			b.ReportMetric(float64(len(body))/float64(out.Len()), "ratio")
+			b.ReportMetric(float64(out.Len()), "compressed-bytes")
+		})
+	}
+}
+
+func TestCompression_StreamWriteDeadline(t *testing.T) {
+	original := config.AppConfig
+	config.AppConfig = compressionTestConfig()
+	t.Cleanup(func() { config.AppConfig = original })
+
+	// Event streams lift the server write timeout through http.ResponseController, which must
+	// reach the connection behind the compression writer
+	router := gin.New()
+	router.Use(Compression())
+	router.GET("/events", func(c *gin.Context) {
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: {}\n\n")
+		c.Writer.Flush()
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d (%s), want the write deadline cleared", resp.StatusCode, body)
+	}
+}
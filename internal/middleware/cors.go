@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+// CORS applies the configured cross-origin policy. Only origins listed in CORS_ALLOWED_ORIGINS
+// may call the API from a browser; requests from other origins are rejected with 403 and
+// preflights are answered without the Access-Control-Allow-* headers. When no origins are
+// configured, all origins are allowed in development and none elsewhere.
+func CORS() gin.HandlerFunc {
+	return cors.New(corsConfig(config.AppConfig))
+}
+
+// corsConfig builds the gin-contrib/cors configuration from the application config
+func corsConfig(cfg *config.Config) cors.Config {
+	corsCfg := cors.Config{
+		AllowMethods:     cfg.CORSAllowedMethods,
+		AllowHeaders:     cfg.CORSAllowedHeaders,
+		ExposeHeaders:    []string{"X-Request-ID", "ETag", IdempotentReplayedHeader},
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}
+
+	origins := cfg.CORSAllowedOrigins
+	if len(origins) == 0 && cfg.Environment == "development" {
+		// Permissive mode for local development; echo the origin so credentials still work
+		corsCfg.AllowOriginFunc = func(string) bool { return true }
+		return corsCfg
+	}
+	corsCfg.AllowOriginFunc = func(origin string) bool {
+		return slices.Contains(origins, origin)
+	}
+	return corsCfg
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+func setupCORSRouter(cfg *config.Config) *gin.Engine {
+	router := gin.New()
+	router.Use(cors.New(corsConfig(cfg)))
+	router.GET("/citizen/:cpf", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"cpf": c.Param("cpf")})
+	})
+	return router
+}
+
+func corsTestConfig(environment string, origins ...string) *config.Config {
+	return &config.Config{
+		Environment:        environment,
+		CORSAllowedOrigins: origins,
+		CORSAllowedMethods: []string{"GET", "PUT"},
+		CORSAllowedHeaders: []string{"Authorization", "Content-Type"},
+		CORSMaxAge:         time.Hour,
+	}
+}
+
+func sendPreflight(router *gin.Engine, origin string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodOptions, "/citizen/12345678901", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_PreflightAllowedOrigin(t *testing.T) {
+	router := setupCORSRouter(corsTestConfig("production", "https://app.rio"))
+
+	w := sendPreflight(router, "https://app.rio")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("preflight status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.rio" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want https://app.rio", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET,PUT" {
+		t.Errorf("Access-Control-Allow-Methods = %q, want GET,PUT", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 3600", got)
+	}
+}
+
+func TestCORS_RejectsDisallowedOrigin(t *testing.T) {
+	router := setupCORSRouter(corsTestConfig("production", "https://app.rio"))
+
+	w := sendPreflight(router, "https://evil.example")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("preflight status = %d, want 403", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want empty", got)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/citizen/12345678901", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin GET status = %d, want 403", w.Code)
+	}
+}
+
+func TestCORS_NoOriginsConfigured(t *testing.T) {
+	// Outside development an empty allowlist allows no origin
+	w := sendPreflight(setupCORSRouter(corsTestConfig("production")), "https://app.rio")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("production preflight status = %d, want 403", w.Code)
+	}
+
+	// Development is permissive
+	w = sendPreflight(setupCORSRouter(corsTestConfig("development")), "http://localhost:3000")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" {
+		t.Errorf("development preflight = (%d, %q), want (204, http://localhost:3000)",
+			w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORS_SameOriginRequest(t *testing.T) {
+	router := setupCORSRouter(corsTestConfig("production"))
+
+	// Requests without an Origin header (server-to-server) are not affected
+	req, _ := http.NewRequest(http.MethodGet, "/citizen/12345678901", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
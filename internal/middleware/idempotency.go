@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-generated key of a mutating request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with an idempotent response
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+// idempotentResponse is the state stored for an idempotency key. It is written as pending when
+// the first request starts and completed with its response once the handler returns.
+type idempotentResponse struct {
+	RequestHash string            `json:"request_hash"`
+	Completed   bool              `json:"completed"`
+	Status      int               `json:"status,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// idempotencyWriter keeps a copy of the response body so it can be stored for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyRedisKey scopes a key to the CPF and route, so the same key can't replay a
+// response for another citizen or endpoint
+func idempotencyRedisKey(cpf, method, route, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s:%s:%s", cpf, method, route, key)
+}
+
+// requestFingerprint identifies the request body sent with an idempotency key
+func requestFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Idempotency replays the stored response of a mutating request when it is retried with the same
+// Idempotency-Key header, instead of running the handler again (e.g. sending a second
+// verification code). Keys are scoped by CPF and route and kept for IDEMPOTENCY_KEY_TTL. Server
+// errors are not stored, so they can be retried. Requests without the header are not affected,
+// and Redis failures let the request through.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		method := c.Request.Method
+		if key == "" || (method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch && method != http.MethodDelete) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Idempotency-Key must have at most %d characters", maxIdempotencyKeyLength),
+			})
+			return
+		}
+		if config.Redis == nil {
+			c.Next()
+			return
+		}
+
+		// Read the body for the fingerprint and restore it for the handler
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+		hash := requestFingerprint(body)
+
+		ctx := c.Request.Context()
+		logger := observability.Logger().With(zap.String("route", c.FullPath()), zap.String("idempotency_key", key))
+		redisKey := idempotencyRedisKey(c.Param("cpf"), method, c.FullPath(), key)
+		ttl := config.AppConfig.IdempotencyKeyTTL
+
+		pending, _ := json.Marshal(idempotentResponse{RequestHash: hash})
+		acquired, err := config.Redis.SetNX(ctx, redisKey, string(pending), ttl).Result()
+		if err != nil {
+			logger.Warn("failed to reserve idempotency key, processing request without it", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !acquired {
+			replayIdempotentResponse(c, redisKey, hash, logger)
+			return
+		}
+
+		// Release the pending key unless the response is stored, also when the handler panics, so
+		// retries aren't answered with 409 until the key expires. The panic keeps unwinding to the
+		// recovery middleware.
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := config.Redis.Del(ctx, redisKey).Err(); err != nil {
+				logger.Warn("failed to release idempotency key", zap.Error(err))
+			}
+		}()
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+		completed, _ := json.Marshal(idempotentResponse{
+			RequestHash: hash,
+			Completed:   true,
+			Status:      status,
+			Headers:     headers,
+			Body:        writer.body.Bytes(),
+		})
+		if err := config.Redis.Set(ctx, redisKey, string(completed), ttl).Err(); err != nil {
+			logger.Warn("failed to store idempotent response", zap.Error(err))
+			return
+		}
+		stored = true
+	}
+}
+
+// replayIdempotentResponse answers a request whose idempotency key was already used
+func replayIdempotentResponse(c *gin.Context, redisKey, hash string, logger *logging.SafeLogger) {
+	data, err := config.Redis.Get(c.Request.Context(), redisKey).Result()
+	if err == redis.Nil {
+		// Expired between SetNX and Get; the previous request is long gone
+		c.Next()
+		return
+	}
+	var stored idempotentResponse
+	if err == nil {
+		err = json.Unmarshal([]byte(data), &stored)
+	}
+	if err != nil {
+		logger.Warn("failed to read idempotent response, processing request without it", zap.Error(err))
+		c.Next()
+		return
+	}
+
+	if stored.RequestHash != hash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency-Key already used with a different request body",
+		})
+		return
+	}
+	if !stored.Completed {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "A request with this Idempotency-Key is still being processed",
+		})
+		return
+	}
+
+	logger.Debug("replaying idempotent response", zap.Int("status", stored.Status))
+	for name, value := range stored.Headers {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(stored.Status, stored.Headers["Content-Type"], stored.Body)
+	c.Abort()
+}
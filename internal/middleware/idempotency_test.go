@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/redis/go-redis/v9"
+)
+
+// setupIdempotencyRouter serves a PUT route that counts how many times its handler ran
+func setupIdempotencyRouter(calls *int) *gin.Engine {
+	router := gin.New()
+	router.PUT("/citizen/:cpf/phone", Idempotency(), func(c *gin.Context) {
+		*calls++
+		c.Header("ETag", `"7"`)
+		c.JSON(http.StatusOK, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func sendIdempotent(router *gin.Engine, cpf, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPut, "/citizen/"+cpf+"/phone", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// setupIdempotencyRedis points config.Redis at a local Redis, skipping the test when unavailable
+func setupIdempotencyRedis(t *testing.T) {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("Skipping idempotency replay tests: Redis not available (%v)", err)
+	}
+
+	originalRedis, originalTTL := config.Redis, config.AppConfig.IdempotencyKeyTTL
+	config.Redis = redisclient.NewClient(client)
+	config.AppConfig.IdempotencyKeyTTL = time.Minute
+	t.Cleanup(func() {
+		keys, _ := client.Keys(context.Background(), "idempotency:*:"+t.Name()+"*").Result()
+		if len(keys) > 0 {
+			client.Del(context.Background(), keys...)
+		}
+		client.Close()
+		config.Redis, config.AppConfig.IdempotencyKeyTTL = originalRedis, originalTTL
+	})
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	calls := 0
+	router := setupIdempotencyRouter(&calls)
+
+	for i := 0; i < 2; i++ {
+		if w := sendIdempotent(router, "12345678901", "", `{}`); w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	calls := 0
+	router := setupIdempotencyRouter(&calls)
+
+	w := sendIdempotent(router, "12345678901", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+	if calls != 0 {
+		t.Errorf("handler calls = %d, want 0", calls)
+	}
+}
+
+func TestIdempotencyRedisKey(t *testing.T) {
+	key := idempotencyRedisKey("12345678901", http.MethodPut, "/v1/citizen/:cpf/phone", "abc")
+	if key != "idempotency:12345678901:PUT:/v1/citizen/:cpf/phone:abc" {
+		t.Errorf("idempotencyRedisKey() = %q", key)
+	}
+	if key == idempotencyRedisKey("10987654321", http.MethodPut, "/v1/citizen/:cpf/phone", "abc") {
+		t.Error("idempotency keys of different CPFs must not collide")
+	}
+	if requestFingerprint([]byte(`{"a":1}`)) == requestFingerprint([]byte(`{"a":2}`)) {
+		t.Error("different bodies must have different fingerprints")
+	}
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	setupIdempotencyRedis(t)
+	calls := 0
+	router := setupIdempotencyRouter(&calls)
+	key := t.Name()
+
+	first := sendIdempotent(router, "12345678901", key, `{"valor":"1"}`)
+	second := sendIdempotent(router, "12345678901", key, `{"valor":"1"}`)
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replayed response = (%d, %s), want (%d, %s)", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" || second.Header().Get("ETag") != `"7"` {
+		t.Errorf("replayed headers = %v, want Idempotent-Replayed and ETag", second.Header())
+	}
+
+	// Another CPF with the same key runs the handler
+	sendIdempotent(router, "10987654321", key, `{"valor":"1"}`)
+	if calls != 2 {
+		t.Errorf("handler calls after other CPF = %d, want 2", calls)
+	}
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	setupIdempotencyRedis(t)
+	calls := 0
+	router := setupIdempotencyRouter(&calls)
+	key := t.Name()
+
+	sendIdempotent(router, "12345678901", key, `{"valor":"1"}`)
+	w := sendIdempotent(router, "12345678901", key, `{"valor":"2"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+}
+
+func TestIdempotency_HandlerPanicReleasesKey(t *testing.T) {
+	setupIdempotencyRedis(t)
+	calls := 0
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.PUT("/citizen/:cpf/phone", Idempotency(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusOK, gin.H{"call": calls})
+	})
+	key := t.Name()
+
+	if w := sendIdempotent(router, "12345678901", key, `{"valor":"1"}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status of the panicking request = %d, want 500", w.Code)
+	}
+
+	// The retry runs the handler instead of finding the key still pending
+	w := sendIdempotent(router, "12345678901", key, `{"valor":"1"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("status of the retry = %d, want 200", w.Code)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
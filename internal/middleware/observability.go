@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"regexp"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"go.uber.org/zap"
 )
@@ -23,20 +25,15 @@ func RequestLogger() gin.HandlerFunc {
 		// Log request details with sensitive data masking
 		observability.Logger().Info("request completed",
 			zap.String("path", path),
+			zap.String("route", c.FullPath()),
 			zap.String("query", query),
 			zap.String("ip", c.ClientIP()),
 			zap.String("method", c.Request.Method),
 			zap.Int("status", status),
 			zap.Duration("latency", latency),
 			zap.String("user_agent", c.Request.UserAgent()),
+			zap.String("request_id", c.GetString("RequestID")),
 		)
-
-		// Update metrics
-		observability.RequestDuration.WithLabelValues(
-			path,
-			c.Request.Method,
-			string(rune(status)),
-		).Observe(latency.Seconds())
 	}
 }
 
@@ -49,11 +46,17 @@ func RequestTracker() gin.HandlerFunc {
 	}
 }
 
-// RequestID adds a unique request ID to the context
+// requestIDPattern restricts inbound request IDs to UUIDs and similar tokens, so arbitrary
+// client input never ends up in logs and response headers
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID adds a request ID to the context and echoes it in the response. An inbound
+// X-Request-ID sent by the client or gateway is reused when trusted and well-formed, so client
+// logs can be correlated with server logs; otherwise a new ID is generated.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
+		if !trustInboundRequestID() || !isValidRequestID(requestID) {
 			requestID = generateRequestID()
 		}
 		c.Set("RequestID", requestID)
@@ -62,6 +65,16 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// trustInboundRequestID reports whether inbound X-Request-ID headers should be reused
+func trustInboundRequestID() bool {
+	return config.AppConfig == nil || config.AppConfig.TrustInboundRequestID
+}
+
+// isValidRequestID checks the format of an inbound request ID
+func isValidRequestID(requestID string) bool {
+	return requestIDPattern.MatchString(requestID)
+}
+
 // generateRequestID generates a unique request ID
 func generateRequestID() string {
 	// Implementation using UUID or similar
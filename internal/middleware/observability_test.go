@@ -3,9 +3,11 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 )
 
 func TestRequestLogger(t *testing.T) {
@@ -194,6 +196,77 @@ func TestRequestID_WithProvidedID(t *testing.T) {
 	}
 }
 
+func TestRequestID_InvalidProvidedID(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	invalidIDs := []string{
+		"id with spaces",
+		"id\r\nX-Injected: true",
+		"<script>",
+		strings.Repeat("a", 129),
+	}
+
+	for _, invalidID := range invalidIDs {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header["X-Request-Id"] = []string{invalidID}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseID := w.Header().Get("X-Request-ID")
+		if responseID == invalidID || responseID == "" {
+			t.Errorf("X-Request-ID = %q, want a generated ID replacing %q", responseID, invalidID)
+		}
+	}
+}
+
+func TestRequestID_UntrustedProvidedID(t *testing.T) {
+	original := config.AppConfig.TrustInboundRequestID
+	config.AppConfig.TrustInboundRequestID = false
+	defer func() { config.AppConfig.TrustInboundRequestID = original }()
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "custom-request-id-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	responseID := w.Header().Get("X-Request-ID")
+	if responseID == "custom-request-id-123" || responseID == "" {
+		t.Errorf("X-Request-ID = %q, want a generated ID", responseID)
+	}
+}
+
+func TestIsValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"550e8400-e29b-41d4-a716-446655440000", true},
+		{"20240101120000-abc123", true},
+		{"trace:span.1_2", true},
+		{"", false},
+		{"has space", false},
+		{"quote\"", false},
+		{strings.Repeat("a", 128), true},
+		{strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidRequestID(tt.id); got != tt.want {
+			t.Errorf("isValidRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
 func TestGenerateRequestID(t *testing.T) {
 	id1 := generateRequestID()
 	id2 := generateRequestID()
@@ -0,0 +1,368 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.uber.org/zap"
+)
+
+// openAPISpec is the subset of the generated Swagger 2.0 document used to validate requests
+type openAPISpec struct {
+	BasePath    string                                 `json:"basePath"`
+	Paths       map[string]map[string]openAPIOperation `json:"paths"`
+	Definitions map[string]*openAPISchema              `json:"definitions"`
+}
+
+type openAPIOperation struct {
+	Parameters []openAPIParameter `json:"parameters"`
+}
+
+// openAPIParameter is a path, query or body parameter. Path and query parameters carry their
+// constraints inline; body parameters reference a schema.
+type openAPIParameter struct {
+	Name      string         `json:"name"`
+	In        string         `json:"in"`
+	Required  bool           `json:"required"`
+	Schema    *openAPISchema `json:"schema"`
+	Type      string         `json:"type"`
+	Enum      []interface{}  `json:"enum"`
+	MinLength *int           `json:"minLength"`
+	MaxLength *int           `json:"maxLength"`
+	Pattern   string         `json:"pattern"`
+	Minimum   *float64       `json:"minimum"`
+	Maximum   *float64       `json:"maximum"`
+}
+
+func (p openAPIParameter) schema() *openAPISchema {
+	return &openAPISchema{
+		Type:      p.Type,
+		Enum:      p.Enum,
+		MinLength: p.MinLength,
+		MaxLength: p.MaxLength,
+		Pattern:   p.Pattern,
+		Minimum:   p.Minimum,
+		Maximum:   p.Maximum,
+	}
+}
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	AllOf      []*openAPISchema          `json:"allOf"`
+	Type       string                    `json:"type"`
+	Required   []string                  `json:"required"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+	Enum       []interface{}             `json:"enum"`
+	MinLength  *int                      `json:"minLength"`
+	MaxLength  *int                      `json:"maxLength"`
+	Pattern    string                    `json:"pattern"`
+	Minimum    *float64                  `json:"minimum"`
+	Maximum    *float64                  `json:"maximum"`
+}
+
+// RequestValidator validates requests against the operations of an OpenAPI (Swagger 2.0) spec
+type RequestValidator struct {
+	definitions map[string]*openAPISchema
+	operations  map[string]openAPIOperation // keyed by "METHOD /gin/route/:param"
+	patterns    sync.Map                    // pattern -> *regexp.Regexp
+}
+
+// NewRequestValidator builds a validator from the JSON of a Swagger 2.0 document
+func NewRequestValidator(specJSON string) (*RequestValidator, error) {
+	var spec openAPISpec
+	if err := json.Unmarshal([]byte(specJSON), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	operations := make(map[string]openAPIOperation)
+	for path, methods := range spec.Paths {
+		route := strings.TrimSuffix(spec.BasePath, "/") + swaggerPathToRoute(path)
+		for method, operation := range methods {
+			operations[strings.ToUpper(method)+" "+route] = operation
+		}
+	}
+
+	return &RequestValidator{
+		definitions: spec.Definitions,
+		operations:  operations,
+	}, nil
+}
+
+// swaggerPathToRoute converts "/citizen/{cpf}" into the gin route "/citizen/:cpf"
+func swaggerPathToRoute(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Global request validator, nil until InitRequestValidator succeeds
+var requestValidator *RequestValidator
+
+// InitRequestValidator loads the OpenAPI spec used by OpenAPIValidation
+func InitRequestValidator(specJSON string) error {
+	validator, err := NewRequestValidator(specJSON)
+	if err != nil {
+		return err
+	}
+	requestValidator = validator
+	return nil
+}
+
+// OpenAPIValidation validates the path and query parameters and the JSON body of requests in a
+// route group against the OpenAPI operation of the matched route, answering 422 with structured
+// errors on constraint violations. It is a no-op unless group is listed in
+// OPENAPI_VALIDATION_GROUPS, so validation can be rolled out one route group at a time.
+func OpenAPIValidation(group string) gin.HandlerFunc {
+	enabled := false
+	for _, g := range config.AppConfig.OpenAPIValidationGroups {
+		if g == group {
+			enabled = true
+			break
+		}
+	}
+
+	return func(c *gin.Context) {
+		if !enabled || requestValidator == nil {
+			c.Next()
+			return
+		}
+
+		errs := requestValidator.Validate(c)
+		if len(errs) > 0 {
+			observability.Logger().Debug("request failed OpenAPI validation",
+				zap.String("group", group),
+				zap.String("route", c.FullPath()),
+				zap.Any("errors", errs))
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "Request validation failed",
+				"errors": errs,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Validate checks the request against the operation of its matched route. Malformed or empty
+// JSON bodies are left to the handler's binding, which already reports them.
+func (v *RequestValidator) Validate(c *gin.Context) []utils.ValidationError {
+	operation, ok := v.operations[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		return nil
+	}
+
+	var errs []utils.ValidationError
+	for _, param := range operation.Parameters {
+		switch param.In {
+		case "path":
+			errs = append(errs, v.validateParameter(param, c.Param(param.Name))...)
+		case "query":
+			value, present := c.GetQuery(param.Name)
+			if !present {
+				if param.Required {
+					errs = append(errs, requiredError(param.Name))
+				}
+				continue
+			}
+			errs = append(errs, v.validateParameter(param, value)...)
+		case "body":
+			errs = append(errs, v.validateBody(c, param)...)
+		}
+	}
+	return errs
+}
+
+// validateParameter converts a raw path/query value to the parameter type before validating it
+func (v *RequestValidator) validateParameter(param openAPIParameter, raw string) []utils.ValidationError {
+	var value interface{} = raw
+	switch param.Type {
+	case "integer", "number":
+		value = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []utils.ValidationError{typeError(param.Name, param.Type)}
+		}
+		value = b
+	}
+	return v.validateValue(param.Name, param.schema(), value)
+}
+
+func (v *RequestValidator) validateBody(c *gin.Context, param openAPIParameter) []utils.ValidationError {
+	if c.Request.Body == nil || param.Schema == nil {
+		return nil
+	}
+	if contentType := c.ContentType(); contentType != "" && contentType != gin.MIMEJSON {
+		return nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil
+	}
+	return v.validateValue("", param.Schema, document)
+}
+
+// resolve follows $ref into the spec definitions
+func (v *RequestValidator) resolve(schema *openAPISchema) *openAPISchema {
+	for schema != nil && schema.Ref != "" {
+		schema = v.definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+	}
+	return schema
+}
+
+// validateValue validates a decoded JSON value against schema. Null values are treated as absent.
+func (v *RequestValidator) validateValue(field string, schema *openAPISchema, value interface{}) []utils.ValidationError {
+	schema = v.resolve(schema)
+	if schema == nil || value == nil {
+		return nil
+	}
+
+	var errs []utils.ValidationError
+	for _, sub := range schema.AllOf {
+		errs = append(errs, v.validateValue(field, sub, value)...)
+	}
+
+	name := field
+	if name == "" {
+		name = "body"
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, typeError(name, schema.Type))
+		}
+		for _, required := range schema.Required {
+			if object[required] == nil {
+				errs = append(errs, requiredError(joinField(field, required)))
+			}
+		}
+		for key, propertyValue := range object {
+			if property, ok := schema.Properties[key]; ok {
+				errs = append(errs, v.validateValue(joinField(field, key), property, propertyValue)...)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(errs, typeError(name, schema.Type))
+		}
+		for i, item := range items {
+			errs = append(errs, v.validateValue(fmt.Sprintf("%s[%d]", name, i), schema.Items, item)...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return append(errs, typeError(name, schema.Type))
+		}
+		length := utf8.RuneCountInString(s)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeTooShort, Message: fmt.Sprintf("%s must be at least %d characters", name, *schema.MinLength)})
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeTooLong, Message: fmt.Sprintf("%s must be at most %d characters", name, *schema.MaxLength)})
+		}
+		if schema.Pattern != "" {
+			if re := v.pattern(schema.Pattern); re != nil && !re.MatchString(s) {
+				errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeInvalidFormat, Message: fmt.Sprintf("%s has an invalid format", name)})
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return append(errs, typeError(name, schema.Type))
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return append(errs, typeError(name, schema.Type))
+		}
+		if schema.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return append(errs, typeError(name, schema.Type))
+			}
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeInvalidValue, Message: fmt.Sprintf("%s must be at least %v", name, *schema.Minimum)})
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeInvalidValue, Message: fmt.Sprintf("%s must be at most %v", name, *schema.Maximum)})
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return append(errs, typeError(name, schema.Type))
+		}
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, e := range schema.Enum {
+			allowed = append(allowed, fmt.Sprint(e))
+		}
+		errs = append(errs, utils.ValidationError{Field: name, Code: utils.ValidationCodeInvalidValue, Message: fmt.Sprintf("%s must be one of: %s", name, strings.Join(allowed, " "))})
+	}
+	return errs
+}
+
+// pattern compiles and caches a schema pattern; invalid patterns are ignored
+func (v *RequestValidator) pattern(pattern string) *regexp.Regexp {
+	if cached, ok := v.patterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	v.patterns.Store(pattern, re)
+	return re
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func requiredError(field string) utils.ValidationError {
+	return utils.ValidationError{Field: field, Code: utils.ValidationCodeRequired, Message: fmt.Sprintf("%s is required", field)}
+}
+
+func typeError(field, expected string) utils.ValidationError {
+	return utils.ValidationError{Field: field, Code: utils.ValidationCodeInvalidType, Message: fmt.Sprintf("%s must be of type %s", field, expected)}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/docs"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+const testOpenAPISpec = `{
+	"swagger": "2.0",
+	"basePath": "/v1",
+	"paths": {
+		"/citizen/{cpf}/ethnicity": {
+			"put": {
+				"parameters": [
+					{"type": "string", "maxLength": 11, "minLength": 11, "name": "cpf", "in": "path", "required": true},
+					{"type": "integer", "maximum": 100, "minimum": 1, "name": "per_page", "in": "query"},
+					{"name": "data", "in": "body", "required": true, "schema": {"$ref": "#/definitions/models.EthnicityInput"}}
+				]
+			}
+		}
+	},
+	"definitions": {
+		"models.EthnicityInput": {
+			"type": "object",
+			"required": ["valor"],
+			"properties": {
+				"valor": {"type": "string", "enum": ["branca", "parda", "preta"]},
+				"origem": {"allOf": [{"$ref": "#/definitions/models.Origin"}]},
+				"tags": {"type": "array", "items": {"type": "string", "minLength": 2}}
+			}
+		},
+		"models.Origin": {
+			"type": "object",
+			"required": ["canal"],
+			"properties": {
+				"canal": {"type": "string", "pattern": "^[a-z]+$"}
+			}
+		}
+	}
+}`
+
+// setupOpenAPIValidationRouter serves the test spec route with validation enabled for "citizen"
+func setupOpenAPIValidationRouter(t *testing.T, group string) *gin.Engine {
+	t.Helper()
+	originalGroups, originalValidator := config.AppConfig.OpenAPIValidationGroups, requestValidator
+	t.Cleanup(func() {
+		config.AppConfig.OpenAPIValidationGroups, requestValidator = originalGroups, originalValidator
+	})
+	config.AppConfig.OpenAPIValidationGroups = []string{"citizen"}
+	if err := InitRequestValidator(testOpenAPISpec); err != nil {
+		t.Fatalf("InitRequestValidator() error = %v", err)
+	}
+
+	router := gin.New()
+	citizen := router.Group("/v1/citizen")
+	citizen.Use(OpenAPIValidation(group))
+	citizen.PUT("/:cpf/ethnicity", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func TestOpenAPIValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{"valid request", "/v1/citizen/12345678901/ethnicity", `{"valor":"parda","origem":{"canal":"app"},"tags":["ok"]}`, http.StatusOK, nil},
+		{"null optional field", "/v1/citizen/12345678901/ethnicity", `{"valor":"parda","origem":null}`, http.StatusOK, nil},
+		{"short CPF", "/v1/citizen/123/ethnicity", `{"valor":"parda"}`, http.StatusUnprocessableEntity, []string{"cpf"}},
+		{"query out of range", "/v1/citizen/12345678901/ethnicity?per_page=500", `{"valor":"parda"}`, http.StatusUnprocessableEntity, []string{"per_page"}},
+		{"query wrong type", "/v1/citizen/12345678901/ethnicity?per_page=abc", `{"valor":"parda"}`, http.StatusUnprocessableEntity, []string{"per_page"}},
+		{"value not in enum", "/v1/citizen/12345678901/ethnicity", `{"valor":"azul"}`, http.StatusUnprocessableEntity, []string{"valor"}},
+		{"missing required field", "/v1/citizen/12345678901/ethnicity", `{}`, http.StatusUnprocessableEntity, []string{"valor"}},
+		{"nested violations", "/v1/citizen/12345678901/ethnicity", `{"valor":"parda","origem":{"canal":"APP"},"tags":["x"]}`, http.StatusUnprocessableEntity, []string{"origem.canal", "tags[0]"}},
+		{"nested required field", "/v1/citizen/12345678901/ethnicity", `{"valor":"parda","origem":{}}`, http.StatusUnprocessableEntity, []string{"origem.canal"}},
+		{"wrong type", "/v1/citizen/12345678901/ethnicity", `{"valor":1}`, http.StatusUnprocessableEntity, []string{"valor"}},
+		{"malformed JSON is left to the handler", "/v1/citizen/12345678901/ethnicity", `{"valor":`, http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupOpenAPIValidationRouter(t, "citizen")
+
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if w.Body.String() != tt.body {
+					t.Errorf("handler body = %q, want %q", w.Body.String(), tt.body)
+				}
+				return
+			}
+
+			var response struct {
+				Error  string `json:"error"`
+				Errors []struct {
+					Field string `json:"field"`
+					Code  string `json:"code"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			fields := make(map[string]bool)
+			for _, e := range response.Errors {
+				fields[e.Field] = true
+			}
+			for _, field := range tt.wantFields {
+				if !fields[field] {
+					t.Errorf("errors = %+v, want an error for %q", response.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidation_GroupNotEnabled(t *testing.T) {
+	router := setupOpenAPIValidationRouter(t, "memory")
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/citizen/123/ethnicity", strings.NewReader(`{"valor":"azul"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestNewRequestValidator_GeneratedSpec(t *testing.T) {
+	validator, err := NewRequestValidator(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		t.Fatalf("NewRequestValidator() error = %v", err)
+	}
+	if _, ok := validator.operations["GET /v1/citizen/:cpf"]; !ok {
+		t.Error("generated spec should contain the GET /v1/citizen/:cpf operation")
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
+)
+
+// Scopes carried by service tokens
+const (
+	ScopeCitizenReadBatch  = "citizen:read:batch"
+	ScopeCitizenReadSearch = "citizen:read:search"
+	ScopeLegalEntityRead   = "legal-entity:read"
+	ScopePhoneStatusRead   = "phone:read:status"
+)
+
+// serviceTokenKey marks requests authenticated with a verified service token
+const serviceTokenKey = "service_token"
+
+// jwksMinRefreshInterval limits refetches triggered by tokens signed with an unknown key id
+const jwksMinRefreshInterval = time.Minute
+
+// jwksKeySet caches the RSA keys published at SERVICE_TOKEN_JWKS_URL, keyed by key id
+type jwksKeySet struct {
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// serviceKeys holds the signing keys of service tokens
+var serviceKeys = &jwksKeySet{}
+
+// key returns the key with id kid, refetching the key set once it is older than
+// SERVICE_TOKEN_JWKS_CACHE_TTL or when kid is unknown (at most once per jwksMinRefreshInterval)
+func (s *jwksKeySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.fetchedAt)
+	key, ok := s.keys[kid]
+	if s.keys == nil || age > config.AppConfig.ServiceTokenJWKSCacheTTL || (!ok && age > jwksMinRefreshInterval) {
+		keys, err := fetchJWKS(ctx, config.AppConfig.ServiceTokenJWKSURL)
+		if err != nil {
+			if ok {
+				// Keep using the cached key while the JWKS endpoint is unavailable
+				return key, nil
+			}
+			return nil, err
+		}
+		s.keys = keys
+		s.fetchedAt = time.Now()
+		key, ok = s.keys[kid]
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// fetchJWKS downloads a JWKS document and returns its RSA signing keys
+func fetchJWKS(ctx context.Context, url string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+
+	resp, err := httpclient.Client(httpclient.ClientJWKS).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var document struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// isServiceToken reports whether claims were issued by the service token issuer
+func isServiceToken(claims *models.JWTClaims) bool {
+	return config.AppConfig.ServiceTokenIssuer != "" && claims.ISS == config.AppConfig.ServiceTokenIssuer
+}
+
+// verifyServiceToken checks the RS256 signature of a service token against the issuer's JWKS,
+// its validity window and, when SERVICE_TOKEN_AUDIENCE is set, its audience. Unlike citizen
+// tokens, service tokens are not validated upstream by Istio.
+func verifyServiceToken(ctx context.Context, token string, claims *models.JWTClaims) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid token format")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("failed to decode header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return fmt.Errorf("failed to parse header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	key, err := serviceKeys.key(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	now := time.Now().Unix()
+	if claims.Exp == 0 || now >= claims.Exp {
+		return fmt.Errorf("token expired")
+	}
+	if claims.NBF != 0 && now < claims.NBF {
+		return fmt.Errorf("token not yet valid")
+	}
+	if audience := config.AppConfig.ServiceTokenAudience; audience != "" && !slices.Contains(claims.GetAudiences(), audience) {
+		return fmt.Errorf("token audience does not include %q", audience)
+	}
+	return nil
+}
+
+// IsServiceToken reports whether the request was authenticated with a verified service token
+func IsServiceToken(c *gin.Context) bool {
+	return c.GetBool(serviceTokenKey)
+}
+
+// RequireScope restricts a route to service tokens carrying scope. Citizen tokens are rejected
+// even for admins, so machine-to-machine routes stay separate from citizen and admin access.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("claims"); !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Claims not found"})
+			c.Abort()
+			return
+		}
+
+		if !IsServiceToken(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Service token required"})
+			c.Abort()
+			return
+		}
+
+		if !HasAnyScope(c, []string{scope}) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Scope %s required", scope)})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+const testServiceIssuer = "https://auth.rio/realms/services"
+
+// setupServiceTokens serves a JWKS with a fresh signing key and points the service token
+// configuration at it, restoring the previous configuration when the test ends
+func setupServiceTokens(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+
+	previous := *config.AppConfig
+	config.AppConfig.ServiceTokenIssuer = testServiceIssuer
+	config.AppConfig.ServiceTokenJWKSURL = server.URL
+	config.AppConfig.ServiceTokenJWKSCacheTTL = time.Hour
+	serviceKeys = &jwksKeySet{}
+
+	t.Cleanup(func() {
+		server.Close()
+		*config.AppConfig = previous
+		serviceKeys = &jwksKeySet{}
+	})
+	return key
+}
+
+func signServiceToken(t *testing.T, key *rsa.PrivateKey, claims models.JWTClaims) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test-key"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func serviceClaims(scope string) models.JWTClaims {
+	return models.JWTClaims{
+		SUB:   "service-account-batch",
+		ISS:   testServiceIssuer,
+		Exp:   time.Now().Add(time.Hour).Unix(),
+		Scope: scope,
+	}
+}
+
+func setupScopedRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.POST("/service/batch", RequireScope(ScopeCitizenReadBatch), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/citizen/:cpf", RequireOwnCPF(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	return router
+}
+
+func sendWithToken(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireScope_ServiceTokenWithScope(t *testing.T) {
+	key := setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	token := signServiceToken(t, key, serviceClaims("legal-entity:read citizen:read:batch"))
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", token); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestRequireScope_MissingScope(t *testing.T) {
+	key := setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	token := signServiceToken(t, key, serviceClaims(ScopeLegalEntityRead))
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", token); w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+}
+
+func TestRequireScope_CitizenTokenRejected(t *testing.T) {
+	setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	// A citizen token carrying the scope is still not a service token
+	claims := models.JWTClaims{
+		ISS:               "https://auth.rio/realms/citizens",
+		PreferredUsername: "12345678901",
+		Scope:             ScopeCitizenReadBatch,
+	}
+	claims.RealmAccess.Roles = []string{"go:admin"}
+	token := createTestJWT(claims)
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", token); w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+}
+
+func TestAuthMiddleware_ServiceTokenInvalidSignature(t *testing.T) {
+	setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	token := signServiceToken(t, otherKey, serviceClaims(ScopeCitizenReadBatch))
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", token); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+
+	// Unsigned tokens claiming the service issuer are rejected too
+	unsigned := createTestJWT(serviceClaims(ScopeCitizenReadBatch))
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", unsigned); w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuthMiddleware_ServiceTokenExpired(t *testing.T) {
+	key := setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	claims := serviceClaims(ScopeCitizenReadBatch)
+	claims.Exp = time.Now().Add(-time.Minute).Unix()
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", signServiceToken(t, key, claims)); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuthMiddleware_ServiceTokenAudience(t *testing.T) {
+	key := setupServiceTokens(t)
+	config.AppConfig.ServiceTokenAudience = "app-rmi"
+	router := setupScopedRouter()
+
+	claims := serviceClaims(ScopeCitizenReadBatch)
+	claims.AUD = "other-api"
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", signServiceToken(t, key, claims)); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong audience status = %d, want 401", w.Code)
+	}
+
+	claims.AUD = []string{"account", "app-rmi"}
+	if w := sendWithToken(router, http.MethodPost, "/service/batch", signServiceToken(t, key, claims)); w.Code != http.StatusOK {
+		t.Errorf("matching audience status = %d, want 200", w.Code)
+	}
+}
+
+func TestRequireOwnCPF_ServiceTokenRejected(t *testing.T) {
+	key := setupServiceTokens(t)
+	router := setupScopedRouter()
+
+	claims := serviceClaims(ScopeCitizenReadBatch)
+	claims.PreferredUsername = "12345678901"
+	if w := sendWithToken(router, http.MethodGet, "/citizen/12345678901", signServiceToken(t, key, claims)); w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+}
+
+func TestJWKSKeySet_CachesKeys(t *testing.T) {
+	requests := 0
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1", "kty": "RSA",
+				"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	previous := *config.AppConfig
+	defer func() { *config.AppConfig = previous }()
+	config.AppConfig.ServiceTokenJWKSURL = server.URL
+	config.AppConfig.ServiceTokenJWKSCacheTTL = time.Hour
+
+	keys := &jwksKeySet{}
+	for i := 0; i < 3; i++ {
+		got, err := keys.key(t.Context(), "k1")
+		if err != nil {
+			t.Fatalf("key() error = %v", err)
+		}
+		if got.N.Cmp(key.N) != 0 {
+			t.Error("key() returned a different key")
+		}
+	}
+	if _, err := keys.key(t.Context(), "unknown"); err == nil {
+		t.Error("key() should fail for an unknown key id")
+	}
+	if requests != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", requests)
+	}
+}
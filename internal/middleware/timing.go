@@ -1,16 +1,30 @@
 package middleware
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.uber.org/zap"
 )
 
-// RequestTiming adds comprehensive timing information to requests
+// unmatchedRoute labels requests that didn't match any route (e.g. 404s), so raw paths never
+// become metric labels
+const unmatchedRoute = "unmatched"
+
+// routeLabel returns the route template of the request (e.g. /v1/citizen/:cpf), keeping CPFs and
+// other path parameters out of metric labels
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
+
+// RequestTiming adds comprehensive timing information to requests and records the per-route
+// latency and size histograms
 func RequestTiming() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -48,23 +62,15 @@ func RequestTiming() gin.HandlerFunc {
 			attribute.String("http.duration", latency.String()),
 		)
 
-		// Log request completion with timing
-		observability.Logger().Info("request completed",
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
-			zap.String("route", c.FullPath()),
-			zap.Int("status", status),
-			zap.Duration("latency", latency),
-			zap.String("client_ip", c.ClientIP()),
-			zap.String("user_agent", c.Request.UserAgent()),
-		)
-
-		// Update metrics
-		observability.RequestDuration.WithLabelValues(
-			c.FullPath(),
-			c.Request.Method,
-			string(rune(status)),
-		).Observe(latency.Seconds())
+		// Update metrics (the access log itself is written by RequestLogger)
+		route := routeLabel(c)
+		statusLabel := strconv.Itoa(status)
+		observability.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, statusLabel).Observe(latency.Seconds())
+		if c.Request.ContentLength > 0 {
+			observability.HTTPRequestBytes.WithLabelValues(route).Observe(float64(c.Request.ContentLength))
+		}
+		observability.HTTPResponseBytes.WithLabelValues(route).Observe(float64(max(c.Writer.Size(), 0)))
+		observability.RequestDuration.WithLabelValues(route, c.Request.Method, statusLabel).Observe(latency.Seconds())
 
 		// Record errors in span if status indicates error
 		if status >= 400 {
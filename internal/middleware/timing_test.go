@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestRequestTiming_Success(t *testing.T) {
@@ -208,3 +210,27 @@ func TestRequestTiming_DifferentStatusCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestTiming_RouteMetrics(t *testing.T) {
+	router := gin.New()
+	router.Use(RequestTiming())
+	router.GET("/citizen/:cpf/metrics-test", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+
+	before := testutil.CollectAndCount(observability.HTTPRequestDuration)
+	for _, cpf := range []string{"12345678901", "10987654321"} {
+		req, _ := http.NewRequest("GET", "/citizen/"+cpf+"/metrics-test", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req, _ := http.NewRequest("GET", "/not-a-route/12345678901", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Both CPFs share the route template series, and the 404 goes to the unmatched series
+	if got := testutil.CollectAndCount(observability.HTTPRequestDuration) - before; got != 2 {
+		t.Errorf("new http_request_duration_seconds series = %d, want 2", got)
+	}
+	if got := testutil.CollectAndCount(observability.HTTPResponseBytes, "http_response_bytes"); got < 2 {
+		t.Errorf("http_response_bytes series = %d, want at least 2", got)
+	}
+}
@@ -14,12 +14,17 @@ type Avatar struct {
 	IsActive  bool               `bson:"is_active" json:"is_active"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	// StorageKey identifies the image in the avatar storage backend; when set, URL is resolved from it
+	StorageKey string `bson:"storage_key,omitempty" json:"storage_key,omitempty"`
 }
 
-// AvatarRequest represents the request payload for creating/updating avatars
+// AvatarRequest represents the request payload for creating/updating avatars.
+// Either URL (an externally hosted image) or Image (base64 PNG, JPEG, WebP or GIF bytes, kept
+// in the avatar storage backend) must be given.
 type AvatarRequest struct {
-	Name string `json:"name" binding:"required" validate:"min=1,max=100"`
-	URL  string `json:"url" binding:"required" validate:"url"`
+	Name  string `json:"name" binding:"required" validate:"min=1,max=100"`
+	URL   string `json:"url,omitempty" validate:"omitempty,url"`
+	Image string `json:"image,omitempty"`
 }
 
 // AvatarResponse represents the response format for avatar endpoints
@@ -31,6 +36,14 @@ type AvatarResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Sort fields and orders accepted by the avatar listing
+const (
+	AvatarSortCreatedAt = "created_at"
+	AvatarSortName      = "name"
+	AvatarOrderAsc      = "asc"
+	AvatarOrderDesc     = "desc"
+)
+
 // AvatarsListResponse represents paginated response for listing avatars
 type AvatarsListResponse struct {
 	Data       []AvatarResponse `json:"data"`
@@ -51,6 +64,13 @@ type UserAvatarRequest struct {
 	AvatarID *string `json:"avatar_id"`
 }
 
+// AvatarMigrationResponse reports the outcome of moving externally hosted avatars into the storage backend
+type AvatarMigrationResponse struct {
+	Migrated int      `json:"migrated"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
 // ToResponse converts Avatar model to AvatarResponse
 func (a *Avatar) ToResponse() AvatarResponse {
 	return AvatarResponse{
@@ -73,6 +73,21 @@ type BetaWhitelistListResponse struct {
 	TotalCount  int64                   `json:"total_count"`
 }
 
+// BetaGroupStats represents the membership statistics of a beta group
+type BetaGroupStats struct {
+	GroupID          string    `json:"group_id"`
+	GroupName        string    `json:"group_name"`
+	WhitelistedCount int64     `json:"whitelisted_count"`
+	BoundCPFCount    int64     `json:"bound_cpf_count"`  // Whitelisted phones bound to a CPF
+	OptedInCount     int64     `json:"opted_in_count"`   // Whitelisted phones currently opted in
+	OptInRate        float64   `json:"opt_in_rate"`      // OptedInCount / WhitelistedCount, 0 for an empty group
+	RecentAdditions  int64     `json:"recent_additions"` // Phones added (or last updated) within the recent window
+	RecentOptIns     int64     `json:"recent_opt_ins"`   // Opt-ins recorded for the group's phones within the recent window
+	RecentOptOuts    int64     `json:"recent_opt_outs"`  // Opt-outs recorded for the group's phones within the recent window
+	RecentSince      time.Time `json:"recent_since"`     // Start of the recent window
+	ComputedAt       time.Time `json:"computed_at"`
+}
+
 // BetaStatusResponse represents the response for beta status check
 type BetaStatusResponse struct {
 	PhoneNumber     string `json:"phone_number"`
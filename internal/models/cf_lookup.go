@@ -16,9 +16,23 @@ type CFLookup struct {
 	EquipeSaudeData *EquipeSaudeInfo   `bson:"equipe_saude_data,omitempty" json:"equipe_saude_data,omitempty"`
 	DistanceMeters  int                `bson:"distance_meters" json:"distance_meters"`
 	LookupSource    string             `bson:"lookup_source" json:"lookup_source"` // "mcp"
-	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
-	IsActive        bool               `bson:"is_active" json:"is_active"`
+	Timestamps      `bson:",inline"`
+	// RefreshedAt is when the CF data was last fetched from MCP; nil on documents stored before it existed
+	RefreshedAt *time.Time `bson:"refreshed_at,omitempty" json:"refreshed_at,omitempty"`
+	IsActive    bool       `bson:"is_active" json:"is_active"`
+	// NoCFFound marks an inactive document recording that the last lookup found no CF for the address
+	NoCFFound bool `bson:"no_cf_found,omitempty" json:"no_cf_found,omitempty"`
+	// Stale is set at read time when the data is older than CF_LOOKUP_MAX_AGE and a refresh was triggered
+	Stale bool `bson:"-" json:"stale,omitempty"`
+}
+
+// LastRefreshedAt returns when the CF data was last fetched from MCP, falling back to UpdatedAt for
+// documents stored before RefreshedAt existed
+func (cf *CFLookup) LastRefreshedAt() time.Time {
+	if cf.RefreshedAt != nil {
+		return *cf.RefreshedAt
+	}
+	return cf.UpdatedAt
 }
 
 // CFInfo represents detailed information about a Clínica da Família
@@ -82,6 +96,15 @@ type HealthServicesResult struct {
 	FamilyHealthTeam *EquipeSaudeInfo `json:"family_health_team,omitempty"`
 }
 
+// CFAddressPreview is the Clínica da Família an address would map to, looked up without
+// saving the address or the result
+type CFAddressPreview struct {
+	AddressHash     string           `json:"address_hash"`
+	Found           bool             `json:"found"`
+	CFData          *CFInfo          `json:"cf_data,omitempty"`
+	EquipeSaudeData *EquipeSaudeInfo `json:"equipe_saude_data,omitempty"`
+}
+
 // CFLookupRequest represents a request to lookup CF for a citizen
 type CFLookupRequest struct {
 	CPF     string `json:"cpf" binding:"required"`
@@ -167,6 +190,12 @@ func (cf *CFLookup) ToClinicaFamilia() *ClinicaFamilia {
 	fonte := "mcp"
 	indicador := true
 
+	var stale *bool
+	if cf.Stale {
+		staleFlag := true
+		stale = &staleFlag
+	}
+
 	return &ClinicaFamilia{
 		Indicador:          &indicador,
 		IDCNES:             cf.CFData.IDEquipamento,
@@ -176,6 +205,7 @@ func (cf *CFLookup) ToClinicaFamilia() *ClinicaFamilia {
 		Endereco:           &endereco,
 		HorarioAtendimento: &horarioAtendimento,
 		Fonte:              &fonte,
+		Stale:              stale,
 	}
 }
 
@@ -223,3 +253,123 @@ func (cf *CFLookup) ToEquipeSaudeFamilia() *EquipeSaudeFamilia {
 		Enfermeiros: enfermeiros,
 	}
 }
+
+// CFTeamsBatchRequest represents a request to look up the CF and family health team of many citizens
+type CFTeamsBatchRequest struct {
+	CPFs []string `json:"cpfs" binding:"required,min=1"`
+}
+
+// CFTeamsBatchItem represents the stored CF and family health team summary of a single citizen
+type CFTeamsBatchItem struct {
+	CPF                string              `json:"cpf"`
+	HasCFData          bool                `json:"has_cf_data"`
+	Error              string              `json:"error,omitempty"`
+	ClinicaFamilia     *ClinicaFamilia     `json:"clinica_familia,omitempty"`
+	EquipeSaudeFamilia *EquipeSaudeFamilia `json:"equipe_saude_familia,omitempty"`
+	UpdatedAt          *time.Time          `json:"updated_at,omitempty"`
+}
+
+// CFTeamsBatchResponse represents the response of a bulk CF team lookup
+type CFTeamsBatchResponse struct {
+	Results      []CFTeamsBatchItem `json:"results"`
+	TotalCount   int                `json:"total_count"`
+	FoundCount   int                `json:"found_count"`
+	MissingCount int                `json:"missing_count"`
+}
+
+// ToCFTeamsBatchItem converts CFLookup to the summary returned by the bulk CF team lookup
+func (cf *CFLookup) ToCFTeamsBatchItem(cpf string) CFTeamsBatchItem {
+	if cf == nil {
+		return CFTeamsBatchItem{CPF: cpf, HasCFData: false}
+	}
+
+	updatedAt := cf.UpdatedAt
+	return CFTeamsBatchItem{
+		CPF:                cpf,
+		HasCFData:          true,
+		ClinicaFamilia:     cf.ToClinicaFamilia(),
+		EquipeSaudeFamilia: cf.ToEquipeSaudeFamilia(),
+		UpdatedAt:          &updatedAt,
+	}
+}
+
+// CF status reasons reported in the wallet's clinica_familia_status
+const (
+	ClinicaFamiliaStatusFound       = "found"        // CF data available (base data or lookup)
+	ClinicaFamiliaStatusNoEquipment = "no_equipment" // the health services lookup has no equipment for the address
+	ClinicaFamiliaStatusNotFound    = "not_found"    // the lookup returned no CF for the address
+	ClinicaFamiliaStatusNoAddress   = "no_address"   // no address available to look up
+	ClinicaFamiliaStatusPending     = "pending"      // the lookup failed and was queued to run in background
+	ClinicaFamiliaStatusUnavailable = "unavailable"  // CF lookup is disabled
+)
+
+// CF coverage stats grouping options
+const (
+	CFCoverageByBairro    = "bairro"
+	CFCoverageByMunicipio = "municipio"
+)
+
+// CFCoverageRegion represents the CF assignment counts of a single region
+type CFCoverageRegion struct {
+	Region       string  `json:"region"`
+	WithCF       int     `json:"with_cf"`
+	NoCFFound    int     `json:"no_cf_found"`
+	Total        int     `json:"total"`
+	CoverageRate float64 `json:"coverage_rate"` // with_cf / total, between 0 and 1
+}
+
+// NewCFCoverageRegion builds the coverage counts of a region
+func NewCFCoverageRegion(region string, withCF, noCFFound int) CFCoverageRegion {
+	total := withCF + noCFFound
+	coverage := CFCoverageRegion{
+		Region:    region,
+		WithCF:    withCF,
+		NoCFFound: noCFFound,
+		Total:     total,
+	}
+	if total > 0 {
+		coverage.CoverageRate = float64(withCF) / float64(total)
+	}
+	return coverage
+}
+
+// CFCoverageStats represents aggregate CF coverage across regions. Citizens without an address
+// region are grouped under an empty region.
+type CFCoverageStats struct {
+	GroupBy        string             `json:"group_by"`
+	Regions        []CFCoverageRegion `json:"regions"`
+	TotalWithCF    int                `json:"total_with_cf"`
+	TotalNoCFFound int                `json:"total_no_cf_found"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+}
+
+// CFLookupBulkRequest selects the citizens whose CF lookup is re-triggered in bulk. Regions match
+// the citizen's current address (self-declared first, then base data), ignoring case.
+type CFLookupBulkRequest struct {
+	Bairro    string `json:"bairro,omitempty" example:"Copacabana"`
+	Municipio string `json:"municipio,omitempty" example:"Rio de Janeiro"`
+	DryRun    bool   `json:"dry_run,omitempty"` // only count the matching citizens
+}
+
+// CFLookupBulkResponse represents the result of a bulk CF lookup re-trigger
+type CFLookupBulkResponse struct {
+	BatchID          string `json:"batch_id,omitempty"` // empty on dry run
+	Bairro           string `json:"bairro,omitempty"`
+	Municipio        string `json:"municipio,omitempty"`
+	Matched          int    `json:"matched"`            // citizens in the region with a usable address
+	Enqueued         int    `json:"enqueued"`           // cf_lookup jobs queued
+	SkippedNoAddress int    `json:"skipped_no_address"` // citizens in the region without a street to look up
+	DryRun           bool   `json:"dry_run"`
+}
+
+// CFLookupBatchStatus represents the progress of the jobs queued by a bulk CF lookup re-trigger
+type CFLookupBatchStatus struct {
+	BatchID   string    `json:"batch_id"`
+	Bairro    string    `json:"bairro,omitempty"`
+	Municipio string    `json:"municipio,omitempty"`
+	Enqueued  int       `json:"enqueued"`
+	Completed int       `json:"completed"` // lookups finished successfully
+	Failed    int       `json:"failed"`    // lookup attempts that failed (failed jobs are retried)
+	Pending   int       `json:"pending"`   // enqueued jobs not completed yet
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -21,8 +21,7 @@ func TestCFLookup_ToResponse(t *testing.T) {
 		AddressUsed:    "Rua Teste, 123",
 		DistanceMeters: 500,
 		LookupSource:   "mcp",
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		Timestamps:     Timestamps{CreatedAt: now, UpdatedAt: now},
 		IsActive:       true,
 		CFData: CFInfo{
 			IDEquipamento:        &idEquip,
@@ -103,6 +102,17 @@ func TestCFLookup_ToClinicaFamilia(t *testing.T) {
 	assert.NotEmpty(t, *clinica.HorarioAtendimento)
 }
 
+func TestCFLookup_ToClinicaFamilia_Stale(t *testing.T) {
+	cf := &CFLookup{CFData: CFInfo{NomePopular: "Clinica do Centro"}}
+
+	assert.Nil(t, cf.ToClinicaFamilia().Stale)
+
+	cf.Stale = true
+	clinica := cf.ToClinicaFamilia()
+	require.NotNil(t, clinica.Stale)
+	assert.True(t, *clinica.Stale)
+}
+
 func TestCFLookup_ToClinicaFamilia_Nil(t *testing.T) {
 	var cf *CFLookup = nil
 
@@ -307,8 +317,7 @@ func TestCFLookup_ToResponse_WithAllFields(t *testing.T) {
 		AddressUsed:    "Av Test, 999",
 		DistanceMeters: 1500,
 		LookupSource:   "mcp",
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		Timestamps:     Timestamps{CreatedAt: now, UpdatedAt: now},
 		IsActive:       true,
 		CFData: CFInfo{
 			IDEquipamento: &idEquip,
@@ -478,3 +487,56 @@ func TestCFLookup_ToEquipeSaudeFamilia_ProfissionalSaudeStructure(t *testing.T)
 	assert.Equal(t, "Dr. Test", *equipe.Medicos[0].Nome)
 	assert.Equal(t, "Enf. Test", *equipe.Enfermeiros[0].Nome)
 }
+
+func TestCFLookup_ToCFTeamsBatchItem(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cf := &CFLookup{
+		CFData: CFInfo{
+			NomePopular: "CF Test",
+			Logradouro:  "Rua Test",
+			Numero:      "100",
+			Bairro:      "Centro",
+		},
+		EquipeSaudeData: &EquipeSaudeInfo{
+			NomeOficial: "Equipe Test",
+			Medicos:     []string{"Dr. Test"},
+		},
+		Timestamps: Timestamps{UpdatedAt: updatedAt},
+	}
+
+	item := cf.ToCFTeamsBatchItem("12345678901")
+
+	assert.Equal(t, "12345678901", item.CPF)
+	assert.True(t, item.HasCFData)
+	require.NotNil(t, item.ClinicaFamilia)
+	assert.Equal(t, "CF Test", *item.ClinicaFamilia.Nome)
+	require.NotNil(t, item.EquipeSaudeFamilia)
+	assert.Equal(t, "Equipe Test", *item.EquipeSaudeFamilia.Nome)
+	require.NotNil(t, item.UpdatedAt)
+	assert.Equal(t, updatedAt, *item.UpdatedAt)
+}
+
+func TestCFLookup_ToCFTeamsBatchItem_Nil(t *testing.T) {
+	var cf *CFLookup
+
+	item := cf.ToCFTeamsBatchItem("12345678901")
+
+	assert.Equal(t, "12345678901", item.CPF)
+	assert.False(t, item.HasCFData)
+	assert.Nil(t, item.ClinicaFamilia)
+	assert.Nil(t, item.EquipeSaudeFamilia)
+	assert.Nil(t, item.UpdatedAt)
+}
+
+func TestNewCFCoverageRegion(t *testing.T) {
+	region := NewCFCoverageRegion("Centro", 3, 1)
+	assert.Equal(t, "Centro", region.Region)
+	assert.Equal(t, 3, region.WithCF)
+	assert.Equal(t, 1, region.NoCFFound)
+	assert.Equal(t, 4, region.Total)
+	assert.InDelta(t, 0.75, region.CoverageRate, 0.0001)
+
+	empty := NewCFCoverageRegion("", 0, 0)
+	assert.Equal(t, 0, empty.Total)
+	assert.Zero(t, empty.CoverageRate)
+}
@@ -111,11 +111,13 @@ type TelefoneAlternativo struct {
 	Valor   *string `json:"valor" bson:"valor,omitempty"`
 }
 
-// Telefone represents phone information
+// Telefone represents phone information. Verificados lists the phones the citizen verified and the
+// primary one is also the Principal; the merged citizen data only lists the other verified phones.
 type Telefone struct {
 	Indicador   *bool                 `json:"indicador" bson:"indicador,omitempty"`
 	Principal   *TelefonePrincipal    `json:"principal" bson:"principal,omitempty"`
 	Alternativo []TelefoneAlternativo `json:"alternativo" bson:"alternativo,omitempty"`
+	Verificados []TelefoneVerificado  `json:"verificados,omitempty" bson:"verificados,omitempty"`
 }
 
 // ClinicaFamilia represents family clinic information
@@ -128,6 +130,7 @@ type ClinicaFamilia struct {
 	Endereco           *string `json:"endereco" bson:"endereco,omitempty"`
 	HorarioAtendimento *string `json:"horario_atendimento" bson:"horario_atendimento,omitempty"`
 	Fonte              *string `json:"fonte,omitempty" bson:"-"` // "bigquery" or "mcp" - not stored in DB, populated at response time
+	Stale              *bool   `json:"stale,omitempty" bson:"-"` // true when MCP data is past CF_LOOKUP_MAX_AGE and being refreshed
 }
 
 // EquipeSaudeFamilia represents family health team information
@@ -222,6 +225,10 @@ type Citizen struct {
 	Endereco      *Endereco   `json:"endereco" bson:"endereco,omitempty"`
 	Email         *Email      `json:"email" bson:"email,omitempty"`
 	Telefone      *Telefone   `json:"telefone" bson:"telefone,omitempty"`
+	// IDs of the citizen in other municipal systems, keyed by system name
+	ExternalIDs map[string]string `json:"external_ids,omitempty" bson:"external_ids,omitempty"`
+	// Staleness of each self-declared field present, keyed by field name (not stored)
+	SelfDeclaredStatus map[string]SelfDeclaredFieldStatus `json:"self_declared_status,omitempty" bson:"-"`
 	// Wallet and internal fields
 	Documentos        *Documentos        `json:"documentos,omitempty" bson:"documentos,omitempty"`
 	Saude             *Saude             `json:"saude,omitempty" bson:"saude,omitempty"`
@@ -253,6 +260,20 @@ type CitizenResponse struct {
 	Endereco      *Endereco   `json:"endereco" bson:"endereco,omitempty"`
 	Email         *Email      `json:"email" bson:"email,omitempty"`
 	Telefone      *Telefone   `json:"telefone" bson:"telefone,omitempty"`
+	// IDs of the citizen in other municipal systems, keyed by system name
+	ExternalIDs map[string]string `json:"external_ids,omitempty" bson:"external_ids,omitempty"`
+	// Staleness of each self-declared field present, keyed by field name
+	SelfDeclaredStatus map[string]SelfDeclaredFieldStatus `json:"self_declared_status,omitempty" bson:"-"`
+	// Server-computed values, only present when requested with include_derived=true
+	Derived *CitizenDerivedFields `json:"_derived,omitempty" bson:"-"`
+}
+
+// CitizenDerivedFields holds values computed from source data, kept apart to avoid confusion with it
+type CitizenDerivedFields struct {
+	EnderecoFormatado *string `json:"endereco_formatado,omitempty"`
+	Idade             *int    `json:"idade,omitempty"`
+	EmailMascarado    *string `json:"email_mascarado,omitempty"`
+	TelefoneMascarado *string `json:"telefone_mascarado,omitempty"`
 }
 
 // ToCitizenResponse converts a Citizen to CitizenResponse (excluding wallet fields)
@@ -276,7 +297,81 @@ func (c *Citizen) ToCitizenResponse() *CitizenResponse {
 		Endereco:      c.Endereco,
 		Email:         c.Email,
 		Telefone:      c.Telefone,
+		ExternalIDs:   c.ExternalIDs,
+
+		SelfDeclaredStatus: c.SelfDeclaredStatus,
+	}
+}
+
+// FieldMasker masks PII values for restricted responses (implemented by utils.PIIMasker,
+// declared here because models cannot import utils)
+type FieldMasker interface {
+	MaskCPF(cpf string) string
+	MaskPhone(phone string) string
+	MaskEmail(email string) string
+}
+
+// ToCitizenResponseMasked converts a Citizen to CitizenResponse with CPF, emails and phones masked.
+// Contact data is copied so the source citizen (which may be cached) is never modified.
+func (c *Citizen) ToCitizenResponseMasked(masker FieldMasker) *CitizenResponse {
+	response := c.ToCitizenResponse()
+	if response.ID == c.CPF {
+		response.ID = masker.MaskCPF(c.CPF)
+	}
+	response.CPF = masker.MaskCPF(c.CPF)
+	response.Email = maskEmail(c.Email, masker)
+	response.Telefone = maskTelefone(c.Telefone, masker)
+	return response
+}
+
+func maskValue(value *string, mask func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	masked := mask(*value)
+	return &masked
+}
+
+func maskEmail(email *Email, masker FieldMasker) *Email {
+	if email == nil {
+		return nil
+	}
+
+	masked := &Email{Indicador: email.Indicador}
+	if email.Principal != nil {
+		principal := *email.Principal
+		principal.Valor = maskValue(principal.Valor, masker.MaskEmail)
+		masked.Principal = &principal
+	}
+	if email.Alternativo != nil {
+		masked.Alternativo = make([]EmailAlternativo, len(email.Alternativo))
+		for i, alt := range email.Alternativo {
+			alt.Valor = maskValue(alt.Valor, masker.MaskEmail)
+			masked.Alternativo[i] = alt
+		}
 	}
+	return masked
+}
+
+func maskTelefone(telefone *Telefone, masker FieldMasker) *Telefone {
+	if telefone == nil {
+		return nil
+	}
+
+	masked := &Telefone{Indicador: telefone.Indicador}
+	if telefone.Principal != nil {
+		principal := *telefone.Principal
+		principal.Valor = maskValue(principal.Valor, masker.MaskPhone)
+		masked.Principal = &principal
+	}
+	if telefone.Alternativo != nil {
+		masked.Alternativo = make([]TelefoneAlternativo, len(telefone.Alternativo))
+		for i, alt := range telefone.Alternativo {
+			alt.Valor = maskValue(alt.Valor, masker.MaskPhone)
+			masked.Alternativo[i] = alt
+		}
+	}
+	return masked
 }
 
 // CitizenWallet represents citizen wallet information
@@ -286,6 +381,36 @@ type CitizenWallet struct {
 	Saude             *Saude             `json:"saude" bson:"saude,omitempty"`
 	AssistenciaSocial *AssistenciaSocial `json:"assistencia_social" bson:"assistencia_social,omitempty"`
 	Educacao          *Educacao          `json:"educacao" bson:"educacao,omitempty"`
+	// ClinicaFamiliaStatus explains why saude.clinica_familia is (or isn't) filled in
+	ClinicaFamiliaStatus string `json:"clinica_familia_status,omitempty" bson:"-" example:"no_equipment"`
+	// CFLookupPending is set when the CF lookup was queued to run in background, so the client can
+	// fetch the wallet again later to get saude.clinica_familia
+	CFLookupPending bool `json:"cf_lookup_pending,omitempty" bson:"-"`
+	// Warnings lists the sections that failed to load and are returned as null
+	Warnings []WalletWarning `json:"warnings,omitempty" bson:"-"`
+}
+
+// WalletWarning reports a wallet section that couldn't be built, so the rest of the wallet is
+// still returned
+type WalletWarning struct {
+	Section string `json:"section" example:"educacao"`
+	Message string `json:"message" example:"section unavailable"`
+}
+
+// Wallet sections that can be requested with GET /citizen/{cpf}/wallet?sections=
+const (
+	WalletSectionDocumentos        = "documentos"
+	WalletSectionSaude             = "saude"
+	WalletSectionAssistenciaSocial = "assistencia_social"
+	WalletSectionEducacao          = "educacao"
+)
+
+// WalletSections lists every wallet section, in response order
+var WalletSections = []string{
+	WalletSectionDocumentos,
+	WalletSectionSaude,
+	WalletSectionAssistenciaSocial,
+	WalletSectionEducacao,
 }
 
 // MaintenanceRequestDocument represents the new document structure for 1746 calls
@@ -459,14 +584,16 @@ func (doc *MaintenanceRequestDocument) ConvertToMaintenanceRequest() *Maintenanc
 type PaginatedMaintenanceRequests struct {
 	Data       []MaintenanceRequest `json:"data"`
 	Pagination struct {
-		Page       int `json:"page"`
-		PerPage    int `json:"per_page"`
-		Total      int `json:"total"`
-		TotalPages int `json:"total_pages"`
+		Page       int    `json:"page,omitempty"` // Not set in cursor mode
+		PerPage    int    `json:"per_page"`
+		Total      int    `json:"total"`
+		TotalPages int    `json:"total_pages"`
+		NextCursor string `json:"next_cursor,omitempty"` // Cursor of the next page in cursor mode; empty on the last page
 	} `json:"pagination"`
 }
 
-// ValidEthnicityOptions returns a list of valid ethnicity options
+// ValidEthnicityOptions returns the built-in ethnicity options. The options served and accepted
+// at runtime are admin-editable and seeded from this list (see services.IsValidEthnicity).
 func ValidEthnicityOptions() []string {
 	return []string{
 		"branca",
@@ -478,7 +605,7 @@ func ValidEthnicityOptions() []string {
 	}
 }
 
-// IsValidEthnicity checks if a given ethnicity value is valid
+// IsValidEthnicity checks if a given ethnicity value is one of the built-in options
 func IsValidEthnicity(value string) bool {
 	for _, valid := range ValidEthnicityOptions() {
 		if valid == value {
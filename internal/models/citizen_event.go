@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Citizen event types published on the internal event bus and streamed through GET /citizen/{cpf}/events
+const (
+	CitizenEventPhoneVerified      = "phone_verified"
+	CitizenEventCFDataReady        = "cf_data_ready"
+	CitizenEventSelfDeclaredSynced = "self_declared_synced"
+	CitizenEventMaintenanceChanged = "maintenance_changed"
+)
+
+// CitizenEvent represents a real-time update about a citizen's data
+type CitizenEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CPF       string                 `json:"cpf,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// DecodeData decodes the event data into the typed payload of its event type
+func (e *CitizenEvent) DecodeData(v interface{}) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// PhoneVerifiedEventData is the payload of a phone_verified event
+type PhoneVerifiedEventData struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+// CFDataReadyEventData is the payload of a cf_data_ready event
+type CFDataReadyEventData struct {
+	AddressHash string `json:"address_hash,omitempty"`
+}
+
+// SelfDeclaredSyncedEventData is the payload of a self_declared_synced event
+type SelfDeclaredSyncedEventData struct {
+	Field string `json:"field"`
+}
+
+// MaintenanceChangedEventData is the payload of a maintenance_changed event
+type MaintenanceChangedEventData struct {
+	RequestID string `json:"request_id"`
+	Status    string `json:"status,omitempty"`
+}
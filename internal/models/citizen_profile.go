@@ -0,0 +1,11 @@
+package models
+
+// CitizenProfileResponse bundles the citizen data, wallet and profile completeness shown on the
+// app home screen, so it takes a single request
+type CitizenProfileResponse struct {
+	Citizen *CitizenResponse `json:"citizen"`
+	// Wallet is a CitizenWallet, holding only cpf and the requested sections when sections is given
+	Wallet interface{} `json:"wallet"`
+	// Completeness is omitted when the data is served stale, since it needs the user config
+	Completeness *CitizenCompletenessResponse `json:"completeness,omitempty"`
+}
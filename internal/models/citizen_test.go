@@ -379,6 +379,89 @@ func TestToCitizenResponse(t *testing.T) {
 	// This is the key difference - CitizenResponse should not have Documentos, Saude, etc.
 }
 
+type stubMasker struct{}
+
+func (stubMasker) MaskCPF(cpf string) string     { return "cpf:" + cpf }
+func (stubMasker) MaskPhone(phone string) string { return "phone:" + phone }
+func (stubMasker) MaskEmail(email string) string { return "email:" + email }
+
+func TestToCitizenResponseMasked(t *testing.T) {
+	nome := "João Silva"
+	emailPrincipal := "joao@example.com"
+	emailAlt := "joao.alt@example.com"
+	ddd := "21"
+	phonePrincipal := "987654321"
+	phoneAlt := "912345678"
+
+	citizen := &Citizen{
+		ID:   "12345678909",
+		CPF:  "12345678909",
+		Nome: &nome,
+		Email: &Email{
+			Principal:   &EmailPrincipal{Valor: &emailPrincipal},
+			Alternativo: []EmailAlternativo{{Valor: &emailAlt}},
+		},
+		Telefone: &Telefone{
+			Principal:   &TelefonePrincipal{DDD: &ddd, Valor: &phonePrincipal},
+			Alternativo: []TelefoneAlternativo{{Valor: &phoneAlt}, {Valor: nil}},
+		},
+	}
+
+	response := citizen.ToCitizenResponseMasked(stubMasker{})
+
+	if response.CPF != "cpf:12345678909" {
+		t.Errorf("ToCitizenResponseMasked() CPF = %v, want masked", response.CPF)
+	}
+	if response.ID != "cpf:12345678909" {
+		t.Errorf("ToCitizenResponseMasked() ID = %v, want masked when it holds the CPF", response.ID)
+	}
+	if *response.Nome != nome {
+		t.Errorf("ToCitizenResponseMasked() Nome = %v, want %v", *response.Nome, nome)
+	}
+	if *response.Email.Principal.Valor != "email:joao@example.com" {
+		t.Errorf("ToCitizenResponseMasked() email principal = %v", *response.Email.Principal.Valor)
+	}
+	if *response.Email.Alternativo[0].Valor != "email:joao.alt@example.com" {
+		t.Errorf("ToCitizenResponseMasked() email alternativo = %v", *response.Email.Alternativo[0].Valor)
+	}
+	if *response.Telefone.Principal.Valor != "phone:987654321" {
+		t.Errorf("ToCitizenResponseMasked() telefone principal = %v", *response.Telefone.Principal.Valor)
+	}
+	if *response.Telefone.Principal.DDD != ddd {
+		t.Errorf("ToCitizenResponseMasked() DDD = %v, want %v", *response.Telefone.Principal.DDD, ddd)
+	}
+	if *response.Telefone.Alternativo[0].Valor != "phone:912345678" {
+		t.Errorf("ToCitizenResponseMasked() telefone alternativo = %v", *response.Telefone.Alternativo[0].Valor)
+	}
+	if response.Telefone.Alternativo[1].Valor != nil {
+		t.Errorf("ToCitizenResponseMasked() nil telefone alternativo should stay nil")
+	}
+
+	// Source citizen must be left untouched
+	if *citizen.Email.Principal.Valor != emailPrincipal || *citizen.Email.Alternativo[0].Valor != emailAlt {
+		t.Errorf("ToCitizenResponseMasked() modified source emails")
+	}
+	if *citizen.Telefone.Principal.Valor != phonePrincipal || *citizen.Telefone.Alternativo[0].Valor != phoneAlt {
+		t.Errorf("ToCitizenResponseMasked() modified source phones")
+	}
+	if citizen.CPF != "12345678909" {
+		t.Errorf("ToCitizenResponseMasked() modified source CPF")
+	}
+}
+
+func TestToCitizenResponseMasked_NilContacts(t *testing.T) {
+	citizen := &Citizen{ID: "object-id", CPF: "12345678909"}
+
+	response := citizen.ToCitizenResponseMasked(stubMasker{})
+
+	if response.ID != "object-id" {
+		t.Errorf("ToCitizenResponseMasked() ID = %v, want object-id", response.ID)
+	}
+	if response.Email != nil || response.Telefone != nil {
+		t.Errorf("ToCitizenResponseMasked() should keep nil contacts nil")
+	}
+}
+
 func TestConvertToMaintenanceRequest(t *testing.T) {
 	// Test data
 	doc := &MaintenanceRequestDocument{
@@ -558,3 +641,18 @@ func TestValidDisabilityOptions(t *testing.T) {
 		t.Errorf("ValidDisabilityOptions() returned %d options, want %d", len(options), expectedCount)
 	}
 }
+
+func TestCitizenEvent_DecodeData(t *testing.T) {
+	event := CitizenEvent{
+		Type: CitizenEventMaintenanceChanged,
+		Data: map[string]interface{}{"request_id": "req-1", "status": "aberto"},
+	}
+
+	var data MaintenanceChangedEventData
+	if err := event.DecodeData(&data); err != nil {
+		t.Fatalf("DecodeData() error = %v", err)
+	}
+	if data.RequestID != "req-1" || data.Status != "aberto" {
+		t.Errorf("DecodeData() = %+v, want request_id req-1 and status aberto", data)
+	}
+}
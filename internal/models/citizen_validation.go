@@ -0,0 +1,237 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Field error codes returned by the citizen validators (same values as utils.ValidationCode*,
+// declared here because models cannot import utils)
+const (
+	CitizenFieldCodeRequired      = "required"
+	CitizenFieldCodeInvalidFormat = "invalid_format"
+	CitizenFieldCodeInvalidValue  = "invalid_value"
+)
+
+// CitizenFieldError describes a single invalid field of citizen data
+type CitizenFieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CitizenValidationError lists every invalid field found while validating citizen data
+type CitizenValidationError struct {
+	Errors []CitizenFieldError `json:"errors"`
+}
+
+func (e *CitizenValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return "invalid citizen data: " + strings.Join(parts, "; ")
+}
+
+// citizenValidator collects field errors so all of them are reported at once
+type citizenValidator struct {
+	errors []CitizenFieldError
+}
+
+func (v *citizenValidator) add(field, code, message string) {
+	v.errors = append(v.errors, CitizenFieldError{Field: field, Code: code, Message: message})
+}
+
+func (v *citizenValidator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &CitizenValidationError{Errors: v.errors}
+}
+
+// Validate checks the citizen invariants the merge and wallet logic rely on: a valid CPF,
+// non-negative ages, well-formed nested address/email/phone and known values for the
+// self-declared enum fields. It returns a *CitizenValidationError listing every invalid field.
+func (c *Citizen) Validate() error {
+	v := &citizenValidator{}
+	now := time.Now()
+
+	if c.CPF == "" {
+		v.add("cpf", CitizenFieldCodeRequired, "cpf is required")
+	} else if !isValidCPF(c.CPF) {
+		v.add("cpf", CitizenFieldCodeInvalidFormat, "cpf has an invalid format")
+	}
+	if c.Mae != nil && c.Mae.CPF != nil && *c.Mae.CPF != "" && !isValidCPF(*c.Mae.CPF) {
+		v.add("mae.cpf", CitizenFieldCodeInvalidFormat, "mae.cpf has an invalid format")
+	}
+
+	if c.Nascimento != nil && c.Nascimento.Data != nil && c.Nascimento.Data.After(now) {
+		v.add("nascimento.data", CitizenFieldCodeInvalidValue, "nascimento.data must not be in the future")
+	}
+	if c.Obito != nil && c.Obito.Ano != nil {
+		if *c.Obito.Ano < 0 || int(*c.Obito.Ano) > now.Year() {
+			v.add("obito.ano", CitizenFieldCodeInvalidValue, "obito.ano must be a past year")
+		} else if c.Nascimento != nil && c.Nascimento.Data != nil && int(*c.Obito.Ano) < c.Nascimento.Data.Year() {
+			v.add("obito.ano", CitizenFieldCodeInvalidValue, "obito.ano must not be before the year of birth")
+		}
+	}
+
+	if c.Endereco != nil {
+		c.Endereco.validate(v, "endereco")
+	}
+	if c.Email != nil {
+		c.Email.validate(v, "email")
+	}
+	if c.Telefone != nil {
+		c.Telefone.validate(v, "telefone")
+	}
+
+	validateEnum(v, "genero", c.Genero, IsValidGender)
+	validateEnum(v, "renda_familiar", c.RendaFamiliar, IsValidFamilyIncome)
+	validateEnum(v, "escolaridade", c.Escolaridade, IsValidEducation)
+	validateEnum(v, "deficiencia", c.Deficiencia, IsValidDisability)
+
+	return v.err()
+}
+
+// Validate checks that the address has the fields required to build it. CEP and estado are
+// checked by the address_cep and address_uf validation rules instead, whose mode (shadow or
+// enforce) is configured in VALIDATION_RULES.
+func (e *Endereco) Validate() error {
+	v := &citizenValidator{}
+	e.validate(v, "endereco")
+	return v.err()
+}
+
+func (e *Endereco) validate(v *citizenValidator, field string) {
+	if p := e.Principal; p != nil {
+		prefix := field + ".principal"
+		for _, f := range []struct {
+			name  string
+			value *string
+		}{{"logradouro", p.Logradouro}, {"bairro", p.Bairro}, {"municipio", p.Municipio}} {
+			if f.value != nil && strings.TrimSpace(*f.value) == "" {
+				v.add(prefix+"."+f.name, CitizenFieldCodeRequired, prefix+"."+f.name+" must not be empty")
+			}
+		}
+	}
+}
+
+// Validate checks that every email address is well formed
+func (e *Email) Validate() error {
+	v := &citizenValidator{}
+	e.validate(v, "email")
+	return v.err()
+}
+
+func (e *Email) validate(v *citizenValidator, field string) {
+	if e.Principal != nil {
+		validateEmailAddress(v, field+".principal.valor", e.Principal.Valor)
+	}
+	for i, alt := range e.Alternativo {
+		validateEmailAddress(v, fmt.Sprintf("%s.alternativo[%d].valor", field, i), alt.Valor)
+	}
+}
+
+// Validate checks that every phone number has a value and that DDI, DDD and number are numeric
+func (t *Telefone) Validate() error {
+	v := &citizenValidator{}
+	t.validate(v, "telefone")
+	return v.err()
+}
+
+func (t *Telefone) validate(v *citizenValidator, field string) {
+	if p := t.Principal; p != nil {
+		validatePhone(v, field+".principal", p.DDI, p.DDD, p.Valor)
+	}
+	for i, alt := range t.Alternativo {
+		validatePhone(v, fmt.Sprintf("%s.alternativo[%d]", field, i), alt.DDI, alt.DDD, alt.Valor)
+	}
+	for i, verified := range t.Verificados {
+		validatePhone(v, fmt.Sprintf("%s.verificados[%d]", field, i), verified.DDI, verified.DDD, verified.Valor)
+	}
+}
+
+func validateEmailAddress(v *citizenValidator, field string, email *string) {
+	if email == nil {
+		return
+	}
+	at := strings.LastIndex(*email, "@")
+	if at <= 0 || at == len(*email)-1 || strings.ContainsAny(*email, " \t\n") {
+		v.add(field, CitizenFieldCodeInvalidFormat, field+" has an invalid format")
+	}
+}
+
+func validatePhone(v *citizenValidator, prefix string, ddi, ddd, valor *string) {
+	if valor == nil || *valor == "" {
+		v.add(prefix+".valor", CitizenFieldCodeRequired, prefix+".valor is required")
+	} else if !isDigits(*valor) {
+		v.add(prefix+".valor", CitizenFieldCodeInvalidFormat, prefix+".valor must contain only digits")
+	}
+	if ddi != nil && *ddi != "" && !isDigits(*ddi) {
+		v.add(prefix+".ddi", CitizenFieldCodeInvalidFormat, prefix+".ddi must contain only digits")
+	}
+	if ddd != nil && *ddd != "" && !isDigits(*ddd) {
+		v.add(prefix+".ddd", CitizenFieldCodeInvalidFormat, prefix+".ddd must contain only digits")
+	}
+}
+
+func validateEnum(v *citizenValidator, field string, value *string, valid func(string) bool) {
+	if value != nil && !valid(*value) {
+		v.add(field, CitizenFieldCodeInvalidValue, field+" is not a valid option")
+	}
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isValidCPF checks length and check digits of a CPF with or without punctuation (mirrors
+// utils.ValidateCPF, which models cannot import)
+func isValidCPF(cpf string) bool {
+	digits := make([]int, 0, 11)
+	for _, r := range cpf {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, int(r-'0'))
+		case r == '.' || r == '-':
+		default:
+			return false
+		}
+	}
+	if len(digits) != 11 {
+		return false
+	}
+
+	allSame := true
+	for _, d := range digits[1:] {
+		if d != digits[0] {
+			allSame = false
+			break
+		}
+	}
+	if allSame {
+		return false
+	}
+
+	for n := 9; n <= 10; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += digits[i] * (n + 1 - i)
+		}
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		if digits[n] != check {
+			return false
+		}
+	}
+	return true
+}
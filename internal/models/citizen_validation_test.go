@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCitizenValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	past := time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC)
+	future := time.Now().AddDate(1, 0, 0)
+	ano := func(y int32) *int32 { return &y }
+
+	tests := []struct {
+		name       string
+		citizen    Citizen
+		wantFields []string
+	}{
+		{
+			name: "valid citizen",
+			citizen: Citizen{
+				CPF:        "03561350712",
+				Nascimento: &Nascimento{Data: &past},
+				Mae:        &Mae{CPF: str("111.444.777-35")},
+				Endereco: &Endereco{Principal: &EnderecoPrincipal{
+					CEP: str("20040-020"), Estado: str("RJ"), Logradouro: str("Rua A"),
+				}},
+				Email:         &Email{Principal: &EmailPrincipal{Valor: str("joao@example.com")}},
+				Telefone:      &Telefone{Principal: &TelefonePrincipal{DDI: str("55"), DDD: str("21"), Valor: str("987654321")}},
+				Escolaridade:  str("Superior completo"),
+				RendaFamiliar: str("1 a 2 salários mínimos"),
+			},
+		},
+		{
+			name:       "missing CPF",
+			citizen:    Citizen{},
+			wantFields: []string{"cpf"},
+		},
+		{
+			name:       "invalid CPF check digits",
+			citizen:    Citizen{CPF: "12345678900"},
+			wantFields: []string{"cpf"},
+		},
+		{
+			name:       "invalid mother CPF",
+			citizen:    Citizen{CPF: "03561350712", Mae: &Mae{CPF: str("11111111111")}},
+			wantFields: []string{"mae.cpf"},
+		},
+		{
+			name:       "birth date in the future",
+			citizen:    Citizen{CPF: "03561350712", Nascimento: &Nascimento{Data: &future}},
+			wantFields: []string{"nascimento.data"},
+		},
+		{
+			name:       "death before birth",
+			citizen:    Citizen{CPF: "03561350712", Nascimento: &Nascimento{Data: &past}, Obito: &Obito{Ano: ano(1980)}},
+			wantFields: []string{"obito.ano"},
+		},
+		{
+			// CEP and estado are left to the address_cep and address_uf validation rules
+			name: "malformed address",
+			citizen: Citizen{CPF: "03561350712", Endereco: &Endereco{
+				Principal:   &EnderecoPrincipal{CEP: str("123"), Estado: str("Rio"), Bairro: str(" ")},
+				Alternativo: []EnderecoAlternativo{{CEP: str("abcdefgh")}},
+			}},
+			wantFields: []string{"endereco.principal.bairro"},
+		},
+		{
+			name: "malformed email and phone",
+			citizen: Citizen{
+				CPF:      "03561350712",
+				Email:    &Email{Alternativo: []EmailAlternativo{{Valor: str("not-an-email")}}},
+				Telefone: &Telefone{Principal: &TelefonePrincipal{DDD: str("2a")}},
+			},
+			wantFields: []string{"email.alternativo[0].valor", "telefone.principal.valor", "telefone.principal.ddd"},
+		},
+		{
+			name:       "unknown enum values",
+			citizen:    Citizen{CPF: "03561350712", Escolaridade: str("doutorado em tudo"), Deficiencia: str("x")},
+			wantFields: []string{"escolaridade", "deficiencia"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.citizen.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *CitizenValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *CitizenValidationError", err)
+			}
+			if len(validationErr.Errors) != len(tt.wantFields) {
+				t.Fatalf("Validate() returned %d errors (%v), want %d", len(validationErr.Errors), validationErr, len(tt.wantFields))
+			}
+			for i, field := range tt.wantFields {
+				if validationErr.Errors[i].Field != field {
+					t.Errorf("Validate() error %d field = %q, want %q", i, validationErr.Errors[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestIsValidCPF(t *testing.T) {
+	tests := []struct {
+		cpf  string
+		want bool
+	}{
+		{"03561350712", true},
+		{"035.613.507-12", true},
+		{"03561350713", false},
+		{"00000000000", false},
+		{"1234567890", false},
+		{"0356135071a", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidCPF(tt.cpf); got != tt.want {
+			t.Errorf("isValidCPF(%q) = %v, want %v", tt.cpf, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "math"
+
+// CitizenCompletenessResponse reports how complete a citizen's profile is
+type CitizenCompletenessResponse struct {
+	CPF     string          `json:"cpf"`
+	Score   int             `json:"score"`   // 0-100
+	Fields  map[string]bool `json:"fields"`  // Whether each field is filled in (or verified)
+	Weights map[string]int  `json:"weights"` // Weight of each field in the score
+}
+
+// CompletenessScore returns the weighted share (0-100) of the fields that are filled in.
+// Fields without a weight don't count.
+func CompletenessScore(fields map[string]bool, weights map[string]int) int {
+	total, achieved := 0, 0
+	for field, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		if fields[field] {
+			achieved += weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(float64(achieved) * 100 / float64(total)))
+}
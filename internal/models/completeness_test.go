@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestCompletenessScore(t *testing.T) {
+	weights := map[string]int{"telefone": 30, "email": 20, "endereco": 50}
+
+	tests := []struct {
+		name   string
+		fields map[string]bool
+		want   int
+	}{
+		{"nothing filled", map[string]bool{}, 0},
+		{"everything filled", map[string]bool{"telefone": true, "email": true, "endereco": true}, 100},
+		{"partially filled", map[string]bool{"telefone": true, "email": true, "endereco": false}, 50},
+		{"unweighted field ignored", map[string]bool{"avatar": true, "endereco": true}, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompletenessScore(tt.fields, weights); got != tt.want {
+				t.Errorf("CompletenessScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletenessScore_Rounding(t *testing.T) {
+	weights := map[string]int{"telefone": 1, "email": 1, "endereco": 1}
+
+	if got := CompletenessScore(map[string]bool{"telefone": true, "email": true}, weights); got != 67 {
+		t.Errorf("CompletenessScore() = %d, want 67", got)
+	}
+}
+
+func TestCompletenessScore_NoWeights(t *testing.T) {
+	if got := CompletenessScore(map[string]bool{"telefone": true}, map[string]int{"telefone": 0}); got != 0 {
+		t.Errorf("CompletenessScore() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DataFreshnessQuantiles are the data age quantiles reported per collection; 1 is the oldest
+// sampled document
+var DataFreshnessQuantiles = []string{"0.5", "0.9", "0.99", "1"}
+
+// CollectionFreshness is the estimated age distribution of a collection's documents, from the
+// updated_at field or, when missing, created_at
+type CollectionFreshness struct {
+	Collection string             `json:"collection"`
+	Sampled    int                `json:"sampled"`               // Documents sampled
+	Undated    int                `json:"undated"`               // Sampled documents without updated_at or created_at
+	AgeSeconds map[string]float64 `json:"age_seconds,omitempty"` // Age in seconds per quantile of the dated documents
+	Error      string             `json:"error,omitempty"`       // Why the collection couldn't be sampled
+}
+
+// DataFreshnessReport is the response of GET /admin/data-freshness
+type DataFreshnessReport struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	SampleSize  int                   `json:"sample_size"`
+	Collections []CollectionFreshness `json:"collections"`
+}
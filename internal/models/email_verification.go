@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailVerification represents an email verification request. When magic links are enabled the
+// record also holds the SHA-256 of the link nonce; the link itself is only kept in memory long
+// enough to be sent.
+type EmailVerification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CPF           string             `bson:"cpf" json:"cpf"`
+	Email         string             `bson:"email" json:"email"`
+	Token         string             `bson:"token" json:"-"`
+	LinkTokenHash string             `bson:"link_token_hash,omitempty" json:"-"`
+	Link          string             `bson:"-" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// EmailVerificationValidateRequest represents the request body for validating an email verification
+type EmailVerificationValidateRequest struct {
+	Token string `json:"token" binding:"required"`
+	Email string `json:"email" binding:"required"`
+}
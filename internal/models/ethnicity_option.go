@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// EthnicityOption is an admin-editable self-declared ethnicity option
+type EthnicityOption struct {
+	Value     string    `json:"value" bson:"_id"`
+	Order     int       `json:"order" bson:"order"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// CreateEthnicityOptionRequest is the body of POST /admin/ethnicity/options
+type CreateEthnicityOptionRequest struct {
+	Value string `json:"value" binding:"required,max=50" example:"quilombola"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Known feature flags, consulted by the handlers they gate
+const (
+	// FeatureFlagCFIntegration gates the Clínica da Família lookups of the citizen and wallet endpoints
+	FeatureFlagCFIntegration = "cf_integration"
+)
+
+// DefaultFeatureFlags holds the value of known flags that weren't created yet, so gating a
+// behavior doesn't change it until an admin defines the flag
+var DefaultFeatureFlags = map[string]bool{
+	FeatureFlagCFIntegration: true,
+}
+
+// featureFlagKeyPattern restricts flag keys to lowercase letters, digits and underscores
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// IsValidFeatureFlagKey reports whether key can name a feature flag
+func IsValidFeatureFlagKey(key string) bool {
+	return featureFlagKeyPattern.MatchString(key)
+}
+
+// FeatureFlag gates a behavior per cohort. A disabled flag is off for everyone (kill switch);
+// an enabled one is on for the listed CPFs, for members of the listed beta groups and for the
+// rollout percentage of the remaining CPFs.
+type FeatureFlag struct {
+	Key               string    `json:"key" bson:"_id"`
+	Description       string    `json:"description,omitempty" bson:"description,omitempty"`
+	Enabled           bool      `json:"enabled" bson:"enabled"`
+	CPFs              []string  `json:"cpfs,omitempty" bson:"cpfs,omitempty"`
+	BetaGroupIDs      []string  `json:"beta_group_ids,omitempty" bson:"beta_group_ids,omitempty"`
+	RolloutPercentage int       `json:"rollout_percentage" bson:"rollout_percentage"`
+	CreatedAt         time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// FeatureFlagRequest is the body of POST /admin/feature-flags and PUT /admin/feature-flags/{key}.
+// The key is only read on creation.
+type FeatureFlagRequest struct {
+	Key               string   `json:"key,omitempty" example:"cf_integration"`
+	Description       string   `json:"description,omitempty" binding:"max=200" example:"Integração com Clínica da Família"`
+	Enabled           bool     `json:"enabled" example:"true"`
+	CPFs              []string `json:"cpfs,omitempty" binding:"max=1000"`
+	BetaGroupIDs      []string `json:"beta_group_ids,omitempty" binding:"max=50"`
+	RolloutPercentage int      `json:"rollout_percentage" binding:"min=0,max=100" example:"10"`
+}
+
+// FeatureFlagListResponse is the response of GET /admin/feature-flags
+type FeatureFlagListResponse struct {
+	Flags []FeatureFlag `json:"flags"`
+}
+
+// FeatureFlagEvaluationResponse is the response of GET /config/flags
+type FeatureFlagEvaluationResponse struct {
+	CPF   string          `json:"cpf,omitempty"`
+	Flags map[string]bool `json:"flags"`
+}
@@ -0,0 +1,84 @@
+package models
+
+// Conditions under which a self-declared field overrides the base citizen data
+const (
+	MergeConditionPrincipalPresent = "principal_present" // the self-declared field has a principal value
+	MergeConditionValuePresent     = "value_present"     // the self-declared field is set
+	MergeConditionAlways           = "always"            // the field only exists as self-declared data
+)
+
+// How the base indicator ("indicador") is handled when the self-declared value wins
+const (
+	MergeIndicatorSetIfMissing = "set_if_missing" // set to true only when the base data has no indicator
+	MergeIndicatorSetTrue      = "set_true"       // always set to true
+)
+
+// MergeRule describes how a self-declared field is merged over the base citizen data
+type MergeRule struct {
+	Field             string `json:"field"`
+	Condition         string `json:"condition"`           // see MergeCondition* constants
+	RequiresIndicator bool   `json:"requires_indicator"`  // the self-declared indicador must be true
+	Indicator         string `json:"indicator,omitempty"` // see MergeIndicator* constants
+	Description       string `json:"description"`
+}
+
+// MergeRulesResponse lists the merge precedence rules currently in effect
+type MergeRulesResponse struct {
+	Rules []MergeRule `json:"rules"`
+}
+
+// SelfDeclaredMergeRules returns the precedence rules used to overlay self-declared data on the
+// base citizen data, in the order they are applied
+func SelfDeclaredMergeRules() []MergeRule {
+	return []MergeRule{
+		{
+			Field:       "endereco",
+			Condition:   MergeConditionPrincipalPresent,
+			Indicator:   MergeIndicatorSetIfMissing,
+			Description: "The self-declared principal address replaces the base principal address",
+		},
+		{
+			Field:       "email",
+			Condition:   MergeConditionPrincipalPresent,
+			Indicator:   MergeIndicatorSetIfMissing,
+			Description: "The self-declared principal email replaces the base principal email",
+		},
+		{
+			Field:             "telefone",
+			Condition:         MergeConditionPrincipalPresent,
+			RequiresIndicator: true,
+			Indicator:         MergeIndicatorSetTrue,
+			Description:       "The self-declared primary phone replaces the base principal phone only once verified (indicador=true); the other verified phones are listed in verificados",
+		},
+		{
+			Field:       "raca",
+			Condition:   MergeConditionValuePresent,
+			Description: "The self-declared ethnicity replaces the base ethnicity",
+		},
+		{
+			Field:       "nome_exibicao",
+			Condition:   MergeConditionAlways,
+			Description: "Exhibition name only exists as self-declared data and is always returned, even when empty",
+		},
+		{
+			Field:       "genero",
+			Condition:   MergeConditionAlways,
+			Description: "Gender only exists as self-declared data",
+		},
+		{
+			Field:       "renda_familiar",
+			Condition:   MergeConditionAlways,
+			Description: "Family income only exists as self-declared data",
+		},
+		{
+			Field:       "escolaridade",
+			Condition:   MergeConditionAlways,
+			Description: "Education only exists as self-declared data",
+		},
+		{
+			Field:       "deficiencia",
+			Condition:   MergeConditionAlways,
+			Description: "Disability only exists as self-declared data",
+		},
+	}
+}
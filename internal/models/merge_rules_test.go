@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestSelfDeclaredMergeRules(t *testing.T) {
+	conditions := map[string]bool{
+		MergeConditionPrincipalPresent: true,
+		MergeConditionValuePresent:     true,
+		MergeConditionAlways:           true,
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range SelfDeclaredMergeRules() {
+		if seen[rule.Field] {
+			t.Errorf("duplicate merge rule for %q", rule.Field)
+		}
+		seen[rule.Field] = true
+		if !conditions[rule.Condition] {
+			t.Errorf("merge rule %q has unknown condition %q", rule.Field, rule.Condition)
+		}
+		if rule.Description == "" {
+			t.Errorf("merge rule %q has no description", rule.Field)
+		}
+		if rule.RequiresIndicator != (rule.Field == "telefone") {
+			t.Errorf("merge rule %q RequiresIndicator = %v", rule.Field, rule.RequiresIndicator)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+// Reasons for a citizen to have no notification target
+const (
+	NotificationTargetReasonOptedOut        = "opted_out"         // the citizen opted out of notifications
+	NotificationTargetReasonNoVerifiedPhone = "no_verified_phone" // no verified phone in any configured source
+	NotificationTargetReasonPhonesBlocked   = "phones_blocked"    // every verified phone is quarantined or opted out
+)
+
+// Reasons for a verified phone to be skipped as notification target
+const (
+	NotificationPhoneSkipQuarantined = "quarantined"
+	NotificationPhoneSkipOptedOut    = "opted_out"
+)
+
+// NotificationTargetResponse is the phone notifications for a citizen should be sent to
+type NotificationTargetResponse struct {
+	CPF         string                   `json:"cpf"`
+	Found       bool                     `json:"found"`
+	PhoneNumber string                   `json:"phone_number,omitempty"` // Storage format (DDI + DDD + number)
+	Source      string                   `json:"source,omitempty"`       // "self_declared" or "base"
+	Reason      string                   `json:"reason,omitempty"`       // Why no phone was found
+	Skipped     []NotificationTargetSkip `json:"skipped,omitempty"`      // Verified phones passed over, in fallback order
+	// PreferredChannel is the channel the citizen prefers to be notified through, when chosen
+	PreferredChannel string `json:"preferred_channel,omitempty"`
+}
+
+// NotificationTargetSkip is a verified phone that can't receive notifications
+type NotificationTargetSkip struct {
+	Source      string `json:"source"`
+	PhoneNumber string `json:"phone_number"`
+	Reason      string `json:"reason"`
+}
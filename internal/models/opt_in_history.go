@@ -11,11 +11,12 @@ type OptInHistory struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	PhoneNumber      string             `bson:"phone_number,omitempty" json:"phone_number,omitempty"`
 	CPF              string             `bson:"cpf" json:"cpf"`
-	Action           string             `bson:"action" json:"action"` // opt_in, opt_out, category_update
+	Action           string             `bson:"action" json:"action"` // opt_in, opt_out, rejected, category_update, phone_demoted, reassigned
 	Scope            string             `bson:"scope" json:"scope"`   // global, category
 	Category         *string            `bson:"category,omitempty" json:"category,omitempty"`
 	Channel          string             `bson:"channel" json:"channel"`
 	Reason           *string            `bson:"reason,omitempty" json:"reason,omitempty"` // only for opt_out
+	Note             *string            `bson:"note,omitempty" json:"note,omitempty"`     // free-text note left with an opt_out
 	OldValue         *bool              `bson:"old_value,omitempty" json:"old_value,omitempty"`
 	NewValue         *bool              `bson:"new_value,omitempty" json:"new_value,omitempty"`
 	ValidationResult *ValidationResult  `bson:"validation_result,omitempty" json:"validation_result,omitempty"`
@@ -31,9 +32,18 @@ type ValidationResult struct {
 const (
 	OptInActionOptIn          = "opt_in"
 	OptInActionOptOut         = "opt_out"
+	OptInActionRejected       = "rejected" // registration rejected by the citizen
 	OptInActionCategoryUpdate = "category_update"
+	OptInActionPhoneDemoted   = "phone_demoted" // verified phone sent back to pending after repeated delivery failures
+	OptInActionReassigned     = "reassigned"    // phone bound to this CPF by an admin reassignment
 )
 
+// OptInHistoryListResponse represents a page of the opt-in history
+type OptInHistoryListResponse struct {
+	Data       []OptInHistory `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
 // OptInScope constants
 const (
 	OptInScopeGlobal   = "global"
@@ -47,3 +57,6 @@ const (
 	OptOutReasonIncorrectPerson   = "incorrect_person"
 	OptOutReasonTooManyMessages   = "too_many_messages"
 )
+
+// PhoneDemotionReasonDeliveryFailures is the history reason of phones demoted by delivery receipts
+const PhoneDemotionReasonDeliveryFailures = "delivery_failures"
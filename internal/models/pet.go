@@ -183,8 +183,7 @@ type SelfRegisteredPet struct {
 	SizeName           string     `bson:"porte_nome" json:"porte_nome"`
 	PhotoURL           string     `bson:"foto_url,omitempty" json:"foto_url,omitempty"`
 	Source             string     `bson:"source" json:"source"`
-	CreatedAt          time.Time  `bson:"created_at" json:"created_at"`
-	UpdatedAt          time.Time  `bson:"updated_at" json:"updated_at"`
+	Timestamps         `bson:",inline"`
 }
 
 // ToPet converts a SelfRegisteredPet to a Pet model
@@ -194,8 +194,7 @@ func TestSelfRegisteredPet_ToPet(t *testing.T) {
 		SizeName:           "Large",
 		PhotoURL:           "https://example.com/rex.jpg",
 		Source:             "self_registered",
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+		Timestamps:         Timestamps{CreatedAt: time.Now(), UpdatedAt: time.Now()},
 	}
 
 	pet := selfRegistered.ToPet()
@@ -12,6 +12,7 @@ type PhoneCPFMapping struct {
 	OptIn             bool              `bson:"opt_in" json:"opt_in"`
 	CategoryOptIns    map[string]bool   `bson:"category_opt_ins,omitempty" json:"category_opt_ins,omitempty"`
 	QuarantineUntil   *time.Time        `bson:"quarantine_until,omitempty" json:"quarantine_until,omitempty"`
+	QuarantineReason  string            `bson:"quarantine_reason,omitempty" json:"quarantine_reason,omitempty"`
 	QuarantineHistory []QuarantineEvent `bson:"quarantine_history,omitempty" json:"quarantine_history,omitempty"`
 	ValidationAttempt ValidationAttempt `bson:"validation_attempt,omitempty" json:"validation_attempt,omitempty"`
 	Channel           string            `bson:"channel,omitempty" json:"channel,omitempty"`
@@ -25,6 +26,7 @@ type QuarantineEvent struct {
 	QuarantinedAt   time.Time  `bson:"quarantined_at" json:"quarantined_at"`
 	QuarantineUntil time.Time  `bson:"quarantine_until" json:"quarantine_until"`
 	ReleasedAt      *time.Time `bson:"released_at,omitempty" json:"released_at,omitempty"`
+	Reason          string     `bson:"reason,omitempty" json:"reason,omitempty"`
 }
 
 // ValidationAttempt represents validation attempt details
@@ -50,6 +52,23 @@ type PhoneStatusResponse struct {
 	BetaGroupName   string          `json:"beta_group_name,omitempty"`
 }
 
+// PhoneStatusDetailsResponse represents the phone status enriched for routing decisions of service
+// callers (e.g. the chatbot), aggregated from the phone mappings, beta groups and opt-in history
+type PhoneStatusDetailsResponse struct {
+	PhoneStatusResponse
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
+	// BoundCPFCount is the number of CPFs with an active mapping for the phone
+	BoundCPFCount   int                     `json:"bound_cpf_count"`
+	LastOptInAction *PhoneStatusOptInAction `json:"last_opt_in_action,omitempty"`
+}
+
+// PhoneStatusOptInAction is the latest opt-in history entry of a phone
+type PhoneStatusOptInAction struct {
+	Action    string    `json:"action"`
+	Channel   string    `json:"channel"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // QuarantineRequest represents the request to quarantine a phone number
 type QuarantineRequest struct {
 	// Empty - no additional data needed for quarantine
@@ -63,6 +82,30 @@ type QuarantineResponse struct {
 	Message         string    `json:"message"`
 }
 
+// BulkQuarantineRequest represents the request to quarantine several phone numbers at once
+type BulkQuarantineRequest struct {
+	PhoneNumbers []string `json:"phone_numbers" binding:"required,min=1,max=1000"`
+	Reason       string   `json:"reason" binding:"required"`
+	// DurationDays defaults to PHONE_QUARANTINE_TTL when omitted
+	DurationDays int `json:"duration_days,omitempty" binding:"omitempty,min=1,max=3650"`
+}
+
+// BulkQuarantineResult represents the outcome of quarantining one phone number in a bulk request
+type BulkQuarantineResult struct {
+	PhoneNumber string `json:"phone_number"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+}
+
+// BulkQuarantineResponse represents the response for bulk quarantine operations
+type BulkQuarantineResponse struct {
+	Reason          string                 `json:"reason"`
+	QuarantineUntil time.Time              `json:"quarantine_until"`
+	Quarantined     int                    `json:"quarantined"`
+	Failed          int                    `json:"failed"`
+	Results         []BulkQuarantineResult `json:"results"`
+}
+
 // BindRequest represents the request to bind a phone number to a CPF
 type BindRequest struct {
 	CPF     string `json:"cpf" binding:"required"`
@@ -78,12 +121,30 @@ type BindResponse struct {
 	Message     string `json:"message"`
 }
 
+// PhoneReassignRequest represents the request to move a phone number to another CPF
+type PhoneReassignRequest struct {
+	CPF    string `json:"cpf" binding:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PhoneReassignResponse represents the response for phone reassignment
+type PhoneReassignResponse struct {
+	Status      string `json:"status"`
+	PhoneNumber string `json:"phone_number"`
+	CPF         string `json:"cpf"`
+	// PreviousCPFs lists the CPFs whose active mappings were unbound
+	PreviousCPFs []string `json:"previous_cpfs"`
+	Message      string   `json:"message"`
+}
+
 // QuarantinedPhone represents a quarantined phone number for admin endpoints
 type QuarantinedPhone struct {
-	PhoneNumber     string    `json:"phone_number"`
-	CPF             string    `json:"cpf,omitempty"`
-	QuarantineUntil time.Time `json:"quarantine_until"`
-	Expired         bool      `json:"expired"`
+	PhoneNumber      string     `json:"phone_number"`
+	CPF              string     `json:"cpf,omitempty"`
+	QuarantineReason string     `json:"quarantine_reason,omitempty"`
+	QuarantinedAt    *time.Time `json:"quarantined_at,omitempty"`
+	QuarantineUntil  time.Time  `json:"quarantine_until"`
+	Expired          bool       `json:"expired"`
 }
 
 // QuarantinedListResponse represents the paginated response for quarantined phones
@@ -100,6 +161,57 @@ type QuarantineStats struct {
 	QuarantinesWithCPF     int `json:"quarantines_with_cpf"`
 	QuarantinesWithoutCPF  int `json:"quarantines_without_cpf"`
 	QuarantineHistoryTotal int `json:"quarantine_history_total"`
+	// ActiveByReason counts active quarantines per reason (QuarantineReasonUnspecified when none was given)
+	ActiveByReason map[string]int `json:"active_by_reason"`
+}
+
+// PhoneReconcileDiscrepancy describes a field whose cached value differs from MongoDB
+type PhoneReconcileDiscrepancy struct {
+	// Key is the Redis key holding the stale value
+	Key         string      `json:"key"`
+	Field       string      `json:"field"`
+	MongoValue  interface{} `json:"mongo_value"`
+	CachedValue interface{} `json:"cached_value"`
+}
+
+// PhoneReconcileResponse represents the result of reconciling a phone mapping between MongoDB and Redis
+type PhoneReconcileResponse struct {
+	PhoneNumber  string `json:"phone_number"`
+	FoundInMongo bool   `json:"found_in_mongo"`
+	// CachedKeys lists the Redis keys currently holding state for the phone
+	CachedKeys []string `json:"cached_keys"`
+	// PendingWrite is true when the write buffer holds a change not yet synced to MongoDB;
+	// it is reported but never compared or repaired
+	PendingWrite  bool                        `json:"pending_write"`
+	Discrepancies []PhoneReconcileDiscrepancy `json:"discrepancies"`
+	Consistent    bool                        `json:"consistent"`
+	Repaired      bool                        `json:"repaired"`
+	RepairedKeys  []string                    `json:"repaired_keys,omitempty"`
+}
+
+// Delivery receipt statuses reported by the messaging provider
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// PhoneDeliveryReceiptRequest represents a delivery receipt for a message sent to a phone
+type PhoneDeliveryReceiptRequest struct {
+	Status string `json:"status" binding:"required,oneof=delivered failed"`
+	Error  string `json:"error,omitempty" binding:"max=500"` // Provider error description, for failures
+}
+
+// PhoneDeliveryReceiptResponse represents the result of recording a delivery receipt
+type PhoneDeliveryReceiptResponse struct {
+	PhoneNumber string `json:"phone_number"`
+	CPF         string `json:"cpf,omitempty"`
+	Status      string `json:"status"`
+	// Failures is the number of delivery failures counted in the current window
+	Failures  int `json:"failures"`
+	Threshold int `json:"threshold"`
+	// Demoted is true when this receipt demoted the citizen's verified self-declared phone back
+	// to pending verification
+	Demoted bool `json:"demoted"`
 }
 
 // PaginationInfo represents pagination information
@@ -115,8 +227,12 @@ const (
 	MappingStatusActive      = "active"
 	MappingStatusBlocked     = "blocked"
 	MappingStatusQuarantined = "quarantined"
+	MappingStatusUnbound     = "unbound" // the phone was reassigned to another CPF
 )
 
+// QuarantineReasonUnspecified groups quarantines created without a reason in the stats
+const QuarantineReasonUnspecified = "unspecified"
+
 // Channel constants
 const (
 	ChannelWhatsApp = "whatsapp"
@@ -38,3 +38,17 @@ const (
 	VerificationCodeLength  = 6
 	MaxVerificationAttempts = 3
 )
+
+// VerificationExpireRequest filters the outstanding verification codes to expire. With no
+// filter, every outstanding phone and email verification is expired.
+type VerificationExpireRequest struct {
+	CPF         string `json:"cpf,omitempty" example:"12345678901"`
+	PhoneNumber string `json:"phone_number,omitempty" example:"5521987654321"`
+}
+
+// VerificationExpireResponse reports how many outstanding verification codes were expired
+type VerificationExpireResponse struct {
+	PhoneVerificationsExpired int64     `json:"phone_verifications_expired"`
+	EmailVerificationsExpired int64     `json:"email_verifications_expired"`
+	ExpiredAt                 time.Time `json:"expired_at"`
+}
@@ -7,6 +7,7 @@ type SelfDeclaredData struct {
 	CPF             string    `bson:"cpf" json:"cpf"`
 	Endereco        *Endereco `bson:"endereco,omitempty" json:"endereco"`
 	Email           *Email    `bson:"email,omitempty" json:"email"`
+	EmailPending    *Email    `bson:"email_pending,omitempty" json:"email_pending"`
 	Telefone        *Telefone `bson:"telefone,omitempty" json:"telefone"`
 	TelefonePending *Telefone `bson:"telefone_pending,omitempty" json:"telefone_pending"`
 	Raca            *string   `bson:"raca,omitempty" json:"raca"`
@@ -16,5 +17,24 @@ type SelfDeclaredData struct {
 	Escolaridade    *string   `bson:"escolaridade,omitempty" json:"escolaridade"`
 	Deficiencia     *string   `bson:"deficiencia,omitempty" json:"deficiencia"`
 	Version         int32     `bson:"version,omitempty" json:"version,omitempty"`
-	UpdatedAt       time.Time `bson:"updated_at" json:"updated_at"`
+	Timestamps      `bson:",inline"`
+}
+
+// SelfDeclaredFieldStatus tells clients how recent a self-declared field is, so the app can ask
+// the citizen to confirm data older than SELF_DECLARED_OUTDATED_THRESHOLD
+type SelfDeclaredFieldStatus struct {
+	IsStale     bool       `json:"is_stale"`
+	LastUpdated *time.Time `json:"last_updated"`
+}
+
+// NewSelfDeclaredFieldStatus computes the status of a self-declared field. Fields without a
+// timestamp (legacy data) are always reported as stale.
+func NewSelfDeclaredFieldStatus(lastUpdated *time.Time, threshold time.Duration, now time.Time) SelfDeclaredFieldStatus {
+	if lastUpdated == nil || lastUpdated.IsZero() {
+		return SelfDeclaredFieldStatus{IsStale: true}
+	}
+	return SelfDeclaredFieldStatus{
+		IsStale:     now.Sub(*lastUpdated) > threshold,
+		LastUpdated: lastUpdated,
+	}
 }
@@ -9,39 +9,83 @@ type SelfDeclaredAddressInput struct {
 	Municipio      string  `json:"municipio" binding:"required"`
 	Numero         string  `json:"numero" binding:"required"`
 	TipoLogradouro *string `json:"tipo_logradouro"`
+	Version        *int32  `json:"version,omitempty"`
 }
 
 type SelfDeclaredEmailInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredPhoneInput struct {
 	DDI string `json:"ddi" binding:"required"`
 	// DDD é obrigatório somente quando o DDI é 55 (Brasil)
-	DDD   string `json:"ddd" binding:"required_if=DDI 55"`
-	Valor string `json:"valor" binding:"required"`
+	DDD     string `json:"ddd" binding:"required_if=DDI 55"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredRacaInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredNomeExibicaoInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredGeneroInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredRendaFamiliarInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredEscolaridadeInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
 }
 
 type SelfDeclaredDeficienciaInput struct {
-	Valor string `json:"valor" binding:"required"`
+	Valor   string `json:"valor" binding:"required"`
+	Version *int32 `json:"version,omitempty"`
+}
+
+// SelfDeclaredPatchInput is a partial self-declared update. Only the fields present in the
+// body are updated; omitted fields are left untouched.
+type SelfDeclaredPatchInput struct {
+	Endereco *SelfDeclaredAddressInput `json:"endereco,omitempty"`
+	Email    *SelfDeclaredEmailInput   `json:"email,omitempty"`
+	Telefone *SelfDeclaredPhoneInput   `json:"telefone,omitempty"`
+	Raca     *SelfDeclaredRacaInput    `json:"raca,omitempty"`
+	// Version is the self-declared version the update is based on (alternative to If-Match)
+	Version *int32 `json:"version,omitempty"`
+}
+
+// IsEmpty reports whether no field was provided
+func (p SelfDeclaredPatchInput) IsEmpty() bool {
+	return p.Endereco == nil && p.Email == nil && p.Telefone == nil && p.Raca == nil
+}
+
+// Per-field outcomes of a partial self-declared update
+const (
+	SelfDeclaredPatchUpdated             = "updated"
+	SelfDeclaredPatchUnchanged           = "unchanged"
+	SelfDeclaredPatchVerificationPending = "verification_pending"
+	SelfDeclaredPatchError               = "error"
+)
+
+// SelfDeclaredPatchFieldResult is the outcome of updating a single field
+type SelfDeclaredPatchFieldResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SelfDeclaredPatchResponse maps each field present in the request to its outcome
+type SelfDeclaredPatchResponse struct {
+	Results map[string]SelfDeclaredPatchFieldResult `json:"results"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestSelfDeclaredPatchInput_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name  string
+		input SelfDeclaredPatchInput
+		want  bool
+	}{
+		{"no fields", SelfDeclaredPatchInput{}, true},
+		{"email only", SelfDeclaredPatchInput{Email: &SelfDeclaredEmailInput{Valor: "a@b.com"}}, false},
+		{"ethnicity only", SelfDeclaredPatchInput{Raca: &SelfDeclaredRacaInput{Valor: "parda"}}, false},
+		{"phone only", SelfDeclaredPatchInput{Telefone: &SelfDeclaredPhoneInput{DDI: "55", DDD: "21", Valor: "987654321"}}, false},
+		{"address only", SelfDeclaredPatchInput{Endereco: &SelfDeclaredAddressInput{Bairro: "Centro"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.input.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
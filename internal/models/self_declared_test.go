@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSelfDeclaredFieldStatus(t *testing.T) {
+	now := time.Now()
+	threshold := 180 * 24 * time.Hour
+	recent := now.Add(-time.Hour)
+	old := now.Add(-threshold - time.Hour)
+	zero := time.Time{}
+
+	status := NewSelfDeclaredFieldStatus(&recent, threshold, now)
+	assert.False(t, status.IsStale)
+	assert.Equal(t, &recent, status.LastUpdated)
+
+	status = NewSelfDeclaredFieldStatus(&old, threshold, now)
+	assert.True(t, status.IsStale)
+	assert.Equal(t, &old, status.LastUpdated)
+
+	status = NewSelfDeclaredFieldStatus(nil, threshold, now)
+	assert.True(t, status.IsStale, "legacy data without timestamp is stale")
+	assert.Nil(t, status.LastUpdated)
+
+	status = NewSelfDeclaredFieldStatus(&zero, threshold, now)
+	assert.True(t, status.IsStale)
+	assert.Nil(t, status.LastUpdated)
+}
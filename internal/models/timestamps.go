@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Timestamps records when a stored document was created and last written. Documents embed it
+// inline (`bson:",inline"`) and every write stamps it: updated_at on each write, created_at only
+// when the document is inserted. Writes made with MongoDB update operators follow the same
+// policy through utils.TimestampedUpdate.
+type Timestamps struct {
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Touch stamps a write made at now: UpdatedAt is always set and CreatedAt only when the document
+// doesn't have one yet
+func (t *Timestamps) Touch(now time.Time) {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+}
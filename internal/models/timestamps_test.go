@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTimestamps_Touch(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(48 * time.Hour)
+
+	var fresh Timestamps
+	fresh.Touch(now)
+	assert.Equal(t, now, fresh.CreatedAt, "first write sets created_at")
+	assert.Equal(t, now, fresh.UpdatedAt)
+
+	stored := Timestamps{CreatedAt: created, UpdatedAt: created}
+	stored.Touch(now)
+	assert.Equal(t, created, stored.CreatedAt, "later writes keep created_at")
+	assert.Equal(t, now, stored.UpdatedAt)
+}
+
+func TestTimestamps_InlineBSON(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	userConfig := UserConfig{CPF: "12345678901"}
+	userConfig.Touch(now)
+
+	data, err := bson.Marshal(userConfig)
+	assert.NoError(t, err)
+
+	var doc bson.M
+	assert.NoError(t, bson.Unmarshal(data, &doc))
+	assert.Contains(t, doc, "created_at")
+	assert.Contains(t, doc, "updated_at")
+	assert.NotContains(t, doc, "timestamps")
+}
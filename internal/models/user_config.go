@@ -1,25 +1,42 @@
 package models
 
-import "time"
-
 // UserConfig represents user configuration and preferences
 type UserConfig struct {
-	CPF            string          `bson:"cpf" json:"cpf"`
-	FirstLogin     bool            `bson:"first_login" json:"first_login"`
-	OptIn          bool            `bson:"opt_in" json:"opt_in"`
-	CategoryOptIns map[string]bool `bson:"category_opt_ins,omitempty" json:"category_opt_ins,omitempty"`
-	AvatarID       *string         `bson:"avatar_id,omitempty" json:"avatar_id,omitempty"`
-	Version        int32           `bson:"version,omitempty" json:"version,omitempty"`
-	UpdatedAt      time.Time       `bson:"updated_at" json:"updated_at"`
+	CPF              string          `bson:"cpf" json:"cpf"`
+	FirstLogin       bool            `bson:"first_login" json:"first_login"`
+	OptIn            bool            `bson:"opt_in" json:"opt_in"`
+	CategoryOptIns   map[string]bool `bson:"category_opt_ins,omitempty" json:"category_opt_ins,omitempty"`
+	AvatarID         *string         `bson:"avatar_id,omitempty" json:"avatar_id,omitempty"`
+	OptOutReason     *string         `bson:"opt_out_reason,omitempty" json:"opt_out_reason,omitempty"`       // Code from the opt-out reasons list, cleared on opt-in
+	OptOutNote       *string         `bson:"opt_out_note,omitempty" json:"opt_out_note,omitempty"`           // Free-text note left with the opt-out
+	PreferredChannel *string         `bson:"preferred_channel,omitempty" json:"preferred_channel,omitempty"` // Code of the channel the citizen prefers to be notified through
+	Version          int32           `bson:"version,omitempty" json:"version,omitempty"`
+	Timestamps       `bson:",inline"`
 }
 
 // UserConfigResponse represents the response format for user config endpoints
 type UserConfigResponse struct {
 	FirstLogin bool `json:"firstlogin"`
+	// Avatar is the default avatar assigned when completing the first login, if any
+	Avatar *AvatarResponse `json:"avatar,omitempty"`
 }
 
 // UserConfigOptInResponse represents the response format for opt-in endpoints
 type UserConfigOptInResponse struct {
 	OptIn          *bool           `json:"opt_in" binding:"required"`
 	CategoryOptIns map[string]bool `json:"category_opt_ins,omitempty"`
+	// Reason and Note are only accepted when opting out; Reason must be a code from /config/opt-out-reasons
+	Reason string `json:"reason,omitempty"`
+	Note   string `json:"note,omitempty" binding:"max=500"`
+}
+
+// PreferredChannelRequest is the body of the preferred channel update. PreferredChannel must be the
+// code of one of the available channels (/config/channels).
+type PreferredChannelRequest struct {
+	PreferredChannel string `json:"preferred_channel" binding:"required"`
+}
+
+// PreferredChannelResponse is the preferred notification channel of a citizen, null when not chosen
+type PreferredChannelResponse struct {
+	PreferredChannel *string `json:"preferred_channel"`
 }
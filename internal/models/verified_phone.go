@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// TelefoneVerificado is a phone the citizen confirmed with a verification code. ID is the phone
+// in storage format (the phone_number of its phone mapping), so a CPF lists each verified phone
+// once, the same way the phone mapping collection lists each CPF of a phone once.
+type TelefoneVerificado struct {
+	ID         string     `json:"id" bson:"id"`
+	DDI        *string    `json:"ddi" bson:"ddi,omitempty"`
+	DDD        *string    `json:"ddd" bson:"ddd,omitempty"`
+	Valor      *string    `json:"valor" bson:"valor,omitempty"`
+	Principal  bool       `json:"principal" bson:"principal"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty" bson:"verified_at,omitempty"`
+}
+
+// Origem and Sistema of the phones the citizen declared and verified through the API
+const (
+	TelefoneOrigemSelfDeclared = "self-declared"
+	TelefoneSistemaRMI         = "rmi"
+)
+
+// FindVerified returns the verified phone with the given ID, or nil
+func (t *Telefone) FindVerified(id string) *TelefoneVerificado {
+	for i := range t.Verificados {
+		if t.Verificados[i].ID == id {
+			return &t.Verificados[i]
+		}
+	}
+	return nil
+}
+
+// PrimaryVerified returns the verified phone marked as primary, or nil
+func (t *Telefone) PrimaryVerified() *TelefoneVerificado {
+	for i := range t.Verificados {
+		if t.Verificados[i].Principal {
+			return &t.Verificados[i]
+		}
+	}
+	return nil
+}
+
+// SecondaryVerified returns the verified phones other than the primary one
+func (t *Telefone) SecondaryVerified() []TelefoneVerificado {
+	var secondary []TelefoneVerificado
+	for _, phone := range t.Verificados {
+		if !phone.Principal {
+			secondary = append(secondary, phone)
+		}
+	}
+	return secondary
+}
+
+// AddVerified adds phone to the verified phones, replacing a previous verification of the same
+// ID, and makes it the primary phone
+func (t *Telefone) AddVerified(phone TelefoneVerificado, now time.Time) {
+	if existing := t.FindVerified(phone.ID); existing != nil {
+		*existing = phone
+	} else {
+		t.Verificados = append(t.Verificados, phone)
+	}
+	t.SetPrimary(phone.ID, now)
+}
+
+// SetPrimary marks the verified phone with the given ID as primary and copies it to Principal,
+// which flags the telefone as verified. It reports whether the ID is a verified phone.
+func (t *Telefone) SetPrimary(id string, now time.Time) bool {
+	phone := t.FindVerified(id)
+	if phone == nil {
+		return false
+	}
+	for i := range t.Verificados {
+		t.Verificados[i].Principal = t.Verificados[i].ID == id
+	}
+
+	verified := true
+	origem := TelefoneOrigemSelfDeclared
+	sistema := TelefoneSistemaRMI
+	t.Indicador = &verified
+	t.Principal = &TelefonePrincipal{
+		DDI:       phone.DDI,
+		DDD:       phone.DDD,
+		Valor:     phone.Valor,
+		Origem:    &origem,
+		Sistema:   &sistema,
+		UpdatedAt: &now,
+	}
+	return true
+}
+
+// RemoveVerified drops the verified phone with the given ID and reports whether it was there.
+// Principal is left as is; when the primary phone is removed, the caller decides what replaces it.
+func (t *Telefone) RemoveVerified(id string) bool {
+	for i := range t.Verificados {
+		if t.Verificados[i].ID == id {
+			t.Verificados = append(t.Verificados[:i:i], t.Verificados[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func verifiedPhone(id, valor string) TelefoneVerificado {
+	ddi, ddd := "55", "21"
+	return TelefoneVerificado{ID: id, DDI: &ddi, DDD: &ddd, Valor: &valor}
+}
+
+func TestTelefone_AddVerified(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var telefone Telefone
+
+	telefone.AddVerified(verifiedPhone("5521987654321", "987654321"), now)
+	telefone.AddVerified(verifiedPhone("5521912345678", "912345678"), now)
+
+	assert.Len(t, telefone.Verificados, 2)
+	assert.Equal(t, "5521912345678", telefone.PrimaryVerified().ID, "the last verified phone becomes primary")
+	assert.Equal(t, "912345678", *telefone.Principal.Valor)
+	assert.Equal(t, TelefoneOrigemSelfDeclared, *telefone.Principal.Origem)
+	assert.True(t, *telefone.Indicador)
+
+	// Verifying a phone again replaces it instead of listing it twice
+	telefone.AddVerified(verifiedPhone("5521987654321", "987654321"), now)
+	assert.Len(t, telefone.Verificados, 2)
+	assert.Equal(t, "5521987654321", telefone.PrimaryVerified().ID)
+	assert.Equal(t, []TelefoneVerificado{telefone.Verificados[1]}, telefone.SecondaryVerified())
+}
+
+func TestTelefone_SetPrimary(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var telefone Telefone
+	telefone.AddVerified(verifiedPhone("5521987654321", "987654321"), now)
+	telefone.AddVerified(verifiedPhone("5521912345678", "912345678"), now)
+
+	later := now.Add(time.Hour)
+	assert.True(t, telefone.SetPrimary("5521987654321", later))
+	assert.Equal(t, "5521987654321", telefone.PrimaryVerified().ID)
+	assert.Equal(t, "987654321", *telefone.Principal.Valor)
+	assert.Equal(t, later, *telefone.Principal.UpdatedAt)
+	assert.Len(t, telefone.SecondaryVerified(), 1)
+
+	assert.False(t, telefone.SetPrimary("5521999999999", later), "unverified phones can't be primary")
+	assert.Equal(t, "5521987654321", telefone.PrimaryVerified().ID)
+}
+
+func TestTelefone_RemoveVerified(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var telefone Telefone
+	telefone.AddVerified(verifiedPhone("5521987654321", "987654321"), now)
+	telefone.AddVerified(verifiedPhone("5521912345678", "912345678"), now)
+	verificados := telefone.Verificados
+
+	assert.True(t, telefone.RemoveVerified("5521987654321"))
+	assert.False(t, telefone.RemoveVerified("5521987654321"))
+	assert.Len(t, telefone.Verificados, 1)
+	assert.Equal(t, "5521987654321", verificados[0].ID, "the previous list is not modified")
+}
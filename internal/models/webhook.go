@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// AddressChangedWebhookEvent is the event name sent by the address change webhook
+const AddressChangedWebhookEvent = "address.changed"
+
+// AddressChangeWebhookPayload is the body posted to the address change webhook. Addresses are
+// sent as hashes so downstream systems can detect changes without receiving the address itself.
+// PreferredChannel is the channel the citizen prefers to be notified through, when chosen.
+type AddressChangeWebhookPayload struct {
+	Event            string    `json:"event"`
+	CPF              string    `json:"cpf"`
+	OldAddressHash   string    `json:"old_address_hash,omitempty"`
+	NewAddressHash   string    `json:"new_address_hash"`
+	PreferredChannel string    `json:"preferred_channel,omitempty"`
+	ChangedAt        time.Time `json:"changed_at"`
+}
+
+// PhoneVerifiedWebhookEvent is the event name sent by the phone verified webhook
+const PhoneVerifiedWebhookEvent = "phone.verified"
+
+// PhoneVerifiedWebhookPayload is the body posted to the phone verified webhook once a phone is
+// verified and bound to a CPF. Phone is in storage format (DDI+DDD+number, digits only) and
+// Channel is the channel the verification code was delivered through. PreferredChannel is the
+// channel the citizen prefers to be notified through, when chosen.
+type PhoneVerifiedWebhookPayload struct {
+	Event            string    `json:"event"`
+	CPF              string    `json:"cpf"`
+	Phone            string    `json:"phone"`
+	Channel          string    `json:"channel"`
+	PreferredChannel string    `json:"preferred_channel,omitempty"`
+	VerifiedAt       time.Time `json:"verified_at"`
+}
+
+// EmailVerificationRequestedWebhookEvent is the event name sent by the email verification webhook
+const EmailVerificationRequestedWebhookEvent = "email.verification_requested"
+
+// EmailVerificationWebhookPayload is the body posted to the email verification webhook. The
+// receiving system is responsible for delivering the token to the citizen's mailbox. Link is the
+// one-tap verification link, present when EMAIL_VERIFICATION_LINK_URL is set. PreferredChannel is
+// the channel the citizen prefers to be notified through, when chosen, for any notice sent besides
+// the email itself.
+type EmailVerificationWebhookPayload struct {
+	Event            string    `json:"event"`
+	CPF              string    `json:"cpf"`
+	Email            string    `json:"email"`
+	Token            string    `json:"token"`
+	Link             string    `json:"link,omitempty"`
+	PreferredChannel string    `json:"preferred_channel,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
@@ -1,8 +1,10 @@
 package observability
 
 import (
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
 )
 
 var (
@@ -15,6 +17,86 @@ var (
 		[]string{"path", "method", "status"},
 	)
 
+	// HTTPRequestDuration tracks request latency per route template, for SLO dashboards
+	HTTPRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests in seconds by route template, method and status",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"route", "method", "status"},
+	)
+
+	// HTTPRequestBytes tracks request body sizes per route template
+	HTTPRequestBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_bytes",
+			Help:    "Size of HTTP request bodies in bytes by route template",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8), // 64B to 1MiB
+		},
+		[]string{"route"},
+	)
+
+	// HTTPResponseBytes tracks response body sizes per route template
+	HTTPResponseBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_bytes",
+			Help:    "Size of HTTP response bodies in bytes by route template",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8), // 64B to 1MiB
+		},
+		[]string{"route"},
+	)
+
+	// HTTPResponseCompressionBytes tracks compressed response bodies before and after compression
+	// (stage: uncompressed/compressed), to weigh bandwidth savings against compression time
+	HTTPResponseCompressionBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_response_compression_bytes_total",
+			Help: "Total bytes of compressed HTTP response bodies before and after compression by encoding",
+		},
+		[]string{"encoding", "stage"},
+	)
+
+	// HTTPResponseCompressionDuration tracks the time spent compressing each response body
+	HTTPResponseCompressionDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_compression_duration_seconds",
+			Help:    "Time spent compressing HTTP response bodies in seconds by encoding",
+			Buckets: prometheus.ExponentialBuckets(0.00005, 4, 8), // 50µs to ~0.8s
+		},
+		[]string{"encoding"},
+	)
+
+	// CollectionDataAgeSeconds tracks how old the documents of each collection are, estimated
+	// from a sample of their updated_at/created_at (quantile: 0.5/0.9/0.99/1)
+	CollectionDataAgeSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "collection_data_age_seconds",
+			Help: "Age in seconds of the documents of a collection by quantile, from the data freshness report",
+		},
+		[]string{"collection", "quantile"},
+	)
+
+	// OutboundHTTPRequestsInFlight tracks outbound requests waiting for a response, per client
+	// (mcp, webhook, whatsapp)
+	OutboundHTTPRequestsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "outbound_http_requests_in_flight",
+			Help: "Outbound HTTP requests waiting for a response",
+		},
+		[]string{"client"},
+	)
+
+	// OutboundHTTPConnections tracks the connections outbound requests got, and whether they were
+	// reused from the idle pool (reused=true) or newly dialed
+	OutboundHTTPConnections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbound_http_connections_total",
+			Help: "Connections used by outbound HTTP requests, by whether they were reused",
+		},
+		[]string{"client", "reused"},
+	)
+
 	// CacheHits tracks cache hits/misses
 	CacheHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -86,6 +168,15 @@ var (
 		[]string{"queue"},
 	)
 
+	// Sync jobs currently being processed by the sync workers, per queue
+	RMISyncJobsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rmi_sync_jobs_in_flight",
+			Help: "Number of sync jobs currently being processed",
+		},
+		[]string{"queue"},
+	)
+
 	RMISyncOperationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "rmi_sync_operations_total",
@@ -102,6 +193,210 @@ var (
 		[]string{"queue"},
 	)
 
+	RMISyncFlushedJobsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_sync_flushed_jobs_total",
+			Help: "Total number of sync jobs drained by a synchronous flush (shutdown or admin request)",
+		},
+		[]string{"queue", "trigger"},
+	)
+
+	// Outbound webhook delivery attempts (status: success/failure)
+	RMIWebhookDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_webhook_deliveries_total",
+			Help: "Total number of outbound webhook delivery attempts",
+		},
+		[]string{"webhook", "status"},
+	)
+
+	RMIWebhookDeliveryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "rmi_webhook_delivery_duration_seconds",
+			Help: "Duration of outbound webhook delivery attempts in seconds",
+		},
+		[]string{"webhook"},
+	)
+
+	// CF data refreshes triggered on read (reason: max_age)
+	RMICFRefreshTriggersTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_cf_refresh_triggers_total",
+			Help: "Total number of CF lookup refreshes triggered when serving cached CF data",
+		},
+		[]string{"reason"},
+	)
+
+	// CF data served past CF_LOOKUP_MAX_AGE while a refresh is pending
+	RMICFStaleReadsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rmi_cf_stale_reads_total",
+			Help: "Total number of CF data reads served stale because the data was older than CF_LOOKUP_MAX_AGE",
+		},
+	)
+
+	// Phone numbers released by the quarantine expiry sweeper
+	PhoneQuarantineReleasedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "phone_quarantine_released_total",
+			Help: "Total number of phone numbers automatically released after their quarantine expired",
+		},
+	)
+
+	// Synchronous wallet CF lookups that timed out and were queued in background
+	RMICFSyncLookupTimeoutsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rmi_cf_sync_lookup_timeouts_total",
+			Help: "Total number of synchronous wallet CF lookups that hit CF_LOOKUP_SYNC_TIMEOUT and fell back to a background job",
+		},
+	)
+
+	// CF lookups answered by the MCP server with "no equipment found" (mode: async/sync/cached)
+	RMICFLookupNoEquipmentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_cf_lookup_no_equipment_total",
+			Help: "Total number of CF lookups for which no health equipment was found for the address",
+		},
+		[]string{"mode"},
+	)
+
+	// Wallet sections returned as null because building them failed (section: documentos/saude/...)
+	WalletSectionFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_wallet_section_failures_total",
+			Help: "Total number of wallet sections that failed to build and were returned as null with a warning",
+		},
+		[]string{"section"},
+	)
+
+	// Synchronous CF lookups that joined an identical lookup already in flight instead of calling MCP
+	CFLookupCoalescedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "cf_lookup_coalesced_total",
+			Help: "Total number of synchronous CF lookups that shared the MCP call of a concurrent lookup for the same CPF and address",
+		},
+	)
+
+	// State of the circuit breaker around synchronous MCP lookups (0 closed, 1 half-open, 2 open)
+	RMICFCircuitState = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "cf_circuit_state",
+			Help: "State of the CF MCP circuit breaker: 0 closed, 1 half-open, 2 open",
+		},
+	)
+
+	// Stricter validation rule violations (mode: shadow/enforce); shadow violations were accepted
+	RMIValidationRuleViolationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_validation_rule_violations_total",
+			Help: "Total number of inputs that violated a configurable validation rule",
+		},
+		[]string{"rule", "mode"},
+	)
+
+	// GET responses served from a cached copy because MongoDB was unavailable (resource: citizen/wallet)
+	RMIServedStaleTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "served_stale_total",
+			Help: "Total number of responses served from stale cached data while MongoDB was unavailable",
+		},
+		[]string{"resource"},
+	)
+
+	// Citizen event streams (SSE) currently open on this instance
+	RMIEventStreamsActive = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rmi_event_streams_active",
+			Help: "Number of citizen event streams (SSE) currently open",
+		},
+	)
+
+	// Event stream connections rejected by the concurrency caps (limit: instance/cpf)
+	RMIEventStreamsRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_event_streams_rejected_total",
+			Help: "Total number of citizen event stream connections rejected by the concurrency caps",
+		},
+		[]string{"limit"},
+	)
+
+	// Phone verification codes issued for self-declared phones
+	PhoneVerificationRequestedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "phone_verification_requested_total",
+			Help: "Total number of phone verification codes requested",
+		},
+	)
+
+	// Phone verification validations that failed (reason: invalid_phone/invalid_or_expired_code/locked_out)
+	PhoneVerificationFailedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "phone_verification_failed_total",
+			Help: "Total number of failed phone verification validations",
+		},
+		[]string{"reason"},
+	)
+
+	// Delivery receipts reported for phones (status: delivered/failed)
+	PhoneDeliveryReceiptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "phone_delivery_receipts_total",
+			Help: "Total number of message delivery receipts recorded for phones",
+		},
+		[]string{"status"},
+	)
+
+	// Verified self-declared phones sent back to pending verification after repeated delivery failures
+	PhoneDemotionsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "phone_demotions_total",
+			Help: "Total number of verified self-declared phones demoted to pending verification",
+		},
+	)
+
+	// Stale write buffer entries checked against MongoDB by the reconciler (result: in_sync/requeued)
+	WriteBufferReconciledTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "write_buffer_reconciled_total",
+			Help: "Total number of stale write buffer entries reconciled against MongoDB",
+		},
+		[]string{"type", "result"},
+	)
+
+	// Audit log batches written by the audit worker (status: success/error)
+	RMIAuditFlushesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rmi_audit_flushes_total",
+			Help: "Total number of audit log batches flushed to MongoDB",
+		},
+		[]string{"status"},
+	)
+
+	RMIAuditBatchSize = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rmi_audit_batch_size",
+			Help:    "Number of audit logs written per flush",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+		},
+	)
+
+	// Audit logs lost by the audit worker (reason: buffer_full with AUDIT_OVERFLOW_POLICY=drop,
+	// spill_corrupt/spill_requeue_failed for spilled logs that couldn't be decoded or put back)
+	RMIAuditEventsDroppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audit_events_dropped_total",
+			Help: "Total number of audit events dropped by the audit worker",
+		},
+		[]string{"reason"},
+	)
+
+	RMIAuditBufferUtilization = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "audit_buffer_utilization",
+			Help: "Fraction of the audit worker buffer in use (0 to 1)",
+		},
+	)
+
 	RMICacheHitRatio = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "rmi_cache_hit_ratio",
@@ -116,12 +411,71 @@ var (
 			Help: "Whether degraded mode is currently active",
 		},
 	)
+
+	// MongoDBReads counts MongoDB reads by the read preference they were routed with
+	// ("default" for the client's nearest), so read-after-write pinning to the primary is visible
+	MongoDBReads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mongodb_reads_total",
+			Help: "Total number of MongoDB reads by collection and read preference",
+		},
+		[]string{"collection", "read_preference"},
+	)
+
+	// Connection pool metrics (updated by the config pool monitors)
+	MongoDBSessionsInProgress = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mongodb_sessions_in_progress",
+			Help: "Number of MongoDB sessions currently in progress",
+		},
+	)
+
+	RedisPoolTotalConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_pool_total_conns",
+			Help: "Total number of connections in the Redis pool",
+		},
+		[]string{"redis_type"},
+	)
+
+	RedisPoolIdleConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_pool_idle_conns",
+			Help: "Number of idle connections in the Redis pool",
+		},
+		[]string{"redis_type"},
+	)
+
+	RedisPoolStaleConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "redis_pool_stale_conns",
+			Help: "Number of stale connections removed from the Redis pool",
+		},
+		[]string{"redis_type"},
+	)
 )
 
 // InitMetrics initializes the metrics system
 func InitMetrics() {
-	// Metrics are automatically initialized by promauto
-	// This function can be used for any additional setup
+	// Metrics are automatically initialized by promauto.
+	// Wire the connection pool monitors to the pool gauges.
+	config.MongoPoolStatsReporter = RecordMongoPoolStats
+	config.RedisPoolStatsReporter = RecordRedisPoolStats
+}
+
+// RecordMongoPoolStats updates the MongoDB connection pool gauges
+func RecordMongoPoolStats(sessionsInProgress int) {
+	MongoDBSessionsInProgress.Set(float64(sessionsInProgress))
+}
+
+// RecordRedisPoolStats updates the Redis connection pool gauges for the given redis type (single/cluster)
+func RecordRedisPoolStats(redisType string, stats *redis.PoolStats) {
+	if stats == nil {
+		return
+	}
+	RedisPoolTotalConns.WithLabelValues(redisType).Set(float64(stats.TotalConns))
+	RedisPoolIdleConns.WithLabelValues(redisType).Set(float64(stats.IdleConns))
+	RedisPoolStaleConns.WithLabelValues(redisType).Set(float64(stats.StaleConns))
 }
 
 // ShutdownMetrics shuts down the metrics system
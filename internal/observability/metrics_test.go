@@ -3,6 +3,9 @@ package observability
 import (
 	"testing"
 
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,6 +34,34 @@ func TestMetricsExist(t *testing.T) {
 	assert.NotNil(t, RMISyncFailuresTotal)
 	assert.NotNil(t, RMICacheHitRatio)
 	assert.NotNil(t, RMIDegradedModeActive)
+	assert.NotNil(t, MongoDBSessionsInProgress)
+	assert.NotNil(t, RedisPoolTotalConns)
+	assert.NotNil(t, RedisPoolIdleConns)
+	assert.NotNil(t, RedisPoolStaleConns)
+}
+
+func TestInitMetrics_WiresPoolReporters(t *testing.T) {
+	InitMetrics()
+
+	assert.NotNil(t, config.MongoPoolStatsReporter)
+	assert.NotNil(t, config.RedisPoolStatsReporter)
+}
+
+func TestRecordMongoPoolStats(t *testing.T) {
+	RecordMongoPoolStats(42)
+
+	assert.Equal(t, float64(42), testutil.ToFloat64(MongoDBSessionsInProgress))
+}
+
+func TestRecordRedisPoolStats(t *testing.T) {
+	RecordRedisPoolStats("cluster", &redis.PoolStats{TotalConns: 10, IdleConns: 4, StaleConns: 1})
+
+	assert.Equal(t, float64(10), testutil.ToFloat64(RedisPoolTotalConns.WithLabelValues("cluster")))
+	assert.Equal(t, float64(4), testutil.ToFloat64(RedisPoolIdleConns.WithLabelValues("cluster")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(RedisPoolStaleConns.WithLabelValues("cluster")))
+
+	// Should not panic on nil stats
+	RecordRedisPoolStats("single", nil)
 }
 
 func TestRequestDuration(t *testing.T) {
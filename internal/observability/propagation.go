@@ -0,0 +1,63 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceContextJobKey is the key under which the trace context is stored in async job payloads
+const TraceContextJobKey = "trace_context"
+
+// InjectTraceContext serializes the trace context of ctx (W3C traceparent/tracestate and
+// baggage). It returns nil when ctx carries no trace.
+func InjectTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// ExtractTraceContext returns ctx continuing the trace serialized by InjectTraceContext
+func ExtractTraceContext(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// InjectTraceContextIntoJob stores the trace context of ctx in a job payload so the worker
+// processing it can continue the same trace
+func InjectTraceContextIntoJob(ctx context.Context, data map[string]interface{}) {
+	if carrier := InjectTraceContext(ctx); carrier != nil {
+		data[TraceContextJobKey] = carrier
+	}
+}
+
+// ExtractTraceContextFromJob returns ctx continuing the trace stored in a job payload by
+// InjectTraceContextIntoJob. Payloads decoded from JSON hold the carrier as a generic map.
+func ExtractTraceContextFromJob(ctx context.Context, data map[string]interface{}) context.Context {
+	switch raw := data[TraceContextJobKey].(type) {
+	case map[string]string:
+		return ExtractTraceContext(ctx, raw)
+	case map[string]interface{}:
+		carrier := make(map[string]string, len(raw))
+		for key, value := range raw {
+			if s, ok := value.(string); ok {
+				carrier[key] = s
+			}
+		}
+		return ExtractTraceContext(ctx, carrier)
+	default:
+		return ctx
+	}
+}
+
+// InjectTraceHeaders adds the W3C traceparent headers of ctx to an outgoing HTTP request
+func InjectTraceHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
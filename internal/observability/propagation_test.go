@@ -0,0 +1,65 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// withTraceContextPropagator installs the propagator set up by InitTracer for the test
+func withTraceContextPropagator(t *testing.T) {
+	t.Helper()
+	original := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() { otel.SetTextMapPropagator(original) })
+}
+
+func TestTraceContextJobRoundTrip(t *testing.T) {
+	withTraceContextPropagator(t)
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "queue")
+	defer span.End()
+
+	data := map[string]interface{}{"cpf": "12345678909"}
+	InjectTraceContextIntoJob(ctx, data)
+	require.Contains(t, data, TraceContextJobKey)
+
+	// Jobs go through Redis as JSON
+	encoded, err := json.Marshal(data)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	extracted := trace.SpanContextFromContext(ExtractTraceContextFromJob(context.Background(), decoded))
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
+
+func TestInjectTraceContextIntoJob_NoTrace(t *testing.T) {
+	withTraceContextPropagator(t)
+
+	data := map[string]interface{}{}
+	InjectTraceContextIntoJob(context.Background(), data)
+	assert.NotContains(t, data, TraceContextJobKey)
+
+	ctx := ExtractTraceContextFromJob(context.Background(), data)
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestInjectTraceHeaders(t *testing.T) {
+	withTraceContextPropagator(t)
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	header := http.Header{}
+	InjectTraceHeaders(ctx, header)
+	assert.Contains(t, header.Get("traceparent"), span.SpanContext().TraceID().String())
+}
@@ -153,6 +153,36 @@ func (c *Client) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	return cmd
 }
 
+// Incr wraps Redis Incr with comprehensive tracing
+func (c *Client) Incr(ctx context.Context, key string) *redis.IntCmd {
+	start := time.Now()
+	ctx, span := otel.Tracer("redis").Start(ctx, "redis.incr",
+		trace.WithAttributes(
+			attribute.String("redis.key", key),
+			attribute.String("redis.operation", "incr"),
+			attribute.String("redis.client", "app-rmi"),
+		),
+	)
+	defer func() {
+		duration := time.Since(start)
+		span.SetAttributes(
+			attribute.Int64("redis.duration_ms", duration.Milliseconds()),
+			attribute.String("redis.duration", duration.String()),
+		)
+		span.End()
+	}()
+
+	cmd := c.cmdable.Incr(ctx, key)
+	if err := cmd.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("redis.error", err.Error()))
+	} else {
+		span.SetStatus(codes.Ok, "success")
+	}
+	return cmd
+}
+
 // Ping wraps Redis Ping with comprehensive tracing
 func (c *Client) Ping(ctx context.Context) *redis.StatusCmd {
 	start := time.Now()
@@ -273,6 +303,36 @@ func (c *Client) Keys(ctx context.Context, pattern string) *redis.StringSliceCmd
 	return cmd
 }
 
+// Scan wraps Redis Scan with comprehensive tracing
+func (c *Client) Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd {
+	start := time.Now()
+	ctx, span := otel.Tracer("redis").Start(ctx, "redis.scan",
+		trace.WithAttributes(
+			attribute.String("redis.pattern", match),
+			attribute.String("redis.operation", "scan"),
+			attribute.String("redis.client", "app-rmi"),
+		),
+	)
+	defer func() {
+		duration := time.Since(start)
+		span.SetAttributes(
+			attribute.Int64("redis.duration_ms", duration.Milliseconds()),
+			attribute.String("redis.duration", duration.String()),
+		)
+		span.End()
+	}()
+
+	cmd := c.cmdable.Scan(ctx, cursor, match, count)
+	if err := cmd.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("redis.error", err.Error()))
+	} else {
+		span.SetStatus(codes.Ok, "success")
+	}
+	return cmd
+}
+
 // FlushDB wraps Redis FlushDB with comprehensive tracing
 func (c *Client) FlushDB(ctx context.Context) *redis.StatusCmd {
 	start := time.Now()
@@ -509,3 +569,93 @@ func (c *Client) RPop(ctx context.Context, key string) *redis.StringCmd {
 	}
 	return cmd
 }
+
+// LRange wraps Redis LRange with comprehensive tracing
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	begin := time.Now()
+	ctx, span := otel.Tracer("redis").Start(ctx, "redis.lrange",
+		trace.WithAttributes(
+			attribute.String("redis.key", key),
+			attribute.String("redis.operation", "lrange"),
+			attribute.String("redis.client", "app-rmi"),
+			attribute.String("redis.type", "list"),
+		),
+	)
+	defer func() {
+		duration := time.Since(begin)
+		span.SetAttributes(
+			attribute.Int64("redis.duration_ms", duration.Milliseconds()),
+			attribute.String("redis.duration", duration.String()),
+		)
+		span.End()
+	}()
+
+	cmd := c.cmdable.LRange(ctx, key, start, stop)
+	if err := cmd.Err(); err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("redis.error", err.Error()))
+	} else {
+		span.SetStatus(codes.Ok, "success")
+	}
+	return cmd
+}
+
+// Publish wraps Redis Publish with comprehensive tracing
+func (c *Client) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	start := time.Now()
+	ctx, span := otel.Tracer("redis").Start(ctx, "redis.publish",
+		trace.WithAttributes(
+			attribute.String("redis.channel", channel),
+			attribute.String("redis.operation", "publish"),
+			attribute.String("redis.client", "app-rmi"),
+			attribute.String("redis.type", "pubsub"),
+		),
+	)
+	defer func() {
+		duration := time.Since(start)
+		span.SetAttributes(
+			attribute.Int64("redis.duration_ms", duration.Milliseconds()),
+			attribute.String("redis.duration", duration.String()),
+		)
+		span.End()
+	}()
+
+	cmd := c.cmdable.Publish(ctx, channel, message)
+	if err := cmd.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("redis.error", err.Error()))
+	} else {
+		span.SetStatus(codes.Ok, "success")
+	}
+	return cmd
+}
+
+// Subscribe subscribes to the given channels with proper interface handling.
+// Returns nil if the underlying client doesn't support pub/sub (should not happen).
+func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	if singleClient, ok := c.cmdable.(*redis.Client); ok {
+		return singleClient.Subscribe(ctx, channels...)
+	}
+
+	if clusterClient, ok := c.cmdable.(*redis.ClusterClient); ok {
+		return clusterClient.Subscribe(ctx, channels...)
+	}
+
+	return nil
+}
+
+// PSubscribe subscribes to the channels matching the given patterns with proper interface handling.
+// Returns nil if the underlying client doesn't support pub/sub (should not happen).
+func (c *Client) PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub {
+	if singleClient, ok := c.cmdable.(*redis.Client); ok {
+		return singleClient.PSubscribe(ctx, patterns...)
+	}
+
+	if clusterClient, ok := c.cmdable.(*redis.ClusterClient); ok {
+		return clusterClient.PSubscribe(ctx, patterns...)
+	}
+
+	return nil
+}
@@ -318,6 +318,21 @@ func TestClient_Del(t *testing.T) {
 	})
 }
 
+func TestClient_Incr(t *testing.T) {
+	client, cleanup := setupRedisForTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	client.Del(ctx, "test:incr:key")
+	defer client.Del(ctx, "test:incr:key")
+
+	for want := int64(1); want <= 2; want++ {
+		cmd := client.Incr(ctx, "test:incr:key")
+		require.NoError(t, cmd.Err(), "Incr should not error")
+		assert.Equal(t, want, cmd.Val(), "Incr should return the incremented value")
+	}
+}
+
 func TestClient_Ping(t *testing.T) {
 	client, cleanup := setupRedisForTest(t)
 	defer cleanup()
@@ -1025,3 +1040,87 @@ func TestClient_Eval_ErrorPath(t *testing.T) {
 		assert.Error(t, err, "Eval with cancelled context must return an error")
 	})
 }
+
+// TestClient_LRange verifies the traced LRange wrapper behaviour
+func TestClient_LRange(t *testing.T) {
+	client, cleanup := setupRedisForTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	key := "test:lrange"
+
+	require.NoError(t, client.LPush(ctx, key, "a", "b", "c").Err())
+
+	values, err := client.LRange(ctx, key, 0, -1).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a"}, values)
+
+	values, err = client.LRange(ctx, "test:lrange_missing", 0, -1).Result()
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+// TestClient_PublishSubscribe verifies pub/sub through the wrapper
+func TestClient_PublishSubscribe(t *testing.T) {
+	client, cleanup := setupRedisForTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	channel := "test:pubsub"
+
+	pubsub := client.Subscribe(ctx, channel)
+	require.NotNil(t, pubsub)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed before publishing
+	_, err := pubsub.Receive(ctx)
+	require.NoError(t, err)
+
+	receivers, err := client.Publish(ctx, channel, "hello").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), receivers)
+
+	select {
+	case msg := <-pubsub.Channel():
+		assert.Equal(t, channel, msg.Channel)
+		assert.Equal(t, "hello", msg.Payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestClient_SubscribeInvalidType(t *testing.T) {
+	client := &Client{cmdable: &mockCmdable{}}
+	assert.Nil(t, client.Subscribe(context.Background(), "test:channel"))
+}
+
+// TestClient_PSubscribe verifies pattern subscriptions through the wrapper
+func TestClient_PSubscribe(t *testing.T) {
+	client, cleanup := setupRedisForTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	pubsub := client.PSubscribe(ctx, "test:psub:*")
+	require.NotNil(t, pubsub)
+	defer pubsub.Close()
+
+	_, err := pubsub.Receive(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Publish(ctx, "test:psub:a", "hello").Err())
+
+	select {
+	case msg := <-pubsub.Channel():
+		assert.Equal(t, "test:psub:a", msg.Channel)
+		assert.Equal(t, "test:psub:*", msg.Pattern)
+		assert.Equal(t, "hello", msg.Payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestClient_PSubscribeInvalidType(t *testing.T) {
+	client := &Client{cmdable: &mockCmdable{}}
+	assert.Nil(t, client.PSubscribe(context.Background(), "test:*"))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
+)
+
+// AddressWebhookJobType is the sync job type (and queue) used to deliver address change webhooks
+const AddressWebhookJobType = "address_webhook"
+
+// Headers sent with every webhook delivery. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" using the configured secret.
+const (
+	WebhookSignatureHeader = "X-RMI-Signature"
+	WebhookTimestampHeader = "X-RMI-Timestamp"
+)
+
+// QueueAddressChangeWebhook queues an address change notification for delivery by the sync workers
+func QueueAddressChangeWebhook(ctx context.Context, cpf, oldAddressHash, newAddressHash string) error {
+	payload := models.AddressChangeWebhookPayload{
+		Event:            models.AddressChangedWebhookEvent,
+		CPF:              cpf,
+		OldAddressHash:   oldAddressHash,
+		NewAddressHash:   newAddressHash,
+		PreferredChannel: GetPreferredChannel(ctx, cpf),
+		ChangedAt:        time.Now(),
+	}
+	return queueWebhookJob(ctx, AddressWebhookJobType, cpf, payload, config.AppConfig.AddressWebhookMaxRetries)
+}
+
+// queueWebhookJob pushes a webhook payload onto its sync queue, so delivery gets the sync
+// workers' retry and DLQ handling
+func queueWebhookJob(ctx context.Context, jobType, key string, payload interface{}, maxRetries int) error {
+	now := time.Now()
+	job := SyncJob{
+		ID:         fmt.Sprintf("%s_%s_%d", jobType, key, now.UnixNano()),
+		Type:       jobType,
+		Key:        key,
+		Collection: jobType,
+		Data:       payload,
+		Timestamp:  now,
+		RetryCount: 0,
+		MaxRetries: maxRetries,
+	}
+
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s job: %w", jobType, err)
+	}
+
+	queueKey := fmt.Sprintf("sync:queue:%s", jobType)
+	if err := config.Redis.LPush(ctx, queueKey, string(jobBytes)).Err(); err != nil {
+		return fmt.Errorf("failed to queue %s job: %w", jobType, err)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the signature header value for a webhook body
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliverAddressChangeWebhook posts a signed address change notification to the configured URL.
+// Any non-2xx response is returned as an error so the job is retried.
+func DeliverAddressChangeWebhook(ctx context.Context, payload models.AddressChangeWebhookPayload) error {
+	return deliverWebhook(ctx, AddressWebhookJobType, config.AppConfig.AddressWebhookURL, config.AppConfig.AddressWebhookSecret, config.AppConfig.AddressWebhookTimeout, payload)
+}
+
+func deliverWebhook(ctx context.Context, webhook, url, secret string, timeout time.Duration, payload interface{}) (err error) {
+	start := time.Now()
+	defer func() {
+		status := "success"
+		if err != nil {
+			status = "failure"
+		}
+		observability.RMIWebhookDeliveriesTotal.WithLabelValues(webhook, status).Inc()
+		observability.RMIWebhookDeliveryDuration.WithLabelValues(webhook).Observe(time.Since(start).Seconds())
+	}()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, body))
+	observability.InjectTraceHeaders(ctx, req.Header)
+
+	client := httpclient.Client(httpclient.ClientWebhook)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event":"address.changed"}`)
+
+	signature := SignWebhookPayload("secret", "1700000000", body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(t, signature, SignWebhookPayload("secret", "1700000000", body))
+	assert.NotEqual(t, signature, SignWebhookPayload("other-secret", "1700000000", body))
+	assert.NotEqual(t, signature, SignWebhookPayload("secret", "1700000001", body))
+}
+
+func TestDeliverWebhook_SignsPayload(t *testing.T) {
+	payload := models.AddressChangeWebhookPayload{
+		Event:          models.AddressChangedWebhookEvent,
+		CPF:            "12345678901",
+		OldAddressHash: HashAddress("Rua A, 1, Centro, Rio de Janeiro, RJ"),
+		NewAddressHash: HashAddress("Rua B, 2, Centro, Rio de Janeiro, RJ"),
+		ChangedAt:      time.Now(),
+	}
+
+	var received models.AddressChangeWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, SignWebhookPayload("secret", timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.Unmarshal(body, &received))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := deliverWebhook(context.Background(), AddressWebhookJobType, server.URL, "secret", time.Second, payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload.CPF, received.CPF)
+	assert.Equal(t, payload.NewAddressHash, received.NewAddressHash)
+}
+
+func TestDeliverWebhook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := deliverWebhook(context.Background(), AddressWebhookJobType, server.URL, "secret", time.Second, map[string]string{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
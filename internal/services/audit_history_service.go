@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// MaxAuditHistoryExportEntries caps how many audit entries a single export returns
+const MaxAuditHistoryExportEntries = 5000
+
+// auditActionLabels maps audit actions to human-readable (pt-BR) labels
+var auditActionLabels = map[string]string{
+	utils.AuditActionCreate:   "Criação",
+	utils.AuditActionRead:     "Consulta",
+	utils.AuditActionUpdate:   "Atualização",
+	utils.AuditActionDelete:   "Remoção",
+	utils.AuditActionValidate: "Validação",
+	utils.AuditActionLogin:    "Login",
+	utils.AuditActionLogout:   "Logout",
+}
+
+// auditResourceLabels maps audit resources to human-readable (pt-BR) labels
+var auditResourceLabels = map[string]string{
+	utils.AuditResourceAddress:              "Endereço",
+	utils.AuditResourcePhone:                "Telefone",
+	utils.AuditResourceEmail:                "E-mail",
+	utils.AuditResourceEthnicity:            "Raça/Cor",
+	utils.AuditResourceExhibitionName:       "Nome de exibição",
+	utils.AuditResourcePhoneVerification:    "Verificação de telefone",
+	utils.AuditResourceUserConfig:           "Configurações do usuário",
+	utils.AuditResourceBetaGroup:            "Grupo beta",
+	utils.AuditResourceBetaWhitelist:        "Lista beta",
+	utils.AuditResourcePhoneMapping:         "Vínculo de telefone",
+	utils.AuditResourcePhoneQuarantine:      "Quarentena de telefone",
+	utils.AuditResourceAvatar:               "Avatar",
+	utils.AuditResourceNotificationCategory: "Categoria de notificação",
+	utils.AuditResourceMemory:               "Memória",
+	utils.AuditResourcePet:                  "Pet",
+	utils.AuditResourceLegalEntity:          "Pessoa jurídica",
+	utils.AuditResourceOptInHistory:         "Histórico de opt-in",
+}
+
+// auditHistoryCSVHeader is the localized header row of the CSV export
+var auditHistoryCSVHeader = []string{"Data/Hora", "Ação", "Dado", "Valor anterior", "Novo valor"}
+
+// AuditHistoryService handles citizen audit history queries
+type AuditHistoryService struct {
+	database *mongo.Database
+	logger   *logging.SafeLogger
+}
+
+// NewAuditHistoryService creates a new audit history service instance
+func NewAuditHistoryService(database *mongo.Database, logger *logging.SafeLogger) *AuditHistoryService {
+	return &AuditHistoryService{
+		database: database,
+		logger:   logger,
+	}
+}
+
+// Global audit history service instance
+var AuditHistoryServiceInstance *AuditHistoryService
+
+// InitAuditHistoryService initializes the global audit history service instance
+func InitAuditHistoryService() {
+	logger := zap.L().Named("audit_history_service")
+
+	AuditHistoryServiceInstance = NewAuditHistoryService(config.MongoDB, &logging.SafeLogger{})
+
+	logger.Info("audit history service initialized successfully")
+}
+
+// GetHistoryByCPF returns the citizen's audit timeline (newest first) with sensitive values redacted.
+// Request metadata such as IP address and user agent is not part of the citizen-facing history.
+func (s *AuditHistoryService) GetHistoryByCPF(ctx context.Context, cpf string, limit int) ([]utils.AuditLog, error) {
+	if limit <= 0 || limit > MaxAuditHistoryExportEntries {
+		limit = MaxAuditHistoryExportEntries
+	}
+
+	collection := s.database.Collection(config.AppConfig.AuditLogsCollection)
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, bson.M{"cpf": cpf}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find audit history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []utils.AuditLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit history: %w", err)
+	}
+
+	for i := range entries {
+		entries[i].OldValue = utils.SanitizeAuditData(normalizeAuditValue(entries[i].OldValue))
+		entries[i].NewValue = utils.SanitizeAuditData(normalizeAuditValue(entries[i].NewValue))
+		entries[i].IPAddress = ""
+		entries[i].UserAgent = ""
+	}
+
+	s.logger.Debug("retrieved audit history for CPF",
+		zap.String("cpf", cpf),
+		zap.Int("entries", len(entries)))
+
+	return entries, nil
+}
+
+// AuditActorFilter selects the audit logs written by an actor (the authenticated user ID)
+type AuditActorFilter struct {
+	UserID   string
+	Resource string
+	Action   string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AuditLogListResponse represents a page of audit logs
+type AuditLogListResponse struct {
+	Data       []utils.AuditLog      `json:"data"`
+	Pagination models.PaginationInfo `json:"pagination"`
+}
+
+// auditActorQuery builds the audit_logs filter for an actor lookup; From is inclusive and To exclusive
+func auditActorQuery(filter AuditActorFilter) bson.M {
+	query := bson.M{"user_id": filter.UserID}
+	if filter.Resource != "" {
+		query["resource"] = filter.Resource
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.From != nil || filter.To != nil {
+		timestamp := bson.M{}
+		if filter.From != nil {
+			timestamp["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			timestamp["$lt"] = *filter.To
+		}
+		query["timestamp"] = timestamp
+	}
+	return query
+}
+
+// GetLogsByActor returns the audit logs written by an actor (newest first), paginated, with
+// sensitive values redacted. Unlike the citizen history, request metadata is kept for investigations.
+func (s *AuditHistoryService) GetLogsByActor(ctx context.Context, filter AuditActorFilter, page, perPage int) (*AuditLogListResponse, error) {
+	collection := s.database.Collection(config.AppConfig.AuditLogsCollection)
+	query := auditActorQuery(filter)
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * perPage)).
+		SetLimit(int64(perPage))
+
+	cursor, err := collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find audit logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []utils.AuditLog{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit logs: %w", err)
+	}
+
+	for i := range entries {
+		entries[i].OldValue = utils.SanitizeAuditData(normalizeAuditValue(entries[i].OldValue))
+		entries[i].NewValue = utils.SanitizeAuditData(normalizeAuditValue(entries[i].NewValue))
+	}
+
+	s.logger.Debug("retrieved audit logs for actor",
+		zap.String("user_id", filter.UserID),
+		zap.Int("entries", len(entries)),
+		zap.Int64("total", total))
+
+	return &AuditLogListResponse{
+		Data: entries,
+		Pagination: models.PaginationInfo{
+			Page:       page,
+			PerPage:    perPage,
+			Total:      int(total),
+			TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+		},
+	}, nil
+}
+
+// normalizeAuditValue converts BSON-decoded values (primitive.D, primitive.A) into plain
+// JSON-compatible maps and slices so they can be sanitized and rendered consistently
+func normalizeAuditValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	data, err := bson.MarshalExtJSON(bson.M{"v": value}, false, false)
+	if err != nil {
+		return value
+	}
+
+	var wrapper map[string]interface{}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return value
+	}
+	return wrapper["v"]
+}
+
+// WriteAuditHistoryCSV writes the audit timeline as a CSV with localized labels
+func WriteAuditHistoryCSV(w io.Writer, entries []utils.AuditLog) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(auditHistoryCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			entry.Timestamp.In(saoPauloLocation()).Format("02/01/2006 15:04:05"),
+			localizedAuditLabel(auditActionLabels, entry.Action),
+			localizedAuditLabel(auditResourceLabels, entry.Resource),
+			formatAuditValue(entry.OldValue),
+			formatAuditValue(entry.NewValue),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// localizedAuditLabel returns the localized label for a key, falling back to the raw key
+func localizedAuditLabel(labels map[string]string, key string) string {
+	if label, ok := labels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// formatAuditValue renders an audit value as a compact string for export
+func formatAuditValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// saoPauloLocation returns the America/Sao_Paulo timezone, falling back to UTC-3
+func saoPauloLocation() *time.Location {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		return time.FixedZone("BRT", -3*60*60)
+	}
+	return loc
+}
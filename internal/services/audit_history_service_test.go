@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestWriteAuditHistoryCSV(t *testing.T) {
+	entries := []utils.AuditLog{
+		{
+			Action:    utils.AuditActionUpdate,
+			Resource:  utils.AuditResourceEmail,
+			OldValue:  "old@example.com",
+			NewValue:  "new@example.com",
+			Timestamp: time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			Action:    "CUSTOM",
+			Resource:  "unknown_resource",
+			NewValue:  map[string]interface{}{"code": "[REDACTED]"},
+			Timestamp: time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAuditHistoryCSV(&buf, entries); err != nil {
+		t.Fatalf("WriteAuditHistoryCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse generated CSV: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("WriteAuditHistoryCSV() rows = %d, want 3", len(records))
+	}
+
+	if records[0][1] != "Ação" {
+		t.Errorf("WriteAuditHistoryCSV() header = %v, want localized labels", records[0])
+	}
+
+	if records[1][0] != "10/03/2024 12:00:00" {
+		t.Errorf("WriteAuditHistoryCSV() timestamp = %q, want local Sao Paulo time", records[1][0])
+	}
+
+	if records[1][1] != "Atualização" || records[1][2] != "E-mail" {
+		t.Errorf("WriteAuditHistoryCSV() labels = %v, want localized action/resource", records[1])
+	}
+
+	if records[2][1] != "CUSTOM" || records[2][2] != "unknown_resource" {
+		t.Errorf("WriteAuditHistoryCSV() unknown labels = %v, want raw values", records[2])
+	}
+
+	if !strings.Contains(records[2][4], "[REDACTED]") {
+		t.Errorf("WriteAuditHistoryCSV() new value = %q, want JSON-encoded value", records[2][4])
+	}
+}
+
+func TestNormalizeAuditValue(t *testing.T) {
+	value := bson.D{{Key: "code", Value: "123456"}, {Key: "nested", Value: bson.D{{Key: "token", Value: "abc"}}}}
+
+	sanitized := utils.SanitizeAuditData(normalizeAuditValue(value))
+
+	m, ok := sanitized.(map[string]interface{})
+	if !ok {
+		t.Fatalf("normalizeAuditValue() type = %T, want map[string]interface{}", sanitized)
+	}
+
+	if m["code"] != "[REDACTED]" {
+		t.Errorf("sanitized code = %v, want [REDACTED]", m["code"])
+	}
+
+	nested, ok := m["nested"].(map[string]interface{})
+	if !ok || nested["token"] != "[REDACTED]" {
+		t.Errorf("sanitized nested = %v, want token redacted", m["nested"])
+	}
+
+	if normalizeAuditValue(nil) != nil {
+		t.Error("normalizeAuditValue(nil) should return nil")
+	}
+}
+
+func TestGetHistoryByCPF(t *testing.T) {
+	if config.MongoDB == nil {
+		t.Skip("Skipping audit history service tests: MongoDB not initialized")
+	}
+
+	_ = logging.InitLogger()
+
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	config.AppConfig.AuditLogsCollection = "test_audit_history"
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.AuditLogsCollection)
+	defer collection.Drop(ctx)
+
+	_, err := collection.InsertMany(ctx, []interface{}{
+		utils.AuditLog{CPF: "03561350712", Action: utils.AuditActionUpdate, Resource: utils.AuditResourcePhone, IPAddress: "10.0.0.1", Timestamp: time.Now().Add(-time.Hour)},
+		utils.AuditLog{CPF: "03561350712", Action: utils.AuditActionUpdate, Resource: utils.AuditResourceEmail, Timestamp: time.Now()},
+		utils.AuditLog{CPF: "11144477735", Action: utils.AuditActionUpdate, Resource: utils.AuditResourceEmail, Timestamp: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert audit logs: %v", err)
+	}
+
+	service := NewAuditHistoryService(config.MongoDB, logging.GetLogger())
+	entries, err := service.GetHistoryByCPF(ctx, "03561350712", 0)
+	if err != nil {
+		t.Fatalf("GetHistoryByCPF() error = %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("GetHistoryByCPF() len = %d, want 2", len(entries))
+	}
+
+	if entries[0].Resource != utils.AuditResourceEmail {
+		t.Errorf("GetHistoryByCPF() first resource = %s, want newest entry first", entries[0].Resource)
+	}
+
+	if entries[1].IPAddress != "" {
+		t.Error("GetHistoryByCPF() should strip IP address from citizen history")
+	}
+}
+
+func TestAuditActorQuery(t *testing.T) {
+	query := auditActorQuery(AuditActorFilter{UserID: "operator-1"})
+	if len(query) != 1 || query["user_id"] != "operator-1" {
+		t.Errorf("auditActorQuery() = %v, want only user_id", query)
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	query = auditActorQuery(AuditActorFilter{
+		UserID:   "operator-1",
+		Resource: utils.AuditResourcePhone,
+		Action:   utils.AuditActionUpdate,
+		From:     &from,
+		To:       &to,
+	})
+	if query["resource"] != utils.AuditResourcePhone || query["action"] != utils.AuditActionUpdate {
+		t.Errorf("auditActorQuery() = %v, want resource and action filters", query)
+	}
+	timestamp, ok := query["timestamp"].(bson.M)
+	if !ok || timestamp["$gte"] != from || timestamp["$lt"] != to {
+		t.Errorf("timestamp filter = %v, want [from, to)", query["timestamp"])
+	}
+}
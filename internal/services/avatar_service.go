@@ -2,9 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,26 +25,79 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
 )
 
+// ErrInvalidAvatarImage is returned when an uploaded avatar image can't be decoded or isn't a supported image type
+var ErrInvalidAvatarImage = errors.New("invalid avatar image")
+
+// maxAvatarImageBytes bounds uploaded and migrated avatar images
+const maxAvatarImageBytes = 2 << 20
+
+// avatarImageExtensions lists the accepted image types and the extension used in their storage key
+var avatarImageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/webp": "webp",
+	"image/gif":  "gif",
+}
+
 // AvatarService handles avatar operations with caching
 type AvatarService struct {
 	mongoClient *mongo.Client
 	database    *mongo.Database
+	storage     AvatarStorage
 	logger      *zap.Logger
 }
 
-// NewAvatarService creates a new AvatarService instance
+// NewAvatarService creates a new AvatarService instance, storing images in MongoDB.
+// Use SetStorage to select another backend.
 func NewAvatarService(mongoClient *mongo.Client, database *mongo.Database, logger *zap.Logger) *AvatarService {
 	return &AvatarService{
 		mongoClient: mongoClient,
 		database:    database,
+		storage:     NewMongoAvatarStorage(database.Collection(config.AppConfig.AvatarImagesCollection), config.AppConfig.AvatarPublicBaseURL),
 		logger:      logger,
 	}
 }
 
-// ListAvatars retrieves paginated list of active avatars with caching
-func (s *AvatarService) ListAvatars(ctx context.Context, page, perPage int) (*models.AvatarsListResponse, error) {
+// SetStorage replaces the avatar image storage backend
+func (s *AvatarService) SetStorage(storage AvatarStorage) {
+	s.storage = storage
+}
+
+// MaxAvatarListPerPage returns the largest page size of the avatar listing (AVATAR_LIST_MAX_PER_PAGE)
+func MaxAvatarListPerPage() int {
+	if config.AppConfig.AvatarListMaxPerPage > 0 {
+		return config.AppConfig.AvatarListMaxPerPage
+	}
+	return 100
+}
+
+// avatarListSort returns the sort of the avatar listing for the given field and order. Name sorts
+// ascending and created_at descending (latest first) unless an order is given; _id breaks ties so
+// pages stay consistent between requests.
+func avatarListSort(sortField, order string) (string, string, bson.D) {
+	if sortField != models.AvatarSortName {
+		sortField = models.AvatarSortCreatedAt
+	}
+	if order != models.AvatarOrderAsc && order != models.AvatarOrderDesc {
+		order = models.AvatarOrderDesc
+		if sortField == models.AvatarSortName {
+			order = models.AvatarOrderAsc
+		}
+	}
+
+	direction := -1
+	if order == models.AvatarOrderAsc {
+		direction = 1
+	}
+	return sortField, order, bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// ListAvatars retrieves paginated list of active avatars with caching, sorted by sortField
+// ("created_at" or "name") in order ("asc" or "desc"); empty values use the default sort
+func (s *AvatarService) ListAvatars(ctx context.Context, page, perPage int, sortField, order string) (*models.AvatarsListResponse, error) {
 	ctx, span := utils.TraceBusinessLogic(ctx, "list_avatars")
 	defer span.End()
 
@@ -44,12 +105,13 @@ func (s *AvatarService) ListAvatars(ctx context.Context, page, perPage int) (*mo
 	if page < 1 {
 		page = 1
 	}
-	if perPage < 1 || perPage > 100 {
+	if perPage < 1 || perPage > MaxAvatarListPerPage() {
 		perPage = 20 // Default page size
 	}
+	sortField, order, sort := avatarListSort(sortField, order)
 
 	// Try cache first
-	cacheKey := fmt.Sprintf("avatars:list:page:%d:per_page:%d", page, perPage)
+	cacheKey := fmt.Sprintf("avatars:list:page:%d:per_page:%d:sort:%s:%s", page, perPage, sortField, order)
 	cached, err := config.Redis.Get(ctx, cacheKey).Result()
 	if err == nil {
 		observability.CacheHits.WithLabelValues("list_avatars").Inc()
@@ -87,7 +149,7 @@ func (s *AvatarService) ListAvatars(ctx context.Context, page, perPage int) (*mo
 	findOptions := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(perPage)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}}) // Latest first
+		SetSort(sort)
 
 	cursor, err := collection.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -104,8 +166,9 @@ func (s *AvatarService) ListAvatars(ctx context.Context, page, perPage int) (*mo
 
 	// Convert to response format
 	avatarResponses := make([]models.AvatarResponse, len(avatars))
-	for i, avatar := range avatars {
-		avatarResponses[i] = avatar.ToResponse()
+	for i := range avatars {
+		s.resolveURL(&avatars[i])
+		avatarResponses[i] = avatars[i].ToResponse()
 	}
 
 	response := &models.AvatarsListResponse{
@@ -153,6 +216,7 @@ func (s *AvatarService) GetAvatarByID(ctx context.Context, avatarID string) (*mo
 
 		var avatar models.Avatar
 		if err := json.Unmarshal([]byte(cached), &avatar); err == nil {
+			s.resolveURL(&avatar)
 			return &avatar, nil
 		}
 	}
@@ -186,10 +250,12 @@ func (s *AvatarService) GetAvatarByID(ctx context.Context, avatarID string) (*mo
 
 	s.logger.Debug("avatar found and cached", zap.String("id", avatarID), zap.String("name", avatar.Name))
 
+	s.resolveURL(&avatar)
 	return &avatar, nil
 }
 
-// CreateAvatar creates a new avatar (admin only)
+// CreateAvatar creates a new avatar (admin only). An uploaded image is kept in the storage
+// backend and the avatar URL is resolved from it.
 func (s *AvatarService) CreateAvatar(ctx context.Context, request *models.AvatarRequest) (*models.Avatar, error) {
 	ctx, span := utils.TraceBusinessLogic(ctx, "create_avatar")
 	defer span.End()
@@ -204,6 +270,19 @@ func (s *AvatarService) CreateAvatar(ctx context.Context, request *models.Avatar
 		UpdatedAt: time.Now(),
 	}
 
+	if request.Image != "" {
+		data, contentType, err := decodeAvatarImage(request.Image)
+		if err != nil {
+			return nil, err
+		}
+		avatar.StorageKey = avatarStorageKey(avatar.ID, contentType)
+		if err := s.storage.Put(ctx, avatar.StorageKey, data, contentType); err != nil {
+			s.logger.Error("failed to store avatar image", zap.Error(err), zap.String("name", request.Name))
+			return nil, fmt.Errorf("failed to store avatar image: %w", err)
+		}
+		s.resolveURL(avatar)
+	}
+
 	// Insert into database
 	ctx, dbSpan := utils.TraceDatabaseUpdate(ctx, config.AppConfig.AvatarsCollection, "create_avatar", false)
 	defer dbSpan.End()
@@ -212,6 +291,11 @@ func (s *AvatarService) CreateAvatar(ctx context.Context, request *models.Avatar
 	_, err := collection.InsertOne(ctx, avatar)
 	if err != nil {
 		s.logger.Error("failed to create avatar", zap.Error(err), zap.String("name", request.Name))
+		if avatar.StorageKey != "" {
+			if delErr := s.storage.Delete(ctx, avatar.StorageKey); delErr != nil {
+				s.logger.Warn("failed to remove orphaned avatar image", zap.Error(delErr), zap.String("key", avatar.StorageKey))
+			}
+		}
 		return nil, fmt.Errorf("failed to create avatar: %w", err)
 	}
 
@@ -287,6 +371,199 @@ func (s *AvatarService) ValidateAvatarExists(ctx context.Context, avatarID strin
 	return avatar != nil, nil
 }
 
+// PickDefaultAvatar chooses an avatar from the active pool for a new user, according to
+// DEFAULT_AVATAR_MODE. It returns nil when the mode is off or there are no active avatars.
+func (s *AvatarService) PickDefaultAvatar(ctx context.Context, cpf string) (*models.Avatar, error) {
+	mode := config.AppConfig.DefaultAvatarMode
+	if mode == "" || mode == config.DefaultAvatarModeOff {
+		return nil, nil
+	}
+
+	ctx, span := utils.TraceDatabaseFind(ctx, config.AppConfig.AvatarsCollection, "default_avatar_pool")
+	defer span.End()
+
+	// Oldest first, so the deterministic pick only moves when the pool changes
+	cursor, err := s.database.Collection(config.AppConfig.AvatarsCollection).Find(
+		ctx,
+		bson.M{"is_active": true},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query avatar pool: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var pool []models.Avatar
+	if err := cursor.All(ctx, &pool); err != nil {
+		return nil, fmt.Errorf("failed to decode avatar pool: %w", err)
+	}
+	if len(pool) == 0 {
+		return nil, nil
+	}
+
+	var index int
+	if mode == config.DefaultAvatarModeDeterministic {
+		hash := fnv.New32a()
+		hash.Write([]byte(cpf))
+		index = int(hash.Sum32() % uint32(len(pool)))
+	} else {
+		index = rand.IntN(len(pool))
+	}
+
+	utils.AddSpanAttribute(span, "pool_size", len(pool))
+	s.resolveURL(&pool[index])
+	return &pool[index], nil
+}
+
+// GetAvatarImage returns the bytes and content type of an image kept in the storage backend
+func (s *AvatarService) GetAvatarImage(ctx context.Context, key string) ([]byte, string, error) {
+	ctx, span := utils.TraceExternalService(ctx, "avatar_storage", "get_image")
+	defer span.End()
+
+	return s.storage.Get(ctx, key)
+}
+
+// MigrateAvatarImages copies the images of avatars that still point at an external URL into the
+// storage backend, so their URL is resolved from storage (and the CDN) from then on. Avatars are
+// migrated one by one; a failed download or upload leaves that avatar untouched and is reported
+// in the response.
+func (s *AvatarService) MigrateAvatarImages(ctx context.Context, client *http.Client) (*models.AvatarMigrationResponse, error) {
+	ctx, span := utils.TraceBusinessLogic(ctx, "migrate_avatar_images")
+	defer span.End()
+
+	collection := s.database.Collection(config.AppConfig.AvatarsCollection)
+	cursor, err := collection.Find(ctx, bson.M{
+		"storage_key": bson.M{"$exists": false},
+		"url":         bson.M{"$ne": ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query avatars: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var avatars []models.Avatar
+	if err := cursor.All(ctx, &avatars); err != nil {
+		return nil, fmt.Errorf("failed to decode avatars: %w", err)
+	}
+
+	response := &models.AvatarMigrationResponse{}
+	for _, avatar := range avatars {
+		if err := s.migrateAvatarImage(ctx, client, avatar); err != nil {
+			s.logger.Warn("failed to migrate avatar image", zap.Error(err), zap.String("avatar_id", avatar.ID.Hex()))
+			response.Failed++
+			response.Errors = append(response.Errors, fmt.Sprintf("%s: %v", avatar.ID.Hex(), err))
+			continue
+		}
+		response.Migrated++
+	}
+
+	if response.Migrated > 0 {
+		s.invalidateListCache(ctx)
+	}
+
+	utils.AddSpanAttribute(span, "migrated", response.Migrated)
+	utils.AddSpanAttribute(span, "failed", response.Failed)
+	s.logger.Info("avatar images migrated", zap.Int("migrated", response.Migrated), zap.Int("failed", response.Failed))
+	return response, nil
+}
+
+// migrateAvatarImage downloads one avatar image, stores it and records its storage key
+func (s *AvatarService) migrateAvatarImage(ctx context.Context, client *http.Client, avatar models.Avatar) error {
+	if err := validateAvatarSourceURL(avatar.URL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, avatar.URL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid avatar URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download avatar image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download avatar image: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAvatarImageBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read avatar image: %w", err)
+	}
+	if len(data) > maxAvatarImageBytes {
+		return fmt.Errorf("%w: larger than %d bytes", ErrInvalidAvatarImage, maxAvatarImageBytes)
+	}
+	contentType := http.DetectContentType(data)
+	if _, ok := avatarImageExtensions[contentType]; !ok {
+		return fmt.Errorf("%w: unsupported type %s", ErrInvalidAvatarImage, contentType)
+	}
+
+	key := avatarStorageKey(avatar.ID, contentType)
+	if err := s.storage.Put(ctx, key, data, contentType); err != nil {
+		return err
+	}
+
+	_, err = s.database.Collection(config.AppConfig.AvatarsCollection).UpdateOne(ctx,
+		bson.M{"_id": avatar.ID},
+		bson.M{"$set": bson.M{"storage_key": key, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		if delErr := s.storage.Delete(ctx, key); delErr != nil {
+			s.logger.Warn("failed to remove orphaned avatar image", zap.Error(delErr), zap.String("key", key))
+		}
+		return fmt.Errorf("failed to update avatar: %w", err)
+	}
+
+	s.invalidateAvatarCache(ctx, avatar.ID.Hex())
+	return nil
+}
+
+// validateAvatarSourceURL only accepts http(s) URLs whose host isn't a non-public IP. Hostnames
+// are checked again when dialing, as they may resolve to internal addresses.
+func validateAvatarSourceURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid avatar URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid avatar URL: unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid avatar URL: missing host")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !httpclient.IsPublicIP(ip) {
+		return fmt.Errorf("invalid avatar URL: non-public address %s", ip)
+	}
+	return nil
+}
+
+// resolveURL points the avatar URL at its image in the storage backend, when it has one
+func (s *AvatarService) resolveURL(avatar *models.Avatar) {
+	if avatar.StorageKey != "" {
+		avatar.URL = s.storage.URL(avatar.StorageKey)
+	}
+}
+
+// decodeAvatarImage decodes a base64 avatar image and checks its type and size
+func decodeAvatarImage(encoded string) ([]byte, string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: not valid base64", ErrInvalidAvatarImage)
+	}
+	if len(data) == 0 || len(data) > maxAvatarImageBytes {
+		return nil, "", fmt.Errorf("%w: size must be between 1 and %d bytes", ErrInvalidAvatarImage, maxAvatarImageBytes)
+	}
+	contentType := http.DetectContentType(data)
+	if _, ok := avatarImageExtensions[contentType]; !ok {
+		return nil, "", fmt.Errorf("%w: unsupported type %s", ErrInvalidAvatarImage, contentType)
+	}
+	return data, contentType, nil
+}
+
+// avatarStorageKey returns the storage key of an avatar image, e.g. "avatars/<id>.png"
+func avatarStorageKey(id primitive.ObjectID, contentType string) string {
+	return "avatars/" + id.Hex() + "." + avatarImageExtensions[contentType]
+}
+
 // invalidateAvatarCache removes avatar from cache
 func (s *AvatarService) invalidateAvatarCache(ctx context.Context, avatarID string) {
 	cacheKey := fmt.Sprintf("avatar:id:%s", avatarID)
@@ -346,5 +623,6 @@ var AvatarServiceInstance *AvatarService
 func InitAvatarService() {
 	logger := zap.L().Named("avatar_service")
 	AvatarServiceInstance = NewAvatarService(config.MongoDB.Client(), config.MongoDB, logger)
-	logger.Info("avatar service initialized")
+	AvatarServiceInstance.SetStorage(NewAvatarStorage(config.MongoDB))
+	logger.Info("avatar service initialized", zap.String("storage_backend", config.AppConfig.AvatarStorageBackend))
 }
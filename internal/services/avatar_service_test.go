@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,7 +69,7 @@ func TestListAvatars_Empty(t *testing.T) {
 
 	ctx := context.Background()
 
-	response, err := service.ListAvatars(ctx, 1, 20)
+	response, err := service.ListAvatars(ctx, 1, 20, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() error = %v", err)
 	}
@@ -126,7 +127,7 @@ func TestListAvatars_WithData(t *testing.T) {
 	}
 
 	// List avatars
-	response, err := service.ListAvatars(ctx, 1, 20)
+	response, err := service.ListAvatars(ctx, 1, 20, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() error = %v", err)
 	}
@@ -170,7 +171,7 @@ func TestListAvatars_Pagination(t *testing.T) {
 	}
 
 	// Test pagination
-	response, err := service.ListAvatars(ctx, 1, 2)
+	response, err := service.ListAvatars(ctx, 1, 2, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() page 1 error = %v", err)
 	}
@@ -188,7 +189,7 @@ func TestListAvatars_Pagination(t *testing.T) {
 	}
 
 	// Test page 2
-	response2, err := service.ListAvatars(ctx, 2, 2)
+	response2, err := service.ListAvatars(ctx, 2, 2, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() page 2 error = %v", err)
 	}
@@ -198,6 +199,49 @@ func TestListAvatars_Pagination(t *testing.T) {
 	}
 }
 
+func TestListAvatars_Sort(t *testing.T) {
+	service, cleanup := setupAvatarServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Two avatars share created_at, so only the _id tiebreak orders them
+	collection := config.MongoDB.Collection(config.AppConfig.AvatarsCollection)
+	now := time.Now().Truncate(time.Millisecond)
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	_, err := collection.InsertMany(ctx, []interface{}{
+		models.Avatar{ID: first, Name: "Tucano", URL: "http://example.com/t.png", IsActive: true, CreatedAt: now},
+		models.Avatar{ID: second, Name: "Arara", URL: "http://example.com/a.png", IsActive: true, CreatedAt: now},
+		models.Avatar{ID: primitive.NewObjectID(), Name: "Mico", URL: "http://example.com/m.png", IsActive: true, CreatedAt: now.Add(-time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert avatars: %v", err)
+	}
+
+	tests := []struct {
+		sort, order string
+		want        []string
+	}{
+		{"", "", []string{"Arara", "Tucano", "Mico"}},
+		{"created_at", "asc", []string{"Mico", "Tucano", "Arara"}},
+		{"name", "", []string{"Arara", "Mico", "Tucano"}},
+		{"name", "desc", []string{"Tucano", "Mico", "Arara"}},
+	}
+	for _, tt := range tests {
+		response, err := service.ListAvatars(ctx, 1, 20, tt.sort, tt.order)
+		if err != nil {
+			t.Fatalf("ListAvatars(%q, %q) error = %v", tt.sort, tt.order, err)
+		}
+		var names []string
+		for _, avatar := range response.Data {
+			names = append(names, avatar.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ListAvatars(%q, %q) = %v, want %v", tt.sort, tt.order, names, tt.want)
+		}
+	}
+}
+
 func TestListAvatars_FromCache(t *testing.T) {
 	service, cleanup := setupAvatarServiceTest(t)
 	defer cleanup()
@@ -220,7 +264,7 @@ func TestListAvatars_FromCache(t *testing.T) {
 	}
 
 	// First call - populates cache
-	_, err = service.ListAvatars(ctx, 1, 20)
+	_, err = service.ListAvatars(ctx, 1, 20, "", "")
 	if err != nil {
 		t.Fatalf("First ListAvatars() error = %v", err)
 	}
@@ -229,7 +273,7 @@ func TestListAvatars_FromCache(t *testing.T) {
 	_, _ = collection.DeleteOne(ctx, bson.M{"_id": avatar.ID})
 
 	// Second call - should use cache
-	response, err := service.ListAvatars(ctx, 1, 20)
+	response, err := service.ListAvatars(ctx, 1, 20, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() from cache error = %v", err)
 	}
@@ -246,7 +290,7 @@ func TestListAvatars_InvalidPagination(t *testing.T) {
 	ctx := context.Background()
 
 	// Test with invalid page (should default to 1)
-	response, err := service.ListAvatars(ctx, 0, 20)
+	response, err := service.ListAvatars(ctx, 0, 20, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() error = %v", err)
 	}
@@ -256,7 +300,7 @@ func TestListAvatars_InvalidPagination(t *testing.T) {
 	}
 
 	// Test with invalid perPage (should default to 20)
-	response, err = service.ListAvatars(ctx, 1, 0)
+	response, err = service.ListAvatars(ctx, 1, 0, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() error = %v", err)
 	}
@@ -266,7 +310,7 @@ func TestListAvatars_InvalidPagination(t *testing.T) {
 	}
 
 	// Test with perPage > 100 (should cap to 20)
-	response, err = service.ListAvatars(ctx, 1, 150)
+	response, err = service.ListAvatars(ctx, 1, 150, "", "")
 	if err != nil {
 		t.Errorf("ListAvatars() error = %v", err)
 	}
@@ -607,3 +651,83 @@ func TestValidateAvatarExists_Inactive(t *testing.T) {
 		t.Error("ValidateAvatarExists() should return false for inactive avatar")
 	}
 }
+
+func TestPickDefaultAvatar(t *testing.T) {
+	service, cleanup := setupAvatarServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	original := config.AppConfig.DefaultAvatarMode
+	defer func() { config.AppConfig.DefaultAvatarMode = original }()
+
+	// Empty pool
+	config.AppConfig.DefaultAvatarMode = config.DefaultAvatarModeDeterministic
+	avatar, err := service.PickDefaultAvatar(ctx, "03561350712")
+	if err != nil {
+		t.Fatalf("PickDefaultAvatar() error = %v", err)
+	}
+	if avatar != nil {
+		t.Errorf("PickDefaultAvatar() with empty pool = %v, want nil", avatar)
+	}
+
+	collection := config.MongoDB.Collection(config.AppConfig.AvatarsCollection)
+	activeIDs := make(map[primitive.ObjectID]bool)
+	for i := 0; i < 3; i++ {
+		id := primitive.NewObjectID()
+		activeIDs[id] = true
+		if _, err := collection.InsertOne(ctx, bson.M{"_id": id, "name": "Avatar", "url": "https://example.com/a.png", "is_active": true, "created_at": time.Now()}); err != nil {
+			t.Fatalf("Failed to insert avatar: %v", err)
+		}
+	}
+	if _, err := collection.InsertOne(ctx, bson.M{"name": "Inactive", "url": "https://example.com/b.png", "is_active": false, "created_at": time.Now()}); err != nil {
+		t.Fatalf("Failed to insert avatar: %v", err)
+	}
+
+	// Deterministic picks the same active avatar for a CPF
+	first, err := service.PickDefaultAvatar(ctx, "03561350712")
+	if err != nil || first == nil {
+		t.Fatalf("PickDefaultAvatar() = %v, %v, want an avatar", first, err)
+	}
+	second, _ := service.PickDefaultAvatar(ctx, "03561350712")
+	if second == nil || second.ID != first.ID {
+		t.Errorf("PickDefaultAvatar() deterministic = %v, want %v", second, first.ID)
+	}
+	if !activeIDs[first.ID] {
+		t.Errorf("PickDefaultAvatar() = %v, want an active avatar", first.ID)
+	}
+
+	// Random picks an active avatar
+	config.AppConfig.DefaultAvatarMode = config.DefaultAvatarModeRandom
+	random, err := service.PickDefaultAvatar(ctx, "03561350712")
+	if err != nil || random == nil || !activeIDs[random.ID] {
+		t.Errorf("PickDefaultAvatar() random = %v, %v, want an active avatar", random, err)
+	}
+
+	// Off assigns nothing
+	config.AppConfig.DefaultAvatarMode = config.DefaultAvatarModeOff
+	if avatar, _ := service.PickDefaultAvatar(ctx, "03561350712"); avatar != nil {
+		t.Errorf("PickDefaultAvatar() with mode off = %v, want nil", avatar)
+	}
+}
+
+func TestValidateAvatarSourceURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://cdn.example.com/avatar.png", false},
+		{"http://8.8.8.8/avatar.png", false},
+		{"ftp://cdn.example.com/avatar.png", true},
+		{"file:///etc/passwd", true},
+		{"https:///avatar.png", true},
+		{"http://127.0.0.1:6379/", true},
+		{"http://169.254.169.254/latest/meta-data/", true},
+		{"http://10.0.0.5/avatar.png", true},
+		{"http://[::1]/avatar.png", true},
+	}
+	for _, tt := range tests {
+		if err := validateAvatarSourceURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("validateAvatarSourceURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
+)
+
+// ErrAvatarImageNotFound is returned when the storage backend has no image under the given key
+var ErrAvatarImageNotFound = errors.New("avatar image not found")
+
+// avatarImagePath is the API route serving images kept in the storage backend
+const avatarImagePath = "/v1/avatars/images/"
+
+// AvatarStorage keeps avatar image bytes outside the avatars collection, so the images can live
+// in object storage and be served from a CDN
+type AvatarStorage interface {
+	// Put stores the image under key, replacing any previous one
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns the image bytes and content type, or ErrAvatarImageNotFound
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	// Delete removes the image; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the image
+	URL(key string) string
+}
+
+// NewAvatarStorage returns the storage backend selected by AVATAR_STORAGE_BACKEND
+func NewAvatarStorage(database *mongo.Database) AvatarStorage {
+	if config.AppConfig.AvatarStorageBackend == config.AvatarStorageS3 {
+		return NewS3AvatarStorage(
+			config.AppConfig.AvatarS3Endpoint,
+			config.AppConfig.AvatarS3Bucket,
+			config.AppConfig.AvatarS3Region,
+			config.AppConfig.AvatarS3AccessKeyID,
+			config.AppConfig.AvatarS3SecretAccessKey,
+			config.AppConfig.AvatarPublicBaseURL,
+		)
+	}
+	return NewMongoAvatarStorage(database.Collection(config.AppConfig.AvatarImagesCollection), config.AppConfig.AvatarPublicBaseURL)
+}
+
+// avatarImage is the document stored by MongoAvatarStorage
+type avatarImage struct {
+	Key         string    `bson:"_id"`
+	Data        []byte    `bson:"data"`
+	ContentType string    `bson:"content_type"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+}
+
+// MongoAvatarStorage keeps avatar images in a MongoDB collection, served by the API itself
+type MongoAvatarStorage struct {
+	collection *mongo.Collection
+	baseURL    string
+}
+
+// NewMongoAvatarStorage creates a MongoAvatarStorage. baseURL is prepended to the API image route
+// and may be empty for relative URLs.
+func NewMongoAvatarStorage(collection *mongo.Collection, baseURL string) *MongoAvatarStorage {
+	return &MongoAvatarStorage{collection: collection, baseURL: baseURL}
+}
+
+// Put stores the image under key
+func (s *MongoAvatarStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	image := avatarImage{Key: key, Data: data, ContentType: contentType, UpdatedAt: time.Now()}
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": key}, image, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to store avatar image: %w", err)
+	}
+	return nil
+}
+
+// Get returns the image stored under key
+func (s *MongoAvatarStorage) Get(ctx context.Context, key string) ([]byte, string, error) {
+	var image avatarImage
+	if err := s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&image); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, "", ErrAvatarImageNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get avatar image: %w", err)
+	}
+	return image.Data, image.ContentType, nil
+}
+
+// Delete removes the image stored under key
+func (s *MongoAvatarStorage) Delete(ctx context.Context, key string) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("failed to delete avatar image: %w", err)
+	}
+	return nil
+}
+
+// URL returns the API route serving the image
+func (s *MongoAvatarStorage) URL(key string) string {
+	return s.baseURL + avatarImagePath + key
+}
+
+// S3AvatarStorage keeps avatar images in an S3-compatible bucket, addressed path-style and signed
+// with AWS Signature Version 4 (also accepted by GCS HMAC keys and MinIO)
+type S3AvatarStorage struct {
+	endpoint        string
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	publicBaseURL   string
+	client          *http.Client
+	now             func() time.Time
+}
+
+// NewS3AvatarStorage creates an S3AvatarStorage. publicBaseURL (e.g. a CDN in front of the bucket)
+// is used for image URLs; when empty, URLs point at the bucket on the endpoint.
+func NewS3AvatarStorage(endpoint, bucket, region, accessKeyID, secretAccessKey, publicBaseURL string) *S3AvatarStorage {
+	return &S3AvatarStorage{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		bucket:          bucket,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		publicBaseURL:   strings.TrimSuffix(publicBaseURL, "/"),
+		client:          httpclient.Client(httpclient.ClientAvatarStorage),
+		now:             time.Now,
+	}
+}
+
+// Put uploads the image to the bucket
+func (s *S3AvatarStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data, contentType)
+	if err != nil {
+		return fmt.Errorf("failed to upload avatar image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload avatar image: %s", s3ErrorStatus(resp))
+	}
+	return nil
+}
+
+// Get downloads the image from the bucket
+func (s *S3AvatarStorage) Get(ctx context.Context, key string) ([]byte, string, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download avatar image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", ErrAvatarImageNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download avatar image: %s", s3ErrorStatus(resp))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read avatar image: %w", err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// Delete removes the image from the bucket
+func (s *S3AvatarStorage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to delete avatar image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete avatar image: %s", s3ErrorStatus(resp))
+	}
+	return nil
+}
+
+// URL returns the public URL of the image
+func (s *S3AvatarStorage) URL(key string) string {
+	if s.publicBaseURL != "" {
+		return s.publicBaseURL + "/" + key
+	}
+	return s.endpoint + s.objectPath(key)
+}
+
+// objectPath returns the escaped path-style object path, which is also the SigV4 canonical URI
+func (s *S3AvatarStorage) objectPath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/" + url.PathEscape(s.bucket) + "/" + strings.Join(segments, "/")
+}
+
+// do sends a signed request for the object under key
+func (s *S3AvatarStorage) do(ctx context.Context, method, key string, body []byte, contentType string) (*http.Response, error) {
+	path := s.objectPath(key)
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, path, body)
+	return s.client.Do(req)
+}
+
+// sign adds the AWS Signature Version 4 headers to req
+func (s *S3AvatarStorage) sign(req *http.Request, canonicalURI string, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHex)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		"",
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHex + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3ErrorStatus describes a failed S3 response, including the start of its XML error body
+func s3ErrorStatus(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// testAvatarPNG is enough of a PNG for http.DetectContentType
+var testAvatarPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+
+// fakeS3 is a minimal in-memory S3 endpoint that checks requests are signed
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/20250102/us-east-1/s3/aws4_request") ||
+		r.Header.Get("X-Amz-Date") != "20250102T030405Z" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3AvatarStorage(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	storage := NewS3AvatarStorage(server.URL, "avatars-bucket", "us-east-1", "test-key", "test-secret", "")
+	storage.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	ctx := context.Background()
+
+	if err := storage.Put(ctx, "avatars/abc.png", testAvatarPNG, "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := fake.objects["/avatars-bucket/avatars/abc.png"]; !ok {
+		t.Errorf("Put() stored objects = %v, want path-style key", fake.objects)
+	}
+
+	data, contentType, err := storage.Get(ctx, "avatars/abc.png")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !bytes.Equal(data, testAvatarPNG) || contentType != "image/png" {
+		t.Errorf("Get() = %d bytes of %q, want the stored PNG", len(data), contentType)
+	}
+
+	if err := storage.Delete(ctx, "avatars/abc.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, _, err := storage.Get(ctx, "avatars/abc.png"); !errors.Is(err, ErrAvatarImageNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrAvatarImageNotFound", err)
+	}
+
+	if got := storage.URL("avatars/abc.png"); got != server.URL+"/avatars-bucket/avatars/abc.png" {
+		t.Errorf("URL() = %q, want the bucket URL", got)
+	}
+	cdn := NewS3AvatarStorage(server.URL, "avatars-bucket", "us-east-1", "test-key", "test-secret", "https://cdn.example.com/")
+	if got := cdn.URL("avatars/abc.png"); got != "https://cdn.example.com/avatars/abc.png" {
+		t.Errorf("URL() with public base = %q, want the CDN URL", got)
+	}
+}
+
+func TestS3AvatarStorage_RejectedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>SignatureDoesNotMatch</Code></Error>"))
+	}))
+	defer server.Close()
+
+	storage := NewS3AvatarStorage(server.URL, "bucket", "us-east-1", "key", "secret", "")
+	err := storage.Put(context.Background(), "avatars/abc.png", testAvatarPNG, "image/png")
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put() error = %v, want the S3 error", err)
+	}
+}
+
+func TestDecodeAvatarImage(t *testing.T) {
+	data, contentType, err := decodeAvatarImage(base64.StdEncoding.EncodeToString(testAvatarPNG))
+	if err != nil || contentType != "image/png" || !bytes.Equal(data, testAvatarPNG) {
+		t.Errorf("decodeAvatarImage() = %q, %v, want the PNG", contentType, err)
+	}
+
+	tests := map[string]string{
+		"invalid base64": "not base64!",
+		"empty":          "",
+		"not an image":   base64.StdEncoding.EncodeToString([]byte("plain text")),
+		"too large":      base64.StdEncoding.EncodeToString(append(testAvatarPNG, make([]byte, maxAvatarImageBytes)...)),
+	}
+	for name, encoded := range tests {
+		if _, _, err := decodeAvatarImage(encoded); !errors.Is(err, ErrInvalidAvatarImage) {
+			t.Errorf("decodeAvatarImage(%s) error = %v, want ErrInvalidAvatarImage", name, err)
+		}
+	}
+}
+
+func TestCreateAvatar_WithImage(t *testing.T) {
+	service, cleanup := setupAvatarServiceTest(t)
+	defer cleanup()
+	config.AppConfig.AvatarImagesCollection = "test_avatar_images"
+	service.SetStorage(NewMongoAvatarStorage(config.MongoDB.Collection("test_avatar_images"), "https://api.example.com"))
+	defer config.MongoDB.Collection("test_avatar_images").Drop(context.Background())
+
+	ctx := context.Background()
+	avatar, err := service.CreateAvatar(ctx, &models.AvatarRequest{
+		Name:  "Uploaded",
+		Image: base64.StdEncoding.EncodeToString(testAvatarPNG),
+	})
+	if err != nil {
+		t.Fatalf("CreateAvatar() error = %v", err)
+	}
+
+	wantKey := "avatars/" + avatar.ID.Hex() + ".png"
+	if avatar.StorageKey != wantKey || avatar.URL != "https://api.example.com/v1/avatars/images/"+wantKey {
+		t.Errorf("CreateAvatar() key = %q, URL = %q", avatar.StorageKey, avatar.URL)
+	}
+
+	data, contentType, err := service.GetAvatarImage(ctx, wantKey)
+	if err != nil || contentType != "image/png" || !bytes.Equal(data, testAvatarPNG) {
+		t.Errorf("GetAvatarImage() = %q, %v, want the uploaded PNG", contentType, err)
+	}
+
+	found, err := service.GetAvatarByID(ctx, avatar.ID.Hex())
+	if err != nil || found == nil || found.URL != avatar.URL {
+		t.Errorf("GetAvatarByID() = %+v, %v, want the storage URL", found, err)
+	}
+}
+
+func TestMigrateAvatarImages(t *testing.T) {
+	service, cleanup := setupAvatarServiceTest(t)
+	defer cleanup()
+	config.AppConfig.AvatarImagesCollection = "test_avatar_images"
+	service.SetStorage(NewMongoAvatarStorage(config.MongoDB.Collection("test_avatar_images"), ""))
+	defer config.MongoDB.Collection("test_avatar_images").Drop(context.Background())
+
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok.png" {
+			w.Write(testAvatarPNG)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer images.Close()
+
+	ctx := context.Background()
+	ok, err := service.CreateAvatar(ctx, &models.AvatarRequest{Name: "Hosted", URL: images.URL + "/ok.png"})
+	if err != nil {
+		t.Fatalf("CreateAvatar() error = %v", err)
+	}
+	if _, err := service.CreateAvatar(ctx, &models.AvatarRequest{Name: "Broken", URL: images.URL + "/missing.png"}); err != nil {
+		t.Fatalf("CreateAvatar() error = %v", err)
+	}
+
+	result, err := service.MigrateAvatarImages(ctx, images.Client())
+	if err != nil {
+		t.Fatalf("MigrateAvatarImages() error = %v", err)
+	}
+	if result.Migrated != 1 || result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("MigrateAvatarImages() = %+v, want 1 migrated and 1 failed", result)
+	}
+
+	var migrated models.Avatar
+	err = config.MongoDB.Collection(config.AppConfig.AvatarsCollection).FindOne(ctx, bson.M{"_id": ok.ID}).Decode(&migrated)
+	if err != nil {
+		t.Fatalf("failed to read migrated avatar: %v", err)
+	}
+	if migrated.StorageKey != "avatars/"+ok.ID.Hex()+".png" {
+		t.Errorf("migrated storage_key = %q", migrated.StorageKey)
+	}
+
+	// Already migrated avatars are skipped on the next run
+	result, err = service.MigrateAvatarImages(ctx, images.Client())
+	if err != nil || result.Migrated != 0 {
+		t.Errorf("second MigrateAvatarImages() = %+v, %v, want nothing migrated", result, err)
+	}
+}
@@ -2,14 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -240,6 +243,7 @@ func (s *BetaGroupService) DeleteGroup(ctx context.Context, groupID string) erro
 
 	// Invalidate cache for all phones that were in this group
 	s.invalidateBetaStatusCache(ctx, groupID)
+	s.invalidateGroupStats(ctx, groupID)
 
 	return nil
 }
@@ -299,6 +303,7 @@ func (s *BetaGroupService) AddToWhitelist(ctx context.Context, phoneNumber, grou
 
 	// Invalidate cache for this phone
 	s.invalidateBetaStatusCacheForPhone(ctx, storagePhone)
+	s.invalidateGroupStats(ctx, groupID)
 
 	return &models.BetaWhitelistResponse{
 		PhoneNumber: phoneNumber,
@@ -342,6 +347,7 @@ func (s *BetaGroupService) RemoveFromWhitelist(ctx context.Context, phoneNumber
 
 	// Invalidate cache for this phone
 	s.invalidateBetaStatusCacheForPhone(ctx, storagePhone)
+	s.invalidateGroupStats(ctx, mapping.BetaGroupID)
 
 	return nil
 }
@@ -452,69 +458,18 @@ func (s *BetaGroupService) ListWhitelistedPhones(ctx context.Context, page, perP
 			continue
 		}
 
-		// Extract phone number (required field)
-		phoneNumber, ok := rawDoc["phone_number"].(string)
+		entry, ok := s.decodeWhitelistEntry(rawDoc)
 		if !ok {
-			s.logger.Warn("phone mapping has non-string phone_number field",
-				zap.String("collection", config.AppConfig.PhoneMappingCollection),
-				zap.Any("phone_number_value", rawDoc["phone_number"]),
-				zap.String("phone_number_type", fmt.Sprintf("%T", rawDoc["phone_number"])),
-				zap.Any("beta_group_id", rawDoc["beta_group_id"]))
-			continue
-		}
-		if phoneNumber == "" {
-			s.logger.Warn("phone mapping has empty phone_number",
-				zap.String("collection", config.AppConfig.PhoneMappingCollection),
-				zap.Any("beta_group_id", rawDoc["beta_group_id"]))
-			continue
-		}
-
-		// Extract beta_group_id (handle both string and ObjectID types)
-		var betaGroupID string
-		switch v := rawDoc["beta_group_id"].(type) {
-		case string:
-			betaGroupID = v
-		case primitive.ObjectID:
-			betaGroupID = v.Hex()
-		default:
-			s.logger.Warn("phone mapping has invalid beta_group_id type",
-				zap.String("phone_number", phoneNumber),
-				zap.String("collection", config.AppConfig.PhoneMappingCollection),
-				zap.Any("beta_group_id", rawDoc["beta_group_id"]))
-			continue
-		}
-
-		if betaGroupID == "" {
-			s.logger.Warn("phone mapping has empty beta_group_id",
-				zap.String("phone_number", phoneNumber),
-				zap.String("collection", config.AppConfig.PhoneMappingCollection))
 			continue
 		}
 
 		// Get group name
-		groupName := ""
-		group, err := s.GetGroup(ctx, betaGroupID)
+		group, err := s.GetGroup(ctx, entry.GroupID)
 		if err == nil {
-			groupName = group.Name
-		}
-
-		// Extract updated_at timestamp with fallback to created_at
-		var addedAt time.Time
-		if updatedAt, ok := rawDoc["updated_at"].(primitive.DateTime); ok {
-			addedAt = updatedAt.Time()
-		} else if createdAt, ok := rawDoc["created_at"].(primitive.DateTime); ok {
-			addedAt = createdAt.Time()
-		} else {
-			// If both timestamps are missing, use zero time
-			addedAt = time.Time{}
+			entry.GroupName = group.Name
 		}
 
-		whitelisted = append(whitelisted, models.BetaWhitelistResponse{
-			PhoneNumber: phoneNumber,
-			GroupID:     betaGroupID,
-			GroupName:   groupName,
-			AddedAt:     addedAt,
-		})
+		whitelisted = append(whitelisted, entry)
 	}
 
 	return &models.BetaWhitelistListResponse{
@@ -528,6 +483,199 @@ func (s *BetaGroupService) ListWhitelistedPhones(ctx context.Context, page, perP
 	}, nil
 }
 
+// Beta whitelist export formats
+const (
+	WhitelistExportFormatCSV  = "csv"
+	WhitelistExportFormatJSON = "json"
+)
+
+var betaWhitelistCSVHeader = []string{"Telefone", "ID do grupo", "Grupo", "Adicionado em"}
+
+// ExportWhitelistedPhones streams every whitelisted phone, optionally only those of groupID, to w
+// as CSV or as a JSON array, writing each entry as it is read from MongoDB. The cursor walks the
+// beta_group_id_1 index, so the whitelist is never loaded at once. Phones are masked unless
+// maskPhone is false. It returns the number of exported entries.
+func (s *BetaGroupService) ExportWhitelistedPhones(ctx context.Context, w io.Writer, groupID, format string, maskPhone bool) (int, error) {
+	filter := bson.M{"beta_group_id": bson.M{"$exists": true, "$ne": ""}}
+	if groupID != "" {
+		filter["beta_group_id"] = groupID
+	}
+
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "beta_group_id", Value: 1}}).
+			SetProjection(bson.M{"phone_number": 1, "beta_group_id": 1, "updated_at": 1, "created_at": 1}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find whitelisted phones: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	writer := newWhitelistExportWriter(w, format)
+	if err := writer.begin(); err != nil {
+		return 0, err
+	}
+
+	// Entries come grouped by group, so each group name is looked up once
+	groupNames := make(map[string]string)
+	exported := 0
+	for cursor.Next(ctx) {
+		var rawDoc bson.M
+		if err := cursor.Decode(&rawDoc); err != nil {
+			s.logger.Warn("failed to decode raw document in whitelist export", zap.Error(err))
+			continue
+		}
+		entry, ok := s.decodeWhitelistEntry(rawDoc)
+		if !ok {
+			continue
+		}
+
+		groupName, found := groupNames[entry.GroupID]
+		if !found {
+			if group, err := s.GetGroup(ctx, entry.GroupID); err == nil {
+				groupName = group.Name
+			}
+			groupNames[entry.GroupID] = groupName
+		}
+		entry.GroupName = groupName
+		if maskPhone {
+			entry.PhoneNumber = utils.MaskPhone(entry.PhoneNumber)
+		}
+
+		if err := writer.write(entry); err != nil {
+			return exported, err
+		}
+		exported++
+	}
+	if err := cursor.Err(); err != nil {
+		return exported, fmt.Errorf("failed to read whitelisted phones: %w", err)
+	}
+
+	return exported, writer.end()
+}
+
+// whitelistExportWriter writes beta whitelist entries in an export format
+type whitelistExportWriter struct {
+	format  string
+	w       io.Writer
+	csv     *csv.Writer
+	entries int
+}
+
+func newWhitelistExportWriter(w io.Writer, format string) *whitelistExportWriter {
+	writer := &whitelistExportWriter{format: format, w: w}
+	if format == WhitelistExportFormatCSV {
+		writer.csv = csv.NewWriter(w)
+	}
+	return writer
+}
+
+// begin writes the CSV header or opens the JSON array
+func (e *whitelistExportWriter) begin() error {
+	if e.csv != nil {
+		if err := e.csv.Write(betaWhitelistCSVHeader); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		return nil
+	}
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+// write writes one entry as a CSV record or a JSON array element
+func (e *whitelistExportWriter) write(entry models.BetaWhitelistResponse) error {
+	if e.csv != nil {
+		addedAt := ""
+		if !entry.AddedAt.IsZero() {
+			addedAt = entry.AddedAt.In(saoPauloLocation()).Format("02/01/2006 15:04:05")
+		}
+		if err := e.csv.Write([]string{entry.PhoneNumber, entry.GroupID, entry.GroupName, addedAt}); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal whitelist entry: %w", err)
+	}
+	if e.entries > 0 {
+		data = append([]byte(","), data...)
+	}
+	e.entries++
+	_, err = e.w.Write(data)
+	return err
+}
+
+// end flushes the CSV or closes the JSON array
+func (e *whitelistExportWriter) end() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+// decodeWhitelistEntry reads a whitelisted phone mapping, skipping (and logging) malformed
+// entries. The group name is left for the caller to fill in.
+func (s *BetaGroupService) decodeWhitelistEntry(rawDoc bson.M) (models.BetaWhitelistResponse, bool) {
+	// Extract phone number (required field)
+	phoneNumber, ok := rawDoc["phone_number"].(string)
+	if !ok {
+		s.logger.Warn("phone mapping has non-string phone_number field",
+			zap.String("collection", config.AppConfig.PhoneMappingCollection),
+			zap.Any("phone_number_value", rawDoc["phone_number"]),
+			zap.String("phone_number_type", fmt.Sprintf("%T", rawDoc["phone_number"])),
+			zap.Any("beta_group_id", rawDoc["beta_group_id"]))
+		return models.BetaWhitelistResponse{}, false
+	}
+	if phoneNumber == "" {
+		s.logger.Warn("phone mapping has empty phone_number",
+			zap.String("collection", config.AppConfig.PhoneMappingCollection),
+			zap.Any("beta_group_id", rawDoc["beta_group_id"]))
+		return models.BetaWhitelistResponse{}, false
+	}
+
+	// Extract beta_group_id (handle both string and ObjectID types)
+	var betaGroupID string
+	switch v := rawDoc["beta_group_id"].(type) {
+	case string:
+		betaGroupID = v
+	case primitive.ObjectID:
+		betaGroupID = v.Hex()
+	default:
+		s.logger.Warn("phone mapping has invalid beta_group_id type",
+			zap.String("phone_number", phoneNumber),
+			zap.String("collection", config.AppConfig.PhoneMappingCollection),
+			zap.Any("beta_group_id", rawDoc["beta_group_id"]))
+		return models.BetaWhitelistResponse{}, false
+	}
+
+	if betaGroupID == "" {
+		s.logger.Warn("phone mapping has empty beta_group_id",
+			zap.String("phone_number", phoneNumber),
+			zap.String("collection", config.AppConfig.PhoneMappingCollection))
+		return models.BetaWhitelistResponse{}, false
+	}
+
+	// Extract updated_at timestamp with fallback to created_at
+	var addedAt time.Time
+	if updatedAt, ok := rawDoc["updated_at"].(primitive.DateTime); ok {
+		addedAt = updatedAt.Time()
+	} else if createdAt, ok := rawDoc["created_at"].(primitive.DateTime); ok {
+		addedAt = createdAt.Time()
+	}
+
+	return models.BetaWhitelistResponse{
+		PhoneNumber: phoneNumber,
+		GroupID:     betaGroupID,
+		AddedAt:     addedAt,
+	}, true
+}
+
 // BulkAddToWhitelist adds multiple phone numbers to a beta group
 func (s *BetaGroupService) BulkAddToWhitelist(ctx context.Context, phoneNumbers []string, groupID string) ([]models.BetaWhitelistResponse, error) {
 	// Validate group ID
@@ -594,6 +742,10 @@ func (s *BetaGroupService) BulkAddToWhitelist(ctx context.Context, phoneNumbers
 		})
 	}
 
+	if len(results) > 0 {
+		s.invalidateGroupStats(ctx, groupID)
+	}
+
 	return results, nil
 }
 
@@ -601,26 +753,36 @@ func (s *BetaGroupService) BulkAddToWhitelist(ctx context.Context, phoneNumbers
 func (s *BetaGroupService) BulkRemoveFromWhitelist(ctx context.Context, phoneNumbers []string) error {
 	phoneCollection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
 	now := time.Now()
+	affectedGroups := make(map[string]bool)
 
 	for _, phoneNumber := range phoneNumbers {
 		storagePhone := strings.TrimPrefix(phoneNumber, "+")
 
-		// Remove from whitelist
-		_, err := phoneCollection.UpdateOne(ctx,
+		// Remove from whitelist, keeping the previous group to invalidate its stats
+		var previous models.PhoneCPFMapping
+		err := phoneCollection.FindOneAndUpdate(ctx,
 			bson.M{"phone_number": storagePhone},
 			bson.M{
 				"$unset": bson.M{"beta_group_id": ""},
 				"$set":   bson.M{"updated_at": now},
 			},
-		)
+			options.FindOneAndUpdate().SetProjection(bson.M{"beta_group_id": 1}),
+		).Decode(&previous)
 		if err != nil {
 			continue // Skip on error
 		}
+		affectedGroups[previous.BetaGroupID] = true
 
 		// Invalidate cache for this phone
 		s.invalidateBetaStatusCacheForPhone(ctx, storagePhone)
 	}
 
+	groupIDs := make([]string, 0, len(affectedGroups))
+	for groupID := range affectedGroups {
+		groupIDs = append(groupIDs, groupID)
+	}
+	s.invalidateGroupStats(ctx, groupIDs...)
+
 	return nil
 }
 
@@ -656,6 +818,9 @@ func (s *BetaGroupService) BulkMoveWhitelist(ctx context.Context, phoneNumbers [
 		return fmt.Errorf("failed to get to group: %w", err)
 	}
 
+	// Both groups' stats change whichever path performs the move
+	defer s.invalidateGroupStats(ctx, fromGroupID, toGroupID)
+
 	// Use batch operations for better performance
 	if err := s.bulkMoveWhitelistBatch(ctx, phoneNumbers, fromGroupID, toGroupID); err != nil {
 		s.logger.Warn("batch move operation failed, falling back to individual operations", zap.Error(err))
@@ -747,6 +912,7 @@ func (s *BetaGroupService) invalidateBetaStatusCacheBatch(ctx context.Context, p
 		storagePhone := strings.TrimPrefix(phoneNumber, "+")
 		cacheKey := fmt.Sprintf("beta_status:%s", storagePhone)
 		pipe.Del(ctx, cacheKey)
+		pipe.Del(ctx, phoneStatusDetailsCacheKey(storagePhone))
 	}
 
 	// Execute pipeline
@@ -778,4 +944,5 @@ func (s *BetaGroupService) invalidateBetaStatusCache(ctx context.Context, groupI
 func (s *BetaGroupService) invalidateBetaStatusCacheForPhone(ctx context.Context, phoneNumber string) {
 	cacheKey := fmt.Sprintf("beta_status:%s", phoneNumber)
 	config.Redis.Del(ctx, cacheKey)
+	config.Redis.Del(ctx, phoneStatusDetailsCacheKey(phoneNumber))
 }
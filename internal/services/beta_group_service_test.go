@@ -1,9 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
@@ -527,3 +531,182 @@ func TestListWhitelistedPhones(t *testing.T) {
 		t.Errorf("ListWhitelistedPhones() TotalCount = %d, want 3", phones.TotalCount)
 	}
 }
+
+func TestExportWhitelistedPhones(t *testing.T) {
+	service, cleanup := setupBetaGroupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	group, _ := service.CreateGroup(ctx, "Whitelist Export Test")
+	_, _ = service.AddToWhitelist(ctx, "+5521987654444", group.ID)
+	_, _ = service.AddToWhitelist(ctx, "+5521987655555", group.ID)
+
+	var buf bytes.Buffer
+	exported, err := service.ExportWhitelistedPhones(ctx, &buf, group.ID, WhitelistExportFormatJSON, true)
+	if err != nil {
+		t.Fatalf("ExportWhitelistedPhones() error = %v, want nil", err)
+	}
+	if exported != 2 {
+		t.Errorf("ExportWhitelistedPhones() exported = %d, want 2", exported)
+	}
+
+	var entries []models.BetaWhitelistResponse
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("ExportWhitelistedPhones() produced invalid JSON: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.GroupName != "Whitelist Export Test" {
+			t.Errorf("ExportWhitelistedPhones() GroupName = %q, want %q", entry.GroupName, "Whitelist Export Test")
+		}
+		if strings.Contains(entry.PhoneNumber, "987654444") || strings.Contains(entry.PhoneNumber, "987655555") {
+			t.Errorf("ExportWhitelistedPhones() PhoneNumber = %q, want masked", entry.PhoneNumber)
+		}
+	}
+}
+
+func TestWhitelistExportWriter(t *testing.T) {
+	addedAt := time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)
+	entries := []models.BetaWhitelistResponse{
+		{PhoneNumber: "+5521987651111", GroupID: "g1", GroupName: "Grupo, A", AddedAt: addedAt},
+		{PhoneNumber: "+5521987652222", GroupID: "g2", GroupName: "Grupo B"},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		entries []models.BetaWhitelistResponse
+		want    string
+	}{
+		{
+			name:    "csv",
+			format:  WhitelistExportFormatCSV,
+			entries: entries,
+			want: "Telefone,ID do grupo,Grupo,Adicionado em\n" +
+				"+5521987651111,g1,\"Grupo, A\",10/03/2024 12:04:05\n" +
+				"+5521987652222,g2,Grupo B,\n",
+		},
+		{
+			name:   "empty csv",
+			format: WhitelistExportFormatCSV,
+			want:   "Telefone,ID do grupo,Grupo,Adicionado em\n",
+		},
+		{
+			name:   "empty json",
+			format: WhitelistExportFormatJSON,
+			want:   "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := newWhitelistExportWriter(&buf, tt.format)
+			if err := writer.begin(); err != nil {
+				t.Fatalf("begin() error = %v", err)
+			}
+			for _, entry := range tt.entries {
+				if err := writer.write(entry); err != nil {
+					t.Fatalf("write() error = %v", err)
+				}
+			}
+			if err := writer.end(); err != nil {
+				t.Fatalf("end() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("export = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := newWhitelistExportWriter(&buf, WhitelistExportFormatJSON)
+		_ = writer.begin()
+		for _, entry := range entries {
+			_ = writer.write(entry)
+		}
+		_ = writer.end()
+
+		var decoded []models.BetaWhitelistResponse
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("export is not a JSON array: %v (%s)", err, buf.String())
+		}
+		if len(decoded) != 2 || decoded[1].PhoneNumber != "+5521987652222" {
+			t.Errorf("decoded = %+v, want both entries", decoded)
+		}
+	})
+}
+
+func TestGetGroupStats(t *testing.T) {
+	service, cleanup := setupBetaGroupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	originalHistoryCollection := config.AppConfig.OptInHistoryCollection
+	originalCacheTTL := config.AppConfig.BetaGroupStatsCacheTTL
+	originalRecentWindow := config.AppConfig.BetaGroupStatsRecentWindow
+	config.AppConfig.OptInHistoryCollection = "test_beta_stats_opt_in_history"
+	config.AppConfig.BetaGroupStatsCacheTTL = time.Minute
+	config.AppConfig.BetaGroupStatsRecentWindow = 24 * time.Hour
+	defer func() {
+		_ = config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).Drop(ctx)
+		config.AppConfig.OptInHistoryCollection = originalHistoryCollection
+		config.AppConfig.BetaGroupStatsCacheTTL = originalCacheTTL
+		config.AppConfig.BetaGroupStatsRecentWindow = originalRecentWindow
+	}()
+
+	group, _ := service.CreateGroup(ctx, "Stats Test")
+	defer service.invalidateGroupStats(ctx, group.ID)
+	_, _ = service.AddToWhitelist(ctx, "+5521987656666", group.ID)
+	_, _ = service.AddToWhitelist(ctx, "+5521987657777", group.ID)
+
+	// Bind the first phone to an opted-in CPF
+	_, _ = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).UpdateOne(ctx,
+		bson.M{"phone_number": "5521987656666"},
+		bson.M{"$set": bson.M{"cpf": "11144477735", "opt_in": true}},
+	)
+	_, _ = config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).InsertMany(ctx, []interface{}{
+		models.OptInHistory{PhoneNumber: "5521987656666", CPF: "11144477735", Action: models.OptInActionOptIn, Timestamp: time.Now()},
+		models.OptInHistory{PhoneNumber: "5521987657777", Action: models.OptInActionOptOut, Timestamp: time.Now()},
+		models.OptInHistory{PhoneNumber: "5521987657777", Action: models.OptInActionOptIn, Timestamp: time.Now().Add(-48 * time.Hour)},
+	})
+
+	stats, err := service.GetGroupStats(ctx, group.ID)
+	if err != nil {
+		t.Fatalf("GetGroupStats() error = %v, want nil", err)
+	}
+	if stats.WhitelistedCount != 2 || stats.BoundCPFCount != 1 || stats.OptedInCount != 1 {
+		t.Errorf("GetGroupStats() counts = %d/%d/%d, want 2/1/1", stats.WhitelistedCount, stats.BoundCPFCount, stats.OptedInCount)
+	}
+	if stats.OptInRate != 0.5 {
+		t.Errorf("GetGroupStats() OptInRate = %v, want 0.5", stats.OptInRate)
+	}
+	if stats.RecentAdditions != 2 {
+		t.Errorf("GetGroupStats() RecentAdditions = %d, want 2", stats.RecentAdditions)
+	}
+	if stats.RecentOptIns != 1 || stats.RecentOptOuts != 1 {
+		t.Errorf("GetGroupStats() recent opt-ins/outs = %d/%d, want 1/1", stats.RecentOptIns, stats.RecentOptOuts)
+	}
+
+	// Bulk removal invalidates the cached stats
+	_ = service.BulkRemoveFromWhitelist(ctx, []string{"+5521987657777"})
+	stats, err = service.GetGroupStats(ctx, group.ID)
+	if err != nil {
+		t.Fatalf("GetGroupStats() after removal error = %v, want nil", err)
+	}
+	if stats.WhitelistedCount != 1 {
+		t.Errorf("GetGroupStats() after removal WhitelistedCount = %d, want 1", stats.WhitelistedCount)
+	}
+}
+
+func TestGetGroupStats_NotFound(t *testing.T) {
+	service, cleanup := setupBetaGroupTest(t)
+	defer cleanup()
+
+	_, err := service.GetGroupStats(context.Background(), "507f1f77bcf86cd799439011")
+	if err != models.ErrGroupNotFound {
+		t.Errorf("GetGroupStats() error = %v, want ErrGroupNotFound", err)
+	}
+}
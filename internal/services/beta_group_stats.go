@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// betaGroupStatsCacheKey returns the Redis key of a group's cached statistics
+func betaGroupStatsCacheKey(groupID string) string {
+	return fmt.Sprintf("beta_group_stats:%s", groupID)
+}
+
+// GetGroupStats returns the membership statistics of a beta group: whitelisted phones, how many
+// are bound to a CPF or opted in, and the additions and opt-in activity within
+// BETA_GROUP_STATS_RECENT_WINDOW. Statistics are cached for BETA_GROUP_STATS_CACHE_TTL and
+// invalidated whenever the group's whitelist changes.
+func (s *BetaGroupService) GetGroupStats(ctx context.Context, groupID string) (*models.BetaGroupStats, error) {
+	group, err := s.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := betaGroupStatsCacheKey(groupID)
+	if cachedValue, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil && cachedValue != "" {
+		var stats models.BetaGroupStats
+		if err := json.Unmarshal([]byte(cachedValue), &stats); err == nil {
+			// The group may have been renamed since the stats were computed
+			stats.GroupName = group.Name
+			return &stats, nil
+		}
+	}
+
+	now := time.Now()
+	stats := &models.BetaGroupStats{
+		GroupID:     groupID,
+		GroupName:   group.Name,
+		RecentSince: now.Add(-config.AppConfig.BetaGroupStatsRecentWindow),
+		ComputedAt:  now,
+	}
+	if err := s.countGroupMembers(ctx, stats); err != nil {
+		return nil, err
+	}
+	if err := s.countGroupOptInActivity(ctx, stats); err != nil {
+		return nil, err
+	}
+	if stats.WhitelistedCount > 0 {
+		stats.OptInRate = float64(stats.OptedInCount) / float64(stats.WhitelistedCount)
+	}
+
+	if cacheJSON, err := json.Marshal(stats); err == nil {
+		if err := config.Redis.Set(ctx, cacheKey, string(cacheJSON), config.AppConfig.BetaGroupStatsCacheTTL).Err(); err != nil {
+			s.logger.Warn("failed to cache beta group stats", zap.String("group_id", groupID), zap.Error(err))
+		}
+	}
+
+	return stats, nil
+}
+
+// countGroupMembers fills the phone mapping counts of stats in a single pass over the group's
+// phones. Recent additions use the same added-at as the whitelist listing (updated_at, falling
+// back to created_at).
+func (s *BetaGroupService) countGroupMembers(ctx context.Context, stats *models.BetaGroupStats) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"beta_group_id": stats.GroupID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         nil,
+			"whitelisted": bson.M{"$sum": 1},
+			"bound_cpf": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$type": "$cpf"}, "string"}},
+					bson.M{"$ne": bson.A{"$cpf", ""}},
+				}},
+				1, 0,
+			}}},
+			"opted_in": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$opt_in", true}}, 1, 0,
+			}}},
+			"recent": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{bson.M{"$ifNull": bson.A{"$updated_at", "$created_at"}}, stats.RecentSince}},
+				1, 0,
+			}}},
+		}}},
+	}
+
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate beta group members: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	// An empty group yields no document and keeps the zero counts
+	if cursor.Next(ctx) {
+		var result struct {
+			Whitelisted int64 `bson:"whitelisted"`
+			BoundCPF    int64 `bson:"bound_cpf"`
+			OptedIn     int64 `bson:"opted_in"`
+			Recent      int64 `bson:"recent"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return fmt.Errorf("failed to decode beta group members: %w", err)
+		}
+		stats.WhitelistedCount = result.Whitelisted
+		stats.BoundCPFCount = result.BoundCPF
+		stats.OptedInCount = result.OptedIn
+		stats.RecentAdditions = result.Recent
+	}
+	return cursor.Err()
+}
+
+// countGroupOptInActivity fills the recent opt-ins and opt-outs of stats from the opt-in
+// history of the group's phones
+func (s *BetaGroupService) countGroupOptInActivity(ctx context.Context, stats *models.BetaGroupStats) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"beta_group_id": stats.GroupID}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "phone_number": 1}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": config.AppConfig.OptInHistoryCollection,
+			"let":  bson.M{"phone": "$phone_number"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"$expr":     bson.M{"$eq": bson.A{"$phone_number", "$$phone"}},
+					"action":    bson.M{"$in": bson.A{models.OptInActionOptIn, models.OptInActionOptOut}},
+					"timestamp": bson.M{"$gte": stats.RecentSince},
+				}},
+				bson.M{"$project": bson.M{"_id": 0, "action": 1}},
+			},
+			"as": "history",
+		}}},
+		{{Key: "$unwind", Value: "$history"}},
+		{{Key: "$group", Value: bson.M{"_id": "$history.action", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate beta group opt-in history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var result struct {
+			Action string `bson:"_id"`
+			Count  int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return fmt.Errorf("failed to decode beta group opt-in history: %w", err)
+		}
+		switch result.Action {
+		case models.OptInActionOptIn:
+			stats.RecentOptIns = result.Count
+		case models.OptInActionOptOut:
+			stats.RecentOptOuts = result.Count
+		}
+	}
+	return cursor.Err()
+}
+
+// invalidateGroupStats drops the cached statistics of the given groups
+func (s *BetaGroupService) invalidateGroupStats(ctx context.Context, groupIDs ...string) {
+	keys := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		if groupID != "" {
+			keys = append(keys, betaGroupStatsCacheKey(groupID))
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := config.Redis.Del(ctx, keys...).Err(); err != nil {
+		s.logger.Warn("failed to invalidate beta group stats cache", zap.Strings("group_ids", groupIDs), zap.Error(err))
+	}
+}
@@ -3,11 +3,17 @@ package services
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 )
 
 // CacheService provides a unified interface for all cache operations
@@ -24,118 +30,177 @@ func NewCacheService() *CacheService {
 	}
 }
 
+// Self-declared updates are versioned per citizen. When expectedVersion is set the write only
+// happens if it matches the current version (see GetSelfDeclaredVersion), otherwise an
+// utils.OptimisticLockError is returned. The new version is returned on success.
+//
+// Writes go through the DataManager write buffer unless they are critical: fields listed in
+// CRITICAL_SELF_DECLARED_FIELDS, or any update whose context carries the "critical" operation
+// type (see WithWriteOperationType), are written synchronously to MongoDB with the write concern
+// from utils.GetWriteConcernForOperation.
+
 // UpdateSelfDeclaredAddress updates self-declared address via cache system
-func (s *CacheService) UpdateSelfDeclaredAddress(ctx context.Context, cpf string, endereco *models.Endereco) error {
-	// Create a citizen update operation
+func (s *CacheService) UpdateSelfDeclaredAddress(ctx context.Context, cpf string, endereco *models.Endereco, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredAddressDataOperation{
 		CPF:       cpf,
 		Endereco:  endereco,
+		Version:   version,
 		UpdatedAt: time.Now(),
 	}
 
-	// Use the data manager to write to cache and queue for sync
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredEmail updates self-declared email via cache system
-func (s *CacheService) UpdateSelfDeclaredEmail(ctx context.Context, cpf string, email *models.Email) error {
+func (s *CacheService) UpdateSelfDeclaredEmail(ctx context.Context, cpf string, email *models.Email, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredEmailDataOperation{
 		CPF:       cpf,
 		Email:     email,
+		Version:   version,
 		UpdatedAt: time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredPhone updates self-declared phone via cache system
-func (s *CacheService) UpdateSelfDeclaredPhone(ctx context.Context, cpf string, telefone *models.Telefone) error {
+func (s *CacheService) UpdateSelfDeclaredPhone(ctx context.Context, cpf string, telefone *models.Telefone, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredPhoneDataOperation{
 		CPF:       cpf,
 		Telefone:  telefone,
+		Version:   version,
 		UpdatedAt: time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredRaca updates self-declared ethnicity via cache system
-func (s *CacheService) UpdateSelfDeclaredRaca(ctx context.Context, cpf string, raca string) error {
+func (s *CacheService) UpdateSelfDeclaredRaca(ctx context.Context, cpf string, raca string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredRacaDataOperation{
 		CPF:       cpf,
 		Raca:      raca,
+		Version:   version,
 		UpdatedAt: time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredNomeExibicao updates self-declared exhibition name via cache system
-func (s *CacheService) UpdateSelfDeclaredNomeExibicao(ctx context.Context, cpf string, nomeExibicao string) error {
+func (s *CacheService) UpdateSelfDeclaredNomeExibicao(ctx context.Context, cpf string, nomeExibicao string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredNomeExibicaoDataOperation{
 		CPF:          cpf,
 		NomeExibicao: nomeExibicao,
+		Version:      version,
 		UpdatedAt:    time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredGenero updates self-declared gender via cache system
-func (s *CacheService) UpdateSelfDeclaredGenero(ctx context.Context, cpf string, genero string) error {
+func (s *CacheService) UpdateSelfDeclaredGenero(ctx context.Context, cpf string, genero string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredGeneroDataOperation{
 		CPF:       cpf,
 		Genero:    genero,
+		Version:   version,
 		UpdatedAt: time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredRendaFamiliar updates self-declared family income via cache system
-func (s *CacheService) UpdateSelfDeclaredRendaFamiliar(ctx context.Context, cpf string, rendaFamiliar string) error {
+func (s *CacheService) UpdateSelfDeclaredRendaFamiliar(ctx context.Context, cpf string, rendaFamiliar string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredRendaFamiliarDataOperation{
 		CPF:           cpf,
 		RendaFamiliar: rendaFamiliar,
+		Version:       version,
 		UpdatedAt:     time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
-// UpdateSelfDeclaredEscolaridade updates self-declared education level via cache system
-func (s *CacheService) UpdateSelfDeclaredEscolaridade(ctx context.Context, cpf string, escolaridade string) error {
+// UpdateSelfDeclaredEscolaridade updates self-declared education via cache system
+func (s *CacheService) UpdateSelfDeclaredEscolaridade(ctx context.Context, cpf string, escolaridade string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredEscolaridadeDataOperation{
 		CPF:          cpf,
 		Escolaridade: escolaridade,
+		Version:      version,
 		UpdatedAt:    time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
 // UpdateSelfDeclaredDeficiencia updates self-declared disability via cache system
-func (s *CacheService) UpdateSelfDeclaredDeficiencia(ctx context.Context, cpf string, deficiencia string) error {
+func (s *CacheService) UpdateSelfDeclaredDeficiencia(ctx context.Context, cpf string, deficiencia string, expectedVersion *int32) (int32, error) {
+	version, err := nextSelfDeclaredVersion(ctx, cpf, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
 	op := &SelfDeclaredDeficienciaDataOperation{
 		CPF:         cpf,
 		Deficiencia: deficiencia,
+		Version:     version,
 		UpdatedAt:   time.Now(),
 	}
 
-	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
-	return dataManager.Write(ctx, op)
+	return version, s.writeSelfDeclared(ctx, op, version)
 }
 
-// UpdateUserConfig updates user configuration via cache system
+// UpdateUserConfig updates user configuration via cache system.
+// In field write mode the document fields are written with a targeted $set upsert instead of
+// replacing the whole document through the write buffer.
 func (s *CacheService) UpdateUserConfig(ctx context.Context, userID string, userConfig *models.UserConfig) error {
+	userConfig.Touch(time.Now())
+	if config.AppConfig.UserConfigWriteMode != config.UserConfigWriteModeDocument {
+		return s.setUserConfigFields(ctx, userID, userConfigFields(userConfig))
+	}
+
 	op := &UserConfigDataOperation{
 		UserID: userID,
 		Data:   userConfig,
@@ -145,6 +210,160 @@ func (s *CacheService) UpdateUserConfig(ctx context.Context, userID string, user
 	return dataManager.Write(ctx, op)
 }
 
+// PatchUserConfig updates only the given user config fields (keyed by their bson names, e.g. "first_login").
+// Concurrent patches to different fields of the same user both persist in field write mode.
+func (s *CacheService) PatchUserConfig(ctx context.Context, userID string, fields bson.M) error {
+	if config.AppConfig.UserConfigWriteMode != config.UserConfigWriteModeDocument {
+		return s.setUserConfigFields(ctx, userID, fields)
+	}
+
+	// Document mode: read-modify-write the whole document (last write wins)
+	var userConfig models.UserConfig
+	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
+	err := dataManager.Read(ctx, userID, config.AppConfig.UserConfigCollection, "user_config", &userConfig)
+	if err != nil && err != ErrDocumentNotFound {
+		return fmt.Errorf("failed to read user config: %w", err)
+	}
+	if err == ErrDocumentNotFound {
+		userConfig = models.UserConfig{CPF: userID, FirstLogin: true, OptIn: true}
+	}
+
+	if err := applyUserConfigFields(&userConfig, fields); err != nil {
+		return err
+	}
+
+	return s.UpdateUserConfig(ctx, userID, &userConfig)
+}
+
+// setUserConfigFields writes the given fields with a targeted $set upsert and drops the
+// buffered/cached copies so subsequent reads see the merged document. Nil fields are removed
+// with $unset, the same way a whole-document write omits them.
+func (s *CacheService) setUserConfigFields(ctx context.Context, userID string, fields bson.M) error {
+	update := utils.TimestampedUpdate(fields, time.Now())
+	set := update["$set"].(bson.M)
+	delete(set, "cpf")
+
+	unset := bson.M{}
+	for field, value := range set {
+		if isNilField(value) {
+			unset[field] = ""
+			delete(set, field)
+		}
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	// Defaults for a brand-new document, skipping fields being written in this update
+	setOnInsert := update["$setOnInsert"].(bson.M)
+	setOnInsert["cpf"] = userID
+	for field, value := range userConfigInsertDefaults {
+		_, inSet := set[field]
+		_, inUnset := unset[field]
+		if !inSet && !inUnset {
+			setOnInsert[field] = value
+		}
+	}
+
+	filter := bson.M{"cpf": userID}
+	collection := config.MongoDB.Collection(config.AppConfig.UserConfigCollection)
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Two concurrent upserts raced to insert the document; the retry matches the winner and updates it
+		_, err = collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update user config fields: %w", err)
+	}
+
+	writeKey := fmt.Sprintf("user_config:write:%s", userID)
+	cacheKey := fmt.Sprintf("user_config:cache:%s", userID)
+	if err := config.Redis.Del(ctx, writeKey, cacheKey).Err(); err != nil {
+		s.logger.Warn("failed to invalidate user config cache after field update",
+			zap.String("user_id", userID),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+// userConfigInsertDefaults are the values a user config document starts with when first created
+var userConfigInsertDefaults = bson.M{
+	"first_login": true,
+	"opt_in":      true,
+}
+
+// isNilField reports whether a field value is nil, including typed nil pointers and maps such
+// as an unset *string
+func isNilField(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// userConfigFields converts a user config into the fields written by a targeted update,
+// mirroring the fields a whole-document write would set. Optional fields that are nil are
+// returned as nil so the update removes them, e.g. when the avatar is cleared.
+func userConfigFields(userConfig *models.UserConfig) bson.M {
+	fields := bson.M{
+		"first_login":       userConfig.FirstLogin,
+		"opt_in":            userConfig.OptIn,
+		"category_opt_ins":  nil,
+		"avatar_id":         nil,
+		"opt_out_reason":    nil,
+		"opt_out_note":      nil,
+		"preferred_channel": nil,
+	}
+	if userConfig.CategoryOptIns != nil {
+		fields["category_opt_ins"] = userConfig.CategoryOptIns
+	}
+	if userConfig.AvatarID != nil {
+		fields["avatar_id"] = *userConfig.AvatarID
+	}
+	if userConfig.OptOutReason != nil {
+		fields["opt_out_reason"] = *userConfig.OptOutReason
+	}
+	if userConfig.OptOutNote != nil {
+		fields["opt_out_note"] = *userConfig.OptOutNote
+	}
+	if userConfig.PreferredChannel != nil {
+		fields["preferred_channel"] = *userConfig.PreferredChannel
+	}
+	if userConfig.Version != 0 {
+		fields["version"] = userConfig.Version
+	}
+	return fields
+}
+
+// applyUserConfigFields overlays the given bson fields onto a user config
+func applyUserConfigFields(userConfig *models.UserConfig, fields bson.M) error {
+	data, err := bson.Marshal(userConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user config: %w", err)
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal user config: %w", err)
+	}
+	for field, value := range fields {
+		doc[field] = value
+	}
+
+	data, err = bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal patched user config: %w", err)
+	}
+	return bson.Unmarshal(data, userConfig)
+}
+
 // UpdateOptInHistory updates opt-in history via cache system
 func (s *CacheService) UpdateOptInHistory(ctx context.Context, id string, optInHistory *models.OptInHistory) error {
 	op := &OptInHistoryDataOperation{
@@ -220,6 +439,35 @@ func (s *CacheService) DeleteCitizen(ctx context.Context, cpf string) error {
 	return s.citizenService.DeleteCitizen(ctx, cpf)
 }
 
+// selfDeclaredDataTypes lists the DataManager types holding self-declared fields
+var selfDeclaredDataTypes = []string{
+	"self_declared_address",
+	"self_declared_email",
+	"self_declared_phone",
+	"self_declared_raca",
+	"self_declared_nome_exibicao",
+	"self_declared_genero",
+	"self_declared_renda_familiar",
+	"self_declared_escolaridade",
+	"self_declared_deficiencia",
+}
+
+// InvalidateSelfDeclared removes the merged citizen cache and the self-declared read caches of
+// a CPF in a single pipeline. Each key gets its own DEL so the keys never need to share a hash
+// slot: in cluster mode the pipeline groups the commands per node.
+func (s *CacheService) InvalidateSelfDeclared(ctx context.Context, cpf string) error {
+	pipe := config.Redis.Pipeline()
+	pipe.Del(ctx, fmt.Sprintf("citizen:%s", cpf))
+	for _, dataType := range selfDeclaredDataTypes {
+		pipe.Del(ctx, fmt.Sprintf("%s:cache:%s", dataType, cpf))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to invalidate self-declared cache: %w", err)
+	}
+	return nil
+}
+
 // GetQueueDepth returns the current depth of a sync queue
 func (s *CacheService) GetQueueDepth(ctx context.Context, queueType string) (int64, error) {
 	queueKey := fmt.Sprintf("sync:queue:%s", queueType)
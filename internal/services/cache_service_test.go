@@ -3,12 +3,14 @@ package services
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // setupCacheServiceTest initializes test environment and returns service with cleanup function
@@ -89,7 +91,7 @@ func TestUpdateSelfDeclaredAddress(t *testing.T) {
 
 	endereco := &models.Endereco{}
 
-	err := service.UpdateSelfDeclaredAddress(ctx, "03561350712", endereco)
+	_, err := service.UpdateSelfDeclaredAddress(ctx, "03561350712", endereco, nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredAddress() error = %v", err)
 	}
@@ -104,7 +106,7 @@ func TestUpdateSelfDeclaredEmail(t *testing.T) {
 
 	emailData := &models.Email{}
 
-	err := service.UpdateSelfDeclaredEmail(ctx, "03561350712", emailData)
+	_, err := service.UpdateSelfDeclaredEmail(ctx, "03561350712", emailData, nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredEmail() error = %v", err)
 	}
@@ -118,7 +120,7 @@ func TestUpdateSelfDeclaredPhone(t *testing.T) {
 
 	telefone := &models.Telefone{}
 
-	err := service.UpdateSelfDeclaredPhone(ctx, "03561350712", telefone)
+	_, err := service.UpdateSelfDeclaredPhone(ctx, "03561350712", telefone, nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredPhone() error = %v", err)
 	}
@@ -130,7 +132,7 @@ func TestUpdateSelfDeclaredRaca(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredRaca(ctx, "03561350712", "Branca")
+	_, err := service.UpdateSelfDeclaredRaca(ctx, "03561350712", "Branca", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredRaca() error = %v", err)
 	}
@@ -142,7 +144,7 @@ func TestUpdateSelfDeclaredNomeExibicao(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredNomeExibicao(ctx, "03561350712", "João Silva")
+	_, err := service.UpdateSelfDeclaredNomeExibicao(ctx, "03561350712", "João Silva", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredNomeExibicao() error = %v", err)
 	}
@@ -154,7 +156,7 @@ func TestUpdateSelfDeclaredGenero(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredGenero(ctx, "03561350712", "Masculino")
+	_, err := service.UpdateSelfDeclaredGenero(ctx, "03561350712", "Masculino", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredGenero() error = %v", err)
 	}
@@ -166,7 +168,7 @@ func TestUpdateSelfDeclaredRendaFamiliar(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredRendaFamiliar(ctx, "03561350712", "2 a 4 salários mínimos")
+	_, err := service.UpdateSelfDeclaredRendaFamiliar(ctx, "03561350712", "2 a 4 salários mínimos", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredRendaFamiliar() error = %v", err)
 	}
@@ -178,7 +180,7 @@ func TestUpdateSelfDeclaredEscolaridade(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredEscolaridade(ctx, "03561350712", "Ensino Superior Completo")
+	_, err := service.UpdateSelfDeclaredEscolaridade(ctx, "03561350712", "Ensino Superior Completo", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredEscolaridade() error = %v", err)
 	}
@@ -190,7 +192,7 @@ func TestUpdateSelfDeclaredDeficiencia(t *testing.T) {
 
 	ctx := context.Background()
 
-	err := service.UpdateSelfDeclaredDeficiencia(ctx, "03561350712", "Nenhuma")
+	_, err := service.UpdateSelfDeclaredDeficiencia(ctx, "03561350712", "Nenhuma", nil)
 	if err != nil {
 		t.Errorf("UpdateSelfDeclaredDeficiencia() error = %v", err)
 	}
@@ -350,6 +352,42 @@ func TestDeleteCitizen_Delegation(t *testing.T) {
 	}
 }
 
+func TestInvalidateSelfDeclared(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "03561350712"
+
+	keys := []string{"citizen:" + cpf, "self_declared_email:write:" + cpf}
+	for _, dataType := range selfDeclaredDataTypes {
+		keys = append(keys, dataType+":cache:"+cpf)
+	}
+	for _, key := range keys {
+		if err := config.Redis.Set(ctx, key, "cached", time.Minute).Err(); err != nil {
+			t.Fatalf("failed to seed %s: %v", key, err)
+		}
+	}
+
+	if err := service.InvalidateSelfDeclared(ctx, cpf); err != nil {
+		t.Fatalf("InvalidateSelfDeclared() error = %v", err)
+	}
+
+	for _, key := range keys[2:] {
+		if exists, _ := config.Redis.Exists(ctx, key).Result(); exists != 0 {
+			t.Errorf("InvalidateSelfDeclared() left %s in cache", key)
+		}
+	}
+	if exists, _ := config.Redis.Exists(ctx, keys[0]).Result(); exists != 0 {
+		t.Error("InvalidateSelfDeclared() left the merged citizen cache")
+	}
+
+	// Pending writes must survive so they still reach MongoDB
+	if exists, _ := config.Redis.Exists(ctx, keys[1]).Result(); exists != 1 {
+		t.Error("InvalidateSelfDeclared() should keep the write buffer")
+	}
+}
+
 func TestGetQueueDepth(t *testing.T) {
 	service, cleanup := setupCacheServiceTest(t)
 	defer cleanup()
@@ -525,3 +563,168 @@ func TestGetCacheStats_Empty(t *testing.T) {
 		}
 	}
 }
+
+func TestPatchUserConfig_ConcurrentFieldUpdates(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "52998224725"
+	previousMode := config.AppConfig.UserConfigWriteMode
+	config.AppConfig.UserConfigWriteMode = config.UserConfigWriteModeField
+	defer func() { config.AppConfig.UserConfigWriteMode = previousMode }()
+
+	collection := config.MongoDB.Collection(config.AppConfig.UserConfigCollection)
+	_, _ = collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+	defer collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+
+	// Simulate onboarding: first login and opt-in written simultaneously on a fresh user
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs <- service.PatchUserConfig(ctx, cpf, bson.M{"first_login": false})
+	}()
+	go func() {
+		defer wg.Done()
+		errs <- service.PatchUserConfig(ctx, cpf, bson.M{"opt_in": false})
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("PatchUserConfig() error = %v", err)
+		}
+	}
+
+	var stored models.UserConfig
+	if err := collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored); err != nil {
+		t.Fatalf("failed to read user config: %v", err)
+	}
+
+	if stored.FirstLogin {
+		t.Error("PatchUserConfig() first_login = true, want false to persist")
+	}
+	if stored.OptIn {
+		t.Error("PatchUserConfig() opt_in = true, want false to persist")
+	}
+}
+
+func TestPatchUserConfig_PreservesOtherFields(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "52998224725"
+	previousMode := config.AppConfig.UserConfigWriteMode
+	config.AppConfig.UserConfigWriteMode = config.UserConfigWriteModeField
+	defer func() { config.AppConfig.UserConfigWriteMode = previousMode }()
+
+	collection := config.MongoDB.Collection(config.AppConfig.UserConfigCollection)
+	_, _ = collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+	defer collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+
+	err := service.UpdateUserConfig(ctx, cpf, &models.UserConfig{
+		CPF:            cpf,
+		FirstLogin:     true,
+		OptIn:          false,
+		CategoryOptIns: map[string]bool{"saude": true},
+	})
+	if err != nil {
+		t.Fatalf("UpdateUserConfig() error = %v", err)
+	}
+
+	if err := service.PatchUserConfig(ctx, cpf, bson.M{"first_login": false}); err != nil {
+		t.Fatalf("PatchUserConfig() error = %v", err)
+	}
+
+	var stored models.UserConfig
+	if err := collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored); err != nil {
+		t.Fatalf("failed to read user config: %v", err)
+	}
+
+	if stored.FirstLogin || stored.OptIn || !stored.CategoryOptIns["saude"] {
+		t.Errorf("PatchUserConfig() stored = %+v, want only first_login changed", stored)
+	}
+}
+
+func TestUpdateUserConfig_ClearsAvatar(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "52998224725"
+	previousMode := config.AppConfig.UserConfigWriteMode
+	config.AppConfig.UserConfigWriteMode = config.UserConfigWriteModeField
+	defer func() { config.AppConfig.UserConfigWriteMode = previousMode }()
+
+	collection := config.MongoDB.Collection(config.AppConfig.UserConfigCollection)
+	_, _ = collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+	defer collection.DeleteOne(ctx, bson.M{"cpf": cpf})
+
+	avatarID := "avatar-1"
+	userConfig := &models.UserConfig{CPF: cpf, OptIn: true, AvatarID: &avatarID}
+	if err := service.UpdateUserConfig(ctx, cpf, userConfig); err != nil {
+		t.Fatalf("UpdateUserConfig() error = %v", err)
+	}
+
+	// PUT /citizen/:cpf/avatar with {"avatar_id": null}
+	userConfig.AvatarID = nil
+	if err := service.UpdateUserConfig(ctx, cpf, userConfig); err != nil {
+		t.Fatalf("UpdateUserConfig() error = %v", err)
+	}
+
+	var stored bson.M
+	if err := collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored); err != nil {
+		t.Fatalf("failed to read user config: %v", err)
+	}
+	if _, ok := stored["avatar_id"]; ok {
+		t.Errorf("UpdateUserConfig() avatar_id = %v, want it removed", stored["avatar_id"])
+	}
+	if stored["opt_in"] != true {
+		t.Errorf("UpdateUserConfig() opt_in = %v, want true", stored["opt_in"])
+	}
+}
+
+func TestUserConfigFields(t *testing.T) {
+	avatarID := "avatar-1"
+	fields := userConfigFields(&models.UserConfig{
+		CPF:        "52998224725",
+		FirstLogin: false,
+		OptIn:      true,
+		AvatarID:   &avatarID,
+	})
+
+	if fields["first_login"] != false || fields["opt_in"] != true || fields["avatar_id"] != "avatar-1" {
+		t.Errorf("userConfigFields() = %v, want first_login/opt_in/avatar_id", fields)
+	}
+	if value, ok := fields["category_opt_ins"]; !ok || value != nil {
+		t.Error("userConfigFields() should return nil category_opt_ins so it is unset")
+	}
+	if _, ok := fields["version"]; ok {
+		t.Error("userConfigFields() should omit zero version")
+	}
+	if value, ok := fields["preferred_channel"]; !ok || value != nil {
+		t.Error("userConfigFields() should return nil preferred_channel so it is unset")
+	}
+
+	channel := models.ChannelMobile
+	fields = userConfigFields(&models.UserConfig{CPF: "52998224725", PreferredChannel: &channel})
+	if fields["preferred_channel"] != models.ChannelMobile {
+		t.Errorf("userConfigFields() preferred_channel = %v, want %q", fields["preferred_channel"], models.ChannelMobile)
+	}
+}
+
+func TestApplyUserConfigFields(t *testing.T) {
+	userConfig := &models.UserConfig{CPF: "52998224725", FirstLogin: true, OptIn: true}
+
+	if err := applyUserConfigFields(userConfig, bson.M{"opt_in": false}); err != nil {
+		t.Fatalf("applyUserConfigFields() error = %v", err)
+	}
+
+	if !userConfig.FirstLogin || userConfig.OptIn || userConfig.CPF != "52998224725" {
+		t.Errorf("applyUserConfigFields() = %+v, want only opt_in changed", userConfig)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/circuitbreaker"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"go.uber.org/zap"
+)
+
+// cfCircuitInterval is the window over which the CF circuit breaker counts synchronous lookups
+const cfCircuitInterval = time.Minute
+
+// newCFCircuitBreaker creates the circuit breaker guarding synchronous MCP lookups. It trips once
+// CF_CIRCUIT_MIN_REQUESTS lookups were made in the window and at least CF_CIRCUIT_FAILURE_RATIO of
+// them failed, then lets a single probe through every CF_CIRCUIT_OPEN_TIMEOUT until one succeeds.
+func newCFCircuitBreaker(logger *logging.SafeLogger) *circuitbreaker.CircuitBreaker {
+	failureRatio, minRequests, openTimeout := 0.5, 10, 30*time.Second
+	if config.AppConfig != nil {
+		if config.AppConfig.CFCircuitFailureRatio > 0 {
+			failureRatio = config.AppConfig.CFCircuitFailureRatio
+		}
+		if config.AppConfig.CFCircuitMinRequests > 0 {
+			minRequests = config.AppConfig.CFCircuitMinRequests
+		}
+		if config.AppConfig.CFCircuitOpenTimeout > 0 {
+			openTimeout = config.AppConfig.CFCircuitOpenTimeout
+		}
+	}
+
+	observability.RMICFCircuitState.Set(float64(circuitbreaker.StateClosed))
+	return circuitbreaker.NewCircuitBreaker("cf_mcp", circuitbreaker.Settings{
+		MaxRequests: 1,
+		Interval:    cfCircuitInterval,
+		Timeout:     openTimeout,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			if counts.Requests < uint32(minRequests) {
+				return false
+			}
+			return float64(counts.TotalFailures)/float64(counts.Requests) >= failureRatio
+		},
+		OnStateChange: func(name string, from circuitbreaker.State, to circuitbreaker.State) {
+			observability.RMICFCircuitState.Set(float64(to))
+			logger.Warn("circuit breaker state changed",
+				zap.String("name", name),
+				zap.String("from", from.String()),
+				zap.String("to", to.String()))
+		},
+	}, logger.Unwrap())
+}
+
+// IsCFCircuitOpen reports whether err is the CF circuit breaker rejecting a lookup
+func IsCFCircuitOpen(err error) bool {
+	return errors.Is(err, circuitbreaker.ErrCircuitOpen) || errors.Is(err, circuitbreaker.ErrTooManyRequests)
+}
+
+// findNearestCFGuarded looks CF data up through the circuit breaker. "No equipment found" is a
+// definitive MCP answer, so it counts as a success; while the circuit is open the lookup fails
+// at once with circuitbreaker.ErrCircuitOpen without calling the MCP server.
+func (s *CFLookupService) findNearestCFGuarded(ctx context.Context, address string) (*models.HealthServicesResult, error) {
+	if s.mcpBreaker == nil {
+		return s.mcpClient.FindNearestCF(ctx, address)
+	}
+
+	var lookupErr error
+	result, err := s.mcpBreaker.Execute(ctx, func() (interface{}, error) {
+		healthData, err := s.mcpClient.FindNearestCF(ctx, address)
+		if errors.Is(err, ErrNoEquipmentFound) {
+			lookupErr = err
+			return nil, nil
+		}
+		return healthData, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	healthData, _ := result.(*models.HealthServicesResult)
+	return healthData, nil
+}
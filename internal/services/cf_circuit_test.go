@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/circuitbreaker"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupCFCircuitTest configures a CF circuit that trips after three lookups with half of them
+// failed and probes again after openTimeout
+func setupCFCircuitTest(t *testing.T, openTimeout time.Duration) {
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	ratio, minRequests, timeout := config.AppConfig.CFCircuitFailureRatio, config.AppConfig.CFCircuitMinRequests, config.AppConfig.CFCircuitOpenTimeout
+	config.AppConfig.CFCircuitFailureRatio = 0.5
+	config.AppConfig.CFCircuitMinRequests = 3
+	config.AppConfig.CFCircuitOpenTimeout = openTimeout
+	t.Cleanup(func() {
+		config.AppConfig.CFCircuitFailureRatio = ratio
+		config.AppConfig.CFCircuitMinRequests = minRequests
+		config.AppConfig.CFCircuitOpenTimeout = timeout
+	})
+}
+
+func TestFindNearestCFGuarded_OpensOnMCPFailures(t *testing.T) {
+	setupCFCircuitTest(t, 50*time.Millisecond)
+
+	var hits atomic.Int32
+	client, server := setupMCPTest(t, func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+	client.retryConfig.MaxRetries = 0
+
+	service := NewCFLookupService(nil, client, logging.GetLogger())
+	ctx := context.Background()
+
+	// Failures below CF_CIRCUIT_MIN_REQUESTS reach the MCP server
+	for i := 0; i < 3; i++ {
+		_, err := service.findNearestCFGuarded(ctx, "Rua Teste, 1")
+		assert.Error(t, err)
+		assert.False(t, IsCFCircuitOpen(err))
+	}
+	assert.Equal(t, int32(3), hits.Load())
+
+	// The circuit is now open: lookups fail fast without calling the MCP server
+	_, err := service.findNearestCFGuarded(ctx, "Rua Teste, 1")
+	assert.ErrorIs(t, err, circuitbreaker.ErrCircuitOpen)
+	assert.True(t, IsCFCircuitOpen(err))
+	assert.Equal(t, int32(3), hits.Load())
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(observability.RMICFCircuitState) == float64(circuitbreaker.StateOpen)
+	}, time.Second, 10*time.Millisecond)
+
+	// After CF_CIRCUIT_OPEN_TIMEOUT a single probe goes through and reopens the circuit on failure
+	time.Sleep(60 * time.Millisecond)
+	_, err = service.findNearestCFGuarded(ctx, "Rua Teste, 1")
+	assert.False(t, IsCFCircuitOpen(err))
+	assert.Equal(t, int32(4), hits.Load())
+
+	_, err = service.findNearestCFGuarded(ctx, "Rua Teste, 1")
+	assert.ErrorIs(t, err, circuitbreaker.ErrCircuitOpen)
+	assert.Equal(t, int32(4), hits.Load())
+}
+
+func TestFindNearestCFGuarded_NoEquipmentDoesNotTrip(t *testing.T) {
+	setupCFCircuitTest(t, time.Minute)
+
+	lookups := 0
+	client, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+
+	service := NewCFLookupService(nil, client, logging.GetLogger())
+
+	for i := 0; i < 5; i++ {
+		result, err := service.findNearestCFGuarded(context.Background(), "Rua Sem Cobertura, 1")
+		assert.ErrorIs(t, err, ErrNoEquipmentFound)
+		assert.Nil(t, result)
+	}
+	assert.Equal(t, 5, lookups)
+	assert.Equal(t, circuitbreaker.StateClosed, service.mcpBreaker.State())
+}
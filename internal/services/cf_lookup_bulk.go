@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ErrCFLookupBatchNotFound is returned when a bulk CF lookup batch is unknown or has expired
+var ErrCFLookupBatchNotFound = errors.New("CF lookup batch not found")
+
+const (
+	// CFLookupBatchIDKey is the job data key linking a cf_lookup job to its bulk batch
+	CFLookupBatchIDKey = "batch_id"
+
+	// cfLookupBatchTTL is how long the progress of a bulk batch stays available
+	cfLookupBatchTTL = 7 * 24 * time.Hour
+	// cfLookupBulkPushSize is how many jobs are pushed to the queue per Redis call
+	cfLookupBulkPushSize = 500
+	// cfLookupRateLimitRetryInterval is how long bulk jobs wait before asking the rate limiter again
+	cfLookupRateLimitRetryInterval = time.Second
+)
+
+// cfLookupBatchKey returns the Redis hash holding the progress of a bulk batch
+func cfLookupBatchKey(batchID string) string {
+	return "cf_lookup:batch:" + batchID
+}
+
+// cfLookupRegionFilter matches documents whose principal address is in the requested region,
+// ignoring case
+func cfLookupRegionFilter(request models.CFLookupBulkRequest) bson.M {
+	filter := bson.M{}
+	if bairro := strings.TrimSpace(request.Bairro); bairro != "" {
+		filter["endereco.principal.bairro"] = bson.M{"$regex": "^" + regexp.QuoteMeta(bairro) + "$", "$options": "i"}
+	}
+	if municipio := strings.TrimSpace(request.Municipio); municipio != "" {
+		filter["endereco.principal.municipio"] = bson.M{"$regex": "^" + regexp.QuoteMeta(municipio) + "$", "$options": "i"}
+	}
+	return filter
+}
+
+// cfLookupRegionMatches reports whether a principal address is in the requested region, ignoring case
+func cfLookupRegionMatches(principal *models.EnderecoPrincipal, request models.CFLookupBulkRequest) bool {
+	if principal == nil {
+		return false
+	}
+	matches := func(value *string, want string) bool {
+		want = strings.TrimSpace(want)
+		if want == "" {
+			return true
+		}
+		return value != nil && strings.EqualFold(strings.TrimSpace(*value), want)
+	}
+	return matches(principal.Bairro, request.Bairro) && matches(principal.Municipio, request.Municipio)
+}
+
+// principalAddress returns the principal address of an endereco, if any
+func principalAddress(endereco *models.Endereco) *models.EnderecoPrincipal {
+	if endereco == nil {
+		return nil
+	}
+	return endereco.Principal
+}
+
+// cfLookupBulkDocument is a citizen (or self-declared) address joined with the address stored in
+// the other collection
+type cfLookupBulkDocument struct {
+	CPF      string           `bson:"cpf"`
+	Endereco *models.Endereco `bson:"endereco"`
+	Joined   []struct {
+		Endereco *models.Endereco `bson:"endereco"`
+	} `bson:"joined"`
+}
+
+// joinedPrincipal returns the principal address of the joined document, if any
+func (d *cfLookupBulkDocument) joinedPrincipal() *models.EnderecoPrincipal {
+	if len(d.Joined) == 0 {
+		return nil
+	}
+	return principalAddress(d.Joined[0].Endereco)
+}
+
+// EnqueueCFLookupsByRegion queues a cf_lookup job for every citizen whose current address
+// (self-declared first, then base data) is in the requested bairro/municipio. Citizens are
+// streamed from MongoDB, never loaded at once, and jobs carry a batch ID whose progress is
+// returned by GetCFLookupBatchStatus. The sync worker paces these jobs with the global CF rate
+// limiter.
+func (s *CFLookupService) EnqueueCFLookupsByRegion(ctx context.Context, request models.CFLookupBulkRequest) (*models.CFLookupBulkResponse, error) {
+	ctx, span := utils.TraceBusinessLogic(ctx, "cf_lookup_enqueue_by_region")
+	defer span.End()
+
+	request.Bairro = strings.TrimSpace(request.Bairro)
+	request.Municipio = strings.TrimSpace(request.Municipio)
+	if request.Bairro == "" && request.Municipio == "" {
+		return nil, fmt.Errorf("bairro or municipio is required")
+	}
+
+	response := &models.CFLookupBulkResponse{
+		Bairro:    request.Bairro,
+		Municipio: request.Municipio,
+		DryRun:    request.DryRun,
+	}
+
+	batchID := ""
+	if !request.DryRun {
+		batchID = primitive.NewObjectID().Hex()
+		response.BatchID = batchID
+		pipe := config.Redis.Pipeline()
+		pipe.HSet(ctx, cfLookupBatchKey(batchID), map[string]interface{}{
+			"bairro":     request.Bairro,
+			"municipio":  request.Municipio,
+			"created_at": time.Now().Format(time.RFC3339),
+			"enqueued":   0,
+			"completed":  0,
+			"failed":     0,
+		})
+		pipe.Expire(ctx, cfLookupBatchKey(batchID), cfLookupBatchTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create CF lookup batch: %w", err)
+		}
+	}
+
+	pending := make([]interface{}, 0, cfLookupBulkPushSize)
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		pipe := config.Redis.Pipeline()
+		pipe.LPush(ctx, "sync:queue:cf_lookup", pending...)
+		pipe.HIncrBy(ctx, cfLookupBatchKey(batchID), "enqueued", int64(len(pending)))
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to queue CF lookup jobs: %w", err)
+		}
+		response.Enqueued += len(pending)
+		pending = pending[:0]
+		return nil
+	}
+
+	visit := func(cpf string, principal *models.EnderecoPrincipal) error {
+		address := s.buildFullAddress(principal.Logradouro, principal.Numero, principal.Complemento,
+			principal.Bairro, principal.Municipio, principal.Estado)
+		if address == "" {
+			response.SkippedNoAddress++
+			return nil
+		}
+		response.Matched++
+		if request.DryRun {
+			return nil
+		}
+
+		job := newCFLookupJob(ctx, cpf, address)
+		job.Data.(map[string]interface{})[CFLookupBatchIDKey] = batchID
+		jobBytes, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to marshal CF lookup job: %w", err)
+		}
+		pending = append(pending, string(jobBytes))
+		if len(pending) >= cfLookupBulkPushSize {
+			return flush()
+		}
+		return nil
+	}
+
+	// Citizens whose base address is in the region and who did not self-declare an address elsewhere
+	err := s.streamRegionAddresses(ctx, config.AppConfig.CitizenCollection, config.AppConfig.SelfDeclaredCollection, request,
+		func(doc *cfLookupBulkDocument) error {
+			principal := principalAddress(doc.Endereco)
+			if selfDeclared := doc.joinedPrincipal(); selfDeclared != nil {
+				if !cfLookupRegionMatches(selfDeclared, request) {
+					return nil
+				}
+				principal = selfDeclared
+			}
+			return visit(doc.CPF, principal)
+		})
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{"collection": config.AppConfig.CitizenCollection})
+		return nil, err
+	}
+
+	// Citizens who self-declared an address in the region while their base address is elsewhere
+	err = s.streamRegionAddresses(ctx, config.AppConfig.SelfDeclaredCollection, config.AppConfig.CitizenCollection, request,
+		func(doc *cfLookupBulkDocument) error {
+			if cfLookupRegionMatches(doc.joinedPrincipal(), request) {
+				return nil // already handled with the base data
+			}
+			return visit(doc.CPF, principalAddress(doc.Endereco))
+		})
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{"collection": config.AppConfig.SelfDeclaredCollection})
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		utils.RecordErrorInSpan(span, err, nil)
+		return nil, err
+	}
+
+	utils.AddSpanAttribute(span, "batch_id", batchID)
+	utils.AddSpanAttribute(span, "matched", response.Matched)
+	utils.AddSpanAttribute(span, "enqueued", response.Enqueued)
+	s.logger.Info("bulk CF lookup queued",
+		zap.String("batch_id", batchID),
+		zap.String("bairro", request.Bairro),
+		zap.String("municipio", request.Municipio),
+		zap.Int("matched", response.Matched),
+		zap.Int("enqueued", response.Enqueued),
+		zap.Int("skipped_no_address", response.SkippedNoAddress),
+		zap.Bool("dry_run", request.DryRun))
+
+	return response, nil
+}
+
+// streamRegionAddresses iterates, one document at a time, over the documents of collection whose
+// principal address is in the region, joined with the address of the same CPF in joinCollection
+func (s *CFLookupService) streamRegionAddresses(ctx context.Context, collection, joinCollection string, request models.CFLookupBulkRequest, fn func(*cfLookupBulkDocument) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: cfLookupRegionFilter(request)}},
+		{{Key: "$project", Value: bson.M{"cpf": 1, "endereco.principal": 1}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         joinCollection,
+			"localField":   "cpf",
+			"foreignField": "cpf",
+			"as":           "joined",
+		}}},
+		{{Key: "$project", Value: bson.M{"cpf": 1, "endereco.principal": 1, "joined.endereco.principal": 1}}},
+	}
+
+	cursor, err := s.database.Collection(collection).Aggregate(ctx, pipeline, options.Aggregate().SetBatchSize(cfLookupBulkPushSize))
+	if err != nil {
+		return fmt.Errorf("failed to query %s addresses: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc cfLookupBulkDocument
+		if err := cursor.Decode(&doc); err != nil {
+			s.logger.Warn("failed to decode address for bulk CF lookup", zap.Error(err), zap.String("collection", collection))
+			continue
+		}
+		if doc.CPF == "" || principalAddress(doc.Endereco) == nil {
+			continue
+		}
+		if err := fn(&doc); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate %s addresses: %w", collection, err)
+	}
+	return nil
+}
+
+// GetCFLookupBatchStatus returns the progress of a bulk CF lookup batch
+func GetCFLookupBatchStatus(ctx context.Context, batchID string) (*models.CFLookupBatchStatus, error) {
+	pipe := config.Redis.Pipeline()
+	cmd := pipe.HGetAll(ctx, cfLookupBatchKey(batchID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get CF lookup batch: %w", err)
+	}
+	fields, err := cmd.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CF lookup batch: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, ErrCFLookupBatchNotFound
+	}
+
+	count := func(field string) int {
+		n, _ := strconv.Atoi(fields[field])
+		return n
+	}
+	status := &models.CFLookupBatchStatus{
+		BatchID:   batchID,
+		Bairro:    fields["bairro"],
+		Municipio: fields["municipio"],
+		Enqueued:  count("enqueued"),
+		Completed: count("completed"),
+		Failed:    count("failed"),
+	}
+	status.Pending = status.Enqueued - status.Completed
+	if status.Pending < 0 {
+		status.Pending = 0
+	}
+	if createdAt, err := time.Parse(time.RFC3339, fields["created_at"]); err == nil {
+		status.CreatedAt = createdAt
+	}
+	return status, nil
+}
+
+// recordCFLookupBatchResult counts a finished lookup attempt in its bulk batch. Batches that
+// expired are not recreated.
+func recordCFLookupBatchResult(ctx context.Context, batchID string, lookupErr error) error {
+	field := "completed"
+	if lookupErr != nil {
+		field = "failed"
+	}
+	return config.Redis.Eval(ctx, cfLookupBatchIncrScript, []string{cfLookupBatchKey(batchID)}, field).Err()
+}
+
+// cfLookupBatchIncrScript increments a counter of an existing batch hash
+const cfLookupBatchIncrScript = `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HINCRBY", KEYS[1], ARGV[1], 1)
+end
+return 0`
+
+// waitForCFRateLimit blocks until the global CF rate limiter allows another lookup, pacing the
+// jobs of bulk batches
+func waitForCFRateLimit(ctx context.Context, cpf string) error {
+	if CFRateLimiterInstance == nil {
+		return nil
+	}
+	for {
+		if allowed, _ := CFRateLimiterInstance.ShouldAllowCFLookup(ctx, cpf, 0); allowed {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cfLookupRateLimitRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCFLookupRegionFilter(t *testing.T) {
+	filter := cfLookupRegionFilter(models.CFLookupBulkRequest{Bairro: " Copacabana ", Municipio: "Rio (RJ)"})
+
+	bairro, ok := filter["endereco.principal.bairro"].(bson.M)
+	if !ok || bairro["$regex"] != "^Copacabana$" || bairro["$options"] != "i" {
+		t.Errorf("bairro filter = %v, want case-insensitive exact match on Copacabana", filter["endereco.principal.bairro"])
+	}
+	municipio, ok := filter["endereco.principal.municipio"].(bson.M)
+	if !ok || municipio["$regex"] != `^Rio \(RJ\)$` {
+		t.Errorf("municipio filter = %v, want escaped exact match", filter["endereco.principal.municipio"])
+	}
+
+	filter = cfLookupRegionFilter(models.CFLookupBulkRequest{Bairro: "Centro"})
+	if _, ok := filter["endereco.principal.municipio"]; ok {
+		t.Error("municipio filter should be omitted when not requested")
+	}
+}
+
+func TestCFLookupRegionMatches(t *testing.T) {
+	bairro, municipio := "COPACABANA", "Rio de Janeiro"
+	principal := &models.EnderecoPrincipal{Bairro: &bairro, Municipio: &municipio}
+
+	tests := []struct {
+		name      string
+		principal *models.EnderecoPrincipal
+		request   models.CFLookupBulkRequest
+		want      bool
+	}{
+		{"bairro ignoring case", principal, models.CFLookupBulkRequest{Bairro: "copacabana"}, true},
+		{"bairro and municipio", principal, models.CFLookupBulkRequest{Bairro: "Copacabana", Municipio: "rio de janeiro"}, true},
+		{"other bairro", principal, models.CFLookupBulkRequest{Bairro: "Centro"}, false},
+		{"other municipio", principal, models.CFLookupBulkRequest{Bairro: "Copacabana", Municipio: "Niterói"}, false},
+		{"missing bairro", &models.EnderecoPrincipal{Municipio: &municipio}, models.CFLookupBulkRequest{Bairro: "Copacabana"}, false},
+		{"no address", nil, models.CFLookupBulkRequest{Bairro: "Copacabana"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfLookupRegionMatches(tt.principal, tt.request); got != tt.want {
+				t.Errorf("cfLookupRegionMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
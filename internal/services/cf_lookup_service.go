@@ -5,37 +5,56 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/prefeitura-rio/app-rmi/internal/circuitbreaker"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 // Global CF lookup service instance
 var CFLookupServiceInstance *CFLookupService
 
+// ErrCFRateLimited is returned when the global CF rate limiter rejects an on-demand lookup
+var ErrCFRateLimited = errors.New("CF lookup rate limit exceeded")
+
+const (
+	// MaxCFTeamsBatchSize caps how many CPFs a single bulk CF team lookup accepts
+	MaxCFTeamsBatchSize = 500
+	// CFTeamsBatchConcurrency caps how many CPFs are resolved in parallel during a bulk lookup
+	CFTeamsBatchConcurrency = 10
+)
+
 // CFLookupService handles CF lookup business logic
 type CFLookupService struct {
-	database  *mongo.Database
-	mcpClient *MCPClient
-	logger    *logging.SafeLogger
+	database   *mongo.Database
+	mcpClient  *MCPClient
+	mcpBreaker *circuitbreaker.CircuitBreaker // guards synchronous lookups during MCP outages
+	lookups    singleflight.Group             // coalesces concurrent synchronous lookups per CPF and address
+	logger     *logging.SafeLogger
 }
 
 // NewCFLookupService creates a new CF lookup service instance
 func NewCFLookupService(database *mongo.Database, mcpClient *MCPClient, logger *logging.SafeLogger) *CFLookupService {
 	return &CFLookupService{
-		database:  database,
-		mcpClient: mcpClient,
-		logger:    logger,
+		database:   database,
+		mcpClient:  mcpClient,
+		mcpBreaker: newCFCircuitBreaker(logger),
+		logger:     logger,
 	}
 }
 
@@ -56,7 +75,7 @@ func InitCFLookupService() {
 		zap.Duration("cache_ttl", config.AppConfig.CFLookupCacheTTL))
 
 	// Initialize MCP client with error handling
-	mcpClient := NewMCPClient(config.AppConfig, &logging.SafeLogger{})
+	mcpClient := NewMCPClient(config.AppConfig, httpclient.Client(httpclient.ClientMCP), &logging.SafeLogger{})
 	if mcpClient == nil {
 		logger.Error("failed to initialize MCP client - CF lookup service disabled")
 		CFLookupServiceInstance = nil
@@ -67,6 +86,12 @@ func InitCFLookupService() {
 	logger.Info("CF lookup service initialized successfully")
 }
 
+// CFIntegrationEnabled reports whether a citizen's requests integrate CF data: the service must
+// be enabled and the cf_integration feature flag on for the CPF
+func CFIntegrationEnabled(ctx context.Context, cpf string) bool {
+	return CFLookupServiceInstance != nil && IsFeatureEnabled(ctx, models.FeatureFlagCFIntegration, cpf)
+}
+
 // ShouldLookupCF determines if a CF lookup should be performed for a citizen
 func (s *CFLookupService) ShouldLookupCF(ctx context.Context, cpf string, citizenData *models.Citizen) (bool, string, error) {
 	startTime := time.Now()
@@ -111,6 +136,14 @@ func (s *CFLookupService) ShouldLookupCF(ctx context.Context, cpf string, citize
 		return false, address, nil
 	}
 
+	// The last lookup for this address found no equipment - don't ask again until it expires
+	if s.isNoEquipmentCached(ctx, cpf, addressHash) {
+		s.logger.Debug("skipping CF lookup - no equipment found recently for same address",
+			zap.String("cpf", cpf),
+			zap.String("address_hash", addressHash))
+		return false, address, nil
+	}
+
 	// No rate limiting needed for CF lookups
 
 	s.logger.Debug("CF lookup should be performed",
@@ -141,12 +174,28 @@ func (s *CFLookupService) PerformCFLookup(ctx context.Context, cpf, address stri
 		zap.String("operation", "cf_lookup_start"),
 		zap.String("address_hash", s.GenerateAddressHash(address)))
 
+	// A job queued before (or alongside) a "no equipment" answer for the same address doesn't ask again
+	if s.isNoEquipmentCached(ctx, cpf, s.GenerateAddressHash(address)) {
+		observability.RMICFLookupNoEquipmentTotal.WithLabelValues("cached").Inc()
+		s.logger.Info("skipping CF lookup - no equipment found recently for same address",
+			zap.String("cpf", cpf),
+			zap.String("operation", "cf_lookup_no_equipment_cached"))
+		return nil
+	}
+
 	// Add timeout for the entire operation
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
 	// Call MCP server to find CF with enhanced error handling
 	healthData, err := s.mcpClient.FindNearestCF(ctx, address)
+	if errors.Is(err, ErrNoEquipmentFound) {
+		s.logger.Info("no health equipment found for address",
+			zap.String("cpf", cpf),
+			zap.String("address", address))
+		s.recordNoEquipment(ctx, cpf, address, "async")
+		return nil
+	}
 	if err != nil {
 		// Categorize the error for better handling
 		errorType := s.categorizeError(err)
@@ -175,6 +224,7 @@ func (s *CFLookupService) PerformCFLookup(ctx context.Context, cpf, address stri
 		s.logger.Info("no health services found for address",
 			zap.String("cpf", cpf),
 			zap.String("address", address))
+		s.recordNoCFFound(ctx, cpf, address)
 		return nil
 	}
 
@@ -188,8 +238,6 @@ func (s *CFLookupService) PerformCFLookup(ctx context.Context, cpf, address stri
 		CFData:          *healthData.HealthFacility,
 		EquipeSaudeData: healthData.FamilyHealthTeam,
 		LookupSource:    "mcp",
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
 		IsActive:        true,
 	}
 
@@ -214,6 +262,8 @@ func (s *CFLookupService) PerformCFLookup(ctx context.Context, cpf, address stri
 		// Don't fail the operation for cache errors
 	}
 
+	EventBusInstance.PublishCFDataReady(ctx, cpf, addressHash)
+
 	s.logger.Info("CF lookup completed successfully",
 		zap.String("cpf", cpf),
 		zap.String("cf_name_popular", healthData.HealthFacility.NomePopular),
@@ -229,8 +279,20 @@ func (s *CFLookupService) PerformCFLookup(ctx context.Context, cpf, address stri
 	return nil
 }
 
-// GetCFDataForCitizen retrieves CF data for a citizen (from cache or database)
+// GetCFDataForCitizen retrieves CF data for a citizen (from cache or database). Data past
+// CF_LOOKUP_MAX_AGE is returned as stale and a re-lookup is queued.
 func (s *CFLookupService) GetCFDataForCitizen(ctx context.Context, cpf string) (*models.CFLookup, error) {
+	cfData, err := s.getStoredCFData(ctx, cpf)
+	if err != nil || cfData == nil {
+		return cfData, err
+	}
+	s.refreshIfExpired(ctx, cfData)
+	return cfData, nil
+}
+
+// getStoredCFData retrieves the stored CF data for a citizen (from cache or database) without
+// queuing any lookup
+func (s *CFLookupService) getStoredCFData(ctx context.Context, cpf string) (*models.CFLookup, error) {
 	ctx, span := utils.TraceCacheGet(ctx, fmt.Sprintf("cf_lookup:%s", cpf))
 	defer span.End()
 
@@ -263,6 +325,223 @@ func (s *CFLookupService) GetCFDataForCitizen(ctx context.Context, cpf string) (
 	return cfData, nil
 }
 
+// isCFDataExpired reports whether active CF data was last refreshed more than CF_LOOKUP_MAX_AGE ago
+func isCFDataExpired(cfData *models.CFLookup, maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && cfData.IsActive && now.Sub(cfData.LastRefreshedAt()) > maxAge
+}
+
+// refreshIfExpired marks CF data past its max age as stale and queues an async re-lookup, so reads
+// keep serving the stale data without blocking. Refreshes are queued at most once per
+// CF_LOOKUP_RATE_LIMIT for each CPF.
+func (s *CFLookupService) refreshIfExpired(ctx context.Context, cfData *models.CFLookup) {
+	if !isCFDataExpired(cfData, config.AppConfig.CFLookupMaxAge, time.Now()) {
+		return
+	}
+	cfData.Stale = true
+	observability.RMICFStaleReadsTotal.Inc()
+
+	if cfData.AddressUsed == "" {
+		return
+	}
+
+	if lockTTL := config.AppConfig.CFLookupRateLimit; lockTTL > 0 {
+		refreshKey := fmt.Sprintf("cf_lookup:refresh:%s", cfData.CPF)
+		acquired, err := config.Redis.SetNX(ctx, refreshKey, "1", lockTTL).Result()
+		if err != nil {
+			s.logger.Warn("failed to check CF refresh lock", zap.Error(err), zap.String("cpf", cfData.CPF))
+			return
+		}
+		if !acquired {
+			return
+		}
+	}
+
+	observability.RMICFRefreshTriggersTotal.WithLabelValues("max_age").Inc()
+	s.logger.Info("CF data past max age - queuing refresh",
+		zap.String("cpf", cfData.CPF),
+		zap.Time("refreshed_at", cfData.LastRefreshedAt()),
+		zap.Duration("max_age", config.AppConfig.CFLookupMaxAge),
+		zap.String("operation", "cf_refresh_triggered"))
+	s.queueCFLookupJob(ctx, cfData.CPF, cfData.AddressUsed)
+}
+
+// GetCFTeamsBatch returns the stored CF and family health team summary for many citizens.
+// It only reads existing CF lookup data (cache or database) and never triggers new lookups, not
+// even refreshes of data past CF_LOOKUP_MAX_AGE.
+// Results keep the request order; duplicated CPFs are returned once.
+func (s *CFLookupService) GetCFTeamsBatch(ctx context.Context, cpfs []string) *models.CFTeamsBatchResponse {
+	ctx, span := utils.TraceBusinessLogic(ctx, "cf_lookup_teams_batch")
+	defer span.End()
+
+	uniqueCPFs := make([]string, 0, len(cpfs))
+	seen := make(map[string]bool, len(cpfs))
+	for _, cpf := range cpfs {
+		cpf = strings.TrimSpace(cpf)
+		if seen[cpf] {
+			continue
+		}
+		seen[cpf] = true
+		uniqueCPFs = append(uniqueCPFs, cpf)
+	}
+
+	results := make([]models.CFTeamsBatchItem, len(uniqueCPFs))
+	semaphore := make(chan struct{}, CFTeamsBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, cpf := range uniqueCPFs {
+		if !utils.ValidateCPF(cpf) {
+			results[i] = models.CFTeamsBatchItem{CPF: cpf, Error: "invalid_cpf"}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, cpf string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			cfData, err := s.getStoredCFData(ctx, cpf)
+			if err != nil {
+				s.logger.Warn("failed to get CF data for batch lookup", zap.Error(err), zap.String("cpf", cpf))
+				results[i] = models.CFTeamsBatchItem{CPF: cpf, Error: "lookup_failed"}
+				return
+			}
+			results[i] = cfData.ToCFTeamsBatchItem(cpf)
+		}(i, cpf)
+	}
+	wg.Wait()
+
+	response := &models.CFTeamsBatchResponse{
+		Results:    results,
+		TotalCount: len(results),
+	}
+	for _, item := range results {
+		if item.HasCFData {
+			response.FoundCount++
+		} else {
+			response.MissingCount++
+		}
+	}
+
+	utils.AddSpanAttribute(span, "batch.total_count", response.TotalCount)
+	utils.AddSpanAttribute(span, "batch.found_count", response.FoundCount)
+
+	return response
+}
+
+// cfCoverageRegionFields maps the supported coverage groupings to the address field they use
+var cfCoverageRegionFields = map[string]string{
+	models.CFCoverageByBairro:    "bairro",
+	models.CFCoverageByMunicipio: "municipio",
+}
+
+// IsValidCFCoverageGroupBy reports whether coverage stats can be grouped by the given region type
+func IsValidCFCoverageGroupBy(by string) bool {
+	_, ok := cfCoverageRegionFields[by]
+	return ok
+}
+
+// GetCoverageStats aggregates stored CF lookups by the region of the citizen's address
+// (self-declared address first, then base data), counting citizens with an active CF and
+// citizens whose lookup found no CF. The aggregation is expensive, so results are cached for
+// CF_COVERAGE_STATS_CACHE_TTL.
+func (s *CFLookupService) GetCoverageStats(ctx context.Context, by string) (*models.CFCoverageStats, error) {
+	field, ok := cfCoverageRegionFields[by]
+	if !ok {
+		return nil, fmt.Errorf("unsupported coverage grouping: %s", by)
+	}
+
+	cacheKey := fmt.Sprintf("cf_coverage:stats:%s", by)
+	if cached, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil {
+		var stats models.CFCoverageStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	ctx, span := utils.TraceDatabaseFind(ctx, config.AppConfig.CFLookupCollection, "cf_coverage_aggregate")
+	defer span.End()
+
+	regionPath := "endereco.principal." + field
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": []bson.M{{"is_active": true}, {"no_cf_found": true}}}}},
+		{{Key: "$project", Value: bson.M{"cpf": 1, "is_active": 1, "no_cf_found": 1}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         config.AppConfig.SelfDeclaredCollection,
+			"localField":   "cpf",
+			"foreignField": "cpf",
+			"as":           "self_declared",
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         config.AppConfig.CitizenCollection,
+			"localField":   "cpf",
+			"foreignField": "cpf",
+			"as":           "citizen",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"is_active": 1,
+			"region": bson.M{"$ifNull": bson.A{
+				bson.M{"$arrayElemAt": bson.A{"$self_declared." + regionPath, 0}},
+				bson.M{"$ifNull": bson.A{
+					bson.M{"$arrayElemAt": bson.A{"$citizen." + regionPath, 0}},
+					"",
+				}},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$region",
+			"with_cf": bson.M{"$sum": bson.M{"$cond": bson.A{"$is_active", 1, 0}}},
+			"no_cf":   bson.M{"$sum": bson.M{"$cond": bson.A{"$is_active", 0, 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := s.database.Collection(config.AppConfig.CFLookupCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{"group_by": by})
+		return nil, fmt.Errorf("failed to aggregate CF coverage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Region string `bson:"_id"`
+		WithCF int    `bson:"with_cf"`
+		NoCF   int    `bson:"no_cf"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		utils.RecordErrorInSpan(span, err, map[string]interface{}{"group_by": by})
+		return nil, fmt.Errorf("failed to decode CF coverage: %w", err)
+	}
+
+	stats := &models.CFCoverageStats{
+		GroupBy:     by,
+		Regions:     make([]models.CFCoverageRegion, 0, len(results)),
+		GeneratedAt: time.Now(),
+	}
+	for _, result := range results {
+		stats.Regions = append(stats.Regions, models.NewCFCoverageRegion(result.Region, result.WithCF, result.NoCF))
+		stats.TotalWithCF += result.WithCF
+		stats.TotalNoCFFound += result.NoCF
+	}
+	utils.AddSpanAttribute(span, "region_count", len(stats.Regions))
+
+	if data, err := json.Marshal(stats); err == nil {
+		if err := config.Redis.Set(ctx, cacheKey, data, config.AppConfig.CFCoverageStatsCacheTTL).Err(); err != nil {
+			s.logger.Warn("failed to cache CF coverage stats", zap.Error(err), zap.String("group_by", by))
+		}
+	}
+
+	return stats, nil
+}
+
+// Ping checks that the MCP server backing CF lookups is reachable
+func (s *CFLookupService) Ping(ctx context.Context) error {
+	if s == nil || s.mcpClient == nil {
+		return fmt.Errorf("CF lookup service not available")
+	}
+	return s.mcpClient.Ping(ctx)
+}
+
 // InvalidateCFDataForAddress invalidates CF data when address changes
 func (s *CFLookupService) InvalidateCFDataForAddress(ctx context.Context, cpf, newAddressHash string) error {
 	ctx, span := utils.TraceBusinessLogic(ctx, "cf_lookup_invalidate")
@@ -311,7 +590,11 @@ func (s *CFLookupService) InvalidateCFDataForAddress(ctx context.Context, cpf, n
 
 // GenerateAddressHash creates a hash of the address for tracking changes
 func (s *CFLookupService) GenerateAddressHash(address string) string {
-	// Normalize address (lowercase, trim spaces)
+	return HashAddress(address)
+}
+
+// HashAddress returns the SHA-256 hash of the normalized (lowercase, trimmed) address
+func HashAddress(address string) string {
 	normalized := strings.TrimSpace(strings.ToLower(address))
 
 	hash := sha256.Sum256([]byte(normalized))
@@ -482,22 +765,23 @@ func (s *CFLookupService) storeCFLookup(ctx context.Context, cfLookup *models.CF
 	// Use upsert to replace/create a single document per CPF
 	filter := bson.M{"cpf": cfLookup.CPF}
 
-	update := bson.M{
-		"$set": bson.M{
-			"cpf":             cfLookup.CPF,
-			"address_hash":    cfLookup.AddressHash,
-			"address_used":    cfLookup.AddressUsed,
-			"cf_data":         cfLookup.CFData,
-			"distance_meters": cfLookup.DistanceMeters,
-			"lookup_source":   cfLookup.LookupSource,
-			"updated_at":      time.Now(),
-			"is_active":       true,
-		},
-		"$setOnInsert": bson.M{
-			"_id":        cfLookup.ID,
-			"created_at": cfLookup.CreatedAt,
-		},
-	}
+	// Keep the caller's copy, which is cached next, in line with the stored document
+	now := time.Now()
+	cfLookup.Touch(now)
+	cfLookup.RefreshedAt = &now
+
+	update := utils.TimestampedUpdate(bson.M{
+		"cpf":             cfLookup.CPF,
+		"address_hash":    cfLookup.AddressHash,
+		"address_used":    cfLookup.AddressUsed,
+		"cf_data":         cfLookup.CFData,
+		"distance_meters": cfLookup.DistanceMeters,
+		"lookup_source":   cfLookup.LookupSource,
+		"refreshed_at":    now,
+		"is_active":       true,
+		"no_cf_found":     false,
+	}, now)
+	update["$setOnInsert"].(bson.M)["_id"] = cfLookup.ID
 
 	opts := options.Update().SetUpsert(true)
 	_, err := collection.UpdateOne(ctx, filter, update, opts)
@@ -505,6 +789,11 @@ func (s *CFLookupService) storeCFLookup(ctx context.Context, cfLookup *models.CF
 		return fmt.Errorf("failed to upsert CF lookup: %w", err)
 	}
 
+	// A CF was found for the citizen, so any earlier "no equipment" result no longer applies
+	if err := config.Redis.Del(ctx, noEquipmentCacheKey(cfLookup.CPF)).Err(); err != nil {
+		s.logger.Warn("failed to clear CF no equipment cache", zap.Error(err), zap.String("cpf", cfLookup.CPF))
+	}
+
 	s.logger.Debug("CF lookup stored successfully",
 		zap.String("cpf", cfLookup.CPF),
 		zap.String("address_hash", cfLookup.AddressHash))
@@ -512,6 +801,60 @@ func (s *CFLookupService) storeCFLookup(ctx context.Context, cfLookup *models.CF
 	return nil
 }
 
+// recordNoCFFound records that a lookup found no CF for the citizen's address, so coverage stats
+// can report it. The document stays inactive and is replaced by the next successful lookup.
+func (s *CFLookupService) recordNoCFFound(ctx context.Context, cpf, address string) {
+	collection := s.database.Collection(config.AppConfig.CFLookupCollection)
+
+	update := utils.TimestampedUpdate(bson.M{
+		"cpf":          cpf,
+		"address_hash": s.GenerateAddressHash(address),
+		"address_used": address,
+		"is_active":    false,
+		"no_cf_found":  true,
+	}, time.Now())
+	update["$setOnInsert"].(bson.M)["_id"] = primitive.NewObjectID()
+
+	// Never overwrite an active lookup (e.g. one stored concurrently for the same CPF)
+	filter := bson.M{"cpf": cpf, "is_active": bson.M{"$ne": true}}
+	if _, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil && !mongo.IsDuplicateKeyError(err) {
+		s.logger.Warn("failed to record CF lookup without result", zap.Error(err), zap.String("cpf", cpf))
+	}
+}
+
+// noEquipmentCacheKey returns the Redis key remembering that the citizen's address has no equipment
+func noEquipmentCacheKey(cpf string) string {
+	return fmt.Sprintf("cf_lookup:no_equipment:%s", cpf)
+}
+
+// recordNoEquipment handles an MCP "no equipment found" answer: it is recorded like any lookup
+// without a CF, counted by mode (async or sync) and cached for the address hash for
+// CF_LOOKUP_NO_EQUIPMENT_TTL so the same address isn't looked up again in the meantime.
+func (s *CFLookupService) recordNoEquipment(ctx context.Context, cpf, address, mode string) {
+	observability.RMICFLookupNoEquipmentTotal.WithLabelValues(mode).Inc()
+	s.recordNoCFFound(ctx, cpf, address)
+
+	ttl := config.AppConfig.CFLookupNoEquipmentTTL
+	if ttl <= 0 {
+		return
+	}
+	if err := config.Redis.Set(ctx, noEquipmentCacheKey(cpf), s.GenerateAddressHash(address), ttl).Err(); err != nil {
+		s.logger.Warn("failed to cache CF no equipment result", zap.Error(err), zap.String("cpf", cpf))
+	}
+}
+
+// IsNoEquipmentCached reports whether a lookup of the citizen's address recently found no
+// equipment, in which case it isn't looked up again until CF_LOOKUP_NO_EQUIPMENT_TTL expires
+func (s *CFLookupService) IsNoEquipmentCached(ctx context.Context, cpf, address string) bool {
+	return s.isNoEquipmentCached(ctx, cpf, s.GenerateAddressHash(address))
+}
+
+// isNoEquipmentCached reports whether the last lookup for the citizen's address (by hash) found no equipment
+func (s *CFLookupService) isNoEquipmentCached(ctx context.Context, cpf, addressHash string) bool {
+	cached, err := config.Redis.Get(ctx, noEquipmentCacheKey(cpf)).Result()
+	return err == nil && cached == addressHash
+}
+
 // getCachedCFData retrieves CF data from Redis cache
 func (s *CFLookupService) getCachedCFData(ctx context.Context, cpf string) (*models.CFLookup, error) {
 	cacheKey := fmt.Sprintf("cf_lookup:cpf:%s", cpf)
@@ -572,6 +915,40 @@ func (s *CFLookupService) TrySynchronousCFLookup(ctx context.Context, cpf, addre
 		return cachedData, nil
 	}
 
+	// Don't call MCP again for an address that recently had no equipment
+	addressHash := s.GenerateAddressHash(address)
+	if s.isNoEquipmentCached(ctx, cpf, addressHash) {
+		observability.RMICFLookupNoEquipmentTotal.WithLabelValues("cached").Inc()
+		s.logger.Debug("no equipment found recently for address", zap.String("cpf", cpf))
+		return nil, ErrNoEquipmentFound
+	}
+
+	// Concurrent lookups for the same CPF and address share one MCP call, whose result is stored
+	// and cached once. The shared call doesn't inherit the cancellation of the caller that started
+	// it, so a client going away doesn't fail the others; each caller still stops waiting when its
+	// own context ends.
+	leader := false
+	result := s.lookups.DoChan(cpf+":"+addressHash, func() (interface{}, error) {
+		leader = true
+		return s.synchronousCFLookup(context.WithoutCancel(ctx), cpf, address)
+	})
+
+	select {
+	case res := <-result:
+		if res.Shared && !leader {
+			observability.CFLookupCoalescedTotal.Inc()
+			s.logger.Debug("synchronous CF lookup coalesced", zap.String("cpf", cpf))
+		}
+		cfLookup, _ := res.Val.(*models.CFLookup)
+		return cfLookup, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// synchronousCFLookup performs the MCP lookup of TrySynchronousCFLookup, storing and caching a
+// found CF and queueing a background job when the lookup fails
+func (s *CFLookupService) synchronousCFLookup(ctx context.Context, cpf, address string) (*models.CFLookup, error) {
 	// Try synchronous MCP lookup with configurable timeout
 	// Default 8 seconds balances user experience with MCP server response times
 	syncCtx, cancel := context.WithTimeout(ctx, config.AppConfig.CFLookupSyncTimeout)
@@ -581,13 +958,29 @@ func (s *CFLookupService) TrySynchronousCFLookup(ctx context.Context, cpf, addre
 
 	// No rate limiting needed for CF lookups
 
-	// Perform MCP lookup
-	healthData, err := s.mcpClient.FindNearestCF(syncCtx, address)
+	// Perform MCP lookup, failing fast while the MCP circuit is open
+	healthData, err := s.findNearestCFGuarded(syncCtx, address)
+
+	if errors.Is(err, ErrNoEquipmentFound) {
+		// A definitive answer - no point in retrying asynchronously
+		s.logger.Info("no health equipment found for address in synchronous lookup",
+			zap.String("cpf", cpf),
+			zap.String("address", address))
+		s.recordNoEquipment(ctx, cpf, address, "sync")
+		return nil, ErrNoEquipmentFound
+	}
+
+	if IsCFCircuitOpen(err) {
+		s.logger.Debug("MCP circuit open, skipping synchronous CF lookup", zap.String("cpf", cpf))
+		s.queueCFLookupJob(context.WithoutCancel(ctx), cpf, address)
+		return nil, err
+	}
 
 	if err != nil {
 		s.logger.Debug("synchronous CF lookup failed", zap.Error(err), zap.String("cpf", cpf))
-		// Fall back to async lookup - queue a job manually
-		s.queueCFLookupJob(ctx, cpf, address)
+		// Fall back to async lookup - queue a job manually. The caller's deadline may be what
+		// failed the lookup, so the job is queued without it
+		s.queueCFLookupJob(context.WithoutCancel(ctx), cpf, address)
 		return nil, err
 	}
 
@@ -596,6 +989,7 @@ func (s *CFLookupService) TrySynchronousCFLookup(ctx context.Context, cpf, addre
 		s.logger.Debug("no health services found for address in synchronous lookup",
 			zap.String("cpf", cpf),
 			zap.String("address", address))
+		s.recordNoCFFound(ctx, cpf, address)
 		return nil, nil
 	}
 
@@ -608,14 +1002,11 @@ func (s *CFLookupService) TrySynchronousCFLookup(ctx context.Context, cpf, addre
 		CFData:          *healthData.HealthFacility,
 		EquipeSaudeData: healthData.FamilyHealthTeam,
 		LookupSource:    "mcp",
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
 		IsActive:        true,
 	}
 
-	// Store in database
-	collection := s.database.Collection(config.AppConfig.CFLookupCollection)
-	_, err = collection.InsertOne(ctx, cfLookup)
+	// Store in database (replacing any earlier no-result document of the CPF)
+	err = s.storeCFLookup(ctx, cfLookup)
 	if err != nil {
 		s.logger.Error("failed to store synchronous CF lookup result", zap.Error(err))
 		return cfLookup, nil // Return the data even if storage failed
@@ -634,20 +1025,64 @@ func (s *CFLookupService) TrySynchronousCFLookup(ctx context.Context, cpf, addre
 	return cfLookup, nil
 }
 
-// queueCFLookupJob queues a CF lookup job for background processing
-func (s *CFLookupService) queueCFLookupJob(ctx context.Context, cpf, address string) {
-	job := SyncJob{
+// PreviewCFForAddress looks up the CF an address would map to without storing, caching or
+// queueing anything, so a citizen can confirm a new address before saving it. The lookup counts
+// against the global CF rate limiter (ErrCFRateLimited) and fails fast while the MCP circuit is
+// open.
+func (s *CFLookupService) PreviewCFForAddress(ctx context.Context, cpf, address string) (*models.CFAddressPreview, error) {
+	ctx, span := utils.TraceBusinessLogic(ctx, "cf_lookup_preview")
+	defer span.End()
+
+	preview := &models.CFAddressPreview{AddressHash: s.GenerateAddressHash(address)}
+
+	if CFRateLimiterInstance != nil {
+		if allowed, reason := CFRateLimiterInstance.ShouldAllowCFLookup(ctx, cpf, 0); !allowed {
+			s.logger.Debug("CF preview rate limited", zap.String("cpf", cpf), zap.String("reason", reason))
+			return nil, ErrCFRateLimited
+		}
+	}
+
+	previewCtx, cancel := context.WithTimeout(ctx, config.AppConfig.CFLookupSyncTimeout)
+	defer cancel()
+
+	healthData, err := s.findNearestCFGuarded(previewCtx, address)
+	if errors.Is(err, ErrNoEquipmentFound) {
+		return preview, nil
+	}
+	if err != nil {
+		s.logger.Debug("CF preview lookup failed", zap.Error(err), zap.String("cpf", cpf))
+		return nil, err
+	}
+
+	if healthData != nil && healthData.HealthFacility != nil {
+		preview.Found = true
+		preview.CFData = healthData.HealthFacility
+		preview.EquipeSaudeData = healthData.FamilyHealthTeam
+	}
+	return preview, nil
+}
+
+// newCFLookupJob builds a CF lookup job carrying the trace context of ctx
+func newCFLookupJob(ctx context.Context, cpf, address string) SyncJob {
+	jobData := map[string]interface{}{
+		"cpf":     cpf,
+		"address": address,
+	}
+	observability.InjectTraceContextIntoJob(ctx, jobData)
+	return SyncJob{
 		ID:         primitive.NewObjectID().Hex(),
 		Type:       "cf_lookup",
 		Collection: "cf_lookup",
-		Data: map[string]interface{}{
-			"cpf":     cpf,
-			"address": address,
-		},
+		Data:       jobData,
 		Timestamp:  time.Now(),
 		RetryCount: 0,
 		MaxRetries: 3,
 	}
+}
+
+// queueCFLookupJob queues a CF lookup job for background processing
+func (s *CFLookupService) queueCFLookupJob(ctx context.Context, cpf, address string) {
+	job := newCFLookupJob(ctx, cpf, address)
 
 	jobBytes, err := json.Marshal(job)
 	if err != nil {
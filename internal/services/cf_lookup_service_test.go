@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
@@ -140,6 +143,31 @@ func TestGenerateAddressHash(t *testing.T) {
 	}
 }
 
+func TestIsCFDataExpired(t *testing.T) {
+	now := time.Now()
+	maxAge := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		cfData *models.CFLookup
+		maxAge time.Duration
+		want   bool
+	}{
+		{"fresh data", &models.CFLookup{IsActive: true, Timestamps: models.Timestamps{UpdatedAt: now.Add(-time.Hour)}}, maxAge, false},
+		{"data past max age", &models.CFLookup{IsActive: true, Timestamps: models.Timestamps{UpdatedAt: now.Add(-maxAge - time.Hour)}}, maxAge, true},
+		{"max age disabled", &models.CFLookup{IsActive: true, Timestamps: models.Timestamps{UpdatedAt: now.Add(-maxAge - time.Hour)}}, 0, false},
+		{"inactive data", &models.CFLookup{IsActive: false, Timestamps: models.Timestamps{UpdatedAt: now.Add(-maxAge - time.Hour)}}, maxAge, false},
+		{"recently refreshed", &models.CFLookup{IsActive: true, Timestamps: models.Timestamps{UpdatedAt: now.Add(-maxAge - time.Hour)}, RefreshedAt: timePtr(now.Add(-time.Hour))}, maxAge, false},
+		{"refreshed past max age", &models.CFLookup{IsActive: true, Timestamps: models.Timestamps{UpdatedAt: now}, RefreshedAt: timePtr(now.Add(-maxAge - time.Hour))}, maxAge, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isCFDataExpired(tt.cfData, tt.maxAge, now))
+		})
+	}
+}
+
 func TestExtractAddress(t *testing.T) {
 	service, cleanup := setupCFLookupTest(t)
 	defer cleanup()
@@ -363,8 +391,7 @@ func TestStoreCFLookup(t *testing.T) {
 		},
 		DistanceMeters: 500,
 		LookupSource:   "mcp",
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+		Timestamps:     models.Timestamps{CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		IsActive:       true,
 	}
 
@@ -402,8 +429,7 @@ func TestStoreCFLookup_Upsert(t *testing.T) {
 			Ativo:       true,
 		},
 		LookupSource: "mcp",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Timestamps:   models.Timestamps{CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		IsActive:     true,
 	}
 
@@ -425,8 +451,7 @@ func TestStoreCFLookup_Upsert(t *testing.T) {
 			Ativo:       true,
 		},
 		LookupSource: "mcp",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Timestamps:   models.Timestamps{CreatedAt: time.Now(), UpdatedAt: time.Now()},
 		IsActive:     true,
 	}
 
@@ -1032,7 +1057,7 @@ func TestTrySynchronousCFLookup_CachedData(t *testing.T) {
 
 	// Create mock MCP client
 	logger := &logging.SafeLogger{}
-	service.mcpClient = NewMCPClient(config.AppConfig, logger)
+	service.mcpClient = NewMCPClient(config.AppConfig, httpclient.Client(httpclient.ClientMCP), logger)
 
 	// Should return cached data without calling MCP
 	result, err := service.TrySynchronousCFLookup(ctx, "12345678901", "Rua Test, 123")
@@ -1045,3 +1070,433 @@ func TestTrySynchronousCFLookup_CachedData(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// Helper function to create time pointers
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestGetCFTeamsBatch(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	idEquipe := "equipe-123"
+	cfData := &models.CFLookup{
+		ID:          primitive.NewObjectID(),
+		CPF:         "03561350712",
+		AddressHash: "hash123",
+		CFData: models.CFInfo{
+			NomeOficial: "CF Test",
+			NomePopular: "Clínica Test",
+			Bairro:      "Copacabana",
+			Ativo:       true,
+		},
+		EquipeSaudeData: &models.EquipeSaudeInfo{
+			IDEquipe:    &idEquipe,
+			NomeOficial: "Equipe Test",
+		},
+		IsActive: true,
+	}
+
+	collection := config.MongoDB.Collection(config.AppConfig.CFLookupCollection)
+	_, err := collection.InsertOne(ctx, cfData)
+	assert.NoError(t, err)
+
+	response := service.GetCFTeamsBatch(ctx, []string{"03561350712", "11144477735", "03561350712", "123"})
+
+	assert.Equal(t, 3, response.TotalCount)
+	assert.Equal(t, 1, response.FoundCount)
+	assert.Equal(t, 2, response.MissingCount)
+
+	// Results keep request order
+	assert.Equal(t, "03561350712", response.Results[0].CPF)
+	assert.True(t, response.Results[0].HasCFData)
+	assert.NotNil(t, response.Results[0].ClinicaFamilia)
+	assert.NotNil(t, response.Results[0].EquipeSaudeFamilia)
+
+	assert.Equal(t, "11144477735", response.Results[1].CPF)
+	assert.False(t, response.Results[1].HasCFData)
+	assert.Empty(t, response.Results[1].Error)
+
+	assert.Equal(t, "123", response.Results[2].CPF)
+	assert.False(t, response.Results[2].HasCFData)
+	assert.Equal(t, "invalid_cpf", response.Results[2].Error)
+}
+
+func TestGetCFTeamsBatch_QueuesNoRefresh(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	originalMaxAge, originalRateLimit := config.AppConfig.CFLookupMaxAge, config.AppConfig.CFLookupRateLimit
+	config.AppConfig.CFLookupMaxAge = time.Hour
+	config.AppConfig.CFLookupRateLimit = time.Minute
+	defer func() {
+		config.AppConfig.CFLookupMaxAge, config.AppConfig.CFLookupRateLimit = originalMaxAge, originalRateLimit
+	}()
+
+	queueKey := "sync:queue:cf_lookup"
+	refreshKey := "cf_lookup:refresh:03561350712"
+	config.Redis.Del(ctx, queueKey, refreshKey)
+	defer config.Redis.Del(ctx, queueKey, refreshKey)
+
+	// CF data past its max age would queue a refresh on a citizen read
+	expired := time.Now().Add(-2 * time.Hour)
+	cfData := &models.CFLookup{
+		ID:          primitive.NewObjectID(),
+		CPF:         "03561350712",
+		AddressHash: "hash123",
+		AddressUsed: "Rua Test, 123",
+		CFData:      models.CFInfo{NomeOficial: "CF Test", Ativo: true},
+		IsActive:    true,
+		Timestamps:  models.Timestamps{CreatedAt: expired, UpdatedAt: expired},
+	}
+	_, err := config.MongoDB.Collection(config.AppConfig.CFLookupCollection).InsertOne(ctx, cfData)
+	assert.NoError(t, err)
+
+	response := service.GetCFTeamsBatch(ctx, []string{"03561350712"})
+	assert.Equal(t, 1, response.FoundCount)
+
+	queued, err := config.Redis.LLen(ctx, queueKey).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), queued, "batch reads must not queue CF lookups")
+
+	// A citizen read of the same data does queue the refresh
+	_, err = service.GetCFDataForCitizen(ctx, "03561350712")
+	assert.NoError(t, err)
+	queued, err = config.Redis.LLen(ctx, queueKey).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), queued)
+}
+
+func TestRecordNoCFFound(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.CFLookupCollection)
+
+	service.recordNoCFFound(ctx, "12345678901", "Rua Sem CF, 1 - Centro")
+
+	var stored models.CFLookup
+	err := collection.FindOne(ctx, bson.M{"cpf": "12345678901"}).Decode(&stored)
+	assert.NoError(t, err)
+	assert.False(t, stored.IsActive)
+	assert.True(t, stored.NoCFFound)
+	assert.Equal(t, service.GenerateAddressHash("Rua Sem CF, 1 - Centro"), stored.AddressHash)
+
+	// A later successful lookup replaces the no-result marker
+	err = service.storeCFLookup(ctx, &models.CFLookup{
+		ID:          primitive.NewObjectID(),
+		CPF:         "12345678901",
+		AddressHash: "hash123",
+		CFData:      models.CFInfo{NomePopular: "Clínica Centro"},
+		Timestamps:  models.Timestamps{CreatedAt: time.Now()},
+		IsActive:    true,
+	})
+	assert.NoError(t, err)
+	err = collection.FindOne(ctx, bson.M{"cpf": "12345678901"}).Decode(&stored)
+	assert.NoError(t, err)
+	assert.True(t, stored.IsActive)
+	assert.False(t, stored.NoCFFound)
+
+	// Recording a no-result never overwrites an active lookup
+	service.recordNoCFFound(ctx, "12345678901", "Outra Rua, 2 - Centro")
+	err = collection.FindOne(ctx, bson.M{"cpf": "12345678901"}).Decode(&stored)
+	assert.NoError(t, err)
+	assert.True(t, stored.IsActive)
+	assert.Equal(t, "hash123", stored.AddressHash)
+}
+
+func TestTrySynchronousCFLookup_NoEquipment(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	config.AppConfig.CFLookupNoEquipmentTTL = time.Hour
+	cpf := "12345678901"
+	address := "Rua Sem Cobertura, 1 - Centro"
+
+	lookups := 0
+	client, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+	service.mcpClient = client
+
+	queueKey := "sync:queue:cf_lookup"
+	config.Redis.Del(ctx, queueKey)
+
+	result, err := service.TrySynchronousCFLookup(ctx, cpf, address)
+	assert.ErrorIs(t, err, ErrNoEquipmentFound)
+	assert.Nil(t, result)
+	assert.Equal(t, 1, lookups)
+
+	// A definitive "no equipment" answer is not retried in background
+	queued, err := config.Redis.LLen(ctx, queueKey).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), queued)
+
+	// It's recorded for coverage stats and negatively cached for the address
+	var stored models.CFLookup
+	err = config.MongoDB.Collection(config.AppConfig.CFLookupCollection).FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored)
+	assert.NoError(t, err)
+	assert.True(t, stored.NoCFFound)
+	assert.True(t, service.isNoEquipmentCached(ctx, cpf, service.GenerateAddressHash(address)))
+	assert.False(t, service.isNoEquipmentCached(ctx, cpf, service.GenerateAddressHash("Outra Rua, 2 - Centro")))
+
+	// Same address again is answered from the negative cache
+	result, err = service.TrySynchronousCFLookup(ctx, cpf, address)
+	assert.ErrorIs(t, err, ErrNoEquipmentFound)
+	assert.Nil(t, result)
+	assert.Equal(t, 1, lookups)
+
+	// A CF found later clears the negative cache
+	err = service.storeCFLookup(ctx, &models.CFLookup{
+		ID:          primitive.NewObjectID(),
+		CPF:         cpf,
+		AddressHash: "hash123",
+		CFData:      models.CFInfo{NomePopular: "Clínica Centro"},
+		Timestamps:  models.Timestamps{CreatedAt: time.Now()},
+		IsActive:    true,
+	})
+	assert.NoError(t, err)
+	assert.False(t, service.isNoEquipmentCached(ctx, cpf, service.GenerateAddressHash(address)))
+}
+
+func TestShouldLookupCF_NoEquipmentCached(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	config.AppConfig.CFLookupNoEquipmentTTL = time.Hour
+
+	citizenData := &models.Citizen{
+		Endereco: &models.Endereco{
+			Principal: &models.EnderecoPrincipal{
+				Logradouro: strPtr("Rua Sem Cobertura"),
+				Numero:     strPtr("1"),
+				Bairro:     strPtr("Centro"),
+				Municipio:  strPtr("Rio de Janeiro"),
+				Estado:     strPtr("RJ"),
+			},
+		},
+	}
+	address := service.ExtractAddress(citizenData)
+	service.recordNoEquipment(ctx, "12345678901", address, "async")
+
+	shouldLookup, returnedAddress, err := service.ShouldLookupCF(ctx, "12345678901", citizenData)
+	assert.NoError(t, err)
+	assert.False(t, shouldLookup)
+	assert.Equal(t, address, returnedAddress)
+}
+
+func TestPerformCFLookup_NoEquipmentCached(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	config.AppConfig.CFLookupNoEquipmentTTL = time.Hour
+	cpf := "12345678901"
+	address := "Rua Sem Cobertura, 1 - Centro"
+
+	lookups := 0
+	client, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+	service.mcpClient = client
+
+	assert.NoError(t, service.PerformCFLookup(ctx, cpf, address))
+	assert.Equal(t, 1, lookups)
+	assert.True(t, service.IsNoEquipmentCached(ctx, cpf, address))
+
+	// A job for the same address queued meanwhile doesn't call MCP again
+	assert.NoError(t, service.PerformCFLookup(ctx, cpf, address))
+	assert.Equal(t, 1, lookups)
+	assert.False(t, service.IsNoEquipmentCached(ctx, cpf, "Outra Rua, 2 - Centro"))
+}
+
+func TestIsValidCFCoverageGroupBy(t *testing.T) {
+	assert.True(t, IsValidCFCoverageGroupBy(models.CFCoverageByBairro))
+	assert.True(t, IsValidCFCoverageGroupBy(models.CFCoverageByMunicipio))
+	assert.False(t, IsValidCFCoverageGroupBy("estado"))
+	assert.False(t, IsValidCFCoverageGroupBy(""))
+}
+
+func TestGetCoverageStats(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	originalCitizens, originalSelfDeclared := config.AppConfig.CitizenCollection, config.AppConfig.SelfDeclaredCollection
+	config.AppConfig.CitizenCollection = "test_cf_coverage_citizens"
+	config.AppConfig.SelfDeclaredCollection = "test_cf_coverage_self_declared"
+	config.AppConfig.CFCoverageStatsCacheTTL = time.Minute
+	defer func() {
+		_ = config.MongoDB.Collection(config.AppConfig.CitizenCollection).Drop(ctx)
+		_ = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).Drop(ctx)
+		config.AppConfig.CitizenCollection, config.AppConfig.SelfDeclaredCollection = originalCitizens, originalSelfDeclared
+		config.Redis.Del(ctx, "cf_coverage:stats:bairro")
+	}()
+	config.Redis.Del(ctx, "cf_coverage:stats:bairro")
+
+	citizens := config.MongoDB.Collection(config.AppConfig.CitizenCollection)
+	selfDeclared := config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection)
+	lookups := config.MongoDB.Collection(config.AppConfig.CFLookupCollection)
+
+	_, err := citizens.InsertMany(ctx, []interface{}{
+		bson.M{"cpf": "11111111111", "endereco": bson.M{"principal": bson.M{"bairro": "Centro"}}},
+		bson.M{"cpf": "22222222222", "endereco": bson.M{"principal": bson.M{"bairro": "Centro"}}},
+		bson.M{"cpf": "33333333333", "endereco": bson.M{"principal": bson.M{"bairro": "Tijuca"}}},
+	})
+	assert.NoError(t, err)
+
+	// Self-declared address takes precedence over the base data
+	_, err = selfDeclared.InsertOne(ctx, bson.M{"cpf": "33333333333", "endereco": bson.M{"principal": bson.M{"bairro": "Centro"}}})
+	assert.NoError(t, err)
+
+	_, err = lookups.InsertMany(ctx, []interface{}{
+		bson.M{"cpf": "11111111111", "is_active": true},
+		bson.M{"cpf": "22222222222", "is_active": false, "no_cf_found": true},
+		bson.M{"cpf": "33333333333", "is_active": true},
+		bson.M{"cpf": "44444444444", "is_active": false},
+	})
+	assert.NoError(t, err)
+
+	stats, err := service.GetCoverageStats(ctx, models.CFCoverageByBairro)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CFCoverageByBairro, stats.GroupBy)
+	assert.Equal(t, 2, stats.TotalWithCF)
+	assert.Equal(t, 1, stats.TotalNoCFFound)
+	if assert.Len(t, stats.Regions, 1) {
+		assert.Equal(t, "Centro", stats.Regions[0].Region)
+		assert.Equal(t, 3, stats.Regions[0].Total)
+	}
+
+	// Results are cached
+	cached, err := config.Redis.Get(ctx, "cf_coverage:stats:bairro").Result()
+	assert.NoError(t, err)
+	assert.Contains(t, cached, "Centro")
+
+	_, err = service.GetCoverageStats(ctx, "estado")
+	assert.Error(t, err)
+}
+
+func TestPreviewCFForAddress_NoEquipment(t *testing.T) {
+	lookups := 0
+	mcpClient, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+
+	originalTimeout := config.AppConfig.CFLookupSyncTimeout
+	config.AppConfig.CFLookupSyncTimeout = 5 * time.Second
+	defer func() { config.AppConfig.CFLookupSyncTimeout = originalTimeout }()
+
+	originalLimiter := CFRateLimiterInstance
+	CFRateLimiterInstance = nil
+	defer func() { CFRateLimiterInstance = originalLimiter }()
+
+	// Without a database: the preview must not store anything
+	service := NewCFLookupService(nil, mcpClient, logging.GetLogger())
+	address := "Rua Sem Cobertura, 1, , Centro, Rio de Janeiro, RJ"
+
+	preview, err := service.PreviewCFForAddress(context.Background(), "11144477735", address)
+
+	assert.NoError(t, err)
+	assert.False(t, preview.Found)
+	assert.Nil(t, preview.CFData)
+	assert.Equal(t, HashAddress(address), preview.AddressHash)
+	assert.Equal(t, 1, lookups)
+}
+
+func TestPreviewCFForAddress_RateLimited(t *testing.T) {
+	lookups := 0
+	mcpClient, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+
+	originalLimiter := CFRateLimiterInstance
+	CFRateLimiterInstance = NewCFRateLimiterManager(1, logging.GetLogger())
+	defer func() { CFRateLimiterInstance = originalLimiter }()
+	CFRateLimiterInstance.ShouldAllowCFLookup(context.Background(), "52998224725", 0)
+
+	service := NewCFLookupService(nil, mcpClient, logging.GetLogger())
+	_, err := service.PreviewCFForAddress(context.Background(), "11144477735", "Rua Test, 123")
+
+	assert.ErrorIs(t, err, ErrCFRateLimited)
+	assert.Equal(t, 0, lookups)
+}
+
+func TestTrySynchronousCFLookup_CoalescesConcurrentLookups(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	config.AppConfig.CFLookupNoEquipmentTTL = time.Hour
+	cpf := "12345678901"
+	address := "Rua Concorrida, 10 - Centro"
+
+	// Hold the MCP server until every caller is waiting on the same lookup
+	lookups := 0
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var enterOnce sync.Once
+	handler := noEquipmentMCPHandler(&lookups)
+	client, server := setupMCPTest(t, func(w http.ResponseWriter, r *http.Request) {
+		enterOnce.Do(func() { close(entered) })
+		<-release
+		handler(w, r)
+	})
+	defer server.Close()
+	service.mcpClient = client
+
+	const callers = 5
+	errs := make(chan error, callers)
+	lookup := func() {
+		_, err := service.TrySynchronousCFLookup(ctx, cpf, address)
+		errs <- err
+	}
+
+	go lookup()
+	<-entered
+	for i := 1; i < callers; i++ {
+		go lookup()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		assert.ErrorIs(t, <-errs, ErrNoEquipmentFound)
+	}
+	assert.Equal(t, 1, lookups, "concurrent lookups should share one MCP call")
+
+	// The shared result is stored once
+	count, err := config.MongoDB.Collection(config.AppConfig.CFLookupCollection).CountDocuments(ctx, bson.M{"cpf": cpf})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTrySynchronousCFLookup_CallerCancellation(t *testing.T) {
+	service, cleanup := setupCFLookupTest(t)
+	defer cleanup()
+
+	config.AppConfig.CFLookupNoEquipmentTTL = time.Hour
+	lookups := 0
+	release := make(chan struct{})
+	handler := noEquipmentMCPHandler(&lookups)
+	client, server := setupMCPTest(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler(w, r)
+	})
+	defer server.Close()
+	service.mcpClient = client
+
+	// A caller that gives up returns its own context error without waiting for the MCP call
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := service.TrySynchronousCFLookup(ctx, "12345678901", "Rua Lenta, 1 - Centro")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The shared call itself isn't cancelled: a later caller joins it or reads its result
+	close(release)
+	_, err = service.TrySynchronousCFLookup(context.Background(), "12345678901", "Rua Lenta, 1 - Centro")
+	assert.ErrorIs(t, err, ErrNoEquipmentFound)
+	assert.Equal(t, 1, lookups)
+}
@@ -45,6 +45,11 @@ func (s *CitizenCacheService) GetCitizen(ctx context.Context, cpf string) (*mode
 
 // UpdateCitizen updates citizen data in cache and queues for MongoDB sync
 func (s *CitizenCacheService) UpdateCitizen(ctx context.Context, cpf string, citizen *models.Citizen) error {
+	// Reject malformed data before it reaches the write buffer and the merge logic
+	if err := citizen.Validate(); err != nil {
+		return err
+	}
+
 	// Check if there's already a pending write
 	existingKey := fmt.Sprintf("citizen:write:%s", cpf)
 	existingData, err := s.dataManager.redis.Get(ctx, existingKey).Result()
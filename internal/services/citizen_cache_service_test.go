@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -197,6 +198,25 @@ func TestUpdateCitizen_Success(t *testing.T) {
 	}
 }
 
+func TestUpdateCitizen_InvalidData(t *testing.T) {
+	service, cleanup := setupCitizenCacheTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	citizen := &models.Citizen{CPF: "12345678900"}
+
+	err := service.UpdateCitizen(ctx, "12345678900", citizen)
+	var validationErr *models.CitizenValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("UpdateCitizen() error = %v, want *models.CitizenValidationError", err)
+	}
+
+	if service.IsCitizenInCache(ctx, "12345678900") {
+		t.Error("Invalid citizen should not be written to cache")
+	}
+}
+
 func TestUpdateCitizen_Overwrite(t *testing.T) {
 	service, cleanup := setupCitizenCacheTest(t)
 	defer cleanup()
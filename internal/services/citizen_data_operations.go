@@ -228,6 +228,7 @@ func (op *MaintenanceRequestDataOperation) GetType() string {
 type SelfDeclaredAddressDataOperation struct {
 	CPF       string
 	Endereco  *models.Endereco
+	Version   int32
 	UpdatedAt time.Time
 }
 
@@ -246,6 +247,7 @@ func (op *SelfDeclaredAddressDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":        op.CPF,
 		"endereco":   op.Endereco,
+		"version":    op.Version,
 		"updated_at": op.UpdatedAt,
 	}
 }
@@ -264,6 +266,7 @@ func (op *SelfDeclaredAddressDataOperation) GetType() string {
 type SelfDeclaredEmailDataOperation struct {
 	CPF       string
 	Email     *models.Email
+	Version   int32
 	UpdatedAt time.Time
 }
 
@@ -282,6 +285,7 @@ func (op *SelfDeclaredEmailDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":        op.CPF,
 		"email":      op.Email,
+		"version":    op.Version,
 		"updated_at": op.UpdatedAt,
 	}
 }
@@ -300,6 +304,7 @@ func (op *SelfDeclaredEmailDataOperation) GetType() string {
 type SelfDeclaredPhoneDataOperation struct {
 	CPF       string
 	Telefone  *models.Telefone
+	Version   int32
 	UpdatedAt time.Time
 }
 
@@ -318,6 +323,7 @@ func (op *SelfDeclaredPhoneDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":        op.CPF,
 		"telefone":   op.Telefone,
+		"version":    op.Version,
 		"updated_at": op.UpdatedAt,
 	}
 }
@@ -336,6 +342,7 @@ func (op *SelfDeclaredPhoneDataOperation) GetType() string {
 type SelfDeclaredRacaDataOperation struct {
 	CPF       string
 	Raca      string
+	Version   int32
 	UpdatedAt time.Time
 }
 
@@ -354,6 +361,7 @@ func (op *SelfDeclaredRacaDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":        op.CPF,
 		"raca":       op.Raca,
+		"version":    op.Version,
 		"updated_at": op.UpdatedAt,
 	}
 }
@@ -372,6 +380,7 @@ func (op *SelfDeclaredRacaDataOperation) GetType() string {
 type SelfDeclaredNomeExibicaoDataOperation struct {
 	CPF          string
 	NomeExibicao string
+	Version      int32
 	UpdatedAt    time.Time
 }
 
@@ -390,6 +399,7 @@ func (op *SelfDeclaredNomeExibicaoDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":           op.CPF,
 		"nome_exibicao": op.NomeExibicao,
+		"version":       op.Version,
 		"updated_at":    op.UpdatedAt,
 	}
 }
@@ -408,6 +418,7 @@ func (op *SelfDeclaredNomeExibicaoDataOperation) GetType() string {
 type SelfDeclaredGeneroDataOperation struct {
 	CPF       string
 	Genero    string
+	Version   int32
 	UpdatedAt time.Time
 }
 
@@ -426,6 +437,7 @@ func (op *SelfDeclaredGeneroDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":        op.CPF,
 		"genero":     op.Genero,
+		"version":    op.Version,
 		"updated_at": op.UpdatedAt,
 	}
 }
@@ -444,6 +456,7 @@ func (op *SelfDeclaredGeneroDataOperation) GetType() string {
 type SelfDeclaredRendaFamiliarDataOperation struct {
 	CPF           string
 	RendaFamiliar string
+	Version       int32
 	UpdatedAt     time.Time
 }
 
@@ -462,6 +475,7 @@ func (op *SelfDeclaredRendaFamiliarDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":            op.CPF,
 		"renda_familiar": op.RendaFamiliar,
+		"version":        op.Version,
 		"updated_at":     op.UpdatedAt,
 	}
 }
@@ -480,6 +494,7 @@ func (op *SelfDeclaredRendaFamiliarDataOperation) GetType() string {
 type SelfDeclaredEscolaridadeDataOperation struct {
 	CPF          string
 	Escolaridade string
+	Version      int32
 	UpdatedAt    time.Time
 }
 
@@ -498,6 +513,7 @@ func (op *SelfDeclaredEscolaridadeDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":          op.CPF,
 		"escolaridade": op.Escolaridade,
+		"version":      op.Version,
 		"updated_at":   op.UpdatedAt,
 	}
 }
@@ -516,6 +532,7 @@ func (op *SelfDeclaredEscolaridadeDataOperation) GetType() string {
 type SelfDeclaredDeficienciaDataOperation struct {
 	CPF         string
 	Deficiencia string
+	Version     int32
 	UpdatedAt   time.Time
 }
 
@@ -534,6 +551,7 @@ func (op *SelfDeclaredDeficienciaDataOperation) GetData() interface{} {
 	return map[string]interface{}{
 		"cpf":         op.CPF,
 		"deficiencia": op.Deficiencia,
+		"version":     op.Version,
 		"updated_at":  op.UpdatedAt,
 	}
 }
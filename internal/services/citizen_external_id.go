@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrExternalIDSystemUnsupported is returned when looking up a system other than the indexed one
+	ErrExternalIDSystemUnsupported = errors.New("external ID system not supported")
+	// ErrExternalIDNotFound is returned when no citizen has the external ID
+	ErrExternalIDNotFound = errors.New("external ID not found")
+	// ErrExternalIDAmbiguous is returned when more than one citizen has the external ID
+	ErrExternalIDAmbiguous = errors.New("external ID matches more than one citizen")
+)
+
+// CitizenExternalIDService resolves IDs citizens have in other municipal systems to their CPF.
+// Only the system configured in CITIZEN_EXTERNAL_ID_SYSTEM is indexed, so only it can be resolved.
+type CitizenExternalIDService struct {
+	logger *logging.SafeLogger
+}
+
+// NewCitizenExternalIDService creates a new CitizenExternalIDService
+func NewCitizenExternalIDService(logger *logging.SafeLogger) *CitizenExternalIDService {
+	return &CitizenExternalIDService{
+		logger: logger,
+	}
+}
+
+// externalIDCacheKey is the Redis key holding the CPF an external ID resolves to
+func externalIDCacheKey(system, id string) string {
+	return fmt.Sprintf("external_id:%s:%s", system, id)
+}
+
+// ResolveCPF returns the CPF of the only citizen with the external ID, reading through a Redis
+// copy of the mapping. Missing and ambiguous IDs aren't cached, so fixing the source data takes
+// effect right away.
+func (s *CitizenExternalIDService) ResolveCPF(ctx context.Context, system, id string) (string, error) {
+	if system == "" || system != config.AppConfig.CitizenExternalIDSystem {
+		return "", ErrExternalIDSystemUnsupported
+	}
+
+	cacheKey := externalIDCacheKey(system, id)
+	if cpf, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil && cpf != "" {
+		return cpf, nil
+	}
+
+	// Cache miss - fetch from database; two matches are enough to detect a collision
+	collection := config.MongoDB.Collection(config.AppConfig.CitizenCollection)
+	cursor, err := collection.Find(ctx,
+		bson.M{"external_ids." + system: id},
+		options.Find().SetProjection(bson.M{"cpf": 1}).SetLimit(2),
+	)
+	if err != nil {
+		s.logger.Error("failed to resolve external ID", zap.Error(err), zap.String("system", system))
+		return "", fmt.Errorf("failed to resolve external ID: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var matches []models.Citizen
+	if err := cursor.All(ctx, &matches); err != nil {
+		return "", fmt.Errorf("failed to decode external ID matches: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", ErrExternalIDNotFound
+	case 1:
+	default:
+		s.logger.Warn("external ID matches more than one citizen",
+			zap.String("system", system),
+			zap.String("external_id", id))
+		return "", ErrExternalIDAmbiguous
+	}
+
+	cpf := matches[0].CPF
+	if err := config.Redis.Set(ctx, cacheKey, cpf, config.AppConfig.ExternalIDCacheTTL).Err(); err != nil {
+		s.logger.Warn("failed to cache external ID mapping", zap.Error(err), zap.String("system", system))
+	}
+	return cpf, nil
+}
+
+// Invalidate drops the cached mapping of an external ID, e.g. after it was found to be outdated
+func (s *CitizenExternalIDService) Invalidate(ctx context.Context, system, id string) {
+	if err := config.Redis.Del(ctx, externalIDCacheKey(system, id)).Err(); err != nil {
+		s.logger.Warn("failed to invalidate external ID mapping", zap.Error(err), zap.String("system", system))
+	}
+}
@@ -1,17 +1,227 @@
 package services
 
-import "github.com/prefeitura-rio/app-rmi/internal/models"
+import (
+	"context"
+	"time"
 
-// ConfigService provides configuration data for the application
-type ConfigService struct{}
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.uber.org/zap"
+)
+
+// Names of the static lists served by ConfigService
+const (
+	StaticListChannels         = "channels"
+	StaticListOptOutReasons    = "opt_out_reasons"
+	StaticListEthnicityOptions = "ethnicity_options"
+)
+
+// ethnicityOptionsSeedTimeout bounds seeding the ethnicity options on startup
+const ethnicityOptionsSeedTimeout = 10 * time.Second
+
+// ConfigService provides configuration data for the application. The lists it serves are
+// kept in memory and refreshed every STATIC_LISTS_REFRESH_INTERVAL once started; the
+// admin-editable ethnicity options are also refreshed as soon as any instance changes them.
+type ConfigService struct {
+	channels         *StaticListCache[*models.ChannelsResponse]
+	optOutReasons    *StaticListCache[*models.OptOutReasonsResponse]
+	ethnicityOptions *StaticListCache[[]string]
+	watchCancel      context.CancelFunc
+	logger           *logging.SafeLogger
+}
+
+// ConfigServiceInstance is the global config service instance
+var ConfigServiceInstance *ConfigService
+
+// InitConfigService initializes the global config service instance and starts refreshing its lists
+func InitConfigService() {
+	logger := logging.GetLogger()
+
+	// Seed the admin-editable ethnicity options from the built-in list on first boot
+	if config.MongoDB != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), ethnicityOptionsSeedTimeout)
+		if err := NewEthnicityOptionService(logger).Seed(ctx); err != nil {
+			logger.Warn("failed to seed ethnicity options, serving the built-in list", zap.Error(err))
+		}
+		cancel()
+	}
+
+	ConfigServiceInstance = NewConfigService()
+	ConfigServiceInstance.Start(config.AppConfig.StaticListsRefreshInterval)
+	logger.Info("config service initialized",
+		zap.Duration("refresh_interval", config.AppConfig.StaticListsRefreshInterval))
+}
 
 // NewConfigService creates a new ConfigService
 func NewConfigService() *ConfigService {
-	return &ConfigService{}
+	logger := logging.GetLogger()
+	return &ConfigService{
+		channels: NewStaticListCache(StaticListChannels, func(ctx context.Context) (*models.ChannelsResponse, error) {
+			return availableChannels(), nil
+		}, logger),
+		optOutReasons: NewStaticListCache(StaticListOptOutReasons, func(ctx context.Context) (*models.OptOutReasonsResponse, error) {
+			return optOutReasons(), nil
+		}, logger),
+		ethnicityOptions: NewStaticListCache(StaticListEthnicityOptions, func(ctx context.Context) ([]string, error) {
+			if config.MongoDB == nil || config.Redis == nil {
+				return models.ValidEthnicityOptions(), nil
+			}
+			return NewEthnicityOptionService(logger).List(ctx)
+		}, logger),
+		logger: logger,
+	}
+}
+
+// Start refreshes every list in the background every interval, and the ethnicity options whenever
+// they change
+func (s *ConfigService) Start(interval time.Duration) {
+	s.channels.Start(interval)
+	s.optOutReasons.Start(interval)
+	s.ethnicityOptions.Start(interval)
+
+	if config.Redis != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.watchCancel = cancel
+		go s.watchEthnicityOptions(ctx)
+	}
+}
+
+// Stop stops the background refreshes
+func (s *ConfigService) Stop() {
+	s.channels.Stop()
+	s.optOutReasons.Stop()
+	s.ethnicityOptions.Stop()
+	if s.watchCancel != nil {
+		s.watchCancel()
+	}
+}
+
+// watchEthnicityOptions refreshes the ethnicity options whenever an instance announces a change
+// on EthnicityOptionsChannel, until ctx ends
+func (s *ConfigService) watchEthnicityOptions(ctx context.Context) {
+	pubsub := config.Redis.Subscribe(ctx, EthnicityOptionsChannel)
+	if pubsub == nil {
+		s.logger.Warn("redis client does not support pub/sub, ethnicity options only refresh periodically")
+		return
+	}
+	defer pubsub.Close()
+
+	// The channel reconnects on its own when the connection drops
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			if err := s.RefreshEthnicityOptions(ctx); err != nil {
+				s.logger.Warn("failed to refresh changed ethnicity options, serving previous version", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RefreshEthnicityOptions reloads the ethnicity options right away
+func (s *ConfigService) RefreshEthnicityOptions(ctx context.Context) error {
+	refreshCtx, cancel := context.WithTimeout(ctx, staticListRefreshTimeout)
+	defer cancel()
+	return s.ethnicityOptions.Refresh(refreshCtx)
+}
+
+// LastRefresh returns when the named list was last loaded (zero if it never was or is unknown)
+func (s *ConfigService) LastRefresh(list string) time.Time {
+	switch list {
+	case StaticListChannels:
+		return s.channels.LastRefresh()
+	case StaticListOptOutReasons:
+		return s.optOutReasons.LastRefresh()
+	case StaticListEthnicityOptions:
+		return s.ethnicityOptions.LastRefresh()
+	default:
+		return time.Time{}
+	}
 }
 
 // GetAvailableChannels returns the list of available communication channels
 func (s *ConfigService) GetAvailableChannels() *models.ChannelsResponse {
+	channels, _, err := s.channels.Get(context.Background())
+	if err != nil {
+		s.logger.Error("failed to load channels", zap.Error(err))
+		return availableChannels()
+	}
+	return channels
+}
+
+// GetOptOutReasons returns the list of available opt-out reasons
+func (s *ConfigService) GetOptOutReasons() *models.OptOutReasonsResponse {
+	reasons, _, err := s.optOutReasons.Get(context.Background())
+	if err != nil {
+		s.logger.Error("failed to load opt-out reasons", zap.Error(err))
+		return optOutReasons()
+	}
+	return reasons
+}
+
+// IsValidOptOutReason reports whether code is one of the available opt-out reasons, falling back
+// to the built-in list when the config service isn't initialized
+func IsValidOptOutReason(code string) bool {
+	reasons := optOutReasons()
+	if ConfigServiceInstance != nil {
+		reasons = ConfigServiceInstance.GetOptOutReasons()
+	}
+	for _, reason := range reasons.Reasons {
+		if reason.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAvailableChannel reports whether code is one of the available communication channels, falling
+// back to the built-in list when the config service isn't initialized
+func IsAvailableChannel(code string) bool {
+	channels := availableChannels()
+	if ConfigServiceInstance != nil {
+		channels = ConfigServiceInstance.GetAvailableChannels()
+	}
+	for _, channel := range channels.Channels {
+		if channel.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEthnicityOptions returns the valid self-declared ethnicity options
+func (s *ConfigService) GetEthnicityOptions() []string {
+	options, _, err := s.ethnicityOptions.Get(context.Background())
+	if err != nil {
+		s.logger.Error("failed to load ethnicity options", zap.Error(err))
+		return models.ValidEthnicityOptions()
+	}
+	return options
+}
+
+// IsValidEthnicity reports whether value is one of the current ethnicity options, falling back to
+// the built-in list when the config service isn't initialized
+func IsValidEthnicity(value string) bool {
+	options := models.ValidEthnicityOptions()
+	if ConfigServiceInstance != nil {
+		options = ConfigServiceInstance.GetEthnicityOptions()
+	}
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// availableChannels builds the list of available communication channels
+func availableChannels() *models.ChannelsResponse {
 	return &models.ChannelsResponse{
 		Channels: []models.Channel{
 			{
@@ -30,8 +240,8 @@ func (s *ConfigService) GetAvailableChannels() *models.ChannelsResponse {
 	}
 }
 
-// GetOptOutReasons returns the list of available opt-out reasons
-func (s *ConfigService) GetOptOutReasons() *models.OptOutReasonsResponse {
+// optOutReasons builds the list of available opt-out reasons
+func optOutReasons() *models.OptOutReasonsResponse {
 	return &models.OptOutReasonsResponse{
 		Reasons: []models.OptOutReason{
 			{
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"testing"
 
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 )
 
@@ -231,3 +233,71 @@ func TestGetAvailableChannels_AllHaveRequiredFields(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEthnicityOptions(t *testing.T) {
+	service := NewConfigService()
+
+	if !service.LastRefresh(StaticListEthnicityOptions).IsZero() {
+		t.Error("LastRefresh() should be zero before the list is loaded")
+	}
+
+	options := service.GetEthnicityOptions()
+	if len(options) != len(models.ValidEthnicityOptions()) {
+		t.Errorf("GetEthnicityOptions() returned %d options, want %d", len(options), len(models.ValidEthnicityOptions()))
+	}
+	if service.LastRefresh(StaticListEthnicityOptions).IsZero() {
+		t.Error("LastRefresh() should be set after the list is loaded")
+	}
+	if !service.LastRefresh("unknown").IsZero() {
+		t.Error("LastRefresh() should be zero for unknown lists")
+	}
+}
+
+func TestIsAvailableChannel(t *testing.T) {
+	instance := ConfigServiceInstance
+	defer func() { ConfigServiceInstance = instance }()
+
+	// Without the config service, the built-in channels are accepted
+	ConfigServiceInstance = nil
+	for _, code := range []string{models.ChannelWhatsApp, models.ChannelWeb, models.ChannelMobile} {
+		if !IsAvailableChannel(code) {
+			t.Errorf("IsAvailableChannel(%q) = false, want true", code)
+		}
+	}
+	if IsAvailableChannel("sms") || IsAvailableChannel("") {
+		t.Error("IsAvailableChannel() should reject unknown channels")
+	}
+}
+
+func TestIsValidEthnicity(t *testing.T) {
+	instance := ConfigServiceInstance
+	defer func() { ConfigServiceInstance = instance }()
+
+	// Without the config service, the built-in options are accepted
+	ConfigServiceInstance = nil
+	if !IsValidEthnicity("parda") {
+		t.Error("IsValidEthnicity(\"parda\") = false, want true")
+	}
+	if IsValidEthnicity("quilombola") {
+		t.Error("IsValidEthnicity(\"quilombola\") = true, want false")
+	}
+
+	// With it, the current (admin-edited) options are
+	service := NewConfigService()
+	service.ethnicityOptions = NewStaticListCache(StaticListEthnicityOptions, func(ctx context.Context) ([]string, error) {
+		return []string{"parda", "quilombola"}, nil
+	}, logging.GetLogger())
+	ConfigServiceInstance = service
+	if !IsValidEthnicity("quilombola") {
+		t.Error("IsValidEthnicity(\"quilombola\") = false, want true after it was added")
+	}
+	if IsValidEthnicity("branca") {
+		t.Error("IsValidEthnicity(\"branca\") = true, want false after it was removed")
+	}
+}
+
+func TestNormalizeEthnicityOption(t *testing.T) {
+	if got := NormalizeEthnicityOption("  Quilombola "); got != "quilombola" {
+		t.Errorf("NormalizeEthnicityOption() = %q, want %q", got, "quilombola")
+	}
+}
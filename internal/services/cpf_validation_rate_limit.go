@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+// cpfValidationRateKey counts the CPF validation requests of a client IP within the rate window
+func cpfValidationRateKey(ip string) string {
+	return fmt.Sprintf("cpf_validation_rate:%s", ip)
+}
+
+// CPFValidationRetryAfter counts a CPF validation request from the client IP and returns how long
+// the IP has to wait when it went over CPF_VALIDATION_RATE_LIMIT within CPF_VALIDATION_RATE_WINDOW,
+// or 0 when the request is allowed. Rate limiting is disabled when the limit is 0.
+func CPFValidationRetryAfter(ctx context.Context, ip string) (time.Duration, error) {
+	if config.AppConfig.CPFValidationRateLimit <= 0 {
+		return 0, nil
+	}
+
+	// Same INCR + PEXPIRE window as the phone verification lockout
+	window := config.AppConfig.CPFValidationRateWindow
+	result, err := config.Redis.Eval(ctx, phoneVerificationFailureScript, []string{cpfValidationRateKey(ip)}, window.Milliseconds()).Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count CPF validation request: %w", err)
+	}
+	if len(result) < 2 {
+		return 0, fmt.Errorf("unexpected CPF validation rate script result: %v", result)
+	}
+
+	count, ok := result[0].(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected CPF validation request count: %v", result[0])
+	}
+	if count <= int64(config.AppConfig.CPFValidationRateLimit) {
+		return 0, nil
+	}
+
+	ttl, ok := result[1].(int64)
+	if !ok || ttl <= 0 {
+		// No expiry left to report; fall back to a full window
+		return window, nil
+	}
+	return time.Duration(ttl) * time.Millisecond, nil
+}
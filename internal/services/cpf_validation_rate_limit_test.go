@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+func TestCPFValidationRetryAfter(t *testing.T) {
+	setupTestEnvironment()
+	if config.Redis == nil {
+		t.Skip("Skipping CPF validation rate limit tests: Redis not available")
+	}
+
+	origLimit, origWindow := config.AppConfig.CPFValidationRateLimit, config.AppConfig.CPFValidationRateWindow
+	defer func() {
+		config.AppConfig.CPFValidationRateLimit, config.AppConfig.CPFValidationRateWindow = origLimit, origWindow
+	}()
+	config.AppConfig.CPFValidationRateLimit = 2
+	config.AppConfig.CPFValidationRateWindow = time.Minute
+
+	ctx := context.Background()
+	ip := "192.0.2.10"
+	config.Redis.Del(ctx, cpfValidationRateKey(ip))
+	defer config.Redis.Del(ctx, cpfValidationRateKey(ip))
+
+	for i := 1; i <= 2; i++ {
+		if retryAfter, err := CPFValidationRetryAfter(ctx, ip); err != nil || retryAfter != 0 {
+			t.Fatalf("request %d: CPFValidationRetryAfter() = (%v, %v), want allowed", i, retryAfter, err)
+		}
+	}
+
+	retryAfter, err := CPFValidationRetryAfter(ctx, ip)
+	if err != nil {
+		t.Fatalf("CPFValidationRetryAfter() error = %v", err)
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Errorf("CPFValidationRetryAfter() = %v, want within the window", retryAfter)
+	}
+
+	// Other IPs have their own window
+	if retryAfter, err := CPFValidationRetryAfter(ctx, "192.0.2.11"); err != nil || retryAfter != 0 {
+		t.Errorf("CPFValidationRetryAfter() for another IP = (%v, %v), want allowed", retryAfter, err)
+	}
+	config.Redis.Del(ctx, cpfValidationRateKey("192.0.2.11"))
+
+	// A limit of 0 disables rate limiting
+	config.AppConfig.CPFValidationRateLimit = 0
+	if retryAfter, err := CPFValidationRetryAfter(ctx, ip); err != nil || retryAfter != 0 {
+		t.Errorf("CPFValidationRetryAfter() with limit 0 = (%v, %v), want allowed", retryAfter, err)
+	}
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	// dataFreshnessReportKey holds the latest report, shared by every instance
+	dataFreshnessReportKey = "data_freshness:report"
+	// dataFreshnessLockKey makes a single instance compute the report each interval
+	dataFreshnessLockKey = "data_freshness:lock"
+	// dataFreshnessTimeout bounds a full report run
+	dataFreshnessTimeout = 5 * time.Minute
+)
+
+// DataFreshnessServiceInstance is the global data freshness service instance
+var DataFreshnessServiceInstance *DataFreshnessService
+
+// DataFreshnessService estimates how old the documents of each configured collection are, from
+// a random sample of their updated_at (or created_at) timestamps. Reports are computed on
+// DATA_FRESHNESS_INTERVAL by one instance, stored in Redis and exported by every instance as
+// the collection_data_age_seconds gauges.
+type DataFreshnessService struct {
+	logger   *logging.SafeLogger
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewDataFreshnessService creates a new DataFreshnessService
+func NewDataFreshnessService(logger *logging.SafeLogger) *DataFreshnessService {
+	return &DataFreshnessService{
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+}
+
+// InitDataFreshnessService initializes the global data freshness service instance and starts
+// the scheduled reports
+func InitDataFreshnessService(logger *logging.SafeLogger) {
+	DataFreshnessServiceInstance = NewDataFreshnessService(logger)
+	DataFreshnessServiceInstance.Start(config.AppConfig.DataFreshnessInterval)
+}
+
+// Start runs a report right away and then every interval, until Stop is called. A non-positive
+// interval disables the scheduled reports; GetReport still computes them on demand.
+func (s *DataFreshnessService) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(interval)
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduled reports
+func (s *DataFreshnessService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// runScheduled computes the report if no other instance did it this interval, then exports
+// the latest report as gauges
+func (s *DataFreshnessService) runScheduled(interval time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataFreshnessTimeout)
+	defer cancel()
+
+	acquired, err := config.Redis.SetNX(ctx, dataFreshnessLockKey, time.Now().Unix(), interval).Result()
+	if err != nil {
+		s.logger.Warn("failed to acquire data freshness lock", zap.Error(err))
+		return
+	}
+
+	var report *models.DataFreshnessReport
+	if acquired {
+		report, err = s.Generate(ctx)
+	} else {
+		report, err = s.cachedReport(ctx)
+	}
+	if err != nil {
+		s.logger.Warn("failed to load data freshness report", zap.Error(err))
+		return
+	}
+	if report != nil {
+		recordDataFreshnessMetrics(report)
+	}
+}
+
+// GetReport returns the latest report, computing one when none is stored
+func (s *DataFreshnessService) GetReport(ctx context.Context) (*models.DataFreshnessReport, error) {
+	report, err := s.cachedReport(ctx)
+	if err != nil {
+		s.logger.Warn("failed to read cached data freshness report", zap.Error(err))
+	}
+	if report != nil {
+		return report, nil
+	}
+	return s.Generate(ctx)
+}
+
+// Generate samples every configured collection, stores the report in Redis and updates the
+// gauges. Collections that fail are reported with their error instead of failing the report.
+func (s *DataFreshnessService) Generate(ctx context.Context) (*models.DataFreshnessReport, error) {
+	start := time.Now()
+	report := &models.DataFreshnessReport{
+		GeneratedAt: start,
+		SampleSize:  config.AppConfig.DataFreshnessSampleSize,
+		Collections: make([]models.CollectionFreshness, 0, len(config.AppConfig.DataFreshnessCollections)),
+	}
+
+	for _, collection := range config.AppConfig.DataFreshnessCollections {
+		freshness, err := s.collectionFreshness(ctx, collection, start)
+		if err != nil {
+			s.logger.Warn("failed to sample collection for data freshness",
+				zap.String("collection", collection), zap.Error(err))
+			freshness = models.CollectionFreshness{Collection: collection, Error: err.Error()}
+		}
+		report.Collections = append(report.Collections, freshness)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data freshness report: %w", err)
+	}
+	if err := config.Redis.Set(ctx, dataFreshnessReportKey, data, dataFreshnessReportTTL()).Err(); err != nil {
+		s.logger.Warn("failed to cache data freshness report", zap.Error(err))
+	}
+	recordDataFreshnessMetrics(report)
+
+	s.logger.Info("data freshness report generated",
+		zap.Int("collections", len(report.Collections)),
+		zap.Duration("duration", time.Since(start)))
+	return report, nil
+}
+
+// cachedReport returns the stored report, or nil when there is none
+func (s *DataFreshnessService) cachedReport(ctx context.Context) (*models.DataFreshnessReport, error) {
+	data, err := config.Redis.Get(ctx, dataFreshnessReportKey).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && data == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report models.DataFreshnessReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data freshness report: %w", err)
+	}
+	return &report, nil
+}
+
+// collectionFreshness samples a collection, reading from secondaries when available
+func (s *DataFreshnessService) collectionFreshness(ctx context.Context, collection string, now time.Time) (models.CollectionFreshness, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sample", Value: bson.M{"size": config.AppConfig.DataFreshnessSampleSize}}},
+		{{Key: "$project", Value: bson.M{
+			"_id": 0,
+			"ts":  bson.M{"$ifNull": bson.A{"$updated_at", "$created_at"}},
+		}}},
+	}
+
+	var timestamps []interface{}
+	err := utils.ExecuteReadWithLoadDistribution(ctx, collection, func(coll *mongo.Collection) error {
+		cursor, err := coll.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var doc struct {
+				TS interface{} `bson:"ts"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			timestamps = append(timestamps, doc.TS)
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return models.CollectionFreshness{}, err
+	}
+
+	return computeCollectionFreshness(collection, timestamps, now), nil
+}
+
+// computeCollectionFreshness builds a collection's age distribution from sampled timestamps
+func computeCollectionFreshness(collection string, timestamps []interface{}, now time.Time) models.CollectionFreshness {
+	freshness := models.CollectionFreshness{Collection: collection, Sampled: len(timestamps)}
+
+	ages := make([]float64, 0, len(timestamps))
+	for _, value := range timestamps {
+		ts, ok := utils.DocumentTimestamp(value)
+		if !ok {
+			freshness.Undated++
+			continue
+		}
+		// Clock skew can put a timestamp slightly in the future
+		ages = append(ages, math.Max(0, now.Sub(ts).Seconds()))
+	}
+	if len(ages) > 0 {
+		freshness.AgeSeconds = ageQuantiles(ages)
+	}
+	return freshness
+}
+
+// ageQuantiles returns the nearest-rank quantiles in models.DataFreshnessQuantiles of ages,
+// which must not be empty. ages is sorted in place.
+func ageQuantiles(ages []float64) map[string]float64 {
+	sort.Float64s(ages)
+
+	quantiles := make(map[string]float64, len(models.DataFreshnessQuantiles))
+	for _, label := range models.DataFreshnessQuantiles {
+		q, _ := strconv.ParseFloat(label, 64)
+		rank := int(math.Ceil(q*float64(len(ages)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		quantiles[label] = ages[rank]
+	}
+	return quantiles
+}
+
+// recordDataFreshnessMetrics exports a report as gauges; collections that failed keep their
+// previous values
+func recordDataFreshnessMetrics(report *models.DataFreshnessReport) {
+	for _, collection := range report.Collections {
+		for quantile, age := range collection.AgeSeconds {
+			observability.CollectionDataAgeSeconds.WithLabelValues(collection.Collection, quantile).Set(age)
+		}
+	}
+}
+
+// dataFreshnessReportTTL keeps a report for two intervals, so it survives a missed run
+func dataFreshnessReportTTL() time.Duration {
+	if interval := config.AppConfig.DataFreshnessInterval; interval > 0 {
+		return 2 * interval
+	}
+	return time.Hour
+}
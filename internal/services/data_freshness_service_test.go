@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAgeQuantiles(t *testing.T) {
+	ages := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		ages = append(ages, float64(i))
+	}
+
+	got := ageQuantiles(ages)
+	want := map[string]float64{"0.5": 50, "0.9": 90, "0.99": 99, "1": 100}
+	for quantile, age := range want {
+		if got[quantile] != age {
+			t.Errorf("ageQuantiles()[%s] = %v, want %v", quantile, got[quantile], age)
+		}
+	}
+
+	single := ageQuantiles([]float64{42})
+	for quantile, age := range single {
+		if age != 42 {
+			t.Errorf("ageQuantiles() of one age [%s] = %v, want 42", quantile, age)
+		}
+	}
+}
+
+func TestComputeCollectionFreshness(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	timestamps := []interface{}{
+		primitive.NewDateTimeFromTime(now.Add(-time.Hour)),
+		now.Add(-2 * time.Hour),
+		now.Add(-3 * time.Hour).Format(time.RFC3339),
+		now.Add(time.Minute), // clock skew
+		nil,
+		"not a date",
+	}
+
+	got := computeCollectionFreshness("citizens", timestamps, now)
+	if got.Collection != "citizens" || got.Sampled != 6 || got.Undated != 2 {
+		t.Fatalf("computeCollectionFreshness() = %+v, want 6 sampled and 2 undated", got)
+	}
+	if got.AgeSeconds["1"] != (3 * time.Hour).Seconds() {
+		t.Errorf("oldest age = %v, want %v", got.AgeSeconds["1"], (3 * time.Hour).Seconds())
+	}
+	if got.AgeSeconds["0.5"] != time.Hour.Seconds() {
+		t.Errorf("median age = %v, want %v", got.AgeSeconds["0.5"], time.Hour.Seconds())
+	}
+
+	empty := computeCollectionFreshness("maintenance_requests", []interface{}{nil}, now)
+	if empty.AgeSeconds != nil {
+		t.Errorf("AgeSeconds without dated documents = %v, want nil", empty.AgeSeconds)
+	}
+}
@@ -15,12 +15,36 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
 	"go.uber.org/zap"
 )
 
 // ErrDocumentNotFound is returned when a document is not found in the database
 var ErrDocumentNotFound = errors.New("document not found")
 
+// ErrNotCached is returned by ReadCacheOnly when the data is not in any Redis layer
+var ErrNotCached = errors.New("data not found in cache")
+
+// writeBufferTTL is how long a write stays in the Redis write buffer waiting for its sync job
+const writeBufferTTL = 6 * time.Hour
+
+// IsMongoUnavailable reports whether err means MongoDB could not be reached (open circuit breaker,
+// network failure or timeout), as opposed to a missing document or a query error.
+func IsMongoUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var serverSelectionErr topology.ServerSelectionError
+	return errors.Is(err, circuitbreaker.ErrCircuitOpen) ||
+		errors.Is(err, circuitbreaker.ErrTooManyRequests) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &serverSelectionErr) ||
+		mongo.IsNetworkError(err) ||
+		mongo.IsTimeout(err) ||
+		errors.Is(err, mongo.ErrClientDisconnected)
+}
+
 // DataOperation represents a generic data operation
 type DataOperation interface {
 	GetKey() string
@@ -90,7 +114,7 @@ func (dm *DataManager) Write(ctx context.Context, op DataOperation) error {
 	}
 
 	// Write to Redis with TTL (6 hours for write buffer - reduced to prevent long gaps)
-	err = dm.redis.Set(ctx, writeKey, string(dataBytes), 6*time.Hour).Err()
+	err = dm.redis.Set(ctx, writeKey, string(dataBytes), writeBufferTTL).Err()
 	if err != nil {
 		return fmt.Errorf("failed to write to Redis buffer: %w", err)
 	}
@@ -129,85 +153,13 @@ func (dm *DataManager) Write(ctx context.Context, op DataOperation) error {
 
 // Read reads data from cache layers, falling back to MongoDB
 func (dm *DataManager) Read(ctx context.Context, key string, collection string, dataType string, result interface{}) error {
-	// 1. Check Redis write buffer first (most recent data)
-	writeKey := fmt.Sprintf("%s:write:%s", dataType, key)
-	dm.logger.Debug("attempting to read from write buffer",
-		zap.String("type", dataType),
-		zap.String("key", key))
-
-	if data, err := dm.redis.Get(ctx, writeKey).Result(); err == nil {
-		dataStr := string(data)
-		if len(dataStr) > 100 {
-			dataStr = dataStr[:100] + "..."
-		}
-		dm.logger.Debug("found data in write buffer",
-			zap.String("type", dataType),
-			zap.String("key", key),
-			zap.String("data_preview", dataStr))
-
-		if err := json.Unmarshal([]byte(data), result); err == nil {
-			dm.logger.Debug("successfully read from write buffer",
-				zap.String("type", dataType),
-				zap.String("key", key))
-			return nil
-		} else {
-			dm.logger.Warn("failed to unmarshal data from write buffer",
-				zap.String("type", dataType),
-				zap.String("key", key),
-				zap.Error(err))
-		}
-	} else {
-		dm.logger.Debug("write buffer miss, checking read cache",
-			zap.String("type", dataType),
-			zap.String("key", key))
-	}
-
-	// 2. Check Redis read cache
-	cacheKey := fmt.Sprintf("%s:cache:%s", dataType, key)
-	if data, err := dm.redis.Get(ctx, cacheKey).Result(); err == nil {
-		if err := json.Unmarshal([]byte(data), result); err == nil {
-			dm.logger.Debug("data read from cache",
-				zap.String("type", dataType),
-				zap.String("key", key))
-			return nil
-		} else {
-			dm.logger.Warn("failed to unmarshal data from read cache",
-				zap.String("type", dataType),
-				zap.String("key", key),
-				zap.Error(err))
-		}
-	} else {
-		dm.logger.Debug("read cache miss, falling back to MongoDB",
-			zap.String("type", dataType),
-			zap.String("key", key))
+	// 1-2. Check Redis write buffer and read cache
+	if dm.readFromCache(ctx, key, dataType, result) {
+		return nil
 	}
 
 	// 3. Fall back to MongoDB
-	// Use appropriate filter based on collection type using config values
-	var filter bson.M
-	switch collection {
-	case config.AppConfig.CitizenCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.SelfDeclaredCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.UserConfigCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.PhoneMappingCollection:
-		filter = bson.M{"phone": key}
-	case config.AppConfig.OptInHistoryCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.BetaGroupCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.PhoneVerificationCollection:
-		filter = bson.M{"phone": key}
-	case config.AppConfig.MaintenanceRequestCollection:
-		filter = bson.M{"cpf": key}
-	case config.AppConfig.DepartmentCollection:
-		filter = bson.M{"cd_ua": key}
-	default:
-		// Default to _id for other collections
-		filter = bson.M{"_id": key}
-	}
+	filter := bson.M{keyFieldForCollection(collection): key}
 
 	dm.logger.Debug("querying MongoDB",
 		zap.String("type", dataType),
@@ -215,10 +167,19 @@ func (dm *DataManager) Read(ctx context.Context, key string, collection string,
 		zap.String("collection", collection),
 		zap.Any("filter", filter))
 
+	// Read-after-write contexts (utils.WithReadAfterWrite) go to the primary; other reads use the
+	// collection's configured read preference
+	coll := dm.mongo.Collection(collection)
+	pref := utils.ReadPreferenceFor(ctx, collection)
+	if pref != nil {
+		coll = dm.mongo.Collection(collection, options.Collection().SetReadPreference(pref))
+	}
+	utils.RecordMongoRead(collection, pref)
+
 	// Execute MongoDB query with circuit breaker and retry logic
 	_, err := dm.circuitBreaker.Execute(ctx, func() (interface{}, error) {
 		return nil, retry.WithExponentialBackoff(ctx, dm.retryConfig, func() error {
-			return dm.mongo.Collection(collection).FindOne(ctx, filter).Decode(result)
+			return coll.FindOne(ctx, filter).Decode(result)
 		})
 	})
 
@@ -280,6 +241,121 @@ func (dm *DataManager) Read(ctx context.Context, key string, collection string,
 	return nil
 }
 
+// keyFieldForCollection returns the field that identifies a document by its key in the given
+// collection, defaulting to _id
+func keyFieldForCollection(collection string) string {
+	switch collection {
+	case config.AppConfig.CitizenCollection:
+		return "cpf"
+	case config.AppConfig.SelfDeclaredCollection:
+		return "cpf"
+	case config.AppConfig.UserConfigCollection:
+		return "cpf"
+	case config.AppConfig.PhoneMappingCollection:
+		return "phone"
+	case config.AppConfig.OptInHistoryCollection:
+		return "cpf"
+	case config.AppConfig.BetaGroupCollection:
+		return "cpf"
+	case config.AppConfig.PhoneVerificationCollection:
+		return "phone"
+	case config.AppConfig.MaintenanceRequestCollection:
+		return "cpf"
+	case config.AppConfig.DepartmentCollection:
+		return "cd_ua"
+	default:
+		// Default to _id for other collections
+		return "_id"
+	}
+}
+
+// documentUpdate returns the upsert writing doc, a whole document converted through JSON, to
+// collection. Self-declared and user config documents follow the models.Timestamps policy: their
+// created_at is only set on insert and updated_at is stamped with the time of the write.
+func documentUpdate(collection string, doc bson.M) bson.M {
+	switch collection {
+	case config.AppConfig.SelfDeclaredCollection, config.AppConfig.UserConfigCollection:
+		return utils.TimestampedUpdate(doc, documentWriteTime(doc))
+	}
+	return bson.M{"$set": doc}
+}
+
+// documentWriteTime returns when a buffered write was made: the updated_at of its data, or now
+// for data without one
+func documentWriteTime(data bson.M) time.Time {
+	if ts, ok := utils.DocumentTimestamp(data["updated_at"]); ok && !ts.IsZero() {
+		return ts
+	}
+	return time.Now()
+}
+
+// ReadCacheOnly reads data from the Redis write buffer and read cache without touching MongoDB.
+// It returns ErrNotCached when neither layer holds the data.
+func (dm *DataManager) ReadCacheOnly(ctx context.Context, key string, dataType string, result interface{}) error {
+	if dm.readFromCache(ctx, key, dataType, result) {
+		return nil
+	}
+	return ErrNotCached
+}
+
+// readFromCache reads data from the Redis write buffer, then the read cache
+func (dm *DataManager) readFromCache(ctx context.Context, key string, dataType string, result interface{}) bool {
+	// 1. Check Redis write buffer first (most recent data)
+	writeKey := fmt.Sprintf("%s:write:%s", dataType, key)
+	dm.logger.Debug("attempting to read from write buffer",
+		zap.String("type", dataType),
+		zap.String("key", key))
+
+	if data, err := dm.redis.Get(ctx, writeKey).Result(); err == nil {
+		dataStr := string(data)
+		if len(dataStr) > 100 {
+			dataStr = dataStr[:100] + "..."
+		}
+		dm.logger.Debug("found data in write buffer",
+			zap.String("type", dataType),
+			zap.String("key", key),
+			zap.String("data_preview", dataStr))
+
+		if err := json.Unmarshal([]byte(data), result); err == nil {
+			dm.logger.Debug("successfully read from write buffer",
+				zap.String("type", dataType),
+				zap.String("key", key))
+			return true
+		} else {
+			dm.logger.Warn("failed to unmarshal data from write buffer",
+				zap.String("type", dataType),
+				zap.String("key", key),
+				zap.Error(err))
+		}
+	} else {
+		dm.logger.Debug("write buffer miss, checking read cache",
+			zap.String("type", dataType),
+			zap.String("key", key))
+	}
+
+	// 2. Check Redis read cache
+	cacheKey := fmt.Sprintf("%s:cache:%s", dataType, key)
+	if data, err := dm.redis.Get(ctx, cacheKey).Result(); err == nil {
+		if err := json.Unmarshal([]byte(data), result); err == nil {
+			dm.logger.Debug("data read from cache",
+				zap.String("type", dataType),
+				zap.String("key", key))
+			return true
+		} else {
+			dm.logger.Warn("failed to unmarshal data from read cache",
+				zap.String("type", dataType),
+				zap.String("key", key),
+				zap.Error(err))
+		}
+	} else {
+		dm.logger.Debug("read cache miss",
+			zap.String("type", dataType),
+			zap.String("key", key))
+	}
+
+	return false
+}
+
 // Delete removes data from all cache layers and MongoDB
 func (dm *DataManager) Delete(ctx context.Context, key string, collection string, dataType string) error {
 	// 1. Remove from Redis write buffer
@@ -1,8 +1,15 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/circuitbreaker"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
 )
 
 func TestDataOperationInterface(t *testing.T) {
@@ -73,3 +80,28 @@ func TestPhoneMappingDataOperation(t *testing.T) {
 		t.Errorf("Expected TTL 24h, got %v", op.GetTTL())
 	}
 }
+
+func TestIsMongoUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"circuit open", fmt.Errorf("database temporarily unavailable: %w", circuitbreaker.ErrCircuitOpen), true},
+		{"too many requests", circuitbreaker.ErrTooManyRequests, true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"server selection", topology.ServerSelectionError{Wrapped: errors.New("no reachable servers")}, true},
+		{"client disconnected", mongo.ErrClientDisconnected, true},
+		{"not found", ErrDocumentNotFound, false},
+		{"no documents", mongo.ErrNoDocuments, false},
+		{"other", errors.New("invalid filter"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMongoUnavailable(tt.err); got != tt.want {
+				t.Errorf("IsMongoUnavailable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// EmailVerificationWebhookName labels email verification deliveries in the webhook metrics
+const EmailVerificationWebhookName = "email_verification"
+
+// ErrEmailVerificationNotFound is returned when no unexpired verification matches the token
+var ErrEmailVerificationNotFound = errors.New("email verification not found or expired")
+
+// EmailVerificationSender delivers a verification token to the citizen's email address
+type EmailVerificationSender interface {
+	Send(ctx context.Context, verification *models.EmailVerification) error
+}
+
+// webhookEmailVerificationSender posts the token to a signed webhook that sends the email
+type webhookEmailVerificationSender struct {
+	url     string
+	secret  string
+	timeout time.Duration
+}
+
+func (s *webhookEmailVerificationSender) Send(ctx context.Context, verification *models.EmailVerification) error {
+	payload := models.EmailVerificationWebhookPayload{
+		Event:            models.EmailVerificationRequestedWebhookEvent,
+		CPF:              verification.CPF,
+		Email:            verification.Email,
+		Token:            verification.Token,
+		Link:             verification.Link,
+		PreferredChannel: GetPreferredChannel(ctx, verification.CPF),
+		ExpiresAt:        verification.ExpiresAt,
+	}
+	return deliverWebhook(ctx, EmailVerificationWebhookName, s.url, s.secret, s.timeout, payload)
+}
+
+// logEmailVerificationSender only logs the token; meant for development environments
+type logEmailVerificationSender struct {
+	logger *logging.SafeLogger
+}
+
+func (s *logEmailVerificationSender) Send(ctx context.Context, verification *models.EmailVerification) error {
+	s.logger.Info("email verification token generated",
+		zap.String("cpf", verification.CPF),
+		zap.String("email", verification.Email),
+		zap.String("token", verification.Token),
+		zap.String("link", verification.Link),
+		zap.Time("expires_at", verification.ExpiresAt))
+	return nil
+}
+
+// NewEmailVerificationSender creates the sender for the given channel (see EMAIL_VERIFICATION_CHANNEL)
+func NewEmailVerificationSender(channel string, logger *logging.SafeLogger) (EmailVerificationSender, error) {
+	switch channel {
+	case config.EmailVerificationChannelWebhook:
+		return &webhookEmailVerificationSender{
+			url:     config.AppConfig.EmailVerificationWebhookURL,
+			secret:  config.AppConfig.EmailVerificationWebhookSecret,
+			timeout: config.AppConfig.EmailVerificationWebhookTimeout,
+		}, nil
+	case config.EmailVerificationChannelLog:
+		return &logEmailVerificationSender{logger: logger}, nil
+	default:
+		return nil, fmt.Errorf("unknown email verification channel: %q", channel)
+	}
+}
+
+// Global email verification sender
+var EmailVerificationSenderInstance EmailVerificationSender
+
+// InitEmailVerificationSender initializes the global email verification sender
+func InitEmailVerificationSender() {
+	logger := logging.GetLogger()
+	if !config.AppConfig.EmailVerificationEnabled {
+		logger.Info("email verification disabled, self-declared emails are stored immediately")
+		return
+	}
+
+	sender, err := NewEmailVerificationSender(config.AppConfig.EmailVerificationChannel, logger)
+	if err != nil {
+		logger.Error("failed to initialize email verification sender", zap.Error(err))
+		return
+	}
+	EmailVerificationSenderInstance = sender
+	logger.Info("email verification sender initialized", zap.String("channel", config.AppConfig.EmailVerificationChannel))
+}
+
+// StartEmailVerification replaces any previous verification for the CPF, sends a new token and
+// stores the email as pending in the self-declared data until it is validated.
+func StartEmailVerification(ctx context.Context, cpf string, email *models.Email) error {
+	if EmailVerificationSenderInstance == nil {
+		return fmt.Errorf("email verification sender not initialized")
+	}
+	if email == nil || email.Principal == nil || email.Principal.Valor == nil {
+		return fmt.Errorf("email is required")
+	}
+
+	token, err := utils.GenerateVerificationToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	verification := &models.EmailVerification{
+		CPF:       cpf,
+		Email:     *email.Principal.Valor,
+		Token:     token,
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.AppConfig.EmailVerificationTTL),
+	}
+	if config.AppConfig.EmailVerificationLinkURL != "" {
+		if err := attachEmailVerificationLink(verification); err != nil {
+			return err
+		}
+	}
+
+	collection := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection)
+	if _, err := collection.DeleteMany(ctx, bson.M{"cpf": cpf}); err != nil {
+		logging.GetLogger().Warn("failed to delete previous email verifications", zap.String("cpf", cpf), zap.Error(err))
+	}
+
+	// Send first so a delivery failure doesn't leave an unusable record behind
+	if err := EmailVerificationSenderInstance.Send(ctx, verification); err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+
+	if _, err := collection.InsertOne(ctx, verification); err != nil {
+		return fmt.Errorf("failed to create email verification record: %w", err)
+	}
+
+	_, err = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).UpdateOne(
+		ctx,
+		bson.M{"cpf": cpf},
+		utils.TimestampedUpdate(bson.M{"email_pending": email}, now),
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pending email: %w", err)
+	}
+	return nil
+}
+
+// FindEmailVerification returns the unexpired verification for the CPF, email and token
+func FindEmailVerification(ctx context.Context, cpf, email, token string) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).FindOne(
+		ctx,
+		bson.M{
+			"cpf":        cpf,
+			"email":      email,
+			"token":      token,
+			"expires_at": bson.M{"$gt": time.Now()},
+		},
+	).Decode(&verification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrEmailVerificationNotFound
+		}
+		return nil, fmt.Errorf("failed to find email verification: %w", err)
+	}
+	return &verification, nil
+}
+
+// CompleteEmailVerification removes the verification record and the pending email once the
+// verified email has been stored.
+func CompleteEmailVerification(ctx context.Context, cpf, email string) error {
+	_, err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).DeleteOne(
+		ctx,
+		bson.M{"cpf": cpf, "email": email},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete email verification: %w", err)
+	}
+
+	_, err = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).UpdateOne(
+		ctx,
+		bson.M{"cpf": cpf},
+		bson.M{"$unset": bson.M{"email_pending": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear pending email: %w", err)
+	}
+	return nil
+}
+
+// attachEmailVerificationLink generates the magic link for a verification. Only the hash of its
+// nonce is stored, so the link can't be rebuilt from the database.
+func attachEmailVerificationLink(verification *models.EmailVerification) error {
+	nonce, err := utils.GenerateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(config.AppConfig.EmailVerificationLinkURL)
+	if err != nil {
+		return fmt.Errorf("invalid email verification link URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", signEmailVerificationLinkToken(nonce))
+	link.RawQuery = query.Encode()
+
+	verification.LinkTokenHash = hashEmailVerificationLinkNonce(nonce)
+	verification.Link = link.String()
+	return nil
+}
+
+// signEmailVerificationLinkToken returns the link token for a nonce: the nonce followed by its
+// HMAC-SHA256 under EMAIL_VERIFICATION_LINK_SECRET
+func signEmailVerificationLinkToken(nonce string) string {
+	return nonce + "." + emailVerificationLinkSignature(nonce)
+}
+
+// verifyEmailVerificationLinkToken checks a link token's signature in constant time and returns
+// its nonce
+func verifyEmailVerificationLinkToken(token string) (string, bool) {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(signature), []byte(emailVerificationLinkSignature(nonce))) {
+		return "", false
+	}
+	return nonce, true
+}
+
+func emailVerificationLinkSignature(nonce string) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.EmailVerificationLinkSecret))
+	mac.Write([]byte(nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hashEmailVerificationLinkNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConsumeEmailVerificationLink validates a magic link token and removes its verification, so each
+// link can be used only once. Unsigned, unknown, expired and already used tokens all return
+// ErrEmailVerificationNotFound.
+func ConsumeEmailVerificationLink(ctx context.Context, token string) (*models.EmailVerification, error) {
+	if config.AppConfig.EmailVerificationLinkSecret == "" {
+		return nil, ErrEmailVerificationNotFound
+	}
+	nonce, ok := verifyEmailVerificationLinkToken(token)
+	if !ok {
+		return nil, ErrEmailVerificationNotFound
+	}
+
+	var verification models.EmailVerification
+	err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).FindOneAndDelete(
+		ctx,
+		bson.M{
+			"link_token_hash": hashEmailVerificationLinkNonce(nonce),
+			"expires_at":      bson.M{"$gt": time.Now()},
+		},
+	).Decode(&verification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrEmailVerificationNotFound
+		}
+		return nil, fmt.Errorf("failed to consume email verification link: %w", err)
+	}
+	return &verification, nil
+}
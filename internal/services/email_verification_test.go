@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// capturingEmailVerificationSender records the verifications it was asked to send
+type capturingEmailVerificationSender struct {
+	sent []models.EmailVerification
+	err  error
+}
+
+func (s *capturingEmailVerificationSender) Send(ctx context.Context, verification *models.EmailVerification) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, *verification)
+	return nil
+}
+
+func TestNewEmailVerificationSender(t *testing.T) {
+	sender, err := NewEmailVerificationSender(config.EmailVerificationChannelWebhook, &logging.SafeLogger{})
+	require.NoError(t, err)
+	assert.IsType(t, &webhookEmailVerificationSender{}, sender)
+
+	sender, err = NewEmailVerificationSender(config.EmailVerificationChannelLog, &logging.SafeLogger{})
+	require.NoError(t, err)
+	assert.IsType(t, &logEmailVerificationSender{}, sender)
+
+	_, err = NewEmailVerificationSender("sms", &logging.SafeLogger{})
+	assert.Error(t, err)
+}
+
+func setupEmailVerificationTest(t *testing.T) (*capturingEmailVerificationSender, func()) {
+	if config.MongoDB == nil {
+		t.Skip("MongoDB not initialized")
+	}
+
+	ctx := context.Background()
+	originalVerifications, originalSelfDeclared := config.AppConfig.EmailVerificationCollection, config.AppConfig.SelfDeclaredCollection
+	originalTTL, originalSender := config.AppConfig.EmailVerificationTTL, EmailVerificationSenderInstance
+	config.AppConfig.EmailVerificationCollection = "test_email_verifications"
+	config.AppConfig.SelfDeclaredCollection = "test_email_verification_self_declared"
+	config.AppConfig.EmailVerificationTTL = time.Hour
+
+	sender := &capturingEmailVerificationSender{}
+	EmailVerificationSenderInstance = sender
+
+	return sender, func() {
+		_ = config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).Drop(ctx)
+		_ = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).Drop(ctx)
+		config.AppConfig.EmailVerificationCollection, config.AppConfig.SelfDeclaredCollection = originalVerifications, originalSelfDeclared
+		config.AppConfig.EmailVerificationTTL, EmailVerificationSenderInstance = originalTTL, originalSender
+	}
+}
+
+func pendingEmail(valor string) *models.Email {
+	return &models.Email{
+		Indicador: utils.BoolPtr(false),
+		Principal: &models.EmailPrincipal{Valor: &valor},
+	}
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	sender, cleanup := setupEmailVerificationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "12345678909"
+
+	require.NoError(t, StartEmailVerification(ctx, cpf, pendingEmail("old@example.com")))
+	require.NoError(t, StartEmailVerification(ctx, cpf, pendingEmail("new@example.com")))
+	require.Len(t, sender.sent, 2)
+
+	// Starting again replaces the previous verification
+	count, err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).CountDocuments(ctx, bson.M{"cpf": cpf})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, err = FindEmailVerification(ctx, cpf, "old@example.com", sender.sent[0].Token)
+	assert.ErrorIs(t, err, ErrEmailVerificationNotFound)
+	_, err = FindEmailVerification(ctx, cpf, "new@example.com", "wrong-token")
+	assert.ErrorIs(t, err, ErrEmailVerificationNotFound)
+
+	verification, err := FindEmailVerification(ctx, cpf, "new@example.com", sender.sent[1].Token)
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", verification.Email)
+
+	// The email is kept pending, never as the self-declared email
+	var selfDeclared models.SelfDeclaredData
+	require.NoError(t, config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).FindOne(ctx, bson.M{"cpf": cpf}).Decode(&selfDeclared))
+	assert.Nil(t, selfDeclared.Email)
+	require.NotNil(t, selfDeclared.EmailPending)
+	assert.Equal(t, "new@example.com", *selfDeclared.EmailPending.Principal.Valor)
+
+	require.NoError(t, CompleteEmailVerification(ctx, cpf, "new@example.com"))
+	_, err = FindEmailVerification(ctx, cpf, "new@example.com", sender.sent[1].Token)
+	assert.ErrorIs(t, err, ErrEmailVerificationNotFound)
+
+	selfDeclared = models.SelfDeclaredData{}
+	require.NoError(t, config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).FindOne(ctx, bson.M{"cpf": cpf}).Decode(&selfDeclared))
+	assert.Nil(t, selfDeclared.EmailPending)
+}
+
+func TestStartEmailVerification_SendFailure(t *testing.T) {
+	sender, cleanup := setupEmailVerificationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cpf := "12345678909"
+	sender.err = errors.New("webhook unavailable")
+
+	assert.Error(t, StartEmailVerification(ctx, cpf, pendingEmail("maria@example.com")))
+
+	// Nothing is stored when the token could not be sent
+	count, err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).CountDocuments(ctx, bson.M{"cpf": cpf})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestEmailVerificationLinkToken(t *testing.T) {
+	originalSecret := config.AppConfig.EmailVerificationLinkSecret
+	config.AppConfig.EmailVerificationLinkSecret = "link-secret"
+	defer func() { config.AppConfig.EmailVerificationLinkSecret = originalSecret }()
+
+	token := signEmailVerificationLinkToken("abc123")
+	nonce, ok := verifyEmailVerificationLinkToken(token)
+	assert.True(t, ok)
+	assert.Equal(t, "abc123", nonce)
+
+	// Tampered nonces, missing signatures and tokens signed with another secret are rejected
+	_, ok = verifyEmailVerificationLinkToken("abc124" + token[len("abc123"):])
+	assert.False(t, ok)
+	_, ok = verifyEmailVerificationLinkToken("abc123")
+	assert.False(t, ok)
+	config.AppConfig.EmailVerificationLinkSecret = "other-secret"
+	_, ok = verifyEmailVerificationLinkToken(token)
+	assert.False(t, ok)
+}
+
+func TestEmailVerificationLinkFlow(t *testing.T) {
+	sender, cleanup := setupEmailVerificationTest(t)
+	defer cleanup()
+
+	originalURL, originalSecret := config.AppConfig.EmailVerificationLinkURL, config.AppConfig.EmailVerificationLinkSecret
+	config.AppConfig.EmailVerificationLinkURL = "https://api.example.com/v1/verify/email"
+	config.AppConfig.EmailVerificationLinkSecret = "link-secret"
+	defer func() {
+		config.AppConfig.EmailVerificationLinkURL, config.AppConfig.EmailVerificationLinkSecret = originalURL, originalSecret
+	}()
+
+	ctx := context.Background()
+	cpf := "12345678909"
+
+	require.NoError(t, StartEmailVerification(ctx, cpf, pendingEmail("maria@example.com")))
+	require.Len(t, sender.sent, 1)
+
+	link, err := url.Parse(sender.sent[0].Link)
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/verify/email", link.Path)
+	token := link.Query().Get("token")
+	require.NotEmpty(t, token)
+
+	// Only the hash of the link nonce is stored
+	var stored models.EmailVerification
+	require.NoError(t, config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection).FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored))
+	assert.NotEmpty(t, stored.LinkTokenHash)
+	assert.NotContains(t, token, stored.LinkTokenHash)
+
+	_, err = ConsumeEmailVerificationLink(ctx, token+"x")
+	assert.ErrorIs(t, err, ErrEmailVerificationNotFound)
+
+	verification, err := ConsumeEmailVerificationLink(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, cpf, verification.CPF)
+	assert.Equal(t, "maria@example.com", verification.Email)
+
+	// Links are single-use
+	_, err = ConsumeEmailVerificationLink(ctx, token)
+	assert.ErrorIs(t, err, ErrEmailVerificationNotFound)
+}
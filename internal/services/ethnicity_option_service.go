@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// ethnicityOptionsCacheKey holds the Redis copy of the ethnicity options
+	ethnicityOptionsCacheKey = "ethnicity_options"
+	// EthnicityOptionsChannel is the Redis pub/sub channel announcing that the ethnicity options
+	// changed, so every instance refreshes its in-memory copy
+	EthnicityOptionsChannel = "ethnicity_options:changed"
+)
+
+var (
+	// ErrEthnicityOptionExists is returned when adding an option that already exists
+	ErrEthnicityOptionExists = errors.New("ethnicity option already exists")
+	// ErrEthnicityOptionNotFound is returned when removing an option that doesn't exist
+	ErrEthnicityOptionNotFound = errors.New("ethnicity option not found")
+	// ErrLastEthnicityOption is returned when removing the only remaining option
+	ErrLastEthnicityOption = errors.New("cannot remove the last ethnicity option")
+)
+
+// EthnicityOptionService manages the admin-editable ethnicity options, stored in MongoDB with a
+// read-through Redis copy. An empty collection serves the built-in options.
+type EthnicityOptionService struct {
+	logger *logging.SafeLogger
+}
+
+// NewEthnicityOptionService creates a new EthnicityOptionService
+func NewEthnicityOptionService(logger *logging.SafeLogger) *EthnicityOptionService {
+	return &EthnicityOptionService{
+		logger: logger,
+	}
+}
+
+// NormalizeEthnicityOption trims and lowercases an option value, the form options are stored in
+func NormalizeEthnicityOption(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// List returns the option values in display order, reading through the Redis copy
+func (s *EthnicityOptionService) List(ctx context.Context) ([]string, error) {
+	cachedData, err := config.Redis.Get(ctx, ethnicityOptionsCacheKey).Result()
+	if err == nil && cachedData != "" {
+		var cached []string
+		if err := json.Unmarshal([]byte(cachedData), &cached); err == nil {
+			return cached, nil
+		}
+		s.logger.Warn("failed to unmarshal cached ethnicity options", zap.Error(err))
+	}
+
+	// Cache miss - fetch from database
+	stored, err := s.find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(stored))
+	for _, option := range stored {
+		values = append(values, option.Value)
+	}
+	if len(values) == 0 {
+		// Not seeded yet
+		return models.ValidEthnicityOptions(), nil
+	}
+
+	if jsonData, err := json.Marshal(values); err == nil {
+		config.Redis.Set(ctx, ethnicityOptionsCacheKey, jsonData, config.AppConfig.EthnicityOptionsCacheTTL)
+	}
+	return values, nil
+}
+
+// find reads the stored options in display order
+func (s *EthnicityOptionService) find(ctx context.Context) ([]models.EthnicityOption, error) {
+	collection := config.MongoDB.Collection(config.AppConfig.EthnicityOptionCollection)
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		s.logger.Error("failed to list ethnicity options", zap.Error(err))
+		return nil, fmt.Errorf("failed to list ethnicity options: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	stored := []models.EthnicityOption{}
+	if err := cursor.All(ctx, &stored); err != nil {
+		s.logger.Error("failed to decode ethnicity options", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode ethnicity options: %w", err)
+	}
+	return stored, nil
+}
+
+// Seed stores the built-in options when the collection is empty, so admins edit a copy of them.
+// Instances booting together may both seed; duplicates are ignored.
+func (s *EthnicityOptionService) Seed(ctx context.Context) error {
+	collection := config.MongoDB.Collection(config.AppConfig.EthnicityOptionCollection)
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to count ethnicity options: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	defaults := models.ValidEthnicityOptions()
+	documents := make([]interface{}, 0, len(defaults))
+	for i, value := range defaults {
+		documents = append(documents, models.EthnicityOption{Value: value, Order: i + 1, CreatedAt: now})
+	}
+
+	_, err = collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to seed ethnicity options: %w", err)
+	}
+
+	s.logger.Info("seeded ethnicity options", zap.Int("count", len(defaults)))
+	return nil
+}
+
+// Add appends an option after the existing ones and returns it
+func (s *EthnicityOptionService) Add(ctx context.Context, value string) (*models.EthnicityOption, error) {
+	value = NormalizeEthnicityOption(value)
+	if value == "" {
+		return nil, fmt.Errorf("ethnicity option cannot be empty")
+	}
+
+	// Seed first so the new option is added to the built-in ones instead of replacing them
+	if err := s.Seed(ctx); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order := 1
+	for _, option := range stored {
+		if option.Order >= order {
+			order = option.Order + 1
+		}
+	}
+
+	option := models.EthnicityOption{Value: value, Order: order, CreatedAt: time.Now()}
+	_, err = config.MongoDB.Collection(config.AppConfig.EthnicityOptionCollection).InsertOne(ctx, option)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrEthnicityOptionExists
+	}
+	if err != nil {
+		s.logger.Error("failed to add ethnicity option", zap.Error(err), zap.String("value", value))
+		return nil, fmt.Errorf("failed to add ethnicity option: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("added ethnicity option", zap.String("value", value))
+	return &option, nil
+}
+
+// Remove deletes an option. Citizens who already declared it keep their value.
+func (s *EthnicityOptionService) Remove(ctx context.Context, value string) error {
+	value = NormalizeEthnicityOption(value)
+
+	if err := s.Seed(ctx); err != nil {
+		return err
+	}
+
+	stored, err := s.find(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, option := range stored {
+		if option.Value == value {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrEthnicityOptionNotFound
+	}
+	if len(stored) == 1 {
+		return ErrLastEthnicityOption
+	}
+
+	result, err := config.MongoDB.Collection(config.AppConfig.EthnicityOptionCollection).DeleteOne(ctx, bson.M{"_id": value})
+	if err != nil {
+		s.logger.Error("failed to remove ethnicity option", zap.Error(err), zap.String("value", value))
+		return fmt.Errorf("failed to remove ethnicity option: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrEthnicityOptionNotFound
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("removed ethnicity option", zap.String("value", value))
+	return nil
+}
+
+// invalidate drops the Redis copy and tells every instance to refresh its in-memory copy
+func (s *EthnicityOptionService) invalidate(ctx context.Context) {
+	if err := config.Redis.Del(ctx, ethnicityOptionsCacheKey).Err(); err != nil {
+		s.logger.Warn("failed to invalidate ethnicity options cache", zap.Error(err))
+	}
+	if err := config.Redis.Publish(ctx, EthnicityOptionsChannel, "changed").Err(); err != nil {
+		s.logger.Warn("failed to announce ethnicity options change", zap.Error(err))
+	}
+}
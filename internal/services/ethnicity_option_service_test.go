@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestEthnicityOptionService_SeedAddRemove(t *testing.T) {
+	if config.MongoDB == nil || config.Redis == nil {
+		t.Skip("Skipping ethnicity option service tests: MongoDB or Redis not initialized")
+	}
+
+	_ = logging.InitLogger()
+
+	collectionName, ttl := config.AppConfig.EthnicityOptionCollection, config.AppConfig.EthnicityOptionsCacheTTL
+	config.AppConfig.EthnicityOptionCollection = "test_ethnicity_options"
+	config.AppConfig.EthnicityOptionsCacheTTL = time.Minute
+	defer func() {
+		config.AppConfig.EthnicityOptionCollection = collectionName
+		config.AppConfig.EthnicityOptionsCacheTTL = ttl
+	}()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.EthnicityOptionCollection)
+	collection.Drop(ctx)
+	config.Redis.Del(ctx, ethnicityOptionsCacheKey)
+	defer func() {
+		collection.Drop(ctx)
+		config.Redis.Del(ctx, ethnicityOptionsCacheKey)
+	}()
+
+	service := NewEthnicityOptionService(logging.GetLogger())
+
+	// An empty collection serves the built-in options
+	options, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if !reflect.DeepEqual(options, models.ValidEthnicityOptions()) {
+		t.Errorf("List() before seeding = %v, want built-in options", options)
+	}
+
+	// Seeding twice stores the built-in options once
+	for i := 0; i < 2; i++ {
+		if err := service.Seed(ctx); err != nil {
+			t.Fatalf("Seed() error = %v", err)
+		}
+	}
+	if count, _ := collection.CountDocuments(ctx, map[string]interface{}{}); int(count) != len(models.ValidEthnicityOptions()) {
+		t.Errorf("seeded %d options, want %d", count, len(models.ValidEthnicityOptions()))
+	}
+
+	// Added options go last, are normalized and show up through the cache
+	option, err := service.Add(ctx, " Quilombola ")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if option.Value != "quilombola" || option.Order != len(models.ValidEthnicityOptions())+1 {
+		t.Errorf("Add() = %+v, want quilombola last", option)
+	}
+	if _, err := service.Add(ctx, "quilombola"); !errors.Is(err, ErrEthnicityOptionExists) {
+		t.Errorf("Add() duplicate error = %v, want ErrEthnicityOptionExists", err)
+	}
+	options, _ = service.List(ctx)
+	if options[len(options)-1] != "quilombola" {
+		t.Errorf("List() after Add() = %v, want quilombola last", options)
+	}
+
+	// Removed options disappear from the list
+	if err := service.Remove(ctx, "outra"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := service.Remove(ctx, "outra"); !errors.Is(err, ErrEthnicityOptionNotFound) {
+		t.Errorf("Remove() missing option error = %v, want ErrEthnicityOptionNotFound", err)
+	}
+	options, _ = service.List(ctx)
+	for _, value := range options {
+		if value == "outra" {
+			t.Errorf("List() after Remove() = %v, still has outra", options)
+		}
+	}
+}
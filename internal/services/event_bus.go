@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// citizenEventHistorySize is how many recent events are kept per CPF for reconnecting clients
+	citizenEventHistorySize = 50
+	// citizenEventHistoryTTL bounds how long a client can stay disconnected and still catch up
+	citizenEventHistoryTTL = 10 * time.Minute
+	// eventSubscriptionBufferSize is how many parsed events a subscription buffers for a slow consumer
+	eventSubscriptionBufferSize = 16
+)
+
+// CitizenEventChannel returns the Redis pub/sub channel carrying a citizen's events
+func CitizenEventChannel(cpf string) string {
+	return fmt.Sprintf("citizen:events:%s", cpf)
+}
+
+// citizenEventChannelPattern matches the event channels of every citizen
+const citizenEventChannelPattern = "citizen:events:[0-9]*"
+
+func citizenEventHistoryKey(cpf string) string {
+	return fmt.Sprintf("citizen:events:history:%s", cpf)
+}
+
+// EventBus is the internal per-CPF event bus backed by Redis pub/sub. Handlers and sync
+// workers publish citizen events; the SSE stream and webhook dispatchers subscribe.
+// Publishing also records a short per-CPF history so reconnecting consumers can catch up.
+type EventBus struct {
+	redis  *redisclient.Client
+	logger *logging.SafeLogger
+}
+
+// NewEventBus creates a new event bus
+func NewEventBus(redis *redisclient.Client, logger *logging.SafeLogger) *EventBus {
+	return &EventBus{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Global instance
+var EventBusInstance *EventBus
+
+// InitEventBus initializes the global event bus instance
+func InitEventBus() {
+	logger := logging.GetLogger()
+	EventBusInstance = NewEventBus(config.Redis, logger)
+	logger.Info("event bus initialized")
+}
+
+// Publish publishes an event of the given type to the citizen's channel. data is any of the
+// typed event payloads in models (or nil).
+func (b *EventBus) Publish(ctx context.Context, cpf, eventType string, data interface{}) (*models.CitizenEvent, error) {
+	eventData, err := eventDataToMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event data: %w", eventType, err)
+	}
+
+	now := time.Now()
+	event := &models.CitizenEvent{
+		// Nanosecond timestamps keep IDs ordered so Last-Event-ID can be compared numerically
+		ID:        strconv.FormatInt(now.UnixNano(), 10),
+		Type:      eventType,
+		CPF:       cpf,
+		Data:      eventData,
+		Timestamp: now,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	historyKey := citizenEventHistoryKey(cpf)
+	pipe := b.redis.Pipeline()
+	pipe.LPush(ctx, historyKey, payload)
+	pipe.LTrim(ctx, historyKey, 0, citizenEventHistorySize-1)
+	pipe.Expire(ctx, historyKey, citizenEventHistoryTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		b.logger.Warn("failed to record citizen event history", zap.String("type", eventType), zap.Error(err))
+	}
+
+	if err := b.redis.Publish(ctx, CitizenEventChannel(cpf), payload).Err(); err != nil {
+		return nil, fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return event, nil
+}
+
+// PublishPhoneVerified announces that a citizen's phone was verified
+func (b *EventBus) PublishPhoneVerified(ctx context.Context, cpf, phoneNumber string) {
+	b.publishBestEffort(ctx, cpf, models.CitizenEventPhoneVerified, models.PhoneVerifiedEventData{PhoneNumber: phoneNumber})
+}
+
+// PublishCFDataReady announces that CF data is available for a citizen's address
+func (b *EventBus) PublishCFDataReady(ctx context.Context, cpf, addressHash string) {
+	b.publishBestEffort(ctx, cpf, models.CitizenEventCFDataReady, models.CFDataReadyEventData{AddressHash: addressHash})
+}
+
+// PublishSelfDeclaredSynced announces that a self-declared field was persisted to MongoDB
+func (b *EventBus) PublishSelfDeclaredSynced(ctx context.Context, cpf, field string) {
+	b.publishBestEffort(ctx, cpf, models.CitizenEventSelfDeclaredSynced, models.SelfDeclaredSyncedEventData{Field: field})
+}
+
+// PublishMaintenanceChanged announces that one of the citizen's maintenance requests changed
+func (b *EventBus) PublishMaintenanceChanged(ctx context.Context, cpf, requestID, status string) {
+	b.publishBestEffort(ctx, cpf, models.CitizenEventMaintenanceChanged, models.MaintenanceChangedEventData{RequestID: requestID, Status: status})
+}
+
+// publishBestEffort publishes an event without failing the operation that produced it. A nil
+// bus (not initialized in this process) publishes nothing.
+func (b *EventBus) publishBestEffort(ctx context.Context, cpf, eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+	if _, err := b.Publish(ctx, cpf, eventType, data); err != nil {
+		b.logger.Warn("failed to publish citizen event", zap.String("type", eventType), zap.Error(err))
+	}
+}
+
+// Subscribe subscribes to a citizen's events. The caller must close the subscription.
+func (b *EventBus) Subscribe(ctx context.Context, cpf string) (*EventSubscription, error) {
+	return b.subscribe(ctx, b.redis.Subscribe(ctx, CitizenEventChannel(cpf)))
+}
+
+// SubscribeAll subscribes to the events of every citizen, for dispatchers that fan events out
+// to other systems. The caller must close the subscription.
+func (b *EventBus) SubscribeAll(ctx context.Context) (*EventSubscription, error) {
+	return b.subscribe(ctx, b.redis.PSubscribe(ctx, citizenEventChannelPattern))
+}
+
+func (b *EventBus) subscribe(ctx context.Context, pubsub *redis.PubSub) (*EventSubscription, error) {
+	if pubsub == nil {
+		return nil, fmt.Errorf("redis client does not support pub/sub")
+	}
+
+	// Wait for the subscription to be confirmed so no event published afterwards is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to citizen events: %w", err)
+	}
+	return newEventSubscription(pubsub, b.logger), nil
+}
+
+// EventsSince returns the recorded events of a citizen newer than lastEventID, oldest first
+func (b *EventBus) EventsSince(ctx context.Context, cpf, lastEventID string) ([]models.CitizenEvent, error) {
+	last, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event id: %w", err)
+	}
+
+	payloads, err := b.redis.LRange(ctx, citizenEventHistoryKey(cpf), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read citizen event history: %w", err)
+	}
+
+	// History is stored newest first
+	events := make([]models.CitizenEvent, 0, len(payloads))
+	for i := len(payloads) - 1; i >= 0; i-- {
+		event, err := ParseCitizenEvent(payloads[i])
+		if err != nil {
+			continue
+		}
+		if id, err := strconv.ParseInt(event.ID, 10, 64); err == nil && id > last {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+// EventSubscription delivers the decoded events of a pub/sub subscription
+type EventSubscription struct {
+	pubsub    *redis.PubSub
+	events    chan models.CitizenEvent
+	done      chan struct{}
+	closeOnce sync.Once
+	logger    *logging.SafeLogger
+}
+
+func newEventSubscription(pubsub *redis.PubSub, logger *logging.SafeLogger) *EventSubscription {
+	s := &EventSubscription{
+		pubsub: pubsub,
+		events: make(chan models.CitizenEvent, eventSubscriptionBufferSize),
+		done:   make(chan struct{}),
+		logger: logger,
+	}
+	go s.run()
+	return s
+}
+
+func (s *EventSubscription) run() {
+	defer close(s.events)
+	for msg := range s.pubsub.Channel() {
+		event, err := ParseCitizenEvent(msg.Payload)
+		if err != nil {
+			s.logger.Warn("failed to parse citizen event", zap.String("channel", msg.Channel), zap.Error(err))
+			continue
+		}
+		select {
+		case s.events <- *event:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Events returns the channel of received events; it is closed when the subscription is closed
+func (s *EventSubscription) Events() <-chan models.CitizenEvent {
+	return s.events
+}
+
+// Close closes the subscription
+func (s *EventSubscription) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.pubsub.Close()
+}
+
+// ParseCitizenEvent decodes an event payload received from a citizen's channel
+func ParseCitizenEvent(payload string) (*models.CitizenEvent, error) {
+	var event models.CitizenEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to parse citizen event: %w", err)
+	}
+	return &event, nil
+}
+
+// eventDataToMap converts a typed event payload into the generic event data map
+func eventDataToMap(data interface{}) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(encoded, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
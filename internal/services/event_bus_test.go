@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEventBusTest(t *testing.T, cpf string) *EventBus {
+	t.Helper()
+	if config.Redis == nil {
+		t.Skip("Redis not initialized")
+	}
+	_ = logging.InitLogger()
+
+	cleanup := func() { config.Redis.Del(context.Background(), citizenEventHistoryKey(cpf)) }
+	cleanup()
+	t.Cleanup(cleanup)
+
+	return NewEventBus(config.Redis, logging.GetLogger())
+}
+
+func receiveEvent(t *testing.T, subscription *EventSubscription) models.CitizenEvent {
+	t.Helper()
+	select {
+	case event, ok := <-subscription.Events():
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+	}
+	return models.CitizenEvent{}
+}
+
+func TestEventBus_Publish_RecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	cpf := "11144477735"
+	bus := setupEventBusTest(t, cpf)
+
+	bus.PublishCFDataReady(ctx, cpf, "abc123")
+	bus.PublishSelfDeclaredSynced(ctx, cpf, "email")
+
+	events, err := bus.EventsSince(ctx, cpf, "0")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.CitizenEventCFDataReady, events[0].Type, "events must be returned oldest first")
+	assert.Equal(t, models.CitizenEventSelfDeclaredSynced, events[1].Type)
+	assert.Equal(t, cpf, events[1].CPF)
+	assert.Equal(t, "email", events[1].Data["field"])
+
+	// Only events after the given ID are replayed
+	events, err = bus.EventsSince(ctx, cpf, events[0].ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.CitizenEventSelfDeclaredSynced, events[0].Type)
+}
+
+func TestEventBus_Publish_HistoryIsCapped(t *testing.T) {
+	ctx := context.Background()
+	cpf := "11144477735"
+	bus := setupEventBusTest(t, cpf)
+
+	for i := 0; i < citizenEventHistorySize+5; i++ {
+		bus.PublishCFDataReady(ctx, cpf, "")
+	}
+
+	events, err := bus.EventsSince(ctx, cpf, "0")
+	require.NoError(t, err)
+	assert.Len(t, events, citizenEventHistorySize)
+}
+
+func TestEventBus_EventsSince_InvalidID(t *testing.T) {
+	bus := NewEventBus(config.Redis, logging.GetLogger())
+	_, err := bus.EventsSince(context.Background(), "11144477735", "not-a-number")
+	assert.Error(t, err)
+}
+
+func TestEventBus_Subscribe_ReceivesTypedEvents(t *testing.T) {
+	ctx := context.Background()
+	cpf := "11144477735"
+	bus := setupEventBusTest(t, cpf)
+
+	subscription, err := bus.Subscribe(ctx, cpf)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	bus.PublishPhoneVerified(ctx, cpf, "5521987654321")
+
+	event := receiveEvent(t, subscription)
+	assert.Equal(t, models.CitizenEventPhoneVerified, event.Type)
+	assert.NotEmpty(t, event.ID)
+
+	var data models.PhoneVerifiedEventData
+	require.NoError(t, event.DecodeData(&data))
+	assert.Equal(t, "5521987654321", data.PhoneNumber)
+}
+
+func TestEventBus_SubscribeAll_ReceivesEveryCitizen(t *testing.T) {
+	ctx := context.Background()
+	cpf := "11144477735"
+	otherCPF := "52998224725"
+	bus := setupEventBusTest(t, cpf)
+	t.Cleanup(func() { config.Redis.Del(context.Background(), citizenEventHistoryKey(otherCPF)) })
+
+	subscription, err := bus.SubscribeAll(ctx)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	bus.PublishMaintenanceChanged(ctx, cpf, "req-1", "aberto")
+	bus.PublishMaintenanceChanged(ctx, otherCPF, "req-2", "fechado")
+
+	received := map[string]string{}
+	for i := 0; i < 2; i++ {
+		event := receiveEvent(t, subscription)
+		assert.Equal(t, models.CitizenEventMaintenanceChanged, event.Type)
+
+		var data models.MaintenanceChangedEventData
+		require.NoError(t, event.DecodeData(&data))
+		received[event.CPF] = data.RequestID
+	}
+	assert.Equal(t, map[string]string{cpf: "req-1", otherCPF: "req-2"}, received)
+}
+
+func TestEventBus_Close_ClosesEvents(t *testing.T) {
+	ctx := context.Background()
+	cpf := "11144477735"
+	bus := setupEventBusTest(t, cpf)
+
+	subscription, err := bus.Subscribe(ctx, cpf)
+	require.NoError(t, err)
+	require.NoError(t, subscription.Close())
+
+	select {
+	case _, ok := <-subscription.Events():
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("events channel not closed")
+	}
+}
+
+func TestEventBus_NilBusPublishIsNoop(t *testing.T) {
+	var bus *EventBus
+	assert.NotPanics(t, func() {
+		bus.PublishPhoneVerified(context.Background(), "11144477735", "5521987654321")
+	})
+}
+
+func TestParseCitizenEvent_Invalid(t *testing.T) {
+	_, err := ParseCitizenEvent("{invalid")
+	assert.Error(t, err)
+}
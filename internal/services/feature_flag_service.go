@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	// featureFlagsCacheKey holds the Redis copy of the flag definitions
+	featureFlagsCacheKey = "feature_flags:definitions"
+	// featureFlagsGenerationKey is bumped on every flag change; it is part of the evaluation
+	// cache keys, so a change (e.g. a kill switch) makes every cached evaluation unreachable at once
+	featureFlagsGenerationKey = "feature_flags:generation"
+)
+
+var (
+	// ErrFeatureFlagExists is returned when creating a flag whose key is taken
+	ErrFeatureFlagExists = errors.New("feature flag already exists")
+	// ErrFeatureFlagNotFound is returned when updating or deleting a flag that doesn't exist
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+	// ErrInvalidFeatureFlag is returned for malformed flag definitions
+	ErrInvalidFeatureFlag = errors.New("invalid feature flag")
+)
+
+// FeatureFlagServiceInstance is the global feature flag service, consulted by gated handlers
+var FeatureFlagServiceInstance *FeatureFlagService
+
+// FeatureFlagService evaluates per-cohort feature flags, stored in MongoDB with a read-through
+// Redis copy. Evaluations are cached per CPF for FEATURE_FLAG_CACHE_TTL.
+type FeatureFlagService struct {
+	logger *logging.SafeLogger
+}
+
+// NewFeatureFlagService creates a new FeatureFlagService
+func NewFeatureFlagService(logger *logging.SafeLogger) *FeatureFlagService {
+	return &FeatureFlagService{
+		logger: logger,
+	}
+}
+
+// InitFeatureFlagService initializes the global feature flag service instance
+func InitFeatureFlagService(logger *logging.SafeLogger) {
+	FeatureFlagServiceInstance = NewFeatureFlagService(logger)
+}
+
+// IsFeatureEnabled reports whether a flag is on for a CPF. When flags can't be evaluated it
+// falls back to the flag's default, so an outage doesn't switch gated behaviors off.
+func IsFeatureEnabled(ctx context.Context, key, cpf string) bool {
+	if FeatureFlagServiceInstance == nil {
+		return models.DefaultFeatureFlags[key]
+	}
+	enabled, err := FeatureFlagServiceInstance.Evaluate(ctx, key, cpf)
+	if err != nil {
+		FeatureFlagServiceInstance.logger.Warn("failed to evaluate feature flag, using default",
+			zap.Error(err), zap.String("flag", key))
+		return models.DefaultFeatureFlags[key]
+	}
+	return enabled
+}
+
+// List returns every stored flag ordered by key, reading through the Redis copy
+func (s *FeatureFlagService) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	cachedData, err := config.Redis.Get(ctx, featureFlagsCacheKey).Result()
+	if err == nil && cachedData != "" {
+		var cached []models.FeatureFlag
+		if err := json.Unmarshal([]byte(cachedData), &cached); err == nil {
+			return cached, nil
+		}
+		s.logger.Warn("failed to unmarshal cached feature flags", zap.Error(err))
+	}
+
+	// Cache miss - fetch from database
+	collection := config.MongoDB.Collection(config.AppConfig.FeatureFlagCollection)
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		s.logger.Error("failed to list feature flags", zap.Error(err))
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	flags := []models.FeatureFlag{}
+	if err := cursor.All(ctx, &flags); err != nil {
+		s.logger.Error("failed to decode feature flags", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+
+	if jsonData, err := json.Marshal(flags); err == nil {
+		config.Redis.Set(ctx, featureFlagsCacheKey, jsonData, config.AppConfig.FeatureFlagCacheTTL)
+	}
+	return flags, nil
+}
+
+// Get returns a stored flag
+func (s *FeatureFlagService) Get(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	flags, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range flags {
+		if flags[i].Key == key {
+			return &flags[i], nil
+		}
+	}
+	return nil, ErrFeatureFlagNotFound
+}
+
+// Create stores a new flag
+func (s *FeatureFlagService) Create(ctx context.Context, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	if err := validateFeatureFlagRequest(req.Key, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	flag := models.FeatureFlag{
+		Key:               req.Key,
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		CPFs:              req.CPFs,
+		BetaGroupIDs:      req.BetaGroupIDs,
+		RolloutPercentage: req.RolloutPercentage,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	_, err := config.MongoDB.Collection(config.AppConfig.FeatureFlagCollection).InsertOne(ctx, flag)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrFeatureFlagExists
+	}
+	if err != nil {
+		s.logger.Error("failed to create feature flag", zap.Error(err), zap.String("flag", flag.Key))
+		return nil, fmt.Errorf("failed to create feature flag: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("created feature flag", zap.String("flag", flag.Key), zap.Bool("enabled", flag.Enabled))
+	return &flag, nil
+}
+
+// Update replaces the targeting of an existing flag. Disabling a flag takes effect right away
+// for every CPF, regardless of cached evaluations.
+func (s *FeatureFlagService) Update(ctx context.Context, key string, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	if err := validateFeatureFlagRequest(key, req); err != nil {
+		return nil, err
+	}
+
+	var flag models.FeatureFlag
+	err := config.MongoDB.Collection(config.AppConfig.FeatureFlagCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": key},
+		bson.M{"$set": bson.M{
+			"description":        req.Description,
+			"enabled":            req.Enabled,
+			"cpfs":               req.CPFs,
+			"beta_group_ids":     req.BetaGroupIDs,
+			"rollout_percentage": req.RolloutPercentage,
+			"updated_at":         time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&flag)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if err != nil {
+		s.logger.Error("failed to update feature flag", zap.Error(err), zap.String("flag", key))
+		return nil, fmt.Errorf("failed to update feature flag: %w", err)
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("updated feature flag", zap.String("flag", key), zap.Bool("enabled", flag.Enabled))
+	return &flag, nil
+}
+
+// Delete removes a flag; known flags go back to their default
+func (s *FeatureFlagService) Delete(ctx context.Context, key string) error {
+	result, err := config.MongoDB.Collection(config.AppConfig.FeatureFlagCollection).DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		s.logger.Error("failed to delete feature flag", zap.Error(err), zap.String("flag", key))
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrFeatureFlagNotFound
+	}
+
+	s.invalidate(ctx)
+	s.logger.Info("deleted feature flag", zap.String("flag", key))
+	return nil
+}
+
+// Evaluate reports whether a flag is on for a CPF. Flags that don't exist use their default.
+func (s *FeatureFlagService) Evaluate(ctx context.Context, key, cpf string) (bool, error) {
+	evaluations, err := s.EvaluateAll(ctx, cpf)
+	if err != nil {
+		return false, err
+	}
+	if enabled, ok := evaluations[key]; ok {
+		return enabled, nil
+	}
+	return models.DefaultFeatureFlags[key], nil
+}
+
+// EvaluateAll evaluates every stored and known flag for a CPF. An empty CPF only matches flags
+// rolled out to everyone.
+func (s *FeatureFlagService) EvaluateAll(ctx context.Context, cpf string) (map[string]bool, error) {
+	cacheKey := ""
+	if cpf != "" {
+		if generation, err := s.generation(ctx); err == nil {
+			cacheKey = fmt.Sprintf("feature_flags:eval:%s:%s", generation, cpf)
+			cachedData, err := config.Redis.Get(ctx, cacheKey).Result()
+			if err == nil && cachedData != "" {
+				var cached map[string]bool
+				if err := json.Unmarshal([]byte(cachedData), &cached); err == nil {
+					return cached, nil
+				}
+			}
+		} else {
+			s.logger.Warn("failed to read feature flag generation", zap.Error(err))
+		}
+	}
+
+	flags, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Beta groups are only looked up when a flag that could still match targets them
+	var betaGroupIDs []string
+	betaGroupsLoaded := false
+	evaluations := make(map[string]bool, len(flags)+len(models.DefaultFeatureFlags))
+	for key, enabled := range models.DefaultFeatureFlags {
+		evaluations[key] = enabled
+	}
+	for _, flag := range flags {
+		enabled := evaluateFeatureFlag(flag, cpf, nil)
+		if !enabled && flag.Enabled && cpf != "" && len(flag.BetaGroupIDs) > 0 {
+			if !betaGroupsLoaded {
+				if betaGroupIDs, err = s.betaGroupsOf(ctx, cpf); err != nil {
+					return nil, err
+				}
+				betaGroupsLoaded = true
+			}
+			enabled = evaluateFeatureFlag(flag, cpf, betaGroupIDs)
+		}
+		evaluations[flag.Key] = enabled
+	}
+
+	if cacheKey != "" {
+		if jsonData, err := json.Marshal(evaluations); err == nil {
+			config.Redis.Set(ctx, cacheKey, jsonData, config.AppConfig.FeatureFlagCacheTTL)
+		}
+	}
+	return evaluations, nil
+}
+
+// evaluateFeatureFlag applies a flag's targeting to a CPF and the beta groups its phones belong to
+func evaluateFeatureFlag(flag models.FeatureFlag, cpf string, betaGroupIDs []string) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if flag.RolloutPercentage >= 100 {
+		return true
+	}
+	if cpf == "" {
+		return false
+	}
+	if slices.Contains(flag.CPFs, cpf) {
+		return true
+	}
+	for _, groupID := range betaGroupIDs {
+		if slices.Contains(flag.BetaGroupIDs, groupID) {
+			return true
+		}
+	}
+	return featureFlagRolloutBucket(flag.Key, cpf) < flag.RolloutPercentage
+}
+
+// featureFlagRolloutBucket places a CPF in one of 100 buckets. The flag key is part of the hash, so
+// each flag's rollout reaches a different set of citizens, and raising the percentage only adds
+// citizens.
+func featureFlagRolloutBucket(key, cpf string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + cpf))
+	return int(h.Sum32() % 100)
+}
+
+// betaGroupsOf returns the beta groups the CPF's whitelisted phones belong to
+func (s *FeatureFlagService) betaGroupsOf(ctx context.Context, cpf string) ([]string, error) {
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+	cursor, err := collection.Find(ctx,
+		bson.M{"cpf": cpf, "beta_group_id": bson.M{"$exists": true, "$ne": ""}},
+		options.Find().SetProjection(bson.M{"beta_group_id": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find beta groups of citizen: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var mappings []models.PhoneCPFMapping
+	if err := cursor.All(ctx, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to decode beta groups of citizen: %w", err)
+	}
+
+	groupIDs := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		groupIDs = append(groupIDs, mapping.BetaGroupID)
+	}
+	return groupIDs, nil
+}
+
+// generation returns the current flag generation, "0" before the first change
+func (s *FeatureFlagService) generation(ctx context.Context) (string, error) {
+	generation, err := config.Redis.Get(ctx, featureFlagsGenerationKey).Result()
+	if err == redis.Nil {
+		return "0", nil
+	}
+	return generation, err
+}
+
+// invalidate drops the Redis copy of the definitions and every cached evaluation
+func (s *FeatureFlagService) invalidate(ctx context.Context) {
+	if err := config.Redis.Del(ctx, featureFlagsCacheKey).Err(); err != nil {
+		s.logger.Warn("failed to invalidate feature flags cache", zap.Error(err))
+	}
+	if err := config.Redis.Incr(ctx, featureFlagsGenerationKey).Err(); err != nil {
+		s.logger.Warn("failed to bump feature flag generation", zap.Error(err))
+	}
+}
+
+// validateFeatureFlagRequest checks a flag definition
+func validateFeatureFlagRequest(key string, req models.FeatureFlagRequest) error {
+	if !models.IsValidFeatureFlagKey(key) {
+		return fmt.Errorf("%w: key must have 1-64 lowercase letters, digits or underscores", ErrInvalidFeatureFlag)
+	}
+	if req.RolloutPercentage < 0 || req.RolloutPercentage > 100 {
+		return fmt.Errorf("%w: rollout_percentage must be between 0 and 100", ErrInvalidFeatureFlag)
+	}
+	for _, cpf := range req.CPFs {
+		if !utils.ValidateCPF(cpf) {
+			return fmt.Errorf("%w: invalid CPF %q", ErrInvalidFeatureFlag, cpf)
+		}
+	}
+	for _, groupID := range req.BetaGroupIDs {
+		if _, err := primitive.ObjectIDFromHex(groupID); err != nil {
+			return fmt.Errorf("%w: invalid beta group ID %q", ErrInvalidFeatureFlag, groupID)
+		}
+	}
+	return nil
+}
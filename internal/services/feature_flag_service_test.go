@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestEvaluateFeatureFlag(t *testing.T) {
+	const cpf = "11144477735"
+	groupID := "65a1b2c3d4e5f60718293a4b"
+
+	tests := []struct {
+		name         string
+		flag         models.FeatureFlag
+		cpf          string
+		betaGroupIDs []string
+		want         bool
+	}{
+		{"disabled flag is off even when targeted", models.FeatureFlag{Key: "f", CPFs: []string{cpf}, RolloutPercentage: 100}, cpf, nil, false},
+		{"targeted CPF", models.FeatureFlag{Key: "f", Enabled: true, CPFs: []string{cpf}}, cpf, nil, true},
+		{"untargeted CPF", models.FeatureFlag{Key: "f", Enabled: true, CPFs: []string{"52998224725"}}, cpf, nil, false},
+		{"beta group member", models.FeatureFlag{Key: "f", Enabled: true, BetaGroupIDs: []string{groupID}}, cpf, []string{groupID}, true},
+		{"other beta group", models.FeatureFlag{Key: "f", Enabled: true, BetaGroupIDs: []string{groupID}}, cpf, []string{"65a1b2c3d4e5f60718293a4c"}, false},
+		{"full rollout", models.FeatureFlag{Key: "f", Enabled: true, RolloutPercentage: 100}, cpf, nil, true},
+		{"full rollout without CPF", models.FeatureFlag{Key: "f", Enabled: true, RolloutPercentage: 100}, "", nil, true},
+		{"partial rollout without CPF", models.FeatureFlag{Key: "f", Enabled: true, RolloutPercentage: 99, CPFs: []string{cpf}}, "", nil, false},
+		{"zero rollout", models.FeatureFlag{Key: "f", Enabled: true}, cpf, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateFeatureFlag(tt.flag, tt.cpf, tt.betaGroupIDs); got != tt.want {
+				t.Errorf("evaluateFeatureFlag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagRolloutBucket(t *testing.T) {
+	// Buckets are stable and spread CPFs roughly evenly
+	inRollout := 0
+	for i := 0; i < 1000; i++ {
+		cpf := fmt.Sprintf("%011d", i*7919)
+		bucket := featureFlagRolloutBucket("f", cpf)
+		if bucket < 0 || bucket >= 100 {
+			t.Fatalf("featureFlagRolloutBucket() = %d, want 0-99", bucket)
+		}
+		if bucket != featureFlagRolloutBucket("f", cpf) {
+			t.Fatalf("featureFlagRolloutBucket() not deterministic for %s", cpf)
+		}
+		if bucket < 25 {
+			inRollout++
+		}
+	}
+	if inRollout < 150 || inRollout > 350 {
+		t.Errorf("%d of 1000 CPFs in a 25%% rollout, want about 250", inRollout)
+	}
+}
+
+func TestValidateFeatureFlagRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		req     models.FeatureFlagRequest
+		wantErr bool
+	}{
+		{"valid", "cf_integration", models.FeatureFlagRequest{CPFs: []string{"11144477735"}, BetaGroupIDs: []string{"65a1b2c3d4e5f60718293a4b"}, RolloutPercentage: 50}, false},
+		{"invalid key", "CF-Integration", models.FeatureFlagRequest{}, true},
+		{"rollout above 100", "f", models.FeatureFlagRequest{RolloutPercentage: 101}, true},
+		{"invalid CPF", "f", models.FeatureFlagRequest{CPFs: []string{"12345678900"}}, true},
+		{"invalid beta group", "f", models.FeatureFlagRequest{BetaGroupIDs: []string{"beta"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFeatureFlagRequest(tt.key, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFeatureFlagRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidFeatureFlag) {
+				t.Errorf("validateFeatureFlagRequest() error = %v, want ErrInvalidFeatureFlag", err)
+			}
+		})
+	}
+}
+
+func TestIsFeatureEnabled_DefaultsWithoutService(t *testing.T) {
+	original := FeatureFlagServiceInstance
+	FeatureFlagServiceInstance = nil
+	defer func() { FeatureFlagServiceInstance = original }()
+
+	if !IsFeatureEnabled(context.Background(), models.FeatureFlagCFIntegration, "11144477735") {
+		t.Error("IsFeatureEnabled(cf_integration) = false, want its default true")
+	}
+	if IsFeatureEnabled(context.Background(), "unknown_flag", "11144477735") {
+		t.Error("IsFeatureEnabled(unknown_flag) = true, want false")
+	}
+}
+
+func TestFeatureFlagService_CRUDAndKillSwitch(t *testing.T) {
+	if config.MongoDB == nil || config.Redis == nil {
+		t.Skip("Skipping feature flag service tests: MongoDB or Redis not initialized")
+	}
+
+	_ = logging.InitLogger()
+
+	collectionName, ttl := config.AppConfig.FeatureFlagCollection, config.AppConfig.FeatureFlagCacheTTL
+	config.AppConfig.FeatureFlagCollection = "test_feature_flags"
+	config.AppConfig.FeatureFlagCacheTTL = time.Minute
+	defer func() {
+		config.AppConfig.FeatureFlagCollection = collectionName
+		config.AppConfig.FeatureFlagCacheTTL = ttl
+	}()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.FeatureFlagCollection)
+	collection.Drop(ctx)
+	config.Redis.Del(ctx, featureFlagsCacheKey)
+	defer func() {
+		collection.Drop(ctx)
+		config.Redis.Del(ctx, featureFlagsCacheKey)
+	}()
+
+	const cpf = "11144477735"
+	service := NewFeatureFlagService(logging.GetLogger())
+
+	// Known flags use their default until created
+	if enabled, err := service.Evaluate(ctx, models.FeatureFlagCFIntegration, cpf); err != nil || !enabled {
+		t.Errorf("Evaluate(cf_integration) before creation = %v, %v, want true", enabled, err)
+	}
+
+	req := models.FeatureFlagRequest{Key: "new_checkout", Enabled: true, CPFs: []string{cpf}}
+	if _, err := service.Create(ctx, req); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.Create(ctx, req); !errors.Is(err, ErrFeatureFlagExists) {
+		t.Errorf("Create() duplicate error = %v, want ErrFeatureFlagExists", err)
+	}
+	if enabled, err := service.Evaluate(ctx, "new_checkout", cpf); err != nil || !enabled {
+		t.Errorf("Evaluate() for targeted CPF = %v, %v, want true", enabled, err)
+	}
+
+	// The kill switch wins over the cached evaluation
+	req.Enabled = false
+	if _, err := service.Update(ctx, "new_checkout", req); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if enabled, err := service.Evaluate(ctx, "new_checkout", cpf); err != nil || enabled {
+		t.Errorf("Evaluate() after kill switch = %v, %v, want false", enabled, err)
+	}
+
+	if err := service.Delete(ctx, "new_checkout"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := service.Delete(ctx, "new_checkout"); !errors.Is(err, ErrFeatureFlagNotFound) {
+		t.Errorf("Delete() missing flag error = %v, want ErrFeatureFlagNotFound", err)
+	}
+	if _, err := service.Update(ctx, "new_checkout", req); !errors.Is(err, ErrFeatureFlagNotFound) {
+		t.Errorf("Update() missing flag error = %v, want ErrFeatureFlagNotFound", err)
+	}
+}
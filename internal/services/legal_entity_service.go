@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -9,6 +11,7 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -32,6 +35,9 @@ func NewLegalEntityService(database *mongo.Database, logger *logging.SafeLogger)
 // Global legal entity service instance
 var LegalEntityServiceInstance *LegalEntityService
 
+// ErrLegalEntityNotFound is returned when no legal entity has the requested CNPJ
+var ErrLegalEntityNotFound = errors.New("legal entity not found")
+
 // InitLegalEntityService initializes the global legal entity service instance
 func InitLegalEntityService() {
 	logger := zap.L().Named("legal_entity_service")
@@ -42,8 +48,30 @@ func InitLegalEntityService() {
 	logger.Info("indexes will be managed by global database maintenance system")
 }
 
-// GetLegalEntitiesByCPF retrieves legal entities associated with a CPF with pagination
+// legalEntitiesByCPFCacheKey builds the cache key for a partner CPF lookup, scoped by filter and page
+func legalEntitiesByCPFCacheKey(cpf string, page, perPage int, legalNatureID *string) string {
+	legalNature := "all"
+	if legalNatureID != nil && *legalNatureID != "" {
+		legalNature = *legalNatureID
+	}
+	return fmt.Sprintf("legal_entities:cpf:%s:nj:%s:page:%d:per_page:%d", cpf, legalNature, page, perPage)
+}
+
+// GetLegalEntitiesByCPF retrieves legal entities associated with a CPF with pagination.
+// Results are cached per CPF+filter for LegalEntityCacheTTL since registry data changes slowly.
 func (s *LegalEntityService) GetLegalEntitiesByCPF(ctx context.Context, cpf string, page, perPage int, legalNatureID *string) (*models.PaginatedLegalEntities, error) {
+	// Try cache first
+	cacheKey := legalEntitiesByCPFCacheKey(cpf, page, perPage, legalNatureID)
+	cached, err := config.Redis.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var response models.PaginatedLegalEntities
+		if err := json.Unmarshal([]byte(cached), &response); err == nil {
+			observability.CacheHits.WithLabelValues("get_legal_entities_by_cpf").Inc()
+			s.logger.Debug("legal entities cache hit", zap.String("cpf", cpf), zap.String("cache_key", cacheKey))
+			return &response, nil
+		}
+	}
+
 	collection := s.database.Collection(config.AppConfig.LegalEntityCollection)
 
 	// Build filter query
@@ -94,6 +122,15 @@ func (s *LegalEntityService) GetLegalEntitiesByCPF(ctx context.Context, cpf stri
 	response.Pagination.Total = int(total)
 	response.Pagination.TotalPages = totalPages
 
+	// Cache the result
+	responseJSON, err := json.Marshal(response)
+	if err == nil {
+		err = config.Redis.Set(ctx, cacheKey, responseJSON, config.AppConfig.LegalEntityCacheTTL).Err()
+		if err != nil {
+			s.logger.Warn("failed to cache legal entities", zap.Error(err), zap.String("cpf", cpf))
+		}
+	}
+
 	s.logger.Debug("retrieved legal entities for CPF",
 		zap.String("cpf", cpf),
 		zap.Int("page", page),
@@ -128,19 +165,43 @@ func ValidatePaginationParams(pageStr, perPageStr string) (int, int, error) {
 	return page, perPage, nil
 }
 
-// GetLegalEntityByCNPJ retrieves a legal entity by CNPJ
+// legalEntityByCNPJCacheKey builds the cache key for a CNPJ lookup
+func legalEntityByCNPJCacheKey(cnpj string) string {
+	return fmt.Sprintf("legal_entities:cnpj:%s", cnpj)
+}
+
+// GetLegalEntityByCNPJ retrieves a legal entity by CNPJ (using the unique idx_cnpj index).
+// Results are cached for LegalEntityCacheTTL, like the partner CPF lookup.
 func (s *LegalEntityService) GetLegalEntityByCNPJ(ctx context.Context, cnpj string) (*models.LegalEntity, error) {
+	// Try cache first
+	cacheKey := legalEntityByCNPJCacheKey(cnpj)
+	if cached, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil {
+		var entity models.LegalEntity
+		if err := json.Unmarshal([]byte(cached), &entity); err == nil {
+			observability.CacheHits.WithLabelValues("get_legal_entity_by_cnpj").Inc()
+			s.logger.Debug("legal entity cache hit", zap.String("cnpj", cnpj))
+			return &entity, nil
+		}
+	}
+
 	collection := s.database.Collection(config.AppConfig.LegalEntityCollection)
 
 	var entity models.LegalEntity
 	err := collection.FindOne(ctx, bson.M{"cnpj": cnpj}).Decode(&entity)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("legal entity not found")
+			return nil, ErrLegalEntityNotFound
 		}
 		return nil, fmt.Errorf("failed to find legal entity: %w", err)
 	}
 
+	// Cache the result
+	if entityJSON, err := json.Marshal(entity); err == nil {
+		if err := config.Redis.Set(ctx, cacheKey, entityJSON, config.AppConfig.LegalEntityCacheTTL).Err(); err != nil {
+			s.logger.Warn("failed to cache legal entity", zap.Error(err), zap.String("cnpj", cnpj))
+		}
+	}
+
 	s.logger.Debug("retrieved legal entity by CNPJ", zap.String("cnpj", cnpj))
 
 	return &entity, nil
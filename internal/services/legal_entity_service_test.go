@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
@@ -20,13 +21,28 @@ func setupLegalEntityServiceTest(t *testing.T) (*LegalEntityService, func()) {
 		config.AppConfig = &config.Config{}
 	}
 	config.AppConfig.LegalEntityCollection = "test_legal_entities"
+	config.AppConfig.LegalEntityCacheTTL = time.Minute
 
 	service := NewLegalEntityService(config.MongoDB, logging.GetLogger())
+	clearLegalEntityCache(t)
 
 	return service, func() {
 		ctx := context.Background()
 		collection := config.MongoDB.Collection(config.AppConfig.LegalEntityCollection)
 		_ = collection.Drop(ctx)
+		clearLegalEntityCache(t)
+	}
+}
+
+func clearLegalEntityCache(t *testing.T) {
+	ctx := context.Background()
+	keys, err := config.Redis.Keys(ctx, "legal_entities:cpf:*").Result()
+	if err != nil {
+		t.Logf("failed to list legal entity cache keys: %v", err)
+		return
+	}
+	if len(keys) > 0 {
+		_ = config.Redis.Del(ctx, keys...).Err()
 	}
 }
 
@@ -297,3 +313,51 @@ func TestValidatePaginationParams_InvalidPerPage(t *testing.T) {
 		t.Error("ValidatePaginationParams() should return error for perPage > 100")
 	}
 }
+
+func TestGetLegalEntitiesByCPF_CachedPerFilter(t *testing.T) {
+	service, cleanup := setupLegalEntityServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.LegalEntityCollection)
+
+	_, err := collection.InsertOne(ctx, bson.M{
+		"cnpj":              "12345678000199",
+		"razao_social":      "Company A",
+		"natureza_juridica": bson.M{"id": "2062"},
+		"socios":            []bson.M{{"cpf_socio": "03561350712"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert legal entity: %v", err)
+	}
+
+	// Prime the cache for the unfiltered and filtered lookups
+	if _, err := service.GetLegalEntitiesByCPF(ctx, "03561350712", 1, 10, nil); err != nil {
+		t.Fatalf("GetLegalEntitiesByCPF() error = %v", err)
+	}
+	nature := "2062"
+	if _, err := service.GetLegalEntitiesByCPF(ctx, "03561350712", 1, 10, &nature); err != nil {
+		t.Fatalf("GetLegalEntitiesByCPF() error = %v", err)
+	}
+
+	// Remove the underlying data; cached responses should still be served
+	_ = collection.Drop(ctx)
+
+	result, err := service.GetLegalEntitiesByCPF(ctx, "03561350712", 1, 10, nil)
+	if err != nil {
+		t.Fatalf("GetLegalEntitiesByCPF() error = %v", err)
+	}
+	if result.Pagination.Total != 1 {
+		t.Errorf("GetLegalEntitiesByCPF() cached Total = %v, want 1", result.Pagination.Total)
+	}
+
+	// A different filter must not share the cache entry
+	other := "1000"
+	result, err = service.GetLegalEntitiesByCPF(ctx, "03561350712", 1, 10, &other)
+	if err != nil {
+		t.Fatalf("GetLegalEntitiesByCPF() error = %v", err)
+	}
+	if result.Pagination.Total != 0 {
+		t.Errorf("GetLegalEntitiesByCPF() Total for other filter = %v, want 0", result.Pagination.Total)
+	}
+}
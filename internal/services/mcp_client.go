@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -14,10 +15,22 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.uber.org/zap"
 )
 
+// ErrNoEquipmentFound is returned when the MCP server finds no health equipment for an address
+var ErrNoEquipmentFound = errors.New("no equipment found")
+
+// mcpNoEquipmentMessage is the message the MCP server sends when no equipment serves an address
+const mcpNoEquipmentMessage = "nenhum equipamento encontrado"
+
+// isNoEquipmentMessage reports whether an MCP error message is the "no equipment found" one
+func isNoEquipmentMessage(message string) bool {
+	return strings.Contains(strings.ToLower(message), mcpNoEquipmentMessage)
+}
+
 // MCPClient handles communication with the Rio de Janeiro MCP Server for CF lookups
 type MCPClient struct {
 	baseURL     string
@@ -68,14 +81,13 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
-// NewMCPClient creates a new MCP client instance
-func NewMCPClient(cfg *config.Config, logger *logging.SafeLogger) *MCPClient {
+// NewMCPClient creates a new MCP client instance that sends its requests through httpClient
+// (normally httpclient.Client(httpclient.ClientMCP))
+func NewMCPClient(cfg *config.Config, httpClient *http.Client, logger *logging.SafeLogger) *MCPClient {
 	return &MCPClient{
-		baseURL:   cfg.MCPServerURL,
-		authToken: cfg.MCPAuthToken,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:     cfg.MCPServerURL,
+		authToken:   cfg.MCPAuthToken,
+		client:      httpClient,
 		logger:      logger,
 		retryConfig: DefaultRetryConfig(),
 	}
@@ -216,6 +228,29 @@ func (c *MCPClient) getSessionID(ctx context.Context) (string, error) {
 	return sessionID, nil
 }
 
+// Ping performs a single lightweight request against the MCP server (no retries).
+// The server answers HEAD with 405 plus a session header, so any non-5xx status counts as reachable.
+func (c *MCPClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create ping request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach MCP server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("MCP server returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // makeRequest sends a JSON-RPC request to the MCP server
 func (c *MCPClient) makeRequest(ctx context.Context, sessionID string, payload interface{}) (map[string]interface{}, error) {
 	jsonData, err := json.Marshal(payload)
@@ -235,6 +270,7 @@ func (c *MCPClient) makeRequest(ctx context.Context, sessionID string, payload i
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json, text/event-stream")
 		req.Header.Set("mcp-session-id", sessionID)
+		observability.InjectTraceHeaders(ctx, req.Header)
 
 		c.logger.Debug("sending MCP request",
 			zap.String("session_id", sessionID),
@@ -306,6 +342,7 @@ func (c *MCPClient) sendNotification(ctx context.Context, sessionID string, payl
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json, text/event-stream")
 		req.Header.Set("mcp-session-id", sessionID)
+		observability.InjectTraceHeaders(ctx, req.Header)
 
 		c.logger.Debug("sending MCP notification",
 			zap.String("session_id", sessionID),
@@ -494,6 +531,10 @@ func (c *MCPClient) parseHealthServicesResponse(result map[string]interface{}) (
 			zap.String("reason", errorMsg),
 			zap.String("operation", "health_services_lookup_no_results"))
 
+		if isNoEquipmentMessage(errorMsg) {
+			return nil, ErrNoEquipmentFound
+		}
+
 		// Return nil (no services found) instead of error - this is expected behavior
 		return nil, nil
 	}
@@ -504,11 +545,15 @@ func (c *MCPClient) parseHealthServicesResponse(result map[string]interface{}) (
 	}
 
 	equipamentos, ok := structuredContent["equipamentos"].([]interface{})
-	if !ok || len(equipamentos) == 0 {
-		return nil, fmt.Errorf("no equipment found in response")
+	if !ok {
+		return nil, fmt.Errorf("no equipment found in response: invalid equipamentos format")
+	}
+	if len(equipamentos) == 0 {
+		return nil, ErrNoEquipmentFound
 	}
 
 	var healthResult models.HealthServicesResult
+	noEquipmentReported := false
 
 	// Process each equipment object
 	for _, eq := range equipamentos {
@@ -522,6 +567,9 @@ func (c *MCPClient) parseHealthServicesResponse(result map[string]interface{}) (
 			c.logger.Debug("MCP server reported no equipment found",
 				zap.String("reason", fmt.Sprintf("%v", errorMsg)),
 				zap.String("operation", "equipment_lookup_no_equipment"))
+			if isNoEquipmentMessage(fmt.Sprintf("%v", errorMsg)) {
+				noEquipmentReported = true
+			}
 			continue
 		}
 
@@ -561,6 +609,10 @@ func (c *MCPClient) parseHealthServicesResponse(result map[string]interface{}) (
 		}
 	}
 
+	if healthResult.HealthFacility == nil && healthResult.FamilyHealthTeam == nil && noEquipmentReported {
+		return nil, ErrNoEquipmentFound
+	}
+
 	// Log what we found
 	if healthResult.HealthFacility != nil {
 		c.logger.Info("Health facility found",
@@ -12,6 +12,7 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils/httpclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,7 +28,7 @@ func setupMCPTest(t *testing.T, handler http.HandlerFunc) (*MCPClient, *httptest
 		MCPAuthToken: "test-token",
 	}
 
-	client := NewMCPClient(cfg, logging.GetLogger())
+	client := NewMCPClient(cfg, server.Client(), logging.GetLogger())
 
 	return client, server
 }
@@ -49,12 +50,13 @@ func TestNewMCPClient(t *testing.T) {
 		MCPAuthToken: "test-token",
 	}
 
-	client := NewMCPClient(cfg, logging.GetLogger())
+	httpClient := httpclient.Client(httpclient.ClientMCP)
+	client := NewMCPClient(cfg, httpClient, logging.GetLogger())
 
 	require.NotNil(t, client)
 	assert.Equal(t, "https://test.example.com", client.baseURL)
 	assert.Equal(t, "test-token", client.authToken)
-	assert.NotNil(t, client.client)
+	assert.Same(t, httpClient, client.client)
 	assert.NotNil(t, client.logger)
 	assert.Equal(t, 30*time.Second, client.client.Timeout)
 	assert.Equal(t, 3, client.retryConfig.MaxRetries)
@@ -816,6 +818,51 @@ func TestParseHealthServicesResponse_NoEquipment(t *testing.T) {
 	assert.Contains(t, err.Error(), "no equipment found")
 }
 
+func TestParseHealthServicesResponse_NoEquipmentMessage(t *testing.T) {
+	_ = logging.InitLogger()
+	client := &MCPClient{
+		logger: logging.GetLogger(),
+	}
+
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+	}{
+		{
+			name: "error response",
+			response: map[string]interface{}{
+				"result": map[string]interface{}{
+					"isError":     true,
+					"textContent": "Nenhum equipamento encontrado para o endereço informado",
+				},
+			},
+		},
+		{
+			name: "equipment entry with error",
+			response: map[string]interface{}{
+				"result": map[string]interface{}{
+					"structuredContent": map[string]interface{}{
+						"equipamentos": []interface{}{
+							map[string]interface{}{
+								"error": "Nenhum equipamento encontrado",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.parseHealthServicesResponse(tt.response)
+
+			assert.ErrorIs(t, err, ErrNoEquipmentFound)
+			assert.Nil(t, result)
+		})
+	}
+}
+
 func TestParseHealthServicesResponse_InvalidFormat(t *testing.T) {
 	_ = logging.InitLogger()
 	client := &MCPClient{
@@ -974,6 +1021,61 @@ func TestFindNearestCF_Integration(t *testing.T) {
 	assert.True(t, requestCount >= 4, "Should make at least 4 requests: HEAD, initialize, notification, instructions, CF lookup")
 }
 
+// noEquipmentMCPHandler mocks an MCP server that finds no equipment for any address,
+// counting the equipments_by_address calls it receives
+func noEquipmentMCPHandler(lookups *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("mcp-session-id", "test-session-123")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+		case "POST":
+			var req MCPRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			switch req.Method {
+			case "initialize":
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"result": map[string]interface{}{"protocolVersion": "2024-11-05"},
+				})
+			case "notifications/initialized":
+				w.WriteHeader(http.StatusAccepted)
+			case "tools/call":
+				params := req.Params.(map[string]interface{})
+				if params["name"] == "equipments_instructions" {
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"result": map[string]interface{}{"content": "Instructions loaded"},
+					})
+					return
+				}
+				*lookups++
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"result": map[string]interface{}{
+						"structuredContent": map[string]interface{}{
+							"equipamentos": []interface{}{
+								map[string]interface{}{"error": "Nenhum equipamento encontrado"},
+							},
+						},
+					},
+				})
+			}
+		}
+	}
+}
+
+func TestFindNearestCF_NoEquipment(t *testing.T) {
+	lookups := 0
+	client, server := setupMCPTest(t, noEquipmentMCPHandler(&lookups))
+	defer server.Close()
+
+	result, err := client.FindNearestCF(context.Background(), "Rua Sem Cobertura, 1")
+
+	assert.ErrorIs(t, err, ErrNoEquipmentFound)
+	assert.Nil(t, result)
+	assert.Equal(t, 1, lookups)
+}
+
 func TestFindNearestCF_SessionError(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1190,3 +1292,41 @@ func TestParseHealthServicesResponse_UnknownCategory(t *testing.T) {
 	assert.Nil(t, result.HealthFacility)
 	assert.Nil(t, result.FamilyHealthTeam)
 }
+
+func TestMCPClient_Ping(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "method not allowed is reachable", statusCode: http.StatusMethodNotAllowed, wantErr: false},
+		{name: "ok is reachable", statusCode: http.StatusOK, wantErr: false},
+		{name: "server error is unreachable", statusCode: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := setupMCPTest(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "HEAD", r.Method)
+				assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+				w.WriteHeader(tt.statusCode)
+			})
+			defer server.Close()
+
+			err := client.Ping(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMCPClient_Ping_Unreachable(t *testing.T) {
+	client, server := setupMCPTest(t, func(w http.ResponseWriter, r *http.Request) {})
+	server.Close()
+
+	err := client.Ping(context.Background())
+	assert.Error(t, err)
+}
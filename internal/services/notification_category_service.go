@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,27 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrTooManyCategoryOptIns is returned when category opt-ins exceed MaxCategoryOptIns
+	ErrTooManyCategoryOptIns = errors.New("too many category opt-ins")
+	// ErrInvalidCategory is returned when a category opt-in key is not an active category
+	ErrInvalidCategory = errors.New("invalid category")
+)
+
+// InvalidCategoryError reports the category opt-in key that is not an active category
+type InvalidCategoryError struct {
+	CategoryID string
+}
+
+func (e *InvalidCategoryError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidCategory.Error(), e.CategoryID)
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidCategory)
+func (e *InvalidCategoryError) Unwrap() error {
+	return ErrInvalidCategory
+}
+
 type NotificationCategoryService struct {
 	logger *logging.SafeLogger
 }
@@ -267,3 +289,55 @@ func (s *NotificationCategoryService) ValidateCategoryExists(ctx context.Context
 	}
 	return nil
 }
+
+// ValidateCategoryOptIns checks that a category opt-in update stays within MaxCategoryOptIns
+// and only references active categories
+func (s *NotificationCategoryService) ValidateCategoryOptIns(ctx context.Context, updates map[string]bool) error {
+	limit := config.AppConfig.MaxCategoryOptIns
+	if limit > 0 && len(updates) > limit {
+		return fmt.Errorf("%w: %d categories exceeds limit of %d", ErrTooManyCategoryOptIns, len(updates), limit)
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	categories, err := s.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]bool, len(categories))
+	for _, cat := range categories {
+		active[cat.ID] = true
+	}
+
+	for categoryID := range updates {
+		if !active[categoryID] {
+			return &InvalidCategoryError{CategoryID: categoryID}
+		}
+	}
+
+	return nil
+}
+
+// CheckCategoryOptInsLimit checks that merging updates into current keeps the number of
+// category opt-ins within MaxCategoryOptIns
+func CheckCategoryOptInsLimit(current, updates map[string]bool) error {
+	limit := config.AppConfig.MaxCategoryOptIns
+	if limit <= 0 {
+		return nil
+	}
+
+	merged := len(current)
+	for categoryID := range updates {
+		if _, exists := current[categoryID]; !exists {
+			merged++
+		}
+	}
+
+	if merged > limit {
+		return fmt.Errorf("%w: %d categories exceeds limit of %d", ErrTooManyCategoryOptIns, merged, limit)
+	}
+	return nil
+}
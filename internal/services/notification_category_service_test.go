@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -654,3 +656,72 @@ func TestValidateCategoryExists_Inactive(t *testing.T) {
 		t.Error("ValidateCategoryExists() should return error for inactive category")
 	}
 }
+
+func TestValidateCategoryOptIns_ExceedsLimit(t *testing.T) {
+	service, cleanup := setupNotificationCategoryServiceTest(t)
+	defer cleanup()
+
+	oldLimit := config.AppConfig.MaxCategoryOptIns
+	config.AppConfig.MaxCategoryOptIns = 3
+	defer func() { config.AppConfig.MaxCategoryOptIns = oldLimit }()
+
+	updates := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		updates[fmt.Sprintf("category_%d", i)] = true
+	}
+
+	err := service.ValidateCategoryOptIns(context.Background(), updates)
+	if !errors.Is(err, ErrTooManyCategoryOptIns) {
+		t.Errorf("ValidateCategoryOptIns() error = %v, want ErrTooManyCategoryOptIns", err)
+	}
+}
+
+func TestValidateCategoryOptIns_UnknownCategory(t *testing.T) {
+	service, cleanup := setupNotificationCategoryServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	collection := config.MongoDB.Collection(config.AppConfig.NotificationCategoryCollection)
+	_, err := collection.InsertOne(ctx, bson.M{"_id": "health", "name": "Health", "active": true})
+	if err != nil {
+		t.Fatalf("Failed to insert category: %v", err)
+	}
+
+	if err := service.ValidateCategoryOptIns(ctx, map[string]bool{"health": true}); err != nil {
+		t.Errorf("ValidateCategoryOptIns() error = %v, want nil for active category", err)
+	}
+
+	err = service.ValidateCategoryOptIns(ctx, map[string]bool{"health": true, "unknown": false})
+	var invalidCategory *InvalidCategoryError
+	if !errors.As(err, &invalidCategory) || invalidCategory.CategoryID != "unknown" {
+		t.Errorf("ValidateCategoryOptIns() error = %v, want InvalidCategoryError for 'unknown'", err)
+	}
+	if !errors.Is(err, ErrInvalidCategory) {
+		t.Error("InvalidCategoryError should unwrap to ErrInvalidCategory")
+	}
+}
+
+func TestCheckCategoryOptInsLimit(t *testing.T) {
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	oldLimit := config.AppConfig.MaxCategoryOptIns
+	config.AppConfig.MaxCategoryOptIns = 2
+	defer func() { config.AppConfig.MaxCategoryOptIns = oldLimit }()
+
+	current := map[string]bool{"health": true, "education": false}
+
+	if err := CheckCategoryOptInsLimit(current, map[string]bool{"health": false}); err != nil {
+		t.Errorf("CheckCategoryOptInsLimit() error = %v, want nil when updating existing keys", err)
+	}
+
+	if err := CheckCategoryOptInsLimit(current, map[string]bool{"transport": true}); !errors.Is(err, ErrTooManyCategoryOptIns) {
+		t.Errorf("CheckCategoryOptInsLimit() error = %v, want ErrTooManyCategoryOptIns when adding past the limit", err)
+	}
+
+	config.AppConfig.MaxCategoryOptIns = 0
+	if err := CheckCategoryOptInsLimit(current, map[string]bool{"transport": true}); err != nil {
+		t.Errorf("CheckCategoryOptInsLimit() error = %v, want nil when limit is disabled", err)
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// ResolveNotificationTarget picks the phone notifications for a citizen should be sent to. Verified
+// phones are tried in the NOTIFICATION_PHONE_FALLBACK order (by default the self-declared phone
+// confirmed with a code, then the government base phone), skipping phones that are quarantined or
+// opted out in the phone mapping. Citizens that opted out of notifications have no target.
+func (s *PhoneMappingService) ResolveNotificationTarget(ctx context.Context, cpf string, selfDeclaredPhone, basePhone *models.Telefone, optIn bool) (*models.NotificationTargetResponse, error) {
+	response := &models.NotificationTargetResponse{CPF: cpf}
+	if !optIn {
+		response.Reason = models.NotificationTargetReasonOptedOut
+		return response, nil
+	}
+
+	phones := map[string]*models.Telefone{
+		config.NotificationPhoneSourceSelfDeclared: selfDeclaredPhone,
+		config.NotificationPhoneSourceBase:         basePhone,
+	}
+	for _, source := range config.AppConfig.NotificationPhoneFallback {
+		phoneNumber := verifiedPhoneNumber(phones[source])
+		if phoneNumber == "" {
+			continue
+		}
+
+		skipReason, err := s.notificationSkipReason(ctx, phoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		if skipReason != "" {
+			response.Skipped = append(response.Skipped, models.NotificationTargetSkip{
+				Source:      source,
+				PhoneNumber: phoneNumber,
+				Reason:      skipReason,
+			})
+			continue
+		}
+
+		response.Found = true
+		response.PhoneNumber = phoneNumber
+		response.Source = source
+		return response, nil
+	}
+
+	if len(response.Skipped) > 0 {
+		response.Reason = models.NotificationTargetReasonPhonesBlocked
+	} else {
+		response.Reason = models.NotificationTargetReasonNoVerifiedPhone
+	}
+	return response, nil
+}
+
+// verifiedPhoneNumber returns the main phone in storage format when it is flagged as verified,
+// or an empty string when there's no such (valid) phone
+func verifiedPhoneNumber(phone *models.Telefone) string {
+	if phone == nil || phone.Indicador == nil || !*phone.Indicador || phone.Principal == nil {
+		return ""
+	}
+	return utils.StoredPhoneNumber(phone.Principal.DDI, phone.Principal.DDD, phone.Principal.Valor)
+}
+
+// notificationSkipReason returns why the phone can't receive notifications, or an empty string if it can
+func (s *PhoneMappingService) notificationSkipReason(ctx context.Context, phoneNumber string) (string, error) {
+	var mapping models.PhoneCPFMapping
+	err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).FindOne(
+		ctx,
+		bson.M{"phone_number": phoneNumber},
+	).Decode(&mapping)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		s.logger.Error("failed to get phone mapping", zap.Error(err), zap.String("phone_number", phoneNumber))
+		return "", fmt.Errorf("failed to get phone mapping: %w", err)
+	}
+
+	if mapping.QuarantineUntil != nil && mapping.QuarantineUntil.After(time.Now()) {
+		return models.NotificationPhoneSkipQuarantined, nil
+	}
+	if mapping.Status == models.MappingStatusBlocked {
+		return models.NotificationPhoneSkipOptedOut, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+)
+
+func TestVerifiedPhoneNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone *models.Telefone
+		want  string
+	}{
+		{"nil phone", nil, ""},
+		{"not verified", &models.Telefone{
+			Indicador: utils.BoolPtr(false),
+			Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("987654321")},
+		}, ""},
+		{"verified", &models.Telefone{
+			Indicador: utils.BoolPtr(true),
+			Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("987654321")},
+		}, "5521987654321"},
+		{"missing DDI defaults to Brazil", &models.Telefone{
+			Indicador: utils.BoolPtr(true),
+			Principal: &models.TelefonePrincipal{DDD: strPtr("21"), Valor: strPtr("98765-4321")},
+		}, "5521987654321"},
+		{"invalid number", &models.Telefone{
+			Indicador: utils.BoolPtr(true),
+			Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("00"), Valor: strPtr("987654321")},
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifiedPhoneNumber(tt.phone); got != tt.want {
+				t.Errorf("verifiedPhoneNumber() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNotificationTarget(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	originalFallback := config.AppConfig.NotificationPhoneFallback
+	defer func() { config.AppConfig.NotificationPhoneFallback = originalFallback }()
+	config.AppConfig.NotificationPhoneFallback = []string{config.NotificationPhoneSourceSelfDeclared, config.NotificationPhoneSourceBase}
+
+	cpf := "12345678901"
+	selfDeclaredPhone := &models.Telefone{
+		Indicador: utils.BoolPtr(true),
+		Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("987654321")},
+	}
+	basePhone := &models.Telefone{
+		Indicador: utils.BoolPtr(true),
+		Principal: &models.TelefonePrincipal{DDI: strPtr("55"), DDD: strPtr("21"), Valor: strPtr("912345678")},
+	}
+
+	// Verified self-declared phone comes first
+	response, err := service.ResolveNotificationTarget(ctx, cpf, selfDeclaredPhone, basePhone, true)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if !response.Found || response.PhoneNumber != "5521987654321" || response.Source != config.NotificationPhoneSourceSelfDeclared {
+		t.Errorf("ResolveNotificationTarget() = %+v, want self-declared phone", response)
+	}
+
+	// Unverified self-declared phone falls back to the base phone
+	unverified := &models.Telefone{Indicador: utils.BoolPtr(false), Principal: selfDeclaredPhone.Principal}
+	response, err = service.ResolveNotificationTarget(ctx, cpf, unverified, basePhone, true)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if !response.Found || response.PhoneNumber != "5521912345678" || response.Source != config.NotificationPhoneSourceBase {
+		t.Errorf("ResolveNotificationTarget() = %+v, want base phone", response)
+	}
+
+	// Quarantined self-declared phone is skipped
+	quarantineUntil := time.Now().Add(24 * time.Hour)
+	_, err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).InsertOne(ctx, models.PhoneCPFMapping{
+		PhoneNumber:     "5521987654321",
+		CPF:             cpf,
+		Status:          models.MappingStatusQuarantined,
+		QuarantineUntil: &quarantineUntil,
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert phone mapping: %v", err)
+	}
+	response, err = service.ResolveNotificationTarget(ctx, cpf, selfDeclaredPhone, basePhone, true)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if !response.Found || response.Source != config.NotificationPhoneSourceBase {
+		t.Errorf("ResolveNotificationTarget() = %+v, want base phone", response)
+	}
+	if len(response.Skipped) != 1 || response.Skipped[0].Reason != models.NotificationPhoneSkipQuarantined {
+		t.Errorf("Skipped = %+v, want the quarantined self-declared phone", response.Skipped)
+	}
+
+	// Without fallback the quarantined phone leaves no target
+	config.AppConfig.NotificationPhoneFallback = []string{config.NotificationPhoneSourceSelfDeclared}
+	response, err = service.ResolveNotificationTarget(ctx, cpf, selfDeclaredPhone, basePhone, true)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if response.Found || response.Reason != models.NotificationTargetReasonPhonesBlocked {
+		t.Errorf("ResolveNotificationTarget() = %+v, want reason %q", response, models.NotificationTargetReasonPhonesBlocked)
+	}
+
+	// No verified phone at all
+	response, err = service.ResolveNotificationTarget(ctx, cpf, nil, basePhone, true)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if response.Found || response.Reason != models.NotificationTargetReasonNoVerifiedPhone {
+		t.Errorf("ResolveNotificationTarget() = %+v, want reason %q", response, models.NotificationTargetReasonNoVerifiedPhone)
+	}
+
+	// Opted-out citizens have no target
+	response, err = service.ResolveNotificationTarget(ctx, cpf, selfDeclaredPhone, basePhone, false)
+	if err != nil {
+		t.Fatalf("ResolveNotificationTarget() error = %v", err)
+	}
+	if response.Found || response.Reason != models.NotificationTargetReasonOptedOut {
+		t.Errorf("ResolveNotificationTarget() = %+v, want reason %q", response, models.NotificationTargetReasonOptedOut)
+	}
+}
@@ -245,7 +245,6 @@ func (s *PetService) RegisterPet(ctx context.Context, cpf string, req *models.Pe
 	petID := int(time.Now().Unix() + int64(objectID.Timestamp().Unix()))
 
 	// Create self-registered pet document
-	now := time.Now()
 	selfPet := models.SelfRegisteredPet{
 		ID:                 petID,
 		CPF:                cpf,
@@ -261,9 +260,8 @@ func (s *PetService) RegisterPet(ctx context.Context, cpf string, req *models.Pe
 		SizeName:           req.SizeName,
 		PhotoURL:           req.PhotoURL,
 		Source:             "self_registered",
-		CreatedAt:          now,
-		UpdatedAt:          now,
 	}
+	selfPet.Touch(time.Now())
 
 	// Insert the pet document
 	_, err := collection.InsertOne(ctx, selfPet)
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// phoneDeliveryFailuresKey counts failed deliveries to a phone within PHONE_DELIVERY_FAILURE_WINDOW
+func phoneDeliveryFailuresKey(storagePhone string) string {
+	return fmt.Sprintf("phone_delivery_failures:%s", storagePhone)
+}
+
+// RecordDeliveryReceipt records a provider delivery receipt for the phone. A delivered message
+// resets the failure counter; once PHONE_DELIVERY_FAILURE_THRESHOLD failures are counted within
+// the window, the verified self-declared phone of the CPF bound to the number is demoted back to
+// pending verification, so the citizen has to confirm it again with a code.
+func (s *PhoneMappingService) RecordDeliveryReceipt(ctx context.Context, phoneNumber, status string) (*models.PhoneDeliveryReceiptResponse, error) {
+	components, err := utils.ParsePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+
+	response := &models.PhoneDeliveryReceiptResponse{
+		PhoneNumber: storagePhone,
+		Status:      status,
+		Threshold:   config.AppConfig.PhoneDeliveryFailureThreshold,
+	}
+	observability.PhoneDeliveryReceiptsTotal.WithLabelValues(status).Inc()
+
+	key := phoneDeliveryFailuresKey(storagePhone)
+	if status == models.DeliveryStatusDelivered {
+		if err := config.Redis.Del(ctx, key).Err(); err != nil {
+			return nil, fmt.Errorf("failed to reset phone delivery failures: %w", err)
+		}
+		return response, nil
+	}
+
+	if config.AppConfig.PhoneDeliveryFailureThreshold <= 0 {
+		return response, nil
+	}
+
+	// Same INCR + PEXPIRE window as the phone verification lockout
+	window := config.AppConfig.PhoneDeliveryFailureWindow
+	result, err := config.Redis.Eval(ctx, phoneVerificationFailureScript, []string{key}, window.Milliseconds()).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record phone delivery failure: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("unexpected phone delivery failure script result: %v", result)
+	}
+	count, ok := result[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected phone delivery failure count: %v", result[0])
+	}
+	response.Failures = int(count)
+
+	if response.Failures < config.AppConfig.PhoneDeliveryFailureThreshold {
+		return response, nil
+	}
+
+	var mapping models.PhoneCPFMapping
+	err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).FindOne(
+		ctx,
+		bson.M{"phone_number": storagePhone},
+	).Decode(&mapping)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get phone mapping: %w", err)
+	}
+	if err == mongo.ErrNoDocuments || mapping.CPF == "" {
+		// No citizen to demote; the counter is kept in case the number gets bound later
+		return response, nil
+	}
+	response.CPF = mapping.CPF
+
+	demoted, err := s.demoteSelfDeclaredPhone(ctx, mapping.CPF, storagePhone)
+	if err != nil {
+		return nil, err
+	}
+	if !demoted {
+		return response, nil
+	}
+	response.Demoted = true
+	observability.PhoneDemotionsTotal.Inc()
+
+	if err := config.Redis.Del(ctx, key).Err(); err != nil {
+		s.logger.Warn("failed to reset phone delivery failures after demotion", zap.Error(err), zap.String("phone_number", storagePhone))
+	}
+	s.recordOptInHistory(ctx, phoneNumber, mapping.CPF, models.OptInActionPhoneDemoted, models.ChannelWhatsApp, models.PhoneDemotionReasonDeliveryFailures)
+
+	s.logger.Info("self-declared phone demoted after repeated delivery failures",
+		zap.String("cpf", mapping.CPF),
+		zap.String("phone_number", storagePhone),
+		zap.Int("failures", response.Failures))
+	return response, nil
+}
+
+// demoteSelfDeclaredPhone drops the given number from the CPF's verified self-declared phones
+// and puts it back in telefone_pending. When it was the primary phone, the most recently verified
+// of the remaining phones becomes primary, or the telefone is marked as unverified if none is
+// left. It reports whether the phone was demoted.
+func (s *PhoneMappingService) demoteSelfDeclaredPhone(ctx context.Context, cpf, storagePhone string) (bool, error) {
+	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
+	var phoneData struct {
+		Telefone *models.Telefone `json:"telefone" bson:"telefone"`
+	}
+	err := dataManager.Read(ctx, cpf, config.AppConfig.SelfDeclaredCollection, "self_declared_phone", &phoneData)
+	if err == ErrDocumentNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get self-declared phone: %w", err)
+	}
+
+	// Only verified self-declared phones are demoted; base phones and other numbers are left alone
+	verified := utils.VerifiedPhones(phoneData.Telefone)
+	if len(verified) == 0 {
+		return false, nil
+	}
+	telefone := *phoneData.Telefone
+	telefone.Verificados = verified
+	phone := telefone.FindVerified(storagePhone)
+	if phone == nil {
+		return false, nil
+	}
+	demotedPhone := *phone
+	telefone.RemoveVerified(storagePhone)
+
+	now := time.Now()
+	origem := models.TelefoneOrigemSelfDeclared
+	sistema := models.TelefoneSistemaRMI
+	demoted := &models.Telefone{
+		Indicador: utils.BoolPtr(false),
+		Principal: &models.TelefonePrincipal{
+			DDI:       demotedPhone.DDI,
+			DDD:       demotedPhone.DDD,
+			Valor:     demotedPhone.Valor,
+			Origem:    &origem,
+			Sistema:   &sistema,
+			UpdatedAt: &now,
+		},
+	}
+	if demotedPhone.Principal {
+		if next := latestVerifiedPhone(telefone.Verificados); next != "" {
+			telefone.SetPrimary(next, now)
+		} else {
+			telefone.Indicador = utils.BoolPtr(false)
+			principal := *telefone.Principal
+			principal.UpdatedAt = &now
+			telefone.Principal = &principal
+			demoted.Principal = &principal
+			demoted.Alternativo = telefone.Alternativo
+		}
+	}
+
+	if _, err := NewCacheService().UpdateSelfDeclaredPhone(ctx, cpf, &telefone, nil); err != nil {
+		return false, fmt.Errorf("failed to demote self-declared phone: %w", err)
+	}
+
+	_, err = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).UpdateOne(
+		ctx,
+		bson.M{"cpf": cpf},
+		utils.TimestampedUpdate(bson.M{"telefone_pending": demoted}, now),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to set pending phone: %w", err)
+	}
+
+	if err := utils.InvalidateCitizenCache(ctx, cpf); err != nil {
+		s.logger.Warn("failed to invalidate citizen cache after phone demotion", zap.Error(err), zap.String("cpf", cpf))
+	}
+	return true, nil
+}
+
+// latestVerifiedPhone returns the ID of the most recently verified phone, or an empty string
+// when there's none
+func latestVerifiedPhone(phones []models.TelefoneVerificado) string {
+	latest := -1
+	for i, phone := range phones {
+		if latest < 0 || (phone.VerifiedAt != nil && (phones[latest].VerifiedAt == nil || phone.VerifiedAt.After(*phones[latest].VerifiedAt))) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return ""
+	}
+	return phones[latest].ID
+}
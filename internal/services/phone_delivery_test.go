@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	deliveryTestPhone        = "+5521987650001"
+	deliveryTestStoragePhone = "5521987650001"
+	deliveryTestCPF          = "98765432100"
+)
+
+func setupPhoneDeliveryTest(t *testing.T, threshold int) (*PhoneMappingService, func()) {
+	setupTestEnvironment()
+	if config.Redis == nil || config.MongoDB == nil {
+		t.Skip("Skipping phone delivery tests: Redis or MongoDB not available")
+	}
+
+	origThreshold, origWindow := config.AppConfig.PhoneDeliveryFailureThreshold, config.AppConfig.PhoneDeliveryFailureWindow
+	config.AppConfig.PhoneDeliveryFailureThreshold = threshold
+	config.AppConfig.PhoneDeliveryFailureWindow = time.Hour
+
+	ctx := context.Background()
+	cleanup := func() {
+		config.Redis.Del(ctx, phoneDeliveryFailuresKey(deliveryTestStoragePhone),
+			"self_declared_phone:write:"+deliveryTestCPF, "self_declared_phone:cache:"+deliveryTestCPF)
+		config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).DeleteMany(ctx, bson.M{"phone_number": deliveryTestStoragePhone})
+		config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).DeleteMany(ctx, bson.M{"cpf": deliveryTestCPF})
+		config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).DeleteMany(ctx, bson.M{"cpf": deliveryTestCPF})
+	}
+	cleanup()
+
+	_, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).InsertOne(ctx, models.PhoneCPFMapping{
+		PhoneNumber: deliveryTestStoragePhone,
+		CPF:         deliveryTestCPF,
+		Status:      models.MappingStatusActive,
+		OptIn:       true,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert phone mapping: %v", err)
+	}
+	ddi, ddd, valor := "55", "21", "987650001"
+	_, err = config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).InsertOne(ctx, models.SelfDeclaredData{
+		CPF: deliveryTestCPF,
+		Telefone: &models.Telefone{
+			Indicador: utils.BoolPtr(true),
+			Principal: &models.TelefonePrincipal{
+				DDI:   &ddi,
+				DDD:   &ddd,
+				Valor: &valor,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert self-declared data: %v", err)
+	}
+
+	return NewPhoneMappingService(logging.GetLogger()), func() {
+		cleanup()
+		config.AppConfig.PhoneDeliveryFailureThreshold = origThreshold
+		config.AppConfig.PhoneDeliveryFailureWindow = origWindow
+	}
+}
+
+func TestRecordDeliveryReceipt_DemotesAfterThreshold(t *testing.T) {
+	service, cleanup := setupPhoneDeliveryTest(t, 2)
+	defer cleanup()
+	ctx := context.Background()
+
+	response, err := service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	if err != nil {
+		t.Fatalf("RecordDeliveryReceipt() error = %v", err)
+	}
+	if response.Failures != 1 || response.Demoted {
+		t.Errorf("first failure = (%d, demoted %v), want (1, false)", response.Failures, response.Demoted)
+	}
+
+	response, err = service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	if err != nil {
+		t.Fatalf("RecordDeliveryReceipt() error = %v", err)
+	}
+	if !response.Demoted || response.CPF != deliveryTestCPF {
+		t.Fatalf("second failure = %+v, want demoted for %s", response, deliveryTestCPF)
+	}
+
+	dataManager := NewDataManager(config.Redis, config.MongoDB, logging.GetLogger())
+	var phoneData struct {
+		Telefone *models.Telefone `json:"telefone"`
+	}
+	if err := dataManager.Read(ctx, deliveryTestCPF, config.AppConfig.SelfDeclaredCollection, "self_declared_phone", &phoneData); err != nil {
+		t.Fatalf("failed to read self-declared phone: %v", err)
+	}
+	if phoneData.Telefone == nil || phoneData.Telefone.Indicador == nil || *phoneData.Telefone.Indicador {
+		t.Errorf("self-declared phone after demotion = %+v, want unverified", phoneData.Telefone)
+	}
+
+	count, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).CountDocuments(ctx, bson.M{
+		"cpf":    deliveryTestCPF,
+		"action": models.OptInActionPhoneDemoted,
+	})
+	if err != nil || count != 1 {
+		t.Errorf("phone_demoted history records = (%d, %v), want 1", count, err)
+	}
+
+	// The phone is no longer verified, so further failures don't demote it again
+	service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	response, err = service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	if err != nil || response.Demoted {
+		t.Errorf("failures after demotion = (%+v, %v), want not demoted", response, err)
+	}
+}
+
+func TestRecordDeliveryReceipt_DeliveredResetsCounter(t *testing.T) {
+	service, cleanup := setupPhoneDeliveryTest(t, 2)
+	defer cleanup()
+	ctx := context.Background()
+
+	service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	if _, err := service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusDelivered); err != nil {
+		t.Fatalf("RecordDeliveryReceipt(delivered) error = %v", err)
+	}
+
+	response, err := service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+	if err != nil || response.Failures != 1 || response.Demoted {
+		t.Errorf("failure after delivery = (%+v, %v), want 1 failure and not demoted", response, err)
+	}
+}
+
+func TestRecordDeliveryReceipt_Disabled(t *testing.T) {
+	service, cleanup := setupPhoneDeliveryTest(t, 0)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		response, err := service.RecordDeliveryReceipt(ctx, deliveryTestPhone, models.DeliveryStatusFailed)
+		if err != nil || response.Demoted || response.Failures != 0 {
+			t.Fatalf("RecordDeliveryReceipt() with demotion disabled = (%+v, %v)", response, err)
+		}
+	}
+}
+
+func TestRecordDeliveryReceipt_InvalidPhone(t *testing.T) {
+	service := NewPhoneMappingService(logging.GetLogger())
+	if _, err := service.RecordDeliveryReceipt(context.Background(), "not-a-phone", models.DeliveryStatusFailed); err == nil {
+		t.Error("RecordDeliveryReceipt() should fail for an invalid phone number")
+	}
+}
+
+func TestLatestVerifiedPhone(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	if got := latestVerifiedPhone(nil); got != "" {
+		t.Errorf("latestVerifiedPhone(nil) = %q, want empty", got)
+	}
+	phones := []models.TelefoneVerificado{
+		{ID: "5521900000001"},
+		{ID: "5521900000002", VerifiedAt: &newer},
+		{ID: "5521900000003", VerifiedAt: &older},
+	}
+	if got := latestVerifiedPhone(phones); got != "5521900000002" {
+		t.Errorf("latestVerifiedPhone() = %q, want the most recently verified phone", got)
+	}
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -29,10 +32,17 @@ func NewPhoneMappingService(logger *logging.SafeLogger) *PhoneMappingService {
 
 // GetPhoneStatus checks the status of a phone number including quarantine status
 func (s *PhoneMappingService) GetPhoneStatus(ctx context.Context, phoneNumber string) (*models.PhoneStatusResponse, error) {
+	response, _, err := s.getPhoneStatus(ctx, phoneNumber)
+	return response, err
+}
+
+// getPhoneStatus builds the phone status and also returns the phone mapping it was built from,
+// which is nil when the phone is not found
+func (s *PhoneMappingService) getPhoneStatus(ctx context.Context, phoneNumber string) (*models.PhoneStatusResponse, *models.PhoneCPFMapping, error) {
 	// Parse phone number for storage format
 	components, err := utils.ParsePhoneNumber(phoneNumber)
 	if err != nil {
-		return nil, fmt.Errorf("invalid phone number: %w", err)
+		return nil, nil, fmt.Errorf("invalid phone number: %w", err)
 	}
 	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
 
@@ -52,10 +62,10 @@ func (s *PhoneMappingService) GetPhoneStatus(ctx context.Context, phoneNumber st
 				Quarantined:     false,
 				OptedOut:        false,
 				BetaWhitelisted: false,
-			}, nil
+			}, nil, nil
 		}
 		s.logger.Error("failed to get phone mapping", zap.Error(err), zap.String("phone_number", storagePhone))
-		return nil, fmt.Errorf("failed to get phone mapping: %w", err)
+		return nil, nil, fmt.Errorf("failed to get phone mapping: %w", err)
 	}
 
 	// Check if quarantined (computed on-demand)
@@ -104,7 +114,7 @@ func (s *PhoneMappingService) GetPhoneStatus(ctx context.Context, phoneNumber st
 		}
 	}
 
-	return response, nil
+	return response, &mapping, nil
 }
 
 // QuarantinePhone quarantines a phone number
@@ -116,6 +126,7 @@ func (s *PhoneMappingService) QuarantinePhone(ctx context.Context, phoneNumber s
 	}
 	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
 	now := time.Now()
+	defer s.invalidatePhoneStatusDetails(ctx, storagePhone)
 
 	// Calculate quarantine end date
 	quarantineTTL := config.AppConfig.PhoneQuarantineTTL
@@ -179,6 +190,10 @@ func (s *PhoneMappingService) QuarantinePhone(ctx context.Context, phoneNumber s
 		"$push": bson.M{
 			"quarantine_history": quarantineEvent,
 		},
+		// A reason from an earlier bulk quarantine no longer applies
+		"$unset": bson.M{
+			"quarantine_reason": "",
+		},
 	}
 
 	_, err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).UpdateOne(
@@ -199,6 +214,101 @@ func (s *PhoneMappingService) QuarantinePhone(ctx context.Context, phoneNumber s
 	}, nil
 }
 
+// BulkQuarantinePhones quarantines several phone numbers with a shared reason and duration in a
+// single bulk write. Invalid or repeated numbers and failed writes are reported per number.
+func (s *PhoneMappingService) BulkQuarantinePhones(ctx context.Context, phoneNumbers []string, reason string, duration time.Duration) (*models.BulkQuarantineResponse, error) {
+	now := time.Now()
+	quarantineUntil := now.Add(duration)
+
+	response := &models.BulkQuarantineResponse{
+		Reason:          reason,
+		QuarantineUntil: quarantineUntil,
+		Results:         make([]models.BulkQuarantineResult, len(phoneNumbers)),
+	}
+
+	// Build one upsert per valid number, remembering which result each operation belongs to
+	var operations []mongo.WriteModel
+	var operationResults []int
+	var operationPhones []string
+	seen := make(map[string]bool)
+	for i, phoneNumber := range phoneNumbers {
+		response.Results[i] = models.BulkQuarantineResult{PhoneNumber: phoneNumber}
+
+		components, err := utils.ParsePhoneNumber(phoneNumber)
+		if err != nil {
+			response.Results[i].Error = fmt.Sprintf("invalid phone number: %v", err)
+			continue
+		}
+		storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+		if seen[storagePhone] {
+			response.Results[i].Error = "duplicate phone number"
+			continue
+		}
+		seen[storagePhone] = true
+
+		update := bson.M{
+			"$set": bson.M{
+				"status":            models.MappingStatusQuarantined,
+				"quarantine_until":  quarantineUntil,
+				"quarantine_reason": reason,
+				"updated_at":        now,
+			},
+			"$push": bson.M{
+				"quarantine_history": models.QuarantineEvent{
+					QuarantinedAt:   now,
+					QuarantineUntil: quarantineUntil,
+					Reason:          reason,
+				},
+			},
+			"$setOnInsert": bson.M{
+				"created_at": now,
+			},
+		}
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"phone_number": storagePhone}).
+			SetUpdate(update).
+			SetUpsert(true))
+		operationResults = append(operationResults, i)
+		operationPhones = append(operationPhones, storagePhone)
+	}
+
+	failedOperations := make(map[int]string)
+	_, err := utils.BulkWriteWithWriteConcern(ctx, config.AppConfig.PhoneMappingCollection, operations, "phone_quarantine")
+	if err != nil {
+		// The bulk write is unordered: only the operations listed in the exception failed
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || len(bulkErr.WriteErrors) == 0 {
+			s.logger.Error("failed to bulk quarantine phones", zap.Error(err), zap.Int("count", len(operations)))
+			return nil, fmt.Errorf("failed to bulk quarantine phones: %w", err)
+		}
+		for _, writeErr := range bulkErr.WriteErrors {
+			failedOperations[writeErr.Index] = writeErr.Message
+		}
+	}
+
+	var quarantinedPhones []string
+	for op, i := range operationResults {
+		if message, failed := failedOperations[op]; failed {
+			response.Results[i].Error = "failed to quarantine phone: " + message
+			continue
+		}
+		response.Results[i].Success = true
+		quarantinedPhones = append(quarantinedPhones, operationPhones[op])
+	}
+	s.invalidatePhoneStatusDetails(ctx, quarantinedPhones...)
+
+	for _, result := range response.Results {
+		if result.Success {
+			response.Quarantined++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
 // ReleaseQuarantine releases a phone number from quarantine
 func (s *PhoneMappingService) ReleaseQuarantine(ctx context.Context, phoneNumber string) (*models.QuarantineResponse, error) {
 	// Parse phone number for storage format
@@ -208,6 +318,7 @@ func (s *PhoneMappingService) ReleaseQuarantine(ctx context.Context, phoneNumber
 	}
 	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
 	now := time.Now()
+	defer s.invalidatePhoneStatusDetails(ctx, storagePhone)
 
 	// Find the phone mapping
 	var mapping models.PhoneCPFMapping
@@ -260,6 +371,9 @@ func (s *PhoneMappingService) ReleaseQuarantine(ctx context.Context, phoneNumber
 			"updated_at":         now,
 			"quarantine_history": mapping.QuarantineHistory,
 		},
+		"$unset": bson.M{
+			"quarantine_reason": "",
+		},
 	}
 
 	_, err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).UpdateOne(
@@ -280,6 +394,68 @@ func (s *PhoneMappingService) ReleaseQuarantine(ctx context.Context, phoneNumber
 	}, nil
 }
 
+// ReleaseExpiredQuarantines releases up to batchSize phone numbers whose quarantine has expired,
+// oldest first, in a single bulk write. As in ReleaseQuarantine, mappings with a CPF become
+// active again and mappings without one are removed. Returns the released phone numbers
+// (storage format).
+func (s *PhoneMappingService) ReleaseExpiredQuarantines(ctx context.Context, batchSize int) ([]string, error) {
+	now := time.Now()
+	collection := config.AppConfig.PhoneMappingCollection
+
+	// Range query on quarantine_until_1; null values never match $lte
+	expiredFilter := bson.M{"quarantine_until": bson.M{"$lte": now}}
+	cursor, err := config.MongoDB.Collection(collection).Find(ctx, expiredFilter,
+		options.Find().
+			SetSort(bson.D{{Key: "quarantine_until", Value: 1}}).
+			SetLimit(int64(batchSize)).
+			SetProjection(bson.M{"phone_number": 1, "cpf": 1, "quarantine_history": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired quarantines: %w", err)
+	}
+	var expired []models.PhoneCPFMapping
+	if err := cursor.All(ctx, &expired); err != nil {
+		return nil, fmt.Errorf("failed to decode expired quarantines: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	operations := make([]mongo.WriteModel, 0, len(expired))
+	released := make([]string, 0, len(expired))
+	for _, mapping := range expired {
+		// Skip mappings quarantined again since they were read
+		filter := bson.M{"phone_number": mapping.PhoneNumber, "quarantine_until": bson.M{"$lte": now}}
+
+		if mapping.CPF == "" {
+			operations = append(operations, mongo.NewDeleteOneModel().SetFilter(filter))
+		} else {
+			set := bson.M{
+				"status":           models.MappingStatusActive,
+				"quarantine_until": nil,
+				"updated_at":       now,
+			}
+			if n := len(mapping.QuarantineHistory); n > 0 {
+				set[fmt.Sprintf("quarantine_history.%d.released_at", n-1)] = now
+			}
+			operations = append(operations, mongo.NewUpdateOneModel().
+				SetFilter(filter).
+				SetUpdate(bson.M{
+					"$set":   set,
+					"$unset": bson.M{"quarantine_reason": ""},
+				}))
+		}
+		released = append(released, mapping.PhoneNumber)
+	}
+
+	if _, err := utils.BulkWriteWithWriteConcern(ctx, collection, operations, "phone_quarantine_release"); err != nil {
+		return nil, fmt.Errorf("failed to release expired quarantines: %w", err)
+	}
+	s.invalidatePhoneStatusDetails(ctx, released...)
+
+	return released, nil
+}
+
 // BindPhoneToCPF binds a phone number to a CPF without setting opt-in
 func (s *PhoneMappingService) BindPhoneToCPF(ctx context.Context, phoneNumber, cpf, channel string) (*models.BindResponse, error) {
 	// Parse phone number for storage format
@@ -294,6 +470,7 @@ func (s *PhoneMappingService) BindPhoneToCPF(ctx context.Context, phoneNumber, c
 	if !utils.ValidateCPF(cpf) {
 		return nil, fmt.Errorf("invalid CPF format")
 	}
+	defer s.invalidatePhoneStatusDetails(ctx, storagePhone)
 
 	// Check if phone mapping exists
 	var existingMapping models.PhoneCPFMapping
@@ -346,6 +523,7 @@ func (s *PhoneMappingService) BindPhoneToCPF(ctx context.Context, phoneNumber, c
 	// If was quarantined, release it
 	if existingMapping.QuarantineUntil != nil {
 		update["$set"].(bson.M)["quarantine_until"] = nil
+		update["$unset"] = bson.M{"quarantine_reason": ""}
 
 		// Add release time to last quarantine event
 		if len(existingMapping.QuarantineHistory) > 0 {
@@ -375,8 +553,74 @@ func (s *PhoneMappingService) BindPhoneToCPF(ctx context.Context, phoneNumber, c
 	}, nil
 }
 
-// GetQuarantinedPhones returns a paginated list of quarantined phone numbers
-func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, page, perPage int, expired bool) (*models.QuarantinedListResponse, error) {
+// QuarantinedPhonesFilter narrows the quarantined phones listing and export
+type QuarantinedPhonesFilter struct {
+	// Reason keeps only quarantines with this reason
+	Reason string
+	// QuarantinedAfter and QuarantinedBefore bound when the phone was quarantined (inclusive and
+	// exclusive, respectively)
+	QuarantinedAfter  *time.Time
+	QuarantinedBefore *time.Time
+}
+
+// quarantinedPhonesCSVHeader is the localized header row of the quarantined phones export
+var quarantinedPhonesCSVHeader = []string{"Telefone", "CPF", "Motivo", "Início da quarentena", "Fim da quarentena", "Expirada"}
+
+// quarantinedPhonesQuery builds the MongoDB filter for quarantined phones. The quarantine_until
+// range always leads the filter, so the quarantine_until_1 index serves both the match and the
+// sort; the reason and period conditions are applied on the indexed candidates.
+func quarantinedPhonesQuery(filter QuarantinedPhonesFilter, expired bool, now time.Time) bson.M {
+	query := bson.M{"quarantine_until": bson.M{"$gt": now}}
+	if expired {
+		query["quarantine_until"] = bson.M{"$lte": now}
+	}
+
+	if filter.Reason != "" {
+		query["quarantine_reason"] = filter.Reason
+	}
+
+	if filter.QuarantinedAfter != nil || filter.QuarantinedBefore != nil {
+		period := bson.M{}
+		if filter.QuarantinedAfter != nil {
+			period["$gte"] = *filter.QuarantinedAfter
+		}
+		if filter.QuarantinedBefore != nil {
+			period["$lt"] = *filter.QuarantinedBefore
+		}
+		query["quarantine_history"] = bson.M{"$elemMatch": bson.M{"quarantined_at": period}}
+	}
+
+	return query
+}
+
+// toQuarantinedPhone converts a phone mapping into its admin representation, masking the CPF
+// unless maskCPF is false
+func toQuarantinedPhone(mapping models.PhoneCPFMapping, now time.Time, maskCPF bool) models.QuarantinedPhone {
+	quarantinedPhone := models.QuarantinedPhone{
+		PhoneNumber:      utils.ExtractPhoneFromComponents("55", mapping.PhoneNumber[:2], mapping.PhoneNumber[2:]),
+		QuarantineReason: mapping.QuarantineReason,
+		QuarantineUntil:  *mapping.QuarantineUntil,
+		Expired:          mapping.QuarantineUntil.Before(now),
+	}
+
+	// The latest history event is the current quarantine
+	if len(mapping.QuarantineHistory) > 0 {
+		quarantinedAt := mapping.QuarantineHistory[len(mapping.QuarantineHistory)-1].QuarantinedAt
+		quarantinedPhone.QuarantinedAt = &quarantinedAt
+	}
+
+	if mapping.CPF != "" {
+		quarantinedPhone.CPF = mapping.CPF
+		if maskCPF {
+			quarantinedPhone.CPF = utils.MaskCPF(mapping.CPF)
+		}
+	}
+
+	return quarantinedPhone
+}
+
+// GetQuarantinedPhones returns a page of quarantined phones matching the filter, with masked CPFs
+func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, filter QuarantinedPhonesFilter, page, perPage int, expired bool) (*models.QuarantinedListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -387,17 +631,10 @@ func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, page, pe
 	skip := int64((page - 1) * perPage)
 	perPage64 := int64(perPage)
 	now := time.Now()
-
-	// Build filter
-	filter := bson.M{"quarantine_until": bson.M{"$exists": true, "$ne": nil}}
-	if expired {
-		filter["quarantine_until"] = bson.M{"$lte": now}
-	} else {
-		filter["quarantine_until"] = bson.M{"$gt": now}
-	}
+	query := quarantinedPhonesQuery(filter, expired, now)
 
 	// Count total
-	total, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).CountDocuments(ctx, filter)
+	total, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).CountDocuments(ctx, query)
 	if err != nil {
 		s.logger.Error("failed to count quarantined phones", zap.Error(err))
 		return nil, fmt.Errorf("failed to count quarantined phones: %w", err)
@@ -406,7 +643,7 @@ func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, page, pe
 	// Find quarantined phones
 	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Find(
 		ctx,
-		filter,
+		query,
 		&options.FindOptions{
 			Skip:  &skip,
 			Limit: &perPage64,
@@ -427,17 +664,7 @@ func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, page, pe
 			continue
 		}
 
-		quarantinedPhone := models.QuarantinedPhone{
-			PhoneNumber:     utils.ExtractPhoneFromComponents("55", mapping.PhoneNumber[:2], mapping.PhoneNumber[2:]),
-			QuarantineUntil: *mapping.QuarantineUntil,
-			Expired:         mapping.QuarantineUntil.Before(now),
-		}
-
-		if mapping.CPF != "" {
-			quarantinedPhone.CPF = utils.MaskCPF(mapping.CPF)
-		}
-
-		quarantinedPhones = append(quarantinedPhones, quarantinedPhone)
+		quarantinedPhones = append(quarantinedPhones, toQuarantinedPhone(mapping, now, true))
 	}
 
 	totalPages := (int(total) + perPage - 1) / perPage
@@ -453,6 +680,67 @@ func (s *PhoneMappingService) GetQuarantinedPhones(ctx context.Context, page, pe
 	}, nil
 }
 
+// ExportQuarantinedPhonesCSV streams every quarantined phone matching the filter to w as a CSV
+// with localized labels, one row per phone as it is read from MongoDB. CPFs are masked unless
+// maskCPF is false. It returns the number of exported rows.
+func (s *PhoneMappingService) ExportQuarantinedPhonesCSV(ctx context.Context, w io.Writer, filter QuarantinedPhonesFilter, expired, maskCPF bool) (int, error) {
+	now := time.Now()
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Find(
+		ctx,
+		quarantinedPhonesQuery(filter, expired, now),
+		options.Find().SetSort(bson.M{"quarantine_until": 1}),
+	)
+	if err != nil {
+		s.logger.Error("failed to find quarantined phones for export", zap.Error(err))
+		return 0, fmt.Errorf("failed to find quarantined phones: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(quarantinedPhonesCSVHeader); err != nil {
+		return 0, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	location := saoPauloLocation()
+	exported := 0
+	for cursor.Next(ctx) {
+		var mapping models.PhoneCPFMapping
+		if err := cursor.Decode(&mapping); err != nil {
+			s.logger.Error("failed to decode phone mapping", zap.Error(err))
+			continue
+		}
+
+		phone := toQuarantinedPhone(mapping, now, maskCPF)
+		quarantinedAt := ""
+		if phone.QuarantinedAt != nil {
+			quarantinedAt = phone.QuarantinedAt.In(location).Format("02/01/2006 15:04:05")
+		}
+		expiredLabel := "Não"
+		if phone.Expired {
+			expiredLabel = "Sim"
+		}
+
+		record := []string{
+			phone.PhoneNumber,
+			phone.CPF,
+			phone.QuarantineReason,
+			quarantinedAt,
+			phone.QuarantineUntil.In(location).Format("02/01/2006 15:04:05"),
+			expiredLabel,
+		}
+		if err := writer.Write(record); err != nil {
+			return exported, fmt.Errorf("failed to write CSV record: %w", err)
+		}
+		exported++
+	}
+	if err := cursor.Err(); err != nil {
+		return exported, fmt.Errorf("failed to read quarantined phones: %w", err)
+	}
+
+	writer.Flush()
+	return exported, writer.Error()
+}
+
 // GetQuarantineStats returns quarantine statistics
 func (s *PhoneMappingService) GetQuarantineStats(ctx context.Context) (*models.QuarantineStats, error) {
 	now := time.Now()
@@ -543,6 +831,11 @@ func (s *PhoneMappingService) GetQuarantineStats(ctx context.Context) (*models.Q
 		}
 	}
 
+	activeByReason, err := s.countActiveQuarantinesByReason(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.QuarantineStats{
 		TotalQuarantined:       int(totalQuarantined),
 		ExpiredQuarantines:     int(expiredQuarantines),
@@ -550,9 +843,47 @@ func (s *PhoneMappingService) GetQuarantineStats(ctx context.Context) (*models.Q
 		QuarantinesWithCPF:     int(quarantinesWithCPF),
 		QuarantinesWithoutCPF:  int(quarantinesWithoutCPF),
 		QuarantineHistoryTotal: quarantineHistoryTotal,
+		ActiveByReason:         activeByReason,
 	}, nil
 }
 
+// countActiveQuarantinesByReason counts active quarantines grouped by their reason
+func (s *PhoneMappingService) countActiveQuarantinesByReason(ctx context.Context, now time.Time) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"quarantine_until": bson.M{"$gt": now}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$quarantine_reason", models.QuarantineReasonUnspecified}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		s.logger.Error("failed to count quarantines by reason", zap.Error(err))
+		return nil, fmt.Errorf("failed to count quarantines by reason: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Reason string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		s.logger.Error("failed to decode quarantines by reason", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode quarantines by reason: %w", err)
+	}
+
+	byReason := make(map[string]int, len(groups))
+	for _, group := range groups {
+		reason := group.Reason
+		if reason == "" {
+			reason = models.QuarantineReasonUnspecified
+		}
+		byReason[reason] += group.Count
+	}
+	return byReason, nil
+}
+
 // FindCPFByPhone finds a CPF by phone number (existing method, updated for new model)
 func (s *PhoneMappingService) FindCPFByPhone(ctx context.Context, phoneNumber string) (*models.PhoneCitizenResponse, error) {
 	// Parse phone number for storage format
@@ -794,6 +1125,7 @@ func (s *PhoneMappingService) OptIn(ctx context.Context, phoneNumber, cpf, chann
 	// If was quarantined, release it
 	if existingMapping.QuarantineUntil != nil {
 		update["$set"].(bson.M)["quarantine_until"] = nil
+		update["$unset"] = bson.M{"quarantine_reason": ""}
 
 		// Add release time to last quarantine event
 		if len(existingMapping.QuarantineHistory) > 0 {
@@ -935,7 +1267,7 @@ func (s *PhoneMappingService) RejectRegistration(ctx context.Context, phoneNumbe
 	}
 
 	// Record rejection in history
-	s.recordOptInHistory(ctx, phoneNumber, cpf, "rejected", "whatsapp", "Registro rejeitado pelo usuário")
+	s.recordOptInHistory(ctx, phoneNumber, cpf, models.OptInActionRejected, "whatsapp", "Registro rejeitado pelo usuário")
 
 	// Block the mapping
 	update := bson.M{
@@ -972,6 +1304,15 @@ func (s *PhoneMappingService) recordOptInHistory(ctx context.Context, phoneNumbe
 	}
 	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
 
+	// Repeated opt-ins/opt-outs don't change the consent state, so they aren't recorded again
+	if s.optInStateUnchanged(ctx, storagePhone, cpf, channel, action) {
+		s.logger.Debug("skipping opt-in history, state unchanged",
+			zap.String("phone_number", storagePhone),
+			zap.String("action", action),
+			zap.String("channel", channel))
+		return
+	}
+
 	history := models.OptInHistory{
 		PhoneNumber: storagePhone,
 		CPF:         cpf,
@@ -985,7 +1326,37 @@ func (s *PhoneMappingService) recordOptInHistory(ctx context.Context, phoneNumbe
 	if err != nil {
 		s.logger.Error("failed to record opt-in history", zap.Error(err), zap.String("phone_number", phoneNumber))
 		// Don't fail the main operation for this error
+		return
+	}
+	s.invalidatePhoneStatusDetails(ctx, storagePhone)
+}
+
+// optInStateUnchanged reports whether the last opt-in/opt-out recorded for the phone, CPF and
+// channel already has the given action. Other actions (e.g. rejected) are always recorded.
+func (s *PhoneMappingService) optInStateUnchanged(ctx context.Context, storagePhone, cpf, channel, action string) bool {
+	if action != models.OptInActionOptIn && action != models.OptInActionOptOut {
+		return false
+	}
+
+	var last models.OptInHistory
+	err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).FindOne(
+		ctx,
+		bson.M{
+			"phone_number": storagePhone,
+			"cpf":          cpf,
+			"channel":      channel,
+			"action":       bson.M{"$in": []string{models.OptInActionOptIn, models.OptInActionOptOut}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}),
+	).Decode(&last)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			// Prefer a duplicate entry over losing a consent change
+			s.logger.Warn("failed to check last opt-in history", zap.Error(err), zap.String("phone_number", storagePhone))
+		}
+		return false
 	}
+	return last.Action == action
 }
 
 // BulkUpdatePhoneStatuses updates multiple phone statuses in a single bulk operation
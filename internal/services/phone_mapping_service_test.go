@@ -279,6 +279,100 @@ func TestQuarantinePhone_ExtendExisting(t *testing.T) {
 	}
 }
 
+func TestBulkQuarantinePhones(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Existing mapping bound to a CPF keeps its CPF when quarantined
+	now := time.Now()
+	_, _ = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).InsertOne(ctx, models.PhoneCPFMapping{
+		PhoneNumber: "5521999887768",
+		CPF:         "03561350712",
+		Status:      models.MappingStatusActive,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	})
+
+	phones := []string{"+5521999887768", "+5521999887769", "invalid", "+5521999887769"}
+	response, err := service.BulkQuarantinePhones(ctx, phones, "spam", 48*time.Hour)
+	if err != nil {
+		t.Fatalf("BulkQuarantinePhones() error = %v, want nil", err)
+	}
+	if response.Quarantined != 2 || response.Failed != 2 {
+		t.Errorf("BulkQuarantinePhones() quarantined/failed = %d/%d, want 2/2", response.Quarantined, response.Failed)
+	}
+	if len(response.Results) != len(phones) {
+		t.Fatalf("Results length = %d, want %d", len(response.Results), len(phones))
+	}
+	for i, want := range []bool{true, true, false, false} {
+		if response.Results[i].Success != want {
+			t.Errorf("Results[%d].Success = %v, want %v (error: %s)", i, response.Results[i].Success, want, response.Results[i].Error)
+		}
+	}
+	if response.Results[3].Error != "duplicate phone number" {
+		t.Errorf("Results[3].Error = %q, want duplicate phone number", response.Results[3].Error)
+	}
+
+	var mapping models.PhoneCPFMapping
+	err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).FindOne(
+		ctx,
+		bson.M{"phone_number": "5521999887768"},
+	).Decode(&mapping)
+	if err != nil {
+		t.Fatalf("Failed to find quarantine record: %v", err)
+	}
+	if mapping.CPF != "03561350712" {
+		t.Errorf("Mapping CPF = %s, want 03561350712", mapping.CPF)
+	}
+	if mapping.Status != models.MappingStatusQuarantined || mapping.QuarantineReason != "spam" {
+		t.Errorf("Mapping status/reason = %s/%s, want %s/spam", mapping.Status, mapping.QuarantineReason, models.MappingStatusQuarantined)
+	}
+	if len(mapping.QuarantineHistory) != 1 || mapping.QuarantineHistory[0].Reason != "spam" {
+		t.Errorf("QuarantineHistory = %+v, want one event with reason spam", mapping.QuarantineHistory)
+	}
+	if mapping.QuarantineUntil == nil || mapping.QuarantineUntil.After(now.Add(49*time.Hour)) {
+		t.Errorf("QuarantineUntil = %v, want about 48h from now", mapping.QuarantineUntil)
+	}
+
+	count, _ := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).CountDocuments(
+		ctx,
+		bson.M{"phone_number": "5521999887769"},
+	)
+	if count != 1 {
+		t.Errorf("Quarantine records for new phone = %d, want 1", count)
+	}
+}
+
+func TestGetQuarantineStats_ByReason(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := service.BulkQuarantinePhones(ctx, []string{"+5521999000011", "+5521999000012"}, "spam", time.Hour); err != nil {
+		t.Fatalf("BulkQuarantinePhones() error = %v, want nil", err)
+	}
+	if _, err := service.BulkQuarantinePhones(ctx, []string{"+5521999000013"}, "fraud", time.Hour); err != nil {
+		t.Fatalf("BulkQuarantinePhones() error = %v, want nil", err)
+	}
+	if _, err := service.QuarantinePhone(ctx, "+5521999000014"); err != nil {
+		t.Fatalf("QuarantinePhone() error = %v, want nil", err)
+	}
+
+	stats, err := service.GetQuarantineStats(ctx)
+	if err != nil {
+		t.Fatalf("GetQuarantineStats() error = %v, want nil", err)
+	}
+	want := map[string]int{"spam": 2, "fraud": 1, models.QuarantineReasonUnspecified: 1}
+	for reason, count := range want {
+		if stats.ActiveByReason[reason] != count {
+			t.Errorf("ActiveByReason[%s] = %d, want %d", reason, stats.ActiveByReason[reason], count)
+		}
+	}
+}
+
 func TestReleaseQuarantine_WithCPF(t *testing.T) {
 	service, cleanup := setupPhoneMappingTest(t)
 	defer cleanup()
@@ -367,6 +461,95 @@ func TestReleaseQuarantine_WithoutCPF(t *testing.T) {
 	}
 }
 
+func TestReleaseExpiredQuarantines(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+
+	now := time.Now()
+	expiredOldest := now.Add(-48 * time.Hour)
+	expiredRecent := now.Add(-time.Hour)
+	active := now.Add(24 * time.Hour)
+	mappings := []interface{}{
+		models.PhoneCPFMapping{
+			PhoneNumber:      "5521988770001",
+			CPF:              "03561350712",
+			Status:           models.MappingStatusQuarantined,
+			QuarantineUntil:  &expiredOldest,
+			QuarantineReason: "fraud",
+			QuarantineHistory: []models.QuarantineEvent{
+				{QuarantinedAt: now.Add(-72 * time.Hour), QuarantineUntil: expiredOldest},
+			},
+		},
+		models.PhoneCPFMapping{
+			PhoneNumber:     "5521988770002",
+			Status:          models.MappingStatusQuarantined,
+			QuarantineUntil: &expiredRecent,
+		},
+		models.PhoneCPFMapping{
+			PhoneNumber:     "5521988770003",
+			CPF:             "03561350712",
+			Status:          models.MappingStatusQuarantined,
+			QuarantineUntil: &active,
+		},
+	}
+	if _, err := collection.InsertMany(ctx, mappings); err != nil {
+		t.Fatalf("Failed to insert mappings: %v", err)
+	}
+
+	// Batches are released oldest first
+	released, err := service.ReleaseExpiredQuarantines(ctx, 1)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredQuarantines() error = %v", err)
+	}
+	if len(released) != 1 || released[0] != "5521988770001" {
+		t.Errorf("ReleaseExpiredQuarantines() = %v, want [5521988770001]", released)
+	}
+
+	var mapping models.PhoneCPFMapping
+	if err := collection.FindOne(ctx, bson.M{"phone_number": "5521988770001"}).Decode(&mapping); err != nil {
+		t.Fatalf("Failed to find released mapping: %v", err)
+	}
+	if mapping.Status != models.MappingStatusActive {
+		t.Errorf("Status = %s, want %s", mapping.Status, models.MappingStatusActive)
+	}
+	if mapping.QuarantineUntil != nil {
+		t.Error("QuarantineUntil should be nil after release")
+	}
+	if mapping.QuarantineReason != "" {
+		t.Errorf("QuarantineReason = %q, want empty", mapping.QuarantineReason)
+	}
+	if len(mapping.QuarantineHistory) != 1 || mapping.QuarantineHistory[0].ReleasedAt == nil {
+		t.Error("Last quarantine event should have a release time")
+	}
+
+	released, err = service.ReleaseExpiredQuarantines(ctx, 10)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredQuarantines() error = %v", err)
+	}
+	if len(released) != 1 || released[0] != "5521988770002" {
+		t.Errorf("ReleaseExpiredQuarantines() = %v, want [5521988770002]", released)
+	}
+
+	// Mappings without a CPF are removed, active quarantines are kept
+	if count, _ := collection.CountDocuments(ctx, bson.M{"phone_number": "5521988770002"}); count != 0 {
+		t.Error("Mapping without CPF should be deleted when its quarantine expires")
+	}
+	if err := collection.FindOne(ctx, bson.M{"phone_number": "5521988770003"}).Decode(&mapping); err != nil {
+		t.Fatalf("Failed to find active quarantine: %v", err)
+	}
+	if mapping.Status != models.MappingStatusQuarantined {
+		t.Errorf("Status = %s, want %s", mapping.Status, models.MappingStatusQuarantined)
+	}
+
+	released, err = service.ReleaseExpiredQuarantines(ctx, 10)
+	if err != nil || len(released) != 0 {
+		t.Errorf("ReleaseExpiredQuarantines() = %v, %v, want nothing left to release", released, err)
+	}
+}
+
 func TestOptIn_NewPhone(t *testing.T) {
 	service, cleanup := setupPhoneMappingTest(t)
 	defer cleanup()
@@ -484,6 +667,53 @@ func TestOptOut_ExistingPhone(t *testing.T) {
 	}
 }
 
+func TestOptOut_Twice_RecordsSingleHistoryEntry(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	now := time.Now()
+	mapping := models.PhoneCPFMapping{
+		PhoneNumber: "5521987651238",
+		CPF:         "03561350712",
+		Status:      models.MappingStatusActive,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+	_, _ = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).InsertOne(ctx, mapping)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.OptOut(ctx, "+5521987651238", "Mensagem era engano", "whatsapp"); err != nil {
+			t.Fatalf("OptOut() call %d error = %v, want nil", i+1, err)
+		}
+	}
+
+	count, _ := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).CountDocuments(
+		ctx,
+		bson.M{"phone_number": "5521987651238", "action": "opt_out"},
+	)
+	if count != 1 {
+		t.Errorf("OptOutHistory count = %d, want 1", count)
+	}
+
+	// Opting in and out again changes the state, so both are recorded
+	if _, err := service.OptIn(ctx, "+5521987651238", "03561350712", "whatsapp"); err != nil {
+		t.Fatalf("OptIn() error = %v, want nil", err)
+	}
+	if _, err := service.OptOut(ctx, "+5521987651238", "Mensagem era engano", "whatsapp"); err != nil {
+		t.Fatalf("OptOut() error = %v, want nil", err)
+	}
+
+	count, _ = config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).CountDocuments(
+		ctx,
+		bson.M{"phone_number": "5521987651238"},
+	)
+	if count != 3 {
+		t.Errorf("OptInHistory count = %d, want 3", count)
+	}
+}
+
 func TestOptOut_NewPhone(t *testing.T) {
 	service, cleanup := setupPhoneMappingTest(t)
 	defer cleanup()
@@ -634,3 +864,56 @@ func TestGetQuarantineStats(t *testing.T) {
 		t.Errorf("QuarantinesWithoutCPF = %d, want 1", stats.QuarantinesWithoutCPF)
 	}
 }
+
+func TestQuarantinedPhonesQuery(t *testing.T) {
+	now := time.Now()
+	after := now.Add(-48 * time.Hour)
+	before := now.Add(-24 * time.Hour)
+
+	query := quarantinedPhonesQuery(QuarantinedPhonesFilter{}, false, now)
+	if got := query["quarantine_until"]; got.(bson.M)["$gt"] != now {
+		t.Errorf("quarantine_until = %v, want $gt now", got)
+	}
+	if len(query) != 1 {
+		t.Errorf("unfiltered query = %v, want only quarantine_until", query)
+	}
+
+	query = quarantinedPhonesQuery(QuarantinedPhonesFilter{Reason: "fraud", QuarantinedAfter: &after, QuarantinedBefore: &before}, true, now)
+	if got := query["quarantine_until"]; got.(bson.M)["$lte"] != now {
+		t.Errorf("quarantine_until = %v, want $lte now", got)
+	}
+	if query["quarantine_reason"] != "fraud" {
+		t.Errorf("quarantine_reason = %v, want fraud", query["quarantine_reason"])
+	}
+	period := query["quarantine_history"].(bson.M)["$elemMatch"].(bson.M)["quarantined_at"].(bson.M)
+	if period["$gte"] != after || period["$lt"] != before {
+		t.Errorf("quarantined_at = %v, want [%v, %v)", period, after, before)
+	}
+}
+
+func TestToQuarantinedPhone(t *testing.T) {
+	now := time.Now()
+	until := now.Add(time.Hour)
+	mapping := models.PhoneCPFMapping{
+		PhoneNumber:       "5521999887766",
+		CPF:               "03561350712",
+		QuarantineUntil:   &until,
+		QuarantineReason:  "fraud",
+		QuarantineHistory: []models.QuarantineEvent{{QuarantinedAt: now.Add(-time.Hour)}, {QuarantinedAt: now}},
+	}
+
+	masked := toQuarantinedPhone(mapping, now, true)
+	if masked.CPF == mapping.CPF {
+		t.Errorf("CPF = %q, want masked", masked.CPF)
+	}
+	if masked.QuarantinedAt == nil || !masked.QuarantinedAt.Equal(now) {
+		t.Errorf("QuarantinedAt = %v, want latest history event %v", masked.QuarantinedAt, now)
+	}
+	if masked.Expired {
+		t.Error("Expired = true, want false")
+	}
+
+	if unmasked := toQuarantinedPhone(mapping, now, false); unmasked.CPF != mapping.CPF {
+		t.Errorf("CPF = %q, want %q", unmasked.CPF, mapping.CPF)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// OptInHistoryFilter narrows the opt-in history query. At least one of CPF and PhoneNumber must
+// be set, so the query is served by the cpf_1 or phone_number_1 index.
+type OptInHistoryFilter struct {
+	// CPF keeps only actions of this CPF
+	CPF string
+	// PhoneNumber keeps only actions of this phone, in storage format (e.g. 5521999999999)
+	PhoneNumber string
+	// From and To bound the action timestamp (inclusive and exclusive, respectively)
+	From *time.Time
+	To   *time.Time
+}
+
+// optInHistoryCSVHeader is the localized header row of the opt-in history export
+var optInHistoryCSVHeader = []string{"Data/Hora", "Ação", "Telefone", "CPF", "Canal", "Escopo", "Categoria", "Motivo", "Observação"}
+
+// optInActionLabels maps opt-in history actions to their localized labels
+var optInActionLabels = map[string]string{
+	models.OptInActionOptIn:          "Opt-in",
+	models.OptInActionOptOut:         "Opt-out",
+	models.OptInActionRejected:       "Cadastro rejeitado",
+	models.OptInActionCategoryUpdate: "Atualização de categoria",
+	models.OptInActionPhoneDemoted:   "Telefone rebaixado",
+}
+
+// optInHistorySort orders the history chronologically, breaking timestamp ties by insertion order
+var optInHistorySort = bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}
+
+// optInHistoryQuery builds the MongoDB filter for the opt-in history
+func optInHistoryQuery(filter OptInHistoryFilter) (bson.M, error) {
+	if filter.CPF == "" && filter.PhoneNumber == "" {
+		return nil, fmt.Errorf("cpf or phone_number is required")
+	}
+
+	query := bson.M{}
+	if filter.CPF != "" {
+		query["cpf"] = filter.CPF
+	}
+	if filter.PhoneNumber != "" {
+		query["phone_number"] = filter.PhoneNumber
+	}
+
+	if filter.From != nil || filter.To != nil {
+		period := bson.M{}
+		if filter.From != nil {
+			period["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			period["$lt"] = *filter.To
+		}
+		query["timestamp"] = period
+	}
+
+	return query, nil
+}
+
+// GetOptInHistory returns a page of the opt-in history matching the filter, oldest action first
+func (s *PhoneMappingService) GetOptInHistory(ctx context.Context, filter OptInHistoryFilter, page, perPage int) (*models.OptInHistoryListResponse, error) {
+	query, err := optInHistoryQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	collection := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection)
+
+	// Count total
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		s.logger.Error("failed to count opt-in history", zap.Error(err))
+		return nil, fmt.Errorf("failed to count opt-in history: %w", err)
+	}
+
+	// Find history entries
+	cursor, err := collection.Find(
+		ctx,
+		query,
+		options.Find().
+			SetSort(optInHistorySort).
+			SetSkip(int64((page-1)*perPage)).
+			SetLimit(int64(perPage)),
+	)
+	if err != nil {
+		s.logger.Error("failed to find opt-in history", zap.Error(err))
+		return nil, fmt.Errorf("failed to find opt-in history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := []models.OptInHistory{}
+	if err := cursor.All(ctx, &history); err != nil {
+		s.logger.Error("failed to decode opt-in history", zap.Error(err))
+		return nil, fmt.Errorf("failed to decode opt-in history: %w", err)
+	}
+
+	return &models.OptInHistoryListResponse{
+		Data: history,
+		Pagination: models.PaginationInfo{
+			Page:       page,
+			PerPage:    perPage,
+			Total:      int(total),
+			TotalPages: (int(total) + perPage - 1) / perPage,
+		},
+	}, nil
+}
+
+// ExportOptInHistoryCSV streams every opt-in history entry matching the filter to w as a CSV with
+// localized labels, oldest action first. It returns the number of exported rows.
+func (s *PhoneMappingService) ExportOptInHistoryCSV(ctx context.Context, w io.Writer, filter OptInHistoryFilter) (int, error) {
+	query, err := optInHistoryQuery(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).Find(
+		ctx,
+		query,
+		options.Find().SetSort(optInHistorySort),
+	)
+	if err != nil {
+		s.logger.Error("failed to find opt-in history for export", zap.Error(err))
+		return 0, fmt.Errorf("failed to find opt-in history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(optInHistoryCSVHeader); err != nil {
+		return 0, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	location := saoPauloLocation()
+	exported := 0
+	for cursor.Next(ctx) {
+		var entry models.OptInHistory
+		if err := cursor.Decode(&entry); err != nil {
+			s.logger.Error("failed to decode opt-in history entry", zap.Error(err))
+			continue
+		}
+
+		if err := writer.Write(optInHistoryCSVRecord(entry, location)); err != nil {
+			return exported, fmt.Errorf("failed to write CSV record: %w", err)
+		}
+		exported++
+	}
+	if err := cursor.Err(); err != nil {
+		return exported, fmt.Errorf("failed to read opt-in history: %w", err)
+	}
+
+	writer.Flush()
+	return exported, writer.Error()
+}
+
+// optInHistoryCSVRecord converts a history entry into a CSV row, with the timestamp in location
+func optInHistoryCSVRecord(entry models.OptInHistory, location *time.Location) []string {
+	action, ok := optInActionLabels[entry.Action]
+	if !ok {
+		action = entry.Action
+	}
+
+	phone := ""
+	if entry.PhoneNumber != "" {
+		phone = "+" + entry.PhoneNumber
+	}
+
+	optional := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+
+	return []string{
+		entry.Timestamp.In(location).Format("02/01/2006 15:04:05"),
+		action,
+		phone,
+		entry.CPF,
+		entry.Channel,
+		entry.Scope,
+		optional(entry.Category),
+		optional(entry.Reason),
+		optional(entry.Note),
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOptInHistoryQuery(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  OptInHistoryFilter
+		want    bson.M
+		wantErr bool
+	}{
+		{"no cpf or phone", OptInHistoryFilter{From: &from}, nil, true},
+		{"cpf", OptInHistoryFilter{CPF: "03561350712"}, bson.M{"cpf": "03561350712"}, false},
+		{"phone", OptInHistoryFilter{PhoneNumber: "5521999887766"}, bson.M{"phone_number": "5521999887766"}, false},
+		{
+			"cpf, phone and period",
+			OptInHistoryFilter{CPF: "03561350712", PhoneNumber: "5521999887766", From: &from, To: &to},
+			bson.M{"cpf": "03561350712", "phone_number": "5521999887766", "timestamp": bson.M{"$gte": from, "$lt": to}},
+			false,
+		},
+		{"open period", OptInHistoryFilter{CPF: "03561350712", To: &to}, bson.M{"cpf": "03561350712", "timestamp": bson.M{"$lt": to}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := optInHistoryQuery(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("optInHistoryQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("optInHistoryQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptInHistoryCSVRecord(t *testing.T) {
+	reason := "too_many_messages"
+	entry := models.OptInHistory{
+		PhoneNumber: "5521999887766",
+		CPF:         "03561350712",
+		Action:      models.OptInActionOptOut,
+		Channel:     models.ChannelWhatsApp,
+		Scope:       models.OptInScopeGlobal,
+		Reason:      &reason,
+		Timestamp:   time.Date(2025, 3, 10, 15, 4, 5, 0, time.UTC),
+	}
+
+	got := optInHistoryCSVRecord(entry, time.FixedZone("BRT", -3*60*60))
+	want := []string{"10/03/2025 12:04:05", "Opt-out", "+5521999887766", "03561350712", models.ChannelWhatsApp, models.OptInScopeGlobal, "", reason, ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("optInHistoryCSVRecord() = %v, want %v", got, want)
+	}
+
+	// Unknown actions keep their raw value
+	entry.Action = "custom_action"
+	entry.PhoneNumber = ""
+	got = optInHistoryCSVRecord(entry, time.UTC)
+	if got[1] != "custom_action" || got[2] != "" {
+		t.Errorf("optInHistoryCSVRecord() = %v, want raw action and empty phone", got)
+	}
+	if len(got) != len(optInHistoryCSVHeader) {
+		t.Errorf("optInHistoryCSVRecord() has %d columns, header has %d", len(got), len(optInHistoryCSVHeader))
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// ErrPhoneAlreadyAssigned is returned when the phone's only active mapping already belongs to the
+// target CPF
+var ErrPhoneAlreadyAssigned = errors.New("phone number is already assigned to this CPF")
+
+// phoneReassignChannel is the channel recorded for mappings and opt-in history changed by an admin
+// reassignment
+const phoneReassignChannel = "admin"
+
+// PhoneReassignment describes a completed reassignment, for the response and the audit trail
+type PhoneReassignment struct {
+	PhoneNumber string
+	CPF         string
+	// Previous holds the mappings that were active for other CPFs before the reassignment
+	Previous []models.PhoneCPFMapping
+}
+
+// PreviousCPFs returns the CPFs whose mappings were unbound
+func (r *PhoneReassignment) PreviousCPFs() []string {
+	cpfs := make([]string, 0, len(r.Previous))
+	for _, mapping := range r.Previous {
+		cpfs = append(cpfs, mapping.CPF)
+	}
+	return cpfs
+}
+
+// ReassignPhone moves a phone number to another CPF, e.g. when the number was recycled by the
+// carrier. Active mappings of other CPFs are unbound and lose their opt-in, the target CPF gets
+// an active mapping without opt-in (released from any quarantine) and both sides are recorded in
+// the opt-in history. Since phone mappings are unique per CPF and phone, not per phone, the steps
+// run through utils.ExecuteWithTransaction so that a failure leaves exactly the previous state
+// and at most one active mapping remains for the phone.
+func (s *PhoneMappingService) ReassignPhone(ctx context.Context, phoneNumber, cpf, reason string) (*PhoneReassignment, error) {
+	components, err := utils.ParsePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+
+	if !utils.ValidateCPF(cpf) {
+		return nil, fmt.Errorf("invalid CPF format")
+	}
+
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+	cursor, err := collection.Find(ctx, bson.M{"phone_number": storagePhone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phone mappings: %w", err)
+	}
+	var mappings []models.PhoneCPFMapping
+	if err := cursor.All(ctx, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to decode phone mappings: %w", err)
+	}
+
+	reassignment := &PhoneReassignment{PhoneNumber: storagePhone, CPF: cpf}
+	var target *models.PhoneCPFMapping
+	otherActive := false
+	for i := range mappings {
+		switch {
+		case mappings[i].CPF == cpf:
+			target = &mappings[i]
+		case mappings[i].Status == models.MappingStatusActive:
+			otherActive = true
+		}
+	}
+	if !otherActive && target != nil && target.Status == models.MappingStatusActive {
+		return nil, ErrPhoneAlreadyAssigned
+	}
+
+	now := time.Now()
+	// Active mappings of the phone owned by other CPFs, whatever they are when the transaction runs
+	previousFilter := bson.M{
+		"phone_number": storagePhone,
+		"status":       models.MappingStatusActive,
+		"cpf":          bson.M{"$ne": cpf},
+	}
+	var historyIDs []interface{}
+
+	operations := []utils.DatabaseOperation{
+		{
+			// Unbind the mappings of the previous owners
+			Operation: func() error {
+				cursor, err := collection.Find(ctx, previousFilter)
+				if err != nil {
+					return fmt.Errorf("failed to get previous phone mappings: %w", err)
+				}
+				if err := cursor.All(ctx, &reassignment.Previous); err != nil {
+					return fmt.Errorf("failed to decode previous phone mappings: %w", err)
+				}
+				if len(reassignment.Previous) == 0 {
+					return nil
+				}
+				_, err = collection.UpdateMany(ctx,
+					previousFilter,
+					bson.M{"$set": bson.M{
+						"status":     models.MappingStatusUnbound,
+						"opt_in":     false,
+						"updated_at": now,
+					}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to unbind previous phone mappings: %w", err)
+				}
+				return nil
+			},
+			Rollback: func() error {
+				for _, mapping := range reassignment.Previous {
+					if _, err := collection.UpdateOne(ctx,
+						bson.M{"phone_number": storagePhone, "cpf": mapping.CPF},
+						bson.M{"$set": bson.M{
+							"status":     mapping.Status,
+							"opt_in":     mapping.OptIn,
+							"updated_at": mapping.UpdatedAt,
+						}},
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// Bind the phone to the new owner
+			Operation: func() error {
+				if target == nil {
+					_, err := collection.InsertOne(ctx, models.PhoneCPFMapping{
+						PhoneNumber: storagePhone,
+						CPF:         cpf,
+						Status:      models.MappingStatusActive,
+						Channel:     phoneReassignChannel,
+						CreatedAt:   &now,
+						UpdatedAt:   &now,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create phone mapping: %w", err)
+					}
+					return nil
+				}
+
+				_, err := collection.UpdateOne(ctx,
+					bson.M{"phone_number": storagePhone, "cpf": cpf},
+					bson.M{
+						"$set": bson.M{
+							"status":     models.MappingStatusActive,
+							"opt_in":     false,
+							"channel":    phoneReassignChannel,
+							"updated_at": now,
+						},
+						"$unset": bson.M{"quarantine_until": "", "quarantine_reason": ""},
+					},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to update phone mapping: %w", err)
+				}
+				return nil
+			},
+			Rollback: func() error {
+				filter := bson.M{"phone_number": storagePhone, "cpf": cpf}
+				if target == nil {
+					_, err := collection.DeleteOne(ctx, filter)
+					return err
+				}
+				_, err := collection.ReplaceOne(ctx, filter, target)
+				return err
+			},
+		},
+		{
+			// Record the change of ownership in the opt-in history of both sides
+			Operation: func() error {
+				previousCPFs := reassignment.PreviousCPFs()
+				entries := make([]interface{}, 0, len(previousCPFs)+1)
+				for _, previousCPF := range previousCPFs {
+					entries = append(entries, phoneReassignHistory(storagePhone, previousCPF, models.OptInActionOptOut, reason, now))
+				}
+				entries = append(entries, phoneReassignHistory(storagePhone, cpf, models.OptInActionReassigned, reason, now))
+
+				result, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).InsertMany(ctx, entries)
+				if err != nil {
+					return fmt.Errorf("failed to record opt-in history: %w", err)
+				}
+				historyIDs = result.InsertedIDs
+				return nil
+			},
+			Rollback: func() error {
+				if len(historyIDs) == 0 {
+					return nil
+				}
+				_, err := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": historyIDs}})
+				return err
+			},
+		},
+	}
+
+	if err := utils.ExecuteWithTransaction(ctx, operations); err != nil {
+		s.logger.Error("failed to reassign phone", zap.Error(err), zap.String("phone_number", storagePhone))
+		return nil, err
+	}
+
+	s.invalidatePhoneMappingCache(ctx, storagePhone)
+
+	s.logger.Info("phone reassigned",
+		zap.String("phone_number", storagePhone),
+		zap.Strings("previous_cpfs", reassignment.PreviousCPFs()),
+		zap.String("cpf", cpf))
+	return reassignment, nil
+}
+
+// phoneReassignHistory builds the opt-in history entry recorded for one side of a reassignment
+func phoneReassignHistory(storagePhone, cpf, action, reason string, now time.Time) models.OptInHistory {
+	if reason == "" {
+		reason = "phone_reassigned"
+	}
+	return models.OptInHistory{
+		ID:          primitive.NewObjectID(),
+		PhoneNumber: storagePhone,
+		CPF:         cpf,
+		Action:      action,
+		Scope:       models.OptInScopeGlobal,
+		Channel:     phoneReassignChannel,
+		Reason:      &reason,
+		Timestamp:   now,
+	}
+}
+
+// invalidatePhoneMappingCache drops the cached mapping, beta status and detailed status of a phone
+// so the next read sees the new owner
+func (s *PhoneMappingService) invalidatePhoneMappingCache(ctx context.Context, storagePhone string) {
+	op := &PhoneMappingDataOperation{PhoneNumber: storagePhone}
+
+	// One DEL per key, so the keys don't need to share a hash slot in cluster mode
+	pipe := config.Redis.Pipeline()
+	pipe.Del(ctx, fmt.Sprintf("%s:cache:%s", op.GetType(), storagePhone))
+	pipe.Del(ctx, fmt.Sprintf("beta_status:%s", storagePhone))
+	pipe.Del(ctx, phoneStatusDetailsCacheKey(storagePhone))
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("failed to invalidate phone mapping cache", zap.Error(err), zap.String("phone_number", storagePhone))
+	}
+}
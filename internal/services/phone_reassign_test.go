@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPhoneReassignHistory(t *testing.T) {
+	now := time.Now()
+
+	entry := phoneReassignHistory("5521987650001", "12345678909", models.OptInActionOptOut, "", now)
+	if entry.Action != models.OptInActionOptOut || entry.Channel != phoneReassignChannel || entry.Scope != models.OptInScopeGlobal {
+		t.Errorf("phoneReassignHistory() = %+v, want an admin global opt_out", entry)
+	}
+	if entry.Reason == nil || *entry.Reason != "phone_reassigned" {
+		t.Errorf("phoneReassignHistory() reason = %v, want the default reason", entry.Reason)
+	}
+
+	entry = phoneReassignHistory("5521987650001", "12345678909", models.OptInActionReassigned, "número reciclado", now)
+	if entry.Reason == nil || *entry.Reason != "número reciclado" {
+		t.Errorf("phoneReassignHistory() reason = %v, want the given reason", entry.Reason)
+	}
+}
+
+func TestReassignPhone(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	oldCPF, newCPF := "03561350712", "12345678909"
+	now := time.Now()
+
+	_, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).InsertOne(ctx, models.PhoneCPFMapping{
+		PhoneNumber: "5521987650001",
+		CPF:         oldCPF,
+		Status:      models.MappingStatusActive,
+		OptIn:       true,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	})
+	if err != nil {
+		t.Fatalf("failed to insert mapping: %v", err)
+	}
+
+	reassignment, err := service.ReassignPhone(ctx, "+5521987650001", newCPF, "número reciclado")
+	if err != nil {
+		t.Skipf("ReassignPhone() needs MongoDB transactions (replica set): %v", err)
+	}
+	if got := reassignment.PreviousCPFs(); len(got) != 1 || got[0] != oldCPF {
+		t.Errorf("PreviousCPFs() = %v, want [%s]", got, oldCPF)
+	}
+
+	// Only the new owner keeps an active mapping
+	var mappings []models.PhoneCPFMapping
+	cursor, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).Find(ctx, bson.M{"phone_number": "5521987650001"})
+	if err != nil {
+		t.Fatalf("failed to list mappings: %v", err)
+	}
+	if err := cursor.All(ctx, &mappings); err != nil {
+		t.Fatalf("failed to decode mappings: %v", err)
+	}
+	active := 0
+	for _, mapping := range mappings {
+		switch mapping.CPF {
+		case oldCPF:
+			if mapping.Status != models.MappingStatusUnbound || mapping.OptIn {
+				t.Errorf("old mapping = %s (opt_in %v), want unbound without opt-in", mapping.Status, mapping.OptIn)
+			}
+		case newCPF:
+			if mapping.OptIn {
+				t.Error("new mapping should start without opt-in")
+			}
+		}
+		if mapping.Status == models.MappingStatusActive {
+			active++
+		}
+	}
+	if len(mappings) != 2 || active != 1 {
+		t.Errorf("got %d mappings with %d active, want 2 with 1 active", len(mappings), active)
+	}
+
+	// Both sides are recorded in the opt-in history
+	history := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection)
+	if count, _ := history.CountDocuments(ctx, bson.M{"cpf": oldCPF, "action": models.OptInActionOptOut}); count != 1 {
+		t.Errorf("old CPF opt-out history entries = %d, want 1", count)
+	}
+	if count, _ := history.CountDocuments(ctx, bson.M{"cpf": newCPF, "action": models.OptInActionReassigned}); count != 1 {
+		t.Errorf("new CPF reassigned history entries = %d, want 1", count)
+	}
+
+	// Reassigning to the current owner is a conflict
+	if _, err := service.ReassignPhone(ctx, "+5521987650001", newCPF, ""); !errors.Is(err, ErrPhoneAlreadyAssigned) {
+		t.Errorf("ReassignPhone() to current owner error = %v, want ErrPhoneAlreadyAssigned", err)
+	}
+}
+
+func TestReassignPhone_OnlyUnbindsActiveMappings(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	activeCPF, quarantinedCPF, newCPF := "03561350712", "52998224725", "12345678909"
+	now := time.Now()
+
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+	_, err := collection.InsertMany(ctx, []interface{}{
+		models.PhoneCPFMapping{PhoneNumber: "5521987650002", CPF: activeCPF, Status: models.MappingStatusActive, OptIn: true, CreatedAt: &now, UpdatedAt: &now},
+		models.PhoneCPFMapping{PhoneNumber: "5521987650002", CPF: quarantinedCPF, Status: models.MappingStatusQuarantined, CreatedAt: &now, UpdatedAt: &now},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert mappings: %v", err)
+	}
+
+	reassignment, err := service.ReassignPhone(ctx, "+5521987650002", newCPF, "")
+	if err != nil {
+		t.Skipf("ReassignPhone() needs MongoDB transactions (replica set): %v", err)
+	}
+	if got := reassignment.PreviousCPFs(); len(got) != 1 || got[0] != activeCPF {
+		t.Errorf("PreviousCPFs() = %v, want [%s]", got, activeCPF)
+	}
+
+	var quarantined models.PhoneCPFMapping
+	if err := collection.FindOne(ctx, bson.M{"phone_number": "5521987650002", "cpf": quarantinedCPF}).Decode(&quarantined); err != nil {
+		t.Fatalf("failed to get quarantined mapping: %v", err)
+	}
+	if quarantined.Status != models.MappingStatusQuarantined {
+		t.Errorf("quarantined mapping status = %s, want it untouched", quarantined.Status)
+	}
+}
+
+func TestReassignPhone_InvalidInput(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if _, err := service.ReassignPhone(ctx, "invalid", "12345678909", ""); err == nil {
+		t.Error("ReassignPhone() should fail for an invalid phone number")
+	}
+	if _, err := service.ReassignPhone(ctx, "+5521987650001", "11111111111", ""); err == nil {
+		t.Error("ReassignPhone() should fail for an invalid CPF")
+	}
+}
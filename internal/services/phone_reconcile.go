@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// ReconcilePhoneMapping compares the phone mapping stored in MongoDB with the state cached in Redis
+// (DataManager read cache and beta status cache). When repair is true, stale read cache entries are
+// refreshed from MongoDB and stale beta status entries are dropped so they are rebuilt on the next read.
+// The write buffer is only reported, since it holds changes that MongoDB has not received yet.
+func (s *PhoneMappingService) ReconcilePhoneMapping(ctx context.Context, phoneNumber string, repair bool) (*models.PhoneReconcileResponse, error) {
+	components, err := utils.ParsePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+
+	var stored *models.PhoneCPFMapping
+	var mapping models.PhoneCPFMapping
+	err = config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).FindOne(
+		ctx,
+		bson.M{"phone_number": storagePhone},
+	).Decode(&mapping)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to get phone mapping: %w", err)
+	}
+	if err == nil {
+		stored = &mapping
+	}
+
+	op := &PhoneMappingDataOperation{PhoneNumber: storagePhone, Data: stored}
+	writeKey := fmt.Sprintf("%s:write:%s", op.GetType(), storagePhone)
+	cacheKey := fmt.Sprintf("%s:cache:%s", op.GetType(), storagePhone)
+	betaKey := fmt.Sprintf("beta_status:%s", storagePhone)
+
+	response := &models.PhoneReconcileResponse{
+		PhoneNumber:   storagePhone,
+		FoundInMongo:  stored != nil,
+		CachedKeys:    []string{},
+		Discrepancies: []models.PhoneReconcileDiscrepancy{},
+	}
+
+	if exists, err := config.Redis.Exists(ctx, writeKey).Result(); err == nil && exists > 0 {
+		response.PendingWrite = true
+		response.CachedKeys = append(response.CachedKeys, writeKey)
+	}
+
+	staleKeys := make(map[string]bool)
+
+	if data, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil {
+		response.CachedKeys = append(response.CachedKeys, cacheKey)
+		var cached models.PhoneCPFMapping
+		if err := json.Unmarshal([]byte(data), &cached); err != nil {
+			response.Discrepancies = append(response.Discrepancies, models.PhoneReconcileDiscrepancy{
+				Key: cacheKey, Field: "payload", CachedValue: "undecodable",
+			})
+		} else {
+			response.Discrepancies = append(response.Discrepancies, comparePhoneMappingCache(cacheKey, stored, &cached)...)
+		}
+	}
+
+	if data, err := config.Redis.Get(ctx, betaKey).Result(); err == nil {
+		response.CachedKeys = append(response.CachedKeys, betaKey)
+		var cached models.BetaStatusResponse
+		if err := json.Unmarshal([]byte(data), &cached); err != nil {
+			response.Discrepancies = append(response.Discrepancies, models.PhoneReconcileDiscrepancy{
+				Key: betaKey, Field: "payload", CachedValue: "undecodable",
+			})
+		} else {
+			response.Discrepancies = append(response.Discrepancies, compareBetaStatusCache(betaKey, stored, &cached)...)
+		}
+	}
+
+	for _, d := range response.Discrepancies {
+		staleKeys[d.Key] = true
+	}
+	response.Consistent = len(staleKeys) == 0
+
+	if !repair || response.Consistent {
+		return response, nil
+	}
+
+	if staleKeys[cacheKey] {
+		if stored != nil {
+			data, err := json.Marshal(stored)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal phone mapping: %w", err)
+			}
+			if err := config.Redis.Set(ctx, cacheKey, string(data), op.GetTTL()).Err(); err != nil {
+				return nil, fmt.Errorf("failed to refresh phone mapping cache: %w", err)
+			}
+		} else if err := config.Redis.Del(ctx, cacheKey).Err(); err != nil {
+			return nil, fmt.Errorf("failed to delete phone mapping cache: %w", err)
+		}
+		response.RepairedKeys = append(response.RepairedKeys, cacheKey)
+	}
+
+	if staleKeys[betaKey] {
+		if err := config.Redis.Del(ctx, betaKey).Err(); err != nil {
+			return nil, fmt.Errorf("failed to delete beta status cache: %w", err)
+		}
+		response.RepairedKeys = append(response.RepairedKeys, betaKey)
+	}
+
+	response.Repaired = true
+	s.logger.Info("phone mapping cache reconciled",
+		zap.String("phone_number", storagePhone),
+		zap.Int("discrepancies", len(response.Discrepancies)),
+		zap.Strings("repaired_keys", response.RepairedKeys))
+
+	return response, nil
+}
+
+// comparePhoneMappingCache returns the fields of a cached phone mapping that differ from MongoDB.
+// A nil stored mapping means the phone does not exist in MongoDB, so any cached entry is stale.
+func comparePhoneMappingCache(key string, stored, cached *models.PhoneCPFMapping) []models.PhoneReconcileDiscrepancy {
+	if stored == nil {
+		return []models.PhoneReconcileDiscrepancy{{Key: key, Field: "exists", MongoValue: false, CachedValue: true}}
+	}
+
+	var discrepancies []models.PhoneReconcileDiscrepancy
+	add := func(field string, mongoValue, cachedValue interface{}) {
+		discrepancies = append(discrepancies, models.PhoneReconcileDiscrepancy{
+			Key: key, Field: field, MongoValue: mongoValue, CachedValue: cachedValue,
+		})
+	}
+
+	if stored.CPF != cached.CPF {
+		add("cpf", utils.MaskCPF(stored.CPF), utils.MaskCPF(cached.CPF))
+	}
+	if stored.Status != cached.Status {
+		add("status", stored.Status, cached.Status)
+	}
+	if stored.OptIn != cached.OptIn {
+		add("opt_in", stored.OptIn, cached.OptIn)
+	}
+	if stored.BetaGroupID != cached.BetaGroupID {
+		add("beta_group_id", stored.BetaGroupID, cached.BetaGroupID)
+	}
+	if !sameOptionalTime(stored.QuarantineUntil, cached.QuarantineUntil) {
+		add("quarantine_until", stored.QuarantineUntil, cached.QuarantineUntil)
+	}
+
+	return discrepancies
+}
+
+// compareBetaStatusCache returns the fields of a cached beta status that differ from the MongoDB mapping
+func compareBetaStatusCache(key string, stored *models.PhoneCPFMapping, cached *models.BetaStatusResponse) []models.PhoneReconcileDiscrepancy {
+	groupID := ""
+	if stored != nil {
+		groupID = stored.BetaGroupID
+	}
+
+	var discrepancies []models.PhoneReconcileDiscrepancy
+	if whitelisted := groupID != ""; whitelisted != cached.BetaWhitelisted {
+		discrepancies = append(discrepancies, models.PhoneReconcileDiscrepancy{
+			Key: key, Field: "beta_whitelisted", MongoValue: whitelisted, CachedValue: cached.BetaWhitelisted,
+		})
+	}
+	if groupID != cached.GroupID {
+		discrepancies = append(discrepancies, models.PhoneReconcileDiscrepancy{
+			Key: key, Field: "group_id", MongoValue: groupID, CachedValue: cached.GroupID,
+		})
+	}
+	return discrepancies
+}
+
+// sameOptionalTime compares two optional timestamps, ignoring sub-millisecond differences
+// lost when MongoDB stores the value
+func sameOptionalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestComparePhoneMappingCache(t *testing.T) {
+	until := time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	stored := &models.PhoneCPFMapping{
+		PhoneNumber:     "5521999999999",
+		CPF:             "12345678901",
+		Status:          models.MappingStatusActive,
+		OptIn:           true,
+		QuarantineUntil: &until,
+	}
+
+	// Sub-millisecond drift from MongoDB storage is not a discrepancy
+	drifted := until.Add(500 * time.Microsecond)
+	same := *stored
+	same.QuarantineUntil = &drifted
+	if got := comparePhoneMappingCache("k", stored, &same); len(got) != 0 {
+		t.Errorf("identical mapping reported discrepancies: %+v", got)
+	}
+
+	stale := *stored
+	stale.Status = models.MappingStatusBlocked
+	stale.OptIn = false
+	stale.QuarantineUntil = nil
+	got := comparePhoneMappingCache("k", stored, &stale)
+	fields := map[string]bool{}
+	for _, d := range got {
+		fields[d.Field] = true
+	}
+	for _, want := range []string{"status", "opt_in", "quarantine_until"} {
+		if !fields[want] {
+			t.Errorf("expected discrepancy on %s, got %+v", want, got)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("expected 3 discrepancies, got %d", len(got))
+	}
+
+	got = comparePhoneMappingCache("k", nil, &stale)
+	if len(got) != 1 || got[0].Field != "exists" {
+		t.Errorf("cached mapping missing from MongoDB = %+v, want single exists discrepancy", got)
+	}
+}
+
+func TestCompareBetaStatusCache(t *testing.T) {
+	stored := &models.PhoneCPFMapping{PhoneNumber: "5521999999999", BetaGroupID: "group-1"}
+
+	if got := compareBetaStatusCache("k", stored, &models.BetaStatusResponse{BetaWhitelisted: true, GroupID: "group-1"}); len(got) != 0 {
+		t.Errorf("matching beta status reported discrepancies: %+v", got)
+	}
+
+	got := compareBetaStatusCache("k", stored, &models.BetaStatusResponse{BetaWhitelisted: false})
+	if len(got) != 2 {
+		t.Errorf("expected beta_whitelisted and group_id discrepancies, got %+v", got)
+	}
+
+	if got := compareBetaStatusCache("k", nil, &models.BetaStatusResponse{BetaWhitelisted: false}); len(got) != 0 {
+		t.Errorf("negative cache for missing mapping reported discrepancies: %+v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// phoneStatusDetailsCacheKey is the Redis key of the cached detailed status of a phone
+func phoneStatusDetailsCacheKey(storagePhone string) string {
+	return fmt.Sprintf("phone_status_details:%s", storagePhone)
+}
+
+// GetPhoneStatusDetails returns the phone status enriched with the quarantine reason, the number of
+// bound CPFs and the last opt-in action, so service callers can route a conversation with a single
+// call. The result is cached for PHONE_STATUS_DETAILS_CACHE_TTL and dropped when the phone is
+// quarantined, released, bound, reassigned, changes beta group or records an opt-in action.
+func (s *PhoneMappingService) GetPhoneStatusDetails(ctx context.Context, phoneNumber string) (*models.PhoneStatusDetailsResponse, error) {
+	components, err := utils.ParsePhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	storagePhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+	cacheKey := phoneStatusDetailsCacheKey(storagePhone)
+
+	if cached, err := config.Redis.Get(ctx, cacheKey).Result(); err == nil {
+		var response models.PhoneStatusDetailsResponse
+		if err := json.Unmarshal([]byte(cached), &response); err == nil {
+			// The cache is shared by every format of the number
+			response.PhoneNumber = phoneNumber
+			return &response, nil
+		}
+	}
+
+	status, mapping, err := s.getPhoneStatus(ctx, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	response := &models.PhoneStatusDetailsResponse{PhoneStatusResponse: *status}
+
+	if mapping != nil {
+		if status.Quarantined {
+			response.QuarantineReason = mapping.QuarantineReason
+		}
+
+		count, err := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection).CountDocuments(ctx, bson.M{
+			"phone_number": storagePhone,
+			"status":       models.MappingStatusActive,
+			"cpf":          bson.M{"$nin": bson.A{nil, ""}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count bound CPFs: %w", err)
+		}
+		response.BoundCPFCount = int(count)
+	}
+
+	var history models.OptInHistory
+	err = config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection).FindOne(ctx,
+		bson.M{"phone_number": storagePhone},
+		options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}}),
+	).Decode(&history)
+	switch {
+	case err == nil:
+		response.LastOptInAction = &models.PhoneStatusOptInAction{
+			Action:    history.Action,
+			Channel:   history.Channel,
+			Timestamp: history.Timestamp,
+		}
+	case err != mongo.ErrNoDocuments:
+		return nil, fmt.Errorf("failed to get last opt-in action: %w", err)
+	}
+
+	if cacheJSON, err := json.Marshal(response); err == nil {
+		if err := config.Redis.Set(ctx, cacheKey, string(cacheJSON), config.AppConfig.PhoneStatusDetailsCacheTTL).Err(); err != nil {
+			s.logger.Warn("failed to cache phone status details", zap.Error(err), zap.String("phone_number", storagePhone))
+		}
+	}
+
+	return response, nil
+}
+
+// invalidatePhoneStatusDetails drops the cached detailed status of the given phones (storage format)
+func (s *PhoneMappingService) invalidatePhoneStatusDetails(ctx context.Context, storagePhones ...string) {
+	if len(storagePhones) == 0 {
+		return
+	}
+
+	// One DEL per key, so the keys don't need to share a hash slot in cluster mode
+	pipe := config.Redis.Pipeline()
+	for _, storagePhone := range storagePhones {
+		pipe.Del(ctx, phoneStatusDetailsCacheKey(storagePhone))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("failed to invalidate phone status details", zap.Error(err), zap.Int("count", len(storagePhones)))
+	}
+}
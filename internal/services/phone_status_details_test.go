@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestGetPhoneStatusDetails(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+	if config.Redis == nil {
+		t.Skip("Skipping phone status details test: Redis not available")
+	}
+
+	ctx := context.Background()
+	storagePhone := "5521987650002"
+	defer config.Redis.Del(ctx, phoneStatusDetailsCacheKey(storagePhone))
+	now := time.Now()
+
+	mappings := config.MongoDB.Collection(config.AppConfig.PhoneMappingCollection)
+	for _, mapping := range []models.PhoneCPFMapping{
+		{PhoneNumber: storagePhone, CPF: "03561350712", Status: models.MappingStatusActive, OptIn: true, CreatedAt: &now},
+		{PhoneNumber: storagePhone, CPF: "12345678909", Status: models.MappingStatusActive, CreatedAt: &now},
+		{PhoneNumber: storagePhone, CPF: "11144477735", Status: models.MappingStatusUnbound, CreatedAt: &now},
+	} {
+		if _, err := mappings.InsertOne(ctx, mapping); err != nil {
+			t.Fatalf("failed to insert mapping: %v", err)
+		}
+	}
+	history := config.MongoDB.Collection(config.AppConfig.OptInHistoryCollection)
+	for _, entry := range []models.OptInHistory{
+		{PhoneNumber: storagePhone, CPF: "03561350712", Action: models.OptInActionOptOut, Channel: "whatsapp", Timestamp: now.Add(-time.Hour)},
+		{PhoneNumber: storagePhone, CPF: "03561350712", Action: models.OptInActionOptIn, Channel: "whatsapp", Timestamp: now.Add(-time.Minute)},
+	} {
+		if _, err := history.InsertOne(ctx, entry); err != nil {
+			t.Fatalf("failed to insert history: %v", err)
+		}
+	}
+
+	details, err := service.GetPhoneStatusDetails(ctx, "+5521987650002")
+	if err != nil {
+		t.Fatalf("GetPhoneStatusDetails() error = %v", err)
+	}
+	if !details.Found || details.Quarantined || details.BoundCPFCount != 2 {
+		t.Errorf("GetPhoneStatusDetails() = %+v, want found, not quarantined, 2 bound CPFs", details)
+	}
+	if details.LastOptInAction == nil || details.LastOptInAction.Action != models.OptInActionOptIn {
+		t.Errorf("LastOptInAction = %+v, want the latest opt_in", details.LastOptInAction)
+	}
+
+	// Quarantining the phone drops the cached details
+	if _, err := service.QuarantinePhone(ctx, "+5521987650002"); err != nil {
+		t.Fatalf("QuarantinePhone() error = %v", err)
+	}
+	details, err = service.GetPhoneStatusDetails(ctx, "+5521987650002")
+	if err != nil {
+		t.Fatalf("GetPhoneStatusDetails() error = %v", err)
+	}
+	if !details.Quarantined || details.QuarantineUntil == nil {
+		t.Errorf("GetPhoneStatusDetails() after quarantine = %+v, want quarantined", details)
+	}
+}
+
+func TestGetPhoneStatusDetails_NotFound(t *testing.T) {
+	service, cleanup := setupPhoneMappingTest(t)
+	defer cleanup()
+	if config.Redis == nil {
+		t.Skip("Skipping phone status details test: Redis not available")
+	}
+
+	ctx := context.Background()
+	defer config.Redis.Del(ctx, phoneStatusDetailsCacheKey("5521987650003"))
+
+	details, err := service.GetPhoneStatusDetails(ctx, "+5521987650003")
+	if err != nil {
+		t.Fatalf("GetPhoneStatusDetails() error = %v", err)
+	}
+	if details.Found || details.BoundCPFCount != 0 || details.LastOptInAction != nil {
+		t.Errorf("GetPhoneStatusDetails() = %+v, want an unknown phone", details)
+	}
+
+	if _, err := service.GetPhoneStatusDetails(ctx, "invalid"); err == nil {
+		t.Error("GetPhoneStatusDetails() should fail for an invalid phone number")
+	}
+}
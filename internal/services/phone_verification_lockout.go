@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// phoneVerificationFailuresKey counts failed code validations of a CPF within the lockout window
+func phoneVerificationFailuresKey(cpf string) string {
+	return fmt.Sprintf("phone_verification_failures:%s", cpf)
+}
+
+// phoneVerificationFailureScript increments the failure counter, starting the window on the first
+// failure, and returns the new count and the milliseconds left in the window
+const phoneVerificationFailureScript = `
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}`
+
+// PhoneVerificationLockedFor returns how long the CPF is still locked out of phone verification,
+// or 0 when it may try again. Lockout is disabled when PHONE_VERIFICATION_MAX_FAILURES is 0.
+func PhoneVerificationLockedFor(ctx context.Context, cpf string) (time.Duration, error) {
+	if config.AppConfig.PhoneVerificationMaxFailures <= 0 {
+		return 0, nil
+	}
+
+	key := phoneVerificationFailuresKey(cpf)
+	value, err := config.Redis.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get phone verification failures: %w", err)
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid phone verification failure count %q: %w", value, err)
+	}
+	if count < config.AppConfig.PhoneVerificationMaxFailures {
+		return 0, nil
+	}
+
+	ttl, err := config.Redis.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get phone verification lockout TTL: %w", err)
+	}
+	if ttl <= 0 {
+		// Key expired between the reads, or has no expiry; fall back to a full window
+		ttl = config.AppConfig.PhoneVerificationLockoutWindow
+	}
+	return ttl, nil
+}
+
+// RecordPhoneVerificationFailure counts a failed code validation for the CPF and reports whether
+// it has now reached PHONE_VERIFICATION_MAX_FAILURES within the lockout window
+func RecordPhoneVerificationFailure(ctx context.Context, cpf string) (bool, error) {
+	if config.AppConfig.PhoneVerificationMaxFailures <= 0 {
+		return false, nil
+	}
+
+	window := config.AppConfig.PhoneVerificationLockoutWindow
+	result, err := config.Redis.Eval(ctx, phoneVerificationFailureScript,
+		[]string{phoneVerificationFailuresKey(cpf)}, window.Milliseconds()).Slice()
+	if err != nil {
+		return false, fmt.Errorf("failed to record phone verification failure: %w", err)
+	}
+	if len(result) == 0 {
+		return false, fmt.Errorf("unexpected phone verification failure script result: %v", result)
+	}
+
+	count, ok := result[0].(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected phone verification failure count: %v", result[0])
+	}
+	return count >= int64(config.AppConfig.PhoneVerificationMaxFailures), nil
+}
+
+// ResetPhoneVerificationFailures clears the failure counter after a successful validation
+func ResetPhoneVerificationFailures(ctx context.Context, cpf string) error {
+	if err := config.Redis.Del(ctx, phoneVerificationFailuresKey(cpf)).Err(); err != nil {
+		return fmt.Errorf("failed to reset phone verification failures: %w", err)
+	}
+	return nil
+}
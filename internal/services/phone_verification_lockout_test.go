@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+func setupPhoneVerificationLockoutTest(t *testing.T, maxFailures int, window time.Duration) (string, func()) {
+	setupTestEnvironment()
+	if config.Redis == nil {
+		t.Skip("Skipping phone verification lockout tests: Redis not available")
+	}
+
+	origMax, origWindow := config.AppConfig.PhoneVerificationMaxFailures, config.AppConfig.PhoneVerificationLockoutWindow
+	config.AppConfig.PhoneVerificationMaxFailures = maxFailures
+	config.AppConfig.PhoneVerificationLockoutWindow = window
+
+	cpf := "lockout-" + t.Name()
+	config.Redis.Del(context.Background(), phoneVerificationFailuresKey(cpf))
+
+	return cpf, func() {
+		config.Redis.Del(context.Background(), phoneVerificationFailuresKey(cpf))
+		config.AppConfig.PhoneVerificationMaxFailures = origMax
+		config.AppConfig.PhoneVerificationLockoutWindow = origWindow
+	}
+}
+
+func TestPhoneVerificationLockout_BlocksAfterMaxFailures(t *testing.T) {
+	cpf, cleanup := setupPhoneVerificationLockoutTest(t, 3, time.Minute)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		if lockedFor, err := PhoneVerificationLockedFor(ctx, cpf); err != nil || lockedFor != 0 {
+			t.Fatalf("attempt %d: PhoneVerificationLockedFor() = (%v, %v), want not locked", i, lockedFor, err)
+		}
+		locked, err := RecordPhoneVerificationFailure(ctx, cpf)
+		if err != nil {
+			t.Fatalf("RecordPhoneVerificationFailure() error = %v", err)
+		}
+		if locked != (i == 3) {
+			t.Errorf("failure %d: locked = %v, want %v", i, locked, i == 3)
+		}
+	}
+
+	lockedFor, err := PhoneVerificationLockedFor(ctx, cpf)
+	if err != nil {
+		t.Fatalf("PhoneVerificationLockedFor() error = %v", err)
+	}
+	if lockedFor <= 0 || lockedFor > time.Minute {
+		t.Errorf("lockedFor = %v, want within the 1m window", lockedFor)
+	}
+}
+
+func TestPhoneVerificationLockout_ExpiresWithWindow(t *testing.T) {
+	cpf, cleanup := setupPhoneVerificationLockoutTest(t, 1, 200*time.Millisecond)
+	defer cleanup()
+	ctx := context.Background()
+
+	if locked, err := RecordPhoneVerificationFailure(ctx, cpf); err != nil || !locked {
+		t.Fatalf("RecordPhoneVerificationFailure() = (%v, %v), want locked", locked, err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if lockedFor, err := PhoneVerificationLockedFor(ctx, cpf); err != nil || lockedFor != 0 {
+		t.Errorf("PhoneVerificationLockedFor() after window = (%v, %v), want not locked", lockedFor, err)
+	}
+}
+
+func TestPhoneVerificationLockout_ResetOnSuccess(t *testing.T) {
+	cpf, cleanup := setupPhoneVerificationLockoutTest(t, 2, time.Minute)
+	defer cleanup()
+	ctx := context.Background()
+
+	RecordPhoneVerificationFailure(ctx, cpf)
+	if err := ResetPhoneVerificationFailures(ctx, cpf); err != nil {
+		t.Fatalf("ResetPhoneVerificationFailures() error = %v", err)
+	}
+
+	// The counter starts over, so one more failure doesn't reach the threshold
+	if locked, err := RecordPhoneVerificationFailure(ctx, cpf); err != nil || locked {
+		t.Errorf("RecordPhoneVerificationFailure() after reset = (%v, %v), want not locked", locked, err)
+	}
+}
+
+func TestPhoneVerificationLockout_Disabled(t *testing.T) {
+	cpf, cleanup := setupPhoneVerificationLockoutTest(t, 0, time.Minute)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		if locked, err := RecordPhoneVerificationFailure(ctx, cpf); err != nil || locked {
+			t.Fatalf("RecordPhoneVerificationFailure() with lockout disabled = (%v, %v)", locked, err)
+		}
+	}
+	if lockedFor, err := PhoneVerificationLockedFor(ctx, cpf); err != nil || lockedFor != 0 {
+		t.Errorf("PhoneVerificationLockedFor() with lockout disabled = (%v, %v)", lockedFor, err)
+	}
+}
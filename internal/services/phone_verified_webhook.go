@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+// PhoneVerifiedWebhookJobType is the sync job type (and queue) used to deliver phone verified webhooks
+const PhoneVerifiedWebhookJobType = "phone_verified_webhook"
+
+// QueuePhoneVerifiedWebhook queues a phone verified notification for delivery by the sync workers
+func QueuePhoneVerifiedWebhook(ctx context.Context, cpf, phone, channel string) error {
+	payload := models.PhoneVerifiedWebhookPayload{
+		Event:            models.PhoneVerifiedWebhookEvent,
+		CPF:              cpf,
+		Phone:            phone,
+		Channel:          channel,
+		PreferredChannel: GetPreferredChannel(ctx, cpf),
+		VerifiedAt:       time.Now(),
+	}
+	return queueWebhookJob(ctx, PhoneVerifiedWebhookJobType, cpf, payload, config.AppConfig.PhoneVerifiedWebhookMaxRetries)
+}
+
+// DeliverPhoneVerifiedWebhook posts a signed phone verified notification to the configured URL.
+// Any non-2xx response is returned as an error so the job is retried.
+func DeliverPhoneVerifiedWebhook(ctx context.Context, payload models.PhoneVerifiedWebhookPayload) error {
+	return deliverWebhook(ctx, PhoneVerifiedWebhookJobType, config.AppConfig.PhoneVerifiedWebhookURL, config.AppConfig.PhoneVerifiedWebhookSecret, config.AppConfig.PhoneVerifiedWebhookTimeout, payload)
+}
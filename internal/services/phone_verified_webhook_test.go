@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverPhoneVerifiedWebhook(t *testing.T) {
+	payload := models.PhoneVerifiedWebhookPayload{
+		Event:      models.PhoneVerifiedWebhookEvent,
+		CPF:        "12345678901",
+		Phone:      "5521987654321",
+		Channel:    models.ChannelWhatsApp,
+		VerifiedAt: time.Now(),
+	}
+
+	var received models.PhoneVerifiedWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		assert.Equal(t, SignWebhookPayload("phone-secret", timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		require.NoError(t, json.Unmarshal(body, &received))
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	original := config.AppConfig
+	config.AppConfig = &config.Config{
+		PhoneVerifiedWebhookURL:     server.URL,
+		PhoneVerifiedWebhookSecret:  "phone-secret",
+		PhoneVerifiedWebhookTimeout: time.Second,
+	}
+	defer func() { config.AppConfig = original }()
+
+	require.NoError(t, DeliverPhoneVerifiedWebhook(context.Background(), payload))
+	assert.Equal(t, models.PhoneVerifiedWebhookEvent, received.Event)
+	assert.Equal(t, payload.CPF, received.CPF)
+	assert.Equal(t, payload.Phone, received.Phone)
+	assert.Equal(t, models.ChannelWhatsApp, received.Channel)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"go.uber.org/zap"
+)
+
+// GetPreferredChannel returns the channel the citizen prefers to be notified through, or an empty
+// string when none was chosen. A preference for a channel that is no longer available is ignored,
+// and so is a user config that can't be read, so notifications fall back to their default channel.
+func GetPreferredChannel(ctx context.Context, cpf string) string {
+	logger := observability.Logger()
+	dataManager := NewDataManager(config.Redis, config.MongoDB, logger)
+
+	var userConfig models.UserConfig
+	if err := dataManager.Read(ctx, cpf, config.AppConfig.UserConfigCollection, "user_config", &userConfig); err != nil {
+		if err != ErrDocumentNotFound {
+			logger.Warn("failed to read preferred channel", zap.Error(err), zap.String("cpf", cpf))
+		}
+		return ""
+	}
+
+	if userConfig.PreferredChannel == nil || !IsAvailableChannel(*userConfig.PreferredChannel) {
+		return ""
+	}
+	return *userConfig.PreferredChannel
+}
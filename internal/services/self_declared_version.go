@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// selfDeclaredVersionTTL keeps the version counter well past the write buffer TTL, so it is
+// only reseeded from MongoDB once every buffered write has been synced
+const selfDeclaredVersionTTL = 24 * time.Hour
+
+// nextSelfDeclaredVersionScript atomically compares the current version with the expected one
+// (-1 skips the comparison) and increments it. Returns {1, new_version} or {0, current_version}.
+const nextSelfDeclaredVersionScript = `
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local expected = tonumber(ARGV[1])
+if expected >= 0 and current ~= expected then
+	return {0, current}
+end
+local next = current + 1
+redis.call('SET', KEYS[1], next, 'PX', ARGV[2])
+return {1, next}
+`
+
+// rollbackSelfDeclaredVersionScript undoes an increment (ARGV[1] is the version it returned)
+// unless another write has taken a newer version since. Returns 1 when rolled back.
+const rollbackSelfDeclaredVersionScript = `
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local version = tonumber(ARGV[1])
+if current ~= version then
+	return 0
+end
+redis.call('SET', KEYS[1], version - 1, 'PX', ARGV[2])
+return 1
+`
+
+func selfDeclaredVersionKey(cpf string) string {
+	return fmt.Sprintf("self_declared:version:%s", cpf)
+}
+
+// newSelfDeclaredVersionConflict builds the error returned when a conditional update is stale
+func newSelfDeclaredVersionConflict(expected, current int32) error {
+	return utils.OptimisticLockError{
+		Resource: "self_declared",
+		Message:  fmt.Sprintf("expected version %d, but current version is %d", expected, current),
+	}
+}
+
+// GetSelfDeclaredVersion returns the current version of a citizen's self-declared data. The
+// version of the latest buffered write is kept in Redis; when it is not there, the version
+// stored in MongoDB is used (0 for citizens without self-declared data) and seeded into Redis.
+func GetSelfDeclaredVersion(ctx context.Context, cpf string) (int32, error) {
+	key := selfDeclaredVersionKey(cpf)
+	if value, err := config.Redis.Get(ctx, key).Result(); err == nil {
+		version, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid self-declared version %q: %w", value, err)
+		}
+		return int32(version), nil
+	} else if err != redis.Nil {
+		return 0, fmt.Errorf("failed to read self-declared version: %w", err)
+	}
+
+	var stored struct {
+		Version int32 `bson:"version"`
+	}
+	err := utils.GetCollectionForContextRead(ctx, config.AppConfig.SelfDeclaredCollection).FindOne(ctx,
+		bson.M{"cpf": cpf},
+		options.FindOne().SetProjection(bson.M{"version": 1}),
+	).Decode(&stored)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, fmt.Errorf("failed to read self-declared version from MongoDB: %w", err)
+	}
+
+	// Another request may have seeded or incremented the counter meanwhile; keep its value
+	seeded, err := config.Redis.SetNX(ctx, key, stored.Version, selfDeclaredVersionTTL).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed self-declared version: %w", err)
+	}
+	if !seeded {
+		return GetSelfDeclaredVersion(ctx, cpf)
+	}
+	return stored.Version, nil
+}
+
+// CheckSelfDeclaredVersion returns an utils.OptimisticLockError when expectedVersion is set and
+// differs from the current version. It does not change the version.
+func CheckSelfDeclaredVersion(ctx context.Context, cpf string, expectedVersion *int32) (int32, error) {
+	current, err := GetSelfDeclaredVersion(ctx, cpf)
+	if err != nil {
+		return 0, err
+	}
+	if expectedVersion != nil && *expectedVersion != current {
+		return current, newSelfDeclaredVersionConflict(*expectedVersion, current)
+	}
+	return current, nil
+}
+
+// nextSelfDeclaredVersion compares the current version with expectedVersion (when set) and
+// increments it in a single atomic step, so of two concurrent writes based on the same version
+// only one succeeds.
+func nextSelfDeclaredVersion(ctx context.Context, cpf string, expectedVersion *int32) (int32, error) {
+	// Make sure the counter starts from the version persisted in MongoDB
+	if _, err := GetSelfDeclaredVersion(ctx, cpf); err != nil {
+		return 0, err
+	}
+
+	expected := int64(-1)
+	if expectedVersion != nil {
+		expected = int64(*expectedVersion)
+	}
+
+	result, err := config.Redis.Eval(ctx, nextSelfDeclaredVersionScript,
+		[]string{selfDeclaredVersionKey(cpf)},
+		expected, selfDeclaredVersionTTL.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment self-declared version: %w", err)
+	}
+	if len(result) != 2 {
+		return 0, fmt.Errorf("unexpected self-declared version result: %v", result)
+	}
+
+	ok, _ := result[0].(int64)
+	version, _ := result[1].(int64)
+	if ok != 1 {
+		return int32(version), newSelfDeclaredVersionConflict(int32(expected), int32(version))
+	}
+	return int32(version), nil
+}
+
+// rollbackSelfDeclaredVersion returns the version counter to where it was before
+// nextSelfDeclaredVersion handed out version, when the write of that version failed. Without it
+// the counter stays ahead of the stored document and the client's next If-Match is rejected.
+// Once a newer version was handed out the counter is left alone; the failed version is then a
+// gap, which If-Match comparisons don't mind.
+func rollbackSelfDeclaredVersion(ctx context.Context, cpf string, version int32) error {
+	err := config.Redis.Eval(ctx, rollbackSelfDeclaredVersionScript,
+		[]string{selfDeclaredVersionKey(cpf)},
+		int64(version), selfDeclaredVersionTTL.Milliseconds(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to roll back self-declared version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const selfDeclaredVersionTestCPF = "03561350712"
+
+func TestGetSelfDeclaredVersion_SeedsFromMongoDB(t *testing.T) {
+	_, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection)
+	_, err := collection.InsertOne(ctx, bson.M{"cpf": selfDeclaredVersionTestCPF, "version": int32(7)})
+	require.NoError(t, err)
+	defer collection.DeleteOne(ctx, bson.M{"cpf": selfDeclaredVersionTestCPF})
+
+	version, err := GetSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), version)
+
+	// The seeded counter is used from now on
+	stored, err := config.Redis.Get(ctx, selfDeclaredVersionKey(selfDeclaredVersionTestCPF)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, "7", stored)
+}
+
+func TestGetSelfDeclaredVersion_NoData(t *testing.T) {
+	_, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	version, err := GetSelfDeclaredVersion(context.Background(), "99999999999")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), version)
+}
+
+func TestUpdateSelfDeclared_VersionIncrements(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	first, err := service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Branca", nil)
+	require.NoError(t, err)
+
+	second, err := service.UpdateSelfDeclaredGenero(ctx, selfDeclaredVersionTestCPF, "Masculino", &first)
+	require.NoError(t, err)
+	assert.Equal(t, first+1, second)
+
+	current, err := CheckSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, &second)
+	require.NoError(t, err)
+	assert.Equal(t, second, current)
+}
+
+func TestUpdateSelfDeclared_VersionConflict(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	version, err := service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Branca", nil)
+	require.NoError(t, err)
+
+	stale := version - 1
+	_, err = service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Parda", &stale)
+	var lockErr utils.OptimisticLockError
+	assert.True(t, errors.As(err, &lockErr), "expected optimistic lock error, got %v", err)
+
+	_, err = CheckSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, &stale)
+	assert.True(t, errors.As(err, &lockErr))
+
+	// The rejected write must not reach the write buffer
+	buffered, err := config.Redis.Get(ctx, "self_declared_raca:write:"+selfDeclaredVersionTestCPF).Result()
+	require.NoError(t, err)
+	assert.Contains(t, buffered, "Branca")
+}
+
+func TestUpdateSelfDeclared_ConcurrentConditionalWrites(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	base, err := service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Branca", nil)
+	require.NoError(t, err)
+
+	// Several devices update the same citizen based on the same version: only one may win
+	const writers = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, conflicted := 0, 0
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expected := base
+			_, err := service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Parda", &expected)
+
+			mu.Lock()
+			defer mu.Unlock()
+			var lockErr utils.OptimisticLockError
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.As(err, &lockErr):
+				conflicted++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, writers-1, conflicted)
+
+	current, err := GetSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF)
+	require.NoError(t, err)
+	assert.Equal(t, base+1, current)
+}
+
+func TestUpdateSelfDeclared_FailedWriteKeepsVersion(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	version, err := service.UpdateSelfDeclaredRaca(ctx, selfDeclaredVersionTestCPF, "Branca", nil)
+	require.NoError(t, err)
+
+	// A critical write without data fails after the version was handed out
+	_, err = service.UpdateSelfDeclaredAddress(WithWriteOperationType(ctx, WriteOperationCritical), selfDeclaredVersionTestCPF, nil, &version)
+	require.Error(t, err)
+
+	// The client's next If-Match with the version it holds still succeeds
+	current, err := CheckSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, &version)
+	require.NoError(t, err)
+	assert.Equal(t, version, current)
+}
+
+func TestRollbackSelfDeclaredVersion_KeepsNewerVersion(t *testing.T) {
+	_, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	failed, err := nextSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, nil)
+	require.NoError(t, err)
+	newer, err := nextSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, rollbackSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF, failed))
+	current, err := GetSelfDeclaredVersion(ctx, selfDeclaredVersionTestCPF)
+	require.NoError(t, err)
+	assert.Equal(t, newer, current, "a version handed out after the failed one is kept")
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Operation types understood by utils.GetWriteConcernForOperation for self-declared writes
+const (
+	WriteOperationUserData = "user_data" // buffered write, synced by the sync worker with W:1
+	WriteOperationCritical = "critical"  // synchronous write acknowledged by a majority
+)
+
+type writeOperationTypeKey struct{}
+
+// WithWriteOperationType returns a context asking CacheService self-declared updates to use the
+// write concern of operationType. Only WriteOperationCritical changes how the write is made; a
+// hint never downgrades a field configured in CRITICAL_SELF_DECLARED_FIELDS.
+func WithWriteOperationType(ctx context.Context, operationType string) context.Context {
+	return context.WithValue(ctx, writeOperationTypeKey{}, operationType)
+}
+
+// selfDeclaredOperationType resolves the operation type of a self-declared write of dataType
+func selfDeclaredOperationType(ctx context.Context, dataType string) string {
+	if slices.Contains(config.AppConfig.CriticalSelfDeclaredFields, getFieldNameFromJobType(dataType)) {
+		return WriteOperationCritical
+	}
+	if operationType, ok := ctx.Value(writeOperationTypeKey{}).(string); ok && operationType != "" {
+		return operationType
+	}
+	return WriteOperationUserData
+}
+
+// writeSelfDeclared writes a self-declared operation of the given version through the write
+// buffer, or straight to MongoDB when the write is critical. When the write fails the version
+// counter is rolled back, so the version stays the one of the stored data.
+func (s *CacheService) writeSelfDeclared(ctx context.Context, op DataOperation, version int32) error {
+	err := s.writeSelfDeclaredOperation(ctx, op)
+	if err != nil {
+		if rollbackErr := rollbackSelfDeclaredVersion(ctx, op.GetKey(), version); rollbackErr != nil {
+			s.logger.Warn("failed to roll back self-declared version after a failed write",
+				zap.String("type", op.GetType()),
+				zap.String("cpf", op.GetKey()),
+				zap.Error(rollbackErr))
+		}
+	}
+	return err
+}
+
+func (s *CacheService) writeSelfDeclaredOperation(ctx context.Context, op DataOperation) error {
+	dataManager := NewDataManager(config.Redis, config.MongoDB, s.logger)
+
+	operationType := selfDeclaredOperationType(ctx, op.GetType())
+	if operationType != WriteOperationCritical {
+		return dataManager.Write(ctx, op)
+	}
+
+	// Decode the data the same way the sync worker does, so both paths store identical documents
+	dataBytes, err := json.Marshal(op.GetData())
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+	var data bson.M
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal to BSON: %w", err)
+	}
+
+	update := selfDeclaredFieldUpdate(op.GetType(), data)
+	if update == nil {
+		return fmt.Errorf("no self-declared field for type %s", op.GetType())
+	}
+
+	collection := config.MongoDB.Collection(op.GetCollection(),
+		options.Collection().SetWriteConcern(utils.GetWriteConcernForOperation(operationType)))
+	if _, err := collection.UpdateOne(ctx, bson.M{"cpf": op.GetKey()}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to write critical self-declared update: %w", err)
+	}
+
+	// The write is durable: drop any older buffered value and refresh the caches, as the sync
+	// worker does after a successful sync. Older jobs still queued for the field are dropped by
+	// the sync worker, as the update recorded the field's version.
+	_ = dataManager.CleanupWriteBuffer(ctx, op.GetType(), op.GetKey())
+	if err := dataManager.UpdateReadCache(ctx, op.GetType(), op.GetKey(), op.GetData()); err != nil {
+		s.logger.Warn("failed to update read cache after critical write",
+			zap.String("type", op.GetType()),
+			zap.String("cpf", op.GetKey()),
+			zap.Error(err))
+	}
+	if err := config.Redis.Del(ctx, fmt.Sprintf("citizen:%s", op.GetKey())).Err(); err != nil {
+		s.logger.Warn("failed to invalidate citizen cache after critical write",
+			zap.String("cpf", op.GetKey()),
+			zap.Error(err))
+	}
+	EventBusInstance.PublishSelfDeclaredSynced(ctx, op.GetKey(), getFieldNameFromJobType(op.GetType()))
+
+	s.logger.Debug("critical self-declared update written to MongoDB",
+		zap.String("type", op.GetType()),
+		zap.String("cpf", op.GetKey()))
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSelfDeclaredOperationType(t *testing.T) {
+	setupTestEnvironment()
+	previous := config.AppConfig.CriticalSelfDeclaredFields
+	defer func() { config.AppConfig.CriticalSelfDeclaredFields = previous }()
+	config.AppConfig.CriticalSelfDeclaredFields = []string{"nome_exibicao"}
+
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		dataType string
+		want     string
+	}{
+		{"default", ctx, "self_declared_raca", WriteOperationUserData},
+		{"configured field", ctx, "self_declared_nome_exibicao", WriteOperationCritical},
+		{"critical hint", WithWriteOperationType(ctx, WriteOperationCritical), "self_declared_raca", WriteOperationCritical},
+		{"hint does not downgrade", WithWriteOperationType(ctx, WriteOperationUserData), "self_declared_nome_exibicao", WriteOperationCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selfDeclaredOperationType(tt.ctx, tt.dataType); got != tt.want {
+				t.Errorf("selfDeclaredOperationType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSelfDeclared_CriticalWriteIsSynchronous(t *testing.T) {
+	service, cleanup := setupCacheServiceTest(t)
+	defer cleanup()
+
+	ctx := WithWriteOperationType(context.Background(), WriteOperationCritical)
+	cpf := "03561350712"
+	collection := config.MongoDB.Collection("self_declared")
+	defer collection.DeleteOne(context.Background(), bson.M{"cpf": cpf})
+
+	version, err := service.UpdateSelfDeclaredNomeExibicao(ctx, cpf, "Maria", nil)
+	if err != nil {
+		t.Fatalf("UpdateSelfDeclaredNomeExibicao() error = %v", err)
+	}
+
+	var stored models.SelfDeclaredData
+	if err := collection.FindOne(ctx, bson.M{"cpf": cpf}).Decode(&stored); err != nil {
+		t.Fatalf("critical write not found in MongoDB: %v", err)
+	}
+	if stored.NomeExibicao == nil || *stored.NomeExibicao != "Maria" || stored.Version != version {
+		t.Errorf("stored nome_exibicao = %v, version = %d, want Maria at version %d", stored.NomeExibicao, stored.Version, version)
+	}
+
+	if depth, _ := service.GetQueueDepth(ctx, "self_declared_nome_exibicao"); depth != 0 {
+		t.Errorf("critical write queued %d sync jobs, want none", depth)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"go.uber.org/zap"
+)
+
+// staticListRefreshTimeout bounds each background refresh of a static list
+const staticListRefreshTimeout = 30 * time.Second
+
+// StaticListCache keeps a static or semi-static list (ethnicity options, channels, opt-out
+// reasons...) in memory. Refreshes build a new snapshot and swap it atomically, so reads never
+// wait on a refresh. Values are shared between callers and must be treated as read-only.
+type StaticListCache[T any] struct {
+	name      string
+	load      func(ctx context.Context) (T, error)
+	snapshot  atomic.Pointer[staticListSnapshot[T]]
+	refreshMu sync.Mutex
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	logger    *logging.SafeLogger
+}
+
+type staticListSnapshot[T any] struct {
+	value       T
+	refreshedAt time.Time
+}
+
+// NewStaticListCache creates a cache for the list returned by load. Nothing is loaded until
+// the first Get or Refresh.
+func NewStaticListCache[T any](name string, load func(ctx context.Context) (T, error), logger *logging.SafeLogger) *StaticListCache[T] {
+	return &StaticListCache[T]{
+		name:   name,
+		load:   load,
+		stop:   make(chan struct{}),
+		logger: logger,
+	}
+}
+
+// Get returns the cached list and when it was loaded, loading it on first use
+func (c *StaticListCache[T]) Get(ctx context.Context) (T, time.Time, error) {
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		return snapshot.value, snapshot.refreshedAt, nil
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		var zero T
+		return zero, time.Time{}, err
+	}
+	snapshot := c.snapshot.Load()
+	return snapshot.value, snapshot.refreshedAt, nil
+}
+
+// Refresh reloads the list and swaps it in. On failure the previous snapshot keeps being served.
+func (c *StaticListCache[T]) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	value, err := c.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", c.name, err)
+	}
+	c.snapshot.Store(&staticListSnapshot[T]{value: value, refreshedAt: time.Now()})
+	return nil
+}
+
+// LastRefresh returns when the list was last loaded (zero if it never was)
+func (c *StaticListCache[T]) LastRefresh() time.Time {
+	if snapshot := c.snapshot.Load(); snapshot != nil {
+		return snapshot.refreshedAt
+	}
+	return time.Time{}
+}
+
+// Start refreshes the list every interval in the background until Stop is called. A
+// non-positive interval disables periodic refreshes.
+func (c *StaticListCache[T]) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	c.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					ctx, cancel := context.WithTimeout(context.Background(), staticListRefreshTimeout)
+					if err := c.Refresh(ctx); err != nil {
+						c.logger.Warn("failed to refresh static list, serving previous version",
+							zap.String("list", c.name), zap.Error(err))
+					}
+					cancel()
+				case <-c.stop:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Stop stops the background refreshes
+func (c *StaticListCache[T]) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+)
+
+func TestStaticListCache_LoadsLazily(t *testing.T) {
+	var loads int32
+	cache := NewStaticListCache("test", func(ctx context.Context) ([]string, error) {
+		atomic.AddInt32(&loads, 1)
+		return []string{"a", "b"}, nil
+	}, logging.GetLogger())
+
+	if !cache.LastRefresh().IsZero() {
+		t.Error("LastRefresh() should be zero before the first load")
+	}
+	if got := atomic.LoadInt32(&loads); got != 0 {
+		t.Errorf("loader called %d times before Get, want 0", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		values, refreshedAt, err := cache.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(values) != 2 {
+			t.Errorf("Get() returned %d values, want 2", len(values))
+		}
+		if refreshedAt.IsZero() {
+			t.Error("Get() returned a zero refresh time")
+		}
+	}
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("loader called %d times, want 1", got)
+	}
+}
+
+func TestStaticListCache_RefreshSwapsSnapshot(t *testing.T) {
+	version := int32(0)
+	cache := NewStaticListCache("test", func(ctx context.Context) (int32, error) {
+		return atomic.AddInt32(&version, 1), nil
+	}, logging.GetLogger())
+
+	first, firstRefresh, err := cache.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	second, secondRefresh, _ := cache.Get(context.Background())
+	if second != first+1 {
+		t.Errorf("Get() after Refresh() = %d, want %d", second, first+1)
+	}
+	if !secondRefresh.After(firstRefresh) {
+		t.Errorf("refresh time %v should be after %v", secondRefresh, firstRefresh)
+	}
+	if !cache.LastRefresh().Equal(secondRefresh) {
+		t.Errorf("LastRefresh() = %v, want %v", cache.LastRefresh(), secondRefresh)
+	}
+}
+
+func TestStaticListCache_FailedRefreshKeepsPreviousSnapshot(t *testing.T) {
+	fail := false
+	cache := NewStaticListCache("test", func(ctx context.Context) (string, error) {
+		if fail {
+			return "", errors.New("source unavailable")
+		}
+		return "ok", nil
+	}, logging.GetLogger())
+
+	value, refreshedAt, err := cache.Get(context.Background())
+	if err != nil || value != "ok" {
+		t.Fatalf("Get() = %q, %v", value, err)
+	}
+
+	fail = true
+	if err := cache.Refresh(context.Background()); err == nil {
+		t.Error("Refresh() should return the loader error")
+	}
+
+	value, stillRefreshedAt, err := cache.Get(context.Background())
+	if err != nil || value != "ok" {
+		t.Errorf("Get() after failed refresh = %q, %v, want previous value", value, err)
+	}
+	if !stillRefreshedAt.Equal(refreshedAt) {
+		t.Errorf("refresh time changed after failed refresh: %v -> %v", refreshedAt, stillRefreshedAt)
+	}
+}
+
+func TestStaticListCache_GetFailsWithoutSnapshot(t *testing.T) {
+	cache := NewStaticListCache("test", func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("source unavailable")
+	}, logging.GetLogger())
+
+	if _, _, err := cache.Get(context.Background()); err == nil {
+		t.Error("Get() should fail when the list was never loaded")
+	}
+}
+
+func TestStaticListCache_StartRefreshesPeriodically(t *testing.T) {
+	var loads int32
+	cache := NewStaticListCache("test", func(ctx context.Context) (int32, error) {
+		return atomic.AddInt32(&loads, 1), nil
+	}, logging.GetLogger())
+
+	cache.Start(10 * time.Millisecond)
+	defer cache.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&loads) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("loader called %d times, want at least 3", atomic.LoadInt32(&loads))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cache.Stop()
+	cache.Stop() // must be idempotent
+	time.Sleep(30 * time.Millisecond)
+	stopped := atomic.LoadInt32(&loads)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&loads); got != stopped {
+		t.Errorf("loader called %d more times after Stop()", got-stopped)
+	}
+}
+
+func TestStaticListCache_ConcurrentReadsAndRefreshes(t *testing.T) {
+	cache := NewStaticListCache("test", func(ctx context.Context) ([]string, error) {
+		return []string{"a", "b", "c"}, nil
+	}, logging.GetLogger())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i%5 == 0 {
+					if err := cache.Refresh(context.Background()); err != nil {
+						t.Errorf("Refresh() error = %v", err)
+					}
+					continue
+				}
+				values, _, err := cache.Get(context.Background())
+				if err != nil || len(values) != 3 {
+					t.Errorf("Get() = %v, %v", values, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
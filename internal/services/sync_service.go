@@ -3,23 +3,35 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
+// Flush triggers, used to label the flushed jobs metric
+const (
+	SyncFlushTriggerShutdown = "shutdown"
+	SyncFlushTriggerAdmin    = "admin"
+)
+
 // SyncService manages background sync operations from Redis to MongoDB
 type SyncService struct {
 	redis        *redisclient.Client
 	mongo        *mongo.Database
 	workers      []*SyncWorker
-	workerCount  int
+	workerCount  int            // workers of the shared pool
+	pools        map[string]int // dedicated workers per queue (SYNC_WORKER_POOLS)
 	logger       *logging.SafeLogger
 	metrics      *Metrics
 	degradedMode *DegradedMode
+	stop         chan struct{}
 }
 
 // NewSyncService creates a new sync service
@@ -31,22 +43,25 @@ func NewSyncService(redis *redisclient.Client, mongo *mongo.Database, workerCoun
 		redis:        redis,
 		mongo:        mongo,
 		workerCount:  workerCount,
+		pools:        config.AppConfig.SyncWorkerPools,
 		logger:       logger,
 		metrics:      metrics,
 		degradedMode: degradedMode,
+		stop:         make(chan struct{}),
 	}
 }
 
 // Start starts the sync service
 func (s *SyncService) Start() {
-	s.logger.Info("starting sync service", zap.Int("worker_count", s.workerCount))
+	s.logger.Info("starting sync service", zap.Int("worker_count", s.workerCount), zap.Any("worker_pools", s.pools))
 
 	// Start degraded mode monitoring
 	go s.degradedMode.StartMonitoring()
 
 	// Start workers
-	for i := 0; i < s.workerCount; i++ {
+	for i, queues := range s.planWorkers() {
 		worker := NewSyncWorker(s.redis, s.mongo, i, s.logger, s.metrics, s.degradedMode)
+		worker.queues = queues
 		s.workers = append(s.workers, worker)
 		go worker.Start()
 	}
@@ -54,6 +69,19 @@ func (s *SyncService) Start() {
 	// Start DLQ monitoring
 	go s.monitorDLQ()
 
+	// Start publishing queue depths
+	go s.monitorQueueDepth(15 * time.Second)
+
+	// Start releasing expired phone quarantines
+	if interval := config.AppConfig.PhoneQuarantineSweepInterval; interval > 0 {
+		go s.sweepExpiredQuarantines(interval)
+	}
+
+	// Start re-enqueueing self-declared writes stuck in the write buffer
+	if interval := config.AppConfig.WriteBufferReconcileInterval; interval > 0 {
+		go s.reconcileWriteBuffers(interval)
+	}
+
 	s.logger.Info("sync service started successfully")
 }
 
@@ -64,14 +92,154 @@ func (s *SyncService) Stop() {
 	// Stop degraded mode monitoring
 	s.degradedMode.Stop()
 
+	// Stop background loops
+	close(s.stop)
+
 	// Stop all workers
 	for _, worker := range s.workers {
 		worker.Stop()
 	}
 
+	// Drain what is left so a deploy doesn't lose the tail of the write-behind queues
+	if timeout := config.AppConfig.SyncFlushTimeout; timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		result, err := s.Flush(ctx, SyncFlushTriggerShutdown, false)
+		if err != nil {
+			s.logger.Error("failed to flush sync queues on shutdown", zap.Error(err))
+		} else if !result.Complete {
+			s.logger.Warn("sync queues not fully flushed on shutdown",
+				zap.Duration("timeout", timeout),
+				zap.Int64("pending", result.TotalPending),
+				zap.Int("flushed", result.TotalFlushed),
+				zap.Int("failed", result.TotalFailed))
+		} else {
+			s.logger.Info("sync queues flushed on shutdown",
+				zap.Int("flushed", result.TotalFlushed),
+				zap.Int("failed", result.TotalFailed))
+		}
+	}
+
 	s.logger.Info("sync service stopped")
 }
 
+// Flush synchronously drains the sync queues (see FlushSyncQueues)
+func (s *SyncService) Flush(ctx context.Context, trigger string, dryRun bool) (*SyncFlushResult, error) {
+	worker := NewSyncWorker(s.redis, s.mongo, -1, s.logger, s.metrics, s.degradedMode)
+	return flushSyncQueues(ctx, worker, trigger, dryRun)
+}
+
+// FlushSyncQueues synchronously drains every sync queue until it is empty or ctx is done, returning
+// the pending, flushed and failed counts per queue. With dryRun only the pending counts are reported.
+func FlushSyncQueues(ctx context.Context, trigger string, dryRun bool) (*SyncFlushResult, error) {
+	logger := logging.GetLogger()
+	metrics := NewMetrics()
+	worker := NewSyncWorker(config.Redis, config.MongoDB, -1, logger, metrics, NewDegradedMode(config.Redis, config.MongoDB, metrics))
+	return flushSyncQueues(ctx, worker, trigger, dryRun)
+}
+
+func flushSyncQueues(ctx context.Context, worker *SyncWorker, trigger string, dryRun bool) (*SyncFlushResult, error) {
+	pending, err := syncQueueBacklog(ctx, worker.redis)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncFlushResult{
+		DryRun:  dryRun,
+		Pending: pending,
+	}
+	for _, count := range pending {
+		result.TotalPending += count
+	}
+	if dryRun {
+		result.Complete = result.TotalPending == 0
+		return result, nil
+	}
+
+	result.Flushed = make(map[string]int)
+	result.Failed = make(map[string]int)
+	result.Complete = true
+	for _, queue := range SyncQueueNames {
+		if pending[queue] == 0 {
+			continue
+		}
+
+		flushed, failed, err := worker.drainQueue(ctx, queue, pending[queue])
+		result.Flushed[queue] = flushed
+		result.Failed[queue] = failed
+		result.TotalFlushed += flushed
+		result.TotalFailed += failed
+		observability.RMISyncFlushedJobsTotal.WithLabelValues(queue, trigger).Add(float64(flushed))
+
+		if err != nil {
+			result.Complete = false
+			if ctx.Err() != nil {
+				break
+			}
+			worker.logger.Error("failed to drain sync queue", zap.String("queue", queue), zap.Error(err))
+			continue
+		}
+		if failed > 0 {
+			result.Complete = false
+		}
+	}
+
+	return result, nil
+}
+
+// planWorkers returns the queues polled by each worker. Queues listed in SYNC_WORKER_POOLS get
+// their own workers, so a flood in one of them (e.g. cf_lookup) can't delay the others; the
+// remaining queues share workerCount workers.
+func (s *SyncService) planWorkers() [][]string {
+	var plan [][]string
+	shared := make([]string, 0, len(SyncQueueNames))
+	for _, queue := range SyncQueueNames {
+		workers, dedicated := s.pools[queue]
+		if !dedicated {
+			shared = append(shared, queue)
+			continue
+		}
+		for i := 0; i < workers; i++ {
+			plan = append(plan, []string{queue})
+		}
+	}
+	for queue := range s.pools {
+		if !slices.Contains(SyncQueueNames, queue) {
+			s.logger.Warn("ignoring worker pool of unknown sync queue", zap.String("queue", queue))
+		}
+	}
+
+	if len(shared) > 0 {
+		for i := 0; i < s.workerCount; i++ {
+			plan = append(plan, shared)
+		}
+	}
+	return plan
+}
+
+// monitorQueueDepth periodically publishes the depth of every sync queue
+func (s *SyncService) monitorQueueDepth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			backlog, err := syncQueueBacklog(context.Background(), s.redis)
+			if err != nil {
+				s.logger.Warn("failed to read sync queue depths", zap.Error(err))
+				continue
+			}
+			for queue, depth := range backlog {
+				s.metrics.RecordQueueDepth(queue, depth)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
 // monitorDLQ monitors the dead letter queue
 func (s *SyncService) monitorDLQ() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -79,7 +247,7 @@ func (s *SyncService) monitorDLQ() {
 
 	for range ticker.C {
 		// Check all DLQ sizes
-		queues := []string{"citizen", "phone_mapping", "user_config", "opt_in_history", "beta_group", "phone_verification", "maintenance_request", "self_declared_address", "self_declared_email", "self_declared_phone", "self_declared_raca", "self_declared_nome_exibicao", "cf_lookup"}
+		queues := []string{"citizen", "phone_mapping", "user_config", "opt_in_history", "beta_group", "phone_verification", "maintenance_request", "self_declared_address", "self_declared_email", "self_declared_phone", "self_declared_raca", "self_declared_nome_exibicao", "cf_lookup", AddressWebhookJobType, PhoneVerifiedWebhookJobType}
 
 		for _, queue := range queues {
 			dlqKey := fmt.Sprintf("sync:dlq:%s", queue)
@@ -100,6 +268,61 @@ func (s *SyncService) monitorDLQ() {
 	}
 }
 
+// sweepExpiredQuarantines periodically releases phone numbers whose quarantine has expired
+func (s *SyncService) sweepExpiredQuarantines(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			released, err := s.SweepExpiredQuarantines(ctx)
+			cancel()
+			if err != nil {
+				s.logger.Error("failed to release expired phone quarantines", zap.Error(err), zap.Int("released", released))
+			} else if released > 0 {
+				s.logger.Info("released expired phone quarantines", zap.Int("released", released))
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// SweepExpiredQuarantines releases every phone number whose quarantine has expired, in batches of
+// PHONE_QUARANTINE_SWEEP_BATCH_SIZE, auditing each batch. Returns how many were released.
+func (s *SyncService) SweepExpiredQuarantines(ctx context.Context) (int, error) {
+	batchSize := config.AppConfig.PhoneQuarantineSweepBatchSize
+	if batchSize <= 0 {
+		batchSize = 500 // Default value
+	}
+
+	phoneMappingService := NewPhoneMappingService(s.logger)
+	auditCtx := utils.AuditContext{UserID: "system:quarantine_sweeper"}
+
+	total := 0
+	for {
+		released, err := phoneMappingService.ReleaseExpiredQuarantines(ctx, batchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(released) == 0 {
+			return total, nil
+		}
+
+		total += len(released)
+		observability.PhoneQuarantineReleasedTotal.Add(float64(len(released)))
+		if err := utils.LogPhoneQuarantineExpiry(ctx, auditCtx, released); err != nil {
+			s.logger.Warn("failed to log quarantine expiry audit event", zap.Error(err))
+		}
+
+		if len(released) < batchSize {
+			return total, nil
+		}
+	}
+}
+
 // GetMetrics returns the metrics for monitoring
 func (s *SyncService) GetMetrics() *Metrics {
 	return s.metrics
@@ -141,6 +141,37 @@ func TestNewSyncService_DifferentWorkerCounts(t *testing.T) {
 	}
 }
 
+func TestSyncService_PlanWorkers(t *testing.T) {
+	service := &SyncService{
+		workerCount: 2,
+		pools:       map[string]int{"cf_lookup": 3, "self_declared_raca": 1, "unknown_queue": 4},
+		logger:      logging.GetLogger(),
+	}
+
+	plan := service.planWorkers()
+	require.Len(t, plan, 6, "3 cf_lookup + 1 self_declared_raca + 2 shared workers")
+
+	dedicated := map[string]int{}
+	for _, queues := range plan[:4] {
+		require.Len(t, queues, 1)
+		dedicated[queues[0]]++
+	}
+	assert.Equal(t, map[string]int{"cf_lookup": 3, "self_declared_raca": 1}, dedicated)
+
+	for _, queues := range plan[4:] {
+		assert.Len(t, queues, len(SyncQueueNames)-2)
+		assert.NotContains(t, queues, "cf_lookup", "dedicated queues are not polled by the shared pool")
+		assert.NotContains(t, queues, "self_declared_raca")
+		assert.Contains(t, queues, "self_declared_address")
+	}
+
+	// Without pools every worker polls every queue
+	service.pools = nil
+	plan = service.planWorkers()
+	require.Len(t, plan, 2)
+	assert.Equal(t, SyncQueueNames, plan[0])
+}
+
 // TestSyncService_Start tests starting the sync service
 func TestSyncService_Start(t *testing.T) {
 	service, _, _, cleanup := setupSyncServiceTest(t)
@@ -152,8 +183,8 @@ func TestSyncService_Start(t *testing.T) {
 	// Give workers time to initialize
 	time.Sleep(100 * time.Millisecond)
 
-	// Verify workers were created
-	assert.Equal(t, service.workerCount, len(service.workers))
+	// Verify workers were created, dedicated pools included
+	assert.Equal(t, len(service.planWorkers()), len(service.workers))
 	assert.NotEmpty(t, service.workers)
 
 	// Stop the service
@@ -172,7 +203,7 @@ func TestSyncService_StartStop(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 
 	// Verify service is running (workers created)
-	assert.Equal(t, service.workerCount, len(service.workers))
+	assert.Equal(t, len(service.planWorkers()), len(service.workers))
 
 	// Stop the service
 	service.Stop()
@@ -735,3 +766,93 @@ func TestSyncService_Lifecycle(t *testing.T) {
 	// 8. Verify degraded mode stopped
 	assert.False(t, service.degradedMode.IsActive())
 }
+
+// pushCitizenSyncJobs queues citizen sync jobs for the flush tests
+func pushCitizenSyncJobs(t *testing.T, redisClient *redisclient.Client, numJobs int) {
+	ctx := context.Background()
+	for i := 0; i < numJobs; i++ {
+		testJob := SyncJob{
+			ID:         uuid.New().String(),
+			Type:       "citizen",
+			Key:        fmt.Sprintf("1234567890%d", i),
+			Collection: "test_citizens",
+			Data: map[string]interface{}{
+				"cpf":  fmt.Sprintf("1234567890%d", i),
+				"nome": fmt.Sprintf("Test User %d", i),
+			},
+			Timestamp:  time.Now(),
+			MaxRetries: 3,
+		}
+
+		jobBytes, err := json.Marshal(testJob)
+		require.NoError(t, err)
+		require.NoError(t, redisClient.LPush(ctx, "sync:queue:citizen", string(jobBytes)).Err())
+	}
+}
+
+// TestSyncService_Flush_DryRun tests that a dry run only reports pending jobs
+func TestSyncService_Flush_DryRun(t *testing.T) {
+	service, redisClient, _, cleanup := setupSyncServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pushCitizenSyncJobs(t, redisClient, 3)
+
+	result, err := service.Flush(ctx, SyncFlushTriggerAdmin, true)
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, int64(3), result.Pending["citizen"])
+	assert.Equal(t, int64(3), result.TotalPending)
+	assert.Zero(t, result.TotalFlushed)
+	assert.False(t, result.Complete)
+
+	depth, err := redisClient.LLen(ctx, "sync:queue:citizen").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), depth, "dry run must not consume jobs")
+}
+
+// TestSyncService_Flush tests that a flush drains the queues synchronously
+func TestSyncService_Flush(t *testing.T) {
+	service, redisClient, db, cleanup := setupSyncServiceTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pushCitizenSyncJobs(t, redisClient, 5)
+
+	result, err := service.Flush(ctx, SyncFlushTriggerAdmin, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, result.Flushed["citizen"])
+	assert.Equal(t, 5, result.TotalFlushed)
+	assert.Zero(t, result.TotalFailed)
+	assert.True(t, result.Complete)
+
+	depth, err := redisClient.LLen(ctx, "sync:queue:citizen").Result()
+	require.NoError(t, err)
+	assert.Zero(t, depth)
+
+	count, err := db.Collection("test_citizens").CountDocuments(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+// TestSyncService_Stop_FlushesQueues tests that stopping the service drains pending jobs
+func TestSyncService_Stop_FlushesQueues(t *testing.T) {
+	service, redisClient, _, cleanup := setupSyncServiceTest(t)
+	defer cleanup()
+
+	originalTimeout := config.AppConfig.SyncFlushTimeout
+	config.AppConfig.SyncFlushTimeout = 10 * time.Second
+	defer func() { config.AppConfig.SyncFlushTimeout = originalTimeout }()
+
+	ctx := context.Background()
+	pushCitizenSyncJobs(t, redisClient, 5)
+
+	// Never started, so only the shutdown flush can consume the jobs
+	service.Stop()
+
+	depth, err := redisClient.LLen(ctx, "sync:queue:citizen").Result()
+	require.NoError(t, err)
+	assert.Zero(t, depth)
+}
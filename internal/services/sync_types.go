@@ -31,3 +31,15 @@ type SyncResult struct {
 	SyncedAt time.Time     `json:"synced_at"`
 	Duration time.Duration `json:"duration"`
 }
+
+// SyncFlushResult reports a synchronous drain of the sync queues
+type SyncFlushResult struct {
+	DryRun       bool             `json:"dry_run"`
+	Pending      map[string]int64 `json:"pending"`
+	Flushed      map[string]int   `json:"flushed,omitempty"`
+	Failed       map[string]int   `json:"failed,omitempty"`
+	TotalPending int64            `json:"total_pending"`
+	TotalFlushed int              `json:"total_flushed"`
+	TotalFailed  int              `json:"total_failed"`
+	Complete     bool             `json:"complete"`
+}
@@ -3,18 +3,64 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
+// SyncQueueNames lists the sync queues processed by the sync workers
+var SyncQueueNames = []string{
+	"citizen",
+	"phone_mapping",
+	"user_config",
+	"opt_in_history",
+	"beta_group",
+	"phone_verification",
+	"maintenance_request",
+	"self_declared_address",
+	"self_declared_email",
+	"self_declared_phone",
+	"self_declared_raca",
+	"self_declared_nome_exibicao",
+	"self_declared_genero",
+	"self_declared_renda_familiar",
+	"self_declared_escolaridade",
+	"self_declared_deficiencia",
+	"cf_lookup",
+	AddressWebhookJobType,
+	PhoneVerifiedWebhookJobType,
+}
+
+// GetSyncQueueBacklog returns the number of pending jobs in each sync queue
+func GetSyncQueueBacklog(ctx context.Context) (map[string]int64, error) {
+	return syncQueueBacklog(ctx, config.Redis)
+}
+
+func syncQueueBacklog(ctx context.Context, redis *redisclient.Client) (map[string]int64, error) {
+	backlog := make(map[string]int64, len(SyncQueueNames))
+	for _, queue := range SyncQueueNames {
+		depth, err := redis.LLen(ctx, fmt.Sprintf("sync:queue:%s", queue)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get depth of queue %s: %w", queue, err)
+		}
+		backlog[queue] = depth
+	}
+	return backlog, nil
+}
+
 // SyncWorker processes sync jobs from Redis queues
 type SyncWorker struct {
 	id           int
@@ -25,6 +71,7 @@ type SyncWorker struct {
 	degradedMode *DegradedMode
 	stopChan     chan struct{}
 	queues       []string
+	next         int // queue the next cycle starts from, so every queue gets its turn first
 }
 
 // NewSyncWorker creates a new sync worker
@@ -37,25 +84,7 @@ func NewSyncWorker(redis *redisclient.Client, mongo *mongo.Database, id int, log
 		metrics:      metrics,
 		degradedMode: degradedMode,
 		stopChan:     make(chan struct{}),
-		queues: []string{
-			"citizen",
-			"phone_mapping",
-			"user_config",
-			"opt_in_history",
-			"beta_group",
-			"phone_verification",
-			"maintenance_request",
-			"self_declared_address",
-			"self_declared_email",
-			"self_declared_phone",
-			"self_declared_raca",
-			"self_declared_nome_exibicao",
-			"self_declared_genero",
-			"self_declared_renda_familiar",
-			"self_declared_escolaridade",
-			"self_declared_deficiencia",
-			"cf_lookup",
-		},
+		queues:       SyncQueueNames,
 	}
 }
 
@@ -96,11 +125,15 @@ func (w *SyncWorker) processQueuesParallel() {
 	const maxJobsPerCycle = 3
 	jobsProcessed := 0
 
-	// Use round-robin approach to fairly distribute processing across queues
-	for _, queue := range w.queues {
+	// Use round-robin approach to fairly distribute processing across queues, starting each cycle
+	// one queue further so the queues at the end of the list aren't left for last every time
+	start := w.next
+	w.next = (w.next + 1) % max(len(w.queues), 1)
+	for i := range w.queues {
 		if jobsProcessed >= maxJobsPerCycle {
 			break
 		}
+		queue := w.queues[(start+i)%len(w.queues)]
 
 		// Non-blocking job retrieval
 		job, err := w.getJobNonBlocking(queue)
@@ -156,6 +189,9 @@ func (w *SyncWorker) getJobNonBlocking(queue string) (*SyncJob, error) {
 func (w *SyncWorker) processJob(job *SyncJob) {
 	start := time.Now()
 
+	observability.RMISyncJobsInFlight.WithLabelValues(job.Type).Inc()
+	defer observability.RMISyncJobsInFlight.WithLabelValues(job.Type).Dec()
+
 	w.logger.Info("processing sync job",
 		zap.String("job_id", job.ID),
 		zap.String("type", job.Type),
@@ -167,6 +203,14 @@ func (w *SyncWorker) processJob(job *SyncJob) {
 
 	duration := time.Since(start)
 
+	if errors.Is(err, errStaleSelfDeclaredJob) {
+		// A newer write of the field is already stored; the job's data, cache and buffer are outdated
+		w.logger.Info("dropped stale self-declared sync job",
+			zap.String("job_id", job.ID),
+			zap.String("type", job.Type),
+			zap.String("key", job.Key))
+		return
+	}
 	if err != nil {
 		w.handleSyncFailure(job, err)
 		w.metrics.IncrementSyncFailures(job.Type)
@@ -237,16 +281,11 @@ func (w *SyncWorker) syncToMongoDB(job *SyncJob) error {
 
 	// For self_declared collection, use field-specific updates to avoid overwriting other fields
 	var update bson.M
+	versioned := false
 	if job.Collection == "self_declared" {
 		fieldName := getFieldNameFromJobType(job.Type)
-		if fieldName != "" && bsonData[fieldName] != nil {
-			// Only update the specific field and timestamp, preserve other fields
-			update = bson.M{
-				"$set": bson.M{
-					fieldName:    bsonData[fieldName],
-					"updated_at": bsonData["updated_at"],
-				},
-			}
+		if update = selfDeclaredFieldUpdate(job.Type, bsonData); update != nil {
+			versioned = skipNewerSelfDeclaredField(filter, job.Type, bsonData)
 			w.logger.Debug("using field-specific update for self_declared collection",
 				zap.String("job_id", job.ID),
 				zap.String("job_type", job.Type),
@@ -254,7 +293,7 @@ func (w *SyncWorker) syncToMongoDB(job *SyncJob) error {
 				zap.String("cpf", job.Key))
 		} else {
 			// Fallback to full document update if field mapping fails
-			update = bson.M{"$set": bsonData}
+			update = documentUpdate(job.Collection, bsonData)
 			w.logger.Warn("falling back to full document update for self_declared",
 				zap.String("job_id", job.ID),
 				zap.String("job_type", job.Type),
@@ -263,7 +302,7 @@ func (w *SyncWorker) syncToMongoDB(job *SyncJob) error {
 		}
 	} else {
 		// For other collections, update the entire document
-		update = bson.M{"$set": bsonData}
+		update = documentUpdate(job.Collection, bsonData)
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -272,6 +311,10 @@ func (w *SyncWorker) syncToMongoDB(job *SyncJob) error {
 	if err != nil {
 		// Check if it's a duplicate key error - this is expected and not an error
 		if mongo.IsDuplicateKeyError(err) {
+			if versioned {
+				// The upsert didn't match the citizen's document because its field is newer
+				return errStaleSelfDeclaredJob
+			}
 			w.logger.Debug("duplicate key during sync - data already exists",
 				zap.String("job_id", job.ID),
 				zap.String("type", job.Type),
@@ -335,6 +378,15 @@ func (w *SyncWorker) handleSyncSuccess(job *SyncJob) {
 			zap.String("write_key", writeKey))
 	}
 
+	if field := getFieldNameFromJobType(job.Type); field != "" {
+		EventBusInstance.PublishSelfDeclaredSynced(ctx, job.Key, field)
+	} else if job.Type == "maintenance_request" {
+		var request models.MaintenanceRequest
+		if err := json.Unmarshal(dataBytes, &request); err == nil && request.CPF != "" {
+			EventBusInstance.PublishMaintenanceChanged(ctx, request.CPF, job.Key, request.Status)
+		}
+	}
+
 	w.logger.Info("sync job succeeded - cache updated and write buffer cleaned",
 		zap.String("job_id", job.ID),
 		zap.String("type", job.Type),
@@ -385,10 +437,7 @@ func (w *SyncWorker) requeueJob(job *SyncJob) {
 	// Re-queue with delay
 	time.Sleep(backoffDelay)
 
-	jobBytes, _ := json.Marshal(job)
-	queueKey := fmt.Sprintf("sync:queue:%s", job.Type)
-
-	w.redis.LPush(context.Background(), queueKey, string(jobBytes))
+	w.pushJob(job)
 
 	w.logger.Info("job re-queued for retry",
 		zap.String("job_id", job.ID),
@@ -398,6 +447,51 @@ func (w *SyncWorker) requeueJob(job *SyncJob) {
 		zap.Duration("backoff_delay", backoffDelay))
 }
 
+// pushJob puts a job back at the tail of its queue
+func (w *SyncWorker) pushJob(job *SyncJob) {
+	jobBytes, _ := json.Marshal(job)
+	queueKey := fmt.Sprintf("sync:queue:%s", job.Type)
+
+	w.redis.LPush(context.Background(), queueKey, string(jobBytes))
+}
+
+// drainQueue synchronously processes up to limit jobs from a queue, stopping early when the queue
+// is empty or ctx is done. The limit keeps failed jobs, which are re-queued without backoff so the
+// drain isn't stalled, from being retried in a loop.
+func (w *SyncWorker) drainQueue(ctx context.Context, queue string, limit int64) (flushed int, failed int, err error) {
+	for i := int64(0); i < limit; i++ {
+		if err := ctx.Err(); err != nil {
+			return flushed, failed, err
+		}
+
+		job, err := w.getJobNonBlocking(queue)
+		if err != nil {
+			return flushed, failed, err
+		}
+		if job == nil {
+			break
+		}
+
+		if syncErr := w.syncToMongoDB(job); syncErr != nil {
+			w.metrics.IncrementSyncFailures(job.Type)
+			job.RetryCount++
+			if job.RetryCount >= job.MaxRetries {
+				w.moveToDLQ(job, syncErr)
+			} else {
+				w.pushJob(job)
+			}
+			failed++
+			continue
+		}
+
+		w.handleSyncSuccess(job)
+		w.metrics.IncrementSyncOperations(job.Type)
+		flushed++
+	}
+
+	return flushed, failed, nil
+}
+
 // handleSpecialJobTypes handles special job types that don't follow normal MongoDB sync pattern
 func (w *SyncWorker) handleSpecialJobTypes(ctx context.Context, job *SyncJob) error {
 	// Check if this is an avatar cleanup job
@@ -412,6 +506,14 @@ func (w *SyncWorker) handleSpecialJobTypes(ctx context.Context, job *SyncJob) er
 		return w.handleCFLookupJob(ctx, job)
 	}
 
+	if job.Type == AddressWebhookJobType {
+		return w.handleAddressWebhookJob(ctx, job)
+	}
+
+	if job.Type == PhoneVerifiedWebhookJobType {
+		return w.handlePhoneVerifiedWebhookJob(ctx, job)
+	}
+
 	// Not a special job type
 	return fmt.Errorf("not_special_job")
 }
@@ -473,6 +575,14 @@ func (w *SyncWorker) handleCFLookupJob(ctx context.Context, job *SyncJob) error
 		return fmt.Errorf("missing or invalid address in CF lookup job")
 	}
 
+	// Continue the trace of the request that queued the job
+	ctx, span := otel.Tracer("").Start(observability.ExtractTraceContextFromJob(ctx, data), "SyncWorker.handleCFLookupJob")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.id", job.ID),
+		attribute.String("cpf", cpf),
+	)
+
 	w.logger.Debug("extracted CF lookup job data",
 		zap.String("cpf", cpf),
 		zap.String("address", address))
@@ -482,7 +592,23 @@ func (w *SyncWorker) handleCFLookupJob(ctx context.Context, job *SyncJob) error
 		return fmt.Errorf("CF lookup service disabled")
 	}
 
+	// Jobs of bulk batches are paced by the global CF rate limiter and counted in their batch
+	batchID, _ := data[CFLookupBatchIDKey].(string)
+	if batchID != "" {
+		span.SetAttributes(attribute.String("cf_lookup.batch_id", batchID))
+		if err := waitForCFRateLimit(ctx, cpf); err != nil {
+			return fmt.Errorf("CF lookup rate limit wait interrupted: %w", err)
+		}
+	}
+
 	err := CFLookupServiceInstance.PerformCFLookup(ctx, cpf, address)
+	if batchID != "" {
+		if recordErr := recordCFLookupBatchResult(ctx, batchID, err); recordErr != nil {
+			w.logger.Warn("failed to record CF lookup batch result",
+				zap.Error(recordErr),
+				zap.String("batch_id", batchID))
+		}
+	}
 	if err != nil {
 		w.logger.Error("CF lookup failed",
 			zap.Error(err),
@@ -512,6 +638,126 @@ func (w *SyncWorker) handleCFLookupJob(ctx context.Context, job *SyncJob) error
 	return nil
 }
 
+// handleAddressWebhookJob delivers an address change webhook; failures are retried by the
+// regular sync retry/DLQ handling
+func (w *SyncWorker) handleAddressWebhookJob(ctx context.Context, job *SyncJob) error {
+	if !config.AppConfig.AddressWebhookEnabled {
+		w.logger.Info("address webhook disabled - dropping queued notification", zap.String("job_id", job.ID))
+		return nil
+	}
+
+	dataBytes, err := json.Marshal(job.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal address webhook job data: %w", err)
+	}
+	var payload models.AddressChangeWebhookPayload
+	if err := json.Unmarshal(dataBytes, &payload); err != nil {
+		return fmt.Errorf("invalid job data format for address webhook: %w", err)
+	}
+
+	attempt := job.RetryCount + 1
+	if err := DeliverAddressChangeWebhook(ctx, payload); err != nil {
+		w.logger.Warn("address webhook delivery failed",
+			zap.String("job_id", job.ID),
+			zap.String("cpf", payload.CPF),
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", job.MaxRetries),
+			zap.Error(err))
+		return fmt.Errorf("address webhook delivery failed: %w", err)
+	}
+
+	w.logger.Info("address webhook delivered",
+		zap.String("job_id", job.ID),
+		zap.String("cpf", payload.CPF),
+		zap.Int("attempt", attempt))
+	return nil
+}
+
+// handlePhoneVerifiedWebhookJob delivers a phone verified webhook; failures are retried by the
+// regular sync retry/DLQ handling
+func (w *SyncWorker) handlePhoneVerifiedWebhookJob(ctx context.Context, job *SyncJob) error {
+	if !config.AppConfig.PhoneVerifiedWebhookEnabled {
+		w.logger.Info("phone verified webhook disabled - dropping queued notification", zap.String("job_id", job.ID))
+		return nil
+	}
+
+	dataBytes, err := json.Marshal(job.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal phone verified webhook job data: %w", err)
+	}
+	var payload models.PhoneVerifiedWebhookPayload
+	if err := json.Unmarshal(dataBytes, &payload); err != nil {
+		return fmt.Errorf("invalid job data format for phone verified webhook: %w", err)
+	}
+
+	attempt := job.RetryCount + 1
+	if err := DeliverPhoneVerifiedWebhook(ctx, payload); err != nil {
+		w.logger.Warn("phone verified webhook delivery failed",
+			zap.String("job_id", job.ID),
+			zap.String("cpf", payload.CPF),
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", job.MaxRetries),
+			zap.Error(err))
+		return fmt.Errorf("phone verified webhook delivery failed: %w", err)
+	}
+
+	w.logger.Info("phone verified webhook delivered",
+		zap.String("job_id", job.ID),
+		zap.String("cpf", payload.CPF),
+		zap.Int("attempt", attempt))
+	return nil
+}
+
+// selfDeclaredFieldUpdate builds the update for a self_declared job that only touches the job's
+// field and timestamps, preserving the other fields. It returns nil when the job type has no field
+// mapping or the data lacks the field. data is the job data decoded from JSON.
+func selfDeclaredFieldUpdate(jobType string, data bson.M) bson.M {
+	fieldName := getFieldNameFromJobType(jobType)
+	if fieldName == "" || data[fieldName] == nil {
+		return nil
+	}
+
+	update := utils.TimestampedUpdate(bson.M{fieldName: data[fieldName]}, documentWriteTime(data))
+	// Record the write's version, for the document and the field; $max keeps jobs synced out of
+	// order from lowering them
+	if version := selfDeclaredDataVersion(data); version > 0 {
+		update["$max"] = bson.M{
+			"version": version,
+			selfDeclaredFieldVersionsField + "." + fieldName: version,
+		}
+	}
+	return update
+}
+
+// selfDeclaredFieldVersionsField holds the version of the last write of each self-declared field
+const selfDeclaredFieldVersionsField = "field_versions"
+
+// errStaleSelfDeclaredJob is returned for a self_declared job whose field was already written by a
+// newer version, e.g. by a critical write made while the job was queued
+var errStaleSelfDeclaredJob = errors.New("self-declared field already written by a newer version")
+
+// selfDeclaredDataVersion returns the version of self-declared data converted through JSON, or 0
+func selfDeclaredDataVersion(data bson.M) int32 {
+	if version, ok := data["version"].(float64); ok && version > 0 {
+		return int32(version)
+	}
+	return 0
+}
+
+// skipNewerSelfDeclaredField narrows the filter of a self_declared field update so it doesn't
+// match a document whose field was written by a newer version. The upsert then fails on the
+// unique cpf index instead of overwriting the field. It reports whether the filter was narrowed,
+// which only happens for versioned data.
+func skipNewerSelfDeclaredField(filter bson.M, jobType string, data bson.M) bool {
+	version := selfDeclaredDataVersion(data)
+	fieldName := getFieldNameFromJobType(jobType)
+	if version == 0 || fieldName == "" {
+		return false
+	}
+	filter[selfDeclaredFieldVersionsField+"."+fieldName] = bson.M{"$not": bson.M{"$gt": version}}
+	return true
+}
+
 // getFieldNameFromJobType maps job types to their corresponding database field names
 // This ensures that self_declared updates only modify specific fields instead of overwriting the entire document
 func getFieldNameFromJobType(jobType string) string {
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // setupSyncWorkerTest initializes MongoDB and Redis for testing
@@ -451,6 +452,54 @@ func TestSyncWorker_SyncToMongoDB_SelfDeclaredFieldSpecific(t *testing.T) {
 	assert.Equal(t, "5521888888888", result["telefone"])
 }
 
+// TestSyncWorker_SyncToMongoDB_SelfDeclaredStaleJob tests that a job queued before a newer write
+// of its field, e.g. a critical write, is dropped instead of overwriting it
+func TestSyncWorker_SyncToMongoDB_SelfDeclaredStaleJob(t *testing.T) {
+	worker, db, cleanup := setupSyncWorkerTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	collection := db.Collection("self_declared")
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cpf", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	job := func(jobType, field string, value interface{}, version int) *SyncJob {
+		return &SyncJob{
+			ID:         uuid.New().String(),
+			Type:       jobType,
+			Key:        "12345678901",
+			Collection: "self_declared",
+			Data: map[string]interface{}{
+				field:        value,
+				"version":    version,
+				"updated_at": time.Now(),
+			},
+			Timestamp:  time.Now(),
+			MaxRetries: 3,
+		}
+	}
+
+	// The critical write (version 2) lands before the job queued at version 1
+	require.NoError(t, worker.syncToMongoDB(job("self_declared_email", "email", "new@example.com", 2)))
+	err = worker.syncToMongoDB(job("self_declared_email", "email", "old@example.com", 1))
+	assert.ErrorIs(t, err, errStaleSelfDeclaredJob)
+
+	// Other fields are versioned on their own
+	require.NoError(t, worker.syncToMongoDB(job("self_declared_raca", "raca", "parda", 1)))
+
+	var result bson.M
+	require.NoError(t, collection.FindOne(ctx, bson.M{"cpf": "12345678901"}).Decode(&result))
+	assert.Equal(t, "new@example.com", result["email"])
+	assert.Equal(t, "parda", result["raca"])
+	assert.EqualValues(t, 2, result["version"])
+	count, err := collection.CountDocuments(ctx, bson.M{"cpf": "12345678901"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
 // TestSyncWorker_SyncToMongoDB_AllSelfDeclaredFields tests all self_declared field types
 func TestSyncWorker_SyncToMongoDB_AllSelfDeclaredFields(t *testing.T) {
 	worker, db, cleanup := setupSyncWorkerTest(t)
@@ -1120,6 +1169,54 @@ func TestSyncWorker_RoundRobinProcessing(t *testing.T) {
 	assert.NotEmpty(t, processedQueues)
 }
 
+// TestSyncWorker_QueueIsolation checks that a flooded queue with its own pool doesn't delay the
+// queues of the shared pool
+func TestSyncWorker_QueueIsolation(t *testing.T) {
+	worker, _, cleanup := setupSyncWorkerTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	service := &SyncService{workerCount: 1, pools: map[string]int{"cf_lookup": 1}, logger: worker.logger}
+	plan := service.planWorkers()
+	worker.queues = plan[len(plan)-1]
+
+	for i := 0; i < 20; i++ {
+		job, _ := json.Marshal(SyncJob{ID: uuid.New().String(), Type: "cf_lookup", Key: fmt.Sprintf("cpf-%d", i), MaxRetries: 3})
+		require.NoError(t, worker.redis.LPush(ctx, "sync:queue:cf_lookup", string(job)).Err())
+	}
+	job, _ := json.Marshal(SyncJob{
+		ID:         uuid.New().String(),
+		Type:       "self_declared_raca",
+		Key:        "03561350712",
+		Collection: "self_declared",
+		Data:       map[string]interface{}{"cpf": "03561350712", "raca": "parda", "updated_at": time.Now()},
+		MaxRetries: 3,
+	})
+	require.NoError(t, worker.redis.LPush(ctx, "sync:queue:self_declared_raca", string(job)).Err())
+
+	worker.processQueuesParallel()
+
+	depth, err := worker.redis.LLen(ctx, "sync:queue:self_declared_raca").Result()
+	require.NoError(t, err)
+	assert.Zero(t, depth, "self-declared job should be synced in the first cycle")
+
+	depth, err = worker.redis.LLen(ctx, "sync:queue:cf_lookup").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), depth, "the shared pool must not take cf_lookup jobs")
+}
+
+// TestSyncWorker_RotatesStartQueue checks that each cycle starts from the next queue
+func TestSyncWorker_RotatesStartQueue(t *testing.T) {
+	worker, _, cleanup := setupSyncWorkerTest(t)
+	defer cleanup()
+
+	worker.queues = []string{"citizen", "phone_mapping", "user_config"}
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, i%3, worker.next)
+		worker.processQueuesParallel()
+	}
+}
+
 // TestSyncWorker_ConcurrentJobProcessing tests concurrent job processing
 func TestSyncWorker_ConcurrentJobProcessing(t *testing.T) {
 	worker, db, cleanup := setupSyncWorkerTest(t)
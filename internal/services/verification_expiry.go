@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ExpireVerifications invalidates outstanding phone and email verification codes by setting
+// their expires_at to now, for example when a code leak is suspected. cpf and phoneNumber
+// optionally narrow the phone verifications; email verifications are only filtered by cpf and
+// are left alone when only phoneNumber is given. The writes use the critical write concern so
+// the returned counts reflect acknowledged updates.
+func ExpireVerifications(ctx context.Context, cpf, phoneNumber string) (*models.VerificationExpireResponse, error) {
+	now := time.Now()
+	result := &models.VerificationExpireResponse{ExpiredAt: now}
+	update := bson.M{"$set": bson.M{"expires_at": now}}
+	collectionOpts := options.Collection().SetWriteConcern(utils.GetWriteConcernForOperation("critical"))
+
+	phoneFilter := bson.M{"expires_at": bson.M{"$gt": now}}
+	if cpf != "" {
+		phoneFilter["cpf"] = cpf
+	}
+	if phoneNumber != "" {
+		components, err := utils.ParsePhoneNumber(phoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		phoneFilter["phone_number"] = utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+	}
+
+	phoneResult, err := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection, collectionOpts).
+		UpdateMany(ctx, phoneFilter, update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire phone verifications: %w", err)
+	}
+	result.PhoneVerificationsExpired = phoneResult.ModifiedCount
+
+	if phoneNumber == "" {
+		emailFilter := bson.M{"expires_at": bson.M{"$gt": now}}
+		if cpf != "" {
+			emailFilter["cpf"] = cpf
+		}
+		emailResult, err := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection, collectionOpts).
+			UpdateMany(ctx, emailFilter, update)
+		if err != nil {
+			return result, fmt.Errorf("failed to expire email verifications: %w", err)
+		}
+		result.EmailVerificationsExpired = emailResult.ModifiedCount
+	}
+
+	logging.GetLogger().Warn("verification codes force-expired",
+		zap.String("cpf", cpf),
+		zap.String("phone_number", phoneNumber),
+		zap.Int64("phone_verifications_expired", result.PhoneVerificationsExpired),
+		zap.Int64("email_verifications_expired", result.EmailVerificationsExpired))
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestExpireVerifications(t *testing.T) {
+	setupTestEnvironment()
+	if config.MongoDB == nil {
+		t.Skip("MongoDB not initialized")
+	}
+
+	ctx := context.Background()
+	originalPhone, originalEmail := config.AppConfig.PhoneVerificationCollection, config.AppConfig.EmailVerificationCollection
+	config.AppConfig.PhoneVerificationCollection = "test_expire_phone_verifications"
+	config.AppConfig.EmailVerificationCollection = "test_expire_email_verifications"
+	phones := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
+	emails := config.MongoDB.Collection(config.AppConfig.EmailVerificationCollection)
+	defer func() {
+		_ = phones.Drop(ctx)
+		_ = emails.Drop(ctx)
+		config.AppConfig.PhoneVerificationCollection, config.AppConfig.EmailVerificationCollection = originalPhone, originalEmail
+	}()
+
+	future := time.Now().Add(time.Hour)
+	_, err := phones.InsertMany(ctx, []interface{}{
+		models.PhoneVerification{CPF: "11144477735", PhoneNumber: "5521987654321", Code: "123456", ExpiresAt: future},
+		models.PhoneVerification{CPF: "52998224725", PhoneNumber: "5521912345678", Code: "654321", ExpiresAt: future},
+		models.PhoneVerification{CPF: "52998224725", PhoneNumber: "5521900000000", Code: "111111", ExpiresAt: time.Now().Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+	_, err = emails.InsertMany(ctx, []interface{}{
+		models.EmailVerification{CPF: "11144477735", Email: "a@example.com", Token: "123456", ExpiresAt: future},
+		models.EmailVerification{CPF: "52998224725", Email: "b@example.com", Token: "654321", ExpiresAt: future},
+	})
+	require.NoError(t, err)
+
+	// A phone filter only touches phone verifications
+	result, err := ExpireVerifications(ctx, "", "+55 21 987654321")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.PhoneVerificationsExpired)
+	assert.Equal(t, int64(0), result.EmailVerificationsExpired)
+
+	result, err = ExpireVerifications(ctx, "52998224725", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.PhoneVerificationsExpired, "already expired codes are not counted")
+	assert.Equal(t, int64(1), result.EmailVerificationsExpired)
+
+	result, err = ExpireVerifications(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.PhoneVerificationsExpired)
+	assert.Equal(t, int64(1), result.EmailVerificationsExpired)
+
+	outstanding, err := phones.CountDocuments(ctx, bson.M{"expires_at": bson.M{"$gt": time.Now()}})
+	require.NoError(t, err)
+	assert.Zero(t, outstanding)
+
+	_, err = ExpireVerifications(ctx, "", "not a phone")
+	assert.Error(t, err)
+}
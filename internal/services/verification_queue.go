@@ -172,6 +172,7 @@ func (vq *VerificationQueue) validateVerificationCode(job VerificationJob) error
 		return fmt.Errorf("invalid phone number format: %w", err)
 	}
 	normalizedPhone := utils.FormatPhoneForStorage(components.DDI, components.DDD, components.Valor)
+	code := utils.NormalizeVerificationCode(job.Code)
 
 	// Find the verification code in the database
 	collection := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
@@ -184,7 +185,7 @@ func (vq *VerificationQueue) validateVerificationCode(job VerificationJob) error
 
 	err = collection.FindOne(ctx, bson.M{
 		"phone_number": normalizedPhone,
-		"code":         job.Code,
+		"code":         code,
 		"used":         false,
 		"expires_at":   bson.M{"$gt": time.Now()},
 	}).Decode(&verification)
@@ -198,7 +199,7 @@ func (vq *VerificationQueue) validateVerificationCode(job VerificationJob) error
 
 	// Mark code as used
 	_, err = collection.UpdateOne(ctx,
-		bson.M{"phone_number": normalizedPhone, "code": job.Code},
+		bson.M{"phone_number": normalizedPhone, "code": code},
 		bson.M{"$set": bson.M{"used": true, "used_at": time.Now()}},
 	)
 	if err != nil {
@@ -231,6 +232,8 @@ func (vq *VerificationQueue) validateVerificationCode(job VerificationJob) error
 		logging.GetLogger().Warn("failed to invalidate phone status cache", zap.Error(err))
 	}
 
+	EventBusInstance.PublishPhoneVerified(ctx, job.CPF, normalizedPhone)
+
 	return nil
 }
 
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prefeitura-rio/app-rmi/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.uber.org/zap"
+)
+
+// Write buffer reconciliation results, used to label the reconciled metric
+const (
+	WriteBufferReconcileInSync   = "in_sync"
+	WriteBufferReconcileRequeued = "requeued"
+)
+
+// WriteBufferReconcileResult summarizes a write buffer reconciliation run
+type WriteBufferReconcileResult struct {
+	Checked  int `json:"checked"`
+	InSync   int `json:"in_sync"`
+	Requeued int `json:"requeued"`
+}
+
+// reconcileWriteBuffers periodically re-enqueues self-declared writes stuck in the write buffer
+func (s *SyncService) reconcileWriteBuffers(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			result, err := s.ReconcileWriteBuffers(ctx)
+			cancel()
+			if err != nil {
+				s.logger.Error("failed to reconcile write buffers", zap.Error(err))
+			} else if result.Requeued > 0 {
+				s.logger.Info("reconciled write buffers",
+					zap.Int("checked", result.Checked),
+					zap.Int("requeued", result.Requeued))
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// ReconcileWriteBuffers checks the self-declared write buffer entries older than
+// WRITE_BUFFER_RECONCILE_STALE_AFTER against MongoDB. An entry whose field doesn't match the
+// stored document and has no job left in the sync queue or DLQ lost its sync job (e.g. the worker
+// crashed mid-flight), so a new job is queued for it. At most WRITE_BUFFER_RECONCILE_BATCH_SIZE
+// entries are checked per data type.
+func (s *SyncService) ReconcileWriteBuffers(ctx context.Context) (*WriteBufferReconcileResult, error) {
+	batchSize := config.AppConfig.WriteBufferReconcileBatchSize
+	if batchSize <= 0 {
+		batchSize = 500 // Default value
+	}
+	staleAfter := config.AppConfig.WriteBufferReconcileStaleAfter
+
+	result := &WriteBufferReconcileResult{}
+	for _, jobType := range SyncQueueNames {
+		field := getFieldNameFromJobType(jobType)
+		if field == "" {
+			continue
+		}
+
+		prefix := fmt.Sprintf("%s:write:", jobType)
+		keys, err := s.scanWriteBufferKeys(ctx, prefix, batchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list %s write buffers: %w", jobType, err)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		pending, err := s.pendingSyncJobKeys(ctx, jobType)
+		if err != nil {
+			return result, fmt.Errorf("failed to list %s sync jobs: %w", jobType, err)
+		}
+
+		for _, writeKey := range keys {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			cpf := strings.TrimPrefix(writeKey, prefix)
+			if pending[cpf] {
+				// The job is only backlogged (or waiting in the DLQ); queueing another duplicates it
+				continue
+			}
+			inSync, checked, err := s.reconcileWriteBuffer(ctx, jobType, field, cpf, writeKey, staleAfter)
+			if err != nil {
+				s.logger.Warn("failed to reconcile write buffer entry",
+					zap.String("write_key", writeKey), zap.Error(err))
+				continue
+			}
+			if !checked {
+				continue
+			}
+
+			result.Checked++
+			if inSync {
+				result.InSync++
+				observability.WriteBufferReconciledTotal.WithLabelValues(jobType, WriteBufferReconcileInSync).Inc()
+				continue
+			}
+			result.Requeued++
+			observability.WriteBufferReconciledTotal.WithLabelValues(jobType, WriteBufferReconcileRequeued).Inc()
+		}
+	}
+	return result, nil
+}
+
+// writeBufferScanCount is the COUNT hint of each SCAN call listing write buffer keys
+const writeBufferScanCount = 100
+
+// scanWriteBufferKeys lists up to limit write buffer keys with the given prefix, using SCAN so
+// Redis isn't blocked walking the whole keyspace
+func (s *SyncService) scanWriteBufferKeys(ctx context.Context, prefix string, limit int) ([]string, error) {
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := s.redis.Scan(ctx, cursor, prefix+"*", writeBufferScanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		if len(keys) >= limit {
+			return keys[:limit], nil
+		}
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+// pendingSyncJobKeys returns the keys with a job of jobType still in its sync queue or DLQ
+func (s *SyncService) pendingSyncJobKeys(ctx context.Context, jobType string) (map[string]bool, error) {
+	pending := make(map[string]bool)
+
+	queued, err := s.redis.LRange(ctx, fmt.Sprintf("sync:queue:%s", jobType), 0, -1).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	for _, data := range queued {
+		var job SyncJob
+		if json.Unmarshal([]byte(data), &job) == nil {
+			pending[job.Key] = true
+		}
+	}
+
+	failed, err := s.redis.LRange(ctx, fmt.Sprintf("sync:dlq:%s", jobType), 0, -1).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	for _, data := range failed {
+		var dlqJob DLQJob
+		if json.Unmarshal([]byte(data), &dlqJob) == nil {
+			pending[dlqJob.OriginalJob.Key] = true
+		}
+	}
+	return pending, nil
+}
+
+// reconcileWriteBuffer checks a single write buffer entry, queueing a sync job when its field
+// doesn't match MongoDB. It reports whether the entry was in sync and whether it was old enough
+// to be checked.
+func (s *SyncService) reconcileWriteBuffer(ctx context.Context, jobType, field, cpf, writeKey string, staleAfter time.Duration) (inSync bool, checked bool, err error) {
+	// The write buffer TTL is set on every write, so the remaining TTL tells the entry's age
+	ttl, err := s.redis.TTL(ctx, writeKey).Result()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get write buffer TTL: %w", err)
+	}
+	if ttl <= 0 || writeBufferTTL-ttl < staleAfter {
+		return false, false, nil
+	}
+
+	raw, err := s.redis.Get(ctx, writeKey).Result()
+	if err == redis.Nil {
+		// Synced (or expired) since it was listed
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to read write buffer: %w", err)
+	}
+	var buffered map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &buffered); err != nil {
+		return false, false, fmt.Errorf("failed to decode write buffer: %w", err)
+	}
+
+	stored, err := s.storedSelfDeclaredField(ctx, cpf, field)
+	if err != nil {
+		return false, false, err
+	}
+	bufferedValue, err := normalizeSelfDeclaredValue(buffered[field])
+	if err != nil {
+		return false, false, err
+	}
+	if reflect.DeepEqual(bufferedValue, stored) {
+		return true, true, nil
+	}
+
+	s.logger.Warn("write buffer entry differs from MongoDB, re-enqueueing sync job",
+		zap.String("type", jobType),
+		zap.String("cpf", cpf),
+		zap.String("field", field),
+		zap.Duration("age", writeBufferTTL-ttl))
+
+	job := SyncJob{
+		ID:         utils.GenerateUUID(),
+		Type:       jobType,
+		Key:        cpf,
+		Collection: config.AppConfig.SelfDeclaredCollection,
+		Data:       buffered,
+		Timestamp:  time.Now(),
+		RetryCount: 0,
+		MaxRetries: 3,
+	}
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to marshal sync job: %w", err)
+	}
+	if err := s.redis.LPush(ctx, fmt.Sprintf("sync:queue:%s", jobType), string(jobBytes)).Err(); err != nil {
+		return false, false, fmt.Errorf("failed to queue sync job: %w", err)
+	}
+	return false, true, nil
+}
+
+// storedSelfDeclaredField returns a field of the CPF's self-declared document normalized by
+// normalizeSelfDeclaredValue, or nil when the document or field doesn't exist
+func (s *SyncService) storedSelfDeclaredField(ctx context.Context, cpf, field string) (interface{}, error) {
+	var doc bson.Raw
+	err := s.mongo.Collection(config.AppConfig.SelfDeclaredCollection).FindOne(
+		ctx,
+		bson.M{"cpf": cpf},
+		options.FindOne().SetProjection(bson.M{"_id": 0, field: 1}),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get self-declared data: %w", err)
+	}
+
+	value, err := doc.LookupErr(field)
+	if err == bsoncore.ErrElementNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read self-declared field: %w", err)
+	}
+	return normalizeBSONValue(value)
+}
+
+// normalizeSelfDeclaredValue converts a buffered (JSON-decoded) field value the way the sync
+// worker stores it in MongoDB and back, so it compares equal to the stored field regardless of
+// how JSON and BSON represent numbers and dates
+func normalizeSelfDeclaredValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	doc, err := bson.Marshal(bson.M{"value": value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert buffered value: %w", err)
+	}
+	return normalizeBSONValue(bson.Raw(doc).Lookup("value"))
+}
+
+// normalizeBSONValue decodes a BSON value into plain JSON values: documents become maps, every
+// number a float64 and dates (BSON or RFC 3339 strings) UTC RFC 3339 strings with millisecond
+// precision, the precision of BSON dates
+func normalizeBSONValue(value bson.RawValue) (interface{}, error) {
+	doc, err := bson.Marshal(bson.D{{Key: "value", Value: value}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert value: %w", err)
+	}
+	decoder, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(doc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert value: %w", err)
+	}
+	decoder.DefaultDocumentM()
+	var decoded bson.M
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	data, err := json.Marshal(decoded["value"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert value: %w", err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to convert value: %w", err)
+	}
+	return normalizeDates(normalized), nil
+}
+
+// normalizeDates rewrites the RFC 3339 strings of a JSON value in UTC with millisecond precision
+func normalizeDates(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC().Truncate(time.Millisecond).Format(time.RFC3339Nano)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeDates(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeDates(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const reconcileTestCPF = "98765432155"
+
+func setupWriteBufferReconcileTest(t *testing.T) (*SyncService, func()) {
+	setupTestEnvironment()
+	if config.Redis == nil || config.MongoDB == nil {
+		t.Skip("Skipping write buffer reconcile tests: Redis or MongoDB not available")
+	}
+
+	origStaleAfter := config.AppConfig.WriteBufferReconcileStaleAfter
+	config.AppConfig.WriteBufferReconcileStaleAfter = 30 * time.Minute
+
+	ctx := context.Background()
+	cleanup := func() {
+		config.Redis.Del(ctx, "self_declared_email:write:"+reconcileTestCPF)
+		config.Redis.Del(ctx, "sync:queue:self_declared_email", "sync:dlq:self_declared_email")
+		config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).DeleteMany(ctx, bson.M{"cpf": reconcileTestCPF})
+	}
+	cleanup()
+
+	return NewSyncService(config.Redis, config.MongoDB, 0, logging.GetLogger()), func() {
+		cleanup()
+		config.AppConfig.WriteBufferReconcileStaleAfter = origStaleAfter
+	}
+}
+
+// bufferEmail writes an email write buffer entry that was written age ago
+func bufferEmail(t *testing.T, age time.Duration) {
+	t.Helper()
+	value := `{"cpf":"` + reconcileTestCPF + `","email":{"indicador":true,"principal":{"valor":"cidadao@example.com"}}}`
+	if err := config.Redis.Set(context.Background(), "self_declared_email:write:"+reconcileTestCPF, value, writeBufferTTL-age).Err(); err != nil {
+		t.Fatalf("failed to write buffer entry: %v", err)
+	}
+}
+
+func TestReconcileWriteBuffers_RequeuesMissingWrite(t *testing.T) {
+	service, cleanup := setupWriteBufferReconcileTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	bufferEmail(t, time.Hour)
+
+	result, err := service.ReconcileWriteBuffers(ctx)
+	if err != nil {
+		t.Fatalf("ReconcileWriteBuffers() error = %v", err)
+	}
+	if result.Requeued != 1 {
+		t.Errorf("requeued = %d, want 1", result.Requeued)
+	}
+	if depth, _ := config.Redis.LLen(ctx, "sync:queue:self_declared_email").Result(); depth != 1 {
+		t.Errorf("self_declared_email queue depth = %d, want 1", depth)
+	}
+}
+
+func TestReconcileWriteBuffers_InSync(t *testing.T) {
+	service, cleanup := setupWriteBufferReconcileTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	bufferEmail(t, time.Hour)
+	_, err := config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).InsertOne(ctx, bson.M{
+		"cpf":   reconcileTestCPF,
+		"email": bson.M{"indicador": true, "principal": bson.M{"valor": "cidadao@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert self-declared data: %v", err)
+	}
+
+	result, err := service.ReconcileWriteBuffers(ctx)
+	if err != nil {
+		t.Fatalf("ReconcileWriteBuffers() error = %v", err)
+	}
+	if result.InSync != 1 || result.Requeued != 0 {
+		t.Errorf("result = %+v, want 1 in sync and none requeued", result)
+	}
+}
+
+func TestReconcileWriteBuffers_SkipsRecentWrites(t *testing.T) {
+	service, cleanup := setupWriteBufferReconcileTest(t)
+	defer cleanup()
+
+	// Still within WRITE_BUFFER_RECONCILE_STALE_AFTER; its sync job may just not have run yet
+	bufferEmail(t, time.Minute)
+
+	result, err := service.ReconcileWriteBuffers(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileWriteBuffers() error = %v", err)
+	}
+	if result.Checked != 0 {
+		t.Errorf("checked = %d, want 0", result.Checked)
+	}
+}
+
+func TestReconcileWriteBuffers_SkipsPendingJobs(t *testing.T) {
+	service, cleanup := setupWriteBufferReconcileTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, queue := range []string{"sync:queue:self_declared_email", "sync:dlq:self_declared_email"} {
+		t.Run(queue, func(t *testing.T) {
+			config.Redis.Del(ctx, "sync:queue:self_declared_email", "sync:dlq:self_declared_email")
+			bufferEmail(t, time.Hour)
+
+			var entry interface{} = SyncJob{ID: "job-1", Type: "self_declared_email", Key: reconcileTestCPF}
+			if queue == "sync:dlq:self_declared_email" {
+				entry = DLQJob{OriginalJob: entry.(SyncJob), Error: "failed", FailedAt: time.Now()}
+			}
+			data, _ := json.Marshal(entry)
+			config.Redis.LPush(ctx, queue, string(data))
+
+			result, err := service.ReconcileWriteBuffers(ctx)
+			if err != nil {
+				t.Fatalf("ReconcileWriteBuffers() error = %v", err)
+			}
+			if result.Requeued != 0 {
+				t.Errorf("requeued = %d, want 0 for a job still in %s", result.Requeued, queue)
+			}
+			wantDepth := int64(1)
+			if queue == "sync:dlq:self_declared_email" {
+				wantDepth = 0
+			}
+			if depth, _ := config.Redis.LLen(ctx, "sync:queue:self_declared_email").Result(); depth != wantDepth {
+				t.Errorf("self_declared_email queue depth = %d, want %d", depth, wantDepth)
+			}
+		})
+	}
+}
+
+func TestNormalizeSelfDeclaredValue(t *testing.T) {
+	var buffered map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"numero":31,"updated_at":"2024-01-01T10:00:00.123-03:00"}`), &buffered); err != nil {
+		t.Fatal(err)
+	}
+	got, err := normalizeSelfDeclaredValue(buffered)
+	if err != nil {
+		t.Fatalf("normalizeSelfDeclaredValue() error = %v", err)
+	}
+
+	// The same field as read from MongoDB, with an int32 and a BSON date
+	doc, _ := bson.Marshal(bson.M{"endereco": bson.M{
+		"numero":     int32(31),
+		"updated_at": time.Date(2024, 1, 1, 13, 0, 0, 123000000, time.UTC),
+	}})
+	stored, err := normalizeBSONValue(bson.Raw(doc).Lookup("endereco"))
+	if err != nil {
+		t.Fatalf("normalizeBSONValue() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, stored) {
+		t.Errorf("normalized buffered value = %v, stored value = %v, want them equal", got, stored)
+	}
+}
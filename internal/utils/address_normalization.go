@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+// brazilianStateNames maps the lowercase, accent-free name of each state to its UF
+var brazilianStateNames = map[string]string{
+	"acre": "AC", "alagoas": "AL", "amapa": "AP", "amazonas": "AM", "bahia": "BA",
+	"ceara": "CE", "distrito federal": "DF", "espirito santo": "ES", "goias": "GO",
+	"maranhao": "MA", "mato grosso": "MT", "mato grosso do sul": "MS", "minas gerais": "MG",
+	"para": "PA", "paraiba": "PB", "parana": "PR", "pernambuco": "PE", "piaui": "PI",
+	"rio de janeiro": "RJ", "rio grande do norte": "RN", "rio grande do sul": "RS",
+	"rondonia": "RO", "roraima": "RR", "santa catarina": "SC", "sao paulo": "SP",
+	"sergipe": "SE", "tocantins": "TO",
+}
+
+// addressLowercaseWords stay lowercase when title-casing bairro and logradouro, unless they
+// start the name
+var addressLowercaseWords = map[string]bool{
+	"a": true, "as": true, "o": true, "os": true, "e": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true,
+	"na": true, "no": true, "nas": true, "nos": true, "em": true,
+}
+
+// cepPunctuation is stripped from CEPs such as "22000-000" or "22.000-000"
+var cepPunctuation = strings.NewReplacer("-", "", ".", "", " ", "")
+
+// NormalizeCEP strips the punctuation of a CEP down to its 8 digits. When that is not possible
+// the trimmed CEP is returned with the violation of rule address_cep.
+func NormalizeCEP(cep string) (string, error) {
+	cep = strings.TrimSpace(cep)
+	digits := cepPunctuation.Replace(cep)
+	if len(digits) != 8 || strings.ContainsFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) {
+		return cep, fmt.Errorf("invalid cep: %q is not an 8-digit CEP", cep)
+	}
+	return digits, nil
+}
+
+// NormalizeUF returns the uppercase UF of estado, given either as a UF in any case or as the
+// state name ("rj", "Rio de Janeiro"). When that is not possible the trimmed estado is
+// returned with the violation of rule address_uf.
+func NormalizeUF(estado string) (string, error) {
+	estado = strings.TrimSpace(estado)
+	if uf, ok := brazilianStateNames[strings.Join(strings.Fields(accentFolder.Replace(strings.ToLower(estado))), " ")]; ok {
+		return uf, nil
+	}
+	if err := AddressUFViolation(estado); err != nil {
+		return estado, err
+	}
+	return strings.ToUpper(estado), nil
+}
+
+// TitleCaseAddressName title-cases a bairro or logradouro ("RUA DAS FLORES" becomes "Rua das
+// Flores"), collapsing repeated spaces. Prepositions and articles stay lowercase and Roman
+// numerals ("Praça XV") uppercase.
+func TitleCaseAddressName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		switch {
+		case i > 0 && addressLowercaseWords[lower]:
+			words[i] = lower
+		case isRomanNumeral(lower):
+			words[i] = strings.ToUpper(word)
+		default:
+			first, size := utf8.DecodeRuneInString(lower)
+			words[i] = string(unicode.ToUpper(first)) + lower[size:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// isRomanNumeral reports whether a lowercase word is made only of the numerals i, v and x,
+// which no Portuguese address word is
+func isRomanNumeral(word string) bool {
+	return word != "" && strings.Trim(word, "ivx") == ""
+}
+
+// NormalizeSelfDeclaredAddress normalizes a self-declared address input in place: the CEP is
+// reduced to its 8 digits, the estado to its uppercase UF and bairro and logradouro are
+// title-cased. A CEP or estado that can't be normalized is kept as sent unless the address_cep
+// or address_uf rule is enforced, in which case the violation is returned.
+func NormalizeSelfDeclaredAddress(input *models.SelfDeclaredAddressInput) error {
+	cep, violation := NormalizeCEP(input.CEP)
+	if err := CheckValidationRule(config.ValidationRuleAddressCEP, violation); err != nil {
+		return err
+	}
+	uf, violation := NormalizeUF(input.Estado)
+	if err := CheckValidationRule(config.ValidationRuleAddressUF, violation); err != nil {
+		return err
+	}
+
+	input.CEP = cep
+	input.Estado = uf
+	input.Bairro = TitleCaseAddressName(input.Bairro)
+	input.Logradouro = TitleCaseAddressName(input.Logradouro)
+	return nil
+}
+
+// NormalizeStoredAddress returns a copy of a stored address with the normalization of
+// NormalizeSelfDeclaredAddress applied, so addresses saved before normalization compare equal
+// to an equivalent input. Missing fields stay nil.
+func NormalizeStoredAddress(principal *models.EnderecoPrincipal) *models.EnderecoPrincipal {
+	normalized := *principal
+	if principal.CEP != nil {
+		cep, _ := NormalizeCEP(*principal.CEP)
+		normalized.CEP = &cep
+	}
+	if principal.Estado != nil {
+		uf, _ := NormalizeUF(*principal.Estado)
+		normalized.Estado = &uf
+	}
+	if principal.Bairro != nil {
+		bairro := TitleCaseAddressName(*principal.Bairro)
+		normalized.Bairro = &bairro
+	}
+	if principal.Logradouro != nil {
+		logradouro := TitleCaseAddressName(*principal.Logradouro)
+		normalized.Logradouro = &logradouro
+	}
+	return &normalized
+}
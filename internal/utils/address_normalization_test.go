@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestNormalizeCEP(t *testing.T) {
+	for _, cep := range []string{"22000-000", "22000000", "22.000-000", " 22000 000 "} {
+		if got, err := NormalizeCEP(cep); err != nil || got != "22000000" {
+			t.Errorf("NormalizeCEP(%q) = %q, %v, want 22000000", cep, got, err)
+		}
+	}
+	for _, cep := range []string{"", "2200-000", "220000000", "22000-00A"} {
+		if _, err := NormalizeCEP(cep); err == nil {
+			t.Errorf("NormalizeCEP(%q) = nil error, want error", cep)
+		}
+	}
+}
+
+func TestNormalizeUF(t *testing.T) {
+	tests := map[string]string{
+		"RJ":               "RJ",
+		"rj":               "RJ",
+		" sp ":             "SP",
+		"Rio de Janeiro":   "RJ",
+		"rio  de janeiro":  "RJ",
+		"São Paulo":        "SP",
+		"PARÁ":             "PA",
+		"Distrito Federal": "DF",
+	}
+	for estado, want := range tests {
+		if got, err := NormalizeUF(estado); err != nil || got != want {
+			t.Errorf("NormalizeUF(%q) = %q, %v, want %s", estado, got, err, want)
+		}
+	}
+	for _, estado := range []string{"", "XX", "Rio", "Gotham"} {
+		if got, err := NormalizeUF(estado); err == nil {
+			t.Errorf("NormalizeUF(%q) = %q, want error", estado, got)
+		}
+	}
+}
+
+func TestTitleCaseAddressName(t *testing.T) {
+	tests := map[string]string{
+		"RUA DAS FLORES":           "Rua das Flores",
+		"rua  das   flores":        "Rua das Flores",
+		"Copacabana":               "Copacabana",
+		"praça xv de novembro":     "Praça XV de Novembro",
+		"AVENIDA DOM PEDRO II":     "Avenida Dom Pedro II",
+		"ilha do governador":       "Ilha do Governador",
+		"estrada dos bandeirantes": "Estrada dos Bandeirantes",
+		"":                         "",
+	}
+	for name, want := range tests {
+		if got := TitleCaseAddressName(name); got != want {
+			t.Errorf("TitleCaseAddressName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNormalizeSelfDeclaredAddress(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{
+		Bairro:     "BOTAFOGO",
+		CEP:        "22250-040",
+		Estado:     "rio de janeiro",
+		Logradouro: "rua voluntários da pátria",
+		Municipio:  "Rio de Janeiro",
+		Numero:     "10",
+	}
+	if err := NormalizeSelfDeclaredAddress(&input); err != nil {
+		t.Fatalf("NormalizeSelfDeclaredAddress() error = %v", err)
+	}
+	if input.CEP != "22250040" || input.Estado != "RJ" || input.Bairro != "Botafogo" || input.Logradouro != "Rua Voluntários da Pátria" {
+		t.Errorf("NormalizeSelfDeclaredAddress() = %+v", input)
+	}
+}
+
+func TestNormalizeSelfDeclaredAddress_InvalidCEP(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{CEP: "2225", Estado: "RJ"}
+
+	withValidationRuleMode(t, config.ValidationRuleAddressCEP, config.ValidationRuleModeShadow)
+	if err := NormalizeSelfDeclaredAddress(&input); err != nil || input.CEP != "2225" {
+		t.Errorf("NormalizeSelfDeclaredAddress() in shadow mode = %q, %v, want the CEP kept", input.CEP, err)
+	}
+
+	withValidationRuleMode(t, config.ValidationRuleAddressCEP, config.ValidationRuleModeEnforce)
+	if err := NormalizeSelfDeclaredAddress(&input); err == nil {
+		t.Error("NormalizeSelfDeclaredAddress() in enforce mode should reject the CEP")
+	}
+}
+
+func TestNormalizeSelfDeclaredAddress_InvalidUF(t *testing.T) {
+	input := models.SelfDeclaredAddressInput{CEP: "22250040", Estado: "Gotham"}
+
+	withValidationRuleMode(t, config.ValidationRuleAddressUF, config.ValidationRuleModeEnforce)
+	if err := NormalizeSelfDeclaredAddress(&input); err == nil {
+		t.Error("NormalizeSelfDeclaredAddress() in enforce mode should reject the estado")
+	}
+}
+
+func TestNormalizeStoredAddress(t *testing.T) {
+	cep, estado, bairro := "22250-040", "rj", "BOTAFOGO"
+	stored := &models.EnderecoPrincipal{CEP: &cep, Estado: &estado, Bairro: &bairro}
+
+	normalized := NormalizeStoredAddress(stored)
+	if *normalized.CEP != "22250040" || *normalized.Estado != "RJ" || *normalized.Bairro != "Botafogo" {
+		t.Errorf("NormalizeStoredAddress() = %+v", normalized)
+	}
+	if normalized.Logradouro != nil {
+		t.Error("NormalizeStoredAddress() should keep missing fields nil")
+	}
+	if *stored.CEP != "22250-040" {
+		t.Error("NormalizeStoredAddress() must not modify the stored address")
+	}
+}
@@ -3,13 +3,17 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -49,6 +53,7 @@ const (
 	AuditResourceEthnicity            = "ethnicity"
 	AuditResourceExhibitionName       = "exhibition_name"
 	AuditResourcePhoneVerification    = "phone_verification"
+	AuditResourceEmailVerification    = "email_verification"
 	AuditResourceUserConfig           = "user_config"
 	AuditResourceBetaGroup            = "beta_group"
 	AuditResourceBetaWhitelist        = "beta_whitelist"
@@ -58,6 +63,8 @@ const (
 	AuditResourceNotificationCategory = "notification_category"
 	AuditResourceMemory               = "memory"
 	AuditResourcePet                  = "pet"
+	AuditResourceLegalEntity          = "legal_entity"
+	AuditResourceOptInHistory         = "opt_in_history"
 )
 
 // AuditContext contains context information for audit logging
@@ -69,13 +76,27 @@ type AuditContext struct {
 	RequestID string
 }
 
+// Defaults used when the audit coalescing configuration is not loaded
+const (
+	defaultAuditBatchSize     = 100
+	defaultAuditFlushInterval = 100 * time.Millisecond
+)
+
 // AuditWorker manages asynchronous audit logging
 type AuditWorker struct {
-	auditChan chan AuditLog
-	workers   int
-	wg        sync.WaitGroup
-	ctx       context.Context
-	cancel    context.CancelFunc
+	auditChan     chan AuditLog
+	workers       int
+	batchSize     int                    // Logs per worker that trigger a flush (1 disables coalescing)
+	flushInterval time.Duration          // Maximum time a log waits in a partial batch
+	flush         func(batch []AuditLog) // Writes a batch; flushBatch outside tests
+	overflow      string                 // AUDIT_OVERFLOW_POLICY applied when the buffer is full
+	wg            sync.WaitGroup
+	ctx           context.Context
+	cancel        context.CancelFunc
+
+	// mu guards stopped so no log is sent on the closed channel during shutdown
+	mu      sync.RWMutex
+	stopped bool
 }
 
 var (
@@ -86,20 +107,48 @@ var (
 // InitAuditWorker initializes the audit worker
 func InitAuditWorker(workers int, bufferSize int) {
 	once.Do(func() {
-		ctx, cancel := context.WithCancel(context.Background())
-		auditWorker = &AuditWorker{
-			auditChan: make(chan AuditLog, bufferSize),
-			workers:   workers,
-			ctx:       ctx,
-			cancel:    cancel,
+		batchSize := config.AppConfig.AuditBatchSize
+		if batchSize <= 0 {
+			batchSize = defaultAuditBatchSize
 		}
+		if !config.AppConfig.AuditCoalescingEnabled {
+			batchSize = 1
+		}
+		flushInterval := config.AppConfig.AuditFlushInterval
+		if flushInterval <= 0 {
+			flushInterval = defaultAuditFlushInterval
+		}
+
+		auditWorker = newAuditWorker(workers, bufferSize, batchSize, flushInterval)
+		auditWorker.overflow = config.AppConfig.AuditOverflowPolicy
 		auditWorker.start()
 	})
 }
 
+// newAuditWorker creates an audit worker that writes batches with flushBatch
+func newAuditWorker(workers, bufferSize, batchSize int, flushInterval time.Duration) *AuditWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	aw := &AuditWorker{
+		auditChan:     make(chan AuditLog, bufferSize),
+		workers:       workers,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	aw.flush = aw.flushBatch
+	return aw
+}
+
 // start starts the audit worker pool
 func (aw *AuditWorker) start() {
-	aw.wg.Add(aw.workers)
+	aw.wg.Add(aw.workers + 1)
+
+	// Track buffer utilization and drain spilled logs
+	go func() {
+		defer aw.wg.Done()
+		aw.monitorBuffer()
+	}()
 
 	// Start workers using the new batched processing
 	for i := 0; i < aw.workers; i++ {
@@ -113,40 +162,42 @@ func (aw *AuditWorker) start() {
 
 	logging.GetLogger().Info("audit worker started with batched processing",
 		zap.Int("workers", aw.workers),
-		zap.Int("buffer_size", cap(aw.auditChan)))
+		zap.Int("buffer_size", cap(aw.auditChan)),
+		zap.Int("batch_size", aw.batchSize),
+		zap.Duration("flush_interval", aw.flushInterval),
+		zap.String("overflow_policy", aw.overflowPolicy()))
 }
 
-// processAuditLogs processes audit logs in batches for better performance
+// processAuditLogs coalesces audit logs into batches and flushes them on the size or time threshold
 func (aw *AuditWorker) processAuditLogs() {
-	batchTicker := time.NewTicker(100 * time.Millisecond) // Process batches every 100ms
-	monitorTicker := time.NewTicker(30 * time.Second)     // Monitor buffer every 30 seconds
+	batchTicker := time.NewTicker(aw.flushInterval)   // Flush partial batches
+	monitorTicker := time.NewTicker(30 * time.Second) // Monitor buffer every 30 seconds
 	defer batchTicker.Stop()
 	defer monitorTicker.Stop()
 
-	var batch []AuditLog
-	batchSize := 100 // Process in batches of 100
+	batch := make([]AuditLog, 0, aw.batchSize)
 
 	for {
 		select {
 		case auditLog, ok := <-aw.auditChan:
 			if !ok {
-				// Channel closed, process remaining batch and exit
+				// Channel closed (shutdown) and drained, flush remaining batch and exit
 				if len(batch) > 0 {
-					aw.flushBatch(batch)
+					aw.flush(batch)
 				}
 				return
 			}
 			batch = append(batch, auditLog)
 
-			// Process batch when it reaches batchSize items
-			if len(batch) >= batchSize {
-				aw.flushBatch(batch)
+			// Flush batch when it reaches batchSize items
+			if len(batch) >= aw.batchSize {
+				aw.flush(batch)
 				batch = batch[:0] // Reset slice but keep capacity
 			}
 		case <-batchTicker.C:
-			// Process any remaining items in batch
+			// Flush any remaining items in batch
 			if len(batch) > 0 {
-				aw.flushBatch(batch)
+				aw.flush(batch)
 				batch = batch[:0] // Reset slice but keep capacity
 			}
 		case <-monitorTicker.C:
@@ -179,25 +230,55 @@ func (aw *AuditWorker) flushBatch(batch []AuditLog) {
 	// Use W=0 write concern for audit logs to prevent blocking
 	opts := options.BulkWrite().SetOrdered(false) // Allow parallel execution
 
+	observability.RMIAuditBatchSize.Observe(float64(len(batch)))
 	result, err := config.MongoDB.Collection(config.AppConfig.AuditLogsCollection).BulkWrite(ctx, operations, opts)
 	if err != nil {
+		observability.RMIAuditFlushesTotal.WithLabelValues("error").Inc()
 		logger.Error("failed to insert audit log batch",
 			zap.Error(err),
 			zap.Int("batch_size", len(batch)))
 		return
 	}
+	observability.RMIAuditFlushesTotal.WithLabelValues("success").Inc()
 
 	logger.Info("audit log batch inserted successfully",
 		zap.Int64("inserted", result.InsertedCount),
 		zap.Int("batch_size", len(batch)))
 }
 
-// Stop stops the audit worker
+// Stop stops the audit worker, waiting until every buffered log has been flushed
 func (aw *AuditWorker) Stop() {
-	if aw != nil {
-		aw.cancel()
-		close(aw.auditChan)
-		aw.wg.Wait()
+	if aw == nil {
+		return
+	}
+
+	aw.mu.Lock()
+	if aw.stopped {
+		aw.mu.Unlock()
+		return
+	}
+	aw.stopped = true
+	aw.cancel()
+	close(aw.auditChan)
+	aw.mu.Unlock()
+
+	aw.wg.Wait()
+}
+
+// enqueue hands an audit log to the workers without blocking. It returns false when the buffer
+// is full or the worker is stopped.
+func (aw *AuditWorker) enqueue(auditLog AuditLog) bool {
+	aw.mu.RLock()
+	defer aw.mu.RUnlock()
+
+	if aw.stopped {
+		return false
+	}
+	select {
+	case aw.auditChan <- auditLog:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -233,17 +314,16 @@ func LogAuditEvent(ctx context.Context, auditCtx AuditContext, action, resource,
 		Metadata:   metadata,
 	}
 
-	// Try to send to audit channel, but don't block
-	select {
-	case auditWorker.auditChan <- auditLog:
+	// Hand the log to the workers, applying the overflow policy when the buffer is full
+	if auditWorker.submit(ctx, auditLog) {
 		return nil
-	default:
-		// Channel is full, fall back to synchronous logging
-		logging.GetLogger().Warn("audit channel full, falling back to synchronous logging",
-			zap.String("cpf", auditCtx.CPF),
-			zap.String("action", action))
-		return logAuditEventSync(ctx, auditCtx, action, resource, resourceID, oldValue, newValue, metadata)
 	}
+
+	// The worker is shutting down or the overflow policy failed, fall back to synchronous logging
+	logging.GetLogger().Warn("audit channel unavailable, falling back to synchronous logging",
+		zap.String("cpf", auditCtx.CPF),
+		zap.String("action", action))
+	return logAuditEventSync(ctx, auditCtx, action, resource, resourceID, oldValue, newValue, metadata)
 }
 
 // logAuditEventSync logs an audit event synchronously (fallback method)
@@ -328,6 +408,74 @@ func LogPhoneVerificationSuccess(ctx context.Context, auditCtx AuditContext, pho
 	return LogAuditEvent(ctx, auditCtx, AuditActionValidate, AuditResourcePhoneVerification, auditCtx.CPF, nil, map[string]string{"phone": phoneNumber, "status": "verified"}, metadata)
 }
 
+// LogPhonePrimaryChange logs the citizen making another of their verified phones the primary one
+func LogPhonePrimaryChange(ctx context.Context, auditCtx AuditContext, oldPhone, newPhone string) error {
+	metadata := map[string]string{
+		"operation": "phone_primary_change",
+		"phone":     newPhone,
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionUpdate, AuditResourcePhone, auditCtx.CPF,
+		map[string]string{"primary": oldPhone},
+		map[string]string{"primary": newPhone}, metadata)
+}
+
+// LogPhoneDemotion logs a verified self-declared phone sent back to pending verification after
+// repeated delivery failures
+func LogPhoneDemotion(ctx context.Context, auditCtx AuditContext, phoneNumber string, failures int) error {
+	metadata := map[string]string{
+		"operation": "phone_demotion",
+		"phone":     phoneNumber,
+		"failures":  strconv.Itoa(failures),
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionUpdate, AuditResourcePhoneVerification, auditCtx.CPF,
+		map[string]string{"phone": phoneNumber, "status": "verified"},
+		map[string]string{"phone": phoneNumber, "status": "pending"}, metadata)
+}
+
+// LogLegalEntityAccess logs an admin reading a full legal entity record, which includes the
+// partners' CPFs
+func LogLegalEntityAccess(ctx context.Context, auditCtx AuditContext, cnpj string, partners int) error {
+	metadata := map[string]string{
+		"operation": "legal_entity_access",
+		"cnpj":      cnpj,
+		"partners":  strconv.Itoa(partners),
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionRead, AuditResourceLegalEntity, cnpj, nil, nil, metadata)
+}
+
+// LogOptInHistoryAccess logs an admin querying the opt-in history of a CPF and/or phone number
+func LogOptInHistoryAccess(ctx context.Context, auditCtx AuditContext, cpf, phoneNumber, format string) error {
+	metadata := map[string]string{
+		"operation": "opt_in_history_access",
+		"format":    format,
+	}
+	resourceID := cpf
+	if cpf != "" {
+		metadata["cpf"] = cpf
+	}
+	if phoneNumber != "" {
+		metadata["phone"] = phoneNumber
+		if resourceID == "" {
+			resourceID = phoneNumber
+		}
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionRead, AuditResourceOptInHistory, resourceID, nil, nil, metadata)
+}
+
+// LogEmailVerificationSuccess logs a successful email verification
+func LogEmailVerificationSuccess(ctx context.Context, auditCtx AuditContext, email string) error {
+	metadata := map[string]string{
+		"operation": "email_verification_success",
+		"email":     email,
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionValidate, AuditResourceEmailVerification, auditCtx.CPF, nil, map[string]string{"email": email, "status": "verified"}, metadata)
+}
+
 // LogEmailUpdate logs an email update audit event
 func LogEmailUpdate(ctx context.Context, auditCtx AuditContext, oldEmail, newEmail interface{}) error {
 	metadata := map[string]string{
@@ -367,6 +515,89 @@ func LogUserConfigUpdate(ctx context.Context, auditCtx AuditContext, field strin
 	return LogAuditEvent(ctx, auditCtx, AuditActionUpdate, AuditResourceUserConfig, auditCtx.CPF, oldValue, newValue, metadata)
 }
 
+// LogPhoneBulkQuarantine logs a single audit event for a bulk phone quarantine, with the full
+// list of numbers attached
+func LogPhoneBulkQuarantine(ctx context.Context, auditCtx AuditContext, phoneNumbers []string, reason string, quarantineUntil time.Time) error {
+	metadata := map[string]string{
+		"operation": "bulk_quarantine",
+		"reason":    reason,
+		"count":     strconv.Itoa(len(phoneNumbers)),
+	}
+	newValue := map[string]interface{}{
+		"phone_numbers":    phoneNumbers,
+		"reason":           reason,
+		"quarantine_until": quarantineUntil,
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionCreate, AuditResourcePhoneQuarantine, "bulk", nil, newValue, metadata)
+}
+
+// LogPhoneQuarantineExpiry logs the automatic release of phone numbers whose quarantine expired
+func LogPhoneQuarantineExpiry(ctx context.Context, auditCtx AuditContext, phoneNumbers []string) error {
+	metadata := map[string]string{
+		"operation": "quarantine_expiry",
+		"count":     strconv.Itoa(len(phoneNumbers)),
+	}
+	newValue := map[string]interface{}{
+		"phone_numbers": phoneNumbers,
+		"status":        "released",
+	}
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionUpdate, AuditResourcePhoneQuarantine, "expiry_sweep", nil, newValue, metadata)
+}
+
+// LogPhoneReassignment logs an admin reassignment of a phone number. One event is recorded per
+// CPF involved, so the change shows up in the audit history of the previous owners as well as of
+// the new one; auditCtx.UserID identifies the admin.
+func LogPhoneReassignment(ctx context.Context, auditCtx AuditContext, phoneNumber string, previousCPFs []string, newCPF, reason string) error {
+	metadata := map[string]string{
+		"operation":     "phone_reassign",
+		"phone_number":  phoneNumber,
+		"reason":        reason,
+		"previous_cpfs": strings.Join(previousCPFs, ","),
+		"new_cpf":       newCPF,
+	}
+
+	var errs []error
+	for _, previousCPF := range previousCPFs {
+		cpfCtx := auditCtx
+		cpfCtx.CPF = previousCPF
+		oldValue := map[string]string{"phone_number": phoneNumber, "cpf": previousCPF, "status": "active"}
+		newValue := map[string]string{"phone_number": phoneNumber, "cpf": previousCPF, "status": "unbound"}
+		errs = append(errs, LogAuditEvent(ctx, cpfCtx, AuditActionUpdate, AuditResourcePhoneMapping, phoneNumber, oldValue, newValue, metadata))
+	}
+
+	cpfCtx := auditCtx
+	cpfCtx.CPF = newCPF
+	newValue := map[string]string{"phone_number": phoneNumber, "cpf": newCPF, "status": "active"}
+	errs = append(errs, LogAuditEvent(ctx, cpfCtx, AuditActionUpdate, AuditResourcePhoneMapping, phoneNumber, nil, newValue, metadata))
+
+	return errors.Join(errs...)
+}
+
+// LogVerificationExpiry logs an admin force-expiring outstanding verification codes. The
+// resource ID is the CPF or phone filter, or "all" when every code was expired.
+func LogVerificationExpiry(ctx context.Context, auditCtx AuditContext, cpf, phoneNumber string, phoneExpired, emailExpired int64) error {
+	metadata := map[string]string{
+		"operation":     "verification_expiry",
+		"phone_expired": strconv.FormatInt(phoneExpired, 10),
+		"email_expired": strconv.FormatInt(emailExpired, 10),
+	}
+	resourceID := "all"
+	if phoneNumber != "" {
+		metadata["phone"] = phoneNumber
+		resourceID = phoneNumber
+	}
+	if cpf != "" {
+		metadata["cpf"] = cpf
+		resourceID = cpf
+	}
+	auditCtx.CPF = cpf
+
+	return LogAuditEvent(ctx, auditCtx, AuditActionUpdate, AuditResourcePhoneVerification, resourceID, nil,
+		map[string]string{"status": "expired"}, metadata)
+}
+
 // GetAuditContextFromRequest extracts audit context from HTTP request
 func GetAuditContextFromRequest(cpf, userID, requestID string, ipAddress, userAgent string) AuditContext {
 	return AuditContext{
@@ -487,5 +718,8 @@ func (aw *AuditWorker) GetAuditWorkerStats() map[string]interface{} {
 		"buffer_capacity":  cap(aw.auditChan),
 		"buffer_usage":     len(aw.auditChan),
 		"buffer_available": cap(aw.auditChan) - len(aw.auditChan),
+		"batch_size":       aw.batchSize,
+		"flush_interval":   aw.flushInterval.String(),
+		"overflow_policy":  aw.overflowPolicy(),
 	}
 }
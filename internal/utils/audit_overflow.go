@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// auditSpillKey is the Redis list holding audit logs spilled by AUDIT_OVERFLOW_POLICY=spill-to-redis
+	auditSpillKey = "audit:spill"
+	// auditMonitorInterval is how often the buffer utilization is published and spilled logs drained
+	auditMonitorInterval = time.Second
+	// auditSpillDrainThreshold is the buffer utilization below which spilled logs are drained back
+	auditSpillDrainThreshold = 0.5
+)
+
+// overflowPolicy returns the configured overflow policy, sync when unset
+func (aw *AuditWorker) overflowPolicy() string {
+	if aw.overflow == "" {
+		return config.AuditOverflowPolicySync
+	}
+	return aw.overflow
+}
+
+// running reports whether the worker still accepts logs
+func (aw *AuditWorker) running() bool {
+	aw.mu.RLock()
+	defer aw.mu.RUnlock()
+	return !aw.stopped
+}
+
+// submit hands an audit log to the workers, applying the overflow policy when the buffer is full.
+// It returns false when the log must be written synchronously instead: the buffer is full under the
+// sync policy, the worker is stopped, the request context ended while blocked, or the spill to
+// Redis failed.
+func (aw *AuditWorker) submit(ctx context.Context, auditLog AuditLog) bool {
+	if aw.enqueue(auditLog) {
+		return true
+	}
+	if !aw.running() {
+		return false
+	}
+
+	switch aw.overflowPolicy() {
+	case config.AuditOverflowPolicyDrop:
+		observability.RMIAuditEventsDroppedTotal.WithLabelValues("buffer_full").Inc()
+		logging.GetLogger().Warn("audit buffer full, dropping audit log",
+			zap.String("action", auditLog.Action),
+			zap.String("resource", auditLog.Resource))
+		return true
+	case config.AuditOverflowPolicySpillToRedis:
+		if err := spillAuditLog(ctx, auditLog); err != nil {
+			logging.GetLogger().Warn("failed to spill audit log to Redis", zap.Error(err))
+			return false
+		}
+		return true
+	case config.AuditOverflowPolicyBlock:
+		return aw.enqueueWait(ctx, auditLog)
+	default:
+		return false
+	}
+}
+
+// enqueueWait hands an audit log to the workers, waiting for buffer space until ctx ends. It returns
+// false when ctx ended or the worker is stopped.
+func (aw *AuditWorker) enqueueWait(ctx context.Context, auditLog AuditLog) bool {
+	aw.mu.RLock()
+	defer aw.mu.RUnlock()
+
+	if aw.stopped {
+		return false
+	}
+	// Stop waits for the read lock, so the workers keep draining the buffer while this waits
+	select {
+	case aw.auditChan <- auditLog:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// spillAuditLog pushes an audit log to the Redis spill list
+func spillAuditLog(ctx context.Context, auditLog AuditLog) error {
+	if config.Redis == nil {
+		return fmt.Errorf("redis not available")
+	}
+
+	data, err := json.Marshal(auditLog)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit log: %w", err)
+	}
+	// Spill even when the request is canceled, so the log isn't lost with it
+	return config.Redis.LPush(context.WithoutCancel(ctx), auditSpillKey, data).Err()
+}
+
+// monitorBuffer publishes the buffer utilization and, with the spill-to-redis policy, drains
+// spilled logs back into the buffer, until the worker stops
+func (aw *AuditWorker) monitorBuffer() {
+	ticker := time.NewTicker(auditMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-aw.ctx.Done():
+			return
+		case <-ticker.C:
+			observability.RMIAuditBufferUtilization.Set(aw.bufferUtilization())
+			if aw.overflowPolicy() == config.AuditOverflowPolicySpillToRedis {
+				aw.drainSpilled(aw.ctx)
+			}
+		}
+	}
+}
+
+// bufferUtilization returns the fraction of the buffer in use
+func (aw *AuditWorker) bufferUtilization() float64 {
+	if cap(aw.auditChan) == 0 {
+		return 0
+	}
+	return float64(len(aw.auditChan)) / float64(cap(aw.auditChan))
+}
+
+// drainSpilled moves spilled logs back into the buffer, oldest first, while it is less than half
+// full. It returns the number of logs moved.
+func (aw *AuditWorker) drainSpilled(ctx context.Context) int {
+	if config.Redis == nil {
+		return 0
+	}
+
+	drained := 0
+	for aw.bufferUtilization() < auditSpillDrainThreshold {
+		data, err := config.Redis.RPop(ctx, auditSpillKey).Result()
+		if err != nil {
+			if err != redis.Nil && ctx.Err() == nil {
+				logging.GetLogger().Warn("failed to drain spilled audit logs", zap.Error(err))
+			}
+			break
+		}
+
+		var auditLog AuditLog
+		if err := json.Unmarshal([]byte(data), &auditLog); err != nil {
+			observability.RMIAuditEventsDroppedTotal.WithLabelValues("spill_corrupt").Inc()
+			logging.GetLogger().Error("failed to decode spilled audit log", zap.Error(err))
+			continue
+		}
+
+		if !aw.enqueue(auditLog) {
+			// The buffer filled up or the worker stopped meanwhile; keep the log for the next drain
+			// (it goes back at the head of the list, so it's retried after the logs spilled since)
+			if err := config.Redis.LPush(context.WithoutCancel(ctx), auditSpillKey, data).Err(); err != nil {
+				observability.RMIAuditEventsDroppedTotal.WithLabelValues("spill_requeue_failed").Inc()
+				logging.GetLogger().Error("failed to requeue spilled audit log", zap.Error(err))
+			}
+			break
+		}
+		drained++
+	}
+
+	if drained > 0 {
+		logging.GetLogger().Info("drained spilled audit logs", zap.Int("drained", drained))
+	}
+	return drained
+}
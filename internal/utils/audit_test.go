@@ -9,6 +9,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestAuditLog_Constants(t *testing.T) {
@@ -228,6 +230,172 @@ func TestAuditWorker_GetAuditWorkerStats_NotInitialized(t *testing.T) {
 	}
 }
 
+// newRecordingAuditWorker returns a worker whose flushes are recorded instead of written to MongoDB
+func newRecordingAuditWorker(batchSize int, flushInterval time.Duration) (*AuditWorker, func() []int) {
+	var mu sync.Mutex
+	var sizes []int
+	aw := newAuditWorker(1, 100, batchSize, flushInterval)
+	aw.flush = func(batch []AuditLog) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(batch))
+	}
+	return aw, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sizes...)
+	}
+}
+
+func TestAuditWorker_CoalescesBySize(t *testing.T) {
+	aw, flushed := newRecordingAuditWorker(3, time.Hour)
+	aw.start()
+
+	for i := 0; i < 7; i++ {
+		if !aw.enqueue(AuditLog{CPF: "12345678901"}) {
+			t.Fatalf("enqueue() = false for log %d", i)
+		}
+	}
+	// Stop flushes the partial batch left in the worker
+	aw.Stop()
+
+	sizes := flushed()
+	total := 0
+	for _, size := range sizes {
+		if size > 3 {
+			t.Errorf("flushed batch of %d logs, want at most 3", size)
+		}
+		total += size
+	}
+	if total != 7 {
+		t.Errorf("flushed %d logs in %v, want 7", total, sizes)
+	}
+}
+
+func TestAuditWorker_FlushesOnInterval(t *testing.T) {
+	aw, flushed := newRecordingAuditWorker(100, 10*time.Millisecond)
+	aw.start()
+	defer aw.Stop()
+
+	aw.enqueue(AuditLog{CPF: "12345678901"})
+	deadline := time.Now().Add(time.Second)
+	for len(flushed()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sizes := flushed(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("flushed batches = %v, want [1]", sizes)
+	}
+}
+
+func TestAuditWorker_EnqueueAfterStop(t *testing.T) {
+	aw, _ := newRecordingAuditWorker(10, time.Hour)
+	aw.start()
+	aw.Stop()
+	aw.Stop() // Stopping twice is a no-op
+
+	if aw.enqueue(AuditLog{CPF: "12345678901"}) {
+		t.Error("enqueue() after Stop() = true, want false")
+	}
+}
+
+// newFullAuditWorker returns a worker without running consumers whose single-slot buffer is full
+func newFullAuditWorker(t *testing.T, policy string) *AuditWorker {
+	aw := newAuditWorker(1, 1, 1, time.Hour)
+	aw.overflow = policy
+	if !aw.enqueue(AuditLog{CPF: "12345678901"}) {
+		t.Fatal("enqueue() = false on an empty buffer")
+	}
+	return aw
+}
+
+func TestAuditWorker_OverflowDrop(t *testing.T) {
+	aw := newFullAuditWorker(t, config.AuditOverflowPolicyDrop)
+	dropped := testutil.ToFloat64(observability.RMIAuditEventsDroppedTotal.WithLabelValues("buffer_full"))
+
+	if !aw.submit(context.Background(), AuditLog{CPF: "98765432100"}) {
+		t.Error("submit() = false, want the log dropped")
+	}
+	if got := testutil.ToFloat64(observability.RMIAuditEventsDroppedTotal.WithLabelValues("buffer_full")); got != dropped+1 {
+		t.Errorf("audit_events_dropped_total = %v, want %v", got, dropped+1)
+	}
+}
+
+func TestAuditWorker_OverflowSync(t *testing.T) {
+	for _, policy := range []string{"", config.AuditOverflowPolicySync} {
+		aw := newFullAuditWorker(t, policy)
+
+		// The log is handed back for a synchronous write right away instead of waiting
+		start := time.Now()
+		if aw.submit(context.Background(), AuditLog{CPF: "98765432100"}) {
+			t.Errorf("submit() with policy %q = true, want synchronous fallback", policy)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("submit() with policy %q waited %v for buffer space", policy, elapsed)
+		}
+	}
+}
+
+func TestAuditWorker_OverflowBlock(t *testing.T) {
+	aw := newFullAuditWorker(t, config.AuditOverflowPolicyBlock)
+
+	// The request context bounds the wait, after which the log is written synchronously
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if aw.submit(ctx, AuditLog{CPF: "98765432100"}) {
+		t.Error("submit() = true with a full buffer and an expired context")
+	}
+
+	// A slot freed while waiting lets the log in
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-aw.auditChan
+	}()
+	if !aw.submit(context.Background(), AuditLog{CPF: "98765432100"}) {
+		t.Error("submit() = false, want the log enqueued once the buffer had space")
+	}
+}
+
+func TestAuditWorker_OverflowAfterStop(t *testing.T) {
+	aw := newFullAuditWorker(t, config.AuditOverflowPolicyDrop)
+	aw.flush = func([]AuditLog) {}
+	aw.start()
+	aw.Stop()
+
+	if aw.submit(context.Background(), AuditLog{CPF: "98765432100"}) {
+		t.Error("submit() after Stop() = true, want synchronous fallback")
+	}
+}
+
+func TestAuditWorker_OverflowSpillToRedis(t *testing.T) {
+	if config.Redis == nil {
+		t.Skip("Skipping spill test: Redis not available")
+	}
+	ctx := context.Background()
+	config.Redis.Del(ctx, auditSpillKey)
+	defer config.Redis.Del(ctx, auditSpillKey)
+
+	aw := newFullAuditWorker(t, config.AuditOverflowPolicySpillToRedis)
+	if !aw.submit(ctx, AuditLog{CPF: "98765432100", Action: AuditActionRead}) {
+		t.Fatal("submit() = false, want the log spilled")
+	}
+	if n, _ := config.Redis.LLen(ctx, auditSpillKey).Result(); n != 1 {
+		t.Fatalf("spill list length = %d, want 1", n)
+	}
+
+	// Nothing is drained while the buffer is full
+	if drained := aw.drainSpilled(ctx); drained != 0 {
+		t.Errorf("drainSpilled() = %d with a full buffer, want 0", drained)
+	}
+
+	<-aw.auditChan
+	if drained := aw.drainSpilled(ctx); drained != 1 {
+		t.Fatalf("drainSpilled() = %d, want 1", drained)
+	}
+	if log := <-aw.auditChan; log.CPF != "98765432100" || log.Action != AuditActionRead {
+		t.Errorf("drained log = %+v, want the spilled one", log)
+	}
+}
+
 func TestGetAuditWorker_BeforeInit(t *testing.T) {
 	// Reset global instance
 	once = sync.Once{}
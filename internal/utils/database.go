@@ -8,6 +8,7 @@ import (
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -191,7 +192,19 @@ func CreatePhoneVerification(ctx context.Context, data PhoneVerificationData) er
 		ExpiresAt:   data.ExpiresAt,
 	}
 
-	_, err := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection).InsertOne(ctx, verification)
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
+	_, err := collection.InsertOne(ctx, verification)
+
+	// A leftover record for the same cpf/phone (e.g. a delete that didn't complete) violates the
+	// unique cpf_1_phone_number_1 index; replace it instead of blocking the user
+	for attempt := 1; err != nil && mongo.IsDuplicateKeyError(err) && attempt <= config.AppConfig.PhoneVerificationDuplicateRetries; attempt++ {
+		logger.Warn("stale phone verification record found, replacing it", zap.Int("attempt", attempt))
+		if _, delErr := collection.DeleteOne(ctx, bson.M{"cpf": data.CPF, "phone_number": data.PhoneNumber}); delErr != nil {
+			logger.Error("failed to delete stale phone verification record", zap.Error(delErr))
+			break
+		}
+		_, err = collection.InsertOne(ctx, verification)
+	}
 	if err != nil {
 		logger.Error("failed to create phone verification record", zap.Error(err))
 		return fmt.Errorf("failed to create verification record: %w", err)
@@ -215,12 +228,7 @@ func UpdateSelfDeclaredPendingPhone(ctx context.Context, cpf string, data PhoneV
 		},
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"telefone_pending": pendingPhone,
-			"updated_at":       time.Now(),
-		},
-	}
+	update := TimestampedUpdate(bson.M{"telefone_pending": pendingPhone}, time.Now())
 
 	_, err := config.MongoDB.Collection(config.AppConfig.SelfDeclaredCollection).UpdateOne(
 		ctx,
@@ -369,22 +377,87 @@ type BulkUpdateRequest struct {
 	Upsert bool
 }
 
-// GetCollectionWithReadPreference returns a collection with a specific read preference for load distribution
-func GetCollectionWithReadPreference(collectionName string, readPref *readpref.ReadPref) *mongo.Collection {
-	collection := config.MongoDB.Collection(collectionName)
+// readAfterWriteKey marks contexts whose reads must observe the request's own writes
+type readAfterWriteKey struct{}
 
-	// Note: Read preferences are typically set at the client level
-	// This function provides a centralized way to get collections with proper context
-	// The actual read preference is determined by the MongoDB client configuration
-	return collection
+// WithReadAfterWrite marks ctx so reads made with it go to the primary. Use it after a write
+// when the same request reads the written data back, since secondaries may still lag behind.
+func WithReadAfterWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readAfterWriteKey{}, true)
+}
+
+// IsReadAfterWrite reports whether ctx was marked with WithReadAfterWrite
+func IsReadAfterWrite(ctx context.Context) bool {
+	marked, _ := ctx.Value(readAfterWriteKey{}).(bool)
+	return marked
+}
+
+// ConfiguredReadPreference returns the MONGODB_READ_PREFERENCES override of a collection, or
+// nil when it uses the client default (nearest)
+func ConfiguredReadPreference(collectionName string) *readpref.ReadPref {
+	if config.AppConfig == nil {
+		return nil
+	}
+	modeName, ok := config.AppConfig.MongoReadPreferences[collectionName]
+	if !ok {
+		return nil
+	}
+	mode, err := readpref.ModeFromString(modeName)
+	if err != nil {
+		return nil
+	}
+	pref, err := readpref.New(mode)
+	if err != nil {
+		return nil
+	}
+	return pref
+}
+
+// ReadPreferenceFor returns the read preference for reading a collection in ctx: primary for
+// read-after-write contexts, else the configured override, else nil for the client default
+func ReadPreferenceFor(ctx context.Context, collectionName string) *readpref.ReadPref {
+	if IsReadAfterWrite(ctx) {
+		return readpref.Primary()
+	}
+	return ConfiguredReadPreference(collectionName)
+}
+
+// GetCollectionWithReadPreference returns a collection reading with readPref. A nil readPref
+// uses the collection's MONGODB_READ_PREFERENCES override, falling back to the client default.
+func GetCollectionWithReadPreference(collectionName string, readPref *readpref.ReadPref) *mongo.Collection {
+	if readPref == nil {
+		readPref = ConfiguredReadPreference(collectionName)
+	}
+	if readPref == nil {
+		return config.MongoDB.Collection(collectionName)
+	}
+	return config.MongoDB.Collection(collectionName, options.Collection().SetReadPreference(readPref))
 }
 
 // GetCollectionForReadOperation returns a collection optimized for read operations
 func GetCollectionForReadOperation(collectionName string) *mongo.Collection {
 	// For read operations, we want to use secondary nodes when possible
 	// This helps distribute load away from the primary
-	// The readPreference=nearest is set at the client level
-	return config.MongoDB.Collection(collectionName)
+	// The readPreference=nearest is set at the client level unless the collection overrides it
+	return GetCollectionWithReadPreference(collectionName, nil)
+}
+
+// GetCollectionForContextRead returns a collection for reading in ctx, honoring read-after-write
+// marks and the collection's configured read preference
+func GetCollectionForContextRead(ctx context.Context, collectionName string) *mongo.Collection {
+	readPref := ReadPreferenceFor(ctx, collectionName)
+	RecordMongoRead(collectionName, readPref)
+	return GetCollectionWithReadPreference(collectionName, readPref)
+}
+
+// RecordMongoRead counts a read of a collection routed with readPref (nil for the client default)
+// in mongodb_reads_total
+func RecordMongoRead(collectionName string, readPref *readpref.ReadPref) {
+	label := "default"
+	if readPref != nil {
+		label = readPref.Mode().String()
+	}
+	observability.MongoDBReads.WithLabelValues(collectionName, label).Inc()
 }
 
 // GetCollectionForWriteOperation returns a collection optimized for write operations
@@ -403,8 +476,8 @@ func ExecuteReadWithLoadDistribution(ctx context.Context, collection string, ope
 
 	// Use a collection that can read from secondary nodes
 	// This helps distribute load away from the primary
-	// The readPreference=nearest is enforced at the client level
-	coll := GetCollectionForReadOperation(collection)
+	// The readPreference=nearest is enforced at the client level unless overridden
+	coll := GetCollectionForContextRead(ctx, collection)
 
 	// Execute the read operation
 	if err := operation(coll); err != nil {
@@ -11,12 +11,16 @@ import (
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
 	"github.com/prefeitura-rio/app-rmi/internal/redisclient"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // setupDatabaseUtilsTest initializes MongoDB for database utility testing
@@ -660,6 +664,85 @@ func TestPhoneVerificationData_Structure(t *testing.T) {
 	}
 }
 
+// setupPhoneVerificationCollection points the verification collection at a scratch collection
+// carrying the same unique cpf/phone_number index as production
+func setupPhoneVerificationCollection(t *testing.T) (*mongo.Collection, func()) {
+	_, cleanup := setupDatabaseUtilsTest(t)
+
+	ctx := context.Background()
+	originalCollection := config.AppConfig.PhoneVerificationCollection
+	originalRetries := config.AppConfig.PhoneVerificationDuplicateRetries
+	config.AppConfig.PhoneVerificationCollection = "test_phone_verifications"
+
+	collection := config.MongoDB.Collection(config.AppConfig.PhoneVerificationCollection)
+	_ = collection.Drop(ctx)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cpf", Value: 1}, {Key: "phone_number", Value: 1}},
+		Options: options.Index().SetName("cpf_1_phone_number_1").SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	return collection, func() {
+		_ = collection.Drop(ctx)
+		config.AppConfig.PhoneVerificationCollection = originalCollection
+		config.AppConfig.PhoneVerificationDuplicateRetries = originalRetries
+		cleanup()
+	}
+}
+
+func TestCreatePhoneVerification_ReplacesStaleRecord(t *testing.T) {
+	collection, cleanup := setupPhoneVerificationCollection(t)
+	defer cleanup()
+
+	config.AppConfig.PhoneVerificationDuplicateRetries = 1
+	ctx := context.Background()
+
+	// Leftover record from a previous attempt that was never deleted
+	_, err := collection.InsertOne(ctx, bson.M{
+		"cpf":          "12345678901",
+		"phone_number": "5521999999999",
+		"code":         "111111",
+		"expires_at":   time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	err = CreatePhoneVerification(ctx, PhoneVerificationData{
+		CPF:         "12345678901",
+		PhoneNumber: "5521999999999",
+		Code:        "222222",
+		ExpiresAt:   time.Now().Add(5 * time.Minute),
+	})
+	require.NoError(t, err, "duplicate key should be resolved by replacing the stale record")
+
+	count, err := collection.CountDocuments(ctx, bson.M{"cpf": "12345678901", "phone_number": "5521999999999"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var result bson.M
+	require.NoError(t, collection.FindOne(ctx, bson.M{"cpf": "12345678901"}).Decode(&result))
+	assert.Equal(t, "222222", result["code"])
+}
+
+func TestCreatePhoneVerification_DuplicateRetryDisabled(t *testing.T) {
+	collection, cleanup := setupPhoneVerificationCollection(t)
+	defer cleanup()
+
+	config.AppConfig.PhoneVerificationDuplicateRetries = 0
+	ctx := context.Background()
+
+	_, err := collection.InsertOne(ctx, bson.M{"cpf": "12345678901", "phone_number": "5521999999999", "code": "111111"})
+	require.NoError(t, err)
+
+	err = CreatePhoneVerification(ctx, PhoneVerificationData{
+		CPF:         "12345678901",
+		PhoneNumber: "5521999999999",
+		Code:        "222222",
+		ExpiresAt:   time.Now().Add(5 * time.Minute),
+	})
+	require.Error(t, err)
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+}
+
 func TestDatabaseOperation_Structure(t *testing.T) {
 	called := false
 
@@ -1757,3 +1840,66 @@ func TestInvalidateCitizenCache_EmptyCPF(t *testing.T) {
 	err := InvalidateCitizenCache(ctx, "")
 	require.NoError(t, err, "InvalidateCitizenCache should handle empty CPF gracefully")
 }
+
+func TestExecuteReadWithLoadDistribution_RoutesByReadPreference(t *testing.T) {
+	if config.MongoDB == nil {
+		t.Skip("Skipping read preference test: MongoDB not available")
+	}
+	originalConfig := config.AppConfig
+	defer func() { config.AppConfig = originalConfig }()
+	cfg := config.Config{}
+	if originalConfig != nil {
+		cfg = *originalConfig
+	}
+	cfg.MongoReadPreferences = map[string]string{"test_read_pref_offload": "secondaryPreferred"}
+	config.AppConfig = &cfg
+
+	ctx := context.Background()
+	for _, name := range []string{"test_read_pref_default", "test_read_pref_offload"} {
+		collection := config.MongoDB.Collection(name)
+		_, err := collection.InsertOne(ctx, bson.M{"_id": "doc", "version": 2})
+		require.NoError(t, err)
+		defer collection.Drop(ctx)
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		collection string
+		want       string
+	}{
+		{"client default", ctx, "test_read_pref_default", "default"},
+		{"configured override", ctx, "test_read_pref_offload", "secondaryPreferred"},
+		{"read-after-write", WithReadAfterWrite(ctx), "test_read_pref_default", "primary"},
+		{"read-after-write wins over override", WithReadAfterWrite(ctx), "test_read_pref_offload", "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := observability.MongoDBReads.WithLabelValues(tt.collection, tt.want)
+			before := testutil.ToFloat64(reads)
+
+			var doc bson.M
+			err := ExecuteReadWithLoadDistribution(tt.ctx, tt.collection, func(coll *mongo.Collection) error {
+				return coll.FindOne(tt.ctx, bson.M{"_id": "doc"}).Decode(&doc)
+			})
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, doc["version"])
+			assert.Equal(t, before+1, testutil.ToFloat64(reads), "read should be routed with %s", tt.want)
+		})
+	}
+}
+
+func TestConfiguredReadPreference(t *testing.T) {
+	originalConfig := config.AppConfig
+	defer func() { config.AppConfig = originalConfig }()
+	config.AppConfig = &config.Config{
+		MongoReadPreferences: map[string]string{"offload": "secondaryPreferred"},
+	}
+
+	pref := ConfiguredReadPreference("offload")
+	require.NotNil(t, pref)
+	assert.Equal(t, readpref.SecondaryPreferredMode, pref.Mode())
+	assert.Nil(t, ConfiguredReadPreference("other"))
+	assert.False(t, IsReadAfterWrite(context.Background()))
+	assert.True(t, IsReadAfterWrite(WithReadAfterWrite(context.Background())))
+}
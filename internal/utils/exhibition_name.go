@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+// Exhibition name rules reported by ValidateExhibitionName
+const (
+	ExhibitionNameRuleTooShort          = "too_short"
+	ExhibitionNameRuleTooLong           = "too_long"
+	ExhibitionNameRuleInvalidCharacters = "invalid_characters"
+	ExhibitionNameRuleBlockedTerm       = "blocked_term"
+)
+
+// ExhibitionNameViolation is the exhibition name rule an input broke
+type ExhibitionNameViolation struct {
+	Rule    string
+	Message string
+}
+
+func (v *ExhibitionNameViolation) Error() string {
+	return v.Message
+}
+
+// accentFolder maps accented Latin letters to their base letter, so blocklisted terms match
+// regardless of accents
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// ValidateExhibitionName checks a trimmed exhibition name against the length, character and
+// blocklist rules, returning the first rule it breaks or nil. Names may only hold letters,
+// spaces, apostrophes, hyphens and periods.
+func ValidateExhibitionName(name string) *ExhibitionNameViolation {
+	minLength := 2
+	var blocklist []string
+	if config.AppConfig != nil {
+		if config.AppConfig.ExhibitionNameMinLength > 0 {
+			minLength = config.AppConfig.ExhibitionNameMinLength
+		}
+		blocklist = config.AppConfig.ExhibitionNameBlocklist
+	}
+
+	length := utf8.RuneCountInString(name)
+	if length < minLength {
+		return &ExhibitionNameViolation{
+			Rule:    ExhibitionNameRuleTooShort,
+			Message: fmt.Sprintf("exhibition name too short (minimum %d characters)", minLength),
+		}
+	}
+	if length > config.MaxExhibitionNameLength {
+		return &ExhibitionNameViolation{
+			Rule:    ExhibitionNameRuleTooLong,
+			Message: fmt.Sprintf("exhibition name too long (maximum %d characters)", config.MaxExhibitionNameLength),
+		}
+	}
+
+	for _, r := range name {
+		if !isExhibitionNameRune(r) {
+			return &ExhibitionNameViolation{
+				Rule:    ExhibitionNameRuleInvalidCharacters,
+				Message: fmt.Sprintf("exhibition name contains a disallowed character: %q", r),
+			}
+		}
+	}
+
+	words := exhibitionNameWords(name)
+	for _, term := range blocklist {
+		if containsWordSequence(words, exhibitionNameWords(term)) {
+			return &ExhibitionNameViolation{
+				Rule:    ExhibitionNameRuleBlockedTerm,
+				Message: "exhibition name contains a disallowed term",
+			}
+		}
+	}
+
+	return nil
+}
+
+// isExhibitionNameRune reports whether r may appear in an exhibition name
+func isExhibitionNameRune(r rune) bool {
+	switch {
+	case unicode.IsLetter(r), unicode.Is(unicode.Mn, r):
+		return true
+	case r == ' ', r == '\'', r == '-', r == '.':
+		return true
+	default:
+		return false
+	}
+}
+
+// exhibitionNameWords splits s into lowercase words without accents
+func exhibitionNameWords(s string) []string {
+	folded := accentFolder.Replace(strings.ToLower(s))
+	return strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// containsWordSequence reports whether needle appears as consecutive words of haystack
+func containsWordSequence(haystack, needle []string) bool {
+	if len(needle) == 0 {
+		return false
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j, word := range needle {
+			if haystack[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
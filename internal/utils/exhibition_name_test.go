@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+)
+
+func TestValidateExhibitionName(t *testing.T) {
+	if config.AppConfig == nil {
+		config.AppConfig = &config.Config{}
+	}
+	minLength, blocklist := config.AppConfig.ExhibitionNameMinLength, config.AppConfig.ExhibitionNameBlocklist
+	config.AppConfig.ExhibitionNameMinLength = 3
+	config.AppConfig.ExhibitionNameBlocklist = []string{"palavrao", "Termo Feio"}
+	defer func() {
+		config.AppConfig.ExhibitionNameMinLength = minLength
+		config.AppConfig.ExhibitionNameBlocklist = blocklist
+	}()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantRule string
+	}{
+		{"plain name", "Maria da Silva", ""},
+		{"accents and punctuation", "Ana-Lúcia D'Ávila Jr.", ""},
+		{"minimum length in characters", "Ção", ""},
+		{"maximum length in characters", strings.Repeat("é", 255), ""},
+		{"too short", "Jo", ExhibitionNameRuleTooShort},
+		{"too long", strings.Repeat("a", 256), ExhibitionNameRuleTooLong},
+		{"digits", "Maria 2", ExhibitionNameRuleInvalidCharacters},
+		{"emoji", "Maria 🙂", ExhibitionNameRuleInvalidCharacters},
+		{"markup", "<b>Maria</b>", ExhibitionNameRuleInvalidCharacters},
+		{"blocked term", "Maria Palavrão", ExhibitionNameRuleBlockedTerm},
+		{"blocked phrase across separators", "termo-feio Silva", ExhibitionNameRuleBlockedTerm},
+		{"blocked term inside a word", "Palavraozinho", ""},
+		{"partial blocked phrase", "Termo Silva", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := ValidateExhibitionName(tt.input)
+			if tt.wantRule == "" {
+				if violation != nil {
+					t.Errorf("ValidateExhibitionName(%q) = %v, want nil", tt.input, violation)
+				}
+				return
+			}
+			if violation == nil || violation.Rule != tt.wantRule {
+				t.Errorf("ValidateExhibitionName(%q) = %v, want rule %q", tt.input, violation, tt.wantRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+)
+
+// Names of the outbound clients, used as the client label of the transport metrics
+const (
+	ClientMCP      = "mcp"
+	ClientWebhook  = "webhook"
+	ClientWhatsApp = "whatsapp"
+	ClientJWKS     = "jwks"
+
+	ClientAvatarStorage   = "avatar_storage"
+	ClientAvatarMigration = "avatar_migration"
+)
+
+// defaultConfig holds the OUTBOUND_HTTP_* defaults, used when the configuration isn't loaded
+var defaultConfig = config.Config{
+	OutboundHTTPTimeout:               30 * time.Second,
+	OutboundHTTPDialTimeout:           5 * time.Second,
+	OutboundHTTPTLSHandshakeTimeout:   10 * time.Second,
+	OutboundHTTPResponseHeaderTimeout: 20 * time.Second,
+	OutboundHTTPIdleConnTimeout:       90 * time.Second,
+	OutboundHTTPMaxIdleConns:          100,
+	OutboundHTTPMaxIdleConnsPerHost:   20,
+	OutboundHTTPMaxConnsPerHost:       100,
+}
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+
+	publicTransport     *http.Transport
+	publicTransportOnce sync.Once
+)
+
+// nonPublicNetworks lists the ranges that IsPublicIP rejects beyond loopback, private, link-local,
+// multicast and unspecified addresses
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved
+}
+
+// Client returns an HTTP client for outbound calls made by the named caller. Every client shares
+// one pooled transport built from the OUTBOUND_HTTP_* configuration, so connections to the same
+// host are reused across callers instead of each caller dialing its own; the name only labels
+// the outbound_http_* metrics.
+func Client(name string) *http.Client {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = NewTransport(currentConfig())
+	})
+	return New(name, sharedTransport, currentConfig().OutboundHTTPTimeout)
+}
+
+// PublicClient returns an HTTP client for fetching URLs taken from stored or user-supplied data. It
+// only connects to public addresses, checked on every dial so DNS answers and redirects can't reach
+// internal services (SSRF), and doesn't go through proxies, which would dial on its behalf.
+func PublicClient(name string) *http.Client {
+	publicTransportOnce.Do(func() {
+		publicTransport = NewTransport(currentConfig())
+		publicTransport.Proxy = nil
+		dialer := &net.Dialer{
+			Timeout:   currentConfig().OutboundHTTPDialTimeout,
+			KeepAlive: 30 * time.Second,
+			Control:   rejectNonPublicAddress,
+		}
+		publicTransport.DialContext = dialer.DialContext
+	})
+	return New(name, publicTransport, currentConfig().OutboundHTTPTimeout)
+}
+
+// IsPublicIP reports whether ip is a globally routable address, i.e. not loopback, private,
+// link-local, multicast, unspecified or reserved
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// rejectNonPublicAddress is a dialer control function refusing connections to non-public IPs
+func rejectNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("connection to non-public address %s refused", host)
+	}
+	return nil
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// New returns a client that sends requests through transport, recording the outbound_http_*
+// metrics under name
+func New(name string, transport http.RoundTripper, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &instrumentedTransport{name: name, base: transport},
+	}
+}
+
+// NewTransport creates a pooled transport with the timeouts and limits of cfg. Proxies are taken
+// from the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY variables.
+func NewTransport(cfg *config.Config) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.OutboundHTTPDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+		TLSHandshakeTimeout:   cfg.OutboundHTTPTLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.OutboundHTTPResponseHeaderTimeout,
+		IdleConnTimeout:       cfg.OutboundHTTPIdleConnTimeout,
+		MaxIdleConns:          cfg.OutboundHTTPMaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.OutboundHTTPMaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.OutboundHTTPMaxConnsPerHost,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+}
+
+// currentConfig returns the loaded configuration, or the defaults when it isn't loaded or has no
+// outbound settings (e.g. in tests)
+func currentConfig() *config.Config {
+	if config.AppConfig == nil || config.AppConfig.OutboundHTTPTimeout == 0 {
+		return &defaultConfig
+	}
+	return config.AppConfig
+}
+
+// instrumentedTransport records in-flight requests and connection reuse of a named client
+type instrumentedTransport struct {
+	name string
+	base http.RoundTripper
+}
+
+// RoundTrip counts the request as in flight until its response headers arrive
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	inFlight := observability.OutboundHTTPRequestsInFlight.WithLabelValues(t.name)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			observability.OutboundHTTPConnections.WithLabelValues(t.name, strconv.FormatBool(info.Reused)).Inc()
+		},
+	}
+	return t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewTransport(t *testing.T) {
+	cfg := defaultConfig
+	cfg.OutboundHTTPMaxIdleConnsPerHost = 7
+	cfg.OutboundHTTPMaxConnsPerHost = 0
+
+	transport := NewTransport(&cfg)
+	if transport.MaxIdleConnsPerHost != 7 || transport.MaxConnsPerHost != 0 || transport.MaxIdleConns != 100 {
+		t.Errorf("transport limits = %d/%d/%d, want 7/0/100", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.MaxIdleConns)
+	}
+	if transport.ResponseHeaderTimeout != 20*time.Second || transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("transport timeouts = %v/%v, want 20s/90s", transport.ResponseHeaderTimeout, transport.IdleConnTimeout)
+	}
+	if transport.Proxy == nil {
+		t.Error("transport.Proxy = nil, want proxies from the environment")
+	}
+}
+
+func TestClient_UsesConfiguredTimeout(t *testing.T) {
+	original := config.AppConfig
+	defer func() { config.AppConfig = original }()
+
+	config.AppConfig = nil
+	if got := Client(ClientMCP).Timeout; got != 30*time.Second {
+		t.Errorf("Timeout without configuration = %v, want the 30s default", got)
+	}
+
+	cfg := defaultConfig
+	cfg.OutboundHTTPTimeout = 3 * time.Second
+	config.AppConfig = &cfg
+	if got := Client(ClientMCP).Timeout; got != 3*time.Second {
+		t.Errorf("Timeout = %v, want 3s from OUTBOUND_HTTP_TIMEOUT", got)
+	}
+}
+
+func TestInstrumentedTransport_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := testutil.ToFloat64(observability.OutboundHTTPRequestsInFlight.WithLabelValues("test")); got != 1 {
+			t.Errorf("in-flight requests during the request = %v, want 1", got)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := New("test", NewTransport(&defaultConfig), time.Second)
+	reused := observability.OutboundHTTPConnections.WithLabelValues("test", "true")
+	dialed := observability.OutboundHTTPConnections.WithLabelValues("test", "false")
+	reusedBefore, dialedBefore := testutil.ToFloat64(reused), testutil.ToFloat64(dialed)
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		// Draining and closing the body returns the connection to the pool
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	if got := testutil.ToFloat64(dialed) - dialedBefore; got != 1 {
+		t.Errorf("dialed connections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(reused) - reusedBefore; got != 2 {
+		t.Errorf("reused connections = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(observability.OutboundHTTPRequestsInFlight.WithLabelValues("test")); got != 0 {
+		t.Errorf("in-flight requests after the responses = %v, want 0", got)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.0.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fc00::1", false},
+		{"fe80::1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestPublicClient_RefusesNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	resp, err := PublicClient("test").Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Get() to a loopback address succeeded, want it refused")
+	}
+}
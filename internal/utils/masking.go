@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PIIMasker implements models.FieldMasker using the masking helpers below
+type PIIMasker struct{}
+
+// MaskCPF masks a CPF keeping only its check digits (e.g., "45049725810" -> "***.***.***-10")
+func (PIIMasker) MaskCPF(cpf string) string { return MaskCPFDocument(cpf) }
+
+// MaskPhone masks all but the last 4 digits of a phone number
+func (PIIMasker) MaskPhone(phone string) string { return MaskPhone(phone) }
+
+// MaskEmail masks the local part of an email address
+func (PIIMasker) MaskEmail(email string) string { return MaskEmail(email) }
+
+// MaskCPF masks a CPF for privacy (e.g., "45049725810" -> "450***25810")
+func MaskCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[:3] + "***" + cpf[6:]
+}
+
+// MaskCPFDocument masks a CPF in document format keeping only the check digits (e.g., "45049725810" -> "***.***.***-10")
+func MaskCPFDocument(cpf string) string {
+	digits := onlyDigits(cpf)
+	if len(digits) != 11 {
+		return "***.***.***-**"
+	}
+	return "***.***.***-" + digits[9:]
+}
+
+// MaskEmail masks the local part of an email address with a fixed-width mask, so its length
+// isn't revealed (e.g., "joao.silva@example.com" -> "j***@example.com")
+func MaskEmail(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+
+	local := []rune(email[:at])
+	return string(local[:1]) + "***" + email[at:]
+}
+
+// MaskPhone masks all but the last 4 digits of a phone number (e.g., "5521987654321" -> "*********4321")
+func MaskPhone(phone string) string {
+	digits := onlyDigits(phone)
+	if len(digits) <= 4 {
+		return digits
+	}
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskCPF(t *testing.T) {
+	tests := []struct {
+		name string
+		cpf  string
+		want string
+	}{
+		{
+			name: "Valid 11-digit CPF",
+			cpf:  "12345678909",
+			want: "123***78909",
+		},
+		{
+			name: "Another valid CPF",
+			cpf:  "98765432100",
+			want: "987***32100",
+		},
+		{
+			name: "Invalid CPF - too short",
+			cpf:  "123456789",
+			want: "123456789",
+		},
+		{
+			name: "Invalid CPF - too long",
+			cpf:  "123456789012",
+			want: "123456789012",
+		},
+		{
+			name: "Empty string",
+			cpf:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MaskCPF(tt.cpf)
+			assert.Equal(t, tt.want, result, "MaskCPF(%q) should return %q", tt.cpf, tt.want)
+		})
+	}
+}
+
+func TestMaskCPF_MasksCorrectDigits(t *testing.T) {
+	cpf := "12345678909"
+	result := MaskCPF(cpf)
+
+	// Should preserve first 3 and last 5 digits
+	assert.Equal(t, "123", result[:3], "First 3 digits should be preserved")
+	assert.Equal(t, "***", result[3:6], "Middle 3 digits should be masked")
+	assert.Equal(t, "78909", result[6:], "Last 5 digits should be preserved")
+}
+
+func TestMaskCPFDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		cpf  string
+		want string
+	}{
+		{name: "Plain CPF", cpf: "12345678909", want: "***.***.***-09"},
+		{name: "Formatted CPF", cpf: "123.456.789-09", want: "***.***.***-09"},
+		{name: "Invalid length", cpf: "123456789", want: "***.***.***-**"},
+		{name: "Empty string", cpf: "", want: "***.***.***-**"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskCPFDocument(tt.cpf), "MaskCPFDocument(%q) should return %q", tt.cpf, tt.want)
+		})
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{name: "Regular email", email: "joao.silva@example.com", want: "j***@example.com"},
+		{name: "Single char local part", email: "j@example.com", want: "j***@example.com"},
+		{name: "Two char local part", email: "jo@example.com", want: "j***@example.com"},
+		{name: "Long local part", email: "joao.carlos.da.silva.santos@example.com", want: "j***@example.com"},
+		{name: "Missing at sign", email: "not-an-email", want: "not-an-email"},
+		{name: "Empty string", email: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskEmail(tt.email), "MaskEmail(%q) should return %q", tt.email, tt.want)
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+	}{
+		{name: "Full phone with DDI", phone: "5521987654321", want: "*********4321"},
+		{name: "Formatted phone", phone: "+55 (21) 98765-4321", want: "*********4321"},
+		{name: "Short number", phone: "1234", want: "1234"},
+		{name: "Empty string", phone: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskPhone(tt.phone), "MaskPhone(%q) should return %q", tt.phone, tt.want)
+		})
+	}
+}
+
+func TestPIIMasker(t *testing.T) {
+	var m PIIMasker
+
+	assert.Equal(t, "***.***.***-09", m.MaskCPF("12345678909"))
+	assert.Equal(t, "*****4321", m.MaskPhone("987654321"))
+	assert.Equal(t, "a***@example.com", m.MaskEmail("ana@example.com"))
+}
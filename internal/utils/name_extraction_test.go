@@ -158,54 +158,3 @@ func TestMaskName_EdgeCases(t *testing.T) {
 		})
 	}
 }
-
-func TestMaskCPF(t *testing.T) {
-	tests := []struct {
-		name string
-		cpf  string
-		want string
-	}{
-		{
-			name: "Valid 11-digit CPF",
-			cpf:  "12345678909",
-			want: "123***78909",
-		},
-		{
-			name: "Another valid CPF",
-			cpf:  "98765432100",
-			want: "987***32100",
-		},
-		{
-			name: "Invalid CPF - too short",
-			cpf:  "123456789",
-			want: "123456789",
-		},
-		{
-			name: "Invalid CPF - too long",
-			cpf:  "123456789012",
-			want: "123456789012",
-		},
-		{
-			name: "Empty string",
-			cpf:  "",
-			want: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := MaskCPF(tt.cpf)
-			assert.Equal(t, tt.want, result, "MaskCPF(%q) should return %q", tt.cpf, tt.want)
-		})
-	}
-}
-
-func TestMaskCPF_MasksCorrectDigits(t *testing.T) {
-	cpf := "12345678909"
-	result := MaskCPF(cpf)
-
-	// Should preserve first 3 and last 5 digits
-	assert.Equal(t, "123", result[:3], "First 3 digits should be preserved")
-	assert.Equal(t, "***", result[3:6], "Middle 3 digits should be masked")
-	assert.Equal(t, "78909", result[6:], "Last 5 digits should be preserved")
-}
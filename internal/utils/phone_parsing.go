@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/nyaruka/phonenumbers"
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 )
 
 // PhoneComponents represents the parsed components of a phone number
@@ -103,6 +104,80 @@ func ParsePhoneNumber(phoneString string) (*PhoneComponents, error) {
 	return components, nil
 }
 
+// validBrazilianDDDs lists the area codes (DDD) assigned by Anatel
+var validBrazilianDDDs = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// nonDigitRegex matches any non-digit character
+var nonDigitRegex = regexp.MustCompile(`[^0-9]`)
+
+// NormalizePhone validates and normalizes self-declared phone components.
+// Brazilian numbers (DDI 55) must use a valid DDD and be either a 9-digit mobile starting
+// with 9 or an 8-digit landline starting with 2-5. Other countries are validated with
+// libphonenumber. The returned components are digits-only and Full holds the E.164 form.
+func NormalizePhone(ddi, ddd, valor string) (*PhoneComponents, error) {
+	ddi = strings.TrimLeft(nonDigitRegex.ReplaceAllString(ddi, ""), "0")
+	ddd = strings.TrimLeft(nonDigitRegex.ReplaceAllString(ddd, ""), "0")
+	valor = nonDigitRegex.ReplaceAllString(valor, "")
+
+	if ddi == "" {
+		return nil, fmt.Errorf("invalid phone number: missing country code")
+	}
+	if valor == "" {
+		return nil, fmt.Errorf("invalid phone number: missing number")
+	}
+
+	if ddi == "55" {
+		if err := CheckValidationRule(config.ValidationRulePhoneDDD, phoneDDDViolation(ddd)); err != nil {
+			return nil, err
+		}
+
+		switch len(valor) {
+		case 9:
+			if valor[0] != '9' {
+				return nil, fmt.Errorf("invalid phone number: 9-digit mobile numbers must start with 9")
+			}
+		case 8:
+			if valor[0] < '2' || valor[0] > '5' {
+				return nil, fmt.Errorf("invalid phone number: 8-digit landline numbers must start with 2-5")
+			}
+		default:
+			return nil, fmt.Errorf("invalid phone number: expected 8 (landline) or 9 (mobile) digits, got %d", len(valor))
+		}
+
+		return &PhoneComponents{
+			DDI:   ddi,
+			DDD:   ddd,
+			Valor: valor,
+			Full:  "+" + ddi + ddd + valor,
+		}, nil
+	}
+
+	num, err := phonenumbers.Parse("+"+ddi+ddd+valor, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	if !phonenumbers.IsValidNumber(num) {
+		return nil, fmt.Errorf("invalid phone number: not a valid number for country code %s", ddi)
+	}
+
+	return &PhoneComponents{
+		DDI:   ddi,
+		DDD:   ddd,
+		Valor: valor,
+		Full:  phonenumbers.Format(num, phonenumbers.E164),
+	}, nil
+}
+
 // ValidatePhoneFormat validates if a phone string is in a valid format
 func ValidatePhoneFormat(phoneString string) error {
 	// Basic format validation
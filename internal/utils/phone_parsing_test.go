@@ -363,3 +363,44 @@ func TestPhoneComponents_Structure(t *testing.T) {
 	assert.Equal(t, "987654321", components.Valor)
 	assert.Equal(t, "+5521987654321", components.Full)
 }
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		ddi      string
+		ddd      string
+		valor    string
+		wantFull string
+		wantErr  bool
+	}{
+		{name: "Valid mobile", ddi: "55", ddd: "21", valor: "987654321", wantFull: "+5521987654321"},
+		{name: "Valid landline", ddi: "55", ddd: "21", valor: "25551234", wantFull: "+552125551234"},
+		{name: "Formatting is stripped", ddi: "+55", ddd: "(021)", valor: "98765-4321", wantFull: "+5521987654321"},
+		{name: "Mobile without leading 9", ddi: "55", ddd: "21", valor: "887654321", wantErr: true},
+		{name: "Legacy 8-digit mobile", ddi: "55", ddd: "21", valor: "87654321", wantErr: true},
+		{name: "Unknown DDD", ddi: "55", ddd: "20", valor: "987654321", wantErr: true},
+		{name: "Missing DDD", ddi: "55", ddd: "", valor: "987654321", wantErr: true},
+		{name: "Too many digits", ddi: "55", ddd: "21", valor: "9876543210", wantErr: true},
+		{name: "Missing DDI", ddi: "", ddd: "21", valor: "987654321", wantErr: true},
+		{name: "Missing number", ddi: "55", ddd: "21", valor: "", wantErr: true},
+		{name: "Valid US number", ddi: "1", ddd: "202", valor: "4561111", wantFull: "+12024561111"},
+		{name: "Invalid international number", ddi: "1", ddd: "000", valor: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizePhone(tt.ddi, tt.ddd, tt.valor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantFull, result.Full)
+			assert.Equal(t, tt.wantFull[1:], FormatPhoneForStorage(result.DDI, result.DDD, result.Valor),
+				"storage format should match E.164 without the plus sign")
+		})
+	}
+}
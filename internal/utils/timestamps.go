@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TimestampedUpdate builds the upsert of a write of set made at writtenAt, following the
+// models.Timestamps policy: updated_at is set to writtenAt on every write and created_at only
+// when the upsert inserts the document, so it never moves once stored. A created_at in set is
+// kept for the insert, and an updated_at in set is replaced. set is not modified.
+func TimestampedUpdate(set bson.M, writtenAt time.Time) bson.M {
+	fields := make(bson.M, len(set)+1)
+	for field, value := range set {
+		fields[field] = value
+	}
+
+	createdAt := writtenAt
+	if ts, ok := DocumentTimestamp(fields["created_at"]); ok && !ts.IsZero() {
+		createdAt = ts
+	}
+	delete(fields, "created_at")
+	fields["updated_at"] = writtenAt
+
+	return bson.M{
+		"$set":         fields,
+		"$setOnInsert": bson.M{"created_at": createdAt},
+	}
+}
+
+// DocumentTimestamp reads an updated_at/created_at value, stored either as a BSON date or as an
+// RFC 3339 string by writers that converted the document through JSON
+func DocumentTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		return v.Time(), true
+	case time.Time:
+		return v, true
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimestampedUpdate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	set := bson.M{"email_pending": "a@b.com", "updated_at": "2020-01-01T00:00:00Z"}
+
+	update := TimestampedUpdate(set, now)
+	fields := update["$set"].(bson.M)
+	if fields["email_pending"] != "a@b.com" || fields["updated_at"] != now {
+		t.Errorf("$set = %v, want the fields with updated_at %v", fields, now)
+	}
+	if _, ok := fields["created_at"]; ok {
+		t.Errorf("$set = %v, must not change created_at", fields)
+	}
+	if got := update["$setOnInsert"].(bson.M)["created_at"]; got != now {
+		t.Errorf("$setOnInsert created_at = %v, want %v", got, now)
+	}
+	if set["updated_at"] != "2020-01-01T00:00:00Z" {
+		t.Error("TimestampedUpdate() modified the given set")
+	}
+
+	// A created_at in the document is kept for the insert only
+	created := now.Add(-time.Hour)
+	update = TimestampedUpdate(bson.M{"created_at": created.Format(time.RFC3339Nano)}, now)
+	if _, ok := update["$set"].(bson.M)["created_at"]; ok {
+		t.Error("$set must not change created_at")
+	}
+	if got := update["$setOnInsert"].(bson.M)["created_at"]; got != created {
+		t.Errorf("$setOnInsert created_at = %v, want %v", got, created)
+	}
+
+	// A zero created_at (document never stamped) is replaced by the write time
+	update = TimestampedUpdate(bson.M{"created_at": "0001-01-01T00:00:00Z"}, now)
+	if got := update["$setOnInsert"].(bson.M)["created_at"]; got != now {
+		t.Errorf("$setOnInsert created_at = %v, want %v", got, now)
+	}
+}
+
+func TestDocumentTimestamp(t *testing.T) {
+	want := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, value := range []interface{}{want, primitive.NewDateTimeFromTime(want), "2024-06-01T12:00:00Z"} {
+		if got, ok := DocumentTimestamp(value); !ok || !got.Equal(want) {
+			t.Errorf("DocumentTimestamp(%v) = %v, %v, want %v", value, got, ok, want)
+		}
+	}
+	for _, value := range []interface{}{nil, "yesterday", 42} {
+		if _, ok := DocumentTimestamp(value); ok {
+			t.Errorf("DocumentTimestamp(%v) should fail", value)
+		}
+	}
+}
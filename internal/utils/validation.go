@@ -5,12 +5,14 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/prefeitura-rio/app-rmi/internal/models"
 )
 
 // ValidationError represents a validation error with field and message
 type ValidationError struct {
 	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message"`
 }
 
@@ -71,6 +73,11 @@ func ValidateAddress(input models.SelfDeclaredAddressInput) *ValidationResult {
 	if input.Estado != "" && len(input.Estado) != 2 {
 		result.AddError("estado", "Estado must be exactly 2 characters")
 	}
+	if len(input.Estado) == 2 {
+		if err := CheckValidationRule(config.ValidationRuleAddressUF, AddressUFViolation(input.Estado)); err != nil {
+			result.AddError("estado", err.Error())
+		}
+	}
 
 	// Length validations
 	if input.Logradouro != "" && len(input.Logradouro) > 200 {
@@ -149,6 +156,9 @@ func ValidateEmail(input models.SelfDeclaredEmailInput) *ValidationResult {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(input.Valor) {
 		result.AddError("valor", "Invalid email format")
+	} else if err := CheckValidationRule(config.ValidationRuleEmailDomain, EmailDomainViolation(input.Valor)); err != nil {
+		// Stricter domain check, only rejecting when the rule is enforced
+		result.AddError("valor", err.Error())
 	}
 
 	// Length validation
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+// Validation error codes returned to clients
+const (
+	ValidationCodeRequired      = "required"
+	ValidationCodeTooShort      = "too_short"
+	ValidationCodeTooLong       = "too_long"
+	ValidationCodeInvalidLength = "invalid_length"
+	ValidationCodeInvalidFormat = "invalid_format"
+	ValidationCodeInvalidValue  = "invalid_value"
+	ValidationCodeInvalidType   = "invalid_type"
+	ValidationCodeInvalidJSON   = "invalid_json"
+	ValidationCodeEmptyBody     = "empty_body"
+)
+
+// bodyField is used as field name for errors that apply to the whole request body
+const bodyField = "body"
+
+// ParseValidationError converts gin binding / validator errors and citizen data errors into structured validation errors
+// so handlers can return machine-parseable bodies instead of raw Go error strings
+func ParseValidationError(err error) []ValidationError {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		result := make([]ValidationError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			result = append(result, fieldErrorToValidationError(fe))
+		}
+		return result
+	}
+
+	var citizenErr *models.CitizenValidationError
+	if errors.As(err, &citizenErr) {
+		result := make([]ValidationError, 0, len(citizenErr.Errors))
+		for _, fe := range citizenErr.Errors {
+			result = append(result, ValidationError{Field: fe.Field, Code: fe.Code, Message: fe.Message})
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = bodyField
+		}
+		return []ValidationError{{
+			Field:   field,
+			Code:    ValidationCodeInvalidType,
+			Message: fmt.Sprintf("expected %s but got %s", typeErr.Type.String(), typeErr.Value),
+		}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []ValidationError{{
+			Field:   bodyField,
+			Code:    ValidationCodeInvalidJSON,
+			Message: "request body is not valid JSON",
+		}}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return []ValidationError{{
+			Field:   bodyField,
+			Code:    ValidationCodeEmptyBody,
+			Message: "request body is empty",
+		}}
+	}
+
+	return []ValidationError{{
+		Field:   bodyField,
+		Code:    ValidationCodeInvalidValue,
+		Message: "request body is invalid",
+	}}
+}
+
+// fieldErrorToValidationError maps a single validator field error to a ValidationError
+func fieldErrorToValidationError(fe validator.FieldError) ValidationError {
+	field := toSnakeCase(fe.Field())
+
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return ValidationError{Field: field, Code: ValidationCodeRequired, Message: fmt.Sprintf("%s is required", field)}
+	case "min":
+		return ValidationError{Field: field, Code: ValidationCodeTooShort, Message: fmt.Sprintf("%s must be at least %s characters", field, fe.Param())}
+	case "max":
+		return ValidationError{Field: field, Code: ValidationCodeTooLong, Message: fmt.Sprintf("%s must be at most %s characters", field, fe.Param())}
+	case "len":
+		return ValidationError{Field: field, Code: ValidationCodeInvalidLength, Message: fmt.Sprintf("%s must be exactly %s characters", field, fe.Param())}
+	case "email", "numeric", "number", "alphanum", "url", "uuid":
+		return ValidationError{Field: field, Code: ValidationCodeInvalidFormat, Message: fmt.Sprintf("%s has an invalid format", field)}
+	case "oneof":
+		return ValidationError{Field: field, Code: ValidationCodeInvalidValue, Message: fmt.Sprintf("%s must be one of: %s", field, fe.Param())}
+	default:
+		return ValidationError{Field: field, Code: ValidationCodeInvalidValue, Message: fmt.Sprintf("%s failed validation '%s'", field, fe.Tag())}
+	}
+}
+
+// toSnakeCase converts a Go struct field name (e.g. TipoLogradouro, DDI) to its snake_case JSON form
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+type validationErrorsTestInput struct {
+	Nome           string `json:"nome" binding:"required,min=3,max=10"`
+	Sigla          string `json:"sigla" binding:"omitempty,len=2"`
+	TipoLogradouro string `json:"tipo_logradouro" binding:"omitempty,oneof=rua avenida"`
+	Idade          int    `json:"idade"`
+}
+
+func TestParseValidationError(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		target    interface{}
+		wantField string
+		wantCode  string
+		wantCount int
+	}{
+		{
+			name:      "missing required field",
+			body:      `{"valor": ""}`,
+			target:    &models.SelfDeclaredEmailInput{},
+			wantField: "valor",
+			wantCode:  ValidationCodeRequired,
+			wantCount: 1,
+		},
+		{
+			name:      "conditionally required field",
+			body:      `{"ddi": "55", "valor": "999999999"}`,
+			target:    &models.SelfDeclaredPhoneInput{},
+			wantField: "ddd",
+			wantCode:  ValidationCodeRequired,
+			wantCount: 1,
+		},
+		{
+			name:      "multiple missing fields",
+			body:      `{}`,
+			target:    &models.SelfDeclaredPhoneInput{},
+			wantField: "ddi",
+			wantCode:  ValidationCodeRequired,
+			wantCount: 2,
+		},
+		{
+			name:      "wrong type",
+			body:      `{"valor": 123}`,
+			target:    &models.SelfDeclaredEmailInput{},
+			wantField: "valor",
+			wantCode:  ValidationCodeInvalidType,
+			wantCount: 1,
+		},
+		{
+			name:      "wrong type on int field",
+			body:      `{"nome": "Maria", "idade": "dez"}`,
+			target:    &validationErrorsTestInput{},
+			wantField: "idade",
+			wantCode:  ValidationCodeInvalidType,
+			wantCount: 1,
+		},
+		{
+			name:      "too short",
+			body:      `{"nome": "Al"}`,
+			target:    &validationErrorsTestInput{},
+			wantField: "nome",
+			wantCode:  ValidationCodeTooShort,
+			wantCount: 1,
+		},
+		{
+			name:      "too long",
+			body:      `{"nome": "Maria Aparecida"}`,
+			target:    &validationErrorsTestInput{},
+			wantField: "nome",
+			wantCode:  ValidationCodeTooLong,
+			wantCount: 1,
+		},
+		{
+			name:      "exact length",
+			body:      `{"nome": "Maria", "sigla": "RIO"}`,
+			target:    &validationErrorsTestInput{},
+			wantField: "sigla",
+			wantCode:  ValidationCodeInvalidLength,
+			wantCount: 1,
+		},
+		{
+			name:      "value not allowed",
+			body:      `{"nome": "Maria", "tipo_logradouro": "beco"}`,
+			target:    &validationErrorsTestInput{},
+			wantField: "tipo_logradouro",
+			wantCode:  ValidationCodeInvalidValue,
+			wantCount: 1,
+		},
+		{
+			name:      "malformed JSON",
+			body:      `{"valor": `,
+			target:    &models.SelfDeclaredEmailInput{},
+			wantField: "body",
+			wantCode:  ValidationCodeInvalidJSON,
+			wantCount: 1,
+		},
+		{
+			name:      "invalid JSON syntax",
+			body:      `{valor}`,
+			target:    &models.SelfDeclaredEmailInput{},
+			wantField: "body",
+			wantCode:  ValidationCodeInvalidJSON,
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.JSON.BindBody([]byte(tt.body), tt.target)
+			if err == nil {
+				t.Fatal("BindBody() expected an error")
+			}
+
+			got := ParseValidationError(err)
+			if len(got) != tt.wantCount {
+				t.Fatalf("ParseValidationError() returned %d errors, want %d: %+v", len(got), tt.wantCount, got)
+			}
+			if got[0].Field != tt.wantField {
+				t.Errorf("ParseValidationError() field = %q, want %q", got[0].Field, tt.wantField)
+			}
+			if got[0].Code != tt.wantCode {
+				t.Errorf("ParseValidationError() code = %q, want %q", got[0].Code, tt.wantCode)
+			}
+			if got[0].Message == "" {
+				t.Error("ParseValidationError() message should not be empty")
+			}
+		})
+	}
+}
+
+func TestParseValidationError_NilAndUnknown(t *testing.T) {
+	if got := ParseValidationError(nil); got != nil {
+		t.Errorf("ParseValidationError(nil) = %v, want nil", got)
+	}
+
+	got := ParseValidationError(errors.New("something odd"))
+	if len(got) != 1 || got[0].Field != "body" || got[0].Code != ValidationCodeInvalidValue {
+		t.Errorf("ParseValidationError(unknown) = %+v, want generic body error", got)
+	}
+	if got[0].Message == "something odd" {
+		t.Error("ParseValidationError() should not leak raw error messages")
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Valor":          "valor",
+		"DDI":            "ddi",
+		"CEP":            "cep",
+		"TipoLogradouro": "tipo_logradouro",
+		"UserID":         "user_id",
+		"HTTPServer":     "http_server",
+	}
+
+	for input, want := range tests {
+		if got := toSnakeCase(input); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
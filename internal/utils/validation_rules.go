@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/logging"
+	"github.com/prefeitura-rio/app-rmi/internal/observability"
+	"go.uber.org/zap"
+)
+
+// brazilianUFs lists the valid values for a self-declared address estado
+var brazilianUFs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// domainLabelRegex matches a single hostname label (letters, digits and inner hyphens)
+var domainLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// CheckValidationRule applies the configured mode of rule to a violation found by one of the
+// rule checks below. It returns the violation only when the rule is enforced; in shadow mode the
+// violation is logged and counted so its impact can be measured before enforcing it.
+func CheckValidationRule(rule string, violation error) error {
+	if violation == nil {
+		return nil
+	}
+
+	mode := config.ValidationRuleMode(rule)
+	switch mode {
+	case config.ValidationRuleModeShadow:
+		observability.RMIValidationRuleViolationsTotal.WithLabelValues(rule, mode).Inc()
+		logging.GetLogger().Info("validation rule would reject input (shadow mode)",
+			zap.String("rule", rule),
+			zap.String("violation", violation.Error()))
+		return nil
+	case config.ValidationRuleModeEnforce:
+		observability.RMIValidationRuleViolationsTotal.WithLabelValues(rule, mode).Inc()
+		return violation
+	default:
+		return nil
+	}
+}
+
+// AddressUFViolation reports why estado is not a Brazilian UF (rule address_uf)
+func AddressUFViolation(estado string) error {
+	if !brazilianUFs[strings.ToUpper(strings.TrimSpace(estado))] {
+		return fmt.Errorf("invalid estado: %q is not a Brazilian UF", estado)
+	}
+	return nil
+}
+
+// EmailDomainViolation reports why the domain of email is not a valid hostname (rule email_domain)
+func EmailDomainViolation(email string) error {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return fmt.Errorf("invalid email domain: missing @")
+	}
+	domain := email[at+1:]
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("invalid email domain: %q has no top-level domain", domain)
+	}
+	for _, label := range labels {
+		if !domainLabelRegex.MatchString(label) {
+			return fmt.Errorf("invalid email domain: %q has an invalid label %q", domain, label)
+		}
+	}
+	return nil
+}
+
+// phoneDDDViolation reports why ddd is not an Anatel area code (rule phone_ddd)
+func phoneDDDViolation(ddd string) error {
+	if !validBrazilianDDDs[ddd] {
+		return fmt.Errorf("invalid phone number: unknown DDD %q", ddd)
+	}
+	return nil
+}
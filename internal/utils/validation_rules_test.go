@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-rmi/internal/config"
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+// withValidationRuleMode sets the mode of rule for the duration of the test
+func withValidationRuleMode(t *testing.T, rule, mode string) {
+	t.Helper()
+	original := config.AppConfig
+	cfg := &config.Config{}
+	if original != nil {
+		*cfg = *original
+	}
+	cfg.ValidationRules = map[string]string{rule: mode}
+	config.AppConfig = cfg
+	t.Cleanup(func() { config.AppConfig = original })
+}
+
+func TestAddressUFViolation(t *testing.T) {
+	for _, estado := range []string{"RJ", "sp", " MG "} {
+		if err := AddressUFViolation(estado); err != nil {
+			t.Errorf("AddressUFViolation(%q) = %v, want nil", estado, err)
+		}
+	}
+	for _, estado := range []string{"", "XX", "Rio"} {
+		if err := AddressUFViolation(estado); err == nil {
+			t.Errorf("AddressUFViolation(%q) = nil, want error", estado)
+		}
+	}
+}
+
+func TestEmailDomainViolation(t *testing.T) {
+	for _, email := range []string{"maria@example.com", "joao@mail.rio.gov.br", "ana@my-domain.com.br"} {
+		if err := EmailDomainViolation(email); err != nil {
+			t.Errorf("EmailDomainViolation(%q) = %v, want nil", email, err)
+		}
+	}
+	for _, email := range []string{"maria@example..com", "maria@-example.com", "maria@example-.com", "maria@localhost", "maria"} {
+		if err := EmailDomainViolation(email); err == nil {
+			t.Errorf("EmailDomainViolation(%q) = nil, want error", email)
+		}
+	}
+}
+
+func TestCheckValidationRule_Modes(t *testing.T) {
+	violation := AddressUFViolation("XX")
+
+	tests := []struct {
+		mode      string
+		wantError bool
+	}{
+		{config.ValidationRuleModeOff, false},
+		{config.ValidationRuleModeShadow, false},
+		{config.ValidationRuleModeEnforce, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			withValidationRuleMode(t, config.ValidationRuleAddressUF, tt.mode)
+
+			err := CheckValidationRule(config.ValidationRuleAddressUF, violation)
+			if (err != nil) != tt.wantError {
+				t.Errorf("CheckValidationRule() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err := CheckValidationRule(config.ValidationRuleAddressUF, nil); err != nil {
+				t.Errorf("CheckValidationRule() without violation = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateEmail_DomainRule(t *testing.T) {
+	input := models.SelfDeclaredEmailInput{Valor: "maria@-example.com"}
+
+	withValidationRuleMode(t, config.ValidationRuleEmailDomain, config.ValidationRuleModeShadow)
+	if result := ValidateEmail(input); !result.IsValid {
+		t.Errorf("ValidateEmail() in shadow mode IsValid = false, errors = %v", result.Errors)
+	}
+
+	withValidationRuleMode(t, config.ValidationRuleEmailDomain, config.ValidationRuleModeEnforce)
+	if result := ValidateEmail(input); result.IsValid {
+		t.Error("ValidateEmail() in enforce mode IsValid = true, want false")
+	}
+}
+
+func TestNormalizePhone_DDDRule(t *testing.T) {
+	withValidationRuleMode(t, config.ValidationRulePhoneDDD, config.ValidationRuleModeEnforce)
+	if _, err := NormalizePhone("55", "20", "987654321"); err == nil {
+		t.Error("NormalizePhone() with unknown DDD in enforce mode = nil error, want error")
+	}
+
+	withValidationRuleMode(t, config.ValidationRulePhoneDDD, config.ValidationRuleModeShadow)
+	phone, err := NormalizePhone("55", "20", "987654321")
+	if err != nil {
+		t.Fatalf("NormalizePhone() with unknown DDD in shadow mode error = %v, want nil", err)
+	}
+	if phone.DDD != "20" {
+		t.Errorf("NormalizePhone() DDD = %q, want %q", phone.DDD, "20")
+	}
+}
@@ -2,19 +2,68 @@ package utils
 
 import (
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"strings"
 
 	"github.com/prefeitura-rio/app-rmi/internal/config"
 )
 
-// GenerateVerificationCode generates a random 6-digit verification code
+// Verification code character sets
+const (
+	numericCodeCharset = "0123456789"
+	// alphanumericCodeCharset leaves out characters easily mistaken for one another when typed
+	// from a message (0/O, 1/I/L)
+	alphanumericCodeCharset = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+)
+
+// GenerateVerificationCode generates a random phone verification code with the configured
+// PHONE_VERIFICATION_CODE_LENGTH and PHONE_VERIFICATION_CODE_ALPHABET (6 digits by default)
 func GenerateVerificationCode() string {
-	code := ""
-	for range 6 {
-		code += fmt.Sprintf("%d", rand.Intn(10))
+	length, alphabet := 6, config.VerificationCodeAlphabetNumeric
+	if config.AppConfig != nil {
+		if config.AppConfig.PhoneVerificationCodeLength > 0 {
+			length = config.AppConfig.PhoneVerificationCodeLength
+		}
+		if config.AppConfig.PhoneVerificationCodeAlphabet != "" {
+			alphabet = config.AppConfig.PhoneVerificationCodeAlphabet
+		}
+	}
+	return GenerateVerificationCodeWith(length, alphabet)
+}
+
+// GenerateVerificationCodeWith generates a random verification code of the given length and
+// alphabet (config.VerificationCodeAlphabetNumeric or config.VerificationCodeAlphabetAlphanumeric)
+func GenerateVerificationCodeWith(length int, alphabet string) string {
+	charset := numericCodeCharset
+	if alphabet == config.VerificationCodeAlphabetAlphanumeric {
+		charset = alphanumericCodeCharset
+	}
+
+	code := make([]byte, length)
+	for i := range code {
+		code[i] = charset[rand.Intn(len(charset))]
+	}
+	return string(code)
+}
+
+// NormalizeVerificationCode puts a code typed by the user in the stored format: surrounding
+// spaces are dropped and letters uppercased, so alphanumeric codes match regardless of case.
+// Digit-only codes are left unchanged, so codes generated under any configuration still match.
+func NormalizeVerificationCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// GenerateVerificationToken generates a random 32-character hex token for email verification.
+// Unlike phone codes it is sent as a link, so it uses crypto/rand and can be long.
+func GenerateVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
 	}
-	return code
+	return hex.EncodeToString(b), nil
 }
 
 // SendVerificationCode sends a verification code to a single phone number
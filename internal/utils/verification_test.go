@@ -3,8 +3,10 @@ package utils
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/prefeitura-rio/app-rmi/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -92,3 +94,57 @@ func TestGenerateVerificationCode_NoLeadingZeros(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateVerificationCode_Configured(t *testing.T) {
+	original := config.AppConfig
+	defer func() { config.AppConfig = original }()
+
+	tests := []struct {
+		name     string
+		length   int
+		alphabet string
+		charset  string
+	}{
+		{name: "numeric-6", length: 6, alphabet: config.VerificationCodeAlphabetNumeric, charset: numericCodeCharset},
+		{name: "numeric-4", length: 4, alphabet: config.VerificationCodeAlphabetNumeric, charset: numericCodeCharset},
+		{name: "alphanumeric-8", length: 8, alphabet: config.VerificationCodeAlphabetAlphanumeric, charset: alphanumericCodeCharset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.Config{
+				PhoneVerificationCodeLength:   tt.length,
+				PhoneVerificationCodeAlphabet: tt.alphabet,
+			}
+
+			for i := 0; i < 20; i++ {
+				code := GenerateVerificationCode()
+				assert.Len(t, code, tt.length)
+				for _, c := range code {
+					assert.True(t, strings.ContainsRune(tt.charset, c), "Character %c should be in %q", c, tt.charset)
+				}
+				// The stored code is already in the normalized format the validation compares with
+				assert.Equal(t, code, NormalizeVerificationCode(code))
+			}
+		})
+	}
+}
+
+func TestNormalizeVerificationCode(t *testing.T) {
+	assert.Equal(t, "012345", NormalizeVerificationCode(" 012345 "))
+	assert.Equal(t, "AB23CD45", NormalizeVerificationCode("ab23Cd45"))
+}
+
+func TestGenerateVerificationToken(t *testing.T) {
+	token, err := GenerateVerificationToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 32, "Token should be 32 hex characters")
+	for i, c := range token {
+		assert.True(t, (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f'),
+			"Character at position %d (%c) should be hex", i, c)
+	}
+
+	other, err := GenerateVerificationToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other, "Tokens should be unique")
+}
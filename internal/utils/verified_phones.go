@@ -0,0 +1,56 @@
+package utils
+
+import "github.com/prefeitura-rio/app-rmi/internal/models"
+
+// defaultPhoneDDI is assumed for phones stored without a country code, such as base phones
+const defaultPhoneDDI = "55"
+
+// StoredPhoneNumber returns a stored phone in storage format (the phone_number of phone
+// mappings), assuming DDI 55 when it is missing, or an empty string when it isn't a valid phone
+func StoredPhoneNumber(ddi, ddd, valor *string) string {
+	if valor == nil {
+		return ""
+	}
+	countryCode, areaCode := defaultPhoneDDI, ""
+	if ddi != nil && *ddi != "" {
+		countryCode = *ddi
+	}
+	if ddd != nil {
+		areaCode = *ddd
+	}
+
+	normalized, err := NormalizePhone(countryCode, areaCode, *valor)
+	if err != nil {
+		return ""
+	}
+	return FormatPhoneForStorage(normalized.DDI, normalized.DDD, normalized.Valor)
+}
+
+// VerifiedPhones returns a copy of the verified phones of a self-declared telefone. Phones
+// verified before telefone.verificados existed are only stored as a verified principal, which is
+// returned as the primary verified phone.
+func VerifiedPhones(telefone *models.Telefone) []models.TelefoneVerificado {
+	if telefone == nil {
+		return nil
+	}
+	if len(telefone.Verificados) > 0 {
+		return append([]models.TelefoneVerificado(nil), telefone.Verificados...)
+	}
+
+	if telefone.Indicador == nil || !*telefone.Indicador || telefone.Principal == nil {
+		return nil
+	}
+	principal := telefone.Principal
+	id := StoredPhoneNumber(principal.DDI, principal.DDD, principal.Valor)
+	if id == "" {
+		return nil
+	}
+	return []models.TelefoneVerificado{{
+		ID:         id,
+		DDI:        principal.DDI,
+		DDD:        principal.DDD,
+		Valor:      principal.Valor,
+		Principal:  true,
+		VerifiedAt: principal.UpdatedAt,
+	}}
+}
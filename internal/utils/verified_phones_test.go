@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-rmi/internal/models"
+)
+
+func TestStoredPhoneNumber(t *testing.T) {
+	ddi, ddd, valor := "55", "21", "987654321"
+	empty := ""
+	tests := []struct {
+		name            string
+		ddi, ddd, valor *string
+		want            string
+	}{
+		{"full phone", &ddi, &ddd, &valor, "5521987654321"},
+		{"missing DDI defaults to 55", nil, &ddd, &valor, "5521987654321"},
+		{"empty DDI defaults to 55", &empty, &ddd, &valor, "5521987654321"},
+		{"missing number", &ddi, &ddd, nil, ""},
+		{"invalid number", &ddi, &ddd, &empty, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StoredPhoneNumber(tt.ddi, tt.ddd, tt.valor); got != tt.want {
+				t.Errorf("StoredPhoneNumber() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifiedPhones(t *testing.T) {
+	ddi, ddd, valor := "55", "21", "987654321"
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	principal := &models.TelefonePrincipal{DDI: &ddi, DDD: &ddd, Valor: &valor, UpdatedAt: &verifiedAt}
+
+	if got := VerifiedPhones(nil); got != nil {
+		t.Errorf("VerifiedPhones(nil) = %v, want nil", got)
+	}
+	if got := VerifiedPhones(&models.Telefone{Indicador: BoolPtr(false), Principal: principal}); got != nil {
+		t.Errorf("VerifiedPhones() of an unverified phone = %v, want nil", got)
+	}
+
+	// A phone verified before the list existed is the primary verified phone
+	legacy := VerifiedPhones(&models.Telefone{Indicador: BoolPtr(true), Principal: principal})
+	if len(legacy) != 1 || legacy[0].ID != "5521987654321" || !legacy[0].Principal || legacy[0].VerifiedAt != &verifiedAt {
+		t.Errorf("VerifiedPhones() of a legacy verified phone = %+v, want it as the primary", legacy)
+	}
+
+	telefone := &models.Telefone{
+		Indicador:   BoolPtr(true),
+		Principal:   principal,
+		Verificados: []models.TelefoneVerificado{{ID: "5521987654321", Principal: true}, {ID: "5521912345678"}},
+	}
+	phones := VerifiedPhones(telefone)
+	if len(phones) != 2 {
+		t.Fatalf("VerifiedPhones() = %+v, want the 2 listed phones", phones)
+	}
+	phones[1].Principal = true
+	if telefone.Verificados[1].Principal {
+		t.Error("VerifiedPhones() must return a copy of the list")
+	}
+}
@@ -89,9 +89,7 @@ func getAuthToken(ctx context.Context) (string, error) {
 	req.Header.Set("accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use HTTP client pool for optimal performance
-	client := httpclient.GetGlobalPool().Get()
-	defer httpclient.GetGlobalPool().Put(client)
+	client := httpclient.Client(httpclient.ClientWhatsApp)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -197,9 +195,7 @@ func SendWhatsAppMessage(ctx context.Context, phones []string, hsmID string, var
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use HTTP client pool for optimal performance
-	client := httpclient.GetGlobalPool().Get()
-	defer httpclient.GetGlobalPool().Put(client)
+	client := httpclient.Client(httpclient.ClientWhatsApp)
 
 	resp, err := client.Do(req)
 	if err != nil {
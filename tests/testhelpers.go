@@ -72,6 +72,7 @@ func SetupTestContainers(t *testing.T) *TestContainers {
 	config.AppConfig.CitizenCollection = "citizens"
 	config.AppConfig.SelfDeclaredCollection = "self_declared"
 	config.AppConfig.PhoneVerificationCollection = "phone_verifications"
+	config.AppConfig.EmailVerificationCollection = "email_verifications"
 	config.AppConfig.UserConfigCollection = "user_config"
 	config.AppConfig.MaintenanceRequestCollection = "maintenance_requests"
 	config.AppConfig.PhoneMappingCollection = "phone_cpf_mappings"